@@ -0,0 +1,137 @@
+package builtins
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// base64Wrap is the default line length of base64's output.
+const base64Wrap = 76
+
+func init() {
+	Register("base64", func(ctx *Context, args ...string) error {
+		return Base64(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "base64 [-d] [-w COLS] [FILE]",
+		Summary:  "encode or decode base64",
+		Flags: []string{
+			"-d\tdecode instead of encoding; whitespace is ignored",
+			"-w COLS\twrap encoded lines after COLS characters (default 76, 0 for none)",
+		},
+	})
+}
+
+// Base64 handles the "base64" built-in command.
+// It encodes a file, or r when no file (or "-") is given, as base64 in lines
+// of -w COLS characters, or decodes it with -d.
+func Base64(r io.Reader, w io.Writer, args ...string) error {
+	var (
+		decode bool
+		wrap   = base64Wrap
+		files  []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-d":
+			decode = true
+		case arg == "-w":
+			if i+1 == len(args) {
+				return fmt.Errorf("%w: -w requires an argument", ErrInvalidArgCount)
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("%w: invalid wrap size %q", ErrInvalidArgs, args[i])
+			}
+			wrap = n
+		case len(arg) > 1 && arg[0] == '-':
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		default:
+			files = append(files, arg)
+		}
+	}
+	if len(files) > 1 {
+		return fmt.Errorf("%w: expected at most one file", ErrInvalidArgCount)
+	}
+	name := "-"
+	if len(files) == 1 {
+		name = files[0]
+	}
+
+	return withInput(r, name, func(in io.Reader) error {
+		if decode {
+			_, err := io.Copy(w, base64.NewDecoder(base64.StdEncoding, &spaceSkipper{r: in}))
+			return err
+		}
+		return encodeBase64(w, in, wrap)
+	})
+}
+
+// encodeBase64 writes r to w as base64 in lines of wrap characters.
+func encodeBase64(w io.Writer, r io.Reader, wrap int) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWrapper{w: bw, width: wrap}
+	enc := base64.NewEncoder(base64.StdEncoding, lw)
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if lw.col > 0 {
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// lineWrapper breaks what is written to w into lines of width bytes, or
+// doesn't if width is 0.
+type lineWrapper struct {
+	w     *bufio.Writer
+	width int
+	col   int
+}
+
+func (lw *lineWrapper) Write(p []byte) (int, error) {
+	for _, c := range p {
+		if err := lw.w.WriteByte(c); err != nil {
+			return 0, err
+		}
+		if lw.col++; lw.col == lw.width {
+			if err := lw.w.WriteByte('\n'); err != nil {
+				return 0, err
+			}
+			lw.col = 0
+		}
+	}
+
+	return len(p), nil
+}
+
+// spaceSkipper reads r without its whitespace, so wrapped base64 decodes.
+type spaceSkipper struct {
+	r io.Reader
+}
+
+func (s *spaceSkipper) Read(p []byte) (int, error) {
+	for {
+		n, err := s.r.Read(p)
+		kept := 0
+		for _, c := range p[:n] {
+			if !strings.ContainsRune(" \t\r\n", rune(c)) {
+				p[kept] = c
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package builtins_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestBase64(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "encode", stdin: "hello\n", want: "aGVsbG8K\n"},
+		{name: "empty", stdin: "", want: ""},
+		{name: "wrap", stdin: "hello\n", args: []string{"-w", "4"}, want: "aGVs\nbG8K\n"},
+		{name: "no wrap", stdin: strings.Repeat("a", 60), args: []string{"-w", "0"}, want: strings.Repeat("YWFh", 20) + "\n"},
+		{name: "default wrap", stdin: strings.Repeat("a", 60), want: strings.Repeat("YWFh", 19) + "\nYWFh\n"},
+		{name: "decode", stdin: "aGVs\nbG8K\n", args: []string{"-d"}, want: "hello\n"},
+		{name: "bad input", stdin: "!!!!", args: []string{"-d"}, wantErr: true},
+		{name: "bad wrap", args: []string{"-w", "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := builtins.Base64(strings.NewReader(tt.stdin), &out, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Base64() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("Base64() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("cat", func(ctx *Context, args ...string) error {
+		return Concatenate(ctx.FileSystem(), ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "cat [-n] [FILE...]",
+		Summary:  "print files one after another",
+		Flags:    []string{"-n\tnumber the lines"},
+	})
+}
+
+// Concatenate handles the "cat" built-in command.
+// It copies each file to w in turn, or r when no file (or "-") is given,
+// numbering the lines across all of them with -n.
+func Concatenate(fsys FileSystem, r io.Reader, w io.Writer, args ...string) error {
+	var (
+		number bool
+		files  = make([]string, 0)
+	)
+	for _, arg := range args {
+		switch {
+		case arg == "-n":
+			number = true
+		case len(arg) > 1 && arg[0] == '-':
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		default:
+			files = append(files, arg)
+		}
+	}
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+
+	line := 0
+	for _, name := range files {
+		err := withFile(fsys, r, name, func(in io.Reader) error {
+			if !number {
+				_, err := io.Copy(w, in)
+				return err
+			}
+			return numberLines(in, w, &line)
+		})
+		if err != nil {
+			return fileError("cat", name, err)
+		}
+	}
+
+	return nil
+}
+
+// numberLines copies r to w with each line prefixed by its number, counting
+// on from *line.
+func numberLines(r io.Reader, w io.Writer, line *int) error {
+	br := bufio.NewReader(r)
+	for {
+		text, err := br.ReadString('\n')
+		if text != "" {
+			*line++
+			if _, wErr := fmt.Fprintf(w, "%6d\t%v", *line, text); wErr != nil {
+				return wErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestConcatenate(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	a := filepath.Join(tmp, "a.txt")
+	b := filepath.Join(tmp, "b.txt")
+	if err := os.WriteFile(a, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("three"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{name: "files in order", args: []string{a, b}, wantOut: "one\ntwo\nthree"},
+		{name: "stdin without files", stdin: "in\n", wantOut: "in\n"},
+		{name: "stdin as -", stdin: "in\n", args: []string{b, "-"}, wantOut: "threein\n"},
+		{name: "numbered across files", args: []string{"-n", a, b}, wantOut: "     1\tone\n     2\ttwo\n     3\tthree"},
+		{name: "missing file", args: []string{filepath.Join(tmp, "missing")}, wantErr: os.ErrNotExist},
+		{name: "unknown flag", args: []string{"-x"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := builtins.Concatenate(builtins.OSFileSystem, strings.NewReader(tt.stdin), &out, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Concatenate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && out.String() != tt.wantOut {
+				t.Fatalf("Concatenate() = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
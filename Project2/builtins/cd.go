@@ -4,23 +4,90 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 var (
 	ErrInvalidArgCount = errors.New("invalid argument count")
+	ErrInvalidArgs     = errors.New("invalid arguments")
 	HomeDir, _         = os.UserHomeDir()
 )
 
+func init() {
+	Register("cd", func(ctx *Context, args ...string) error {
+		return ChangeDirectory(args...)
+	}, Meta{
+		Synopsis: "cd [-L | -P] [DIR]",
+		Summary:  "change the working directory (default: home)",
+		Flags: []string{
+			"-L\tfollow symbolic links as typed, so .. goes back along them (default)",
+			"-P\tresolve symbolic links in the new directory",
+		},
+	})
+	RegisterCompleter("cd", CompleteDirs)
+}
+
+// ChangeDirectory handles the "cd" built-in command.
+// It changes to the directory given, or to the home directory. $PWD keeps
+// the logical path: symbolic links stay as typed and .. removes the previous
+// element, unless -P is given; $OLDPWD is set to the previous directory.
 func ChangeDirectory(args ...string) error {
+	physical := false
+	for len(args) > 0 && (args[0] == "-L" || args[0] == "-P") {
+		physical = args[0] == "-P"
+		args = args[1:]
+	}
+
 	switch len(args) {
 	case 0: // change to home directory if available
 		if HomeDir == "" {
 			return fmt.Errorf("%w: no homedir found, expected one argument (directory)", ErrInvalidArgCount)
 		}
-		return os.Chdir(HomeDir)
+		return chdir(HomeDir, physical)
 	case 1:
-		return os.Chdir(args[0])
+		return chdir(args[0], physical)
 	default:
 		return fmt.Errorf("%w: expected zero or one arguments (directory)", ErrInvalidArgCount)
 	}
 }
+
+// chdir changes the working directory and updates $PWD and $OLDPWD. The
+// logical path is dir joined to $PWD and cleaned, so .. undoes a symbolic
+// link; physical follows dir as the system does and resolves every link. If
+// the logical path doesn't lead anywhere, e.g. because .. of a symlinked
+// directory doesn't exist, the physical one is used.
+func chdir(dir string, physical bool) error {
+	old, err := os.Getwd()
+	if err != nil {
+		// The working directory is gone, so only an absolute dir makes sense.
+		old = string(filepath.Separator)
+	}
+	target := filepath.Clean(dir)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(old, target)
+	}
+
+	if physical || os.Chdir(target) != nil {
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+		if target, err = physicalWd(); err != nil {
+			return err
+		}
+	}
+	if err := os.Setenv("OLDPWD", old); err != nil {
+		return err
+	}
+
+	return os.Setenv("PWD", target)
+}
+
+// physicalWd returns the working directory with every symbolic link resolved.
+func physicalWd() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(wd)
+}
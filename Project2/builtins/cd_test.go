@@ -2,12 +2,21 @@ package builtins_test
 
 import (
 	"errors"
-	"github.com/jh125486/CSCE4600/Project2/builtins"
+	"github.com/jar0582/CSCE4600/Project2/builtins"
 	"os"
 	"testing"
 )
 
 func TestChangeDirectory(t *testing.T) {
+	// Not parallel: changes the working directory and $PWD.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PWD", wd)
+	t.Setenv("OLDPWD", "")
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
 	tmp := t.TempDir()
 
 	type args struct {
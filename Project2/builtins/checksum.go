@@ -0,0 +1,146 @@
+package builtins
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned by md5sum -c and sha256sum -c when a file
+// doesn't match its listed checksum or can't be read.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+func init() {
+	Register("md5sum", func(ctx *Context, args ...string) error {
+		return Md5sum(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "md5sum [-c] [FILE...]",
+		Summary:  "print or check MD5 checksums",
+		Flags: []string{
+			"-c\tread checksums from the files and check them",
+		},
+	})
+	Register("sha256sum", func(ctx *Context, args ...string) error {
+		return Sha256sum(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "sha256sum [-c] [FILE...]",
+		Summary:  "print or check SHA-256 checksums",
+		Flags: []string{
+			"-c\tread checksums from the files and check them",
+		},
+	})
+}
+
+// Md5sum handles the "md5sum" built-in command.
+// It prints the MD5 checksum of each file as "HASH  NAME", or of r when no
+// file (or "-") is given. With -c the files are instead lists in that format,
+// and each listed file is checked and reported as OK or FAILED.
+func Md5sum(r io.Reader, w io.Writer, args ...string) error {
+	return checksum(r, w, md5.New, args...)
+}
+
+// Sha256sum handles the "sha256sum" built-in command.
+// It prints the SHA-256 checksum of each file as "HASH  NAME", or of r when
+// no file (or "-") is given. With -c the files are instead lists in that
+// format, and each listed file is checked and reported as OK or FAILED.
+func Sha256sum(r io.Reader, w io.Writer, args ...string) error {
+	return checksum(r, w, sha256.New, args...)
+}
+
+// checksum prints "HASH  NAME" for each file, or for r when no file (or "-")
+// is given. With -c the files are instead lists in that format, and each
+// listed file is hashed and reported as OK or FAILED.
+func checksum(r io.Reader, w io.Writer, newHash func() hash.Hash, args ...string) error {
+	check := false
+	files := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case arg == "-c":
+			check = true
+		case len(arg) > 1 && arg[0] == '-':
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		default:
+			files = append(files, arg)
+		}
+	}
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+
+	for _, name := range files {
+		var err error
+		if check {
+			err = withInput(r, name, func(in io.Reader) error {
+				return checkSums(r, w, newHash, in)
+			})
+		} else {
+			var sum string
+			if sum, err = hashFile(r, name, newHash); err == nil {
+				_, err = fmt.Fprintf(w, "%v  %v\n", sum, name)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashFile returns the hex checksum of the named file, or of r for "-".
+func hashFile(r io.Reader, name string, newHash func() hash.Hash) (string, error) {
+	h := newHash()
+	if err := withInput(r, name, func(in io.Reader) error {
+		_, err := io.Copy(h, in)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkSums checks each "HASH  NAME" line of list, where a * before the name
+// marks binary mode and makes no difference.
+func checkSums(r io.Reader, w io.Writer, newHash func() hash.Hash, list io.Reader) error {
+	var checked, failed int
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		want, name, ok := strings.Cut(line, " ")
+		if !ok || len(name) < 2 || (name[0] != ' ' && name[0] != '*') {
+			return fmt.Errorf("%w: improperly formatted checksum line %q", ErrInvalidArgs, line)
+		}
+		name = name[1:]
+
+		checked++
+		status := "OK"
+		if got, err := hashFile(r, name, newHash); err != nil {
+			status = "FAILED open or read"
+			failed++
+		} else if !strings.EqualFold(got, want) {
+			status = "FAILED"
+			failed++
+		}
+		if _, err := fmt.Fprintf(w, "%v: %v\n", name, status); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d computed checksums did NOT match", ErrChecksumMismatch, failed, checked)
+	}
+
+	return nil
+}
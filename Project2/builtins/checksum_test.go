@@ -0,0 +1,80 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	a := filepath.Join(tmp, "a.txt")
+	if err := os.WriteFile(a, []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		md5Hello    = "b1946ac92492d2347c6235b4d2611184"
+		sha256Hello = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	)
+	good := filepath.Join(tmp, "good.md5")
+	bad := filepath.Join(tmp, "bad.md5")
+	if err := os.WriteFile(good, []byte(md5Hello+"  "+a+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tmp, "nope")
+	if err := os.WriteFile(bad, []byte(md5Hello+" *"+a+"\n"+md5Hello+"  "+good+"\n"+md5Hello+"  "+missing+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fn      func(r io.Reader, w io.Writer, args ...string) error
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{name: "md5 stdin", fn: builtins.Md5sum, stdin: "hello\n", wantOut: md5Hello + "  -\n"},
+		{name: "md5 file", fn: builtins.Md5sum, args: []string{a}, wantOut: md5Hello + "  " + a + "\n"},
+		{name: "sha256 file", fn: builtins.Sha256sum, args: []string{a}, wantOut: sha256Hello + "  " + a + "\n"},
+		{name: "check ok", fn: builtins.Md5sum, args: []string{"-c", good}, wantOut: a + ": OK\n"},
+		{
+			name:    "check from stdin",
+			fn:      builtins.Md5sum,
+			stdin:   md5Hello + "  " + a + "\n",
+			args:    []string{"-c"},
+			wantOut: a + ": OK\n",
+		},
+		{
+			name:    "check failures",
+			fn:      builtins.Md5sum,
+			args:    []string{"-c", bad},
+			wantOut: a + ": OK\n" + good + ": FAILED\n" + missing + ": FAILED open or read\n",
+			wantErr: builtins.ErrChecksumMismatch,
+		},
+		{name: "check wrong algorithm", fn: builtins.Sha256sum, args: []string{"-c", good}, wantOut: a + ": FAILED\n", wantErr: builtins.ErrChecksumMismatch},
+		{name: "bad list", fn: builtins.Md5sum, stdin: "nonsense\n", args: []string{"-c"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "missing file", fn: builtins.Md5sum, args: []string{missing}, wantErr: os.ErrNotExist},
+		{name: "unknown flag", fn: builtins.Md5sum, args: []string{"-x"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := tt.fn(strings.NewReader(tt.stdin), &out, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checksum error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("checksum got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
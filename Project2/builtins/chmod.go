@@ -0,0 +1,160 @@
+package builtins
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Unix permission bits beyond rwxrwxrwx.
+const (
+	modeSetuid = 0o4000
+	modeSetgid = 0o2000
+	modeSticky = 0o1000
+)
+
+func init() {
+	Register("chmod", func(ctx *Context, args ...string) error {
+		return ChangeMode(args...)
+	}, Meta{
+		Synopsis: "chmod MODE FILE...",
+		Summary:  "change file modes (octal or symbolic, e.g. u+x)",
+	})
+}
+
+// ChangeMode handles the "chmod" built-in command.
+// MODE is either octal (755) or a comma separated list of symbolic
+// clauses such as u+rwx, go-w or a=r.
+func ChangeMode(args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: expected a mode and at least one file", ErrInvalidArgCount)
+	}
+
+	spec := args[0]
+	for _, file := range args[1:] {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fileError("chmod", file, err)
+		}
+		mode, err := applyMode(spec, unixMode(info.Mode()), info.IsDir())
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(file, fileMode(mode)); err != nil {
+			return fileError("chmod", file, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMode applies an octal or symbolic mode spec to the Unix mode bits current.
+func applyMode(spec string, current uint32, isDir bool) (uint32, error) {
+	if spec == "" {
+		return 0, fmt.Errorf("%w: empty mode", ErrInvalidArgs)
+	}
+	if spec[0] >= '0' && spec[0] <= '7' {
+		n, err := strconv.ParseUint(spec, 8, 32)
+		if err != nil || n > 0o7777 {
+			return 0, fmt.Errorf("%w: invalid mode %q", ErrInvalidArgs, spec)
+		}
+		return uint32(n), nil
+	}
+
+	mode := current
+	for _, clause := range strings.Split(spec, ",") {
+		var who uint32
+		i := 0
+		for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+			switch clause[i] {
+			case 'u':
+				who |= 0o4700
+			case 'g':
+				who |= 0o2070
+			case 'o':
+				who |= 0o1007
+			case 'a':
+				who |= 0o7777
+			}
+		}
+		if who == 0 {
+			who = 0o7777
+		}
+		if i == len(clause) {
+			return 0, fmt.Errorf("%w: invalid mode %q", ErrInvalidArgs, spec)
+		}
+
+		// Each operator applies to the permissions that follow it, e.g. u+x-w.
+		for i < len(clause) {
+			op := clause[i]
+			if op != '+' && op != '-' && op != '=' {
+				return 0, fmt.Errorf("%w: invalid mode %q", ErrInvalidArgs, spec)
+			}
+			i++
+			var perm uint32
+			for ; i < len(clause) && strings.IndexByte("rwxXst", clause[i]) >= 0; i++ {
+				switch clause[i] {
+				case 'r':
+					perm |= 0o444
+				case 'w':
+					perm |= 0o222
+				case 'x':
+					perm |= 0o111
+				case 'X':
+					if isDir || mode&0o111 != 0 {
+						perm |= 0o111
+					}
+				case 's':
+					perm |= modeSetuid | modeSetgid
+				case 't':
+					perm |= modeSticky
+				}
+			}
+			perm &= who
+			switch op {
+			case '+':
+				mode |= perm
+			case '-':
+				mode &^= perm
+			case '=':
+				mode = mode&^(who&0o777) | perm
+			}
+		}
+	}
+
+	return mode, nil
+}
+
+// unixMode converts an fs.FileMode into Unix permission bits.
+func unixMode(m fs.FileMode) uint32 {
+	mode := uint32(m.Perm())
+	if m&fs.ModeSetuid != 0 {
+		mode |= modeSetuid
+	}
+	if m&fs.ModeSetgid != 0 {
+		mode |= modeSetgid
+	}
+	if m&fs.ModeSticky != 0 {
+		mode |= modeSticky
+	}
+
+	return mode
+}
+
+// fileMode converts Unix permission bits into an fs.FileMode.
+func fileMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0o777)
+	if mode&modeSetuid != 0 {
+		m |= fs.ModeSetuid
+	}
+	if mode&modeSetgid != 0 {
+		m |= fs.ModeSetgid
+	}
+	if mode&modeSticky != 0 {
+		m |= fs.ModeSticky
+	}
+
+	return m
+}
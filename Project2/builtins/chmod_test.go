@@ -0,0 +1,70 @@
+package builtins
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_applyMode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		spec    string
+		current uint32
+		isDir   bool
+		want    uint32
+		wantErr error
+	}{
+		{name: "octal", spec: "755", current: 0o644, want: 0o755},
+		{name: "octal with setuid", spec: "4711", want: 0o4711},
+		{name: "add for user", spec: "u+x", current: 0o644, want: 0o744},
+		{name: "remove for group and other", spec: "go-r", current: 0o644, want: 0o600},
+		{name: "assign for all", spec: "a=r", current: 0o755, want: 0o444},
+		{name: "no who means all", spec: "+x", current: 0o644, want: 0o755},
+		{name: "several clauses", spec: "u=rwx,g=rx,o=", current: 0o777, want: 0o750},
+		{name: "chained operators", spec: "u+x-w", current: 0o644, want: 0o544},
+		{name: "conditional execute on file", spec: "a+X", current: 0o644, want: 0o644},
+		{name: "conditional execute on directory", spec: "a+X", current: 0o644, isDir: true, want: 0o755},
+		{name: "setgid and sticky", spec: "g+s,+t", current: 0o755, want: 0o3755},
+		{name: "bad octal", spec: "789", wantErr: ErrInvalidArgs},
+		{name: "missing operator", spec: "u", wantErr: ErrInvalidArgs},
+		{name: "bad operator", spec: "u*x", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := applyMode(tt.spec, tt.current, tt.isDir)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applyMode() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("applyMode() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("applyMode() = %04o, want %04o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeMode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChangeMode("u+x,go-r", file); err != nil {
+		t.Fatalf("ChangeMode() unexpected error: %v", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o700 {
+		t.Errorf("ChangeMode() mode = %v, want %v", got, os.FileMode(0o700))
+	}
+}
@@ -0,0 +1,41 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+)
+
+// clearScrollback also clears the lines scrolled off the top of the terminal.
+const clearScrollback = "\033[3J"
+
+func init() {
+	Register("clear", func(ctx *Context, args ...string) error {
+		return Clear(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "clear [-x]",
+		Summary:  "clear the terminal screen",
+		Flags: []string{
+			"-x\tkeep the scrollback",
+		},
+	})
+}
+
+// Clear handles the "clear" built-in command.
+// It clears the screen and the scrollback, or with -x only the screen.
+func Clear(w io.Writer, args ...string) error {
+	keepScrollback := false
+	for _, arg := range args {
+		if arg != "-x" {
+			return fmt.Errorf("%w: clear: unexpected argument %v", ErrInvalidArgs, arg)
+		}
+		keepScrollback = true
+	}
+
+	seq := clearScreen + clearScrollback
+	if keepScrollback {
+		seq = clearScreen
+	}
+	_, err := io.WriteString(w, seq)
+
+	return err
+}
@@ -0,0 +1,51 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{name: "screen and scrollback", want: "\033[H\033[2J\033[3J"},
+		{name: "keep scrollback", args: []string{"-x"}, want: "\033[H\033[2J"},
+		{name: "unknown flag", args: []string{"-q"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := builtins.Clear(&w, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Clear() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("Clear() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalSize(t *testing.T) {
+	// Not parallel: sets $COLUMNS and $LINES.
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+	if cols, rows := builtins.TerminalSize(&bytes.Buffer{}); cols != 80 || rows != 24 {
+		t.Errorf("TerminalSize() got = %dx%d, want 80x24", cols, rows)
+	}
+
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "x")
+	if cols, rows := builtins.TerminalSize(&bytes.Buffer{}); cols != 132 || rows != 24 {
+		t.Errorf("TerminalSize() got = %dx%d, want 132x24", cols, rows)
+	}
+}
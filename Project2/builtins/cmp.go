@@ -0,0 +1,94 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("cmp", func(ctx *Context, args ...string) error {
+		return Cmp(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "cmp [-s] FILE1 [FILE2]",
+		Summary:  "compare two files byte by byte",
+		Flags: []string{
+			"-s\tprint nothing; only the exit status tells",
+		},
+	})
+}
+
+// Cmp handles the "cmp" built-in command.
+// It reports the first byte and line where two files differ, or that one is
+// a prefix of the other, and exits with status 1 if they differ. FILE2
+// defaults to r, as does a "-" for either file.
+func Cmp(r io.Reader, w io.Writer, args ...string) error {
+	silent := false
+	if len(args) > 0 && args[0] == "-s" {
+		silent, args = true, args[1:]
+	}
+	if len(args) == 1 {
+		args = append(args, "-")
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("%w: expected two files", ErrInvalidArgCount)
+	}
+
+	return withInput(r, args[0], func(in1 io.Reader) error {
+		return withInput(r, args[1], func(in2 io.Reader) error {
+			msg, err := compareBytes(bufio.NewReader(in1), bufio.NewReader(in2), args[0], args[1])
+			if err != nil || msg == "" {
+				return err
+			}
+			if !silent {
+				if _, err := fmt.Fprintln(w, msg); err != nil {
+					return err
+				}
+			}
+			return &ExitError{Status: StatusFailure}
+		})
+	})
+}
+
+// compareBytes reads a and b in step and describes where they first differ,
+// or returns "" if they are the same.
+func compareBytes(a, b *bufio.Reader, nameA, nameB string) (string, error) {
+	var (
+		newlines int
+		last     byte
+	)
+	for n := 1; ; n++ {
+		c1, err1 := a.ReadByte()
+		c2, err2 := b.ReadByte()
+		for _, err := range []error{err1, err2} {
+			if err != nil && err != io.EOF {
+				return "", err
+			}
+		}
+		shorter := nameA
+		switch {
+		case err1 == io.EOF && err2 == io.EOF:
+			return "", nil
+		case err1 != io.EOF && err2 != io.EOF:
+			if c1 != c2 {
+				return fmt.Sprintf("%v %v differ: byte %d, line %d", nameA, nameB, n, newlines+1), nil
+			}
+			if c1 == '\n' {
+				newlines++
+			}
+			last = c1
+			continue
+		case err2 == io.EOF:
+			shorter = nameB
+		}
+
+		switch {
+		case n == 1:
+			return fmt.Sprintf("cmp: EOF on %v which is empty", shorter), nil
+		case last == '\n':
+			return fmt.Sprintf("cmp: EOF on %v after byte %d, line %d", shorter, n-1, newlines), nil
+		default:
+			return fmt.Sprintf("cmp: EOF on %v after byte %d, in line %d", shorter, n-1, newlines+1), nil
+		}
+	}
+}
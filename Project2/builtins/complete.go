@@ -0,0 +1,150 @@
+package builtins
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Completer returns the completions of word, the argument being typed to a
+// command after args. Each completion replaces word whole. A completion
+// ending in "/" is a directory that can be completed further.
+type Completer func(args []string, word string) []string
+
+var (
+	completersMu sync.RWMutex
+	completers   = map[string]Completer{}
+)
+
+func init() {
+	RegisterCompleter("git", completeGit)
+}
+
+// RegisterCompleter sets how the arguments of the named command, a builtin
+// or a program, are completed when Tab is pressed. Without one, arguments
+// complete to file names. A nil c removes the completer.
+func RegisterCompleter(name string, c Completer) {
+	completersMu.Lock()
+	defer completersMu.Unlock()
+	if c == nil {
+		delete(completers, name)
+		return
+	}
+	completers[name] = c
+}
+
+// LookupCompleter returns the completer registered for the named command.
+func LookupCompleter(name string) (Completer, bool) {
+	completersMu.RLock()
+	defer completersMu.RUnlock()
+	c, ok := completers[name]
+
+	return c, ok
+}
+
+// CompleteFiles completes word to the names of files and directories.
+func CompleteFiles(_ []string, word string) []string {
+	return completePaths(word, false)
+}
+
+// CompleteDirs completes word to the names of directories only.
+func CompleteDirs(_ []string, word string) []string {
+	return completePaths(word, true)
+}
+
+// CompleteWords returns a completer offering words that start with what is
+// typed.
+func CompleteWords(words ...string) Completer {
+	return func(_ []string, word string) []string {
+		return matchPrefix(words, word)
+	}
+}
+
+// CompletePIDs completes word to the IDs of the user's processes.
+func CompletePIDs(_ []string, word string) []string {
+	procs, err := listProcesses()
+	if err != nil {
+		return nil
+	}
+	uid := os.Getuid()
+	ids := make([]string, 0, len(procs))
+	for _, p := range procs {
+		if p.UID == uid && p.PID != os.Getpid() {
+			ids = append(ids, strconv.Itoa(p.PID))
+		}
+	}
+
+	return matchPrefix(ids, word)
+}
+
+// completeGit completes git's subcommands, as git itself lists them, and
+// file names after them.
+func completeGit(args []string, word string) []string {
+	if len(args) > 0 {
+		return CompleteFiles(args, word)
+	}
+	out, err := exec.Command("git", "--list-cmds=main,others,alias,nohelpers").Output()
+	if err != nil {
+		return nil
+	}
+
+	return matchPrefix(strings.Fields(string(out)), word)
+}
+
+// completePaths lists the entries of word's directory that start with its
+// last element, marking directories with a trailing slash. Hidden entries are
+// only listed when the element starts with a dot.
+func completePaths(word string, dirsOnly bool) []string {
+	dir, prefix := filepath.Split(word)
+	search := dir
+	if search == "" {
+		search = "."
+	} else if strings.HasPrefix(search, "~/") && HomeDir != "" {
+		search = filepath.Join(HomeDir, search[2:])
+	}
+	entries, err := os.ReadDir(search)
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		isDir := e.IsDir()
+		if e.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(search, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		switch {
+		case isDir:
+			matches = append(matches, dir+name+"/")
+		case !dirsOnly:
+			matches = append(matches, dir+name)
+		}
+	}
+
+	return matches
+}
+
+// matchPrefix returns the distinct words starting with prefix, sorted.
+func matchPrefix(words []string, prefix string) []string {
+	seen := make(map[string]bool, len(words))
+	var matches []string
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) && !seen[w] {
+			seen[w] = true
+			matches = append(matches, w)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches
+}
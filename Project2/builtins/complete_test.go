@@ -0,0 +1,53 @@
+package builtins_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestCompleters(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, d := range []string{"src", "static", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "setup.sh"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src", filepath.Join(dir, "source")); err != nil {
+		t.Fatal(err)
+	}
+
+	cd, ok := builtins.LookupCompleter("cd")
+	if !ok {
+		t.Fatal("LookupCompleter(cd) found nothing")
+	}
+	tests := []struct {
+		name     string
+		complete builtins.Completer
+		word     string
+		want     []string
+	}{
+		{name: "files", complete: builtins.CompleteFiles, word: dir + "/s", want: []string{dir + "/setup.sh", dir + "/source/", dir + "/src/", dir + "/static/"}},
+		{name: "dirs", complete: builtins.CompleteDirs, word: dir + "/s", want: []string{dir + "/source/", dir + "/src/", dir + "/static/"}},
+		{name: "hidden", complete: builtins.CompleteFiles, word: dir + "/.", want: []string{dir + "/.hidden/"}},
+		{name: "cd", complete: cd, word: dir + "/st", want: []string{dir + "/static/"}},
+		{name: "words", complete: builtins.CompleteWords("start", "stop", "status", "stop"), word: "sto", want: []string{"stop"}},
+		{name: "no match", complete: builtins.CompleteFiles, word: dir + "/x", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.complete(nil, tt.word); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completer got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package builtins
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+func init() {
+	Register("cp", func(ctx *Context, args ...string) error {
+		return Copy(ctx.FileSystem(), args...)
+	}, Meta{
+		Synopsis: "cp [-nr] SOURCE... DEST",
+		Summary:  "copy files",
+		Flags: []string{
+			"-n\tdo not overwrite existing files",
+			"-r\tcopy directories recursively",
+		},
+	})
+}
+
+// Copy handles the "cp" built-in command.
+// It copies files (and directories with -r) preserving their permissions.
+// With several sources the destination must be a directory. With -n existing files are left alone.
+func Copy(fsys FileSystem, args ...string) error {
+	var (
+		recursive, noClobber bool
+		paths                = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			paths = append(paths, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'r', 'R':
+				recursive = true
+			case 'n':
+				noClobber = true
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+
+	return forEachSource(fsys, "cp", paths, func(src, dst string) error {
+		info, err := fsys.Lstat(src)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if !recursive {
+				return fmt.Errorf("%w: -r not specified; omitting directory", ErrInvalidArgs)
+			}
+			return copyTree(fsys, src, dst, noClobber)
+		}
+		return copyEntry(fsys, src, dst, info, noClobber)
+	})
+}
+
+// forEachSource resolves "SRC... DST" operands, placing sources inside DST when it is a directory.
+func forEachSource(fsys FileSystem, cmd string, paths []string, fn func(src, dst string) error) error {
+	if len(paths) < 2 {
+		return fmt.Errorf("%w: expected source and destination", ErrInvalidArgCount)
+	}
+	sources, dst := paths[:len(paths)-1], paths[len(paths)-1]
+	info, err := fsys.Stat(dst)
+	dstIsDir := err == nil && info.IsDir()
+	if len(sources) > 1 && !dstIsDir {
+		return fileError(cmd, dst, fmt.Errorf("%w: target is not a directory", ErrInvalidArgs))
+	}
+
+	for _, src := range sources {
+		target := dst
+		if dstIsDir {
+			target = filepath.Join(dst, filepath.Base(src))
+		}
+		if err := fn(src, target); err != nil {
+			return fileError(cmd, src, err)
+		}
+	}
+
+	return nil
+}
+
+// copyTree recursively copies the directory src to dst.
+func copyTree(fsys FileSystem, src, dst string, noClobber bool) error {
+	return WalkDir(fsys, src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		return copyEntry(fsys, path, filepath.Join(dst, rel), info, noClobber)
+	})
+}
+
+// copyEntry copies a single file, directory (without contents) or symlink.
+func copyEntry(fsys FileSystem, src, dst string, info fs.FileInfo, noClobber bool) error {
+	switch mode := info.Mode(); {
+	case mode.IsDir():
+		if err := fsys.Mkdir(dst, mode.Perm()); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		return fsys.Chmod(dst, mode.Perm())
+	case mode&fs.ModeSymlink != 0:
+		if _, err := fsys.Lstat(dst); err == nil {
+			if noClobber {
+				return nil
+			}
+			if err := fsys.Remove(dst); err != nil {
+				return err
+			}
+		}
+		target, err := fsys.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return fsys.Symlink(target, dst)
+	default:
+		return copyFile(fsys, src, dst, mode.Perm(), noClobber)
+	}
+}
+
+// copyFile copies the contents of src to dst and gives dst the permissions perm.
+func copyFile(fsys FileSystem, src, dst string, perm fs.FileMode, noClobber bool) error {
+	if noClobber {
+		if _, err := fsys.Lstat(dst); err == nil {
+			return nil
+		}
+	}
+	in, err := fsys.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := fsys.Create(dst, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// OpenFile only applies perm to new files, and the umask may have narrowed it.
+	return fsys.Chmod(dst, perm)
+}
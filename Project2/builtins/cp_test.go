@@ -0,0 +1,155 @@
+package builtins_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, "dir", "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "dir", "sub", "f.txt"), []byte("nested"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/f.txt", filepath.Join(src, "dir", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		existing map[string]string
+		args     func(dst string) []string
+		want     map[string]string
+		wantErr  error
+	}{
+		{
+			name: "file",
+			args: func(dst string) []string {
+				return []string{filepath.Join(src, "run.sh"), filepath.Join(dst, "copy.sh")}
+			},
+			want: map[string]string{"copy.sh": "#!/bin/sh\n"},
+		},
+		{
+			name: "into directory",
+			args: func(dst string) []string {
+				return []string{filepath.Join(src, "run.sh"), dst}
+			},
+			want: map[string]string{"run.sh": "#!/bin/sh\n"},
+		},
+		{
+			name:     "no clobber",
+			existing: map[string]string{"run.sh": "keep"},
+			args: func(dst string) []string {
+				return []string{"-n", filepath.Join(src, "run.sh"), dst}
+			},
+			want: map[string]string{"run.sh": "keep"},
+		},
+		{
+			name: "recursive",
+			args: func(dst string) []string {
+				return []string{"-r", filepath.Join(src, "dir"), filepath.Join(dst, "copy")}
+			},
+			want: map[string]string{"copy/sub/f.txt": "nested", "copy/link": "nested"},
+		},
+		{
+			name: "directory without -r",
+			args: func(dst string) []string {
+				return []string{filepath.Join(src, "dir"), dst}
+			},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name: "several sources need a directory",
+			args: func(dst string) []string {
+				return []string{filepath.Join(src, "run.sh"), filepath.Join(src, "run.sh"), filepath.Join(dst, "x")}
+			},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name: "missing destination",
+			args: func(string) []string {
+				return []string{filepath.Join(src, "run.sh")}
+			},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dst := t.TempDir()
+			for name, content := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dst, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := builtins.Copy(builtins.OSFileSystem, tt.args(dst)...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Copy() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Copy() unexpected error: %v", err)
+			}
+			for name, want := range tt.want {
+				b, err := os.ReadFile(filepath.Join(dst, name))
+				if err != nil {
+					t.Fatalf("Copy() did not create %v: %v", name, err)
+				}
+				if string(b) != want {
+					t.Errorf("Copy() %v = %q, want %q", name, b, want)
+				}
+			}
+		})
+	}
+
+	t.Run("preserves permissions", func(t *testing.T) {
+		t.Parallel()
+		dst := filepath.Join(t.TempDir(), "run.sh")
+		if err := builtins.Copy(builtins.OSFileSystem, filepath.Join(src, "run.sh"), dst); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != 0o750 {
+			t.Errorf("Copy() mode = %v, want %v", got, os.FileMode(0o750))
+		}
+	})
+}
+
+func TestMove(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "a.txt")
+	dir := filepath.Join(tmp, "dir")
+	if err := os.WriteFile(src, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builtins.Move(src, dir); err != nil {
+		t.Fatalf("Move() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Errorf("Move() did not move into the directory: %v", err)
+	}
+	if _, err := os.Stat(src); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Move() left the source behind")
+	}
+	if err := builtins.Move(src, dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Move() error = %v, wantErr %v", err, os.ErrNotExist)
+	}
+}
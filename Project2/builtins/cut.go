@@ -0,0 +1,200 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// span is an inclusive 1-based range of fields, characters or bytes; end 0
+// means "to the end".
+type span struct {
+	start, end int
+}
+
+func init() {
+	Register("cut", func(ctx *Context, args ...string) error {
+		return Cut(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "cut (-f LIST [-d DELIM] | -c LIST | -b LIST) [FILE...]",
+		Summary:  "print selected fields, characters or bytes of lines",
+		Flags: []string{
+			"-b LIST\tselect bytes",
+			"-c LIST\tselect characters",
+			"-d DELIM\tfield delimiter (default tab)",
+			"-f LIST\tselect fields",
+		},
+	})
+}
+
+// Cut handles the "cut" built-in command.
+// It prints selected fields (-f LIST, split on -d DELIM, default tab),
+// characters (-c LIST) or bytes (-b LIST) from each line of the named files,
+// or of r when no file is given. A character is a UTF-8 encoded rune, so -c
+// never splits one, while -b may.
+func Cut(r io.Reader, w io.Writer, args ...string) error {
+	var (
+		delim     = "\t"
+		fields    []span
+		chars     []span
+		byteSpans []span
+		files     = make([]string, 0)
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		value := arg[2:]
+		if value == "" {
+			if len(args) < i+2 {
+				return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+			}
+			value = args[i+1]
+			i++
+		}
+		var err error
+		switch arg[1] {
+		case 'd':
+			if len([]rune(value)) != 1 {
+				return fmt.Errorf("%w: the delimiter must be a single character", ErrInvalidArgs)
+			}
+			delim = value
+		case 'f':
+			fields, err = parseSpans(value)
+		case 'c':
+			chars, err = parseSpans(value)
+		case 'b':
+			byteSpans, err = parseSpans(value)
+		default:
+			return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, arg[1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	lists := 0
+	for _, spans := range [][]span{fields, chars, byteSpans} {
+		if spans != nil {
+			lists++
+		}
+	}
+	if lists != 1 {
+		return fmt.Errorf("%w: expected exactly one of -f, -c or -b", ErrInvalidArgs)
+	}
+
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+	for _, name := range files {
+		if err := withInput(r, name, func(in io.Reader) error {
+			lines, err := readLines(in)
+			if err != nil {
+				return err
+			}
+			for _, line := range lines {
+				switch {
+				case fields != nil:
+					line = cutFields(line, delim, fields)
+				case chars != nil:
+					line = cutChars(line, chars)
+				default:
+					line = cutBytes(line, byteSpans)
+				}
+				if _, err := fmt.Fprintln(w, line); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseSpans parses a list such as "1,3-5,7-" or "-2".
+func parseSpans(list string) ([]span, error) {
+	spans := make([]span, 0)
+	for _, part := range strings.Split(list, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		var (
+			s   = span{start: 1}
+			err error
+		)
+		if from != "" {
+			if s.start, err = strconv.Atoi(from); err != nil || s.start < 1 {
+				return nil, fmt.Errorf("%w: invalid list %q", ErrInvalidArgs, list)
+			}
+		}
+		switch {
+		case !isRange:
+			if from == "" {
+				return nil, fmt.Errorf("%w: invalid list %q", ErrInvalidArgs, list)
+			}
+			s.end = s.start
+		case to != "":
+			if s.end, err = strconv.Atoi(to); err != nil || s.end < s.start {
+				return nil, fmt.Errorf("%w: invalid list %q", ErrInvalidArgs, list)
+			}
+		}
+		spans = append(spans, s)
+	}
+
+	return spans, nil
+}
+
+// selected reports whether the 1-based position n falls in any span.
+func selected(spans []span, n int) bool {
+	for _, s := range spans {
+		if n >= s.start && (s.end == 0 || n <= s.end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cutFields keeps the selected delimited fields; lines without the delimiter pass through unchanged.
+func cutFields(line, delim string, spans []span) string {
+	if !strings.Contains(line, delim) {
+		return line
+	}
+	kept := make([]string, 0)
+	for i, field := range strings.Split(line, delim) {
+		if selected(spans, i+1) {
+			kept = append(kept, field)
+		}
+	}
+
+	return strings.Join(kept, delim)
+}
+
+// cutChars keeps the selected characters of line.
+func cutChars(line string, spans []span) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range line {
+		n++
+		if selected(spans, n) {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+// cutBytes keeps the selected bytes of line.
+func cutBytes(line string, spans []span) string {
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		if selected(spans, i+1) {
+			sb.WriteByte(line[i])
+		}
+	}
+
+	return sb.String()
+}
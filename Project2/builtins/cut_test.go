@@ -0,0 +1,149 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestCut(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "fields with delimiter",
+			stdin:   "root:x:0:0\nbob:x:1000:1000\n",
+			args:    []string{"-d", ":", "-f", "1,3"},
+			wantOut: "root:0\nbob:1000\n",
+		},
+		{
+			name:    "open ended field range",
+			stdin:   "a\tb\tc\td\n",
+			args:    []string{"-f2-"},
+			wantOut: "b\tc\td\n",
+		},
+		{
+			name:    "lines without delimiter pass through",
+			stdin:   "no delimiter\n",
+			args:    []string{"-d,", "-f", "2"},
+			wantOut: "no delimiter\n",
+		},
+		{
+			name:    "characters",
+			stdin:   "abcdef\n",
+			args:    []string{"-c", "-2,5"},
+			wantOut: "abe\n",
+		},
+		{
+			name:    "characters are runes",
+			stdin:   "héllo 世界\n",
+			args:    []string{"-c", "2,7-"},
+			wantOut: "é世界\n",
+		},
+		{
+			name:    "bytes",
+			stdin:   "héllo\n",
+			args:    []string{"-b", "1,4-"},
+			wantOut: "hllo\n",
+		},
+		{
+			name:    "one list only",
+			args:    []string{"-c", "1", "-b", "1"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "needs a list",
+			args:    []string{"-d", ":"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "bad list",
+			args:    []string{"-f", "3-1"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Cut(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Cut() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Cut() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Cut() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "ranges",
+			stdin:   "Hello, World\n",
+			args:    []string{"a-z", "A-Z"},
+			wantOut: "HELLO, WORLD\n",
+		},
+		{
+			name:    "short second set is padded",
+			stdin:   "abcd",
+			args:    []string{"abcd", "xy"},
+			wantOut: "xyyy",
+		},
+		{
+			name:    "delete with escapes",
+			stdin:   "a b\tc\n",
+			args:    []string{"-d", " \\t\\n"},
+			wantOut: "abc",
+		},
+		{
+			name:    "missing set",
+			args:    []string{"abc"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+		{
+			name:    "reversed range",
+			args:    []string{"z-a", "x"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Translate(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Translate() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Translate() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
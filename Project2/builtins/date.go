@@ -5,12 +5,127 @@ package builtins
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Now returns the current time; tests replace it to get stable output.
+var Now = time.Now
+
+func init() {
+	Register("date", func(ctx *Context, args ...string) error {
+		return Date(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "date [-u] [+FORMAT]",
+		Summary:  "print the date and time",
+		Flags: []string{
+			"-u\tuse UTC",
+			"+FORMAT\tstrftime-style format, e.g. +%Y-%m-%d",
+		},
+	})
+}
+
 // Date handles the "date" built-in command.
-func Date(w io.Writer) error {
-	now := time.Now()
-	_, err := fmt.Fprintln(w, now.Format("Mon Jan 2 15:04:05 MST 2006"))
+// With -u the time is shown in UTC, and +FORMAT selects strftime-style output, e.g. date +%Y-%m-%d.
+func Date(w io.Writer, args ...string) error {
+	now := Now()
+	format := "%a %b %e %H:%M:%S %Z %Y"
+	for _, arg := range args {
+		switch {
+		case arg == "-u":
+			now = now.UTC()
+		case strings.HasPrefix(arg, "+"):
+			format = arg[1:]
+		default:
+			return fmt.Errorf("%w: expected [-u] [+FORMAT]", ErrInvalidArgs)
+		}
+	}
+
+	_, err := fmt.Fprintln(w, strftime(format, now))
 	return err
 }
+
+// strftime formats t using C strftime conversion specifications.
+// Unknown conversions are copied through unchanged.
+func strftime(format string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch c := format[i]; c {
+		case 'a':
+			sb.WriteString(t.Format("Mon"))
+		case 'A':
+			sb.WriteString(t.Format("Monday"))
+		case 'b', 'h':
+			sb.WriteString(t.Format("Jan"))
+		case 'B':
+			sb.WriteString(t.Format("January"))
+		case 'c':
+			sb.WriteString(strftime("%a %b %e %H:%M:%S %Y", t))
+		case 'C':
+			fmt.Fprintf(&sb, "%02d", t.Year()/100)
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'D':
+			sb.WriteString(strftime("%m/%d/%y", t))
+		case 'e':
+			fmt.Fprintf(&sb, "%2d", t.Day())
+		case 'F':
+			sb.WriteString(strftime("%Y-%m-%d", t))
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&sb, "%02d", (t.Hour()+11)%12+1)
+		case 'j':
+			fmt.Fprintf(&sb, "%03d", t.YearDay())
+		case 'k':
+			fmt.Fprintf(&sb, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&sb, "%2d", (t.Hour()+11)%12+1)
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", int(t.Month()))
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case 'n':
+			sb.WriteByte('\n')
+		case 'N':
+			fmt.Fprintf(&sb, "%09d", t.Nanosecond())
+		case 'p':
+			sb.WriteString(t.Format("PM"))
+		case 'R':
+			sb.WriteString(strftime("%H:%M", t))
+		case 's':
+			sb.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case 'S':
+			fmt.Fprintf(&sb, "%02d", t.Second())
+		case 't':
+			sb.WriteByte('\t')
+		case 'T':
+			sb.WriteString(strftime("%H:%M:%S", t))
+		case 'u':
+			fmt.Fprint(&sb, (int(t.Weekday())+6)%7+1)
+		case 'w':
+			fmt.Fprint(&sb, int(t.Weekday()))
+		case 'y':
+			fmt.Fprintf(&sb, "%02d", t.Year()%100)
+		case 'Y':
+			fmt.Fprint(&sb, t.Year())
+		case 'z':
+			sb.WriteString(t.Format("-0700"))
+		case 'Z':
+			sb.WriteString(t.Format("MST"))
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
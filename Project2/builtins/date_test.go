@@ -0,0 +1,63 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	fixed := time.Date(2023, time.March, 5, 14, 7, 9, 42, time.FixedZone("CST", -6*60*60))
+	oldNow := Now
+	t.Cleanup(func() {
+		Now = oldNow
+	})
+	Now = func() time.Time { return fixed }
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{name: "default", wantOut: "Sun Mar  5 14:07:09 CST 2023\n"},
+		{name: "utc", args: []string{"-u", "+%H:%M %Z"}, wantOut: "20:07 UTC\n"},
+		{name: "iso date and time", args: []string{"+%F %T"}, wantOut: "2023-03-05 14:07:09\n"},
+		{name: "twelve hour clock", args: []string{"+%I:%M %p"}, wantOut: "02:07 PM\n"},
+		{name: "day numbers", args: []string{"+%j %u %w %y %C"}, wantOut: "064 7 0 23 20\n"},
+		{name: "epoch and zone offset", args: []string{"+%s %z"}, wantOut: "1678046829 -0600\n"},
+		{name: "literal percent and unknown", args: []string{"+100%% %Q"}, wantOut: "100% %Q\n"},
+		{name: "bad argument", args: []string{"tomorrow"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Date(&out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Date() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Date() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Date() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_formatUptime(t *testing.T) {
+	t.Parallel()
+	for d, want := range map[time.Duration]string{
+		17 * time.Minute:                              "17 min",
+		3*time.Hour + 5*time.Minute:                   " 3:05",
+		24*time.Hour + 10*time.Minute:                 "1 day, 10 min",
+		3*24*time.Hour + 14*time.Hour + 2*time.Minute: "3 days, 14:02",
+	} {
+		if got := formatUptime(d); got != want {
+			t.Errorf("formatUptime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
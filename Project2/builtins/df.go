@@ -0,0 +1,92 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+)
+
+// fsUsage is the capacity of one mounted filesystem, in bytes.
+type fsUsage struct {
+	Device, Mount      string
+	Total, Free, Avail uint64
+}
+
+func init() {
+	Register("df", func(ctx *Context, args ...string) error {
+		return DiskFree(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "df [-h] [PATH...]",
+		Summary:  "report filesystem space",
+		Flags:    []string{"-h\thuman-readable sizes"},
+	})
+}
+
+// DiskFree handles the "df" built-in command.
+// It reports the size, used and available space of every mounted filesystem,
+// or of the filesystems holding the given paths, in 1K blocks or, with -h, human units.
+func DiskFree(w io.Writer, args ...string) error {
+	human := false
+	paths := make([]string, 0)
+	for _, arg := range args {
+		switch {
+		case arg == "-h":
+			human = true
+		case len(arg) > 1 && arg[0] == '-':
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	usages, err := filesystemUsage(paths)
+	if err != nil {
+		return err
+	}
+
+	size := func(b uint64) string {
+		if human {
+			return humanSize(int64(b))
+		}
+		return fmt.Sprint(b / 1024)
+	}
+	sizeHeader := "1K-blocks"
+	if human {
+		sizeHeader = "Size"
+	}
+	if _, err := fmt.Fprintf(w, "%-20v %10v %10v %10v %5v %v\n", "Filesystem", sizeHeader, "Used", "Avail", "Use%", "Mounted on"); err != nil {
+		return err
+	}
+	for _, u := range usages {
+		used := u.Total - u.Free
+		pct := "-"
+		if used+u.Avail > 0 {
+			// Like df, usage is relative to what non-root users can use, rounded up.
+			pct = fmt.Sprintf("%d%%", (used*100+used+u.Avail-1)/(used+u.Avail))
+		}
+		if _, err := fmt.Fprintf(w, "%-20v %10v %10v %10v %5v %v\n",
+			u.Device, size(u.Total), size(used), size(u.Avail), pct, u.Mount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// humanSize formats a byte count with a binary unit suffix, e.g. 512, 1.5K, 23M.
+func humanSize(n int64) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return fmt.Sprint(n)
+	}
+	value := float64(n)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, units[unit])
+	}
+
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
@@ -0,0 +1,92 @@
+package builtins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// filesystemUsage statfs's every real filesystem in /proc/mounts, or the ones holding paths.
+func filesystemUsage(paths []string) ([]fsUsage, error) {
+	b, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	type mount struct{ device, dir string }
+	mounts := make([]mount, 0)
+	for _, line := range strings.Split(string(b), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			mounts = append(mounts, mount{device: fields[0], dir: unescapeMount(fields[1])})
+		}
+	}
+
+	targets := make([]mount, 0)
+	if len(paths) == 0 {
+		targets = mounts
+	}
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return nil, fileError("df", p, err)
+		}
+		// The filesystem holding a path is the longest mount point containing it.
+		best := mount{device: "-", dir: "/"}
+		for _, m := range mounts {
+			if (abs == m.dir || strings.HasPrefix(abs, strings.TrimSuffix(m.dir, "/")+"/")) && len(m.dir) >= len(best.dir) {
+				best = m
+			}
+		}
+		targets = append(targets, best)
+	}
+
+	usages := make([]fsUsage, 0, len(targets))
+	for _, m := range targets {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(m.dir, &st); err != nil {
+			if len(paths) == 0 {
+				continue
+			}
+			return nil, fileError("df", m.dir, err)
+		}
+		if st.Blocks == 0 && len(paths) == 0 {
+			// Pseudo filesystems such as proc and cgroup have no capacity.
+			continue
+		}
+		bsize := uint64(st.Frsize)
+		if bsize == 0 {
+			bsize = uint64(st.Bsize)
+		}
+		usages = append(usages, fsUsage{
+			Device: m.device,
+			Mount:  m.dir,
+			Total:  st.Blocks * bsize,
+			Free:   st.Bfree * bsize,
+			Avail:  st.Bavail * bsize,
+		})
+	}
+
+	return usages, nil
+}
+
+// unescapeMount decodes the octal escapes (e.g. \040 for space) used in /proc/mounts.
+func unescapeMount(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
+			sb.WriteByte((s[i+1]-'0')<<6 | (s[i+2]-'0')<<3 | (s[i+3] - '0'))
+			i += 3
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+
+	return sb.String()
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
@@ -0,0 +1,37 @@
+//go:build !linux
+
+package builtins
+
+import (
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// filesystemUsage uses gopsutil for every mounted filesystem, or the ones holding paths.
+func filesystemUsage(paths []string) ([]fsUsage, error) {
+	if len(paths) == 0 {
+		partitions, err := disk.Partitions(false)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range partitions {
+			paths = append(paths, p.Mountpoint)
+		}
+	}
+
+	usages := make([]fsUsage, 0, len(paths))
+	for _, p := range paths {
+		u, err := disk.Usage(p)
+		if err != nil {
+			return nil, fileError("df", p, err)
+		}
+		usages = append(usages, fsUsage{
+			Device: u.Fstype,
+			Mount:  u.Path,
+			Total:  u.Total,
+			Free:   u.Free,
+			Avail:  u.Free,
+		})
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,351 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// diffContext is how many unchanged lines diff -u shows around each change.
+const diffContext = 3
+
+// noNewline follows a last line that has no newline in diff's output.
+const noNewline = "\\ No newline at end of file"
+
+func init() {
+	Register("diff", func(ctx *Context, args ...string) error {
+		return Diff(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "diff [-u | -U N] FILE1 FILE2",
+		Summary:  "show the lines that differ between two files",
+		Paged:    true,
+		Flags: []string{
+			"-u\tunified format with 3 lines of context",
+			"-U N\tunified format with N lines of context",
+		},
+	})
+}
+
+// Diff handles the "diff" built-in command.
+// It prints the changes that turn FILE1 into FILE2, as ed-style commands such
+// as 2,3c2 by default or as unified hunks with -u, and exits with status 1
+// if there are any. Either file may be "-" for r.
+func Diff(r io.Reader, w io.Writer, args ...string) error {
+	unified, context := false, diffContext
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		switch arg := args[0]; {
+		case arg == "-u":
+			unified = true
+		case arg == "-U" && len(args) > 1:
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 0 {
+				return fmt.Errorf("%w: invalid context length %q", ErrInvalidArgs, args[1])
+			}
+			unified, context = true, n
+			args = args[1:]
+		default:
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		}
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("%w: expected two files", ErrInvalidArgCount)
+	}
+
+	var a, b []string
+	if err := withInput(r, args[0], func(in io.Reader) (err error) {
+		a, err = readDiffLines(in)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := withInput(r, args[1], func(in io.Reader) (err error) {
+		b, err = readDiffLines(in)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	ops := diffLines(a, b)
+	changed := false
+	for _, op := range ops {
+		changed = changed || op.kind != ' '
+	}
+	if !changed {
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+	if unified {
+		fmt.Fprintf(bw, "--- %v\t%v\n+++ %v\t%v\n", args[0], modTime(args[0]), args[1], modTime(args[1]))
+		printUnified(bw, ops, a, b, context)
+	} else {
+		printNormal(bw, ops, a, b)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return &ExitError{Status: StatusFailure}
+}
+
+// readDiffLines splits r into lines, each keeping its newline, so that a
+// missing newline at the end counts as a difference.
+func readDiffLines(r io.Reader) ([]string, error) {
+	var lines []string
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// modTime returns a file's modification time as diff -u shows it, or the
+// current time for "-".
+func modTime(name string) string {
+	t := time.Now()
+	if name != "-" {
+		if fi, err := os.Stat(name); err == nil {
+			t = fi.ModTime()
+		}
+	}
+
+	return t.Format("2006-01-02 15:04:05.000000000 -0700")
+}
+
+// diffOp is one step of an edit script: keeping (' '), deleting ('-') or
+// inserting ('+') a line. a and b are the positions in each file before it.
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// diffLines returns a shortest edit script turning a into b, found with
+// Myers' O(ND) algorithm.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1) // v[k+offset] is the furthest x on diagonal k
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		done := false
+		for k := -d; k <= d && !done; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset]
+			} else {
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[k+offset] = x
+			done = x >= n && y >= m
+		}
+		if done {
+			break
+		}
+	}
+
+	// Walk back from the end through the saved rounds.
+	var kinds []byte
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+			prevK = k + 1
+		}
+		prevX := v[prevK+offset]
+		prevY := prevX - prevK
+		for ; x > prevX && y > prevY; x, y = x-1, y-1 {
+			kinds = append(kinds, ' ')
+		}
+		if x == prevX {
+			kinds = append(kinds, '+')
+		} else {
+			kinds = append(kinds, '-')
+		}
+		x, y = prevX, prevY
+	}
+	for ; x > 0 && y > 0; x, y = x-1, y-1 {
+		kinds = append(kinds, ' ')
+	}
+
+	// Forwards again, with the deletions of each run of changes first, as
+	// diff prints them.
+	ops := make([]diffOp, 0, len(kinds))
+	x, y = 0, 0
+	for i := len(kinds) - 1; i >= 0; {
+		if kinds[i] == ' ' {
+			ops = append(ops, diffOp{' ', x, y})
+			x, y, i = x+1, y+1, i-1
+			continue
+		}
+		var dels, adds int
+		for ; i >= 0 && kinds[i] != ' '; i-- {
+			if kinds[i] == '-' {
+				dels++
+			} else {
+				adds++
+			}
+		}
+		for ; dels > 0; dels-- {
+			ops = append(ops, diffOp{'-', x, y})
+			x++
+		}
+		for ; adds > 0; adds-- {
+			ops = append(ops, diffOp{'+', x, y})
+			y++
+		}
+	}
+
+	return ops
+}
+
+// printNormal writes the edit script as diff's default ed-style commands.
+func printNormal(w *bufio.Writer, ops []diffOp, a, b []string) {
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(ops) && ops[i].kind != ' ' {
+			i++
+		}
+		run := ops[start:i]
+		var dels, adds []int
+		for _, op := range run {
+			if op.kind == '-' {
+				dels = append(dels, op.a)
+			} else {
+				adds = append(adds, op.b)
+			}
+		}
+		// Positions are the lines changed, or the line after which lines
+		// are added or where deleted lines would have been.
+		aFrom, bFrom := run[0].a, run[0].b
+		switch {
+		case len(adds) == 0:
+			fmt.Fprintf(w, "%vd%d\n", lineRange(aFrom+1, len(dels)), bFrom)
+		case len(dels) == 0:
+			fmt.Fprintf(w, "%da%v\n", aFrom, lineRange(bFrom+1, len(adds)))
+		default:
+			fmt.Fprintf(w, "%vc%v\n", lineRange(aFrom+1, len(dels)), lineRange(bFrom+1, len(adds)))
+		}
+		for _, n := range dels {
+			writeDiffLine(w, "< ", a[n])
+		}
+		if len(dels) > 0 && len(adds) > 0 {
+			w.WriteString("---\n")
+		}
+		for _, n := range adds {
+			writeDiffLine(w, "> ", b[n])
+		}
+	}
+}
+
+// lineRange formats count lines from first as "first" or "first,last".
+func lineRange(first, count int) string {
+	if count == 1 {
+		return strconv.Itoa(first)
+	}
+
+	return fmt.Sprintf("%d,%d", first, first+count-1)
+}
+
+// printUnified writes the edit script as unified hunks with context lines of
+// unchanged text around the changes; changes closer than twice that share a
+// hunk.
+func printUnified(w *bufio.Writer, ops []diffOp, a, b []string, context int) {
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			same := end
+			for same < len(ops) && ops[same].kind == ' ' {
+				same++
+			}
+			if same == len(ops) || same-end > 2*context {
+				break
+			}
+			end = same
+		}
+		stop := end + context
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		hunk := ops[start:stop]
+		var aCount, bCount int
+		for _, op := range hunk {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(w, "@@ -%v +%v @@\n", hunkRange(hunk[0].a, aCount), hunkRange(hunk[0].b, bCount))
+		for _, op := range hunk {
+			switch op.kind {
+			case '+':
+				writeDiffLine(w, "+", b[op.b])
+			case '-':
+				writeDiffLine(w, "-", a[op.a])
+			default:
+				writeDiffLine(w, " ", a[op.a])
+			}
+		}
+		i = stop
+	}
+}
+
+// hunkRange formats a unified hunk's range of count lines from the 0-based
+// line start: "N" for one line, "N,COUNT" otherwise, where N is the line
+// before the hunk when it is empty.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// writeDiffLine writes a line with its prefix, noting a missing newline.
+func writeDiffLine(w *bufio.Writer, prefix, line string) {
+	w.WriteString(prefix)
+	w.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		w.WriteString("\n" + noNewline + "\n")
+	}
+}
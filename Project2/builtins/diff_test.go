@@ -0,0 +1,165 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(tmp, name)
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	letters := write("letters", "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n")
+	changed := write("changed", "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl")
+	short := write("short", "x\ny\n")
+	empty := write("empty", "")
+
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		want       string
+		wantStatus int
+		wantErr    error
+	}{
+		{name: "same", args: []string{letters, letters}},
+		{
+			name:       "normal",
+			args:       []string{letters, changed},
+			want:       "2c2\n< b\n---\n> B\n11a12\n> l\n\\ No newline at end of file\n",
+			wantStatus: 1,
+		},
+		{
+			name: "unified",
+			args: []string{"-u", letters, changed},
+			want: "@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -9,3 +9,4 @@\n i\n j\n k\n+l\n\\ No newline at end of file\n",
+			wantStatus: 1,
+		},
+		{
+			name: "unified joins close hunks",
+			args: []string{"-U", "5", letters, changed},
+			want: "@@ -1,11 +1,12 @@\n a\n-b\n+B\n c\n d\n e\n f\n g\n h\n i\n j\n k\n+l\n" +
+				"\\ No newline at end of file\n",
+			wantStatus: 1,
+		},
+		{
+			name:       "delete",
+			args:       []string{short, empty},
+			want:       "1,2d0\n< x\n< y\n",
+			wantStatus: 1,
+		},
+		{
+			name:       "unified from empty",
+			args:       []string{"-u", empty, short},
+			want:       "@@ -0,0 +1,2 @@\n+x\n+y\n",
+			wantStatus: 1,
+		},
+		{
+			name:       "stdin",
+			stdin:      "x\nz\n",
+			args:       []string{short, "-"},
+			want:       "2c2\n< y\n---\n> z\n",
+			wantStatus: 1,
+		},
+		{name: "one file", args: []string{short}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "unknown flag", args: []string{"-q", short, short}, wantErr: builtins.ErrInvalidArgs},
+		{name: "missing file", args: []string{short, filepath.Join(tmp, "nope")}, wantErr: os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := builtins.Diff(strings.NewReader(tt.stdin), &out, tt.args...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if status := builtins.StatusOf(err); status != tt.wantStatus {
+				t.Fatalf("Diff() status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			got := out.String()
+			if strings.HasPrefix(got, "--- ") {
+				// Drop the file headers, which have modification times.
+				lines := strings.SplitN(got, "\n", 3)
+				if !strings.HasPrefix(lines[1], "+++ ") {
+					t.Fatalf("Diff() headers = %q", got)
+				}
+				got = lines[2]
+			}
+			if got != tt.want {
+				t.Errorf("Diff() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmp(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(tmp, name)
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a", "abc\ndef\n")
+	b := write("b", "abc\ndxf\n")
+	prefix := write("prefix", "abc\n")
+	partial := write("partial", "abc\nd")
+	empty := write("empty", "")
+
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		want       string
+		wantStatus int
+		wantErr    error
+	}{
+		{name: "same", args: []string{a, a}},
+		{name: "differ", args: []string{a, b}, want: a + " " + b + " differ: byte 6, line 2\n", wantStatus: 1},
+		{name: "silent", args: []string{"-s", a, b}, wantStatus: 1},
+		{name: "eof after line", args: []string{a, prefix}, want: "cmp: EOF on " + prefix + " after byte 4, line 1\n", wantStatus: 1},
+		{name: "eof in line", args: []string{partial, a}, want: "cmp: EOF on " + partial + " after byte 5, in line 2\n", wantStatus: 1},
+		{name: "empty", args: []string{empty, a}, want: "cmp: EOF on " + empty + " which is empty\n", wantStatus: 1},
+		{name: "stdin", stdin: "abc\ndef\n", args: []string{a}},
+		{name: "no files", wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := builtins.Cmp(strings.NewReader(tt.stdin), &out, tt.args...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Cmp() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if status := builtins.StatusOf(err); status != tt.wantStatus {
+				t.Fatalf("Cmp() status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Cmp() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
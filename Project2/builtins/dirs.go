@@ -0,0 +1,248 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// dirStack holds the directories saved by pushd, most recent first. The top of
+// the stack shown by dirs is always the working directory, so a plain cd
+// replaces it.
+var dirStack []string
+
+func init() {
+	Register("pushd", func(ctx *Context, args ...string) error {
+		return Pushd(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "pushd [DIR | +N | -N]",
+		Summary:  "change directory, saving the current one on the directory stack",
+		Flags: []string{
+			"+N\trotate the Nth directory from the left to the top",
+			"-N\trotate the Nth directory from the right to the top",
+		},
+	})
+	Register("popd", func(ctx *Context, args ...string) error {
+		return Popd(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "popd [+N | -N]",
+		Summary:  "remove a directory from the stack and change to the new top",
+	})
+	Register("dirs", func(ctx *Context, args ...string) error {
+		return Dirs(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "dirs [-clv]",
+		Summary:  "print the directory stack",
+		Flags: []string{
+			"-c\tclear the stack",
+			"-l\tshow full paths instead of ~",
+			"-v\tone directory per line with its index",
+		},
+	})
+	RegisterCompleter("pushd", CompleteDirs)
+}
+
+// Pushd handles the "pushd" built-in command.
+//
+//	pushd       swap the top two directories
+//	pushd DIR   change to DIR, saving the current directory on the stack
+//	pushd +N    rotate the stack so the Nth directory from the left (-N: right) is on top
+func Pushd(w io.Writer, args ...string) error {
+	stack, err := fullStack()
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) > 1:
+		return fmt.Errorf("%w: expected zero or one arguments", ErrInvalidArgCount)
+	case len(args) == 0:
+		if len(stack) < 2 {
+			return fmt.Errorf("%w: pushd: no other directory", ErrInvalidArgs)
+		}
+		stack[0], stack[1] = stack[1], stack[0]
+	case isStackIndex(args[0]):
+		n, err := stackIndex(args[0], len(stack))
+		if err != nil {
+			return err
+		}
+		stack = append(stack[n:], stack[:n]...)
+	default:
+		if err := chdir(args[0], false); err != nil {
+			return err
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		stack = append([]string{wd}, stack...)
+	}
+	if err := setStack(stack); err != nil {
+		return err
+	}
+
+	return printStack(w, stack, false)
+}
+
+// Popd handles the "popd" built-in command.
+// It removes the top directory from the stack and changes to the new top, or
+// with +N (-N) removes the Nth directory from the left (right) instead.
+func Popd(w io.Writer, args ...string) error {
+	stack, err := fullStack()
+	if err != nil {
+		return err
+	}
+	n := 0
+	switch len(args) {
+	case 0:
+	case 1:
+		if !isStackIndex(args[0]) {
+			return fmt.Errorf("%w: popd: invalid argument %q", ErrInvalidArgs, args[0])
+		}
+		if n, err = stackIndex(args[0], len(stack)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: expected zero or one arguments", ErrInvalidArgCount)
+	}
+	if len(stack) < 2 {
+		return fmt.Errorf("%w: popd: directory stack empty", ErrInvalidArgs)
+	}
+	stack = append(stack[:n], stack[n+1:]...)
+	if err := setStack(stack); err != nil {
+		return err
+	}
+
+	return printStack(w, stack, false)
+}
+
+// Dirs handles the "dirs" built-in command.
+// It prints the directory stack, one per line with its index with -v, with
+// full paths instead of ~ with -l, or clears it with -c.
+func Dirs(w io.Writer, args ...string) error {
+	var verbose, long bool
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return fmt.Errorf("%w: dirs: invalid argument %q", ErrInvalidArgs, arg)
+		}
+		for _, c := range arg[1:] {
+			switch c {
+			case 'c':
+				dirStack = nil
+				return nil
+			case 'v':
+				verbose = true
+			case 'l':
+				long = true
+			default:
+				return fmt.Errorf("%w: dirs: invalid option -%c", ErrInvalidArgs, c)
+			}
+		}
+	}
+	stack, err := fullStack()
+	if err != nil {
+		return err
+	}
+	if !verbose {
+		return printStack(w, stack, long)
+	}
+	for i, dir := range stack {
+		if !long {
+			dir = AbbreviateHome(dir)
+		}
+		if _, err := fmt.Fprintf(w, "%2d  %v\n", i, dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DirStack returns the directory stack as dirs shows it: the working
+// directory followed by the directories saved by pushd.
+func DirStack() ([]string, error) {
+	return fullStack()
+}
+
+// SetDirStack changes to the first directory of stack and saves the rest, as
+// if they had been pushed.
+func SetDirStack(stack []string) error {
+	if len(stack) == 0 {
+		return fmt.Errorf("%w: empty directory stack", ErrInvalidArgs)
+	}
+
+	return setStack(stack)
+}
+
+// fullStack returns the working directory followed by the saved directories.
+func fullStack() ([]string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{wd}, dirStack...), nil
+}
+
+// setStack changes to the top of stack and saves the rest.
+func setStack(stack []string) error {
+	if err := chdir(stack[0], false); err != nil {
+		return err
+	}
+	dirStack = append([]string(nil), stack[1:]...)
+
+	return nil
+}
+
+// printStack prints stack on one line, abbreviating the home directory unless long.
+func printStack(w io.Writer, stack []string, long bool) error {
+	if !long {
+		stack = append([]string(nil), stack...)
+		for i := range stack {
+			stack[i] = AbbreviateHome(stack[i])
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(stack, " "))
+
+	return err
+}
+
+// isStackIndex reports whether s is a +N or -N stack position.
+func isStackIndex(s string) bool {
+	if len(s) < 2 || (s[0] != '+' && s[0] != '-') {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+
+	return err == nil
+}
+
+// stackIndex converts +N (counting from the left) or -N (from the right) to a
+// position in a stack of size entries.
+func stackIndex(s string, size int) (int, error) {
+	n, _ := strconv.Atoi(s[1:])
+	if n < 0 || n >= size {
+		return 0, fmt.Errorf("%w: %v: directory stack index out of range", ErrInvalidArgs, s)
+	}
+	if s[0] == '-' {
+		n = size - 1 - n
+	}
+
+	return n, nil
+}
+
+// AbbreviateHome replaces the home directory prefix of dir with ~.
+func AbbreviateHome(dir string) string {
+	if HomeDir == "" {
+		return dir
+	}
+	if dir == HomeDir {
+		return "~"
+	}
+	if rest := strings.TrimPrefix(dir, HomeDir); rest != dir && os.IsPathSeparator(rest[0]) {
+		return "~" + rest
+	}
+
+	return dir
+}
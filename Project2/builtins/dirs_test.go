@@ -0,0 +1,103 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPushdPopd(t *testing.T) {
+	// Not parallel: changes the working directory and the shared stack.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		dirStack = nil
+		_ = os.Chdir(wd)
+	}()
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, c := filepath.Join(root, "a"), filepath.Join(root, "b"), filepath.Join(root, "c")
+	for _, dir := range []string{a, b, c} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chdir(a); err != nil {
+		t.Fatal(err)
+	}
+	dirStack = nil
+
+	steps := []struct {
+		name    string
+		run     func(w *bytes.Buffer) error
+		want    []string
+		wantErr error
+	}{
+		{name: "popd empty", run: func(w *bytes.Buffer) error { return Popd(w) }, want: []string{a}, wantErr: ErrInvalidArgs},
+		{name: "pushd b", run: func(w *bytes.Buffer) error { return Pushd(w, b) }, want: []string{b, a}},
+		{name: "pushd c", run: func(w *bytes.Buffer) error { return Pushd(w, c) }, want: []string{c, b, a}},
+		{name: "swap", run: func(w *bytes.Buffer) error { return Pushd(w) }, want: []string{b, c, a}},
+		{name: "rotate +2", run: func(w *bytes.Buffer) error { return Pushd(w, "+2") }, want: []string{a, b, c}},
+		{name: "rotate -0", run: func(w *bytes.Buffer) error { return Pushd(w, "-0") }, want: []string{c, a, b}},
+		{name: "out of range", run: func(w *bytes.Buffer) error { return Pushd(w, "+3") }, want: []string{c, a, b}, wantErr: ErrInvalidArgs},
+		{name: "cd replaces top", run: func(w *bytes.Buffer) error { return ChangeDirectory(root) }, want: []string{root, a, b}},
+		{name: "popd +1", run: func(w *bytes.Buffer) error { return Popd(w, "+1") }, want: []string{root, b}},
+		{name: "popd", run: func(w *bytes.Buffer) error { return Popd(w) }, want: []string{b}},
+	}
+	for _, step := range steps {
+		var out bytes.Buffer
+		if err := step.run(&out); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%v: error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+		got, err := fullStack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(step.want) {
+			t.Fatalf("%v: stack = %q, want %q", step.name, got, step.want)
+		}
+		for i := range got {
+			if got[i] != step.want[i] {
+				t.Fatalf("%v: stack = %q, want %q", step.name, got, step.want)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if err := Dirs(&out, "-v", "-l"); err != nil {
+		t.Fatalf("Dirs() unexpected error: %v", err)
+	}
+	if want := " 0  " + b + "\n"; out.String() != want {
+		t.Errorf("Dirs() got = %q, want %q", out.String(), want)
+	}
+	if err := Dirs(&out, "-x"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("Dirs() error = %v, wantErr %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_abbreviateHome(t *testing.T) {
+	t.Parallel()
+	if HomeDir == "" {
+		t.Skip("no home directory")
+	}
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: HomeDir, want: "~"},
+		{dir: filepath.Join(HomeDir, "src"), want: filepath.Join("~", "src")},
+		{dir: HomeDir + "x", want: HomeDir + "x"},
+	}
+	for _, tt := range tests {
+		if got := AbbreviateHome(tt.dir); got != tt.want {
+			t.Errorf("AbbreviateHome(%q) got = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+func init() {
+	Register("du", func(ctx *Context, args ...string) error {
+		return DiskUsage(ctx.Context(), ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "du [-hs] [PATH...]",
+		Summary:  "report disk usage",
+		Flags: []string{
+			"-h\thuman-readable sizes",
+			"-s\tonly a total per path",
+		},
+	})
+}
+
+// DiskUsage handles the "du" built-in command.
+// It prints the disk usage of every directory below each path (default "."),
+// or only a total per path with -s, in 1K blocks or, with -h, human units.
+// Subdirectories are measured concurrently, and Ctrl-C stops the walk.
+func DiskUsage(ctx context.Context, w io.Writer, args ...string) error {
+	var (
+		summarize, human bool
+		paths            = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			paths = append(paths, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 's':
+				summarize = true
+			case 'h':
+				human = true
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		paths = append(paths, ".")
+	}
+
+	report := func(size int64, path string) error {
+		value := fmt.Sprint((size + 1023) / 1024)
+		if human {
+			value = humanSize(size)
+		}
+		_, err := fmt.Fprintf(w, "%v\t%v\n", value, path)
+		return err
+	}
+
+	for _, root := range paths {
+		walker := &duWalker{
+			ctx:  ctx,
+			sem:  make(chan struct{}, runtime.NumCPU()*4),
+			dirs: make(map[string]int64),
+		}
+		total, err := walker.walk(root)
+		if ctx.Err() != nil {
+			return Interrupted(ctx, err)
+		}
+		if err != nil {
+			return fileError("du", root, err)
+		}
+		if summarize {
+			if err := report(total, root); err != nil {
+				return err
+			}
+			if walker.firstErr != nil {
+				return fileError("du", root, walker.firstErr)
+			}
+			continue
+		}
+
+		// Print children before their parents, as du does.
+		dirs := make([]string, 0, len(walker.dirs))
+		for dir := range walker.dirs {
+			dirs = append(dirs, dir)
+		}
+		sort.Slice(dirs, func(i, j int) bool {
+			return postOrderKey(dirs[i]) < postOrderKey(dirs[j])
+		})
+		for _, dir := range dirs {
+			if err := report(walker.dirs[dir], dir); err != nil {
+				return err
+			}
+		}
+		if walker.firstErr != nil {
+			return fileError("du", root, walker.firstErr)
+		}
+	}
+
+	return nil
+}
+
+// duWalker sums disk usage, measuring subdirectories in parallel up to the semaphore's capacity.
+type duWalker struct {
+	ctx context.Context // stops the walk when cancelled
+	sem chan struct{}
+
+	mu       sync.Mutex
+	dirs     map[string]int64 // total usage of each directory walked
+	firstErr error
+}
+
+// walk returns the usage of path and everything below it. Unreadable
+// subdirectories are skipped; the first such error is kept for the caller.
+func (dw *duWalker) walk(path string) (int64, error) {
+	if err := dw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	total := diskBlocks(info)
+	if !info.IsDir() {
+		return total, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		sum int64
+		wg  sync.WaitGroup
+	)
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		if !entry.IsDir() {
+			if info, err := entry.Info(); err == nil {
+				atomic.AddInt64(&sum, diskBlocks(info))
+			}
+			continue
+		}
+
+		measure := func() {
+			if size, err := dw.walk(child); err != nil {
+				dw.recordErr(err)
+			} else {
+				atomic.AddInt64(&sum, size)
+			}
+		}
+		// Use another goroutine when one is free, otherwise walk inline so deep trees cannot deadlock.
+		select {
+		case dw.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-dw.sem }()
+				measure()
+			}()
+		default:
+			measure()
+		}
+	}
+	wg.Wait()
+
+	total += atomic.LoadInt64(&sum)
+	dw.mu.Lock()
+	dw.dirs[path] = total
+	dw.mu.Unlock()
+
+	return total, nil
+}
+
+func (dw *duWalker) recordErr(err error) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.firstErr == nil {
+		dw.firstErr = err
+	}
+}
+
+// diskBlocks returns the space a file occupies on disk, falling back to its size.
+func diskBlocks(info fs.FileInfo) int64 {
+	if details, ok := sysStat(info); ok {
+		return details.blocks * 512
+	}
+
+	return info.Size()
+}
+
+// postOrderKey sorts paths so that a directory follows everything inside it.
+func postOrderKey(path string) string {
+	return strings.ReplaceAll(path, string(filepath.Separator), "\x01") + "\x02"
+}
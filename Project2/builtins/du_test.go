@@ -0,0 +1,103 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	for _, dir := range []string{"a/b", "a/c", "d"} {
+		if err := os.MkdirAll(filepath.Join(tmp, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "a", "b", "big"), make([]byte, 64*1024), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("every directory children first", func(t *testing.T) {
+		t.Parallel()
+		var out bytes.Buffer
+		if err := DiskUsage(context.Background(), &out, tmp); err != nil {
+			t.Fatalf("DiskUsage() unexpected error: %v", err)
+		}
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			_, path, _ := strings.Cut(line, "\t")
+			got = append(got, strings.TrimPrefix(path, tmp))
+		}
+		want := []string{"/a/b", "/a/c", "/a", "/d", ""}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("DiskUsage() printed %q, want %q", got, want)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		t.Parallel()
+		var out bytes.Buffer
+		if err := DiskUsage(context.Background(), &out, "-sh", tmp); err != nil {
+			t.Fatalf("DiskUsage() unexpected error: %v", err)
+		}
+		if lines := strings.Count(out.String(), "\n"); lines != 1 {
+			t.Fatalf("DiskUsage() printed %v lines, want 1", lines)
+		}
+		if !strings.HasSuffix(out.String(), "K\t"+tmp+"\n") {
+			t.Errorf("DiskUsage() = %q, want a size in K for %v", out.String(), tmp)
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		t.Parallel()
+		var out bytes.Buffer
+		if err := DiskUsage(context.Background(), &out, filepath.Join(tmp, "nope")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("DiskUsage() error = %v, wantErr %v", err, os.ErrNotExist)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var out bytes.Buffer
+		if err := DiskUsage(ctx, &out, tmp); !errors.Is(err, ErrInterrupted) {
+			t.Errorf("DiskUsage() error = %v, wantErr %v", err, ErrInterrupted)
+		}
+	})
+}
+
+func TestDiskFree(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	if err := DiskFree(&out, "-h", t.TempDir()); err != nil {
+		t.Fatalf("DiskFree() unexpected error: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Errorf("DiskFree() printed %v lines, want a header and one filesystem:\n%v", lines, out.String())
+	}
+	if err := DiskFree(&out, "-x"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("DiskFree() error = %v, wantErr %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_humanSize(t *testing.T) {
+	t.Parallel()
+	for n, want := range map[int64]string{
+		0:                "0",
+		1023:             "1023",
+		1536:             "1.5K",
+		20 * 1024 * 1024: "20M",
+		3 << 30:          "3.0G",
+		1<<40 + 1<<39:    "1.5T",
+	} {
+		if got := humanSize(n); got != want {
+			t.Errorf("humanSize(%v) = %q, want %q", n, got, want)
+		}
+	}
+}
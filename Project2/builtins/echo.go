@@ -1,13 +1,138 @@
 package builtins
 
 import (
-	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
+func init() {
+	Register("echo", func(ctx *Context, args ...string) error {
+		return Echo(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "echo [-neE] [--] [ARG...]",
+		Summary:  "print the arguments",
+		Flags: []string{
+			"-n\tdo not print the trailing newline",
+			"-e\tinterpret backslash escapes such as \\n, \\t, \\xHH and \\0NNN",
+			"-E\tdo not interpret backslash escapes (default)",
+			"--\tend of options",
+		},
+	})
+}
+
+// Echo handles the "echo" built-in command.
+// It prints its arguments separated by spaces and followed by a newline.
+// Leading arguments made only of the flags n, e and E are options: -n drops
+// the newline and -e interprets backslash escapes, where \c stops the output
+// there. An argument that isn't a valid option, or one after --, is printed.
 func Echo(w io.Writer, args ...string) error {
+	newline, escapes := true, false
+	for len(args) > 0 {
+		arg := args[0]
+		if arg == "--" {
+			args = args[1:]
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' || strings.Trim(arg[1:], "neE") != "" {
+			break
+		}
+		for _, c := range arg[1:] {
+			switch c {
+			case 'n':
+				newline = false
+			case 'e':
+				escapes = true
+			case 'E':
+				escapes = false
+			}
+		}
+		args = args[1:]
+	}
+
 	message := strings.Join(args, " ")
-	_, err := fmt.Fprintln(w, message)
+	if escapes {
+		var stop bool
+		message, stop = echoUnescape(message)
+		newline = newline && !stop
+	}
+	if newline {
+		message += "\n"
+	}
+	_, err := io.WriteString(w, message)
+
 	return err
 }
+
+// echoEscapes maps the single-character escapes of echo -e to their values.
+var echoEscapes = map[byte]string{
+	'a': "\a", 'b': "\b", 'e': "\x1b", 'E': "\x1b", 'f': "\f",
+	'n': "\n", 'r': "\r", 't': "\t", 'v': "\v", '\\': "\\",
+}
+
+// echoUnescape interprets the backslash escapes of echo -e: the single-character
+// ones above, \0NNN (octal), \xHH (hex), \uHHHH and \UHHHHHHHH (Unicode). It
+// reports whether a \c cut the output short. Unknown escapes are kept as is.
+func echoUnescape(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := s[i+1]
+		if v, ok := echoEscapes[c]; ok {
+			b.WriteString(v)
+			i++
+			continue
+		}
+
+		var base, maxDigits int
+		switch c {
+		case 'c':
+			return b.String(), true
+		case '0':
+			base, maxDigits = 8, 3
+		case 'x':
+			base, maxDigits = 16, 2
+		case 'u':
+			base, maxDigits = 16, 4
+		case 'U':
+			base, maxDigits = 16, 8
+		default:
+			b.WriteByte('\\')
+			continue
+		}
+		digits := 0
+		for digits < maxDigits && i+2+digits < len(s) && isDigit(s[i+2+digits], base) {
+			digits++
+		}
+		if digits == 0 && c != '0' {
+			b.WriteByte('\\')
+			continue
+		}
+		n, _ := strconv.ParseUint("0"+s[i+2:i+2+digits], base, 32)
+		if c == 'u' || c == 'U' {
+			b.WriteRune(rune(n))
+		} else {
+			b.WriteByte(byte(n))
+		}
+		i += 1 + digits
+	}
+
+	return b.String(), false
+}
+
+// isDigit reports whether c is a digit in base 8 or 16.
+func isDigit(c byte, base int) bool {
+	switch {
+	case c >= '0' && c <= '7':
+		return true
+	case base == 8:
+		return false
+	case c >= '8' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package builtins_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestEcho(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no args", want: "\n"},
+		{name: "joins args", args: []string{"a", "b  c"}, want: "a b  c\n"},
+		{name: "no newline", args: []string{"-n", "a"}, want: "a"},
+		{name: "escapes are literal by default", args: []string{`a\tb`}, want: `a\tb` + "\n"},
+		{name: "escapes", args: []string{"-e", `a\tb\nc\\d\a\e`}, want: "a\tb\nc\\d\a\x1b\n"},
+		{name: "octal", args: []string{"-e", `\033[0m\0101\0`}, want: "\x1b[0mA\x00\n"},
+		{name: "hex and unicode", args: []string{"-e", `\x41\x4a\xZé\U0001F600`}, want: "AJ\\xZé😀\n"},
+		{name: "stop output", args: []string{"-e", `one\ctwo`, "three"}, want: "one"},
+		{name: "unknown escape kept", args: []string{"-e", `\q\`}, want: `\q\` + "\n"},
+		{name: "combined flags", args: []string{"-ne", `a\n`}, want: "a\n"},
+		{name: "last of e and E wins", args: []string{"-e", "-E", `a\n`}, want: `a\n` + "\n"},
+		{name: "end of options", args: []string{"--", "-n"}, want: "-n\n"},
+		{name: "not an option", args: []string{"-nx", "-"}, want: "-nx -\n"},
+		{name: "options only lead", args: []string{"a", "-n"}, want: "a -n\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			if err := builtins.Echo(w, tt.args...); err != nil {
+				t.Fatalf("Echo() error = %v", err)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("Echo() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,37 +3,110 @@ package builtins
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
 )
 
-func EnvironmentVariables(w io.Writer, args ...string) error {
-	toRemove := make([]string, 0)
-	for i := 0; i < len(args); i++ {
-		if args[i] == "-u" {
-			if len(args) < i+2 {
+// EnvRunner runs a command with env, NAME=VALUE strings, as its environment.
+type EnvRunner func(env []string, name string, args ...string) error
+
+func init() {
+	Register("env", func(ctx *Context, args ...string) error {
+		return EnvironmentVariables(ctx.Stdout, ctx.Environ(), func(env []string, name string, args ...string) error {
+			return ctx.WithEnv(env).Run(name, args...)
+		}, args...)
+	}, Meta{
+		Synopsis: "env [-i] [-u NAME]... [NAME=VALUE]... [CMD [ARG...]]",
+		Summary:  "print the environment or run a command in a changed one",
+		Paged:    true,
+		Flags: []string{
+			"-i\tstart from an empty environment",
+			"-u NAME\tleave NAME out",
+		},
+	})
+}
+
+// EnvironmentVariables handles the "env" built-in command.
+// It starts from the shell's environment, or an empty one with -i, leaves
+// out each NAME given with -u and sets each NAME=VALUE after the options.
+// With a command after those, it runs the command with the environment made,
+// finding a program on that environment's PATH; the shell's own is left as
+// it is. Otherwise it prints the environment as NAME=VALUE lines.
+func EnvironmentVariables(w io.Writer, environ []string, run EnvRunner, args ...string) error {
+	var (
+		empty    bool
+		toRemove []string
+	)
+options:
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "--":
+			args = args[1:]
+			break options
+		case "-i", "-":
+			empty = true
+			args = args[1:]
+		case "-u":
+			if len(args) < 2 {
 				return fmt.Errorf("%w: -u requires an argument", ErrInvalidArgCount)
 			}
-			toRemove = append(toRemove, args[i+1])
-			i++
+			toRemove = append(toRemove, args[1])
+			args = args[2:]
+		default:
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, args[0])
 		}
 	}
 
-	toShow := make([]string, 0)
-	for _, env := range os.Environ() {
-		show := true
-		for _, v := range toRemove {
-			if strings.HasPrefix(env, v+"=") {
-				show = false
-				break
-			}
+	env := make([]string, 0, len(environ))
+	if !empty {
+		env = append(env, environ...)
+	}
+	for _, name := range toRemove {
+		env = unsetEnv(env, name)
+	}
+	for len(args) > 0 && strings.Contains(args[0], "=") {
+		name, _, _ := strings.Cut(args[0], "=")
+		if name == "" {
+			return fmt.Errorf("%w: invalid variable %q", ErrInvalidArgs, args[0])
 		}
-		if show {
-			toShow = append(toShow, env)
+		env = append(unsetEnv(env, name), args[0])
+		args = args[1:]
+	}
+
+	if len(args) > 0 {
+		if run == nil {
+			return fmt.Errorf("%w: no command runner", ErrInvalidArgs)
+		}
+		return run(env, args[0], args[1:]...)
+	}
+	for _, kv := range env {
+		if _, err := fmt.Fprintln(w, kv); err != nil {
+			return err
 		}
 	}
 
-	_, err := fmt.Fprintln(w, strings.Join(toShow, "\n"))
+	return nil
+}
+
+// unsetEnv returns env without the variable name.
+func unsetEnv(env []string, name string) []string {
+	kept := env[:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, name+"=") {
+			kept = append(kept, kv)
+		}
+	}
+
+	return kept
+}
+
+// lookupEnv returns the value of the variable name in env, where a later
+// setting wins as it does for a process.
+func lookupEnv(env []string, name string) (string, bool) {
+	for i := len(env) - 1; i >= 0; i-- {
+		if value := strings.TrimPrefix(env[i], name+"="); value != env[i] {
+			return value, true
+		}
+	}
 
-	return err
+	return "", false
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -63,7 +64,7 @@ func TestEnvironmentVariables(t *testing.T) {
 
 			// test
 			var out bytes.Buffer
-			if err := EnvironmentVariables(&out, tt.args.args...); tt.wantErr != nil {
+			if err := EnvironmentVariables(&out, os.Environ(), nil, tt.args.args...); tt.wantErr != nil {
 				if !errors.Is(err, tt.wantErr) {
 					t.Fatalf("EnvironmentVariables() error = %v, wantErr %v", err, tt.wantErr)
 				}
@@ -77,3 +78,62 @@ func TestEnvironmentVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentVariables_scoped(t *testing.T) {
+	t.Parallel()
+	environ := []string{"HOME=/home/ann", "LANG=C", "PATH=/bin"}
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantRun []string
+		wantEnv []string
+		wantErr error
+	}{
+		{name: "empty", args: []string{"-i"}},
+		{name: "empty with variables", args: []string{"-i", "A=1", "B=x=y"}, wantOut: "A=1\nB=x=y\n"},
+		{name: "replace and unset", args: []string{"-u", "HOME", "LANG=en"}, wantOut: "PATH=/bin\nLANG=en\n"},
+		{
+			name:    "command",
+			args:    []string{"-i", "--", "PATH=/usr/bin", "printenv", "PATH"},
+			wantRun: []string{"printenv", "PATH"},
+			wantEnv: []string{"PATH=/usr/bin"},
+		},
+		{
+			name:    "command after options",
+			args:    []string{"-u", "LANG", "ls", "-l"},
+			wantRun: []string{"ls", "-l"},
+			wantEnv: []string{"HOME=/home/ann", "PATH=/bin"},
+		},
+		{name: "unknown flag", args: []string{"-x"}, wantErr: ErrInvalidArgs},
+		{name: "no name", args: []string{"=1"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				out bytes.Buffer
+				ran []string
+				env []string
+			)
+			run := func(e []string, name string, args ...string) error {
+				ran, env = append([]string{name}, args...), e
+				return nil
+			}
+			err := EnvironmentVariables(&out, append([]string(nil), environ...), run, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnvironmentVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("EnvironmentVariables() got = %q, want %q", got, tt.wantOut)
+			}
+			if !reflect.DeepEqual(ran, tt.wantRun) {
+				t.Errorf("EnvironmentVariables() ran %q, want %q", ran, tt.wantRun)
+			}
+			if tt.wantRun != nil && !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("EnvironmentVariables() environment = %q, want %q", env, tt.wantEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package builtins
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// redirection is a "[N]>file", "[N]>>file", "[N]<file" or "N>&M" argument to exec.
+type redirection struct {
+	fd      int
+	flag    int
+	file    string
+	dupFrom int // source descriptor for N>&M, or -1
+}
+
+func init() {
+	Register("exec", func(ctx *Context, args ...string) error {
+		return Exec(args...)
+	}, Meta{
+		Synopsis: "exec [REDIRECTION...] [CMD [ARG...]]",
+		Summary:  "replace the shell with a command, or redirect the shell's files",
+	})
+}
+
+// Exec handles the "exec" built-in command.
+// With a command it replaces the shell process with that command. Leading
+// redirections are applied to the shell itself first, so "exec 2>errors.log"
+// with no command sends the shell's stderr to the file from then on.
+func Exec(args ...string) error {
+	redirs, args, err := parseRedirections(args)
+	if err != nil {
+		return err
+	}
+	for _, rd := range redirs {
+		if err := rd.apply(); err != nil {
+			return err
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	// The program is handed the terminal as the shell found it.
+	RestoreTerminal()
+
+	return execProcess(path, args, os.Environ())
+}
+
+// parseRedirections splits the leading redirections off args.
+func parseRedirections(args []string) ([]redirection, []string, error) {
+	var redirs []redirection
+	for len(args) > 0 {
+		rd, ok, err := parseRedirection(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			break
+		}
+		args = args[1:]
+		if rd.file == "" && rd.dupFrom < 0 {
+			if len(args) == 0 {
+				return nil, nil, fmt.Errorf("%w: redirection requires a file name", ErrInvalidArgCount)
+			}
+			rd.file, args = args[0], args[1:]
+		}
+		redirs = append(redirs, rd)
+	}
+
+	return redirs, args, nil
+}
+
+// parseRedirection parses a single redirection, reporting false if s is not one.
+func parseRedirection(s string) (redirection, bool, error) {
+	digits := len(s) - len(strings.TrimLeft(s, "0123456789"))
+	rest := s[digits:]
+	rd := redirection{dupFrom: -1}
+	switch {
+	case strings.HasPrefix(rest, ">>"):
+		rd.fd, rd.flag, rd.file = 1, os.O_WRONLY|os.O_CREATE|os.O_APPEND, rest[2:]
+	case strings.HasPrefix(rest, ">&"):
+		from, err := strconv.Atoi(rest[2:])
+		if err != nil {
+			return rd, false, fmt.Errorf("%w: bad file descriptor in %q", ErrInvalidArgs, s)
+		}
+		rd.fd, rd.dupFrom = 1, from
+	case strings.HasPrefix(rest, ">"):
+		rd.fd, rd.flag, rd.file = 1, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, rest[1:]
+	case strings.HasPrefix(rest, "<"):
+		rd.fd, rd.flag, rd.file = 0, os.O_RDONLY, rest[1:]
+	default:
+		return rd, false, nil
+	}
+	if digits > 0 {
+		fd, err := strconv.Atoi(s[:digits])
+		if err != nil {
+			return rd, false, fmt.Errorf("%w: bad file descriptor in %q", ErrInvalidArgs, s)
+		}
+		rd.fd = fd
+	}
+
+	return rd, true, nil
+}
+
+// apply makes the redirection permanent for the shell process.
+func (rd redirection) apply() error {
+	if rd.dupFrom >= 0 {
+		return redirectFD(rd.dupFrom, rd.fd)
+	}
+	f, err := os.OpenFile(rd.file, rd.flag, 0o666)
+	if err != nil {
+		return fileError("exec", rd.file, err)
+	}
+	defer f.Close()
+
+	return redirectFD(int(f.Fd()), rd.fd)
+}
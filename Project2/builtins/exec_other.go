@@ -0,0 +1,33 @@
+//go:build windows
+
+package builtins
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errExecUnsupported = errors.New("exec is not supported on this platform")
+
+// execProcess can't replace the process on Windows, so it runs the program
+// with the shell's files and then exits with its status, as if it had.
+func execProcess(path string, argv, env []string) error {
+	cmd := exec.Command(path)
+	cmd.Args, cmd.Env = argv, env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+	}
+	os.Exit(cmd.ProcessState.ExitCode())
+
+	return nil
+}
+
+// redirectFD is unsupported: Windows has no descriptors to point elsewhere.
+func redirectFD(int, int) error {
+	return errExecUnsupported
+}
@@ -0,0 +1,98 @@
+package builtins
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_parseRedirections(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		args     []string
+		want     []redirection
+		wantArgs []string
+		wantErr  error
+	}{
+		{
+			name:     "stderr to file",
+			args:     []string{"2>err.log"},
+			want:     []redirection{{fd: 2, flag: os.O_WRONLY | os.O_CREATE | os.O_TRUNC, file: "err.log", dupFrom: -1}},
+			wantArgs: []string{},
+		},
+		{
+			name: "append and input with separate file names",
+			args: []string{">>", "out.log", "<", "in.txt", "cat"},
+			want: []redirection{
+				{fd: 1, flag: os.O_WRONLY | os.O_CREATE | os.O_APPEND, file: "out.log", dupFrom: -1},
+				{fd: 0, flag: os.O_RDONLY, file: "in.txt", dupFrom: -1},
+			},
+			wantArgs: []string{"cat"},
+		},
+		{
+			name:     "duplicate descriptor",
+			args:     []string{"2>&1", "ls", "-l"},
+			want:     []redirection{{fd: 2, dupFrom: 1}},
+			wantArgs: []string{"ls", "-l"},
+		},
+		{
+			name:     "command only",
+			args:     []string{"ls", "2>x"},
+			wantArgs: []string{"ls", "2>x"},
+		},
+		{name: "missing file", args: []string{"2>"}, wantErr: ErrInvalidArgCount},
+		{name: "bad descriptor", args: []string{">&x"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, gotArgs, err := parseRedirections(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseRedirections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRedirections() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("parseRedirections() args = %q, want %q", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	// Redirect a scratch descriptor rather than the test's own stdio.
+	scratch, err := os.Create(filepath.Join(dir, "scratch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scratch.Close()
+	target := filepath.Join(dir, "target")
+	if err := Exec(strconv.Itoa(int(scratch.Fd())) + ">" + target); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if _, err := scratch.WriteString("redirected\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(target); err != nil || string(got) != "redirected\n" {
+		t.Errorf("Exec() target got = %q, %v, want %q", got, err, "redirected\n")
+	}
+
+	if err := Exec("<" + filepath.Join(dir, "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Exec() error = %v, wantErr %v", err, os.ErrNotExist)
+	}
+	if err := Exec("no-such-command-here"); err == nil {
+		t.Errorf("Exec() expected an error for a missing command")
+	}
+}
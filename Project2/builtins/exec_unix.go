@@ -0,0 +1,19 @@
+//go:build !windows
+
+package builtins
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// execProcess replaces the current process image; it only returns on failure.
+func execProcess(path string, argv, env []string) error {
+	return syscall.Exec(path, argv, env)
+}
+
+// redirectFD makes descriptor to refer to the same file as from.
+func redirectFD(from, to int) error {
+	return unix.Dup2(from, to)
+}
@@ -4,7 +4,8 @@ import (
 	"os"
 )
 
-// Exit exits the shell.
+// Exit exits the shell, putting the terminal back as it was found.
 func Exit() {
-    os.Exit(0)
+	RestoreTerminal()
+	os.Exit(0)
 }
@@ -0,0 +1,188 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// ErrHTTPStatus is returned by fetch when the server answers with an error.
+var ErrHTTPStatus = errors.New("HTTP error")
+
+// progressInterval is how often fetch redraws its progress bar.
+const progressInterval = 100 * time.Millisecond
+
+func init() {
+	Register("fetch", func(ctx *Context, args ...string) error {
+		return Fetch(ctx.Context(), ctx.Stdout, ctx.Stderr, args...)
+	}, Meta{
+		Synopsis: "fetch [-X METHOD] [-H 'NAME: VALUE']... [-d BODY | -d @FILE] [-o FILE] URL",
+		Summary:  "make an HTTP request and print or save the response body",
+		Flags: []string{
+			"-X METHOD\trequest method (default GET, or POST with -d)",
+			"-H HEADER\tadd a request header; may be repeated",
+			"-d BODY\tsend BODY, or the contents of FILE for @FILE",
+			"-o FILE\tsave the body to FILE, with a progress bar on a terminal",
+		},
+	})
+}
+
+// Fetch handles the "fetch" built-in command.
+// It sends a request to URL and writes the response body to w, or to the
+// -o file while drawing a progress bar on errW if that is a terminal. A
+// response status of 400 or above is an error once the body is written.
+// Ctrl-C cancels the request.
+func Fetch(ctx context.Context, w, errW io.Writer, args ...string) error {
+	var (
+		method, body, out string
+		headers           = http.Header{}
+		hasBody           bool
+		url               string
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-X" || arg == "-H" || arg == "-d" || arg == "-o" {
+			if i+1 == len(args) {
+				return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+			}
+			i++
+		}
+		switch arg {
+		case "-X":
+			method = strings.ToUpper(args[i])
+		case "-H":
+			name, value, ok := strings.Cut(args[i], ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("%w: invalid header %q", ErrInvalidArgs, args[i])
+			}
+			headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		case "-d":
+			body, hasBody = args[i], true
+		case "-o":
+			out = args[i]
+		default:
+			if strings.HasPrefix(arg, "-") || url != "" {
+				return fmt.Errorf("%w: unexpected argument %v", ErrInvalidArgs, arg)
+			}
+			url = arg
+		}
+	}
+	if url == "" {
+		return fmt.Errorf("%w: expected a URL", ErrInvalidArgCount)
+	}
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	if method == "" {
+		method = http.MethodGet
+		if hasBody {
+			method = http.MethodPost
+		}
+	}
+
+	var reqBody io.Reader
+	if hasBody {
+		if name := strings.TrimPrefix(body, "@"); name != body {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			body = string(data)
+		}
+		reqBody = strings.NewReader(body)
+		if headers.Get("Content-Type") == "" {
+			headers.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Interrupted(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	dst, src := w, io.Reader(resp.Body)
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dst = f
+		if errF, ok := errW.(*os.File); ok && isTerminal(errF) {
+			bar := &progressBar{w: errW, total: resp.ContentLength}
+			defer bar.finish()
+			src = io.TeeReader(resp.Body, bar)
+		}
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return Interrupted(ctx, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %v", ErrHTTPStatus, resp.Status)
+	}
+
+	return nil
+}
+
+// progressBar counts the bytes written to it and redraws a bar on w, such as
+// "[=====>     ]  45%  1.2M/2.6M", at most every progressInterval. With
+// an unknown total it shows only the count.
+type progressBar struct {
+	w     io.Writer
+	total int64 // -1 if unknown
+	done  int64
+	drawn time.Time
+}
+
+func (p *progressBar) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if now := time.Now(); now.Sub(p.drawn) >= progressInterval {
+		p.drawn = now
+		p.draw()
+	}
+
+	return len(b), nil
+}
+
+// finish draws the final state and ends the line.
+func (p *progressBar) finish() {
+	p.draw()
+	_, _ = fmt.Fprintln(p.w)
+}
+
+func (p *progressBar) draw() {
+	if p.total <= 0 {
+		_, _ = fmt.Fprintf(p.w, "\r%v", humanSize(p.done))
+		return
+	}
+	cols, _ := TerminalSize(p.w)
+	width := cols - 30
+	if width < 10 {
+		width = 10
+	}
+	filled := int(int64(width) * p.done / p.total)
+	if filled > width {
+		filled = width
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	_, _ = fmt.Fprintf(p.w, "\r[%v] %3d%%  %v/%v", bar, p.done*100/p.total, humanSize(p.done), humanSize(p.total))
+}
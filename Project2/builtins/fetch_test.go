@@ -0,0 +1,75 @@
+package builtins_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not here", http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%v %v %q %q", r.Method, r.URL.Path, r.Header.Get("X-Test"), body)
+	}))
+	t.Cleanup(srv.Close)
+
+	tmp := t.TempDir()
+	bodyFile := filepath.Join(tmp, "body")
+	if err := os.WriteFile(bodyFile, []byte("from file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(tmp, "out")
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantFile string
+		wantErr  error
+	}{
+		{name: "get", args: []string{srv.URL + "/a"}, want: `GET /a "" ""`},
+		{name: "header", args: []string{"-H", "X-Test: yes", srv.URL}, want: `GET / "yes" ""`},
+		{name: "post body", args: []string{"-d", "a=1", srv.URL}, want: `POST / "" "a=1"`},
+		{name: "body from file", args: []string{"-X", "put", "-d", "@" + bodyFile, srv.URL}, want: `PUT / "" "from file"`},
+		{name: "output file", args: []string{"-o", out, srv.URL + "/saved"}, wantFile: `GET /saved "" ""`},
+		{name: "http error", args: []string{srv.URL + "/missing"}, want: "not here\n", wantErr: builtins.ErrHTTPStatus},
+		{name: "no url", args: []string{"-X", "GET"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "bad header", args: []string{"-H", "nocolon", srv.URL}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := builtins.Fetch(context.Background(), &w, io.Discard, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("Fetch() got = %q, want %q", got, tt.want)
+			}
+			if tt.wantFile != "" {
+				data, err := os.ReadFile(out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != tt.wantFile {
+					t.Errorf("Fetch() wrote %q, want %q", data, tt.wantFile)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package builtins
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSystem is the file system the builtins that work on files and
+// directories, such as ls, cat, rm, cp and find, go through, so that they
+// can be run on something other than the disk: an archive opened as an
+// fs.FS, or an in-memory file system in tests. Paths are given as the user
+// typed them, with the platform's separators, and relative ones are taken
+// from the working directory (or the root of a file system without one).
+type FileSystem interface {
+	// Open opens a file for reading, as fs.FS does.
+	Open(name string) (fs.File, error)
+	// Stat describes a file, following symbolic links.
+	Stat(name string) (fs.FileInfo, error)
+	// Lstat describes a file, or a symbolic link itself.
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadDir returns the entries of a directory sorted by name.
+	ReadDir(name string) ([]fs.DirEntry, error)
+	// Readlink returns the target of a symbolic link.
+	Readlink(name string) (string, error)
+
+	// Create creates or truncates a file for writing, giving a new file the
+	// permissions perm.
+	Create(name string, perm fs.FileMode) (io.WriteCloser, error)
+	// Mkdir creates a directory.
+	Mkdir(name string, perm fs.FileMode) error
+	// Chmod changes a file's permissions.
+	Chmod(name string, mode fs.FileMode) error
+	// Symlink creates newname as a symbolic link to oldname.
+	Symlink(oldname, newname string) error
+	// Remove removes a file or empty directory.
+	Remove(name string) error
+	// RemoveAll removes a file or directory and everything in it, and
+	// succeeds if there is nothing to remove.
+	RemoveAll(name string) error
+}
+
+// OSFileSystem is the FileSystem of the operating system, which the
+// builtins use unless the shell gives them another.
+var OSFileSystem FileSystem = osFileSystem{}
+
+type osFileSystem struct{}
+
+func (osFileSystem) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (osFileSystem) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (osFileSystem) Lstat(name string) (fs.FileInfo, error)     { return os.Lstat(name) }
+func (osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (osFileSystem) Readlink(name string) (string, error)       { return os.Readlink(name) }
+func (osFileSystem) Mkdir(name string, perm fs.FileMode) error  { return os.Mkdir(name, perm) }
+func (osFileSystem) Chmod(name string, mode fs.FileMode) error  { return os.Chmod(name, mode) }
+func (osFileSystem) Symlink(oldname, newname string) error      { return os.Symlink(oldname, newname) }
+func (osFileSystem) Remove(name string) error                   { return os.Remove(name) }
+func (osFileSystem) RemoveAll(name string) error                { return os.RemoveAll(name) }
+
+func (osFileSystem) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+// FromFS returns a read-only FileSystem showing fsys, such as a zip archive,
+// with its root as the working directory. Changing it fails with
+// fs.ErrPermission. fs.FS has no symbolic links, so Stat and Lstat are
+// the same.
+func FromFS(fsys fs.FS) FileSystem {
+	return readOnlyFS{fsys}
+}
+
+type readOnlyFS struct {
+	fsys fs.FS
+}
+
+func (r readOnlyFS) Open(name string) (fs.File, error) {
+	return r.fsys.Open(fsPath(name))
+}
+
+func (r readOnlyFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(r.fsys, fsPath(name))
+}
+
+func (r readOnlyFS) Lstat(name string) (fs.FileInfo, error) {
+	return fs.Stat(r.fsys, fsPath(name))
+}
+
+func (r readOnlyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(r.fsys, fsPath(name))
+}
+
+func (r readOnlyFS) Readlink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+func (r readOnlyFS) Create(name string, _ fs.FileMode) (io.WriteCloser, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func (r readOnlyFS) Mkdir(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrPermission}
+}
+
+func (r readOnlyFS) Chmod(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrPermission}
+}
+
+func (r readOnlyFS) Symlink(_, newname string) error {
+	return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrPermission}
+}
+
+func (r readOnlyFS) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+}
+
+func (r readOnlyFS) RemoveAll(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+}
+
+// fsPath turns a path as typed into one for an fs.FS, taking both absolute
+// and relative paths from its root.
+func fsPath(name string) string {
+	name = filepath.Clean(name)
+	name = strings.TrimPrefix(name, filepath.VolumeName(name))
+	name = strings.TrimLeft(filepath.ToSlash(name), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// WalkDir walks the tree at root in fsys as filepath.WalkDir walks the
+// disk, calling fn for each file and directory in lexical order.
+func WalkDir(fsys FileSystem, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if errors.Is(err, filepath.SkipDir) {
+		return nil
+	}
+
+	return err
+}
+
+// walkDir calls fn for path and, if it is a directory, the tree under it.
+func walkDir(fsys FileSystem, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, filepath.SkipDir) && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		// A second call reports the error reading the directory.
+		if err = fn(path, d, err); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				err = nil
+			}
+			return err
+		}
+	}
+	for _, entry := range entries {
+		if err := walkDir(fsys, filepath.Join(path, entry.Name()), entry, fn); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withFile calls fn with the named file of fsys open for reading, or with r
+// if the name is "-".
+func withFile(fsys FileSystem, r io.Reader, name string, fn func(io.Reader) error) error {
+	if name == "-" {
+		return fn(r)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return fn(f)
+}
@@ -0,0 +1,147 @@
+package builtins_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// memFiles returns the files the in-memory file system tests start with.
+func memFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"notes.txt":      {Data: []byte("one\ntwo\n"), Mode: 0o644},
+		"src/main.go":    {Data: []byte("package main\n"), Mode: 0o644},
+		"src/lib/lib.go": {Data: []byte("package lib\n"), Mode: 0o600},
+		"src/link":       {Data: []byte("main.go"), Mode: fs.ModeSymlink | 0o777},
+	}
+}
+
+func TestMemFileSystem_builtins(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		run       func(fsys builtins.FileSystem, out *bytes.Buffer) error
+		wantOut   string
+		wantFiles map[string]string
+		wantGone  []string
+		wantErr   error
+	}{
+		{
+			name: "ls",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.List(fsys, out, "src")
+			},
+			wantOut: "lib\nlink\nmain.go\n",
+		},
+		{
+			name: "cat follows links",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.Concatenate(fsys, strings.NewReader(""), out, "notes.txt", "src/link")
+			},
+			wantOut: "one\ntwo\npackage main\n",
+		},
+		{
+			name: "cat missing file",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.Concatenate(fsys, strings.NewReader(""), out, "nope.txt")
+			},
+			wantErr: fs.ErrNotExist,
+		},
+		{
+			name: "find",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.Find(context.Background(), fsys, out, nil, "src", "-name", "*.go")
+			},
+			wantOut: "src/lib/lib.go\nsrc/main.go\n",
+		},
+		{
+			name: "cp -r",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.Copy(fsys, "-r", "src", "dst")
+			},
+			wantFiles: map[string]string{
+				"dst/main.go":    "package main\n",
+				"dst/lib/lib.go": "package lib\n",
+				"dst/link":       "main.go",
+				"src/main.go":    "package main\n",
+			},
+		},
+		{
+			name: "rm -r",
+			run: func(fsys builtins.FileSystem, out *bytes.Buffer) error {
+				return builtins.Remove(fsys, strings.NewReader(""), out, "-r", "src")
+			},
+			wantFiles: map[string]string{"notes.txt": "one\ntwo\n"},
+			wantGone:  []string{"src", "src/main.go", "src/lib/lib.go", "src/link"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fsys := builtins.NewMemFileSystem(memFiles())
+			var out bytes.Buffer
+			err := tt.run(fsys, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("got = %q, want %q", out.String(), tt.wantOut)
+			}
+			files := fsys.Files()
+			for name, want := range tt.wantFiles {
+				f, ok := files[name]
+				if !ok {
+					t.Errorf("%v missing", name)
+				} else if string(f.Data) != want {
+					t.Errorf("%v = %q, want %q", name, f.Data, want)
+				}
+			}
+			for _, name := range tt.wantGone {
+				if _, ok := files[name]; ok {
+					t.Errorf("%v not removed", name)
+				}
+			}
+		})
+	}
+}
+
+func TestMemFileSystem_copyKeepsMode(t *testing.T) {
+	t.Parallel()
+	fsys := builtins.NewMemFileSystem(memFiles())
+	if err := builtins.Copy(fsys, "src/lib/lib.go", "notes.txt", "src"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	files := fsys.Files()
+	if got := files["src/lib.go"].Mode.Perm(); got != 0o600 {
+		t.Errorf("mode = %v, want %v", got, fs.FileMode(0o600))
+	}
+	if got := string(files["src/notes.txt"].Data); got != "one\ntwo\n" {
+		t.Errorf("src/notes.txt = %q", got)
+	}
+}
+
+func TestFromFS(t *testing.T) {
+	t.Parallel()
+	fsys := builtins.FromFS(fstest.MapFS{
+		"a.txt":     {Data: []byte("a\n")},
+		"dir/b.txt": {Data: []byte("b\n")},
+	})
+
+	var out bytes.Buffer
+	if err := builtins.Concatenate(fsys, strings.NewReader(""), &out, "/a.txt", "dir/b.txt"); err != nil {
+		t.Fatalf("Concatenate() error = %v", err)
+	}
+	if out.String() != "a\nb\n" {
+		t.Errorf("Concatenate() got = %q, want %q", out.String(), "a\nb\n")
+	}
+	if err := builtins.Remove(fsys, strings.NewReader(""), &out, "a.txt"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Remove() error = %v, want %v", err, fs.ErrPermission)
+	}
+}
@@ -0,0 +1,258 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// findPredicate reports whether a walked entry matches.
+type findPredicate func(path string, d fs.DirEntry, info fs.FileInfo) bool
+
+func init() {
+	Register("find", func(ctx *Context, args ...string) error {
+		return Find(ctx.Context(), ctx.FileSystem(), ctx.Stdout, ctx.Run, args...)
+	}, Meta{
+		Synopsis: "find [PATH...] [PREDICATE...]",
+		Summary:  "search for files",
+		Paged:    true,
+		Flags: []string{
+			"-exec CMD {} ;\trun CMD for each match",
+			"-maxdepth N\tdescend at most N levels",
+			"-mtime [+-]N\tmodified N days ago",
+			"-name GLOB\tbase name matches GLOB",
+			"-size [+-]N[ckMG]\tfile size",
+			"-type f|d\tfiles or directories",
+		},
+	})
+}
+
+// Find handles the "find" built-in command.
+// It walks each starting path (default ".") and prints the entries matching every
+// predicate: -name GLOB, -type f|d, -size [+-]N[ckMG], -mtime [+-]N and -maxdepth N.
+// With -exec CMD {} ; the command is run for each match instead of printing it.
+// Paths are the starting path as given followed by the rest, so find . prints
+// ./a.txt. Ctrl-C stops the walk.
+func Find(ctx context.Context, fsys FileSystem, w io.Writer, run CommandRunner, args ...string) error {
+	var (
+		roots      = make([]string, 0)
+		predicates = make([]findPredicate, 0)
+		maxDepth   = -1
+		execArgs   []string
+	)
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		roots = append(roots, args[0])
+		args = args[1:]
+	}
+	if len(roots) == 0 {
+		roots = append(roots, ".")
+	}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-exec" {
+			end := i + 1
+			for end < len(args) && args[end] != ";" && args[end] != `\;` {
+				end++
+			}
+			if end == len(args) || end == i+1 {
+				return fmt.Errorf("%w: -exec requires a command terminated by ;", ErrInvalidArgs)
+			}
+			execArgs = args[i+1 : end]
+			i = end
+			continue
+		}
+		if len(args) < i+2 {
+			return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, args[i])
+		}
+		flag, value := args[i], args[i+1]
+		i++
+		switch flag {
+		case "-name":
+			if _, err := filepath.Match(value, ""); err != nil {
+				return fmt.Errorf("%w: bad pattern %q", ErrInvalidArgs, value)
+			}
+			predicates = append(predicates, func(path string, _ fs.DirEntry, _ fs.FileInfo) bool {
+				ok, _ := filepath.Match(value, filepath.Base(path))
+				return ok
+			})
+		case "-type":
+			if value != "f" && value != "d" {
+				return fmt.Errorf("%w: -type must be f or d", ErrInvalidArgs)
+			}
+			wantDir := value == "d"
+			predicates = append(predicates, func(_ string, d fs.DirEntry, _ fs.FileInfo) bool {
+				if wantDir {
+					return d.IsDir()
+				}
+				return d.Type().IsRegular()
+			})
+		case "-size":
+			p, err := sizePredicate(value)
+			if err != nil {
+				return err
+			}
+			predicates = append(predicates, p)
+		case "-mtime":
+			p, err := mtimePredicate(value, time.Now())
+			if err != nil {
+				return err
+			}
+			predicates = append(predicates, p)
+		case "-maxdepth":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("%w: invalid depth %q", ErrInvalidArgs, value)
+			}
+			maxDepth = n
+		default:
+			return fmt.Errorf("%w: unknown predicate %v", ErrInvalidArgs, flag)
+		}
+	}
+
+	var firstErr error
+	for _, root := range roots {
+		rootDepth := depth(root)
+		err := WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			path = underRoot(root, path)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				// Keep walking past unreadable entries, but report the first failure.
+				if firstErr == nil {
+					firstErr = err
+				}
+				return nil
+			}
+			if maxDepth >= 0 && depth(path)-rootDepth > maxDepth {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			for _, matches := range predicates {
+				if !matches(path, d, info) {
+					return nil
+				}
+			}
+
+			if execArgs == nil {
+				_, err = fmt.Fprintln(w, path)
+				return err
+			}
+			argv := make([]string, len(execArgs))
+			for j := range execArgs {
+				argv[j] = strings.ReplaceAll(execArgs[j], "{}", path)
+			}
+			return run(argv[0], argv[1:]...)
+		})
+		if err != nil {
+			return Interrupted(ctx, err)
+		}
+	}
+
+	return firstErr
+}
+
+// underRoot returns path, which WalkDir found under root and cleaned, as
+// root followed by the rest of the path, keeping root as it was written.
+func underRoot(root, path string) string {
+	rel, err := filepath.Rel(filepath.Clean(root), path)
+	switch {
+	case err != nil:
+		return path
+	case rel == ".":
+		return root
+	case os.IsPathSeparator(root[len(root)-1]):
+		return root + rel
+	default:
+		return root + string(filepath.Separator) + rel
+	}
+}
+
+// depth counts the path elements of a cleaned path.
+func depth(path string) int {
+	path = filepath.Clean(path)
+	if path == "." || path == string(filepath.Separator) {
+		return 0
+	}
+
+	return strings.Count(strings.Trim(path, string(filepath.Separator)), string(filepath.Separator)) + 1
+}
+
+// compareArg splits a numeric find argument into its sign (+, - or none) and value.
+func compareArg(value string) (byte, string) {
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		return value[0], value[1:]
+	}
+
+	return 0, value
+}
+
+// compareCount applies a find-style +N / -N / N comparison.
+func compareCount(sign byte, got, want int64) bool {
+	switch sign {
+	case '+':
+		return got > want
+	case '-':
+		return got < want
+	default:
+		return got == want
+	}
+}
+
+// sizePredicate parses -size [+-]N[ckMG]; sizes without a unit are in 512-byte blocks.
+func sizePredicate(value string) (findPredicate, error) {
+	sign, value := compareArg(value)
+	unit := int64(512)
+	if value != "" {
+		switch value[len(value)-1] {
+		case 'c':
+			unit = 1
+		case 'k':
+			unit = 1 << 10
+		case 'M':
+			unit = 1 << 20
+		case 'G':
+			unit = 1 << 30
+		}
+		if unit != 512 {
+			value = value[:len(value)-1]
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%w: invalid size %q", ErrInvalidArgs, value)
+	}
+
+	return func(_ string, _ fs.DirEntry, info fs.FileInfo) bool {
+		// Sizes are rounded up to whole units, as find does.
+		units := (info.Size() + unit - 1) / unit
+		return compareCount(sign, units, n)
+	}, nil
+}
+
+// mtimePredicate parses -mtime [+-]N where N counts whole days since modification.
+func mtimePredicate(value string, now time.Time) (findPredicate, error) {
+	sign, value := compareArg(value)
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%w: invalid age %q", ErrInvalidArgs, value)
+	}
+
+	return func(_ string, _ fs.DirEntry, info fs.FileInfo) bool {
+		days := int64(now.Sub(info.ModTime()) / (24 * time.Hour))
+		return compareCount(sign, days, n)
+	}, nil
+}
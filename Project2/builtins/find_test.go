@@ -0,0 +1,170 @@
+package builtins_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	files := map[string]int{
+		"a.go":          10,
+		"b.txt":         2048,
+		"sub/c.go":      0,
+		"sub/deep/d.go": 700,
+	}
+	for name, size := range files {
+		p := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-72 * time.Hour)
+	if err := os.Chtimes(filepath.Join(tmp, "a.go"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	join := func(names ...string) string {
+		var sb strings.Builder
+		for _, name := range names {
+			sb.WriteString(filepath.Join(tmp, name) + "\n")
+		}
+		return sb.String()
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantRun [][]string
+		wantErr error
+	}{
+		{
+			name:    "name glob",
+			args:    []string{tmp, "-name", "*.go"},
+			wantOut: join("a.go", "sub/c.go", "sub/deep/d.go"),
+		},
+		{
+			name:    "directories with max depth",
+			args:    []string{tmp, "-type", "d", "-maxdepth", "1"},
+			wantOut: join("", "sub"),
+		},
+		{
+			name:    "size in kilobytes",
+			args:    []string{tmp, "-type", "f", "-size", "+1k"},
+			wantOut: join("b.txt"),
+		},
+		{
+			name:    "size in blocks",
+			args:    []string{tmp, "-name", "*.go", "-size", "-2"},
+			wantOut: join("a.go", "sub/c.go"),
+		},
+		{
+			name:    "modified days ago",
+			args:    []string{tmp, "-type", "f", "-mtime", "+1"},
+			wantOut: join("a.go"),
+		},
+		{
+			name:    "exec",
+			args:    []string{tmp, "-name", "c.go", "-exec", "wc", "-c", "{}", `\;`},
+			wantRun: [][]string{{"wc", "-c", filepath.Join(tmp, "sub/c.go")}},
+		},
+		{
+			name:    "root as given",
+			args:    []string{tmp + "/./sub/", "-name", "*.go"},
+			wantOut: tmp + "/./sub/c.go\n" + tmp + "/./sub/deep/d.go\n",
+		},
+		{
+			name:    "exec with root as given",
+			args:    []string{tmp + "/./sub", "-name", "c.go", "-exec", "rm", "--", "{}", ";"},
+			wantRun: [][]string{{"rm", "--", tmp + "/./sub/c.go"}},
+		},
+		{
+			name:    "unterminated exec",
+			args:    []string{tmp, "-exec", "rm", "{}"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "bad type",
+			args:    []string{tmp, "-type", "x"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "missing value",
+			args:    []string{tmp, "-name"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+		{
+			name:    "missing root",
+			args:    []string{filepath.Join(tmp, "nope")},
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				out bytes.Buffer
+				ran [][]string
+			)
+			run := func(name string, args ...string) error {
+				ran = append(ran, append([]string{name}, args...))
+				return nil
+			}
+			if err := builtins.Find(context.Background(), builtins.OSFileSystem, &out, run, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Find() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Find() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Find() got = %q, want %q", got, tt.wantOut)
+			}
+			if !reflect.DeepEqual(ran, tt.wantRun) {
+				t.Errorf("Find() ran %q, want %q", ran, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestFind_dot(t *testing.T) {
+	t.Parallel()
+	fsys := builtins.NewMemFileSystem(fstest.MapFS{"a.go": {}, "sub/b.go": {}, "-n.go": {}})
+	var out bytes.Buffer
+	if err := builtins.Find(context.Background(), fsys, &out, nil, ".", "-type", "f"); err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if got, want := out.String(), "./-n.go\n./a.go\n./sub/b.go\n"; got != want {
+		t.Errorf("Find() got = %q, want %q", got, want)
+	}
+}
+
+func TestFind_cancelled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	err := builtins.Find(ctx, builtins.OSFileSystem, &out, nil, t.TempDir())
+	if !errors.Is(err, builtins.ErrInterrupted) {
+		t.Errorf("Find() error = %v, wantErr %v", err, builtins.ErrInterrupted)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Find() got = %q after being cancelled", out.String())
+	}
+}
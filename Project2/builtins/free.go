@@ -0,0 +1,130 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+)
+
+// freeUnits are free's unit flags and the number of bytes each stands for.
+var freeUnits = map[string]int64{
+	"-b": 1,
+	"-k": 1 << 10,
+	"-m": 1 << 20,
+	"-g": 1 << 30,
+}
+
+func init() {
+	Register("free", func(ctx *Context, args ...string) error {
+		return Free(ctx.Context(), ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "free [-b | -k | -m | -g | -h] [-s SECS] [-c COUNT]",
+		Summary:  "show how much memory and swap is used and free",
+		Flags: []string{
+			"-b, -k, -m, -g\tshow bytes, kibibytes (the default), mebibytes or gibibytes",
+			"-h\tshow sizes in human-readable units such as 1.5G",
+			"-s SECS\trepeat every SECS seconds until Ctrl-C",
+			"-c COUNT\trepeat COUNT times, every second unless -s is given",
+		},
+	})
+}
+
+// Free handles the "free" built-in command.
+// It prints the total, used, free, shared, buffer and cache, and available
+// memory, and the total, used and free swap. Used memory is what isn't
+// available to start new programs without swapping. With -s or -c the report
+// is repeated, separated by blank lines.
+func Free(ctx context.Context, w io.Writer, args ...string) error {
+	var (
+		unit     int64 = 1 << 10
+		human    bool
+		interval time.Duration
+		count    int
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if scale, ok := freeUnits[arg]; ok {
+			unit, human = scale, false
+			continue
+		}
+		switch arg {
+		case "-h":
+			human = true
+		case "-s", "-c":
+			if i+1 == len(args) {
+				return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+			}
+			i++
+			if arg == "-s" {
+				secs, err := strconv.ParseFloat(args[i], 64)
+				if err != nil || secs <= 0 {
+					return fmt.Errorf("%w: invalid delay %q", ErrInvalidArgs, args[i])
+				}
+				interval = time.Duration(secs * float64(time.Second))
+			} else {
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n < 1 {
+					return fmt.Errorf("%w: invalid count %q", ErrInvalidArgs, args[i])
+				}
+				count = n
+			}
+		default:
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		}
+	}
+	format := func(n int64) string {
+		if human {
+			return humanSize(n)
+		}
+		return strconv.FormatInt(n/unit, 10)
+	}
+
+	if interval == 0 && count == 0 {
+		return printFree(w, format)
+	}
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	// Ctrl-C stops repeating instead of killing the shell.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for shown := 1; ; shown++ {
+		if err := printFree(w, format); err != nil {
+			return err
+		}
+		if shown == count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+}
+
+// printFree prints one report with sizes formatted by format.
+func printFree(w io.Writer, format func(int64) string) error {
+	m, err := memoryInfo()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%-7v %11v %11v %11v %11v %11v %11v\n"+
+		"%-7v %11v %11v %11v %11v %11v %11v\n"+
+		"%-7v %11v %11v %11v\n",
+		"", "total", "used", "free", "shared", "buff/cache", "available",
+		"Mem:", format(m.Total), format(m.Total-m.Available), format(m.Free), format(m.Shared), format(m.BuffCache), format(m.Available),
+		"Swap:", format(m.SwapTotal), format(m.SwapTotal-m.SwapFree), format(m.SwapFree))
+	return err
+}
@@ -0,0 +1,62 @@
+package builtins_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestFree(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := builtins.Free(context.Background(), &w, "-b"); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "Mem:") || !strings.HasPrefix(lines[2], "Swap:") {
+		t.Fatalf("Free() got = %q", w.String())
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "total used free shared buff/cache available" {
+		t.Errorf("Free() header = %q", lines[0])
+	}
+	mem := strings.Fields(lines[1])
+	total, _ := strconv.ParseInt(mem[1], 10, 64)
+	used, _ := strconv.ParseInt(mem[2], 10, 64)
+	avail, _ := strconv.ParseInt(mem[6], 10, 64)
+	if total <= 0 || used+avail != total {
+		t.Errorf("Free() memory line = %q, want used + available = total", lines[1])
+	}
+
+	w.Reset()
+	if err := builtins.Free(context.Background(), &w, "-h", "-c", "2", "-s", "0.01"); err != nil {
+		t.Fatalf("Free() -c error = %v", err)
+	}
+	if got := strings.Count(w.String(), "Mem:"); got != 2 {
+		t.Errorf("Free() -c 2 printed %d reports, want 2", got)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "unknown flag", args: []string{"-x"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "bad delay", args: []string{"-s", "0"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "bad count", args: []string{"-c", "none"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "missing count", args: []string{"-c"}, wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := builtins.Free(context.Background(), &bytes.Buffer{}, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Free() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
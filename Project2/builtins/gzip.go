@@ -0,0 +1,157 @@
+package builtins
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func init() {
+	Register("gzip", func(ctx *Context, args ...string) error {
+		return Gzip(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "gzip [-cdk] [FILE...]",
+		Summary:  "compress files",
+		Flags: []string{
+			"-c\twrite to standard output and keep the files",
+			"-d\tdecompress, as gunzip does",
+			"-k\tkeep the original files",
+		},
+	})
+	Register("gunzip", func(ctx *Context, args ...string) error {
+		return Gunzip(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "gunzip [-ck] [FILE...]",
+		Summary:  "decompress gzip files",
+		Flags: []string{
+			"-c\twrite to standard output and keep the files",
+			"-k\tkeep the original files",
+		},
+	})
+}
+
+// Gzip handles the "gzip" built-in command.
+// It replaces each file with a compressed FILE.gz, or compresses r to w when
+// no file (or "-") is given. With -d it decompresses instead.
+func Gzip(r io.Reader, w io.Writer, args ...string) error {
+	return gzipFiles(r, w, false, args)
+}
+
+// Gunzip handles the "gunzip" built-in command.
+// It replaces each FILE.gz with the decompressed FILE, or decompresses r to w
+// when no file (or "-") is given.
+func Gunzip(r io.Reader, w io.Writer, args ...string) error {
+	return gzipFiles(r, w, true, args)
+}
+
+// gzipFiles compresses or decompresses the files that args name.
+func gzipFiles(r io.Reader, w io.Writer, decompress bool, args []string) error {
+	cmd := "gzip"
+	if decompress {
+		cmd = "gunzip"
+	}
+	var toStdout, keep bool
+	files := make([]string, 0)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'c':
+				toStdout = true
+			case 'd':
+				decompress = true
+				cmd = "gunzip"
+			case 'k':
+				keep = true
+			default:
+				return fmt.Errorf("%w: %v: unknown flag -%c", ErrInvalidArgs, cmd, flag)
+			}
+		}
+	}
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+
+	for _, name := range files {
+		if name == "-" || toStdout {
+			if err := withInput(r, name, func(in io.Reader) error {
+				return gzipStream(in, w, decompress)
+			}); err != nil {
+				return fileError(cmd, name, err)
+			}
+			continue
+		}
+		if err := gzipFile(name, decompress, keep); err != nil {
+			return fileError(cmd, name, err)
+		}
+	}
+
+	return nil
+}
+
+// gzipStream compresses or decompresses r to w.
+func gzipStream(r io.Reader, w io.Writer, decompress bool) error {
+	if decompress {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		_, err = io.Copy(w, zr)
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := io.Copy(zw, r); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// gzipFile replaces name with its compressed or decompressed version, keeping
+// its permissions, and removes name unless keep is set.
+func gzipFile(name string, decompress, keep bool) error {
+	target := name + ".gz"
+	if decompress {
+		target = strings.TrimSuffix(name, ".gz")
+		if target == name {
+			return fmt.Errorf("%w: unknown suffix", ErrInvalidArgs)
+		}
+	}
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: not a regular file", ErrInvalidArgs)
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if err := gzipStream(in, out, decompress); err != nil {
+		_ = out.Close()
+		_ = os.Remove(target)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if keep {
+		return nil
+	}
+
+	return os.Remove(name)
+}
@@ -0,0 +1,77 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestGzip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := filepath.Join(dir, "notes.txt")
+	text := strings.Repeat("compress me\n", 100)
+	if err := os.WriteFile(name, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builtins.Gzip(nil, nil, name); err != nil {
+		t.Fatalf("Gzip() error = %v", err)
+	}
+	if _, err := os.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Gzip() left %v behind, err %v", name, err)
+	}
+	info, err := os.Stat(name + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 || info.Size() >= int64(len(text)) {
+		t.Errorf("Gzip() wrote mode %v, size %d", info.Mode().Perm(), info.Size())
+	}
+
+	var w bytes.Buffer
+	if err := builtins.Gunzip(nil, &w, "-c", name+".gz"); err != nil {
+		t.Fatalf("Gunzip() -c error = %v", err)
+	}
+	if got := w.String(); got != text {
+		t.Errorf("Gunzip() -c got = %q, want %q", got, text)
+	}
+
+	if err := builtins.Gzip(nil, nil, "-dk", name+".gz"); err != nil {
+		t.Fatalf("Gzip() -dk error = %v", err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != text {
+		t.Errorf("Gzip() -dk got = %q, want %q", data, text)
+	}
+	if _, err := os.Stat(name + ".gz"); err != nil {
+		t.Errorf("Gzip() -dk removed the archive: %v", err)
+	}
+}
+
+func TestGzipStream(t *testing.T) {
+	t.Parallel()
+	var zipped, w bytes.Buffer
+	if err := builtins.Gzip(strings.NewReader("hello\n"), &zipped); err != nil {
+		t.Fatalf("Gzip() error = %v", err)
+	}
+	if err := builtins.Gunzip(&zipped, &w, "-"); err != nil {
+		t.Fatalf("Gunzip() error = %v", err)
+	}
+	if got := w.String(); got != "hello\n" {
+		t.Errorf("Gunzip() got = %q, want %q", got, "hello\n")
+	}
+
+	if err := builtins.Gunzip(nil, nil, filepath.Join(t.TempDir(), "plain.txt")); !errors.Is(err, builtins.ErrInvalidArgs) {
+		t.Errorf("Gunzip() error = %v, wantErr %v", err, builtins.ErrInvalidArgs)
+	}
+}
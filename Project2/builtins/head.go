@@ -0,0 +1,126 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultLineCount is the number of lines head and tail print without -n.
+const DefaultLineCount = 10
+
+func init() {
+	Register("head", func(ctx *Context, args ...string) error {
+		return Head(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "head [-n N] [FILE...]",
+		Summary:  "print the first lines of files",
+		Flags:    []string{"-n N\tprint N lines (default 10)"},
+	})
+}
+
+// Head handles the "head" built-in command.
+// It prints the first N lines of each file, or of r when no file (or "-") is given.
+func Head(r io.Reader, w io.Writer, args ...string) error {
+	n := DefaultLineCount
+	files := make([]string, 0)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-n":
+			if len(args) < i+2 {
+				return fmt.Errorf("%w: -n requires an argument", ErrInvalidArgCount)
+			}
+			count, err := parseLineCount(args[i+1])
+			if err != nil {
+				return err
+			}
+			n = count
+			i++
+		case strings.HasPrefix(args[i], "-n"):
+			count, err := parseLineCount(strings.TrimPrefix(args[i], "-n"))
+			if err != nil {
+				return err
+			}
+			n = count
+		default:
+			files = append(files, args[i])
+		}
+	}
+
+	if len(files) == 0 {
+		return headLines(r, w, n)
+	}
+	for i, name := range files {
+		if len(files) > 1 {
+			if err := printFileHeader(w, name, i == 0); err != nil {
+				return err
+			}
+		}
+		if err := withInput(r, name, func(in io.Reader) error {
+			return headLines(in, w, n)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headLines copies the first n lines of r to w.
+func headLines(r io.Reader, w io.Writer, n int) error {
+	br := bufio.NewReader(r)
+	for i := 0; i < n; i++ {
+		line, err := br.ReadString('\n')
+		if _, wErr := io.WriteString(w, line); wErr != nil {
+			return wErr
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseLineCount parses the argument to -n.
+func parseLineCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid number of lines: %q", ErrInvalidArgs, s)
+	}
+
+	return n, nil
+}
+
+// printFileHeader writes the "==> name <==" banner used when several files are given.
+func printFileHeader(w io.Writer, name string, first bool) error {
+	if !first {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	if name == "-" {
+		name = "standard input"
+	}
+	_, err := fmt.Fprintf(w, "==> %v <==\n", name)
+	return err
+}
+
+// withInput opens the named file (or uses r for "-") and passes it to fn.
+func withInput(r io.Reader, name string, fn func(io.Reader) error) error {
+	if name == "-" {
+		return fn(r)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return fn(f)
+}
@@ -0,0 +1,86 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestHead(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "lines.txt")
+	if err := os.WriteFile(file, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "defaults to ten lines",
+			args:    []string{file},
+			wantOut: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+		},
+		{
+			name:    "reads stdin without files",
+			stdin:   "a\nb\nc\n",
+			args:    []string{"-n", "2"},
+			wantOut: "a\nb\n",
+		},
+		{
+			name:    "attached count",
+			stdin:   "a\nb\nc",
+			args:    []string{"-n5"},
+			wantOut: "a\nb\nc",
+		},
+		{
+			name:    "multiple files get headers",
+			stdin:   "x\ny\n",
+			args:    []string{"-n", "1", file, "-"},
+			wantOut: "==> " + file + " <==\n1\n\n==> standard input <==\nx\n",
+		},
+		{
+			name:    "missing count",
+			args:    []string{"-n"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+		{
+			name:    "bad count",
+			args:    []string{"-n", "ten"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "missing file",
+			args:    []string{filepath.Join(tmp, "nope")},
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Head(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Head() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Head() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Head() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
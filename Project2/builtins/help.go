@@ -0,0 +1,58 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+func init() {
+	Register("help", func(ctx *Context, args ...string) error {
+		return Help(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "help [NAME]",
+		Summary:  "list the builtins or describe one",
+		Paged:    true,
+	})
+	RegisterCompleter("help", func(_ []string, word string) []string {
+		return matchPrefix(Names(), word)
+	})
+}
+
+// Help handles the "help" built-in command.
+// It lists every builtin with its summary, or prints the usage and flags of the named ones.
+func Help(w io.Writer, args ...string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(args) == 0 {
+		for _, name := range Names() {
+			cmd, _ := Lookup(name)
+			if _, err := fmt.Fprintf(tw, "%v\t%v\n", name, cmd.Summary); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	}
+
+	for i, name := range args {
+		m, ok := Lookup(name)
+		if !ok {
+			return fmt.Errorf("%w: help: no builtin named %q", ErrNotFound, name)
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(tw); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(tw, "Usage: %v\n%v\n", m.Synopsis, m.Summary); err != nil {
+			return err
+		}
+		if len(m.Flags) > 0 {
+			if _, err := fmt.Fprintf(tw, "\nOptions:\n  %v\n", strings.Join(m.Flags, "\n  ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Flush()
+}
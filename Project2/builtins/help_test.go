@@ -0,0 +1,63 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestHelp(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "list",
+			want: "print or set the file-creation mask\n",
+		},
+		{
+			name: "usage with flags",
+			args: []string{"head"},
+			want: "Usage: head [-n N] [FILE...]\nprint the first lines of files\n\nOptions:\n  -n N  print N lines (default 10)\n",
+		},
+		{
+			name: "usage without flags",
+			args: []string{"whoami"},
+			want: "Usage: whoami\nprint the current user name\n",
+		},
+		{
+			name:    "unknown",
+			args:    []string{"nope"},
+			wantErr: builtins.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := builtins.Help(&w, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Help() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(w.String(), tt.want) {
+				t.Errorf("Help() got = %q, want %q", w.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+	for _, name := range builtins.Names() {
+		m, ok := builtins.Lookup(name)
+		if !ok || m.Name != name || m.Synopsis == "" || m.Summary == "" {
+			t.Errorf("Lookup(%q) got = %+v, %v; want a complete description", name, m, ok)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package builtins
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// hexdumpWidth is how many bytes hexdump and xxd show per line.
+const hexdumpWidth = 16
+
+func init() {
+	Register("hexdump", func(ctx *Context, args ...string) error {
+		return Hexdump(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "hexdump [-Cv] [-n LEN] [-s OFFSET] [FILE]",
+		Summary:  "show bytes in hex with offsets and an ASCII column",
+		Flags: []string{
+			"-C\tcanonical layout (the only one; accepted for compatibility)",
+			"-n LEN\tshow only LEN bytes",
+			"-s OFFSET\tskip OFFSET bytes first",
+			"-v\tshow repeated lines instead of a *",
+		},
+	})
+	Register("xxd", func(ctx *Context, args ...string) error {
+		return Xxd(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "xxd [-l LEN] [-s OFFSET] [FILE]",
+		Summary:  "show bytes in hex, xxd style",
+		Flags: []string{
+			"-l LEN\tshow only LEN bytes",
+			"-s OFFSET\tskip OFFSET bytes first",
+		},
+	})
+}
+
+// dumpOptions are the flags shared by hexdump and xxd.
+type dumpOptions struct {
+	skip, length int64 // length < 0 means all
+	all          bool  // show repeated lines
+	name         string
+}
+
+// Hexdump handles the "hexdump" built-in command.
+// It prints a file, or r when no file (or "-") is given, 16 bytes a line: the
+// offset, the bytes in hex and then as ASCII with dots for the rest, as in
+// hexdump -C. A run of identical lines is shown as a single * unless -v is
+// given.
+func Hexdump(r io.Reader, w io.Writer, args ...string) error {
+	opts, err := parseDumpArgs(args, "-n", "Cv")
+	if err != nil {
+		return err
+	}
+
+	return dump(r, w, opts, func(bw *bufio.Writer, off int64, line []byte) {
+		fmt.Fprintf(bw, "%08x ", off)
+		for i := 0; i < hexdumpWidth; i++ {
+			if i == hexdumpWidth/2 {
+				bw.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(bw, " %02x", line[i])
+			} else {
+				bw.WriteString("   ")
+			}
+		}
+		fmt.Fprintf(bw, "  |%v|\n", printable(line))
+	}, func(bw *bufio.Writer, end int64) {
+		fmt.Fprintf(bw, "%08x\n", end)
+	})
+}
+
+// Xxd handles the "xxd" built-in command.
+// It prints a file, or r when no file (or "-") is given, like hexdump but in
+// xxd's layout: the offset, the bytes in hex in pairs and then as ASCII.
+func Xxd(r io.Reader, w io.Writer, args ...string) error {
+	opts, err := parseDumpArgs(args, "-l", "")
+	if err != nil {
+		return err
+	}
+	opts.all = true
+
+	return dump(r, w, opts, func(bw *bufio.Writer, off int64, line []byte) {
+		fmt.Fprintf(bw, "%08x:", off)
+		for i := 0; i < hexdumpWidth; i++ {
+			if i%2 == 0 {
+				bw.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(bw, "%02x", line[i])
+			} else {
+				bw.WriteString("  ")
+			}
+		}
+		fmt.Fprintf(bw, "  %v\n", printable(line))
+	}, nil)
+}
+
+// parseDumpArgs parses -s OFFSET, the length flag lengthFlag with its
+// argument, the single-letter switches in switches and at most one file.
+func parseDumpArgs(args []string, lengthFlag, switches string) (dumpOptions, error) {
+	opts := dumpOptions{length: -1, name: "-"}
+	var files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-s" || arg == lengthFlag:
+			if i+1 == len(args) {
+				return opts, fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+			}
+			i++
+			n, err := strconv.ParseInt(args[i], 0, 64)
+			if err != nil || n < 0 {
+				return opts, fmt.Errorf("%w: invalid number %q", ErrInvalidArgs, args[i])
+			}
+			if arg == "-s" {
+				opts.skip = n
+			} else {
+				opts.length = n
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			for _, c := range arg[1:] {
+				if !strings.ContainsRune(switches, c) {
+					return opts, fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, c)
+				}
+				if c == 'v' {
+					opts.all = true
+				}
+			}
+		default:
+			files = append(files, arg)
+		}
+	}
+	if len(files) > 1 {
+		return opts, fmt.Errorf("%w: expected at most one file", ErrInvalidArgCount)
+	}
+	if len(files) == 1 {
+		opts.name = files[0]
+	}
+
+	return opts, nil
+}
+
+// dump reads the input chosen by opts and calls line for each 16 bytes of it,
+// then end, if not nil, with the offset after the last byte.
+func dump(r io.Reader, w io.Writer, opts dumpOptions, line func(*bufio.Writer, int64, []byte), end func(*bufio.Writer, int64)) error {
+	return withInput(r, opts.name, func(in io.Reader) error {
+		if _, err := io.CopyN(io.Discard, in, opts.skip); err != nil && err != io.EOF {
+			return err
+		}
+		if opts.length >= 0 {
+			in = io.LimitReader(in, opts.length)
+		}
+
+		bw := bufio.NewWriter(w)
+		buf := make([]byte, hexdumpWidth)
+		var prev []byte
+		off, starred := opts.skip, false
+		for {
+			n, err := io.ReadFull(in, buf)
+			if n > 0 {
+				if !opts.all && n == hexdumpWidth && bytes.Equal(buf, prev) {
+					if !starred {
+						_, _ = bw.WriteString("*\n")
+						starred = true
+					}
+				} else {
+					line(bw, off, buf[:n])
+					prev, starred = append(prev[:0], buf[:n]...), false
+				}
+				off += int64(n)
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			} else if err != nil {
+				return err
+			}
+		}
+		if end != nil && off > opts.skip {
+			end(bw, off)
+		}
+
+		return bw.Flush()
+	})
+}
+
+// printable returns b with bytes outside printable ASCII shown as dots.
+func printable(b []byte) string {
+	out := make([]byte, len(b))
+	for i, c := range b {
+		if c < ' ' || c > '~' {
+			c = '.'
+		}
+		out[i] = c
+	}
+
+	return string(out)
+}
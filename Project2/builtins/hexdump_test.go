@@ -0,0 +1,84 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestHexdump(t *testing.T) {
+	t.Parallel()
+	const text = "hello world, this is a test of hex\n"
+	tests := []struct {
+		name    string
+		fn      func(r io.Reader, w io.Writer, args ...string) error
+		stdin   string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "canonical",
+			fn:    builtins.Hexdump,
+			stdin: text,
+			want: "00000000  68 65 6c 6c 6f 20 77 6f  72 6c 64 2c 20 74 68 69  |hello world, thi|\n" +
+				"00000010  73 20 69 73 20 61 20 74  65 73 74 20 6f 66 20 68  |s is a test of h|\n" +
+				"00000020  65 78 0a                                          |ex.|\n" +
+				"00000023\n",
+		},
+		{
+			name:  "repeats starred",
+			fn:    builtins.Hexdump,
+			stdin: strings.Repeat("\x00", 48) + "a",
+			want: "00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+				"*\n" +
+				"00000030  61                                                |a|\n" +
+				"00000031\n",
+		},
+		{
+			name:  "repeats shown with -v",
+			fn:    builtins.Hexdump,
+			stdin: strings.Repeat("\x00", 32),
+			args:  []string{"-Cv"},
+			want: "00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+				"00000010  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+				"00000020\n",
+		},
+		{
+			name:  "skip and length",
+			fn:    builtins.Hexdump,
+			stdin: text,
+			args:  []string{"-s", "6", "-n", "5"},
+			want:  "00000006  77 6f 72 6c 64                                    |world|\n0000000b\n",
+		},
+		{
+			name:  "xxd",
+			fn:    builtins.Xxd,
+			stdin: text,
+			want: "00000000: 6865 6c6c 6f20 776f 726c 642c 2074 6869  hello world, thi\n" +
+				"00000010: 7320 6973 2061 2074 6573 7420 6f66 2068  s is a test of h\n" +
+				"00000020: 6578 0a                                  ex.\n",
+		},
+		{name: "xxd length", fn: builtins.Xxd, stdin: text, args: []string{"-l", "0x2"}, want: "00000000: 6865                                     he\n"},
+		{name: "empty", fn: builtins.Hexdump, want: ""},
+		{name: "unknown flag", fn: builtins.Xxd, args: []string{"-v"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "missing length", fn: builtins.Hexdump, args: []string{"-n"}, wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := tt.fn(strings.NewReader(tt.stdin), &out, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("dump error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("dump got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func init() {
+	Register("hostname", func(ctx *Context, args ...string) error {
+		return Hostname(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "hostname [-s]",
+		Summary:  "print the host name",
+		Flags:    []string{"-s\tshort name, up to the first dot"},
+	})
+}
+
+// Hostname handles the "hostname" built-in command.
+// With -s only the part before the first dot is printed.
+func Hostname(w io.Writer, args ...string) error {
+	short := false
+	switch {
+	case len(args) == 1 && args[0] == "-s":
+		short = true
+	case len(args) != 0:
+		return fmt.Errorf("%w: expected [-s]", ErrInvalidArgs)
+	}
+
+	name, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	if short {
+		name, _, _ = strings.Cut(name, ".")
+	}
+
+	_, err = fmt.Fprintln(w, name)
+	return err
+}
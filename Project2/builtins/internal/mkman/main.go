@@ -0,0 +1,169 @@
+// Command mkman writes the manual pages of the builtins, in Markdown, from
+// the doc comments of their handlers: the functions documented as handling
+// a built-in command, such as
+//
+//	// Head handles the "head" built-in command.
+//	// It prints the first lines of ...
+//
+// The first sentence is left out, and the names of the handler's reader and
+// writer parameters become standard input and output. Run it through go
+// generate in the builtins package:
+//
+//	mkman -o DIR PACKAGE_DIR...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// handles matches the first sentence of a handler's doc comment, with the
+// names of the commands it handles.
+var handles = regexp.MustCompile(`^\w+ handles the ((?:"[^"]+"(?:, | and )?)+) built-in commands?\.\s*`)
+
+// plainName matches the builtin names used as file names as they are.
+var plainName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// streams names what a parameter of each type stands for in a manual page.
+var streams = map[string]string{
+	"io.Reader": "standard input",
+	"io.Writer": "standard output",
+}
+
+func main() {
+	out := flag.String("o", "man", "write the pages to `DIR`")
+	flag.Parse()
+
+	pages := map[string]string{}
+	for _, dir := range flag.Args() {
+		if err := readPages(dir, pages); err != nil {
+			fmt.Fprintln(os.Stderr, "mkman:", err)
+			os.Exit(1)
+		}
+	}
+	if err := writePages(*out, pages); err != nil {
+		fmt.Fprintln(os.Stderr, "mkman:", err)
+		os.Exit(1)
+	}
+}
+
+// readPages adds the manual page of each builtin handled in the package in
+// dir to pages, by name.
+func readPages(dir string, pages map[string]string) error {
+	fset := token.NewFileSet()
+	notTest := func(fi fs.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				text := fn.Doc.Text()
+				m := handles.FindStringSubmatch(text)
+				if m == nil {
+					continue
+				}
+				page := markdown(describeParams(text[len(m[0]):], fn.Type.Params))
+				for _, name := range regexp.MustCompile(`"([^"]+)"`).FindAllStringSubmatch(m[1], -1) {
+					pages[name[1]] = page
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// describeParams replaces the names of the stream parameters in text with
+// what they stand for.
+func describeParams(text string, params *ast.FieldList) string {
+	for _, field := range params.List {
+		sel, ok := field.Type.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		stream, ok := streams[pkg.Name+"."+sel.Sel.Name]
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			re := regexp.MustCompile(`(^|[\s(])` + name.Name + `\b`)
+			text = re.ReplaceAllString(text, "${1}"+stream)
+		}
+	}
+
+	return text
+}
+
+// markdown converts doc comment text to Markdown.
+func markdown(text string) string {
+	var p comment.Parser
+	var pr comment.Printer
+
+	return string(pr.Markdown(p.Parse(text)))
+}
+
+// pageFile returns the file name of the page of the builtin name: NAME.md,
+// or for names such as ":" that aren't letters and digits, "_" followed by
+// the name in hex. The builtins package looks pages up the same way.
+func pageFile(name string) string {
+	if plainName.MatchString(name) {
+		return name + ".md"
+	}
+
+	return fmt.Sprintf("_%x.md", name)
+}
+
+// writePages writes each page to dir, removing the pages of builtins that
+// are gone.
+func writePages(dir string, pages map[string]string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	old, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+	files := map[string]bool{}
+	for name := range pages {
+		files[pageFile(name)] = true
+	}
+	for _, path := range old {
+		if !files[filepath.Base(path)] {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, pageFile(name)), []byte(pages[name]), 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func init() {
+	Register("kill", func(ctx *Context, args ...string) error {
+		return Kill(ctx.Stdout, ctx.Jobs, args...)
+	}, Meta{
+		Synopsis: "kill [-SIGNAL | -s SIGNAL] PID|%JOB... | kill -l",
+		Summary:  "signal processes, or the processes of background jobs",
+		Flags: []string{
+			"-SIGNAL\tsignal to send, by name or number (default TERM)",
+			"-s SIGNAL\tthe same, as a separate argument",
+			"-l\tlist the signal names",
+		},
+	})
+}
+
+// Kill handles the "kill" built-in command.
+// It sends a signal, TERM unless another is given, to each process ID, and
+// to each process of the jobs named by job specs such as %1 or %+ in jobs.
+// A job made only of builtins has no processes to signal. -l lists the
+// signals instead.
+func Kill(w io.Writer, jobs JobTable, args ...string) error {
+	if len(args) == 1 && args[0] == "-l" {
+		for _, sig := range sortedSignals() {
+			if _, err := fmt.Fprintf(w, "%2d) SIG%v\n", int(sig), signalName(sig)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	sig := syscall.SIGTERM
+	if len(args) > 0 && args[0] == "-s" {
+		if len(args) < 2 {
+			return fmt.Errorf("%w: kill: -s needs a signal", ErrInvalidArgCount)
+		}
+		s, err := parseSignal(args[1])
+		if err != nil {
+			return err
+		}
+		sig, args = s, args[2:]
+	} else if len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' && args[0] != "--" {
+		s, err := parseSignal(args[0][1:])
+		if err != nil {
+			return err
+		}
+		sig, args = s, args[1:]
+	}
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: kill: expected process IDs or job specs", ErrInvalidArgCount)
+	}
+
+	var pids []int
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "%") {
+			if jobs == nil {
+				return fmt.Errorf("%w: kill: %v: no job control", ErrInvalidArgs, arg)
+			}
+			j, err := jobs.Lookup(arg)
+			if err != nil {
+				return err
+			}
+			pids = append(pids, j.PIDs...)
+			continue
+		}
+		pid, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("%w: kill: %q is not a process ID or job spec", ErrInvalidArgs, arg)
+		}
+		pids = append(pids, pid)
+	}
+	for _, pid := range pids {
+		proc, err := os.FindProcess(pid)
+		if err == nil {
+			err = proc.Signal(sig)
+		}
+		if err != nil {
+			return fmt.Errorf("kill: %d: %w", pid, err)
+		}
+	}
+
+	return nil
+}
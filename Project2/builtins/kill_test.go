@@ -0,0 +1,94 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// jobTable is a job table of fixed jobs, looked up by %N.
+type jobTable []builtins.Job
+
+func (t jobTable) Jobs() []builtins.Job { return t }
+
+func (t jobTable) Lookup(spec string) (builtins.Job, error) {
+	for _, j := range t {
+		if spec == fmt.Sprintf("%%%d", j.ID) {
+			return j, nil
+		}
+	}
+
+	return builtins.Job{}, fmt.Errorf("%w: no such job: %v", builtins.ErrInvalidArgs, spec)
+}
+
+func TestKill(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sleep and signals")
+	}
+	start := func() *exec.Cmd {
+		cmd := exec.Command("sleep", "10")
+		if err := cmd.Start(); err != nil {
+			t.Skipf("needs sleep: %v", err)
+		}
+		t.Cleanup(func() { _ = cmd.Process.Kill() })
+		return cmd
+	}
+	signaled := func(cmd *exec.Cmd) syscall.Signal {
+		var exitErr *exec.ExitError
+		if err := cmd.Wait(); !errors.As(err, &exitErr) {
+			t.Fatalf("Wait() error = %v, want a signal", err)
+		}
+		return exitErr.Sys().(syscall.WaitStatus).Signal()
+	}
+
+	byPID, byJob := start(), start()
+	jobs := jobTable{{ID: 1, Command: "sleep 10", PIDs: []int{byJob.Process.Pid}}}
+	var w bytes.Buffer
+	if err := builtins.Kill(&w, jobs, "-KILL", fmt.Sprint(byPID.Process.Pid)); err != nil {
+		t.Fatalf("Kill() pid error = %v", err)
+	}
+	if got := signaled(byPID); got != syscall.SIGKILL {
+		t.Errorf("Kill() -KILL sent %v", got)
+	}
+	if err := builtins.Kill(&w, jobs, "%1"); err != nil {
+		t.Fatalf("Kill() job error = %v", err)
+	}
+	if got := signaled(byJob); got != syscall.SIGTERM {
+		t.Errorf("Kill() %%1 sent %v, want TERM", got)
+	}
+	if w.Len() != 0 {
+		t.Errorf("Kill() wrote %q", w.String())
+	}
+
+	if err := builtins.Kill(&w, jobs, "-l"); err != nil {
+		t.Fatalf("Kill() -l error = %v", err)
+	}
+	if !strings.Contains(w.String(), " 9) SIGKILL\n") {
+		t.Errorf("Kill() -l got %q", w.String())
+	}
+
+	for _, tt := range []struct {
+		name    string
+		jobs    builtins.JobTable
+		args    []string
+		wantErr error
+	}{
+		{name: "no arguments", jobs: jobs, args: nil, wantErr: builtins.ErrInvalidArgCount},
+		{name: "no job table", jobs: nil, args: []string{"%1"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "no such job", jobs: jobs, args: []string{"%2"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "not a PID", jobs: jobs, args: []string{"x"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "unknown signal", jobs: jobs, args: []string{"-s", "NOPE", "1"}, wantErr: builtins.ErrInvalidArgs},
+	} {
+		if err := builtins.Kill(&w, tt.jobs, tt.args...); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Kill() %v error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package builtins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	Register("ln", func(ctx *Context, args ...string) error {
+		return Link(args...)
+	}, Meta{
+		Synopsis: "ln [-s] TARGET LINK",
+		Summary:  "create a link",
+		Flags:    []string{"-s\tcreate a symbolic link"},
+	})
+}
+
+// Link handles the "ln" built-in command.
+// It creates a hard link to TARGET (a symbolic link with -s) named LINK,
+// or named after TARGET in the current or given directory.
+func Link(args ...string) error {
+	symbolic := false
+	if len(args) > 0 && args[0] == "-s" {
+		symbolic = true
+		args = args[1:]
+	}
+
+	var target, link string
+	switch len(args) {
+	case 1:
+		target, link = args[0], filepath.Base(args[0])
+	case 2:
+		target, link = args[0], args[1]
+		if info, err := os.Stat(link); err == nil && info.IsDir() {
+			link = filepath.Join(link, filepath.Base(target))
+		}
+	default:
+		return fmt.Errorf("%w: expected a target and optional link name", ErrInvalidArgCount)
+	}
+
+	var err error
+	if symbolic {
+		err = os.Symlink(target, link)
+	} else {
+		err = os.Link(target, link)
+	}
+	if err != nil {
+		return fileError("ln", link, err)
+	}
+
+	return nil
+}
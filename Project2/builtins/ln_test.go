@@ -0,0 +1,110 @@
+package builtins_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestLink(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs symbolic links")
+	}
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "target.txt")
+	if err := os.WriteFile(target, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(tmp, "dir")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		wantLink    string
+		wantSymlink string // the target a symbolic link points to
+		wantErr     error
+	}{
+		{
+			name:        "symbolic link",
+			args:        []string{"-s", target, filepath.Join(tmp, "sym")},
+			wantLink:    filepath.Join(tmp, "sym"),
+			wantSymlink: target,
+		},
+		{
+			name:        "dangling symbolic link",
+			args:        []string{"-s", "nowhere", filepath.Join(tmp, "dangling")},
+			wantLink:    filepath.Join(tmp, "dangling"),
+			wantSymlink: "nowhere",
+		},
+		{
+			name:     "hard link",
+			args:     []string{target, filepath.Join(tmp, "hard")},
+			wantLink: filepath.Join(tmp, "hard"),
+		},
+		{
+			name:     "into a directory",
+			args:     []string{target, dir},
+			wantLink: filepath.Join(dir, "target.txt"),
+		},
+		{
+			name:    "existing link name",
+			args:    []string{"-s", target, target},
+			wantErr: os.ErrExist,
+		},
+		{
+			name:    "missing target of a hard link",
+			args:    []string{filepath.Join(tmp, "nope"), filepath.Join(tmp, "nope-link")},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:    "no args",
+			args:    []string{"-s"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+		{
+			name:    "too many args",
+			args:    []string{target, "a", "b"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := builtins.Link(tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Link() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Link() unexpected error: %v", err)
+			}
+
+			if tt.wantSymlink != "" {
+				got, err := os.Readlink(tt.wantLink)
+				if err != nil || got != tt.wantSymlink {
+					t.Errorf("Link() made %v point to %q (%v), want %q", tt.wantLink, got, err, tt.wantSymlink)
+				}
+				return
+			}
+			linkInfo, err := os.Lstat(tt.wantLink)
+			if err != nil {
+				t.Fatalf("Link() did not create %v: %v", tt.wantLink, err)
+			}
+			targetInfo, err := os.Stat(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !os.SameFile(linkInfo, targetInfo) {
+				t.Errorf("Link() %v is not a hard link to %v", tt.wantLink, target)
+			}
+		})
+	}
+}
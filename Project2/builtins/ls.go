@@ -0,0 +1,250 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// lsRecent is how old a file can be for ls -l to show its time of day
+// rather than its year.
+const lsRecent = 180 * 24 * time.Hour
+
+func init() {
+	Register("ls", func(ctx *Context, args ...string) error {
+		return List(ctx.FileSystem(), ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "ls [-1Cal] [PATH...]",
+		Summary:  "list directory contents",
+		Paged:    true,
+		Flags: []string{
+			"-1\tlist one name per line",
+			"-C\tlist names in columns",
+			"-a\tinclude names starting with .",
+			"-l\tshow the mode, size and modification time",
+		},
+	})
+}
+
+// List handles the "ls" built-in command.
+// It prints the names in each directory, sorted, or the file itself for a
+// path that isn't a directory; with no paths it lists the working directory.
+// Several directories are each listed under a "dir:" heading. Names are laid
+// out in columns as wide as the terminal when w is one, or with -C, and one
+// per line otherwise, or with -1. The listing is the same on every platform.
+func List(fsys FileSystem, w io.Writer, args ...string) error {
+	var (
+		all, long bool
+		columns   = isTerminalWriter(w)
+		paths     = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			paths = append(paths, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'a':
+				all = true
+			case 'l':
+				long = true
+			case 'C':
+				columns = true
+			case '1':
+				columns = false
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		paths = append(paths, ".")
+	}
+
+	// Files are listed first, then directories.
+	var files []lsEntry
+	var dirs []string
+	for _, path := range paths {
+		info, err := fsys.Lstat(path)
+		if err != nil {
+			return fileError("ls", path, err)
+		}
+		if info.IsDir() {
+			dirs = append(dirs, path)
+		} else {
+			files = append(files, lsEntry{name: path, path: path, info: info})
+		}
+	}
+	width := 0
+	if columns && !long {
+		width, _ = TerminalSize(w)
+	}
+	if err := printEntries(fsys, w, files, long, width); err != nil {
+		return err
+	}
+	for i, dir := range dirs {
+		entries, err := readEntries(fsys, dir, all)
+		if err != nil {
+			return fileError("ls", dir, err)
+		}
+		if len(paths) > 1 {
+			if i > 0 || len(files) > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%v:\n", dir); err != nil {
+				return err
+			}
+		}
+		if err := printEntries(fsys, w, entries, long, width); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lsEntry is a file to list: the name to show, its path and its metadata.
+type lsEntry struct {
+	name, path string
+	info       fs.FileInfo
+}
+
+// readEntries returns the entries of dir sorted by name, leaving out those
+// starting with a dot unless all is set.
+func readEntries(fsys FileSystem, dir string, all bool) ([]lsEntry, error) {
+	des, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]lsEntry, 0, len(des))
+	for _, de := range des {
+		if !all && strings.HasPrefix(de.Name(), ".") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, lsEntry{name: de.Name(), path: filepath.Join(dir, de.Name()), info: info})
+	}
+
+	return entries, nil
+}
+
+// printEntries writes one entry per line, in the long format with long, or
+// the names in columns fitting width if it isn't 0.
+func printEntries(fsys FileSystem, w io.Writer, entries []lsEntry, long bool, width int) error {
+	if width > 0 {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.name
+		}
+		_, err := io.WriteString(w, lsColumns(names, width))
+		return err
+	}
+	sizeWidth := 0
+	for _, e := range entries {
+		if n := len(strconv.FormatInt(e.info.Size(), 10)); n > sizeWidth {
+			sizeWidth = n
+		}
+	}
+	now := time.Now()
+	for _, e := range entries {
+		line := e.name
+		if long {
+			line = fmt.Sprintf("%v %*d %v %v", e.info.Mode(), sizeWidth, e.info.Size(), lsTime(e.info.ModTime(), now), e.name)
+			if e.info.Mode()&fs.ModeSymlink != 0 {
+				if target, err := fsys.Readlink(e.path); err == nil {
+					line += " -> " + target
+				}
+			}
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lsColumnGap is the space between the columns of a listing.
+const lsColumnGap = 2
+
+// lsColumns lays out names down and then across in as many columns as fit
+// in width terminal columns, measuring each name by its display width so
+// that wide characters line up.
+func lsColumns(names []string, width int) string {
+	if len(names) == 0 {
+		return ""
+	}
+	widths := make([]int, len(names))
+	for i, name := range names {
+		widths[i] = runewidth.StringWidth(name)
+	}
+
+	// Try the fewest rows first, down to one name per row, which is used
+	// even if a name is too wide.
+	rows := len(names)
+	var colWidths []int
+	for r := 1; r <= len(names); r++ {
+		cols := (len(names) + r - 1) / r
+		colWidths = colWidths[:0]
+		total := -lsColumnGap
+		for c := 0; c < cols; c++ {
+			widest := 0
+			for i := c * r; i < (c+1)*r && i < len(names); i++ {
+				if widths[i] > widest {
+					widest = widths[i]
+				}
+			}
+			colWidths = append(colWidths, widest)
+			total += widest + lsColumnGap
+		}
+		if total <= width {
+			rows = r
+			break
+		}
+	}
+	var sb strings.Builder
+	for r := 0; r < rows; r++ {
+		for c := 0; c*rows+r < len(names); c++ {
+			i := c*rows + r
+			if c > 0 {
+				sb.WriteString(strings.Repeat(" ", lsColumnGap))
+			}
+			sb.WriteString(names[i])
+			if (c+1)*rows+r < len(names) {
+				sb.WriteString(strings.Repeat(" ", colWidths[c]-widths[i]))
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// isTerminalWriter reports whether w writes to a terminal.
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// lsTime formats a modification time as ls -l does: with the time of day if
+// it is recent, and with the year otherwise.
+func lsTime(t, now time.Time) string {
+	if t.After(now.Add(-lsRecent)) && !t.After(now) {
+		return t.Format("Jan _2 15:04")
+	}
+
+	return t.Format("Jan _2  2006")
+}
@@ -0,0 +1,106 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	for name, data := range map[string]string{"b.txt": "bb", ".hidden": "", "sub/a.txt": "a"} {
+		path := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(tmp, "b.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(tmp, "b.txt")
+	sub := filepath.Join(tmp, "sub")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{name: "directory", args: []string{tmp}, wantOut: "b.txt\nsub\n"},
+		{name: "all", args: []string{"-a", tmp}, wantOut: ".hidden\nb.txt\nsub\n"},
+		{name: "file", args: []string{file}, wantOut: file + "\n"},
+		{name: "several", args: []string{sub, file, tmp}, wantOut: file + "\n\n" + sub + ":\na.txt\n\n" + tmp + ":\nb.txt\nsub\n"},
+		{name: "long, with the year of an old file", args: []string{"-l", file}, wantOut: "-rw-r--r-- 2 Feb  3  2001 " + file + "\n"},
+		{name: "missing", args: []string{filepath.Join(tmp, "missing")}, wantErr: os.ErrNotExist},
+		{name: "unknown flag", args: []string{"-z"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := builtins.List(builtins.OSFileSystem, &out, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && out.String() != tt.wantOut {
+				t.Fatalf("List() = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+
+	t.Run("long, with the time of a recent file", func(t *testing.T) {
+		t.Parallel()
+		var out bytes.Buffer
+		if err := builtins.List(builtins.OSFileSystem, &out, "-l", sub); err != nil {
+			t.Fatal(err)
+		}
+		if !regexp.MustCompile(`^-rw-r--r-- 1 \w{3} [ \d]\d \d\d:\d\d a\.txt\n$`).MatchString(out.String()) {
+			t.Fatalf("List() = %q", out.String())
+		}
+	})
+}
+
+func TestList_columns(t *testing.T) {
+	// Not parallel: sets $COLUMNS.
+	t.Setenv("COLUMNS", "12")
+	tmp := t.TempDir()
+	for _, name := range []string{"a", "bb", "dddd", "e", "世界"} {
+		if err := os.WriteFile(filepath.Join(tmp, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+	}{
+		{name: "columns by display width", args: []string{"-C", tmp}, wantOut: "a     e\nbb    世界\ndddd\n"},
+		{name: "one per line", args: []string{"-C1", tmp}, wantOut: "a\nbb\ndddd\ne\n世界\n"},
+		{name: "wider than the terminal", args: []string{"-C", filepath.Join(tmp, "dddd"), filepath.Join(tmp, "世界")}, wantOut: filepath.Join(tmp, "dddd") + "\n" + filepath.Join(tmp, "世界") + "\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := builtins.List(builtins.OSFileSystem, &out, tt.args...); err != nil {
+			t.Fatalf("%v: List() error = %v", tt.name, err)
+		}
+		if out.String() != tt.wantOut {
+			t.Errorf("%v: List() = %q, want %q", tt.name, out.String(), tt.wantOut)
+		}
+	}
+}
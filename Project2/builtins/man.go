@@ -0,0 +1,166 @@
+package builtins
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// The manual pages are generated from the doc comments of the builtins'
+// handlers, in this package and the shell's.
+//
+//go:generate go run ./internal/mkman -o man . ../shell
+
+//go:embed man/*.md
+var manuals embed.FS
+
+// manIndent is how far the text of a manual page's sections is indented.
+const manIndent = "    "
+
+// markdownEscape matches a punctuation character escaped with a backslash.
+var markdownEscape = regexp.MustCompile(`\\([[:punct:]])`)
+
+func init() {
+	Register("man", func(ctx *Context, args ...string) error {
+		return Man(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "man NAME...",
+		Summary:  "show the manual page of builtins",
+	})
+	RegisterCompleter("man", func(_ []string, word string) []string {
+		return matchPrefix(Names(), word)
+	})
+}
+
+// manual returns the generated manual page of the builtin name, if any.
+// Names that aren't letters and digits, such as ":", are in files named
+// "_" and the name in hex.
+func manual(name string) string {
+	file := name
+	for _, r := range name {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			file = fmt.Sprintf("_%x", name)
+			break
+		}
+	}
+	page, err := manuals.ReadFile("man/" + file + ".md")
+	if err != nil {
+		return ""
+	}
+
+	return string(page)
+}
+
+// Man handles the "man" built-in command.
+// It shows the manual page of each named builtin through the pager: its
+// synopsis, what it does at length and its options, with the text wrapped
+// to the width of the terminal.
+func Man(w io.Writer, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: man: expected the name of a builtin", ErrInvalidArgCount)
+	}
+	cols, _ := TerminalSize(w)
+	var page strings.Builder
+	for i, name := range args {
+		cmd, ok := Lookup(name)
+		if !ok {
+			return fmt.Errorf("%w: man: no builtin named %q", ErrNotFound, name)
+		}
+		if i > 0 {
+			page.WriteString("\n")
+		}
+		if err := writeManual(&page, cmd.Meta, cols); err != nil {
+			return err
+		}
+	}
+
+	return Page(w, "man "+strings.Join(args, " "), page.String())
+}
+
+// writeManual writes the manual page of m, wrapped to width columns.
+func writeManual(w io.Writer, m Meta, width int) error {
+	text := fmt.Sprintf("NAME\n%v%v - %v\n\nSYNOPSIS\n%v%v\n", manIndent, m.Name, m.Summary, manIndent, m.Synopsis)
+	if m.Manual != "" {
+		text += "\nDESCRIPTION\n" + renderMarkdown(m.Manual, width)
+	}
+	if _, err := io.WriteString(w, text); err != nil {
+		return err
+	}
+	if len(m.Flags) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, "\nOPTIONS\n"); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, flag := range m.Flags {
+		if _, err := fmt.Fprintf(tw, "%v%v\n", manIndent, flag); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// renderMarkdown renders the Markdown of a manual page as indented text
+// wrapped to width columns. Only what the generated pages use is
+// understood: paragraphs, lists and indented code blocks.
+func renderMarkdown(md string, width int) string {
+	var sb strings.Builder
+	for i, block := range strings.Split(strings.TrimSpace(md), "\n\n") {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		lines := strings.Split(block, "\n")
+		switch {
+		case strings.HasPrefix(lines[0], "    ") || strings.HasPrefix(lines[0], "\t"):
+			for _, line := range lines {
+				sb.WriteString(manIndent + line + "\n")
+			}
+		case strings.HasPrefix(strings.TrimSpace(lines[0]), "- "):
+			for _, line := range lines {
+				item := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+				sb.WriteString(wrap(unescapeMarkdown(item), manIndent+"- ", manIndent+"  ", width))
+			}
+		default:
+			sb.WriteString(wrap(unescapeMarkdown(strings.Join(lines, " ")), manIndent, manIndent, width))
+		}
+	}
+
+	return sb.String()
+}
+
+// unescapeMarkdown removes the backslashes escaping punctuation in text.
+func unescapeMarkdown(text string) string {
+	return markdownEscape.ReplaceAllString(text, "$1")
+}
+
+// wrap fills the words of text into lines of at most width columns, where
+// words allow, starting the first with first and the rest with rest.
+func wrap(text, first, rest string, width int) string {
+	var sb strings.Builder
+	line, n := first, runewidth.StringWidth(first)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		wn := runewidth.StringWidth(word)
+		if !empty && n+1+wn > width {
+			sb.WriteString(line + "\n")
+			line, n, empty = rest, runewidth.StringWidth(rest), true
+		}
+		if !empty {
+			line += " "
+			n++
+		}
+		line += word
+		n += wn
+		empty = false
+	}
+	sb.WriteString(line + "\n")
+
+	return sb.String()
+}
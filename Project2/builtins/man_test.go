@@ -0,0 +1,59 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestMan(t *testing.T) {
+	// Not parallel: sets $COLUMNS.
+	t.Setenv("COLUMNS", "40")
+	builtins.Register("test-manual", nil, builtins.Meta{
+		Synopsis: "test-manual [-v] FILE",
+		Summary:  "show a manual",
+		Flags:    []string{"-v\tbe verbose"},
+		Manual: "It reads FILE \\(or standard input\\) and wraps its words to the width of the terminal.\n\n" +
+			"  - one\n  - two items\n\n" +
+			"    code  stays as it is\n",
+	})
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name: "page",
+			args: []string{"test-manual"},
+			wantOut: "NAME\n    test-manual - show a manual\n\n" +
+				"SYNOPSIS\n    test-manual [-v] FILE\n\n" +
+				"DESCRIPTION\n" +
+				"    It reads FILE (or standard input)\n" +
+				"    and wraps its words to the width of\n" +
+				"    the terminal.\n\n" +
+				"    - one\n    - two items\n\n" +
+				"        code  stays as it is\n\n" +
+				"OPTIONS\n    -v  be verbose\n",
+		},
+		{
+			name:    "generated from the handler's doc comment",
+			args:    []string{"whoami"},
+			wantOut: "NAME\n    whoami - print the current user name\n\nSYNOPSIS\n    whoami\n\nDESCRIPTION\n    It prints the name of the user\n    running the shell.\n",
+		},
+		{name: "no name", wantErr: builtins.ErrInvalidArgCount},
+		{name: "unknown", args: []string{"no-such-builtin"}, wantErr: builtins.ErrNotFound},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := builtins.Man(&out, tt.args...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%v: Man() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if tt.wantErr == nil && out.String() != tt.wantOut {
+			t.Errorf("%v: Man() got = %q, want %q", tt.name, out.String(), tt.wantOut)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package builtins
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"testing/fstest"
+	"time"
+)
+
+// maxSymlinks is how many symbolic links Stat follows before giving up on a
+// loop.
+const maxSymlinks = 40
+
+// MemFileSystem is a FileSystem held in memory, for testing builtins without
+// touching the disk. Its files are those of an fstest.MapFS, by path from its
+// root, which is the working directory; a symbolic link is a file with
+// fs.ModeSymlink whose data is the target. Only symbolic links at the end
+// of a path are followed.
+type MemFileSystem struct {
+	mu    sync.Mutex
+	files fstest.MapFS
+}
+
+// NewMemFileSystem returns a MemFileSystem starting with files, which it
+// takes over. Directories holding files need not be listed.
+func NewMemFileSystem(files fstest.MapFS) *MemFileSystem {
+	if files == nil {
+		files = fstest.MapFS{}
+	}
+
+	return &MemFileSystem{files: files}
+}
+
+// Files returns the files, for checking what builtins did to them.
+func (m *MemFileSystem) Files() fstest.MapFS {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	files := make(fstest.MapFS, len(m.files))
+	for name, f := range m.files {
+		files[name] = f
+	}
+
+	return files
+}
+
+func (m *MemFileSystem) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := m.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.files.Open(p)
+}
+
+func (m *MemFileSystem) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := m.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Stat(m.files, p)
+}
+
+func (m *MemFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lstat(fsPath(name))
+}
+
+func (m *MemFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := m.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.ReadDir(m.files, p)
+}
+
+func (m *MemFileSystem) Readlink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[fsPath(name)]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.Mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return string(f.Data), nil
+}
+
+func (m *MemFileSystem) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := m.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkParent("open", name, p); err != nil {
+		return nil, err
+	}
+	if f, ok := m.files[p]; ok {
+		if f.Mode.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		perm = f.Mode.Perm()
+	}
+	m.files[p] = &fstest.MapFile{Mode: perm, ModTime: time.Now()}
+
+	return &memWriter{m: m, path: p, perm: perm}, nil
+}
+
+func (m *MemFileSystem) Mkdir(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := fsPath(name)
+	if _, err := m.lstat(p); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if err := m.checkParent("mkdir", name, p); err != nil {
+		return err
+	}
+	m.files[p] = &fstest.MapFile{Mode: fs.ModeDir | perm.Perm(), ModTime: time.Now()}
+
+	return nil
+}
+
+func (m *MemFileSystem) Chmod(name string, mode fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := m.resolve("chmod", name)
+	if err != nil {
+		return err
+	}
+	info, err := fs.Stat(m.files, p)
+	if err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	f := &fstest.MapFile{ModTime: info.ModTime(), Mode: info.Mode()&fs.ModeType | mode.Perm()}
+	if old, ok := m.files[p]; ok {
+		// A directory only implied by the files in it gets an entry here.
+		f.Data = old.Data
+	}
+	m.files[p] = f
+
+	return nil
+}
+
+func (m *MemFileSystem) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := fsPath(newname)
+	if _, err := m.lstat(p); err == nil {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+	if err := m.checkParent("symlink", newname, p); err != nil {
+		return err
+	}
+	m.files[p] = &fstest.MapFile{Data: []byte(oldname), Mode: fs.ModeSymlink | 0o777, ModTime: time.Now()}
+
+	return nil
+}
+
+func (m *MemFileSystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := fsPath(name)
+	info, err := m.lstat(p)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if info.IsDir() {
+		if entries, _ := fs.ReadDir(m.files, p); len(entries) > 0 {
+			return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+	delete(m.files, p)
+
+	return nil
+}
+
+func (m *MemFileSystem) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := fsPath(name)
+	for file := range m.files {
+		if file == p || p == "." || strings.HasPrefix(file, p+"/") {
+			delete(m.files, file)
+		}
+	}
+
+	return nil
+}
+
+// lstat describes the file at p without following a symbolic link.
+func (m *MemFileSystem) lstat(p string) (fs.FileInfo, error) {
+	if f, ok := m.files[p]; ok {
+		return memInfo{name: path.Base(p), f: f}, nil
+	}
+
+	return fs.Stat(m.files, p)
+}
+
+// resolve returns the path from the root of the file name refers to,
+// following symbolic links.
+func (m *MemFileSystem) resolve(op, name string) (string, error) {
+	p := fsPath(name)
+	for i := 0; i < maxSymlinks; i++ {
+		f, ok := m.files[p]
+		if !ok || f.Mode&fs.ModeSymlink == 0 {
+			return p, nil
+		}
+		target := string(f.Data)
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(p), target)
+		}
+		p = fsPath(target)
+	}
+
+	return "", &fs.PathError{Op: op, Path: name, Err: syscall.ELOOP}
+}
+
+// checkParent checks that the directory to hold p exists.
+func (m *MemFileSystem) checkParent(op, name, p string) error {
+	dir := path.Dir(p)
+	if dir == "." {
+		return nil
+	}
+	info, err := fs.Stat(m.files, dir)
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !info.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+
+	return nil
+}
+
+// memInfo describes a file of a MemFileSystem as it is, without following
+// a symbolic link.
+type memInfo struct {
+	name string
+	f    *fstest.MapFile
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.f.Data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.f.Mode }
+func (i memInfo) ModTime() time.Time { return i.f.ModTime }
+func (i memInfo) IsDir() bool        { return i.f.Mode.IsDir() }
+func (i memInfo) Sys() interface{}   { return i.f.Sys }
+
+// memWriter writes a file of a MemFileSystem, which holds what was written
+// once it is closed.
+type memWriter struct {
+	m    *MemFileSystem
+	path string
+	perm fs.FileMode
+	buf  bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *memWriter) Close() error {
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	w.m.files[w.path] = &fstest.MapFile{Data: w.buf.Bytes(), Mode: w.perm, ModTime: time.Now()}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package builtins
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// fileError reports a failure on path in the "cmd: path: reason" form shared by the file builtins.
+// The underlying error is kept so callers can still match it with errors.Is.
+func fileError(cmd, path string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+
+	return fmt.Errorf("%v: %v: %w", cmd, path, err)
+}
+
+func init() {
+	Register("mkdir", func(ctx *Context, args ...string) error {
+		return MakeDirectory(args...)
+	}, Meta{
+		Synopsis: "mkdir [-p] DIR...",
+		Summary:  "create directories",
+		Flags:    []string{"-p\tcreate parents as needed, no error if existing"},
+	})
+}
+
+// MakeDirectory handles the "mkdir" built-in command.
+// With -p missing parents are created and existing directories are not an error.
+func MakeDirectory(args ...string) error {
+	parents := false
+	if len(args) > 0 && args[0] == "-p" {
+		parents = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected at least one directory", ErrInvalidArgCount)
+	}
+
+	for _, dir := range args {
+		var err error
+		if parents {
+			err = os.MkdirAll(dir, 0o777)
+		} else {
+			err = os.Mkdir(dir, 0o777)
+		}
+		if err != nil {
+			return fileError("mkdir", dir, err)
+		}
+	}
+
+	return nil
+}
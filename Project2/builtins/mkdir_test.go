@@ -0,0 +1,116 @@
+package builtins_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestMakeDirectory(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	tests := []struct {
+		name    string
+		args    []string
+		wantDir string
+		wantErr error
+	}{
+		{
+			name:    "single directory",
+			args:    []string{filepath.Join(tmp, "one")},
+			wantDir: filepath.Join(tmp, "one"),
+		},
+		{
+			name:    "missing parent",
+			args:    []string{filepath.Join(tmp, "a", "b")},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:    "parents",
+			args:    []string{"-p", filepath.Join(tmp, "x", "y", "z")},
+			wantDir: filepath.Join(tmp, "x", "y", "z"),
+		},
+		{
+			name:    "existing directory with -p",
+			args:    []string{"-p", tmp},
+			wantDir: tmp,
+		},
+		{
+			name:    "existing directory",
+			args:    []string{tmp},
+			wantErr: os.ErrExist,
+		},
+		{
+			name:    "no args",
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := builtins.MakeDirectory(tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("MakeDirectory() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("MakeDirectory() unexpected error: %v", err)
+			}
+			if info, err := os.Stat(tt.wantDir); err != nil || !info.IsDir() {
+				t.Errorf("MakeDirectory() did not create %v", tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestRemoveDirectory(t *testing.T) {
+	tmp := t.TempDir()
+	empty := filepath.Join(tmp, "empty")
+	full := filepath.Join(tmp, "full")
+	file := filepath.Join(full, "file")
+	if err := builtins.MakeDirectory(empty, full); err != nil {
+		t.Fatal(err)
+	}
+	if err := builtins.Touch(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builtins.RemoveDirectory(empty); err != nil {
+		t.Errorf("RemoveDirectory() unexpected error: %v", err)
+	}
+	if err := builtins.RemoveDirectory(full); err == nil {
+		t.Errorf("RemoveDirectory() removed a non-empty directory")
+	}
+	if err := builtins.RemoveDirectory(file); err == nil {
+		t.Errorf("RemoveDirectory() removed a file")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := builtins.Touch(file); err != nil {
+		t.Fatalf("Touch() unexpected error: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := builtins.Touch(file); err != nil {
+		t.Fatalf("Touch() unexpected error: %v", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().After(old) {
+		t.Errorf("Touch() did not update the modification time")
+	}
+	if err := builtins.Touch(); !errors.Is(err, builtins.ErrInvalidArgCount) {
+		t.Errorf("Touch() error = %v, wantErr %v", err, builtins.ErrInvalidArgCount)
+	}
+}
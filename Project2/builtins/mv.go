@@ -0,0 +1,42 @@
+package builtins
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func init() {
+	Register("mv", func(ctx *Context, args ...string) error {
+		return Move(args...)
+	}, Meta{
+		Synopsis: "mv SOURCE... DEST",
+		Summary:  "move or rename files",
+	})
+}
+
+// Move handles the "mv" built-in command.
+// It renames files and directories, falling back to copy and delete when the
+// destination is on another filesystem.
+func Move(args ...string) error {
+	return forEachSource(OSFileSystem, "mv", args, func(src, dst string) error {
+		err := os.Rename(src, dst)
+		if !errors.Is(err, syscall.EXDEV) {
+			return err
+		}
+
+		info, err := os.Lstat(src)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = copyTree(OSFileSystem, src, dst, false)
+		} else {
+			err = copyEntry(OSFileSystem, src, dst, info, false)
+		}
+		if err != nil {
+			return err
+		}
+		return os.RemoveAll(src)
+	})
+}
@@ -0,0 +1,379 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Escape sequences switching to the terminal's alternate screen, which the
+// pager draws on so that the shell's output is back when it quits, and
+// showing text in reverse video.
+const (
+	enterAltScreen = "\033[?1049h"
+	leaveAltScreen = "\033[?1049l"
+	reverseVideo   = "\033[7m"
+	resetVideo     = "\033[0m"
+)
+
+// pagerTabWidth is how many columns apart tab stops are.
+const pagerTabWidth = 8
+
+func init() {
+	for _, name := range []string{"less", "pager"} {
+		Register(name, func(ctx *Context, args ...string) error {
+			return Less(ctx.Stdin, ctx.Stdout, args...)
+		}, Meta{
+			Synopsis: name + " [FILE...]",
+			Summary:  "page through files or input; SPACE and b scroll, / searches, q quits",
+		})
+	}
+}
+
+// Less handles the "less" and "pager" built-in commands.
+// It shows the named files, or r when no file is given, a screen at a time
+// when w is a terminal, with the position in the status line. Keys
+// are read from the terminal, so that r can be a redirected file: SPACE or f
+// and b scroll a screen, j or Enter and k a line, d and u half a screen, g
+// and G go to the start and end, /PATTERN searches for a regular expression,
+// n and N find the next and previous match, and q quits. Otherwise, or if
+// the text fits on the screen, it is written out whole.
+func Less(r io.Reader, w io.Writer, args ...string) error {
+	files := make([]string, 0)
+	for _, arg := range args {
+		if len(arg) > 1 && arg[0] == '-' {
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		}
+		files = append(files, arg)
+	}
+	name := ""
+	if len(files) == 1 {
+		name = files[0]
+	}
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+
+	var text strings.Builder
+	for _, file := range files {
+		if err := withInput(r, file, func(in io.Reader) error {
+			_, err := io.Copy(&text, in)
+			return err
+		}); err != nil {
+			return fileError("less", file, err)
+		}
+	}
+
+	return Page(w, name, text.String())
+}
+
+// Page shows text through the pager, named name in its status line, if w
+// writes to a terminal that text doesn't fit on, and otherwise writes it to
+// w whole. The shell pages long builtin output with it under set -o autopage.
+func Page(w io.Writer, name, text string) error {
+	lines, _ := readLines(strings.NewReader(text))
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	cols, rows := TerminalSize(w)
+	if len(lines) < rows {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	tty, err := openTerminal()
+	if err != nil {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	defer tty.Close()
+	restore, err := enableCbreak(tty)
+	if err != nil {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	defer restore()
+
+	// Ctrl-C quits rather than killing the shell.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p := &pager{name: name, lines: lines, cols: cols, rows: rows}
+	if _, err := io.WriteString(w, enterAltScreen); err != nil {
+		return err
+	}
+	defer func() { _, _ = io.WriteString(w, leaveAltScreen) }()
+	for keys := readKeys(ctx, tty, true); ; {
+		p.cols, p.rows = TerminalSize(w)
+		if _, err := io.WriteString(w, p.screen()); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok || p.key(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// pager is the state of a paged text: the lines, the screen they are shown
+// on and the keys typed so far.
+type pager struct {
+	name       string
+	lines      []string
+	cols, rows int
+	top        int // the index of the first line shown
+
+	search  *regexp.Regexp
+	match   int    // the index of the line last found by search
+	message string // shown once in the status line, such as an error
+
+	typing bool   // whether a search is being typed after "/"
+	typed  string // the search typed so far
+	esc    []byte // the escape sequence being read, if not nil
+}
+
+// height returns how many lines of text fit above the status line.
+func (p *pager) height() int {
+	if p.rows < 2 {
+		return 1
+	}
+
+	return p.rows - 1
+}
+
+// scroll moves the text up by n lines, or down if n is negative, as far as
+// the screen still shows a line.
+func (p *pager) scroll(n int) {
+	p.top += n
+	if last := len(p.lines) - p.height(); p.top > last {
+		p.top = last
+	}
+	if p.top < 0 {
+		p.top = 0
+	}
+}
+
+// key handles a key typed, reporting whether the pager should quit.
+func (p *pager) key(b byte) bool {
+	switch {
+	case p.typing:
+		p.typeSearch(b)
+		return false
+	case p.esc != nil:
+		p.escape(b)
+		return false
+	}
+
+	p.message = ""
+	half := p.height() / 2
+	switch b {
+	case 'q', 'Q':
+		return true
+	case '\033':
+		p.esc = []byte{}
+	case ' ', 'f', 'z', 0x06: // Ctrl-F
+		p.scroll(p.height())
+	case 'b', 'w', 0x02: // Ctrl-B
+		p.scroll(-p.height())
+	case 'j', 'e', '\r', '\n', 0x0e: // Ctrl-N
+		p.scroll(1)
+	case 'k', 'y', 0x10: // Ctrl-P
+		p.scroll(-1)
+	case 'd', 0x04: // Ctrl-D
+		p.scroll(half)
+	case 'u', 0x15: // Ctrl-U
+		p.scroll(-half)
+	case 'g', '<':
+		p.top = 0
+	case 'G', '>':
+		p.scroll(len(p.lines))
+	case '/':
+		p.typing, p.typed = true, ""
+	case 'n':
+		p.find(p.nextFrom(1), 1)
+	case 'N':
+		p.find(p.nextFrom(-1), -1)
+	}
+
+	return false
+}
+
+// escape adds b to the escape sequence being read and, once it is complete,
+// handles the key it stands for.
+func (p *pager) escape(b byte) {
+	p.esc = append(p.esc, b)
+	if p.esc[0] != '[' && p.esc[0] != 'O' {
+		p.esc = nil
+		return
+	}
+	if len(p.esc) < 2 || b < 0x40 || b > 0x7e {
+		if len(p.esc) > 8 {
+			p.esc = nil
+		}
+		return
+	}
+	seq := string(p.esc[1:])
+	p.esc = nil
+	switch seq {
+	case "A":
+		p.scroll(-1)
+	case "B":
+		p.scroll(1)
+	case "5~":
+		p.scroll(-p.height())
+	case "6~":
+		p.scroll(p.height())
+	case "H", "1~":
+		p.top = 0
+	case "F", "4~":
+		p.scroll(len(p.lines))
+	}
+}
+
+// typeSearch adds b to the search being typed, running it on Enter.
+// Backspacing past its start or Escape abandons it.
+func (p *pager) typeSearch(b byte) {
+	switch b {
+	case '\r', '\n':
+		p.typing = false
+		if p.typed != "" {
+			re, err := regexp.Compile(p.typed)
+			if err != nil {
+				p.message = "Invalid pattern: " + p.typed
+				return
+			}
+			p.search = re
+		}
+		p.find(p.top, 1)
+	case 0x7f, 0x08:
+		if p.typed == "" {
+			p.typing = false
+			return
+		}
+		r := []rune(p.typed)
+		p.typed = string(r[:len(r)-1])
+	case '\033', 0x03:
+		p.typing = false
+	default:
+		if b >= ' ' {
+			p.typed += string([]byte{b})
+		}
+	}
+}
+
+// nextFrom returns the line a search for the next match in direction dir
+// starts from: next to the last match if it is on the screen, or else the
+// screen's first line.
+func (p *pager) nextFrom(dir int) int {
+	if p.match >= p.top && p.match < p.top+p.height() {
+		return p.match + dir
+	}
+	if dir < 0 {
+		return p.top - 1
+	}
+
+	return p.top
+}
+
+// find looks for the search from line from in direction dir and scrolls the
+// first matching line to the top of the screen, as far as it goes.
+func (p *pager) find(from, dir int) {
+	if p.search == nil {
+		p.message = "No previous search"
+		return
+	}
+	for i := from; i >= 0 && i < len(p.lines); i += dir {
+		if p.search.MatchString(p.lines[i]) {
+			p.match = i
+			p.top = 0
+			p.scroll(i)
+			return
+		}
+	}
+	p.message = "Pattern not found"
+}
+
+// screen returns the escape sequences and text drawing the screen: the lines
+// from top, cut to the screen's width and with matches of the search in
+// reverse video, and a status line.
+func (p *pager) screen() string {
+	var sb strings.Builder
+	sb.WriteString(clearScreen)
+	for i := p.top; i < p.top+p.height(); i++ {
+		if i >= len(p.lines) {
+			sb.WriteString("~\n")
+			continue
+		}
+		line := runewidth.Truncate(expandTabs(p.lines[i]), p.cols, "")
+		if p.search != nil {
+			line = p.search.ReplaceAllStringFunc(line, func(m string) string {
+				if m == "" {
+					return m
+				}
+				return reverseVideo + m + resetVideo
+			})
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(p.status())
+
+	return sb.String()
+}
+
+// status returns the status line: the search being typed, a message, or
+// the lines shown and how far through the text they are.
+func (p *pager) status() string {
+	if p.typing {
+		return "/" + p.typed
+	}
+	if p.message != "" {
+		return reverseVideo + p.message + resetVideo
+	}
+	bottom := p.top + p.height()
+	if bottom >= len(p.lines) {
+		bottom = len(p.lines)
+	}
+	status := fmt.Sprintf("lines %d-%d/%d %d%%", p.top+1, bottom, len(p.lines), bottom*100/len(p.lines))
+	if bottom == len(p.lines) {
+		status += " (END)"
+	}
+	if p.name != "" {
+		status = p.name + " " + status
+	}
+
+	return reverseVideo + status + resetVideo
+}
+
+// expandTabs replaces the tabs in line with spaces up to the next tab stop.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var sb strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := pagerTabWidth - col%pagerTabWidth
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r)
+		col += runewidth.RuneWidth(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLess(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(file, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{name: "stdin", stdin: "a\nb", wantOut: "a\nb"},
+		{name: "files", args: []string{file, file}, wantOut: "one\ntwo\none\ntwo\n"},
+		{name: "missing file", args: []string{file + "x"}, wantErr: os.ErrNotExist},
+		{name: "unknown flag", args: []string{"-S"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := Less(strings.NewReader(tt.stdin), &out, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Less() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); tt.wantErr == nil && got != tt.wantOut {
+				t.Errorf("Less() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+// newTestPager returns a pager over 100 numbered lines on a 5-line screen.
+func newTestPager() *pager {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	return &pager{name: "nums", lines: lines, cols: 20, rows: 5}
+}
+
+func Test_pagerKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		keys       string
+		wantTop    int
+		wantStatus string
+	}{
+		{name: "start", wantStatus: "nums lines 1-4/100 4%"},
+		{name: "screen forward and back", keys: "  b", wantTop: 4, wantStatus: "nums lines 5-8/100 8%"},
+		{name: "lines", keys: "jj\rk", wantTop: 2},
+		{name: "half screens", keys: "ddu", wantTop: 2},
+		{name: "end", keys: "G", wantTop: 96, wantStatus: "nums lines 97-100/100 100% (END)"},
+		{name: "not before the start", keys: "kkb", wantTop: 0},
+		{name: "arrows and page keys", keys: "\033[B\033[B\033[6~\033[A", wantTop: 5},
+		{name: "home and end keys", keys: "\033[F\033[H", wantTop: 0},
+		{name: "search", keys: "/line 5\r", wantTop: 4},
+		{name: "next and previous match", keys: "/line 5\rnnN", wantTop: 49},
+		{name: "search from the screen", keys: "G/^line 2\r", wantTop: 96, wantStatus: "Pattern not found"},
+		{name: "no previous search", keys: "n", wantStatus: "No previous search"},
+		{name: "bad pattern", keys: "/(\r", wantStatus: "Invalid pattern: ("},
+		{name: "typing", keys: "/lx\x7fi", wantStatus: "/li"},
+		{name: "search abandoned", keys: "/x\x7f\x7fj", wantTop: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := newTestPager()
+			for _, b := range []byte(tt.keys) {
+				if p.key(b) {
+					t.Fatalf("key(%q) quit", b)
+				}
+			}
+			if p.top != tt.wantTop {
+				t.Errorf("after %q top = %d, want %d", tt.keys, p.top, tt.wantTop)
+			}
+			status := strings.TrimSuffix(strings.TrimPrefix(p.status(), reverseVideo), resetVideo)
+			if tt.wantStatus != "" && status != tt.wantStatus {
+				t.Errorf("after %q status = %q, want %q", tt.keys, status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_pagerQuit(t *testing.T) {
+	t.Parallel()
+	p := newTestPager()
+	for _, b := range []byte("/q\033") {
+		if p.key(b) {
+			t.Fatalf("key(%q) in a search quit", b)
+		}
+	}
+	if !p.key('q') {
+		t.Fatal("q didn't quit")
+	}
+}
+
+func Test_pagerScreen(t *testing.T) {
+	t.Parallel()
+	p := &pager{lines: []string{"a\tb", "世界 wide and long enough to cut", "bab"}, cols: 12, rows: 5}
+	for _, b := range []byte("/b\r") {
+		p.key(b)
+	}
+	hl := func(s string) string { return reverseVideo + s + resetVideo }
+	want := clearScreen +
+		"a       " + hl("b") + "\n" +
+		"世界 wide an\n" +
+		hl("b") + "a" + hl("b") + "\n" +
+		"~\n" +
+		hl("lines 1-3/3 100% (END)")
+	if got := p.screen(); got != want {
+		t.Errorf("screen() = %q, want %q", got, want)
+	}
+}
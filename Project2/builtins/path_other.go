@@ -0,0 +1,84 @@
+//go:build windows
+
+package builtins
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultPathExt is the executable extensions when $PATHEXT is unset.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// IsPath reports whether a command name is a path to a program rather than a
+// name to look up in PATH: whether it contains a slash, a backslash or a
+// drive letter.
+func IsPath(name string) bool {
+	return strings.ContainsAny(name, `/\:`)
+}
+
+// IsExecutable reports whether the file name, described by info, can be
+// run: whether it is a regular file with an extension in $PATHEXT, as
+// Windows has no execute bits.
+func IsExecutable(name string, info fs.FileInfo) bool {
+	return info.Mode().IsRegular() && executableExt(filepath.Ext(name))
+}
+
+// CommandName returns the name the program file in a PATH directory is run
+// by: its file name without an executable extension, so that go.exe is
+// run as go.
+func CommandName(file string) string {
+	if ext := filepath.Ext(file); executableExt(ext) {
+		return strings.TrimSuffix(file, ext)
+	}
+
+	return file
+}
+
+// pathCandidates returns the file names in a PATH directory that the
+// command name may be: the name with each extension in $PATHEXT, unless it
+// has one already.
+func pathCandidates(name string) []string {
+	if executableExt(filepath.Ext(name)) {
+		return []string{name}
+	}
+	exts := pathExts()
+	names := make([]string, len(exts))
+	for i, ext := range exts {
+		names[i] = name + ext
+	}
+
+	return names
+}
+
+// pathExts returns the extensions in $PATHEXT.
+func pathExts() []string {
+	env := os.Getenv("PATHEXT")
+	if env == "" {
+		env = defaultPathExt
+	}
+	var exts []string
+	for _, ext := range filepath.SplitList(env) {
+		if ext != "" {
+			exts = append(exts, strings.ToLower(ext))
+		}
+	}
+
+	return exts
+}
+
+// executableExt reports whether ext is in $PATHEXT.
+func executableExt(ext string) bool {
+	if ext == "" {
+		return false
+	}
+	for _, e := range pathExts() {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+
+	return false
+}
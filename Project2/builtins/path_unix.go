@@ -0,0 +1,32 @@
+//go:build !windows
+
+package builtins
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// IsPath reports whether a command name is a path to a program rather than a
+// name to look up in PATH: whether it contains a slash.
+func IsPath(name string) bool {
+	return strings.ContainsRune(name, '/')
+}
+
+// IsExecutable reports whether the file name, described by info, can be
+// run: whether it is a regular file with an execute bit set.
+func IsExecutable(name string, info fs.FileInfo) bool {
+	return info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// CommandName returns the name the program file in a PATH directory is run
+// by, which is its file name.
+func CommandName(file string) string {
+	return file
+}
+
+// pathCandidates returns the file names in a PATH directory that the
+// command name may be.
+func pathCandidates(name string) []string {
+	return []string{name}
+}
@@ -0,0 +1,208 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"regexp"
+	"sort"
+	"strconv"
+	"syscall"
+)
+
+func init() {
+	Register("pgrep", func(ctx *Context, args ...string) error {
+		return Pgrep(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "pgrep [-fl] [-u USER] PATTERN",
+		Summary:  "list the IDs of processes whose names match a pattern",
+		Flags: []string{
+			"-f\tmatch the full command line, not only the name",
+			"-l\tprint the name, or the command line with -f, after each ID",
+			"-u USER\tonly processes of USER, a name or a user ID",
+		},
+	})
+	Register("pkill", func(ctx *Context, args ...string) error {
+		return Pkill(args...)
+	}, Meta{
+		Synopsis: "pkill [-SIGNAL | -s SIGNAL] [-f] [-u USER] PATTERN",
+		Summary:  "signal processes whose names match a pattern",
+		Flags: []string{
+			"-SIGNAL\tsignal to send, by name or number (default TERM)",
+			"-s SIGNAL\tthe same, as a separate argument",
+			"-f\tmatch the full command line, not only the name",
+			"-u USER\tonly processes of USER, a name or a user ID",
+		},
+	})
+}
+
+// processMatch selects processes for pgrep and pkill.
+type processMatch struct {
+	pattern *regexp.Regexp
+	full    bool
+	uid     int // -1 for any user
+}
+
+// Pgrep handles the "pgrep" built-in command.
+// It prints the ID of each process whose name matches the regular expression
+// PATTERN, or whose command line does with -f, and exits with status 1 if
+// there are none. The shell itself is never listed.
+func Pgrep(w io.Writer, args ...string) error {
+	var long bool
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "-l" {
+			long = true
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+	m, err := parseProcessMatch(rest, nil)
+	if err != nil {
+		return err
+	}
+
+	procs, err := m.find()
+	if err != nil {
+		return err
+	}
+	for _, p := range procs {
+		line := strconv.Itoa(p.PID)
+		if long && m.full {
+			line += " " + p.Command
+		} else if long {
+			line += " " + p.Name
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if len(procs) == 0 {
+		return &ExitError{Status: StatusFailure}
+	}
+
+	return nil
+}
+
+// Pkill handles the "pkill" built-in command.
+// It sends a signal, TERM unless another is given, to each process pgrep
+// would list, and exits with status 1 if there are none.
+func Pkill(args ...string) error {
+	sig := syscall.SIGTERM
+	m, err := parseProcessMatch(args, &sig)
+	if err != nil {
+		return err
+	}
+
+	procs, err := m.find()
+	if err != nil {
+		return err
+	}
+	for _, p := range procs {
+		proc, err := os.FindProcess(p.PID)
+		if err == nil {
+			err = proc.Signal(sig)
+		}
+		if err != nil {
+			return fmt.Errorf("pkill: %d: %w", p.PID, err)
+		}
+	}
+	if len(procs) == 0 {
+		return &ExitError{Status: StatusFailure}
+	}
+
+	return nil
+}
+
+// parseProcessMatch parses the arguments pgrep and pkill share. If sig is not
+// nil, -SIGNAL and -s SIGNAL set it.
+func parseProcessMatch(args []string, sig *syscall.Signal) (processMatch, error) {
+	m := processMatch{uid: -1}
+	var pattern string
+	havePattern := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-f":
+			m.full = true
+		case arg == "-u" || (arg == "-s" && sig != nil):
+			if i+1 == len(args) {
+				return m, fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+			}
+			i++
+			var err error
+			if arg == "-u" {
+				m.uid, err = lookupUID(args[i])
+			} else {
+				*sig, err = parseSignal(args[i])
+			}
+			if err != nil {
+				return m, err
+			}
+		case len(arg) > 1 && arg[0] == '-' && sig != nil:
+			s, err := parseSignal(arg[1:])
+			if err != nil {
+				return m, fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+			}
+			*sig = s
+		case len(arg) > 1 && arg[0] == '-':
+			return m, fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		case havePattern:
+			return m, fmt.Errorf("%w: expected only one pattern", ErrInvalidArgCount)
+		default:
+			pattern, havePattern = arg, true
+		}
+	}
+	if !havePattern {
+		return m, fmt.Errorf("%w: expected a pattern", ErrInvalidArgCount)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return m, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	m.pattern = re
+
+	return m, nil
+}
+
+// lookupUID returns the user ID of a user name, or of a numeric ID.
+func lookupUID(name string) (int, error) {
+	if uid, err := strconv.Atoi(name); err == nil {
+		return uid, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, fmt.Errorf("%w: user %v has no numeric ID", ErrInvalidArgs, name)
+	}
+
+	return uid, nil
+}
+
+// find returns the matching processes other than the shell, by process ID.
+func (m processMatch) find() ([]ProcessInfo, error) {
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	self := os.Getpid()
+	matched := procs[:0]
+	for _, p := range procs {
+		text := p.Name
+		if m.full {
+			text = p.Command
+		}
+		if p.PID != self && (m.uid < 0 || p.UID == m.uid) && m.pattern.MatchString(text) {
+			matched = append(matched, p)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].PID < matched[j].PID })
+
+	return matched, nil
+}
@@ -0,0 +1,77 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestPgrepPkill(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sleep and signals")
+	}
+	cmd := exec.Command("sleep", "31.4159")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("needs sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+	})
+	pid := cmd.Process.Pid
+
+	var w bytes.Buffer
+	if err := builtins.Pgrep(&w, "-f", "-l", `^sleep 31\.4159$`); err != nil {
+		t.Fatalf("Pgrep() error = %v", err)
+	}
+	if got, want := w.String(), fmt.Sprintf("%d sleep 31.4159\n", pid); got != want {
+		t.Errorf("Pgrep() got = %q, want %q", got, want)
+	}
+	w.Reset()
+	if err := builtins.Pgrep(&w, "-u", fmt.Sprint(os.Getuid()+1), "-f", `^sleep 31\.4159$`); builtins.StatusOf(err) != builtins.StatusFailure {
+		t.Errorf("Pgrep() -u other error = %v, want status 1", err)
+	}
+
+	if err := builtins.Pkill("-KILL", "-f", `^sleep 31\.4159$`); err != nil {
+		t.Fatalf("Pkill() error = %v", err)
+	}
+	if err := cmd.Wait(); err == nil || cmd.ProcessState.String() != "signal: killed" {
+		t.Errorf("Pkill() left the process with %v", cmd.ProcessState)
+	}
+	if err := builtins.Pkill("-f", `^sleep 31\.4159$`); builtins.StatusOf(err) != builtins.StatusFailure {
+		t.Errorf("Pkill() with no match error = %v, want status 1", err)
+	}
+}
+
+func TestPgrepErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "no pattern", args: []string{"-f"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "two patterns", args: []string{"a", "b"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "bad pattern", args: []string{"("}, wantErr: builtins.ErrInvalidArgs},
+		{name: "unknown flag", args: []string{"-x", "a"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "no user", args: []string{"-u"}, wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := builtins.Pgrep(&bytes.Buffer{}, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pgrep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := builtins.Pkill("-NOPE", "a"); !errors.Is(err, builtins.ErrInvalidArgs) {
+		t.Errorf("Pkill() error = %v, wantErr %v", err, builtins.ErrInvalidArgs)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package builtins
+
+import "errors"
+
+var errPriorityUnsupported = errors.New("process niceness is not supported on this platform")
+
+// Niceness returns the scheduling niceness of a process.
+func Niceness(int) (int, error) {
+	return 0, errPriorityUnsupported
+}
+
+// SetNiceness sets the scheduling niceness of a process.
+func SetNiceness(int, int) error {
+	return errPriorityUnsupported
+}
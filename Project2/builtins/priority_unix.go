@@ -0,0 +1,30 @@
+//go:build !windows
+
+package builtins
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// Niceness returns the scheduling niceness of a process, 0 being the usual
+// value, or of the shell for pid 0.
+func Niceness(pid int) (int, error) {
+	prio, err := unix.Getpriority(unix.PRIO_PROCESS, pid)
+	if err != nil {
+		return 0, err
+	}
+	if runtime.GOOS == "linux" {
+		// The Linux system call returns 20 - nice, to keep clear of -1.
+		prio = 20 - prio
+	}
+
+	return prio, nil
+}
+
+// SetNiceness sets the scheduling niceness of a process, clamped by the system
+// to -20..19.
+func SetNiceness(pid, n int) error {
+	return unix.Setpriority(unix.PRIO_PROCESS, pid, n)
+}
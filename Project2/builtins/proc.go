@@ -0,0 +1,23 @@
+package builtins
+
+import "time"
+
+// ProcessInfo is a snapshot of one running process.
+type ProcessInfo struct {
+	PID     int
+	PPID    int
+	UID     int
+	State   string
+	RSS     int64         // resident set size in bytes
+	CPUTime time.Duration // user plus system time consumed so far
+	Name    string        // short executable name
+	Command string        // full command line, or [Name] when unavailable
+}
+
+// MemoryInfo is a snapshot of physical memory and swap use, in bytes.
+type MemoryInfo struct {
+	Total, Free, Available int64
+	Shared                 int64
+	BuffCache              int64 // buffers, page cache and reclaimable kernel memory
+	SwapTotal, SwapFree    int64
+}
@@ -0,0 +1,140 @@
+package builtins
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// clockTicks is the kernel's USER_HZ used by /proc/[pid]/stat times; it is 100 on every mainstream Linux.
+const clockTicks = 100
+
+// listProcesses reads a snapshot of every process from /proc.
+// Processes that exit while being read are skipped.
+func listProcesses() ([]ProcessInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int64(os.Getpagesize())
+	procs := make([]ProcessInfo, 0, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		p, err := readProcess(pid, pageSize)
+		if err != nil {
+			continue
+		}
+		procs = append(procs, p)
+	}
+
+	return procs, nil
+}
+
+// readProcess parses /proc/[pid]/stat and /proc/[pid]/cmdline.
+func readProcess(pid int, pageSize int64) (ProcessInfo, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+
+	// The command name is parenthesised and may itself contain spaces or parentheses.
+	open, closing := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || closing < open {
+		return ProcessInfo{}, fmt.Errorf("malformed %v/stat", dir)
+	}
+	fields := strings.Fields(string(stat[closing+1:]))
+	if len(fields) < 22 {
+		return ProcessInfo{}, fmt.Errorf("malformed %v/stat", dir)
+	}
+
+	p := ProcessInfo{
+		PID:   pid,
+		State: fields[0],
+		Name:  string(stat[open+1 : closing]),
+	}
+	p.PPID, _ = strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	p.CPUTime = time.Duration(utime+stime) * time.Second / clockTicks
+	rss, _ := strconv.ParseInt(fields[21], 10, 64)
+	p.RSS = rss * pageSize
+
+	if info, err := os.Stat(dir); err == nil {
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			p.UID = int(st.Uid)
+		}
+	}
+
+	cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+	cmdline = bytes.TrimRight(cmdline, "\x00")
+	if len(cmdline) == 0 {
+		p.Command = "[" + p.Name + "]"
+	} else {
+		p.Command = string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))
+	}
+
+	return p, nil
+}
+
+// totalMemory reads MemTotal from /proc/meminfo, in bytes.
+func totalMemory() (int64, error) {
+	b, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			return kb * 1024, err
+		}
+	}
+
+	return 0, fmt.Errorf("MemTotal missing from /proc/meminfo")
+}
+
+// memoryInfo reads memory and swap use from /proc/meminfo.
+func memoryInfo() (MemoryInfo, error) {
+	b, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+	kb := make(map[string]int64)
+	for _, line := range strings.Split(string(b), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			n, err := strconv.ParseInt(fields[1], 10, 64)
+			if err == nil {
+				kb[strings.TrimSuffix(fields[0], ":")] = n * 1024
+			}
+		}
+	}
+	if kb["MemTotal"] == 0 {
+		return MemoryInfo{}, fmt.Errorf("MemTotal missing from /proc/meminfo")
+	}
+
+	m := MemoryInfo{
+		Total:     kb["MemTotal"],
+		Free:      kb["MemFree"],
+		Shared:    kb["Shmem"],
+		BuffCache: kb["Buffers"] + kb["Cached"] + kb["SReclaimable"],
+		SwapTotal: kb["SwapTotal"],
+		SwapFree:  kb["SwapFree"],
+	}
+	if avail, ok := kb["MemAvailable"]; ok {
+		m.Available = avail
+	} else {
+		// Kernels before 3.14 don't estimate it.
+		m.Available = m.Free + m.BuffCache
+	}
+
+	return m, nil
+}
@@ -0,0 +1,84 @@
+//go:build !linux
+
+package builtins
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// listProcesses uses gopsutil on platforms without a Linux-style /proc.
+// Processes that exit while being read are skipped.
+func listProcesses() ([]ProcessInfo, error) {
+	handles, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	procs := make([]ProcessInfo, 0, len(handles))
+	for _, h := range handles {
+		name, err := h.Name()
+		if err != nil {
+			continue
+		}
+		p := ProcessInfo{
+			PID:     int(h.Pid),
+			Name:    name,
+			Command: "[" + name + "]",
+		}
+		if ppid, err := h.Ppid(); err == nil {
+			p.PPID = int(ppid)
+		}
+		if uids, err := h.Uids(); err == nil && len(uids) > 0 {
+			p.UID = int(uids[0])
+		}
+		if status, err := h.Status(); err == nil && len(status) > 0 {
+			p.State = strings.ToUpper(status[0][:1])
+		}
+		if mem, err := h.MemoryInfo(); err == nil {
+			p.RSS = int64(mem.RSS)
+		}
+		if times, err := h.Times(); err == nil {
+			p.CPUTime = time.Duration((times.User + times.System) * float64(time.Second))
+		}
+		if cmdline, err := h.Cmdline(); err == nil && cmdline != "" {
+			p.Command = cmdline
+		}
+		procs = append(procs, p)
+	}
+
+	return procs, nil
+}
+
+// totalMemory returns the amount of physical memory, in bytes.
+func totalMemory() (int64, error) {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(vm.Total), nil
+}
+
+// memoryInfo asks gopsutil for memory and swap use.
+func memoryInfo() (MemoryInfo, error) {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+	m := MemoryInfo{
+		Total:     int64(vm.Total),
+		Free:      int64(vm.Free),
+		Available: int64(vm.Available),
+		Shared:    int64(vm.Shared),
+		BuffCache: int64(vm.Buffers + vm.Cached + vm.Sreclaimable),
+	}
+	if swap, err := mem.SwapMemory(); err == nil {
+		m.SwapTotal, m.SwapFree = int64(swap.Total), int64(swap.Free)
+	}
+
+	return m, nil
+}
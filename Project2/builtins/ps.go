@@ -0,0 +1,118 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// processLess orders processes by one ps sort key.
+var processLess = map[string]func(a, b ProcessInfo) bool{
+	"pid":   func(a, b ProcessInfo) bool { return a.PID < b.PID },
+	"ppid":  func(a, b ProcessInfo) bool { return a.PPID < b.PPID },
+	"state": func(a, b ProcessInfo) bool { return a.State < b.State },
+	"rss":   func(a, b ProcessInfo) bool { return a.RSS < b.RSS },
+	"time":  func(a, b ProcessInfo) bool { return a.CPUTime < b.CPUTime },
+	"cmd":   func(a, b ProcessInfo) bool { return a.Command < b.Command },
+}
+
+func init() {
+	Register("ps", func(ctx *Context, args ...string) error {
+		return ProcessStatus(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "ps [-e] [--sort KEY]",
+		Summary:  "list processes",
+		Paged:    true,
+		Flags: []string{
+			"-e\tevery process, not only the current user's",
+			"--sort KEY\tpid, ppid, state, rss, time or cmd; prefix - to reverse",
+		},
+	})
+}
+
+// ProcessStatus handles the "ps" built-in command.
+// It lists the current user's processes, or every process with -e, ordered by
+// --sort KEY (pid, ppid, state, rss, time or cmd; prefix with - to reverse).
+func ProcessStatus(w io.Writer, args ...string) error {
+	var (
+		all     bool
+		sortKey = "pid"
+	)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-e", arg == "-A":
+			all = true
+		case arg == "--sort":
+			if len(args) < i+2 {
+				return fmt.Errorf("%w: --sort requires an argument", ErrInvalidArgCount)
+			}
+			sortKey = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--sort="):
+			sortKey = strings.TrimPrefix(arg, "--sort=")
+		default:
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, arg)
+		}
+	}
+	less, err := sortProcesses(sortKey)
+	if err != nil {
+		return err
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return err
+	}
+	if !all {
+		uid := os.Getuid()
+		mine := procs[:0]
+		for _, p := range procs {
+			if p.UID == uid {
+				mine = append(mine, p)
+			}
+		}
+		procs = mine
+	}
+	sort.SliceStable(procs, func(i, j int) bool {
+		return less(procs[i], procs[j])
+	})
+
+	if _, err := fmt.Fprintf(w, "%7v %7v %1v %10v %8v %v\n", "PID", "PPID", "S", "RSS", "TIME", "COMMAND"); err != nil {
+		return err
+	}
+	for _, p := range procs {
+		if _, err := fmt.Fprintf(w, "%7d %7d %1v %10d %8v %v\n",
+			p.PID, p.PPID, p.State, p.RSS/1024, formatCPUTime(p.CPUTime), p.Command); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortProcesses returns the ordering for a ps sort key, reversed when it starts with "-".
+func sortProcesses(key string) (func(a, b ProcessInfo) bool, error) {
+	desc := strings.HasPrefix(key, "-")
+	less, ok := processLess[strings.TrimPrefix(strings.TrimPrefix(key, "-"), "+")]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort key %q", ErrInvalidArgs, key)
+	}
+	if desc {
+		return func(a, b ProcessInfo) bool { return less(b, a) }, nil
+	}
+
+	return less, nil
+}
+
+// formatCPUTime formats accumulated CPU time as [h:]mm:ss.
+func formatCPUTime(d time.Duration) string {
+	secs := int64(d / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
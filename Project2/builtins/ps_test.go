@@ -0,0 +1,87 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessStatus(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "current user"},
+		{name: "every process", args: []string{"-e"}},
+		{name: "sorted by memory", args: []string{"-e", "--sort=-rss"}},
+		{name: "separate sort key", args: []string{"--sort", "cmd"}},
+		{name: "unknown sort key", args: []string{"--sort=size"}, wantErr: ErrInvalidArgs},
+		{name: "missing sort key", args: []string{"--sort"}, wantErr: ErrInvalidArgCount},
+		{name: "unknown flag", args: []string{"-z"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := ProcessStatus(&out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ProcessStatus() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("ProcessStatus() unexpected error: %v", err)
+			}
+			self := fmt.Sprintf("%7d ", os.Getpid())
+			if !strings.Contains(out.String(), "\n"+self) {
+				t.Errorf("ProcessStatus() did not list the test process %d:\n%v", os.Getpid(), out.String())
+			}
+		})
+	}
+}
+
+func Test_sortProcesses(t *testing.T) {
+	t.Parallel()
+	procs := []ProcessInfo{{PID: 1, RSS: 30}, {PID: 2, RSS: 10}, {PID: 3, RSS: 20}}
+	for key, want := range map[string][]int{
+		"pid":  {1, 2, 3},
+		"-pid": {3, 2, 1},
+		"rss":  {2, 3, 1},
+		"-rss": {1, 3, 2},
+	} {
+		less, err := sortProcesses(key)
+		if err != nil {
+			t.Fatalf("sortProcesses(%q) unexpected error: %v", key, err)
+		}
+		sorted := append([]ProcessInfo{}, procs...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return less(sorted[i], sorted[j])
+		})
+		for i, p := range sorted {
+			if p.PID != want[i] {
+				t.Errorf("sortProcesses(%q) order = %v, want %v", key, sorted, want)
+				break
+			}
+		}
+	}
+}
+
+func Test_formatCPUTime(t *testing.T) {
+	t.Parallel()
+	for d, want := range map[time.Duration]string{
+		0:                           "00:00",
+		75 * time.Second:            "01:15",
+		2*time.Hour + 3*time.Second: "2:00:03",
+	} {
+		if got := formatCPUTime(d); got != want {
+			t.Errorf("formatCPUTime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -6,8 +6,41 @@ import (
 	"os"
 )
 
-func Pwd(w io.Writer) error {
-	wd, err := os.Getwd()
+func init() {
+	Register("pwd", func(ctx *Context, args ...string) error {
+		return Pwd(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "pwd [-L | -P]",
+		Summary:  "print the working directory",
+		Flags: []string{
+			"-L\tprint the logical path, with symbolic links as typed (default)",
+			"-P\tprint the physical path, with symbolic links resolved",
+		},
+	})
+}
+
+// Pwd handles the "pwd" built-in command.
+// By default it prints $PWD, the path cd took, when that still names the
+// working directory; -P prints the path with symbolic links resolved.
+func Pwd(w io.Writer, args ...string) error {
+	physical := false
+	for _, arg := range args {
+		switch arg {
+		case "-L":
+			physical = false
+		case "-P":
+			physical = true
+		default:
+			return fmt.Errorf("%w: pwd: unexpected argument %v", ErrInvalidArgs, arg)
+		}
+	}
+
+	// os.Getwd returns $PWD when it is an absolute path to the working directory.
+	getwd := os.Getwd
+	if physical {
+		getwd = physicalWd
+	}
+	wd, err := getwd()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,78 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestPwd_symlinks(t *testing.T) {
+	// Not parallel: changes the working directory and $PWD.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PWD", wd)
+	t.Setenv("OLDPWD", "")
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	real := filepath.Join(root, "real", "dir")
+	link := filepath.Join(root, "link")
+	if err := os.MkdirAll(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	steps := []struct {
+		name       string
+		cd         []string
+		wantL      string
+		wantP      string
+		wantOldPwd string
+	}{
+		{name: "into the link", cd: []string{link}, wantL: link, wantP: real, wantOldPwd: wd},
+		{name: "dot dot goes back along the link", cd: []string{".."}, wantL: root, wantP: root, wantOldPwd: link},
+		{name: "relative", cd: []string{"link"}, wantL: link, wantP: real, wantOldPwd: root},
+		{name: "physical dot dot", cd: []string{"-P", ".."}, wantL: filepath.Dir(real), wantP: filepath.Dir(real), wantOldPwd: link},
+		{name: "physical", cd: []string{"-P", link}, wantL: real, wantP: real, wantOldPwd: filepath.Dir(real)},
+		{name: "-L after -P", cd: []string{"-P", "-L", link}, wantL: link, wantP: real, wantOldPwd: real},
+	}
+	for _, step := range steps {
+		if err := builtins.ChangeDirectory(step.cd...); err != nil {
+			t.Fatalf("%v: ChangeDirectory() unexpected error: %v", step.name, err)
+		}
+		for _, flag := range []string{"-L", "-P"} {
+			want := step.wantL
+			if flag == "-P" {
+				want = step.wantP
+			}
+			var out bytes.Buffer
+			if err := builtins.Pwd(&out, flag); err != nil {
+				t.Fatalf("%v: Pwd(%v) unexpected error: %v", step.name, flag, err)
+			}
+			if got := out.String(); got != want+"\n" {
+				t.Errorf("%v: Pwd(%v) got = %q, want %q", step.name, flag, got, want+"\n")
+			}
+		}
+		if got := os.Getenv("OLDPWD"); got != step.wantOldPwd {
+			t.Errorf("%v: $OLDPWD = %q, want %q", step.name, got, step.wantOldPwd)
+		}
+	}
+}
+
+func TestPwd_invalidArgs(t *testing.T) {
+	t.Parallel()
+	if err := builtins.Pwd(&bytes.Buffer{}, "-x"); !errors.Is(err, builtins.ErrInvalidArgs) {
+		t.Fatalf("Pwd() error = %v, wantErr %v", err, builtins.ErrInvalidArgs)
+	}
+}
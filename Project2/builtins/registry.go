@@ -0,0 +1,261 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Func is the implementation of a builtin. args excludes the command name.
+type Func func(ctx *Context, args ...string) error
+
+// Meta describes a builtin for "help" and "type".
+type Meta struct {
+	Name     string   // set by Register
+	Synopsis string   // usage line, e.g. "head [-n N] [FILE...]"
+	Summary  string   // one-line description
+	Flags    []string // "FLAG\tdescription" pairs
+	// Paged is set for builtins printing listings that may not fit on the
+	// screen, which the shell pages under set -o autopage.
+	Paged bool
+	// Manual is the longer documentation man shows, in Markdown. Register
+	// fills it in from the generated manual pages if it is empty.
+	Manual string
+}
+
+// Command is a registered builtin.
+type Command struct {
+	Meta
+	Fn Func
+}
+
+// Context is the shell as seen by a running builtin.
+type Context struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Runner runs another command, builtin or external, with ctx's streams.
+	Runner func(ctx *Context, name string, args ...string) error
+	// FunctionLookup reports whether name is a shell function, for builtins
+	// such as type that resolve command names.
+	FunctionLookup func(name string) bool
+	// Functions returns the definitions of the shell's functions by name,
+	// as source that Eval defines them again from, for builtins such as
+	// session.
+	Functions func() map[string]string
+	// Evaluator expands and runs a command line, updating $?.
+	Evaluator func(ctx *Context, line string) error
+	// Exit asks the shell to exit with status once the current command returns.
+	Exit func(status int)
+	// Vars holds the shell's variables, for builtins such as let.
+	Vars Variables
+	// Options holds the shell options changed by set.
+	Options Options
+	// Jobs is the shell's table of background jobs, for builtins such as
+	// kill that list or signal them.
+	Jobs JobTable
+	// Started, if set, is told about each external process a command starts,
+	// so that the shell can track the processes of background jobs.
+	Started func(p *os.Process)
+	// Files are open files that external commands inherit at the same
+	// descriptor numbers, such as the pipes of process substitutions.
+	Files []*os.File
+	// FS is the file system builtins such as ls and rm work on, if not the
+	// operating system's.
+	FS FileSystem
+	// Ctx is cancelled when the command should stop: on Ctrl-C, or when the
+	// shell shuts down. If nil, it never is.
+	Ctx context.Context
+	// Env, if not nil, is the environment commands run with instead of the
+	// shell's, as NAME=VALUE strings, as env sets it up.
+	Env []string
+}
+
+// Variables are shell variables. Names not set in the shell fall back to the
+// environment.
+type Variables interface {
+	Get(name string) (string, bool)
+	Set(name, value string) error
+	// Names returns the names of the variables set in the shell, not those
+	// only in the environment, in sorted order.
+	Names() []string
+}
+
+// Options are the shell's named options, such as errexit for set -e.
+type Options interface {
+	Option(name string) (on, ok bool)
+	SetOption(name string, on bool) error
+	OptionNames() []string
+}
+
+// JobTable is the shell's table of background jobs.
+type JobTable interface {
+	// Jobs returns the jobs in the table, oldest first; the last is the
+	// current job.
+	Jobs() []Job
+	// Lookup finds the job named by a job spec: %N, %% or %+ (the current
+	// job), %- (the previous one), %PREFIX or %?TEXT.
+	Lookup(spec string) (Job, error)
+}
+
+// Job describes a background job as it was when it was looked up.
+type Job struct {
+	ID      int
+	Command string
+	PIDs    []int // the external processes it has started, in order
+	Done    bool
+	Status  int // the exit status, once Done
+}
+
+// Run runs another command in the same shell with ctx's streams. It is a
+// CommandRunner for builtins such as xargs and find.
+func (ctx *Context) Run(name string, args ...string) error {
+	if ctx.Runner == nil {
+		return fmt.Errorf("%w: no command runner", ErrInvalidArgs)
+	}
+
+	return ctx.Runner(ctx, name, args...)
+}
+
+// IsFunction reports whether name is a function of the shell, which runs
+// instead of any builtin or program of that name.
+func (ctx *Context) IsFunction(name string) bool {
+	return ctx.FunctionLookup != nil && ctx.FunctionLookup(name)
+}
+
+// Eval runs a command line in the same shell with ctx's streams, as source
+// and trap do.
+func (ctx *Context) Eval(line string) error {
+	if ctx.Evaluator == nil {
+		return fmt.Errorf("%w: no command line evaluator", ErrInvalidArgs)
+	}
+
+	return ctx.Evaluator(ctx, line)
+}
+
+// WithStdin returns a copy of ctx reading from r.
+func (ctx *Context) WithStdin(r io.Reader) *Context {
+	c := *ctx
+	c.Stdin = r
+
+	return &c
+}
+
+// Context returns the context the command runs in: Ctx, or else one that is
+// never cancelled.
+func (ctx *Context) Context() context.Context {
+	if ctx.Ctx == nil {
+		return context.Background()
+	}
+
+	return ctx.Ctx
+}
+
+// WithContext returns a copy of ctx running in c.
+func (ctx *Context) WithContext(c context.Context) *Context {
+	cp := *ctx
+	cp.Ctx = c
+
+	return &cp
+}
+
+// WithEnv returns a copy of ctx running commands with the environment env.
+func (ctx *Context) WithEnv(env []string) *Context {
+	c := *ctx
+	c.Env = env
+
+	return &c
+}
+
+// Getenv returns the value of an environment variable.
+func (ctx *Context) Getenv(key string) string {
+	if ctx.Env != nil {
+		value, _ := lookupEnv(ctx.Env, key)
+		return value
+	}
+
+	return os.Getenv(key)
+}
+
+// LookupEnv returns the value of an environment variable and whether it is
+// set.
+func (ctx *Context) LookupEnv(key string) (string, bool) {
+	if ctx.Env != nil {
+		return lookupEnv(ctx.Env, key)
+	}
+
+	return os.LookupEnv(key)
+}
+
+// Environ returns the environment as KEY=value strings.
+func (ctx *Context) Environ() []string {
+	if ctx.Env != nil {
+		return append([]string(nil), ctx.Env...)
+	}
+
+	return os.Environ()
+}
+
+// FileSystem returns the file system builtins work on: FS, or else the
+// operating system's.
+func (ctx *Context) FileSystem() FileSystem {
+	if ctx.FS == nil {
+		return OSFileSystem
+	}
+
+	return ctx.FS
+}
+
+// Getwd returns the working directory.
+func (ctx *Context) Getwd() (string, error) {
+	return os.Getwd()
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Command{}
+)
+
+// Register adds a builtin, replacing any earlier one with the same name.
+// Packages providing extra builtins call it from init.
+func Register(name string, fn Func, meta Meta) {
+	meta.Name = name
+	if meta.Manual == "" {
+		meta.Manual = manual(name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = Command{Meta: meta, Fn: fn}
+}
+
+// Lookup returns the builtin registered as name.
+func Lookup(name string) (Command, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	cmd, ok := registry[name]
+
+	return cmd, ok
+}
+
+// IsBuiltin reports whether the shell runs name itself.
+func IsBuiltin(name string) bool {
+	_, ok := Lookup(name)
+	return ok
+}
+
+// Names returns the builtin names in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
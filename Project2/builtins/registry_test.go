@@ -0,0 +1,44 @@
+package builtins_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	builtins.Register("test-greet", func(ctx *builtins.Context, args ...string) error {
+		return ctx.Run("echo", append([]string{"hello"}, args...)...)
+	}, builtins.Meta{Synopsis: "test-greet [NAME]", Summary: "greet someone"})
+
+	cmd, ok := builtins.Lookup("test-greet")
+	if !ok || cmd.Name != "test-greet" || !builtins.IsBuiltin("test-greet") {
+		t.Fatalf("Lookup() got = %+v, %v", cmd.Meta, ok)
+	}
+
+	var out bytes.Buffer
+	ctx := &builtins.Context{
+		Stdin:  strings.NewReader(""),
+		Stdout: &out,
+		Runner: func(ctx *builtins.Context, name string, args ...string) error {
+			echo, _ := builtins.Lookup(name)
+			return echo.Fn(ctx, args...)
+		},
+	}
+	if err := cmd.Fn(ctx, "world"); err != nil {
+		t.Fatalf("Fn() unexpected error: %v", err)
+	}
+	if want := "hello world\n"; out.String() != want {
+		t.Errorf("Fn() got = %q, want %q", out.String(), want)
+	}
+}
+
+func TestContext_Run(t *testing.T) {
+	t.Parallel()
+	if err := (&builtins.Context{}).Run("echo"); err == nil {
+		t.Errorf("Run() expected an error without a Runner")
+	}
+}
@@ -0,0 +1,72 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func init() {
+	Register("renice", func(ctx *Context, args ...string) error {
+		return Renice(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "renice [-n] N [-p] PID...",
+		Summary:  "change the scheduling priority of running processes",
+		Flags: []string{
+			"-n N\tthe new niceness, from -20 (most favoured) to 19",
+			"-p PID\tthe process to change; the flag is optional",
+		},
+	})
+}
+
+// Renice handles the "renice" built-in command.
+// It sets the niceness of each process to N and reports the old and new
+// values. Only a privileged user may lower it, making a process more
+// favoured by the scheduler.
+func Renice(w io.Writer, args ...string) error {
+	if len(args) > 0 && args[0] == "-n" {
+		args = args[1:]
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: expected a priority and a process ID", ErrInvalidArgCount)
+	}
+	prio, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: invalid priority %q", ErrInvalidArgs, args[0])
+	}
+
+	var pids []int
+	for _, arg := range args[1:] {
+		if arg == "-p" {
+			continue
+		}
+		pid, err := strconv.Atoi(arg)
+		if err != nil || pid <= 0 {
+			return fmt.Errorf("%w: invalid process ID %q", ErrInvalidArgs, arg)
+		}
+		pids = append(pids, pid)
+	}
+	if len(pids) == 0 {
+		return fmt.Errorf("%w: expected a process ID", ErrInvalidArgCount)
+	}
+
+	for _, pid := range pids {
+		old, err := Niceness(pid)
+		if err == nil {
+			err = SetNiceness(pid, prio)
+		}
+		if err != nil {
+			return fmt.Errorf("renice: %d: %w", pid, err)
+		}
+		// The system clamps the value, so report what it was set to.
+		now, err := Niceness(pid)
+		if err != nil {
+			now = prio
+		}
+		if _, err := fmt.Fprintf(w, "%d (process ID) old priority %d, new priority %d\n", pid, old, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
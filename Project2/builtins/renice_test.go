@@ -0,0 +1,64 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestRenice(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("no niceness on windows")
+	}
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("needs sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	pid := cmd.Process.Pid
+	old, err := builtins.Niceness(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var w bytes.Buffer
+	if err := builtins.Renice(&w, "-n", "15", "-p", fmt.Sprint(pid)); err != nil {
+		t.Fatalf("Renice() error = %v", err)
+	}
+	want := fmt.Sprintf("%d (process ID) old priority %d, new priority 15\n", pid, old)
+	if got := w.String(); got != want {
+		t.Errorf("Renice() got = %q, want %q", got, want)
+	}
+	if got, err := builtins.Niceness(pid); err != nil || got != 15 {
+		t.Errorf("Niceness() = %d, %v, want 15", got, err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "no pid", args: []string{"5"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "only -p", args: []string{"5", "-p"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "bad priority", args: []string{"high", "1"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "bad pid", args: []string{"5", "-p", "init"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := builtins.Renice(&bytes.Buffer{}, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Renice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package builtins
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+func init() {
+	Register("rm", func(ctx *Context, args ...string) error {
+		return Remove(ctx.FileSystem(), ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "rm [-fir] FILE...",
+		Summary:  "remove files",
+		Flags: []string{
+			"-f\tignore missing files, never prompt",
+			"-i\tprompt before each removal",
+			"-r\tremove directories recursively",
+		},
+	})
+}
+
+// Remove handles the "rm" built-in command.
+// Directories are only removed with -r. With -f missing files are ignored,
+// and with -i each removal is confirmed by reading a y/n answer from r.
+func Remove(fsys FileSystem, r io.Reader, w io.Writer, args ...string) error {
+	var (
+		recursive, force, interactive bool
+		files                         = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'r', 'R':
+				recursive = true
+			case 'f':
+				force = true
+			case 'i':
+				interactive = true
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if len(files) == 0 && !force {
+		return fmt.Errorf("%w: expected at least one file", ErrInvalidArgCount)
+	}
+
+	answers := bufio.NewReader(r)
+	for _, file := range files {
+		if clean := filepath.Clean(file); clean == "/" || clean == "." || clean == ".." {
+			return fileError("rm", file, fmt.Errorf("%w: refusing to remove", ErrInvalidArgs))
+		}
+		info, err := fsys.Lstat(file)
+		if err != nil {
+			if force && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fileError("rm", file, err)
+		}
+		if info.IsDir() && !recursive {
+			return fileError("rm", file, syscall.EISDIR)
+		}
+		if interactive {
+			ok, err := confirm(answers, w, fmt.Sprintf("rm: remove %q? ", file))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if info.IsDir() {
+			err = fsys.RemoveAll(file)
+		} else {
+			err = fsys.Remove(file)
+		}
+		if err != nil {
+			return fileError("rm", file, err)
+		}
+	}
+
+	return nil
+}
+
+// confirm writes prompt to w and reports whether the answer read from r starts with y.
+func confirm(r *bufio.Reader, w io.Writer, prompt string) (bool, error) {
+	if _, err := io.WriteString(w, prompt); err != nil {
+		return false, err
+	}
+	answer, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y"), nil
+}
@@ -0,0 +1,128 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		setup    []string // files to create; names ending in / are directories.
+		args     []string
+		stdin    string
+		prompts  int
+		wantGone []string
+		wantKept []string
+		wantErr  error
+	}{
+		{
+			name:     "removes files",
+			setup:    []string{"a", "b"},
+			args:     []string{"a", "b"},
+			wantGone: []string{"a", "b"},
+		},
+		{
+			name:     "directory needs -r",
+			setup:    []string{"dir/", "dir/file"},
+			args:     []string{"dir"},
+			wantKept: []string{"dir/file"},
+			wantErr:  syscall.EISDIR,
+		},
+		{
+			name:     "recursive",
+			setup:    []string{"dir/", "dir/file"},
+			args:     []string{"-r", "dir"},
+			wantGone: []string{"dir"},
+		},
+		{
+			name:    "missing file",
+			args:    []string{"nope"},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name: "force ignores missing files",
+			args: []string{"-f", "nope"},
+		},
+		{
+			name:     "interactive asks for each file",
+			setup:    []string{"keep", "drop"},
+			args:     []string{"-i", "keep", "drop"},
+			stdin:    "n\ny\n",
+			prompts:  2,
+			wantGone: []string{"drop"},
+			wantKept: []string{"keep"},
+		},
+		{
+			name:    "refuses dot",
+			args:    []string{"-rf", "."},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "no files",
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmp := t.TempDir()
+			abs := func(name string) string {
+				if name == "." {
+					return name
+				}
+				return filepath.Join(tmp, name)
+			}
+			for _, name := range tt.setup {
+				var err error
+				if strings.HasSuffix(name, "/") {
+					err = os.Mkdir(abs(name), 0o755)
+				} else {
+					err = os.WriteFile(abs(name), nil, 0o644)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				if !strings.HasPrefix(arg, "-") {
+					arg = abs(arg)
+				}
+				args[i] = arg
+			}
+
+			var out bytes.Buffer
+			err := builtins.Remove(builtins.OSFileSystem, strings.NewReader(tt.stdin), &out, args...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Remove() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Remove() unexpected error: %v", err)
+			}
+			if got := strings.Count(out.String(), "rm: remove"); got != tt.prompts {
+				t.Errorf("Remove() prompted %v times, want %v", got, tt.prompts)
+			}
+			for _, name := range tt.wantGone {
+				if _, err := os.Lstat(abs(name)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("Remove() left %v behind", name)
+				}
+			}
+			for _, name := range tt.wantKept {
+				if _, err := os.Lstat(abs(name)); err != nil {
+					t.Errorf("Remove() removed %v", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package builtins
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func init() {
+	Register("rmdir", func(ctx *Context, args ...string) error {
+		return RemoveDirectory(args...)
+	}, Meta{
+		Synopsis: "rmdir DIR...",
+		Summary:  "remove empty directories",
+	})
+	RegisterCompleter("rmdir", CompleteDirs)
+}
+
+// RemoveDirectory handles the "rmdir" built-in command.
+// It removes each named directory, which must be empty.
+func RemoveDirectory(args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected at least one directory", ErrInvalidArgCount)
+	}
+
+	for _, dir := range args {
+		info, err := os.Lstat(dir)
+		if err != nil {
+			return fileError("rmdir", dir, err)
+		}
+		if !info.IsDir() {
+			return fileError("rmdir", dir, syscall.ENOTDIR)
+		}
+		if err := os.Remove(dir); err != nil {
+			return fileError("rmdir", dir, err)
+		}
+	}
+
+	return nil
+}
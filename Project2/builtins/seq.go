@@ -0,0 +1,130 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("seq", func(ctx *Context, args ...string) error {
+		return Seq(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "seq [-w] [-s SEP] [FIRST [INCR]] LAST",
+		Summary:  "print a sequence of numbers",
+		Flags: []string{
+			"-s SEP\tseparate the numbers with SEP (default newline)",
+			"-w\tpad the numbers with zeros to the same width",
+		},
+	})
+}
+
+// Seq handles the "seq" built-in command.
+// It prints the numbers from FIRST (default 1) to LAST in steps of INCR
+// (default 1), which may be negative. Numbers may have decimals; all are
+// printed with as many decimals as FIRST or INCR has.
+func Seq(w io.Writer, args ...string) error {
+	sep, pad := "\n", false
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' && !isNumberStart(args[0][1]) {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		switch {
+		case arg == "-w":
+			pad = true
+		case arg == "-s":
+			if len(args) == 0 {
+				return fmt.Errorf("%w: -s requires an argument", ErrInvalidArgCount)
+			}
+			sep, args = args[0], args[1:]
+		case strings.HasPrefix(arg, "-s"):
+			sep = arg[2:]
+		default:
+			return fmt.Errorf("%w: seq: invalid option %v", ErrInvalidArgs, arg)
+		}
+	}
+
+	nums := []string{"1", "1", ""}
+	switch len(args) {
+	case 1:
+	case 2:
+		nums[0] = args[0]
+	case 3:
+		nums[0], nums[1] = args[0], args[1]
+	default:
+		return fmt.Errorf("%w: expected [FIRST [INCR]] LAST", ErrInvalidArgCount)
+	}
+	nums[2] = args[len(args)-1]
+
+	decimals := 0
+	for _, n := range nums[:2] {
+		if _, frac, ok := strings.Cut(n, "."); ok && len(frac) > decimals {
+			decimals = len(frac)
+		}
+	}
+	scale := math.Pow10(decimals)
+	var vals [3]int64
+	for i, n := range nums {
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("%w: seq: invalid number %q", ErrInvalidArgs, n)
+		}
+		vals[i] = int64(math.Round(f * scale))
+	}
+	first, incr, last := vals[0], vals[1], vals[2]
+	if incr == 0 {
+		return fmt.Errorf("%w: seq: increment must not be zero", ErrInvalidArgs)
+	}
+	if (incr > 0 && first > last) || (incr < 0 && first < last) {
+		return nil
+	}
+	// Counting steps rather than adding INCR keeps LAST out of rounding trouble.
+	steps := (last - first) / incr
+	format := func(v int64) string {
+		return strconv.FormatFloat(float64(v)/scale, 'f', decimals, 64)
+	}
+	width := 0
+	if pad {
+		width = len(format(first))
+		if n := len(format(first + steps*incr)); n > width {
+			width = n
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i := int64(0); i <= steps; i++ {
+		if i > 0 {
+			_, _ = bw.WriteString(sep)
+		}
+		// Writing fails once nothing reads the output, as in "seq 1e9 | head".
+		if _, err := bw.WriteString(zeroPad(format(first+i*incr), width)); err != nil {
+			return err
+		}
+	}
+	_, _ = bw.WriteString("\n")
+
+	return bw.Flush()
+}
+
+// isNumberStart reports whether c can follow the - of a negative number.
+func isNumberStart(c byte) bool {
+	return c >= '0' && c <= '9' || c == '.'
+}
+
+// zeroPad pads a number with zeros after its sign up to width characters.
+func zeroPad(s string, width int) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	for len(sign)+len(s) < width {
+		s = "0" + s
+	}
+
+	return sign + s
+}
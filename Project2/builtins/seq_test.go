@@ -0,0 +1,49 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestSeq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{name: "last", args: []string{"3"}, want: "1\n2\n3\n"},
+		{name: "first last", args: []string{"2", "4"}, want: "2\n3\n4\n"},
+		{name: "step", args: []string{"1", "3", "10"}, want: "1\n4\n7\n10\n"},
+		{name: "down", args: []string{"3", "-1", "1"}, want: "3\n2\n1\n"},
+		{name: "negative first", args: []string{"-1", "1"}, want: "-1\n0\n1\n"},
+		{name: "empty", args: []string{"5", "1"}, want: ""},
+		{name: "decimals", args: []string{"0", "0.1", "0.3"}, want: "0.0\n0.1\n0.2\n0.3\n"},
+		{name: "pad", args: []string{"-w", "8", "10"}, want: "08\n09\n10\n"},
+		{name: "pad negative", args: []string{"-w", "-10", "5", "0"}, want: "-10\n-05\n000\n"},
+		{name: "separator", args: []string{"-s", ",", "3"}, want: "1,2,3\n"},
+		{name: "attached separator", args: []string{"-s:", "-w", "9", "10"}, want: "09:10\n"},
+		{name: "zero step", args: []string{"1", "0", "2"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "not a number", args: []string{"x"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "unknown flag", args: []string{"-q", "1"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "no args", wantErr: builtins.ErrInvalidArgCount},
+		{name: "too many args", args: []string{"1", "2", "3", "4"}, wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := builtins.Seq(&w, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Seq() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("Seq() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
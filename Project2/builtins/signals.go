@@ -0,0 +1,60 @@
+package builtins
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// signalNames maps signal names, without the SIG prefix, to signals.
+var signalNames = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"ILL":  syscall.SIGILL,
+	"TRAP": syscall.SIGTRAP,
+	"ABRT": syscall.SIGABRT,
+	"BUS":  syscall.SIGBUS,
+	"FPE":  syscall.SIGFPE,
+	"KILL": syscall.SIGKILL,
+	"SEGV": syscall.SIGSEGV,
+	"PIPE": syscall.SIGPIPE,
+	"ALRM": syscall.SIGALRM,
+	"TERM": syscall.SIGTERM,
+}
+
+// parseSignal accepts a signal number or name, with or without the SIG prefix, in any case.
+func parseSignal(s string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return syscall.Signal(n), nil
+	}
+	if sig, ok := signalNames[strings.TrimPrefix(strings.ToUpper(s), "SIG")]; ok {
+		return sig, nil
+	}
+
+	return 0, fmt.Errorf("%w: unknown signal %q", ErrInvalidArgs, s)
+}
+
+// signalName returns the name of sig without the SIG prefix, or its number if it has none.
+func signalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return name
+		}
+	}
+
+	return strconv.Itoa(int(sig))
+}
+
+// sortedSignals returns the known signals in numeric order.
+func sortedSignals() []syscall.Signal {
+	sigs := make([]syscall.Signal, 0, len(signalNames))
+	for _, sig := range signalNames {
+		sigs = append(sigs, sig)
+	}
+	sort.Slice(sigs, func(i, j int) bool { return sigs[i] < sigs[j] })
+
+	return sigs
+}
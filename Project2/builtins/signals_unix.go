@@ -0,0 +1,21 @@
+//go:build !windows
+
+package builtins
+
+import "syscall"
+
+func init() {
+	for name, sig := range map[string]syscall.Signal{
+		"USR1":  syscall.SIGUSR1,
+		"USR2":  syscall.SIGUSR2,
+		"CHLD":  syscall.SIGCHLD,
+		"CONT":  syscall.SIGCONT,
+		"STOP":  syscall.SIGSTOP,
+		"TSTP":  syscall.SIGTSTP,
+		"TTIN":  syscall.SIGTTIN,
+		"TTOU":  syscall.SIGTTOU,
+		"WINCH": syscall.SIGWINCH,
+	} {
+		signalNames[name] = sig
+	}
+}
@@ -0,0 +1,75 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("sleep", func(ctx *Context, args ...string) error {
+		return Sleep(ctx.Context(), args...)
+	}, Meta{
+		Synopsis: "sleep DURATION",
+		Summary:  "pause for seconds or a duration like 1m30s",
+	})
+}
+
+// Sleep handles the "sleep" built-in command.
+// Each argument is a number of seconds (fractions allowed), optionally suffixed with
+// s, m, h or d, or a Go duration such as 500ms; the durations are added together.
+// Ctrl-C ends the sleep early.
+func Sleep(ctx context.Context, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected a duration", ErrInvalidArgCount)
+	}
+	var total time.Duration
+	for _, arg := range args {
+		d, err := parseDuration(arg)
+		if err != nil {
+			return err
+		}
+		total += d
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	timer := time.NewTimer(total)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return &ExitError{Status: StatusInterrupted, Err: ErrInterrupted}
+	}
+}
+
+// parseDuration parses sleep/timeout durations: plain or suffixed (s, m, h, d) seconds, or a Go duration.
+func parseDuration(s string) (time.Duration, error) {
+	unit := time.Second
+	number := s
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 's':
+			number = s[:n-1]
+		case 'm':
+			number, unit = s[:n-1], time.Minute
+		case 'h':
+			number, unit = s[:n-1], time.Hour
+		case 'd':
+			number, unit = s[:n-1], 24*time.Hour
+		}
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil && f >= 0 && !strings.ContainsAny(number, "eEnN") {
+		return time.Duration(f * float64(unit)), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	return 0, fmt.Errorf("%w: invalid duration %q", ErrInvalidArgs, s)
+}
@@ -0,0 +1,109 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_parseDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr error
+	}{
+		{in: "2", want: 2 * time.Second},
+		{in: "0.25", want: 250 * time.Millisecond},
+		{in: "1.5s", want: 1500 * time.Millisecond},
+		{in: "2m", want: 2 * time.Minute},
+		{in: "1h", want: time.Hour},
+		{in: "0.5d", want: 12 * time.Hour},
+		{in: "500ms", want: 500 * time.Millisecond},
+		{in: "1m30s", want: 90 * time.Second},
+		{in: "-1", wantErr: ErrInvalidArgs},
+		{in: "soon", wantErr: ErrInvalidArgs},
+		{in: "inf", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseDuration(tt.in)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("parseDuration() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("parseDuration() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	if err := Sleep(context.Background(), "10ms", "0.01"); err != nil {
+		t.Fatalf("Sleep() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Sleep() returned after %v, want at least 20ms", elapsed)
+	}
+	if err := Sleep(context.Background()); !errors.Is(err, ErrInvalidArgCount) {
+		t.Errorf("Sleep() error = %v, wantErr %v", err, ErrInvalidArgCount)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := Sleep(ctx, "10s"); StatusOf(err) != StatusInterrupted {
+		t.Errorf("Sleep() cancelled error = %v, want status %v", err, StatusInterrupted)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantErr    error
+	}{
+		{name: "finishes in time", args: []string{"5", "true"}},
+		{name: "keeps command status", args: []string{"5", "sh", "-c", "exit 3"}, wantStatus: 3},
+		{name: "times out", args: []string{"0.05", "sleep", "5"}, wantStatus: StatusTimedOut, wantErr: ErrTimedOut},
+		{name: "custom signal", args: []string{"-s", "KILL", "0.05", "sleep", "5"}, wantStatus: StatusTimedOut, wantErr: ErrTimedOut},
+		{name: "kill after ignored signal", args: []string{"-k", "0.05", "0.05", "sh", "-c", "trap '' TERM; exec sleep 5"}, wantStatus: StatusTimedOut, wantErr: ErrTimedOut},
+		{name: "unknown signal", args: []string{"-s", "NOPE", "1", "true"}, wantErr: ErrInvalidArgs},
+		{name: "missing command", args: []string{"1"}, wantErr: ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := Timeout(context.Background(), nil, tt.args...)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Timeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			status := 0
+			var exitErr *ExitError
+			var cmdErr interface{ ExitCode() int }
+			switch {
+			case errors.As(err, &exitErr):
+				status = exitErr.Status
+			case errors.As(err, &cmdErr):
+				status = cmdErr.ExitCode()
+			case err != nil && tt.wantErr == nil:
+				t.Fatalf("Timeout() unexpected error: %v", err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Timeout() status = %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
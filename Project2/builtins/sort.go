@@ -0,0 +1,198 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sortOptions are the flags accepted by sort.
+type sortOptions struct {
+	reverse  bool
+	numeric  bool
+	unique   bool
+	keyStart int // 1-based first field of the key, 0 for the whole line.
+	keyEnd   int // 1-based last field of the key, 0 for the end of the line.
+}
+
+func init() {
+	Register("sort", func(ctx *Context, args ...string) error {
+		return Sort(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "sort [-nru] [-k N[,M]] [FILE...]",
+		Summary:  "sort lines",
+		Flags: []string{
+			"-k N[,M]\tsort on fields N to M",
+			"-n\tcompare numerically",
+			"-r\treverse the order",
+			"-u\tdrop duplicate lines",
+		},
+	})
+}
+
+// Sort handles the "sort" built-in command.
+// It sorts the lines of the named files, or of r when no file (or "-") is given.
+func Sort(r io.Reader, w io.Writer, args ...string) error {
+	var (
+		opts  sortOptions
+		files = make([]string, 0)
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+	flags:
+		for j := 1; j < len(arg); j++ {
+			switch arg[j] {
+			case 'r':
+				opts.reverse = true
+			case 'n':
+				opts.numeric = true
+			case 'u':
+				opts.unique = true
+			case 'k':
+				key := arg[j+1:]
+				if key == "" {
+					if len(args) < i+2 {
+						return fmt.Errorf("%w: -k requires an argument", ErrInvalidArgCount)
+					}
+					key = args[i+1]
+					i++
+				}
+				if err := opts.parseKey(key); err != nil {
+					return err
+				}
+				break flags
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, arg[j])
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+	for _, name := range files {
+		if err := withInput(r, name, func(in io.Reader) error {
+			read, err := readLines(in)
+			lines = append(lines, read...)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		c := opts.compare(lines[i], lines[j])
+		if c == 0 {
+			// Last-resort comparison of whole lines keeps the output deterministic.
+			c = strings.Compare(lines[i], lines[j])
+		}
+		if opts.reverse {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	for i, line := range lines {
+		if opts.unique && i > 0 && opts.compare(lines[i-1], line) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseKey parses a -k argument of the form N or N,M.
+func (o *sortOptions) parseKey(key string) error {
+	start, end, hasEnd := strings.Cut(key, ",")
+	var err error
+	if o.keyStart, err = strconv.Atoi(start); err != nil || o.keyStart < 1 {
+		return fmt.Errorf("%w: invalid key %q", ErrInvalidArgs, key)
+	}
+	o.keyEnd = 0
+	if hasEnd {
+		if o.keyEnd, err = strconv.Atoi(end); err != nil || o.keyEnd < o.keyStart {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidArgs, key)
+		}
+	}
+
+	return nil
+}
+
+// key extracts the sort key of a line.
+func (o *sortOptions) key(line string) string {
+	if o.keyStart == 0 {
+		return line
+	}
+	fields := strings.Fields(line)
+	if o.keyStart > len(fields) {
+		return ""
+	}
+	end := len(fields)
+	if o.keyEnd != 0 && o.keyEnd < end {
+		end = o.keyEnd
+	}
+
+	return strings.Join(fields[o.keyStart-1:end], " ")
+}
+
+// compare orders two lines by their keys, numerically when -n is set.
+func (o *sortOptions) compare(a, b string) int {
+	ka, kb := o.key(a), o.key(b)
+	if !o.numeric {
+		return strings.Compare(ka, kb)
+	}
+	na, nb := leadingNumber(ka), leadingNumber(kb)
+	switch {
+	case na < nb:
+		return -1
+	case na > nb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// leadingNumber parses the number at the start of s, treating anything non-numeric as zero.
+func leadingNumber(s string) float64 {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || end == 0 && (s[end] == '-' || s[end] == '+')) {
+		end++
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// readLines reads every line of r without its trailing newline.
+func readLines(r io.Reader) ([]string, error) {
+	var (
+		lines = make([]string, 0)
+		br    = bufio.NewReader(r)
+	)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return lines, err
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "lexical",
+			stdin:   "pear\napple\nfig\n",
+			wantOut: "apple\nfig\npear\n",
+		},
+		{
+			name:    "numeric reverse",
+			stdin:   "10\n9\n100\n-1\n",
+			args:    []string{"-rn"},
+			wantOut: "100\n10\n9\n-1\n",
+		},
+		{
+			name:    "unique",
+			stdin:   "b\na\nb\na\n",
+			args:    []string{"-u"},
+			wantOut: "a\nb\n",
+		},
+		{
+			name:    "field key",
+			stdin:   "bob 30\nalice 25\ncarol 27\n",
+			args:    []string{"-n", "-k", "2"},
+			wantOut: "alice 25\ncarol 27\nbob 30\n",
+		},
+		{
+			name:    "attached field key",
+			stdin:   "x b 1\ny a 2\n",
+			args:    []string{"-k2,2"},
+			wantOut: "y a 2\nx b 1\n",
+		},
+		{
+			name:    "missing key",
+			args:    []string{"-k"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+		{
+			name:    "bad key",
+			args:    []string{"-k", "0"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Sort(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Sort() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Sort() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Sort() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestUniq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "collapses adjacent lines",
+			stdin:   "a\na\nb\na\n",
+			wantOut: "a\nb\na\n",
+		},
+		{
+			name:    "counts",
+			stdin:   "a\na\nb\n",
+			args:    []string{"-c"},
+			wantOut: "      2 a\n      1 b\n",
+		},
+		{
+			name:    "duplicates only",
+			stdin:   "a\na\nb\nc\nc\n",
+			args:    []string{"-d"},
+			wantOut: "a\nc\n",
+		},
+		{
+			name:    "too many files",
+			args:    []string{"a", "b"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Uniq(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Uniq() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Uniq() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Uniq() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"time"
+)
+
+// statDetails holds the metadata that only the platform's stat structure provides.
+type statDetails struct {
+	inode, links      uint64
+	uid, gid          uint32
+	atime, ctime      time.Time
+	blocks, blockSize int64
+}
+
+func init() {
+	Register("stat", func(ctx *Context, args ...string) error {
+		return Stat(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "stat FILE...",
+		Summary:  "print file metadata",
+	})
+}
+
+// Stat handles the "stat" built-in command.
+// It prints the size, type, mode, owner, inode, link count, timestamps and
+// (for symbolic links) the link target of each file.
+func Stat(w io.Writer, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected at least one file", ErrInvalidArgCount)
+	}
+
+	for _, file := range args {
+		info, err := os.Lstat(file)
+		if err != nil {
+			return fileError("stat", file, err)
+		}
+
+		name := file
+		if info.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(file)
+			if err != nil {
+				return fileError("stat", file, err)
+			}
+			name = fmt.Sprintf("%v -> %v", file, target)
+		}
+		lines := []string{
+			fmt.Sprintf("  File: %v", name),
+			fmt.Sprintf("  Size: %-10d Type: %v", info.Size(), fileType(info.Mode())),
+		}
+
+		details, ok := sysStat(info)
+		mode := fmt.Sprintf("Access: (%04o/%v)", unixMode(info.Mode()), info.Mode())
+		if ok {
+			lines = append(lines,
+				fmt.Sprintf("Blocks: %-10d IO Block: %-6d Inode: %-10d Links: %d",
+					details.blocks, details.blockSize, details.inode, details.links),
+				fmt.Sprintf("%v  Uid: (%d/%v)  Gid: (%d/%v)",
+					mode, details.uid, userName(details.uid), details.gid, groupName(details.gid)),
+				fmt.Sprintf("Access: %v", details.atime.Format(statTimeFormat)),
+				fmt.Sprintf("Modify: %v", info.ModTime().Format(statTimeFormat)),
+				fmt.Sprintf("Change: %v", details.ctime.Format(statTimeFormat)),
+			)
+		} else {
+			lines = append(lines, mode, fmt.Sprintf("Modify: %v", info.ModTime().Format(statTimeFormat)))
+		}
+
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+const statTimeFormat = "2006-01-02 15:04:05.000000000 -0700"
+
+// fileType describes the kind of file a mode refers to.
+func fileType(m fs.FileMode) string {
+	switch {
+	case m.IsRegular():
+		return "regular file"
+	case m.IsDir():
+		return "directory"
+	case m&fs.ModeSymlink != 0:
+		return "symbolic link"
+	case m&fs.ModeNamedPipe != 0:
+		return "fifo"
+	case m&fs.ModeSocket != 0:
+		return "socket"
+	case m&fs.ModeCharDevice != 0:
+		return "character special file"
+	case m&fs.ModeDevice != 0:
+		return "block special file"
+	default:
+		return "unknown"
+	}
+}
+
+// userName looks up the name of uid, falling back to "UNKNOWN".
+func userName(uid uint32) string {
+	u, err := user.LookupId(fmt.Sprint(uid))
+	if err != nil {
+		return "UNKNOWN"
+	}
+
+	return u.Username
+}
+
+// groupName looks up the name of gid, falling back to "UNKNOWN".
+func groupName(gid uint32) string {
+	g, err := user.LookupGroupId(fmt.Sprint(gid))
+	if err != nil {
+		return "UNKNOWN"
+	}
+
+	return g.Name
+}
@@ -0,0 +1,26 @@
+package builtins
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// sysStat extracts the macOS stat fields that fs.FileInfo does not expose.
+func sysStat(info fs.FileInfo) (statDetails, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return statDetails{}, false
+	}
+
+	return statDetails{
+		inode:     st.Ino,
+		links:     uint64(st.Nlink),
+		uid:       st.Uid,
+		gid:       st.Gid,
+		atime:     time.Unix(st.Atimespec.Unix()),
+		ctime:     time.Unix(st.Ctimespec.Unix()),
+		blocks:    st.Blocks,
+		blockSize: int64(st.Blksize),
+	}, true
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package builtins
+
+import "io/fs"
+
+// sysStat reports that no extra stat fields are available on this platform.
+func sysStat(fs.FileInfo) (statDetails, bool) {
+	return statDetails{}, false
+}
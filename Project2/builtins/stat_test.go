@@ -0,0 +1,85 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestStat(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs symbolic links")
+	}
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "file.txt")
+	if err := os.WriteFile(file, []byte("12345"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(tmp, "link")
+	if err := os.Symlink("file.txt", link); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string // lines or parts of lines the output must contain
+		wantErr error
+	}{
+		{
+			name: "regular file",
+			args: []string{file},
+			want: []string{"  File: " + file + "\n", "  Size: 5          Type: regular file\n", "Access: (0640/-rw-r-----)"},
+		},
+		{
+			name: "directory",
+			args: []string{tmp},
+			want: []string{"  File: " + tmp + "\n", "Type: directory\n"},
+		},
+		{
+			name: "symbolic link",
+			args: []string{link},
+			want: []string{"  File: " + link + " -> file.txt\n", "Type: symbolic link\n"},
+		},
+		{
+			name: "several files",
+			args: []string{file, link},
+			want: []string{"  File: " + file + "\n", "  File: " + link + " -> file.txt\n"},
+		},
+		{
+			name:    "missing file",
+			args:    []string{filepath.Join(tmp, "nope")},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:    "no args",
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.Stat(&out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Stat() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Stat() unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Stat() got %q, want it to contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
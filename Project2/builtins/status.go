@@ -0,0 +1,104 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"syscall"
+)
+
+// Exit statuses with conventional meanings.
+const (
+	StatusSuccess     = 0
+	StatusFailure     = 1
+	StatusUsage       = 2
+	StatusTimedOut    = 124
+	StatusNotFound    = 127
+	StatusSignal      = 128 // plus the signal number
+	StatusInterrupted = StatusSignal + 2
+	StatusBrokenPipe  = StatusSignal + 13
+)
+
+// ErrInterrupted is returned by commands stopped with Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// Interrupted returns the ExitError of a command stopped by Ctrl-C or by the
+// shell shutting down if ctx was cancelled, and err otherwise.
+func Interrupted(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return &ExitError{Status: StatusInterrupted, Err: ErrInterrupted}
+	}
+
+	return err
+}
+
+// isBrokenPipe reports whether err comes from writing to a pipe nothing reads
+// any more, which stops a command as SIGPIPE does a process.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrClosedPipe)
+}
+
+// ExitError reports that a command finished with a specific non-zero exit status.
+type ExitError struct {
+	Status int
+	Err    error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Status)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// StatusOf returns the exit status a command reported through its error:
+// ExitError carries one explicitly, external commands report their own (or
+// 128+N when killed by signal N), usage errors are 2, writing to a broken
+// pipe is 141 as for SIGPIPE and other failures 1.
+func StatusOf(err error) int {
+	var (
+		exitErr *ExitError
+		extErr  *exec.ExitError
+	)
+	switch {
+	case err == nil:
+		return StatusSuccess
+	case errors.As(err, &exitErr):
+		return exitErr.Status
+	case errors.As(err, &extErr):
+		if ws, ok := extErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return StatusSignal + int(ws.Signal())
+		}
+		return extErr.ExitCode()
+	case errors.Is(err, exec.ErrNotFound):
+		return StatusNotFound
+	case errors.Is(err, ErrInvalidArgCount), errors.Is(err, ErrInvalidArgs):
+		return StatusUsage
+	case isBrokenPipe(err):
+		return StatusBrokenPipe
+	default:
+		return StatusFailure
+	}
+}
+
+// IsStatusOnly reports whether err only carries an exit status, with no
+// message worth showing, as when an external command exits non-zero or a
+// command stops at a broken pipe.
+func IsStatusOnly(err error) bool {
+	var (
+		exitErr *ExitError
+		extErr  *exec.ExitError
+	)
+	if errors.As(err, &exitErr) {
+		return exitErr.Err == nil
+	}
+
+	return errors.As(err, &extErr) || isBrokenPipe(err)
+}
@@ -0,0 +1,42 @@
+package builtins
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestStatusOf(t *testing.T) {
+	t.Parallel()
+	falseErr := exec.Command("false").Run()
+	tests := []struct {
+		name       string
+		err        error
+		want       int
+		statusOnly bool
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "builtin status", err: fmt.Errorf("wrapped: %w", &ExitError{Status: 124}), want: 124, statusOnly: true},
+		{name: "builtin status with message", err: &ExitError{Status: 130, Err: ErrInterrupted}, want: 130},
+		{name: "external command", err: falseErr, want: 1, statusOnly: true},
+		{name: "usage", err: fmt.Errorf("%w: bad flag", ErrInvalidArgs), want: 2},
+		{name: "not found", err: &exec.Error{Name: "nope", Err: exec.ErrNotFound}, want: 127},
+		{name: "broken pipe", err: &os.PathError{Op: "write", Path: "|1", Err: syscall.EPIPE}, want: 141, statusOnly: true},
+		{name: "other", err: errors.New("boom"), want: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := StatusOf(tt.err); got != tt.want {
+				t.Errorf("StatusOf() got = %v, want %v", got, tt.want)
+			}
+			if got := IsStatusOnly(tt.err); got != tt.statusOnly {
+				t.Errorf("IsStatusOnly() got = %v, want %v", got, tt.statusOnly)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package builtins
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// FollowInterval is how often "tail -f" polls followed files for new data.
+var FollowInterval = 250 * time.Millisecond
+
+func init() {
+	Register("tail", func(ctx *Context, args ...string) error {
+		return Tail(ctx.Context(), ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "tail [-f] [-n N] [FILE...]",
+		Summary:  "print the last lines of files",
+		Flags: []string{
+			"-f\tkeep printing appended data",
+			"-n N\tprint N lines (default 10)",
+		},
+	})
+}
+
+// Tail handles the "tail" built-in command.
+// It prints the last N lines of each file, or of r when no file (or "-") is given.
+// With -f it keeps printing data appended to the files until interrupted.
+func Tail(ctx context.Context, r io.Reader, w io.Writer, args ...string) error {
+	var (
+		n      = DefaultLineCount
+		follow bool
+		files  = make([]string, 0)
+	)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-f":
+			follow = true
+		case args[i] == "-n":
+			if len(args) < i+2 {
+				return fmt.Errorf("%w: -n requires an argument", ErrInvalidArgCount)
+			}
+			count, err := parseLineCount(args[i+1])
+			if err != nil {
+				return err
+			}
+			n = count
+			i++
+		case strings.HasPrefix(args[i], "-n"):
+			count, err := parseLineCount(strings.TrimPrefix(args[i], "-n"))
+			if err != nil {
+				return err
+			}
+			n = count
+		default:
+			files = append(files, args[i])
+		}
+	}
+
+	if len(files) == 0 {
+		return tailLines(r, w, n)
+	}
+	var followed []*followedFile
+	if follow {
+		// Open before printing so nothing appended in between is missed.
+		var err error
+		if followed, err = openFollowed(files...); err != nil {
+			return err
+		}
+		defer closeFollowed(followed)
+	}
+	for i, name := range files {
+		if len(files) > 1 {
+			if err := printFileHeader(w, name, i == 0); err != nil {
+				return err
+			}
+		}
+		if err := withInput(r, name, func(in io.Reader) error {
+			return tailLines(in, w, n)
+		}); err != nil {
+			return err
+		}
+	}
+	if !follow {
+		return nil
+	}
+
+	// Ctrl-C stops following instead of killing the shell.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	return followFiles(ctx, w, followed)
+}
+
+// tailLines copies the last n lines of r to w.
+func tailLines(r io.Reader, w io.Writer, n int) error {
+	if n == 0 {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+
+	var (
+		br    = bufio.NewReader(r)
+		ring  = make([]string, n)
+		count int
+	)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			ring[count%n] = line
+			count++
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	start := 0
+	if count > n {
+		start = count - n
+	}
+	for i := start; i < count; i++ {
+		if _, err := io.WriteString(w, ring[i%n]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// followedFile is a file being watched by "tail -f".
+type followedFile struct {
+	name   string
+	f      *os.File
+	offset int64
+}
+
+// openFollowed opens the named files positioned at their current end, skipping stdin.
+func openFollowed(names ...string) ([]*followedFile, error) {
+	files := make([]*followedFile, 0, len(names))
+	for _, name := range names {
+		if name == "-" {
+			continue
+		}
+		f, err := os.Open(name)
+		if err == nil {
+			var offset int64
+			if offset, err = f.Seek(0, io.SeekEnd); err == nil {
+				files = append(files, &followedFile{name: name, f: f, offset: offset})
+				continue
+			}
+			_ = f.Close()
+		}
+		closeFollowed(files)
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func closeFollowed(files []*followedFile) {
+	for _, ff := range files {
+		_ = ff.f.Close()
+	}
+}
+
+// followFiles polls the files and copies anything appended to them to w until ctx is done.
+// Files that shrink (e.g. truncated logs) are re-read from the start.
+func followFiles(ctx context.Context, w io.Writer, files []*followedFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	last := files[len(files)-1].name
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for _, ff := range files {
+			info, err := ff.f.Stat()
+			if err != nil {
+				return err
+			}
+			if info.Size() < ff.offset {
+				if ff.offset, err = ff.f.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+			if info.Size() == ff.offset {
+				continue
+			}
+			if len(files) > 1 && ff.name != last {
+				if err := printFileHeader(w, ff.name, false); err != nil {
+					return err
+				}
+				last = ff.name
+			}
+			copied, err := io.Copy(w, ff.f)
+			ff.offset += copied
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "lines.txt")
+	if err := os.WriteFile(file, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "defaults to ten lines",
+			args:    []string{file},
+			wantOut: "3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+		},
+		{
+			name:    "reads stdin without files",
+			stdin:   "a\nb\nc",
+			args:    []string{"-n", "2"},
+			wantOut: "b\nc",
+		},
+		{
+			name:    "more lines than input",
+			stdin:   "a\nb\n",
+			args:    []string{"-n5"},
+			wantOut: "a\nb\n",
+		},
+		{
+			name:  "zero lines",
+			stdin: "a\nb\n",
+			args:  []string{"-n", "0"},
+		},
+		{
+			name:    "bad count",
+			args:    []string{"-n", "-3"},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := Tail(context.Background(), strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Tail() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Tail() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Tail() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for a concurrent writer and reader.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func Test_followFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(file, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldInterval := FollowInterval
+	t.Cleanup(func() {
+		FollowInterval = oldInterval
+	})
+	FollowInterval = time.Millisecond
+
+	followed, err := openFollowed(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		closeFollowed(followed)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- followFiles(ctx, out, followed)
+	}()
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("new\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for out.String() != "new\n" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followFiles() unexpected error: %v", err)
+	}
+	if got := out.String(); got != "new\n" {
+		t.Errorf("followFiles() got = %q, want %q", got, "new\n")
+	}
+}
@@ -0,0 +1,318 @@
+package builtins
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("tar", func(ctx *Context, args ...string) error {
+		return Tar(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "tar -c|-x|-t [-vz] [-f ARCHIVE] [-C DIR] [FILE...]",
+		Summary:  "create, extract or list tar archives",
+		Flags: []string{
+			"-c\tcreate an archive of the FILEs",
+			"-x\textract the archive",
+			"-t\tlist the archive",
+			"-v\tprint each name as it is handled",
+			"-z\tcompress or decompress with gzip",
+			"-f ARCHIVE\tread or write ARCHIVE instead of standard input or output",
+			"-C DIR\textract into, or create from, DIR",
+		},
+	})
+}
+
+// tarOptions are the flags of a tar command.
+type tarOptions struct {
+	mode    byte // 'c', 'x' or 't'
+	verbose bool
+	gzip    bool
+	archive string
+	dir     string
+}
+
+// Tar handles the "tar" built-in command.
+// With -c it writes an archive of the files to ARCHIVE, or to w; with -x it
+// extracts ARCHIVE, or r, into the working directory; with -t it lists the
+// names. Flags may be bundled, with or without the dash, as in "tar czf
+// out.tar.gz dir". Names that would extract outside the directory are refused,
+// and so are symlinks pointing outside it and names that go through a symlink,
+// so an archive can't write anywhere but the directory.
+func Tar(r io.Reader, w io.Writer, args ...string) error {
+	opts, files, err := parseTarArgs(args)
+	if err != nil {
+		return err
+	}
+	if opts.mode == 'c' && len(files) == 0 {
+		return fmt.Errorf("%w: tar: refusing to create an empty archive", ErrInvalidArgCount)
+	}
+
+	switch opts.mode {
+	case 'c':
+		if opts.archive == "" || opts.archive == "-" {
+			return createTar(w, io.Discard, opts, files)
+		}
+		f, err := os.Create(opts.archive)
+		if err != nil {
+			return err
+		}
+		err = createTar(f, w, opts, files)
+		if cErr := f.Close(); err == nil {
+			err = cErr
+		}
+		return err
+	default:
+		name := opts.archive
+		if name == "" {
+			name = "-"
+		}
+		return withInput(r, name, func(in io.Reader) error {
+			return readTar(in, w, opts)
+		})
+	}
+}
+
+// parseTarArgs splits the arguments of tar into its options and the files.
+func parseTarArgs(args []string) (tarOptions, []string, error) {
+	var opts tarOptions
+	var files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		// The first argument is flags even without a dash, as in "tar xf a.tar".
+		if i == 0 && !strings.HasPrefix(arg, "-") {
+			arg = "-" + arg
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'c', 'x', 't':
+				if opts.mode != 0 && opts.mode != byte(flag) {
+					return opts, nil, fmt.Errorf("%w: tar: only one of -c, -x and -t may be given", ErrInvalidArgs)
+				}
+				opts.mode = byte(flag)
+			case 'v':
+				opts.verbose = true
+			case 'z':
+				opts.gzip = true
+			case 'f', 'C':
+				if i+1 == len(args) {
+					return opts, nil, fmt.Errorf("%w: tar: -%c requires an argument", ErrInvalidArgCount, flag)
+				}
+				i++
+				if flag == 'f' {
+					opts.archive = args[i]
+				} else {
+					opts.dir = args[i]
+				}
+			default:
+				return opts, nil, fmt.Errorf("%w: tar: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if opts.mode == 0 {
+		return opts, nil, fmt.Errorf("%w: tar: expected one of -c, -x and -t", ErrInvalidArgs)
+	}
+
+	return opts, files, nil
+}
+
+// createTar writes an archive of files, relative to opts.dir, to w and lists
+// their names on list with -v. When the archive goes to standard output, the
+// list is discarded.
+func createTar(w, list io.Writer, opts tarOptions, files []string) error {
+	var zw *gzip.Writer
+	if opts.gzip {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	tw := tar.NewWriter(w)
+	for _, file := range files {
+		root := filepath.Join(opts.dir, file)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			var link string
+			if info.Mode()&fs.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(opts.dir, path)
+			if opts.dir == "" || err != nil {
+				rel = path
+			}
+			hdr.Name = strings.TrimLeft(filepath.ToSlash(rel), "/")
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if opts.verbose {
+				if _, err := fmt.Fprintln(list, hdr.Name); err != nil {
+					return err
+				}
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return fileError("tar", file, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+
+	return nil
+}
+
+// readTar lists or extracts the archive in r, as opts.mode says.
+func readTar(r io.Reader, w io.Writer, opts tarOptions) error {
+	if opts.gzip {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("tar: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("tar: %w", err)
+		}
+		if opts.mode == 't' || opts.verbose {
+			if _, err := fmt.Fprintln(w, hdr.Name); err != nil {
+				return err
+			}
+		}
+		if opts.mode == 'x' {
+			if err := extractEntry(tr, hdr, opts.dir); err != nil {
+				return fileError("tar", hdr.Name, err)
+			}
+		}
+	}
+}
+
+// extractEntry writes the archive entry hdr, whose contents tr is at, under
+// dir. An entry may not reach outside dir: not by its name, not through a
+// symlink it makes, and not through a symlink an earlier entry made.
+func extractEntry(tr *tar.Reader, hdr *tar.Header, dir string) error {
+	name := filepath.Clean(filepath.FromSlash(hdr.Name))
+	if !insideDir(name) {
+		return fmt.Errorf("%w: path outside the directory", ErrInvalidArgs)
+	}
+	if hdr.Typeflag == tar.TypeSymlink {
+		target := filepath.FromSlash(hdr.Linkname)
+		if filepath.IsAbs(target) || !insideDir(filepath.Join(filepath.Dir(name), target)) {
+			return fmt.Errorf("%w: symlink to %v outside the directory", ErrInvalidArgs, hdr.Linkname)
+		}
+	}
+	if err := checkParents(dir, name); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// A symlink already at path is replaced, never written through.
+	if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	mode := fs.FileMode(hdr.Mode).Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.Mkdir(path, mode); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		return os.Chmod(path, mode)
+	case tar.TypeSymlink:
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return os.Symlink(hdr.Linkname, path)
+	case tar.TypeReg:
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chmod(path, mode)
+	default:
+		// Devices, hard links and the like are skipped.
+		return nil
+	}
+}
+
+// insideDir reports whether the clean relative path name stays inside the
+// directory it is relative to.
+func insideDir(name string) bool {
+	return !filepath.IsAbs(name) && name != ".." && !strings.HasPrefix(name, ".."+string(filepath.Separator))
+}
+
+// checkParents returns an error if a directory on the way from dir to name
+// is a symlink, which a write to name would follow.
+func checkParents(dir, name string) error {
+	rel := ""
+	for _, part := range strings.Split(filepath.Dir(name), string(filepath.Separator)) {
+		if part == "." {
+			continue
+		}
+		rel = filepath.Join(rel, part)
+		info, err := os.Lstat(filepath.Join(dir, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: path through the symlink %v", ErrInvalidArgs, rel)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package builtins_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestTar(t *testing.T) {
+	t.Parallel()
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "hw", "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "hw", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "hw", "sub", "run.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wantList := "hw/\nhw/main.go\nhw/sub/\nhw/sub/run.sh\n"
+
+	tests := []struct {
+		name   string
+		create string
+		list   string
+	}{
+		{name: "plain", create: "-cf", list: "-tf"},
+		{name: "gzip", create: "-czf", list: "tzf"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			archive := filepath.Join(t.TempDir(), "hw.tar")
+			var w bytes.Buffer
+			if err := builtins.Tar(nil, &w, tt.create+"v", archive, "-C", src, "hw"); err != nil {
+				t.Fatalf("Tar() create error = %v", err)
+			}
+			if got := w.String(); got != wantList {
+				t.Errorf("Tar() create got = %q, want %q", got, wantList)
+			}
+
+			w.Reset()
+			if err := builtins.Tar(nil, &w, tt.list, archive); err != nil {
+				t.Fatalf("Tar() list error = %v", err)
+			}
+			if got := w.String(); got != wantList {
+				t.Errorf("Tar() list got = %q, want %q", got, wantList)
+			}
+
+			dst := t.TempDir()
+			extract := strings.Replace(tt.list, "t", "x", 1)
+			if err := builtins.Tar(nil, &w, extract, archive, "-C", dst); err != nil {
+				t.Fatalf("Tar() extract error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dst, "hw", "sub", "run.sh"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "#!/bin/sh\n" {
+				t.Errorf("Tar() extracted %q", data)
+			}
+			if info, err := os.Stat(filepath.Join(dst, "hw", "sub", "run.sh")); err != nil || info.Mode().Perm() != 0o755 {
+				t.Errorf("Tar() extracted mode = %v, err %v", info.Mode(), err)
+			}
+		})
+	}
+}
+
+func TestTarErrors(t *testing.T) {
+	t.Parallel()
+	var evil bytes.Buffer
+	tw := tar.NewWriter(&evil)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		stdin   []byte
+		args    []string
+		wantErr error
+	}{
+		{name: "no mode", args: []string{"-f", "x.tar"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "two modes", args: []string{"-cx"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "empty archive", args: []string{"-c"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "missing archive name", args: []string{"-cf"}, wantErr: builtins.ErrInvalidArgCount},
+		{name: "outside directory", stdin: evil.Bytes(), args: []string{"-x", "-C", t.TempDir()}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := builtins.Tar(bytes.NewReader(tt.stdin), &w, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Tar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTarSymlinkEscape(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		link    string // where the symlink evil points, with %v for the outside directory
+		wantErr error
+	}{
+		{name: "absolute target", link: "%v"},
+		{name: "relative target", link: "../outside"},
+		// A symlink inside the directory is fine, but writing through it is
+		// not: it could have been pointed elsewhere since.
+		{name: "write through symlink", link: "."},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			dst, outside := filepath.Join(root, "dst"), filepath.Join(root, "outside")
+			for _, d := range []string{dst, outside} {
+				if err := os.Mkdir(d, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			link := tt.link
+			if strings.Contains(link, "%v") {
+				link = outside
+			}
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			if err := tw.WriteHeader(&tar.Header{Name: "evil", Linkname: link, Mode: 0o777, Typeflag: tar.TypeSymlink}); err != nil {
+				t.Fatal(err)
+			}
+			if err := tw.WriteHeader(&tar.Header{Name: "evil/pwn.txt", Mode: 0o644, Size: 5, Typeflag: tar.TypeReg}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte("pwned")); err != nil {
+				t.Fatal(err)
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			var w bytes.Buffer
+			if err := builtins.Tar(&archive, &w, "-x", "-C", dst); !errors.Is(err, builtins.ErrInvalidArgs) {
+				t.Errorf("Tar() error = %v, want %v", err, builtins.ErrInvalidArgs)
+			}
+			for _, p := range []string{filepath.Join(outside, "pwn.txt"), filepath.Join(dst, "pwn.txt")} {
+				if _, err := os.Stat(p); err == nil {
+					t.Errorf("Tar() wrote %v", p)
+				}
+			}
+		})
+	}
+}
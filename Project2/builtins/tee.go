@@ -0,0 +1,39 @@
+package builtins
+
+import (
+	"io"
+	"os"
+)
+
+func init() {
+	Register("tee", func(ctx *Context, args ...string) error {
+		return Tee(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "tee [-a] FILE...",
+		Summary:  "copy standard input to files and standard output",
+		Flags:    []string{"-a\tappend instead of overwriting"},
+	})
+}
+
+// Tee handles the "tee" built-in command.
+// It copies r to w and to each named file, appending to the files with -a.
+func Tee(r io.Reader, w io.Writer, args ...string) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if len(args) > 0 && args[0] == "-a" {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		args = args[1:]
+	}
+
+	writers := []io.Writer{w}
+	for _, name := range args {
+		f, err := os.OpenFile(name, flags, 0o666)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		writers = append(writers, f)
+	}
+
+	_, err := io.Copy(io.MultiWriter(writers...), r)
+	return err
+}
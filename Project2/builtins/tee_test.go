@@ -0,0 +1,68 @@
+package builtins_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	existing := filepath.Join(tmp, "existing.txt")
+	appended := filepath.Join(tmp, "appended.txt")
+	for _, name := range []string{existing, appended} {
+		if err := os.WriteFile(name, []byte("old\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		file     string
+		wantFile string
+	}{
+		{
+			name:     "truncates by default",
+			args:     []string{existing},
+			file:     existing,
+			wantFile: "new\n",
+		},
+		{
+			name:     "creates files",
+			args:     []string{filepath.Join(tmp, "created.txt")},
+			file:     filepath.Join(tmp, "created.txt"),
+			wantFile: "new\n",
+		},
+		{
+			name:     "appends with -a",
+			args:     []string{"-a", appended},
+			file:     appended,
+			wantFile: "old\nnew\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := builtins.Tee(strings.NewReader("new\n"), &out, tt.args...); err != nil {
+				t.Fatalf("Tee() unexpected error: %v", err)
+			}
+			if got := out.String(); got != "new\n" {
+				t.Errorf("Tee() stdout = %q, want %q", got, "new\n")
+			}
+			b, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); got != tt.wantFile {
+				t.Errorf("Tee() file = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
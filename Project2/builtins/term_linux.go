@@ -0,0 +1,68 @@
+package builtins
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// termState is a terminal's settings.
+type termState = syscall.Termios
+
+// getTermState reads the settings of the terminal on f.
+func getTermState(f *os.File) (termState, error) {
+	var state termState
+	err := ioctlTermios(f, syscall.TCGETS, &state)
+
+	return state, err
+}
+
+// setTermState changes the settings of the terminal on f to state.
+func setTermState(f *os.File, state *termState) error {
+	return ioctlTermios(f, syscall.TCSETS, state)
+}
+
+// makeRaw changes state to raw mode, for MakeRaw.
+func makeRaw(state *termState) {
+	state.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG | syscall.IEXTEN
+	state.Iflag &^= syscall.IXON
+	state.Cc[syscall.VMIN] = 1
+	state.Cc[syscall.VTIME] = 0
+}
+
+// makeCbreak changes state to cbreak mode, for enableCbreak.
+func makeCbreak(state *termState) {
+	state.Lflag &^= syscall.ICANON | syscall.ECHO
+	state.Cc[syscall.VMIN] = 0
+	state.Cc[syscall.VTIME] = 1
+}
+
+func ioctlTermios(f *os.File, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// openTerminal opens the process's controlling terminal, for reading keys
+// while stdin is redirected.
+func openTerminal() (*os.File, error) {
+	return os.Open("/dev/tty")
+}
+
+// windowSize asks the terminal on f for its size.
+func windowSize(f *os.File) (cols, rows int, err error) {
+	var ws struct{ Row, Col, Xpixel, Ypixel uint16 }
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return 0, 0, errno
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// notifyResize relays SIGWINCH, sent when the terminal is resized, to c.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
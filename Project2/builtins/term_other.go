@@ -0,0 +1,39 @@
+//go:build !linux
+
+package builtins
+
+import (
+	"errors"
+	"os"
+)
+
+// termState is empty, as terminal modes are unsupported here: the shell
+// reads whole lines, and keys are only seen after Enter.
+type termState struct{}
+
+var errTermModes = errors.New("terminal modes are not supported on this platform")
+
+func getTermState(*os.File) (termState, error) {
+	return termState{}, errTermModes
+}
+
+func setTermState(*os.File, *termState) error {
+	return errTermModes
+}
+
+func makeRaw(*termState) {}
+
+func makeCbreak(*termState) {}
+
+// openTerminal is unsupported, as there is no mode to read keys in.
+func openTerminal() (*os.File, error) {
+	return nil, errTermModes
+}
+
+// windowSize is unsupported here, so the size comes from $COLUMNS and $LINES.
+func windowSize(*os.File) (int, int, error) {
+	return 0, 0, errors.New("terminal size is not supported on this platform")
+}
+
+// notifyResize does nothing, as there is no terminal size to keep up to date.
+func notifyResize(chan<- os.Signal) {}
@@ -0,0 +1,148 @@
+package builtins
+
+import (
+	"os"
+	"sync"
+)
+
+// terminal is the one place terminal modes are switched, for the shell's
+// line editor and for builtins such as top. It remembers the mode each
+// terminal was found in, so that it can be put back on exit, on a panic and
+// while an external program such as vim or less runs, whatever switched it.
+var terminal = termModes{
+	get:   getTermState,
+	set:   setTermState,
+	modes: map[uintptr]*termMode{},
+}
+
+// termModes tracks the terminals switched out of the mode they were found in.
+type termModes struct {
+	get func(f *os.File) (termState, error)
+	set func(f *os.File, state *termState) error
+
+	mu    sync.Mutex
+	modes map[uintptr]*termMode // by file descriptor
+}
+
+// termMode is a terminal's state when it was first switched, and now.
+type termMode struct {
+	f        *os.File
+	found    termState
+	current  termState
+	switches int // how many switches are yet to be undone
+}
+
+// MakeRaw switches the terminal on f to reading each key as it is pressed,
+// without echoing it, for the shell's line editor. Keys such as Ctrl-C and
+// Ctrl-Z are read as bytes instead of sending signals. Reads block until a
+// key is pressed. The returned function restores the previous settings.
+func MakeRaw(f *os.File) (func(), error) {
+	return terminal.switchMode(f, makeRaw)
+}
+
+// enableCbreak switches the terminal on f to unbuffered, unechoed input so single
+// key presses can be read without Enter. Reads time out after a tenth of a second
+// (returning no data) so readers can notice cancellation. Signals such as Ctrl-C
+// are still generated. The returned function restores the previous settings.
+func enableCbreak(f *os.File) (func(), error) {
+	return terminal.switchMode(f, makeCbreak)
+}
+
+// RestoreTerminal puts every terminal that was switched to another mode back
+// in the mode it was found in. The switches still outstanding then do nothing
+// when undone.
+func RestoreTerminal() {
+	terminal.restore()
+}
+
+// RestoreTerminalOnPanic restores the terminals as RestoreTerminal does if
+// the goroutine is panicking, and then lets the panic go on. It is deferred
+// at the top of goroutines that may run while a terminal is switched.
+func RestoreTerminalOnPanic() {
+	if r := recover(); r != nil {
+		RestoreTerminal()
+		panic(r)
+	}
+}
+
+// CookedTerminal puts every switched terminal back in the mode it was found
+// in for an external program to run with, and returns a function switching
+// them to their modes again once it has finished.
+func CookedTerminal() func() {
+	return terminal.cooked()
+}
+
+// switchMode applies change to the state of the terminal on f and returns a
+// function putting back the state it had before.
+func (t *termModes) switchMode(f *os.File, change func(*termState)) (func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev, err := t.get(f)
+	if err != nil {
+		return nil, err
+	}
+	next := prev
+	change(&next)
+	if err := t.set(f, &next); err != nil {
+		return nil, err
+	}
+	m, ok := t.modes[f.Fd()]
+	if !ok {
+		m = &termMode{f: f, found: prev}
+		t.modes[f.Fd()] = m
+	}
+	m.current = next
+	m.switches++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { t.undo(f, m, prev) })
+	}, nil
+}
+
+// undo puts the terminal of m back in prev, unless it has been restored
+// since it was switched.
+func (t *termModes) undo(f *os.File, m *termMode, prev termState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.modes[f.Fd()] != m {
+		return
+	}
+	_ = t.set(f, &prev)
+	m.current = prev
+	if m.switches--; m.switches == 0 {
+		delete(t.modes, f.Fd())
+	}
+}
+
+// restore puts every switched terminal back in the mode it was found in and
+// forgets it.
+func (t *termModes) restore() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for fd, m := range t.modes {
+		_ = t.set(m.f, &m.found)
+		delete(t.modes, fd)
+	}
+}
+
+// cooked puts every switched terminal back in the mode it was found in and
+// returns a function switching it to its current mode again.
+func (t *termModes) cooked() func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.modes) == 0 {
+		return func() {}
+	}
+	for _, m := range t.modes {
+		_ = t.set(m.f, &m.found)
+	}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for _, m := range t.modes {
+			_ = t.set(m.f, &m.current)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package builtins
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+// fakeTerminal stands in for a terminal, holding the state last set.
+type fakeTerminal struct {
+	state termState
+}
+
+func (ft *fakeTerminal) modes() *termModes {
+	return &termModes{
+		get: func(*os.File) (termState, error) {
+			return ft.state, nil
+		},
+		set: func(_ *os.File, state *termState) error {
+			ft.state = *state
+			return nil
+		},
+		modes: map[uintptr]*termMode{},
+	}
+}
+
+func Test_termModes(t *testing.T) {
+	t.Parallel()
+	cooked := termState{Lflag: syscall.ICANON | syscall.ECHO | syscall.ISIG}
+	raw := cooked
+	makeRaw(&raw)
+	cbreak := raw
+	makeCbreak(&cbreak)
+	check := func(ft *fakeTerminal, want termState, what string) {
+		t.Helper()
+		if ft.state.Lflag != want.Lflag || ft.state.Cc != want.Cc {
+			t.Errorf("%v: terminal Lflag = %#x, want %#x", what, ft.state.Lflag, want.Lflag)
+		}
+	}
+
+	t.Run("nested switches undo in turn", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeTerminal{state: cooked}
+		tm := ft.modes()
+		undoRaw, _ := tm.switchMode(os.Stdin, makeRaw)
+		check(ft, raw, "raw")
+		undoCbreak, _ := tm.switchMode(os.Stdin, makeCbreak)
+		check(ft, cbreak, "cbreak inside raw")
+		undoCbreak()
+		undoCbreak()
+		check(ft, raw, "cbreak undone, twice")
+		undoRaw()
+		check(ft, cooked, "raw undone")
+		if len(tm.modes) != 0 {
+			t.Errorf("%d terminals still tracked", len(tm.modes))
+		}
+	})
+
+	t.Run("cooked for a program", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeTerminal{state: cooked}
+		tm := ft.modes()
+		undo, _ := tm.switchMode(os.Stdin, makeRaw)
+		resume := tm.cooked()
+		check(ft, cooked, "while the program runs")
+		resume()
+		check(ft, raw, "after it")
+		undo()
+		check(ft, cooked, "undone")
+	})
+
+	t.Run("restore before undoing", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeTerminal{state: cooked}
+		tm := ft.modes()
+		undoRaw, _ := tm.switchMode(os.Stdin, makeRaw)
+		undoCbreak, _ := tm.switchMode(os.Stdin, makeCbreak)
+		tm.restore()
+		check(ft, cooked, "restored")
+		undoCbreak()
+		undoRaw()
+		check(ft, cooked, "undone after restoring")
+	})
+}
@@ -0,0 +1,76 @@
+package builtins
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// The terminal size assumed when it can't be found out.
+const (
+	defaultColumns = 80
+	defaultLines   = 24
+)
+
+// termSizes caches the size of each terminal asked about, by file descriptor.
+// Asking is a system call, and the prompt and builtins such as top ask often,
+// so the cache is only emptied when the terminal is resized.
+var termSizes struct {
+	once  sync.Once
+	mu    sync.Mutex
+	sizes map[uintptr][2]int
+}
+
+// TerminalSize returns the width and height of the terminal w writes to.
+// If w isn't a terminal, or its size can't be found, $COLUMNS and $LINES are
+// used, and failing those 80 by 24.
+func TerminalSize(w io.Writer) (cols, rows int) {
+	if f, ok := w.(*os.File); ok && isTerminal(f) {
+		if cols, rows, ok := cachedWindowSize(f); ok {
+			return cols, rows
+		}
+	}
+
+	return envSize("COLUMNS", defaultColumns), envSize("LINES", defaultLines)
+}
+
+// cachedWindowSize returns the size of the terminal on f, asking it only if
+// the size isn't known since the last SIGWINCH.
+func cachedWindowSize(f *os.File) (cols, rows int, ok bool) {
+	termSizes.once.Do(func() {
+		termSizes.sizes = map[uintptr][2]int{}
+		resized := make(chan os.Signal, 1)
+		notifyResize(resized)
+		go func() {
+			for range resized {
+				termSizes.mu.Lock()
+				termSizes.sizes = map[uintptr][2]int{}
+				termSizes.mu.Unlock()
+			}
+		}()
+	})
+
+	termSizes.mu.Lock()
+	defer termSizes.mu.Unlock()
+	if size, ok := termSizes.sizes[f.Fd()]; ok {
+		return size[0], size[1], true
+	}
+	cols, rows, err := windowSize(f)
+	if err != nil || cols <= 0 || rows <= 0 {
+		return 0, 0, false
+	}
+	termSizes.sizes[f.Fd()] = [2]int{cols, rows}
+
+	return cols, rows, true
+}
+
+// envSize returns the positive number in the named environment variable, or def.
+func envSize(name string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || n <= 0 {
+		return def
+	}
+
+	return n
+}
@@ -0,0 +1,91 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// ErrTimedOut is wrapped in the ExitError returned when timeout kills its command.
+var ErrTimedOut = errors.New("timed out")
+
+func init() {
+	Register("timeout", func(ctx *Context, args ...string) error {
+		return Timeout(ctx.Context(), ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "timeout [-s SIGNAL] [-k DURATION] DURATION CMD [ARG...]",
+		Summary:  "run a command with a time limit",
+		Flags: []string{
+			"-k DURATION\tkill if still running this long after the signal",
+			"-s SIGNAL\tsignal to send (default TERM)",
+		},
+	})
+}
+
+// Timeout handles the "timeout" built-in command.
+// It runs an external command and sends it a signal (-s SIGNAL, default TERM) if it is
+// still running after DURATION, following up with KILL after -k DURATION if given.
+// A timed-out command yields exit status 124; otherwise the command's own status is kept.
+func Timeout(ctx context.Context, w io.Writer, args ...string) error {
+	var (
+		sig       = syscall.SIGTERM
+		killAfter time.Duration
+		err       error
+	)
+	for len(args) > 1 && (args[0] == "-s" || args[0] == "-k") {
+		switch args[0] {
+		case "-s":
+			sig, err = parseSignal(args[1])
+		case "-k":
+			killAfter, err = parseDuration(args[1])
+		}
+		if err != nil {
+			return err
+		}
+		args = args[2:]
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: expected a duration and a command", ErrInvalidArgCount)
+	}
+	limit, err := parseDuration(args[0])
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, args[1], args[2:]...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	timer := time.NewTimer(limit)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return Interrupted(ctx, err)
+	case <-timer.C:
+	}
+
+	_ = cmd.Process.Signal(sig)
+	if killAfter > 0 {
+		select {
+		case <-done:
+			return &ExitError{Status: StatusTimedOut, Err: ErrTimedOut}
+		case <-time.After(killAfter):
+			_ = cmd.Process.Kill()
+		}
+	}
+	<-done
+
+	return &ExitError{Status: StatusTimedOut, Err: ErrTimedOut}
+}
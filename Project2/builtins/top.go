@@ -0,0 +1,242 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TopRows is the number of processes top shows per refresh when it isn't
+// drawing on a terminal; on one it fills the screen.
+var TopRows = 20
+
+// topHeaderLines is how many lines top prints above the processes.
+const topHeaderLines = 3
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// topSample is a process with its share of CPU and memory since the previous refresh.
+type topSample struct {
+	ProcessInfo
+	cpu, mem float64
+}
+
+func init() {
+	Register("top", func(ctx *Context, args ...string) error {
+		return Top(ctx.Context(), ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "top [-d SECS] [-n N] [-o cpu|mem]",
+		Summary:  "show the busiest processes",
+		Flags: []string{
+			"-d SECS\tdelay between updates",
+			"-n N\tstop after N updates",
+			"-o KEY\tsort by cpu or mem",
+		},
+	})
+}
+
+// Top handles the "top" built-in command.
+// It redraws a table of the busiest processes every -d SECONDS (default 3), sorted by
+// -o cpu or -o mem, until q is pressed, Ctrl-C is hit, or -n ITERATIONS refreshes are shown.
+// While running, c and m switch the sort order. When w is not a terminal the screen
+// is not cleared between refreshes.
+func Top(ctx context.Context, r io.Reader, w io.Writer, args ...string) error {
+	var (
+		interval   = 3 * time.Second
+		iterations int
+		sortBy     = "cpu"
+	)
+	for i := 0; i < len(args); i++ {
+		if len(args) < i+2 {
+			return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, args[i])
+		}
+		flag, value := args[i], args[i+1]
+		i++
+		switch flag {
+		case "-d":
+			secs, err := strconv.ParseFloat(value, 64)
+			if err != nil || secs <= 0 {
+				return fmt.Errorf("%w: invalid delay %q", ErrInvalidArgs, value)
+			}
+			interval = time.Duration(secs * float64(time.Second))
+		case "-n":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("%w: invalid iteration count %q", ErrInvalidArgs, value)
+			}
+			iterations = n
+		case "-o":
+			if value != "cpu" && value != "mem" {
+				return fmt.Errorf("%w: -o must be cpu or mem", ErrInvalidArgs)
+			}
+			sortBy = value
+		default:
+			return fmt.Errorf("%w: unknown flag %v", ErrInvalidArgs, flag)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	interactive := w == io.Writer(os.Stdout) && isTerminal(os.Stdout)
+	var keys <-chan byte
+	if interactive {
+		restore, err := enableCbreak(os.Stdin)
+		if err == nil {
+			defer restore()
+		}
+		keys = readKeys(ctx, r, err == nil)
+	} else if iterations == 0 {
+		keys = readKeys(ctx, r, false)
+	}
+
+	memTotal, _ := totalMemory()
+	prev, err := listProcesses()
+	if err != nil {
+		return err
+	}
+	prevAt := time.Now()
+
+	// The first refresh comes quickly so CPU usage has something to be measured against.
+	timer := time.NewTimer(interval / 6)
+	defer timer.Stop()
+	for shown := 0; iterations == 0 || shown < iterations; {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			switch key {
+			case 'q', 'Q':
+				return nil
+			case 'c':
+				sortBy = "cpu"
+			case 'm':
+				sortBy = "mem"
+			default:
+				continue
+			}
+		case <-timer.C:
+			timer.Reset(interval)
+			shown++
+		}
+
+		procs, err := listProcesses()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		samples := topSamples(prev, procs, now.Sub(prevAt), memTotal)
+		prev, prevAt = procs, now
+
+		rows := TopRows
+		if interactive {
+			if _, err := io.WriteString(w, clearScreen); err != nil {
+				return err
+			}
+			// Leave a line for the cursor below the table.
+			_, lines := TerminalSize(w)
+			if rows = lines - topHeaderLines - 1; rows < 1 {
+				rows = 1
+			}
+		}
+		if err := printTop(w, now, samples, sortBy, rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// readKeys sends each byte read from r until ctx is done or r is exhausted.
+// In cbreak mode an empty read is only the terminal's read timeout, so reading continues.
+func readKeys(ctx context.Context, r io.Reader, cbreak bool) <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		defer RestoreTerminalOnPanic()
+		defer close(keys)
+		buf := make([]byte, 1)
+		for ctx.Err() == nil {
+			n, err := r.Read(buf)
+			if n == 1 {
+				select {
+				case keys <- buf[0]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil && !(cbreak && err == io.EOF) {
+				return
+			}
+		}
+	}()
+
+	return keys
+}
+
+// topSamples computes each process's CPU share over elapsed and its share of memTotal.
+func topSamples(prev, procs []ProcessInfo, elapsed time.Duration, memTotal int64) []topSample {
+	before := make(map[int]time.Duration, len(prev))
+	for _, p := range prev {
+		before[p.PID] = p.CPUTime
+	}
+
+	samples := make([]topSample, len(procs))
+	for i, p := range procs {
+		samples[i].ProcessInfo = p
+		if elapsed > 0 {
+			samples[i].cpu = float64(p.CPUTime-before[p.PID]) / float64(elapsed) * 100
+		}
+		if memTotal > 0 {
+			samples[i].mem = float64(p.RSS) / float64(memTotal) * 100
+		}
+	}
+
+	return samples
+}
+
+// printTop writes one refresh of the top table, with at most rows processes.
+func printTop(w io.Writer, now time.Time, samples []topSample, sortBy string, rows int) error {
+	sort.SliceStable(samples, func(i, j int) bool {
+		if sortBy == "mem" {
+			return samples[i].RSS > samples[j].RSS
+		}
+		if samples[i].cpu != samples[j].cpu {
+			return samples[i].cpu > samples[j].cpu
+		}
+		return samples[i].CPUTime > samples[j].CPUTime
+	})
+	if len(samples) > rows {
+		samples = samples[:rows]
+	}
+
+	if _, err := fmt.Fprintf(w, "top - %v, sorted by %v (q quit, c cpu, m mem)\n\n%7v %1v %6v %6v %10v %8v %v\n",
+		now.Format("15:04:05"), sortBy, "PID", "S", "%CPU", "%MEM", "RSS", "TIME", "COMMAND"); err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if _, err := fmt.Fprintf(w, "%7d %1v %6.1f %6.1f %10d %8v %v\n",
+			s.PID, s.State, s.cpu, s.mem, s.RSS/1024, formatCPUTime(s.CPUTime), s.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTop(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantFrames int
+		wantErr    error
+	}{
+		{name: "iterations", args: []string{"-n", "2", "-d", "0.01"}, wantFrames: 2},
+		{name: "memory order", args: []string{"-n", "1", "-o", "mem", "-d", "0.01"}, wantFrames: 1},
+		{name: "quit key", stdin: "q", args: []string{"-d", "10"}},
+		{name: "bad delay", args: []string{"-d", "0"}, wantErr: ErrInvalidArgs},
+		{name: "bad order", args: []string{"-o", "io"}, wantErr: ErrInvalidArgs},
+		{name: "missing value", args: []string{"-n"}, wantErr: ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := Top(context.Background(), strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Top() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Top() unexpected error: %v", err)
+			}
+			if got := strings.Count(out.String(), "top - "); got != tt.wantFrames {
+				t.Errorf("Top() drew %v frames, want %v", got, tt.wantFrames)
+			}
+			if strings.Contains(out.String(), clearScreen) {
+				t.Errorf("Top() cleared a screen that is not a terminal")
+			}
+		})
+	}
+}
+
+func Test_topSamples(t *testing.T) {
+	t.Parallel()
+	prev := []ProcessInfo{{PID: 1, CPUTime: time.Second}}
+	procs := []ProcessInfo{{PID: 1, CPUTime: 1500 * time.Millisecond, RSS: 256}, {PID: 2, CPUTime: time.Second, RSS: 512}}
+	got := topSamples(prev, procs, time.Second, 1024)
+	if got[0].cpu != 50 || got[0].mem != 25 {
+		t.Errorf("topSamples() pid 1 = %.1f%% cpu %.1f%% mem, want 50%% cpu 25%% mem", got[0].cpu, got[0].mem)
+	}
+	if got[1].cpu != 100 || got[1].mem != 50 {
+		t.Errorf("topSamples() new pid 2 = %.1f%% cpu %.1f%% mem, want 100%% cpu 50%% mem", got[1].cpu, got[1].mem)
+	}
+}
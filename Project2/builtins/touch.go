@@ -5,14 +5,38 @@ package builtins
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
+func init() {
+	Register("touch", func(ctx *Context, args ...string) error {
+		return Touch(args...)
+	}, Meta{
+		Synopsis: "touch FILE...",
+		Summary:  "create files or update their times",
+	})
+}
+
 // Touch handles the "touch" built-in command.
+// It creates each missing file and sets the access and modification times of existing ones to now.
 func Touch(args ...string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: touch <file>")
+		return fmt.Errorf("%w: usage: touch <file>...", ErrInvalidArgCount)
 	}
-	file := args[0]
-	_, err := os.Create(file)
-	return err
+
+	now := time.Now()
+	for _, file := range args {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE, 0o666)
+		if err != nil {
+			return fileError("touch", file, err)
+		}
+		if err := f.Close(); err != nil {
+			return fileError("touch", file, err)
+		}
+		if err := os.Chtimes(file, now, now); err != nil {
+			return fileError("touch", file, err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,132 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("tr", func(ctx *Context, args ...string) error {
+		return Translate(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "tr [-d] SET1 [SET2]",
+		Summary:  "translate or delete characters",
+		Flags:    []string{"-d\tdelete the characters in SET1"},
+	})
+}
+
+// Translate handles the "tr" built-in command.
+// It copies r to w replacing characters in SET1 with the matching characters in SET2,
+// or deleting them with -d. Sets may contain ranges such as a-z and escapes such as \n.
+func Translate(r io.Reader, w io.Writer, args ...string) error {
+	del := false
+	if len(args) > 0 && args[0] == "-d" {
+		del = true
+		args = args[1:]
+	}
+	switch {
+	case del && len(args) != 1:
+		return fmt.Errorf("%w: expected one set with -d", ErrInvalidArgCount)
+	case !del && len(args) != 2:
+		return fmt.Errorf("%w: expected two sets", ErrInvalidArgCount)
+	}
+
+	set1, err := expandSet(args[0])
+	if err != nil {
+		return err
+	}
+	mapping := make(map[rune]rune, len(set1))
+	if del {
+		for _, ch := range set1 {
+			mapping[ch] = -1
+		}
+	} else {
+		set2, err := expandSet(args[1])
+		if err != nil {
+			return err
+		}
+		if len(set2) == 0 {
+			return fmt.Errorf("%w: SET2 must not be empty", ErrInvalidArgs)
+		}
+		for i, ch := range set1 {
+			// SET2 is padded with its last character, as POSIX tr does.
+			if i < len(set2) {
+				mapping[ch] = set2[i]
+			} else {
+				mapping[ch] = set2[len(set2)-1]
+			}
+		}
+	}
+
+	var (
+		br = bufio.NewReader(r)
+		bw = bufio.NewWriter(w)
+	)
+	for {
+		ch, _, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if to, ok := mapping[ch]; ok {
+			if to < 0 {
+				continue
+			}
+			ch = to
+		}
+		if _, err := bw.WriteRune(ch); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// expandSet expands the ranges and backslash escapes of a tr set.
+func expandSet(set string) ([]rune, error) {
+	var (
+		in  = []rune(set)
+		out = make([]rune, 0, len(in))
+	)
+	for i := 0; i < len(in); i++ {
+		ch := in[i]
+		if ch == '\\' && i+1 < len(in) {
+			i++
+			ch = unescape(in[i])
+		}
+		if i+2 < len(in) && in[i+1] == '-' {
+			end := in[i+2]
+			i += 2
+			if end == '\\' && i+1 < len(in) {
+				i++
+				end = unescape(in[i])
+			}
+			if end < ch {
+				return nil, fmt.Errorf("%w: range %c-%c is in reverse order", ErrInvalidArgs, ch, end)
+			}
+			for c := ch; c <= end; c++ {
+				out = append(out, c)
+			}
+			continue
+		}
+		out = append(out, ch)
+	}
+
+	return out, nil
+}
+
+// unescape returns the character named by a backslash escape.
+func unescape(ch rune) rune {
+	switch ch {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	default:
+		return ch
+	}
+}
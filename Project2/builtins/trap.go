@@ -0,0 +1,141 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// TrapExit is the pseudo-signal whose trap runs when the shell exits.
+const TrapExit = "EXIT"
+
+// Traps holds the commands registered with the "trap" builtin, keyed by signal
+// name without the SIG prefix (or TrapExit). Trapped signals are delivered on
+// Signals for the shell to dispatch.
+type Traps struct {
+	mu      sync.Mutex
+	actions map[string]string
+	sigs    chan os.Signal
+}
+
+// NewTraps returns an empty trap table.
+func NewTraps() *Traps {
+	return &Traps{
+		actions: make(map[string]string),
+		sigs:    make(chan os.Signal, 1),
+	}
+}
+
+// Signals delivers the trapped signals as they arrive.
+func (t *Traps) Signals() <-chan os.Signal {
+	return t.sigs
+}
+
+// Action returns the command registered for a signal name or TrapExit.
+// An ignored signal has an empty action.
+func (t *Traps) Action(name string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	action, ok := t.actions[name]
+
+	return action, ok
+}
+
+// SignalAction returns the command registered for sig.
+func (t *Traps) SignalAction(sig os.Signal) (string, bool) {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return "", false
+	}
+
+	return t.Action(signalName(s))
+}
+
+// Trap handles the "trap" built-in command.
+//
+//	trap [-p]               list the registered traps
+//	trap -l                 list the signal names and numbers
+//	trap - SIGNAL...        restore the default behaviour
+//	trap '' SIGNAL...       ignore the signals
+//	trap COMMAND SIGNAL...  run COMMAND when a signal arrives (EXIT: when the shell exits)
+func (t *Traps) Trap(w io.Writer, args ...string) error {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-p") {
+		return t.print(w)
+	}
+	if args[0] == "-l" {
+		for _, sig := range sortedSignals() {
+			if _, err := fmt.Fprintf(w, "%2d) SIG%v\n", int(sig), signalName(sig)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: expected a command and at least one signal", ErrInvalidArgCount)
+	}
+
+	action := args[0]
+	names := make([]string, 0, len(args)-1)
+	sigs := make([]syscall.Signal, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		if arg == "0" || strings.EqualFold(arg, TrapExit) {
+			names, sigs = append(names, TrapExit), append(sigs, 0)
+			continue
+		}
+		sig, err := parseSignal(arg)
+		if err != nil {
+			return err
+		}
+		if sig == syscall.SIGKILL || signalName(sig) == "STOP" {
+			return fmt.Errorf("%w: SIG%v cannot be trapped", ErrInvalidArgs, signalName(sig))
+		}
+		names, sigs = append(names, signalName(sig)), append(sigs, sig)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, name := range names {
+		switch action {
+		case "-":
+			delete(t.actions, name)
+		default:
+			t.actions[name] = action
+		}
+		if sigs[i] == 0 {
+			continue
+		}
+		switch action {
+		case "-":
+			signal.Reset(sigs[i])
+		case "":
+			signal.Ignore(sigs[i])
+		default:
+			signal.Notify(t.sigs, sigs[i])
+		}
+	}
+
+	return nil
+}
+
+// print lists the registered traps in a form that can be re-entered.
+func (t *Traps) print(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.actions))
+	for name := range t.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "trap -- '%v' %v\n", t.actions[name], name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
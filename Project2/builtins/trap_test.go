@@ -0,0 +1,88 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTraps_Trap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "missing signal", args: []string{"echo"}, wantErr: ErrInvalidArgCount},
+		{name: "unknown signal", args: []string{"echo", "NOPE"}, wantErr: ErrInvalidArgs},
+		{name: "kill cannot be trapped", args: []string{"echo", "KILL"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := NewTraps().Trap(&bytes.Buffer{}, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Trap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTraps_list(t *testing.T) {
+	t.Parallel()
+	traps := NewTraps()
+	var out bytes.Buffer
+	if err := traps.Trap(&out, "-l"); err != nil {
+		t.Fatalf("Trap() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), " 2) SIGINT\n") || !strings.Contains(out.String(), "15) SIGTERM\n") {
+		t.Errorf("Trap(-l) got = %q", out.String())
+	}
+
+	if err := traps.Trap(&out, "pwd", "exit", "0"); err != nil {
+		t.Fatalf("Trap() unexpected error: %v", err)
+	}
+	out.Reset()
+	if err := traps.Trap(&out); err != nil {
+		t.Fatalf("Trap() unexpected error: %v", err)
+	}
+	if want := "trap -- 'pwd' EXIT\n"; out.String() != want {
+		t.Errorf("Trap() got = %q, want %q", out.String(), want)
+	}
+
+	if err := traps.Trap(&out, "-", "EXIT"); err != nil {
+		t.Fatalf("Trap() unexpected error: %v", err)
+	}
+	if action, ok := traps.Action(TrapExit); ok {
+		t.Errorf("Action() got = %q after reset, want none", action)
+	}
+}
+
+func TestTraps_signal(t *testing.T) {
+	// Not parallel: changes the process's handling of SIGHUP.
+	traps := NewTraps()
+	if err := traps.Trap(&bytes.Buffer{}, "pwd", "SIGHUP"); err != nil {
+		t.Fatalf("Trap() unexpected error: %v", err)
+	}
+	defer func() { _ = traps.Trap(&bytes.Buffer{}, "-", "HUP") }()
+
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case sig := <-traps.Signals():
+		if action, _ := traps.SignalAction(sig); action != "pwd" {
+			t.Errorf("SignalAction() got = %q, want %q", action, "pwd")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("trapped signal was not delivered")
+	}
+}
@@ -0,0 +1,34 @@
+package builtins
+
+func init() {
+	Register("true", func(ctx *Context, args ...string) error {
+		return True(args...)
+	}, Meta{
+		Synopsis: "true",
+		Summary:  "do nothing, successfully",
+	})
+	Register(":", func(ctx *Context, args ...string) error {
+		return True(args...)
+	}, Meta{
+		Synopsis: ": [ARG...]",
+		Summary:  "do nothing, successfully; the arguments are still expanded",
+	})
+	Register("false", func(ctx *Context, args ...string) error {
+		return False(args...)
+	}, Meta{
+		Synopsis: "false",
+		Summary:  "do nothing, unsuccessfully",
+	})
+}
+
+// True handles the "true" and ":" built-in commands.
+// It ignores its arguments and succeeds.
+func True(...string) error {
+	return nil
+}
+
+// False handles the "false" built-in command.
+// It ignores its arguments and fails with status 1.
+func False(...string) error {
+	return &ExitError{Status: StatusFailure}
+}
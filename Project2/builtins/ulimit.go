@@ -0,0 +1,172 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Resources ulimit can show and set; rlimit maps them to the platform's.
+const (
+	limitCore = iota
+	limitCPU
+	limitFileSize
+	limitOpenFiles
+	limitAddressSpace
+)
+
+// ulimitResource describes a resource limit as ulimit shows it. Limits are
+// given and printed in units of scale bytes, or seconds or files for scale 1.
+type ulimitResource struct {
+	flag  byte
+	name  string
+	unit  string
+	scale uint64
+	id    int
+}
+
+// ulimitResources are in the order ulimit -a lists them.
+var ulimitResources = []ulimitResource{
+	{'c', "core file size", "blocks", 1024, limitCore},
+	{'t', "cpu time", "seconds", 1, limitCPU},
+	{'f', "file size", "blocks", 1024, limitFileSize},
+	{'n', "open files", "", 1, limitOpenFiles},
+	{'v', "virtual memory", "kbytes", 1024, limitAddressSpace},
+}
+
+func init() {
+	Register("ulimit", func(ctx *Context, args ...string) error {
+		return Ulimit(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "ulimit [-HS] [-a | -c | -f | -n | -t | -v]... [LIMIT]",
+		Summary:  "print or set resource limits of the shell and the commands it runs",
+		Flags: []string{
+			"-H\tuse the hard limit",
+			"-S\tuse the soft limit",
+			"-a\tprint every limit",
+			"-c\tcore file size, in 1024-byte blocks",
+			"-f\tsize of files written, in 1024-byte blocks (the default)",
+			"-n\tnumber of open files",
+			"-t\tCPU time, in seconds",
+			"-v\tvirtual memory, in kilobytes",
+		},
+	})
+}
+
+// Ulimit handles the "ulimit" built-in command.
+// It prints the soft limit of a resource, or its hard limit with -H. Given a
+// LIMIT, a number or "unlimited", it sets both limits, or only the one that
+// -H or -S names; only the hard limit can't be raised again. Limits are
+// inherited by the commands the shell runs.
+func Ulimit(w io.Writer, args ...string) error {
+	var (
+		hard, soft, all bool
+		resources       []ulimitResource
+		value           string
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			if value != "" {
+				return fmt.Errorf("%w: expected at most one limit", ErrInvalidArgCount)
+			}
+			value = arg
+			continue
+		}
+	flags:
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'H':
+				hard = true
+				continue
+			case 'S':
+				soft = true
+				continue
+			case 'a':
+				all = true
+				continue
+			}
+			for _, res := range ulimitResources {
+				if res.flag == byte(flag) {
+					resources = append(resources, res)
+					continue flags
+				}
+			}
+			return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+		}
+	}
+	if all {
+		if value != "" {
+			return fmt.Errorf("%w: -a takes no limit", ErrInvalidArgs)
+		}
+		resources = ulimitResources
+	}
+	if len(resources) == 0 {
+		resources = []ulimitResource{ulimitResources[2]} // -f, as in sh
+	}
+
+	if value != "" {
+		if len(resources) > 1 {
+			return fmt.Errorf("%w: a limit can only be set for one resource", ErrInvalidArgs)
+		}
+		return setUlimit(resources[0], value, hard || !soft, soft || !hard)
+	}
+	for _, res := range resources {
+		cur, max, err := getRlimit(res.id)
+		if err != nil {
+			return fmt.Errorf("%v: %w", res.name, err)
+		}
+		limit := formatLimit(cur, res.scale)
+		if hard {
+			limit = formatLimit(max, res.scale)
+		}
+		if len(resources) > 1 {
+			unit := res.unit
+			if unit != "" {
+				unit += ", "
+			}
+			limit = fmt.Sprintf("%-26s(%v-%c) %v", res.name, unit, res.flag, limit)
+		}
+		if _, err := fmt.Fprintln(w, limit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setUlimit sets the hard and/or soft limit of res to value.
+func setUlimit(res ulimitResource, value string, hard, soft bool) error {
+	limit := rlimInfinity
+	if value != "unlimited" {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || n > rlimInfinity/res.scale {
+			return fmt.Errorf("%w: invalid limit %q", ErrInvalidArgs, value)
+		}
+		limit = n * res.scale
+	}
+
+	cur, max, err := getRlimit(res.id)
+	if err != nil {
+		return fmt.Errorf("%v: %w", res.name, err)
+	}
+	if hard {
+		max = limit
+	}
+	if soft {
+		cur = limit
+	}
+	if err := setRlimit(res.id, cur, max); err != nil {
+		return fmt.Errorf("%v: cannot modify limit: %w", res.name, err)
+	}
+
+	return nil
+}
+
+// formatLimit formats a limit in bytes, seconds or files as ulimit prints it.
+func formatLimit(limit, scale uint64) string {
+	if limit == rlimInfinity {
+		return "unlimited"
+	}
+
+	return strconv.FormatUint(limit/scale, 10)
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package builtins
+
+import "errors"
+
+// rlimInfinity is the value of a limit that isn't set.
+const rlimInfinity = ^uint64(0)
+
+var errRlimitUnsupported = errors.New("resource limits are not supported on this platform")
+
+func getRlimit(int) (uint64, uint64, error) {
+	return 0, 0, errRlimitUnsupported
+}
+
+func setRlimit(int, uint64, uint64) error {
+	return errRlimitUnsupported
+}
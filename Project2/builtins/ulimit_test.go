@@ -0,0 +1,67 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// Not parallel: setting a limit changes it for the whole test binary.
+func TestUlimit(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("resource limits are only supported on linux and darwin")
+	}
+	var w bytes.Buffer
+	if err := builtins.Ulimit(&w, "-S", "-c"); err != nil {
+		t.Fatalf("Ulimit() error = %v", err)
+	}
+	old := strings.TrimSpace(w.String())
+	t.Cleanup(func() {
+		if err := builtins.Ulimit(&w, "-S", "-c", old); err != nil {
+			t.Errorf("Ulimit() restore error = %v", err)
+		}
+	})
+
+	if err := builtins.Ulimit(&w, "-Sc", "0"); err != nil {
+		t.Fatalf("Ulimit() set error = %v", err)
+	}
+	w.Reset()
+	if err := builtins.Ulimit(&w, "-c"); err != nil {
+		t.Fatalf("Ulimit() error = %v", err)
+	}
+	if got := w.String(); got != "0\n" {
+		t.Errorf("Ulimit() got = %q, want %q", got, "0\n")
+	}
+
+	w.Reset()
+	if err := builtins.Ulimit(&w, "-a"); err != nil {
+		t.Fatalf("Ulimit() -a error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "core file size") || !strings.HasSuffix(lines[0], "(blocks, -c) 0") {
+		t.Errorf("Ulimit() -a got = %q", w.String())
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "unknown flag", args: []string{"-x"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "bad limit", args: []string{"-n", "lots"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "two resources", args: []string{"-n", "-c", "10"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "two limits", args: []string{"-n", "10", "20"}, wantErr: builtins.ErrInvalidArgCount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := builtins.Ulimit(&w, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ulimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
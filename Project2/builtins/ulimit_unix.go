@@ -0,0 +1,32 @@
+//go:build linux || darwin
+
+package builtins
+
+import "syscall"
+
+// rlimInfinity is the value of a limit that isn't set.
+const rlimInfinity = ^uint64(0)
+
+// rlimits are the platform's resource numbers for the ones ulimit knows.
+var rlimits = map[int]int{
+	limitCore:         syscall.RLIMIT_CORE,
+	limitCPU:          syscall.RLIMIT_CPU,
+	limitFileSize:     syscall.RLIMIT_FSIZE,
+	limitOpenFiles:    syscall.RLIMIT_NOFILE,
+	limitAddressSpace: syscall.RLIMIT_AS,
+}
+
+func getRlimit(id int) (cur, max uint64, err error) {
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(rlimits[id], &rl); err != nil {
+		return 0, 0, err
+	}
+
+	return rl.Cur, rl.Max, nil
+}
+
+// setRlimit goes through syscall, rather than x/sys/unix, so that the Go
+// runtime passes a new open files limit on to the commands it starts.
+func setRlimit(id int, cur, max uint64) error {
+	return syscall.Setrlimit(rlimits[id], &syscall.Rlimit{Cur: cur, Max: max})
+}
@@ -0,0 +1,88 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func init() {
+	Register("umask", func(ctx *Context, args ...string) error {
+		return Umask(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "umask [-S] [MASK]",
+		Summary:  "print or set the file-creation mask",
+		Flags:    []string{"-S\tprint the mask in symbolic form"},
+	})
+}
+
+// Umask handles the "umask" built-in command.
+// With no MASK it prints the file-creation mask, in octal or, with -S, in the
+// symbolic form of the permissions it allows. MASK is octal (022) or symbolic
+// (u=rwx,g=rx,o=) and applies to files created afterwards, e.g. by touch and mkdir.
+func Umask(w io.Writer, args ...string) error {
+	var symbolic bool
+	if len(args) > 0 && args[0] == "-S" {
+		symbolic = true
+		args = args[1:]
+	}
+	switch len(args) {
+	case 0:
+		mask := currentUmask()
+		if symbolic {
+			_, err := fmt.Fprintln(w, symbolicPerms(^mask&0o777))
+			return err
+		}
+		_, err := fmt.Fprintf(w, "%04o\n", mask)
+		return err
+	case 1:
+	default:
+		return fmt.Errorf("%w: expected at most one mask", ErrInvalidArgCount)
+	}
+
+	mask, err := parseUmask(args[0], currentUmask())
+	if err != nil {
+		return err
+	}
+	setUmask(mask)
+
+	return nil
+}
+
+// parseUmask parses an octal or symbolic mask relative to the current mask.
+func parseUmask(spec string, current uint32) (uint32, error) {
+	if spec != "" && spec[0] >= '0' && spec[0] <= '7' {
+		n, err := strconv.ParseUint(spec, 8, 32)
+		if err != nil || n > 0o777 {
+			return 0, fmt.Errorf("%w: invalid mask %q", ErrInvalidArgs, spec)
+		}
+		return uint32(n), nil
+	}
+
+	// Symbolic modes describe what is allowed, the complement of the mask.
+	allowed, err := applyMode(spec, ^current&0o777, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return ^allowed & 0o777, nil
+}
+
+// symbolicPerms formats permission bits as u=rwx,g=rx,o=rx.
+func symbolicPerms(perm uint32) string {
+	s := ""
+	for i, who := range []string{"u", "g", "o"} {
+		bits := perm >> (6 - 3*uint(i)) & 0o7
+		if i > 0 {
+			s += ","
+		}
+		s += who + "="
+		for j, c := range "rwx" {
+			if bits&(4>>uint(j)) != 0 {
+				s += string(c)
+			}
+		}
+	}
+
+	return s
+}
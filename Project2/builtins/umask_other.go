@@ -0,0 +1,14 @@
+//go:build windows
+
+package builtins
+
+// umask is only remembered here; the platform has no file-creation mask.
+var umask uint32 = 0o022
+
+func currentUmask() uint32 {
+	return umask
+}
+
+func setUmask(mask uint32) {
+	umask = mask
+}
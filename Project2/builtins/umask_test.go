@@ -0,0 +1,62 @@
+package builtins
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_parseUmask(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		spec    string
+		current uint32
+		want    uint32
+		wantErr error
+	}{
+		{name: "octal", spec: "027", current: 0o022, want: 0o027},
+		{name: "symbolic set", spec: "u=rwx,g=rx,o=", current: 0o022, want: 0o027},
+		{name: "symbolic remove", spec: "go-w", current: 0, want: 0o022},
+		{name: "symbolic add", spec: "o+w", current: 0o022, want: 0o020},
+		{name: "too large", spec: "1777", wantErr: ErrInvalidArgs},
+		{name: "bad octal", spec: "08", wantErr: ErrInvalidArgs},
+		{name: "bad symbolic", spec: "u~x", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseUmask(tt.spec, tt.current)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseUmask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseUmask() got = %04o, want %04o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUmask(t *testing.T) {
+	// Not parallel: changes the process's file-creation mask.
+	old := currentUmask()
+	defer setUmask(old)
+
+	var out bytes.Buffer
+	if err := Umask(&out, "027"); err != nil {
+		t.Fatalf("Umask() unexpected error: %v", err)
+	}
+	if err := Umask(&out); err != nil {
+		t.Fatalf("Umask() unexpected error: %v", err)
+	}
+	if err := Umask(&out, "-S"); err != nil {
+		t.Fatalf("Umask() unexpected error: %v", err)
+	}
+	if want := "0027\nu=rwx,g=rx,o=\n"; out.String() != want {
+		t.Errorf("Umask() got = %q, want %q", out.String(), want)
+	}
+	if err := Umask(&out, "1", "2"); !errors.Is(err, ErrInvalidArgCount) {
+		t.Errorf("Umask() error = %v, wantErr %v", err, ErrInvalidArgCount)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package builtins
+
+import "syscall"
+
+func currentUmask() uint32 {
+	// The mask can only be read by setting it, so put it straight back.
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+
+	return uint32(mask)
+}
+
+func setUmask(mask uint32) {
+	syscall.Umask(int(mask))
+}
@@ -0,0 +1,80 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("uniq", func(ctx *Context, args ...string) error {
+		return Uniq(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "uniq [-cd] [FILE]",
+		Summary:  "collapse adjacent duplicate lines",
+		Flags: []string{
+			"-c\tprefix lines with their count",
+			"-d\tonly print duplicated lines",
+		},
+	})
+}
+
+// Uniq handles the "uniq" built-in command.
+// It collapses adjacent duplicate lines of the named file, or of r when no file is given.
+// With -c each line is prefixed by its count, and with -d only duplicated lines are printed.
+func Uniq(r io.Reader, w io.Writer, args ...string) error {
+	var (
+		count, dupsOnly bool
+		files           = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'c':
+				count = true
+			case 'd':
+				dupsOnly = true
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if len(files) > 1 {
+		return fmt.Errorf("%w: expected at most one input file", ErrInvalidArgCount)
+	}
+	if len(files) == 0 {
+		files = append(files, "-")
+	}
+
+	var lines []string
+	if err := withInput(r, files[0], func(in io.Reader) (err error) {
+		lines, err = readLines(in)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(lines); {
+		j := i + 1
+		for j < len(lines) && lines[j] == lines[i] {
+			j++
+		}
+		if n := j - i; !dupsOnly || n > 1 {
+			var err error
+			if count {
+				_, err = fmt.Fprintf(w, "%7d %v\n", n, lines[i])
+			} else {
+				_, err = fmt.Fprintln(w, lines[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		i = j
+	}
+
+	return nil
+}
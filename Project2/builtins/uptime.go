@@ -0,0 +1,54 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+func init() {
+	Register("uptime", func(ctx *Context, args ...string) error {
+		return Uptime(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "uptime",
+		Summary:  "print how long the system has been running and its load",
+	})
+}
+
+// Uptime handles the "uptime" built-in command.
+// It prints the current time, how long the system has been running and the 1, 5 and 15 minute load averages.
+func Uptime(w io.Writer, args ...string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: expected no arguments", ErrInvalidArgCount)
+	}
+
+	up, load, err := systemUptime()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%v up %v,  load average: %.2f, %.2f, %.2f\n",
+		Now().Format("15:04:05"), formatUptime(up), load[0], load[1], load[2])
+	return err
+}
+
+// formatUptime renders a duration the way uptime does, e.g. "3 days,  4:05" or "17 min".
+func formatUptime(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d / time.Hour % 24)
+	mins := int(d / time.Minute % 60)
+
+	var s string
+	switch days {
+	case 0:
+	case 1:
+		s = "1 day, "
+	default:
+		s = fmt.Sprintf("%d days, ", days)
+	}
+	if hours == 0 {
+		return s + fmt.Sprintf("%d min", mins)
+	}
+
+	return s + fmt.Sprintf("%2d:%02d", hours, mins)
+}
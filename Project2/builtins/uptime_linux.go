@@ -0,0 +1,38 @@
+package builtins
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemUptime reads the time since boot from /proc/uptime and the load averages from /proc/loadavg.
+func systemUptime() (time.Duration, [3]float64, error) {
+	var load [3]float64
+	b, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, load, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return 0, load, fmt.Errorf("malformed /proc/uptime")
+	}
+	secs, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, load, err
+	}
+
+	if b, err = os.ReadFile("/proc/loadavg"); err != nil {
+		return 0, load, err
+	}
+	fields = strings.Fields(string(b))
+	for i := 0; i < len(load) && i < len(fields); i++ {
+		if load[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return 0, load, err
+		}
+	}
+
+	return time.Duration(secs * float64(time.Second)), load, nil
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package builtins
+
+import (
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// systemUptime asks gopsutil for the time since boot and the load averages.
+func systemUptime() (time.Duration, [3]float64, error) {
+	var avg [3]float64
+	secs, err := host.Uptime()
+	if err != nil {
+		return 0, avg, err
+	}
+	if l, err := load.Avg(); err == nil {
+		avg = [3]float64{l.Load1, l.Load5, l.Load15}
+	}
+
+	return time.Duration(secs) * time.Second, avg, nil
+}
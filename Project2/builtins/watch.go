@@ -0,0 +1,84 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("watch", func(ctx *Context, args ...string) error {
+		return Watch(ctx.Context(), ctx.Stdout, ctx.Run, args...)
+	}, Meta{
+		Synopsis: "watch [-n SECS] CMD [ARG...]",
+		Summary:  "run a command repeatedly, showing its output",
+		Flags:    []string{"-n SECS\tinterval between runs (default 2)"},
+	})
+}
+
+// Watch handles the "watch" built-in command.
+// It runs a command every -n SECONDS (default 2), clearing the screen and printing a
+// timestamped header before each run, until Ctrl-C. Failures are shown and the
+// command keeps being re-run.
+func Watch(ctx context.Context, w io.Writer, run CommandRunner, args ...string) error {
+	interval := 2 * time.Second
+	if len(args) > 0 && strings.HasPrefix(args[0], "-n") {
+		value := strings.TrimPrefix(args[0], "-n")
+		args = args[1:]
+		if value == "" {
+			if len(args) == 0 {
+				return fmt.Errorf("%w: -n requires an argument", ErrInvalidArgCount)
+			}
+			value, args = args[0], args[1:]
+		}
+		secs, err := strconv.ParseFloat(value, 64)
+		if err != nil || secs <= 0 {
+			return fmt.Errorf("%w: invalid interval %q", ErrInvalidArgs, value)
+		}
+		interval = time.Duration(secs * float64(time.Second))
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected a command", ErrInvalidArgCount)
+	}
+
+	// Ctrl-C stops watching instead of killing the shell.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	clear := w == io.Writer(os.Stdout) && isTerminal(os.Stdout)
+	return watch(ctx, w, run, interval, clear, args)
+}
+
+// watch re-runs argv every interval until ctx is done.
+func watch(ctx context.Context, w io.Writer, run CommandRunner, interval time.Duration, clear bool, argv []string) error {
+	host, _ := os.Hostname()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if clear {
+			if _, err := io.WriteString(w, clearScreen); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "Every %.1fs: %v    %v: %v\n\n",
+			interval.Seconds(), strings.Join(argv, " "), host, Now().Format("Mon Jan 2 15:04:05 2006")); err != nil {
+			return err
+		}
+		if err := run(argv[0], argv[1:]...); err != nil {
+			if _, err := fmt.Fprintln(w, err); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "missing command", args: []string{"-n", "1"}, wantErr: ErrInvalidArgCount},
+		{name: "missing interval", args: []string{"-n"}, wantErr: ErrInvalidArgCount},
+		{name: "bad interval", args: []string{"-n0", "ls"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := Watch(context.Background(), &out, nil, tt.args...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Watch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_watch(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		out  bytes.Buffer
+		runs int
+	)
+	run := func(name string, args ...string) error {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		if runs == 2 {
+			return errors.New("failed once")
+		}
+		_, err := out.WriteString(name + " " + strings.Join(args, " ") + "\n")
+		return err
+	}
+
+	if err := watch(ctx, &out, run, time.Millisecond, false, []string{"ls", "-l"}); err != nil {
+		t.Fatalf("watch() unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("watch() ran the command %v times, want 3", runs)
+	}
+	if got := strings.Count(out.String(), "Every 0.0s: ls -l"); got != 3 {
+		t.Errorf("watch() printed %v headers, want 3:\n%v", got, out.String())
+	}
+	if !strings.Contains(out.String(), "failed once\n") {
+		t.Errorf("watch() did not show the failure:\n%v", out.String())
+	}
+}
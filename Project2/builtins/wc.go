@@ -0,0 +1,146 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// wcCounts holds the tallies reported by wc for one input.
+type wcCounts struct {
+	lines, words, chars, bytes int64
+}
+
+func (c *wcCounts) add(o wcCounts) {
+	c.lines += o.lines
+	c.words += o.words
+	c.chars += o.chars
+	c.bytes += o.bytes
+}
+
+func init() {
+	Register("wc", func(ctx *Context, args ...string) error {
+		return WordCount(ctx.Stdin, ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "wc [-clmw] [FILE...]",
+		Summary:  "count lines, words, characters and bytes",
+		Flags: []string{
+			"-c\tbytes",
+			"-l\tlines",
+			"-m\tcharacters",
+			"-w\twords",
+		},
+	})
+}
+
+// WordCount handles the "wc" built-in command.
+// It prints line (-l), word (-w), character (-m) and byte (-c) counts for each file,
+// or for r when no file (or "-") is given, followed by a total when several files are counted.
+func WordCount(r io.Reader, w io.Writer, args ...string) error {
+	var (
+		lines, words, chars, bytes bool
+		files                      = make([]string, 0)
+	)
+	for _, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			files = append(files, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'l':
+				lines = true
+			case 'w':
+				words = true
+			case 'm':
+				chars = true
+			case 'c':
+				bytes = true
+			default:
+				return fmt.Errorf("%w: unknown flag -%c", ErrInvalidArgs, flag)
+			}
+		}
+	}
+	if !lines && !words && !chars && !bytes {
+		lines, words, bytes = true, true, true
+	}
+
+	report := func(c wcCounts, name string) error {
+		fields := make([]string, 0, 5)
+		if lines {
+			fields = append(fields, fmt.Sprintf("%7d", c.lines))
+		}
+		if words {
+			fields = append(fields, fmt.Sprintf("%7d", c.words))
+		}
+		if chars {
+			fields = append(fields, fmt.Sprintf("%7d", c.chars))
+		}
+		if bytes {
+			fields = append(fields, fmt.Sprintf("%7d", c.bytes))
+		}
+		if name != "" {
+			fields = append(fields, name)
+		}
+		_, err := fmt.Fprintln(w, strings.Join(fields, " "))
+		return err
+	}
+
+	if len(files) == 0 {
+		c, err := countInput(r)
+		if err != nil {
+			return err
+		}
+		return report(c, "")
+	}
+
+	var total wcCounts
+	for _, name := range files {
+		var c wcCounts
+		if err := withInput(r, name, func(in io.Reader) (err error) {
+			c, err = countInput(in)
+			return err
+		}); err != nil {
+			return err
+		}
+		total.add(c)
+		if err := report(c, name); err != nil {
+			return err
+		}
+	}
+	if len(files) > 1 {
+		return report(total, "total")
+	}
+
+	return nil
+}
+
+// countInput tallies the lines, words, characters and bytes of r.
+func countInput(r io.Reader) (wcCounts, error) {
+	var (
+		c      wcCounts
+		br     = bufio.NewReader(r)
+		inWord bool
+	)
+	for {
+		ch, size, err := br.ReadRune()
+		if err == io.EOF {
+			return c, nil
+		} else if err != nil {
+			return c, err
+		}
+		c.bytes += int64(size)
+		c.chars++
+		if ch == '\n' {
+			c.lines++
+		}
+		if unicode.IsSpace(ch) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			c.words++
+		}
+	}
+}
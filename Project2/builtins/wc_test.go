@@ -0,0 +1,83 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestWordCount(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	a := filepath.Join(tmp, "a.txt")
+	b := filepath.Join(tmp, "b.txt")
+	if err := os.WriteFile(a, []byte("one two\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("héllo wörld\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "stdin defaults to lines words bytes",
+			stdin:   "hello world\nbye\n",
+			wantOut: "      2       3      16\n",
+		},
+		{
+			name:    "chars differ from bytes",
+			args:    []string{"-mc", b},
+			wantOut: "     12      14 " + b + "\n",
+		},
+		{
+			name:    "wide characters count once",
+			stdin:   "日本語\n",
+			args:    []string{"-m", "-c"},
+			wantOut: "      4      10\n",
+		},
+		{
+			name:    "totals for several files",
+			args:    []string{"-l", "-w", a, b},
+			wantOut: "      2       3 " + a + "\n      1       2 " + b + "\n      3       5 total\n",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"-x"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "missing file",
+			args:    []string{filepath.Join(tmp, "nope")},
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := builtins.WordCount(strings.NewReader(tt.stdin), &out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("WordCount() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("WordCount() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("WordCount() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package builtins
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when a command cannot be resolved.
+var ErrNotFound = errors.New("not found")
+
+func init() {
+	Register("which", func(ctx *Context, args ...string) error {
+		return Which(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "which [-a] NAME...",
+		Summary:  "locate commands in PATH",
+		Flags:    []string{"-a\tprint every match"},
+	})
+	Register("type", func(ctx *Context, args ...string) error {
+		return Type(ctx.Stdout, ctx.IsFunction, IsBuiltin, args...)
+	}, Meta{
+		Synopsis: "type NAME...",
+		Summary:  "tell whether names are functions, builtins or commands",
+	})
+}
+
+// Which handles the "which" built-in command.
+// It prints the path of the executable each name resolves to on PATH, or every match with -a.
+func Which(w io.Writer, args ...string) error {
+	all := false
+	if len(args) > 0 && args[0] == "-a" {
+		all = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected at least one command name", ErrInvalidArgCount)
+	}
+
+	var missing []string
+	for _, name := range args {
+		paths := lookPath(name, os.Getenv("PATH"), all)
+		if len(paths) == 0 {
+			missing = append(missing, name)
+			continue
+		}
+		for _, p := range paths {
+			if _, err := fmt.Fprintln(w, p); err != nil {
+				return err
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %v", ErrNotFound, strings.Join(missing, " "))
+	}
+
+	return nil
+}
+
+// Type handles the "type" built-in command.
+// It reports whether each name is a shell function, a shell builtin or the
+// path of an external command, in the order the shell looks for them.
+func Type(w io.Writer, isFunction, isBuiltin func(name string) bool, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected at least one command name", ErrInvalidArgCount)
+	}
+
+	var missing []string
+	for _, name := range args {
+		var err error
+		if isFunction(name) {
+			_, err = fmt.Fprintf(w, "%v is a function\n", name)
+		} else if isBuiltin(name) {
+			_, err = fmt.Fprintf(w, "%v is a shell builtin\n", name)
+		} else if paths := lookPath(name, os.Getenv("PATH"), false); len(paths) > 0 {
+			_, err = fmt.Fprintf(w, "%v is %v\n", name, paths[0])
+		} else {
+			missing = append(missing, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %v", ErrNotFound, strings.Join(missing, " "))
+	}
+
+	return nil
+}
+
+// LookPath returns the first executable named name in the directories of
+// pathList, a value of PATH, and whether there is one. Paths are checked
+// directly.
+func LookPath(name, pathList string) (string, bool) {
+	if paths := lookPath(name, pathList, false); len(paths) > 0 {
+		return paths[0], true
+	}
+
+	return "", false
+}
+
+// lookPath returns the first (or, with all, every) executable named name in
+// the directories of pathList. Paths are checked directly.
+func lookPath(name, pathList string, all bool) []string {
+	if IsPath(name) {
+		if isExecutable(name) {
+			return []string{name}
+		}
+		return nil
+	}
+
+	paths := make([]string, 0)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			dir = "."
+		}
+		for _, file := range pathCandidates(name) {
+			p := filepath.Join(dir, file)
+			if !isExecutable(p) {
+				continue
+			}
+			paths = append(paths, p)
+			if !all {
+				return paths
+			}
+		}
+	}
+
+	return paths
+}
+
+// isExecutable reports whether path is a file that can be run.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && IsExecutable(path, info)
+}
@@ -0,0 +1,83 @@
+package builtins_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestWhich(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	for _, p := range []string{filepath.Join(dir1, "tool"), filepath.Join(dir2, "tool")} {
+		if err := os.WriteFile(p, []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir1, "data"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir1+string(os.PathListSeparator)+dir2)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "first match",
+			args:    []string{"tool"},
+			wantOut: filepath.Join(dir1, "tool") + "\n",
+		},
+		{
+			name:    "all matches",
+			args:    []string{"-a", "tool"},
+			wantOut: filepath.Join(dir1, "tool") + "\n" + filepath.Join(dir2, "tool") + "\n",
+		},
+		{
+			name:    "not executable",
+			args:    []string{"data"},
+			wantErr: builtins.ErrNotFound,
+		},
+		{
+			name:    "no names",
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := builtins.Which(&out, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Which() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Which() unexpected error: %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("Which() got = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+
+	t.Run("type", func(t *testing.T) {
+		var out bytes.Buffer
+		isBuiltin := func(name string) bool { return name == "cd" || name == "echo" }
+		isFunction := func(name string) bool { return name == "f" || name == "echo" }
+		if err := builtins.Type(&out, isFunction, isBuiltin, "cd", "tool", "f", "echo"); err != nil {
+			t.Fatalf("Type() unexpected error: %v", err)
+		}
+		want := "cd is a shell builtin\ntool is " + filepath.Join(dir1, "tool") + "\nf is a function\necho is a function\n"
+		if got := out.String(); got != want {
+			t.Errorf("Type() got = %q, want %q", got, want)
+		}
+		if err := builtins.Type(&out, isFunction, isBuiltin, "nope"); !errors.Is(err, builtins.ErrNotFound) {
+			t.Errorf("Type() error = %v, wantErr %v", err, builtins.ErrNotFound)
+		}
+	})
+}
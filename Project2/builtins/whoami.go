@@ -0,0 +1,31 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"os/user"
+)
+
+func init() {
+	Register("whoami", func(ctx *Context, args ...string) error {
+		return WhoAmI(ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "whoami",
+		Summary:  "print the current user name",
+	})
+}
+
+// WhoAmI handles the "whoami" built-in command.
+// It prints the name of the user running the shell.
+func WhoAmI(w io.Writer, args ...string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: expected no arguments", ErrInvalidArgCount)
+	}
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, u.Username)
+	return err
+}
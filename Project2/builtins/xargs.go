@@ -0,0 +1,143 @@
+package builtins
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CommandRunner runs a builtin or external command on behalf of another builtin.
+type CommandRunner func(name string, args ...string) error
+
+func init() {
+	Register("xargs", func(ctx *Context, args ...string) error {
+		// Commands run by xargs get an empty stdin since xargs consumes it.
+		return Xargs(ctx.Stdin, ctx.WithStdin(strings.NewReader("")).Run, args...)
+	}, Meta{
+		Synopsis: "xargs [-0] [-n N] [-I REPLACE] CMD [ARG...]",
+		Summary:  "run a command with arguments read from standard input",
+		Flags: []string{
+			"-0\titems are separated by NUL",
+			"-I REPLACE\trun once per item, replacing REPLACE",
+			"-n N\tuse at most N items per run",
+		},
+	})
+}
+
+// Xargs handles the "xargs" built-in command.
+// It reads items from r (whitespace separated, or NUL separated with -0) and runs the
+// command given (default echo) with them appended, at most N at a time with -n.
+// With -I REPLSTR the command runs once per input line with REPLSTR replaced by the line.
+// It stops at the first failing command.
+func Xargs(r io.Reader, run CommandRunner, args ...string) error {
+	var (
+		maxArgs int
+		replace string
+		nulSep  bool
+	)
+	i := 0
+flags:
+	for ; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-0":
+			nulSep = true
+		case strings.HasPrefix(arg, "-n"), strings.HasPrefix(arg, "-I"):
+			value := arg[2:]
+			if value == "" {
+				if len(args) < i+2 {
+					return fmt.Errorf("%w: %v requires an argument", ErrInvalidArgCount, arg)
+				}
+				value = args[i+1]
+				i++
+			}
+			if arg[1] == 'I' {
+				replace = value
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("%w: invalid number of arguments: %q", ErrInvalidArgs, value)
+			}
+			maxArgs = n
+		default:
+			break flags
+		}
+	}
+	command := args[i:]
+	if len(command) == 0 {
+		command = []string{"echo"}
+	}
+
+	items, err := readItems(r, nulSep, replace != "")
+	if err != nil {
+		return err
+	}
+
+	if replace != "" {
+		for _, item := range items {
+			argv := make([]string, len(command))
+			for j := range command {
+				argv[j] = strings.ReplaceAll(command[j], replace, item)
+			}
+			if err := run(argv[0], argv[1:]...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The command runs once even without any input, like POSIX xargs.
+	if maxArgs == 0 {
+		maxArgs = len(items)
+	}
+	batches := make([][]string, 0)
+	for len(items) > maxArgs {
+		batches = append(batches, items[:maxArgs])
+		items = items[maxArgs:]
+	}
+	batches = append(batches, items)
+	for _, batch := range batches {
+		argv := append(append([]string{}, command[1:]...), batch...)
+		if err := run(command[0], argv...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readItems splits r into xargs input items: NUL-terminated with nulSep,
+// whole lines with byLine, and whitespace-separated words otherwise.
+func readItems(r io.Reader, nulSep, byLine bool) ([]string, error) {
+	if !nulSep && !byLine {
+		b, err := io.ReadAll(r)
+		return strings.Fields(string(b)), err
+	}
+
+	sep := byte('\n')
+	if nulSep {
+		sep = 0
+	}
+	var (
+		items = make([]string, 0)
+		br    = bufio.NewReader(r)
+	)
+	for {
+		item, err := br.ReadString(sep)
+		item = strings.TrimSuffix(item, string(sep))
+		if byLine && !nulSep {
+			item = strings.TrimSpace(item)
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+		if err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return items, err
+		}
+	}
+}
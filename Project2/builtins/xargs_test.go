@@ -0,0 +1,82 @@
+package builtins_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func TestXargs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stdin   string
+		args    []string
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:  "defaults to echo",
+			stdin: "a b\nc\n",
+			want:  [][]string{{"echo", "a", "b", "c"}},
+		},
+		{
+			name: "runs once without input",
+			args: []string{"ls", "-l"},
+			want: [][]string{{"ls", "-l"}},
+		},
+		{
+			name:  "batches with -n",
+			stdin: "1 2 3 4 5",
+			args:  []string{"-n", "2", "rm"},
+			want:  [][]string{{"rm", "1", "2"}, {"rm", "3", "4"}, {"rm", "5"}},
+		},
+		{
+			name:  "nul separated",
+			stdin: "with space\x00other\x00",
+			args:  []string{"-0", "touch"},
+			want:  [][]string{{"touch", "with space", "other"}},
+		},
+		{
+			name:  "replace string per line",
+			stdin: "a.txt\nb c.txt\n",
+			args:  []string{"-I{}", "cp", "{}", "backup/{}"},
+			want:  [][]string{{"cp", "a.txt", "backup/a.txt"}, {"cp", "b c.txt", "backup/b c.txt"}},
+		},
+		{
+			name:    "bad batch size",
+			args:    []string{"-n", "0"},
+			wantErr: builtins.ErrInvalidArgs,
+		},
+		{
+			name:    "missing replace string",
+			args:    []string{"-I"},
+			wantErr: builtins.ErrInvalidArgCount,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := make([][]string, 0)
+			run := func(name string, args ...string) error {
+				got = append(got, append([]string{name}, args...))
+				return nil
+			}
+			if err := builtins.Xargs(strings.NewReader(tt.stdin), run, tt.args...); tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Xargs() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Xargs() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Xargs() ran %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package builtins
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// yesChunk is about how many bytes yes writes at a time.
+const yesChunk = 8192
+
+func init() {
+	Register("yes", func(ctx *Context, args ...string) error {
+		return Yes(ctx.Context(), ctx.Stdout, args...)
+	}, Meta{
+		Synopsis: "yes [STRING...]",
+		Summary:  "print a line (default: y) over and over",
+	})
+}
+
+// Yes handles the "yes" built-in command.
+// It prints its arguments joined by spaces, or y, on a line again and again
+// until the output is closed, as when the reader of a pipe exits, or Ctrl-C
+// is hit.
+func Yes(ctx context.Context, w io.Writer, args ...string) error {
+	line := "y\n"
+	if len(args) > 0 {
+		line = strings.Join(args, " ") + "\n"
+	}
+	chunk := strings.Repeat(line, yesChunk/len(line)+1)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	for ctx.Err() == nil {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			if isBrokenPipe(err) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return &ExitError{Status: StatusInterrupted, Err: ErrInterrupted}
+}
@@ -0,0 +1,60 @@
+package builtins_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// closingWriter accepts n bytes and then fails as a pipe with no reader does.
+type closingWriter struct {
+	b strings.Builder
+	n int
+}
+
+func (w *closingWriter) Write(p []byte) (int, error) {
+	if w.b.Len()+len(p) > w.n {
+		return 0, io.ErrClosedPipe
+	}
+
+	return w.b.Write(p)
+}
+
+func TestYes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+		line string
+	}{
+		{name: "default", line: "y\n"},
+		{name: "words", args: []string{"a", "b"}, line: "a b\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &closingWriter{n: 1 << 16}
+			if err := builtins.Yes(context.Background(), w, tt.args...); err != nil {
+				t.Fatalf("Yes() unexpected error: %v", err)
+			}
+			got := w.b.String()
+			if got == "" || strings.ReplaceAll(got, tt.line, "") != "" {
+				t.Errorf("Yes() got %d bytes, want only %q lines", len(got), tt.line)
+			}
+		})
+	}
+}
+
+func TestTrueFalse(t *testing.T) {
+	t.Parallel()
+	if err := builtins.True("ignored"); err != nil {
+		t.Errorf("True() error = %v, want nil", err)
+	}
+	if status := builtins.StatusOf(builtins.False()); status != builtins.StatusFailure {
+		t.Errorf("False() status = %d, want %d", status, builtins.StatusFailure)
+	}
+}
@@ -1,106 +1,65 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"os/user"
 	"strings"
 
-	"github.com/jar0582/CSCE4600/Project2/builtins" // Change the import path to your actual builtins package
+	"github.com/jar0582/CSCE4600/Project2/shell"
+	"github.com/jar0582/CSCE4600/Project2/sshd"
+	"github.com/jar0582/CSCE4600/Project2/web"
 )
 
 func main() {
-    exit := make(chan struct{}, 2) // buffer this so there's no deadlock.
-    runLoop(os.Stdin, os.Stdout, os.Stderr, exit)
-}
-
-func runLoop(r io.Reader, w, errW io.Writer, exit chan struct{}) {
-    var (
-        input    string
-        err      error
-        readLoop = bufio.NewReader(r)
-    )
-    for {
-        select {
-        case <-exit:
-            _, _ = fmt.Fprintln(w, "exiting gracefully...")
-            return
-        default:
-            if err := printPrompt(w); err != nil {
-                _, _ = fmt.Fprintln(errW, err)
-                continue
-            }
-            if input, err = readLoop.ReadString('\n'); err != nil {
-                _, _ = fmt.Fprintln(errW, err)
-                continue
-            }
-            if err = handleInput(w, input, exit); err != nil {
-                _, _ = fmt.Fprintln(errW, err)
-            }
-        }
-    }
-}
+	restricted := flag.Bool("r", false, "run a restricted shell (see set -r)")
+	serve := flag.String("serve", "", "serve the shell to a browser at `ADDR`, such as :9000")
+	sshAddr := flag.String("ssh", "", "serve the shell over SSH at `ADDR`, such as :2222")
+	hostKey := flag.String("hostkey", "", "with -ssh, the host key `FILE` (default ~/.gosh/ssh_host_ed25519_key)")
+	authorized := flag.String("authorized-keys", "", "with -ssh, the public keys `FILE` allowed in (default ~/.ssh/authorized_keys); keys with options are skipped")
+	allow := flag.String("allow", "", "with -serve or -ssh, only allow the comma-separated `COMMANDS`")
+	dryRun := flag.Bool("dry-run", false, "print each command expanded instead of running it (see set -o explain)")
+	auditFile := flag.String("audit", "", "append a JSON line for each command run to `FILE`")
+	flag.Parse()
 
-func printPrompt(w io.Writer) error {
-    // Get current user.
-    // Don't prematurely memoize this because it might change due to `su`?
-    u, err := user.Current()
-    if err != nil {
-        return err
-    }
-    // Get current working directory.
-    wd, err := os.Getwd()
-    if err != nil {
-        return err
-    }
+	var allowed []string
+	if *allow != "" {
+		allowed = strings.Split(*allow, ",")
+	}
+	var audit io.Writer
+	if *auditFile != "" {
+		f, err := os.OpenFile(*auditFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		exitOnError(err)
+		audit = f
+	}
+	switch {
+	case *serve != "":
+		exitOnError(web.ListenAndServe(*serve, web.Options{Restricted: *restricted, Allow: allowed, Audit: audit}, os.Stderr))
+		return
+	case *sshAddr != "":
+		opts := sshd.Options{HostKey: *hostKey, AuthorizedKeys: *authorized, Restricted: *restricted, Allow: allowed, Audit: audit}
+		exitOnError(sshd.ListenAndServe(*sshAddr, opts, os.Stderr))
+		return
+	}
 
-    // /home/User [Username] $
-    _, err = fmt.Fprintf(w, "%v [%v] $ ", wd, u.Username)
-
-    return err
+	sh := shell.New(os.Stdin, os.Stdout, os.Stderr)
+	if *restricted {
+		sh.Restrict()
+	}
+	if audit != nil {
+		sh.Audit(audit)
+	}
+	if *dryRun {
+		sh.Explain()
+	}
+	os.Exit(sh.Run())
 }
 
-func handleInput(w io.Writer, input string, exit chan<- struct{}) error {
-    // Remove trailing spaces.
-    input = strings.TrimSpace(input)
-
-    // Split the input separate the command name and the command arguments.
-    args := strings.Split(input, " ")
-    name, args := args[0], args[1:]
-
-  //commands
-    switch name {
-    case "cd":
-        return builtins.ChangeDirectory(args...)
-    case "env":
-        return builtins.EnvironmentVariables(w, args...)
-    case "exit": // Add "exit" built-in
-        exit <- struct{}{} // Send a signal to exit.
-        return nil // Don't return an error.
-    case "echo":
-        return builtins.Echo(w, args...) // Add "echo" 
-    case "pwd":
-        return builtins.Pwd(w) // Add "pwd" 
-    case "touch":
-		return builtins.Touch(args...) // Add "touch" 
-    case "date":
-		return builtins.Date(w) // Add "date" 
-    }
-
-    return executeCommand(name, args...)
-}
-
-func executeCommand(name string, arg ...string) error {
-    // Otherwise prep the command
-    cmd := exec.Command(name, arg...)
-
-    // Set the correct output device.
-    cmd.Stderr = os.Stderr
-    cmd.Stdout = os.Stdout
-
-    // Execute the command and return the error.
-    return cmd.Run()
+// exitOnError prints err and exits with status 1 if err isn't nil.
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
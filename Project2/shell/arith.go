@@ -0,0 +1,324 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrArithmetic is returned for invalid arithmetic expressions and division by zero.
+var ErrArithmetic = errors.New("arithmetic error")
+
+// arithVars reads and assigns the variables named in an arithmetic expression.
+type arithVars interface {
+	Get(name string) (string, bool)
+	Set(name, value string) error
+}
+
+// evalArith evaluates an integer expression with C-like operators: + - * / %,
+// comparisons, ! && ||, parentheses, assignments (= += -= *= /= %=) and ++/--.
+// Variables hold decimal numbers; unset or empty ones count as zero.
+func evalArith(expr string, vars arithVars) (int64, error) {
+	a := &arith{src: expr, vars: vars}
+	a.next()
+	v, err := a.assignment()
+	if err != nil {
+		return 0, err
+	}
+	if a.tok != "" {
+		return 0, fmt.Errorf("%w: %q: unexpected %q", ErrArithmetic, expr, a.tok)
+	}
+
+	return v, nil
+}
+
+// arith is a recursive-descent evaluator; tok is the current token ("" at the end).
+type arith struct {
+	src  string
+	pos  int
+	tok  string
+	vars arithVars
+}
+
+// arithOps are the operators, longest first so that "<=" wins over "<".
+var arithOps = []string{
+	"++", "--", "+=", "-=", "*=", "/=", "%=", "==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "=", "(", ")",
+}
+
+func (a *arith) next() {
+	for a.pos < len(a.src) && strings.IndexByte(" \t\n", a.src[a.pos]) >= 0 {
+		a.pos++
+	}
+	if a.pos == len(a.src) {
+		a.tok = ""
+		return
+	}
+	start := a.pos
+	if c := a.src[a.pos]; isNameChar(c) {
+		for a.pos < len(a.src) && isNameChar(a.src[a.pos]) {
+			a.pos++
+		}
+		a.tok = a.src[start:a.pos]
+		return
+	}
+	for _, op := range arithOps {
+		if strings.HasPrefix(a.src[a.pos:], op) {
+			a.pos += len(op)
+			a.tok = op
+			return
+		}
+	}
+	a.pos++
+	a.tok = a.src[start:a.pos]
+}
+
+func (a *arith) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %q: %v", ErrArithmetic, a.src, fmt.Sprintf(format, args...))
+}
+
+// assignment := NAME op= assignment | or
+func (a *arith) assignment() (int64, error) {
+	name := a.tok
+	if name == "" || !isNameStart(name[0]) {
+		return a.or()
+	}
+
+	// Look ahead for an assignment operator after the variable name.
+	pos := a.pos
+	a.next()
+	switch op := a.tok; op {
+	case "=", "+=", "-=", "*=", "/=", "%=":
+		a.next()
+		v, err := a.assignment()
+		if err != nil {
+			return 0, err
+		}
+		if op != "=" {
+			cur, err := a.variable(name)
+			if err != nil {
+				return 0, err
+			}
+			if v, err = a.apply(op[:1], cur, v); err != nil {
+				return 0, err
+			}
+		}
+		return v, a.assign(name, v)
+	}
+	a.pos, a.tok = pos, name
+
+	return a.or()
+}
+
+func (a *arith) or() (int64, error) {
+	v, err := a.and()
+	for err == nil && a.tok == "||" {
+		a.next()
+		var r int64
+		if r, err = a.and(); err == nil {
+			v = bool64(v != 0 || r != 0)
+		}
+	}
+
+	return v, err
+}
+
+func (a *arith) and() (int64, error) {
+	v, err := a.equality()
+	for err == nil && a.tok == "&&" {
+		a.next()
+		var r int64
+		if r, err = a.equality(); err == nil {
+			v = bool64(v != 0 && r != 0)
+		}
+	}
+
+	return v, err
+}
+
+func (a *arith) equality() (int64, error) {
+	return a.binary(a.relational, "==", "!=")
+}
+
+func (a *arith) relational() (int64, error) {
+	return a.binary(a.additive, "<", "<=", ">", ">=")
+}
+
+func (a *arith) additive() (int64, error) {
+	return a.binary(a.multiplicative, "+", "-")
+}
+
+func (a *arith) multiplicative() (int64, error) {
+	return a.binary(a.unary, "*", "/", "%")
+}
+
+// binary parses a left-associative chain of the given operators over operand.
+func (a *arith) binary(operand func() (int64, error), ops ...string) (int64, error) {
+	v, err := operand()
+	for err == nil && contains(ops, a.tok) {
+		op := a.tok
+		a.next()
+		var r int64
+		if r, err = operand(); err == nil {
+			v, err = a.apply(op, v, r)
+		}
+	}
+
+	return v, err
+}
+
+// apply computes l op r.
+func (a *arith) apply(op string, l, r int64) (int64, error) {
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/", "%":
+		if r == 0 {
+			return 0, a.errorf("division by zero")
+		}
+		if op == "/" {
+			return l / r, nil
+		}
+		return l % r, nil
+	case "==":
+		return bool64(l == r), nil
+	case "!=":
+		return bool64(l != r), nil
+	case "<":
+		return bool64(l < r), nil
+	case "<=":
+		return bool64(l <= r), nil
+	case ">":
+		return bool64(l > r), nil
+	case ">=":
+		return bool64(l >= r), nil
+	}
+
+	return 0, a.errorf("unknown operator %q", op)
+}
+
+// unary := (- | + | !) unary | (++ | --) NAME | primary
+func (a *arith) unary() (int64, error) {
+	switch op := a.tok; op {
+	case "-", "+", "!":
+		a.next()
+		v, err := a.unary()
+		switch op {
+		case "-":
+			v = -v
+		case "!":
+			v = bool64(v == 0)
+		}
+		return v, err
+	case "++", "--":
+		a.next()
+		name := a.tok
+		if name == "" || !isNameStart(name[0]) {
+			return 0, a.errorf("%v needs a variable", op)
+		}
+		a.next()
+		return a.step(name, op, true)
+	}
+
+	return a.primary()
+}
+
+// primary := NUMBER | NAME [++ | --] | ( assignment )
+func (a *arith) primary() (int64, error) {
+	tok := a.tok
+	switch {
+	case tok == "":
+		return 0, a.errorf("missing operand")
+	case tok == "(":
+		a.next()
+		v, err := a.assignment()
+		if err != nil {
+			return 0, err
+		}
+		if a.tok != ")" {
+			return 0, a.errorf("missing )")
+		}
+		a.next()
+		return v, nil
+	case tok[0] >= '0' && tok[0] <= '9':
+		a.next()
+		v, err := strconv.ParseInt(tok, 0, 64)
+		if err != nil {
+			return 0, a.errorf("invalid number %q", tok)
+		}
+		return v, nil
+	case isNameStart(tok[0]):
+		a.next()
+		if a.tok == "++" || a.tok == "--" {
+			op := a.tok
+			a.next()
+			return a.step(tok, op, false)
+		}
+		return a.variable(tok)
+	}
+
+	return 0, a.errorf("unexpected %q", tok)
+}
+
+// step increments or decrements a variable, returning the new value (prefix)
+// or the old one (postfix).
+func (a *arith) step(name, op string, prefix bool) (int64, error) {
+	old, err := a.variable(name)
+	if err != nil {
+		return 0, err
+	}
+	v := old + 1
+	if op == "--" {
+		v = old - 1
+	}
+	if err := a.assign(name, v); err != nil {
+		return 0, err
+	}
+	if prefix {
+		return v, nil
+	}
+
+	return old, nil
+}
+
+// variable returns the numeric value of a variable.
+func (a *arith) variable(name string) (int64, error) {
+	s, _ := a.vars.Get(name)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return 0, a.errorf("%v: %q is not a number", name, s)
+	}
+
+	return v, nil
+}
+
+func (a *arith) assign(name string, v int64) error {
+	return a.vars.Set(name, strconv.FormatInt(v, 10))
+}
+
+func bool64(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mapVars is a variable store for evaluating expressions in isolation.
+type mapVars map[string]string
+
+func (m mapVars) Get(name string) (string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+func (m mapVars) Set(name, value string) error {
+	m[name] = value
+	return nil
+}
+
+func Test_evalArith(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr     string
+		want     int64
+		wantVars mapVars
+		wantErr  error
+	}{
+		{expr: "1 + 2 * 3", want: 7},
+		{expr: "(1 + 2) * 3", want: 9},
+		{expr: "7 / 2 - 7 % 2", want: 2},
+		{expr: "10 - 4 - 3", want: 3},
+		{expr: "-3 + +1", want: -2},
+		{expr: "2 < 3 && 3 <= 3 && !(1 == 2)", want: 1},
+		{expr: "1 > 2 || 0 >= 1 || 4 != 4", want: 0},
+		{expr: "0x10 + 010", want: 24},
+		{expr: "n * 2", want: 10},
+		{expr: "unset + 1", want: 1},
+		{expr: "x = n + 1", want: 6, wantVars: mapVars{"x": "6"}},
+		{expr: "n += 2", want: 7, wantVars: mapVars{"n": "7"}},
+		{expr: "n++ + n", want: 11, wantVars: mapVars{"n": "6"}},
+		{expr: "--n", want: 4, wantVars: mapVars{"n": "4"}},
+		{expr: "a = b = 2", want: 2, wantVars: mapVars{"a": "2", "b": "2"}},
+		{expr: "1 / 0", wantErr: ErrArithmetic},
+		{expr: "n %= 0", wantErr: ErrArithmetic},
+		{expr: "1 +", wantErr: ErrArithmetic},
+		{expr: "(1", wantErr: ErrArithmetic},
+		{expr: "1 2", wantErr: ErrArithmetic},
+		{expr: "word", wantErr: ErrArithmetic},
+		{expr: "", wantErr: ErrArithmetic},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			vars := mapVars{"n": "5", "word": "abc"}
+			got, err := evalArith(tt.expr, vars)
+			require.True(t, errors.Is(err, tt.wantErr), "evalArith() error = %v, wantErr %v", err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			for name, want := range tt.wantVars {
+				require.Equal(t, want, vars[name], name)
+			}
+		})
+	}
+}
+
+func TestShell_RunLine_arithmetic(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	for _, line := range []string{
+		`let i=2 "j = i * 3"`,
+		`echo $((j - i)) "$(( (i + 1) % 2 ))"`,
+		`(( i++ )); echo $i $?`,
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "4 1\n3 0\n", w.String())
+
+	tests := []struct {
+		line string
+		want int
+	}{
+		{line: "(( i < j ))", want: 0},
+		{line: "(( i > j ))", want: 1},
+		{line: "let 'i - 3'", want: 1},
+		{line: "let 1 0 2", want: 0},
+		{line: "echo $((1 / 0))", want: 1},
+	}
+	for _, tt := range tests {
+		status, _ := sh.RunLine(tt.line)
+		require.Equal(t, tt.want, status, tt.line)
+	}
+}
@@ -0,0 +1,81 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// auditRecord is the JSON line the audit log gets for each command run.
+type auditRecord struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Cwd      string    `json:"cwd"`
+	Argv     []string  `json:"argv"`
+	Status   int       `json:"status"`
+	Duration float64   `json:"duration"` // in seconds
+}
+
+// auditLog writes audit records, one command at a time, as background jobs
+// run commands concurrently.
+type auditLog struct {
+	mu   sync.Mutex
+	enc  *json.Encoder
+	user string
+}
+
+// Audit makes the shell append a JSON line to w for each simple command it
+// runs, builtins and functions included: when it started, the user, the
+// working directory, the arguments, the exit status and how long it took.
+// The user is the one the process runs as.
+func (s *Shell) Audit(w io.Writer) {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	s.AuditAs(w, name)
+}
+
+// AuditAs is Audit recording name as the user, such as the client a server
+// authenticated for the session rather than the user the server runs as.
+func (s *Shell) AuditAs(w io.Writer, name string) {
+	s.mu.Lock()
+	s.audit = &auditLog{enc: json.NewEncoder(w), user: name}
+	s.mu.Unlock()
+}
+
+// startAudit returns a function recording args in the audit log, if there is
+// one, once the command finishes with err.
+func (s *Shell) startAudit(ctx *builtins.Context, args []string) func(err error) {
+	s.mu.Lock()
+	log := s.audit
+	s.mu.Unlock()
+	if log == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	cwd, _ := os.Getwd()
+
+	return func(err error) {
+		rec := auditRecord{
+			Time:     start,
+			User:     log.user,
+			Cwd:      cwd,
+			Argv:     args,
+			Status:   builtins.StatusOf(err),
+			Duration: time.Since(start).Seconds(),
+		}
+		log.mu.Lock()
+		werr := log.enc.Encode(rec)
+		log.mu.Unlock()
+		if werr != nil {
+			_, _ = fmt.Fprintf(ctx.Stderr, "audit: %v\n", werr)
+		}
+	}
+}
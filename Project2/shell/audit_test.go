@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Audit(t *testing.T) {
+	t.Parallel()
+	var out, log bytes.Buffer
+	sh := New(strings.NewReader(""), &out, &out)
+	sh.Audit(&log)
+	before := time.Now()
+	_, err := sh.RunLine(`f() { echo "$1"; }; f 'a b'; false; x=1`)
+	require.NoError(t, err)
+	require.Equal(t, "a b\n", out.String())
+
+	var records []auditRecord
+	dec := json.NewDecoder(&log)
+	for dec.More() {
+		var rec auditRecord
+		require.NoError(t, dec.Decode(&rec))
+		records = append(records, rec)
+	}
+	require.Len(t, records, 3, "assignments alone aren't commands")
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	for _, rec := range records {
+		require.False(t, rec.Time.Before(before.Truncate(time.Second)))
+		require.NotEmpty(t, rec.User)
+		require.Equal(t, cwd, rec.Cwd)
+		require.GreaterOrEqual(t, rec.Duration, 0.0)
+	}
+	// The function's body finishes, and is recorded, before the call.
+	require.Equal(t, []string{"echo", "a b"}, records[0].Argv)
+	require.Equal(t, []string{"f", "a b"}, records[1].Argv)
+	require.Equal(t, 0, records[1].Status)
+	require.Equal(t, []string{"false"}, records[2].Argv)
+	require.Equal(t, 1, records[2].Status)
+}
+
+func TestShell_AuditAs(t *testing.T) {
+	t.Parallel()
+	var log bytes.Buffer
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	sh.AuditAs(&log, "student (key SHA256:abc)")
+	_, err := sh.RunLine("true")
+	require.NoError(t, err)
+
+	var rec auditRecord
+	require.NoError(t, json.Unmarshal(log.Bytes(), &rec))
+	require.Equal(t, "student (key SHA256:abc)", rec.User)
+	require.Equal(t, []string{"true"}, rec.Argv)
+}
@@ -0,0 +1,351 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("bind", func(ctx *builtins.Context, args ...string) error {
+		return bindCommand(ctx.Stdout, keyBindings, args...)
+	}, builtins.Meta{
+		Synopsis: `bind [-l] [-p] [-r KEYSEQ] ['"KEYSEQ": FUNCTION'...]`,
+		Summary:  "bind keys of the line editor to editing functions",
+		Paged:    true,
+		Flags: []string{
+			"-l\tlist the editing functions",
+			"-p\tlist the key bindings, as bind arguments",
+			"-r KEYSEQ\tremove the binding of KEYSEQ",
+		},
+	})
+}
+
+// editFunc is an editing function of the line editor, which keys are bound
+// to by name; key is the key sequence that ran it. done reports that the line
+// has ended, to be returned as text and err.
+type editFunc func(e *editor, key string) (text string, done bool, err error)
+
+// edit makes an editing function of f, which leaves the line open.
+func edit(f func(e *editor)) editFunc {
+	return func(e *editor, _ string) (string, bool, error) {
+		f(e)
+		return "", false, nil
+	}
+}
+
+// editFuncs are the editing functions by name. The names are readline's,
+// where it has one.
+var editFuncs = map[string]editFunc{
+	"accept-line": func(e *editor, _ string) (string, bool, error) {
+		e.endLine("\n")
+		return string(e.buf) + "\n", true, nil
+	},
+	"cancel-line": func(e *editor, _ string) (string, bool, error) {
+		e.endLine("^C\n")
+		return "", true, errLineCancelled
+	},
+	"delete-char": func(e *editor, key string) (string, bool, error) {
+		// Bound to Ctrl-D, it ends the input on an empty line.
+		if len(e.buf) == 0 && key == string(rune(keyCtrlD)) {
+			return "", true, io.EOF
+		}
+		e.delete(e.pos, e.next(e.pos))
+		return "", false, nil
+	},
+	"bracketed-paste-begin": (*editor).paste,
+	"self-insert": func(e *editor, key string) (string, bool, error) {
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+			e.insert(key)
+		}
+		return "", false, nil
+	},
+	"backward-delete-char": edit(func(e *editor) { e.delete(e.prev(e.pos), e.pos) }),
+	"beginning-of-line":    edit(func(e *editor) { e.moveTo(0) }),
+	"end-of-line":          edit(func(e *editor) { e.moveTo(len(e.buf)) }),
+	"backward-char":        edit(func(e *editor) { e.moveTo(e.prev(e.pos)) }),
+	"forward-char":         edit((*editor).forward),
+	"kill-line":            edit(func(e *editor) { e.delete(e.pos, len(e.buf)) }),
+	"unix-line-discard":    edit(func(e *editor) { e.delete(0, e.pos) }),
+	"unix-word-rubout":     edit(func(e *editor) { e.delete(e.wordStart(), e.pos) }),
+	"clear-screen": edit(func(e *editor) {
+		e.write("\x1b[H\x1b[2J")
+		e.reprint()
+	}),
+	"complete": edit((*editor).completeWord),
+}
+
+// defaultBindings are the keys the line editor starts with, as the bytes the
+// terminal sends for them. Printable keys not bound insert themselves.
+var defaultBindings = map[string]string{
+	"\r":       "accept-line",
+	"\n":       "accept-line",
+	"\x03":     "cancel-line",
+	"\x04":     "delete-char",
+	"\x1b[3~":  "delete-char",
+	"\x7f":     "backward-delete-char",
+	"\x08":     "backward-delete-char",
+	"\x01":     "beginning-of-line",
+	"\x1b[H":   "beginning-of-line",
+	"\x1bOH":   "beginning-of-line",
+	"\x1b[1~":  "beginning-of-line",
+	"\x1b[7~":  "beginning-of-line",
+	"\x05":     "end-of-line",
+	"\x1b[F":   "end-of-line",
+	"\x1bOF":   "end-of-line",
+	"\x1b[4~":  "end-of-line",
+	"\x1b[8~":  "end-of-line",
+	"\x02":     "backward-char",
+	"\x1b[D":   "backward-char",
+	"\x1bOD":   "backward-char",
+	"\x06":     "forward-char",
+	"\x1b[C":   "forward-char",
+	"\x1bOC":   "forward-char",
+	"\x0b":     "kill-line",
+	"\x15":     "unix-line-discard",
+	"\x17":     "unix-word-rubout",
+	"\x0c":     "clear-screen",
+	"\t":       "complete",
+	pasteStart: "bracketed-paste-begin",
+}
+
+// keymap binds key sequences to the names of editing functions.
+type keymap struct {
+	mu   sync.RWMutex
+	keys map[string]string
+}
+
+// newKeymap returns a keymap with the default bindings.
+func newKeymap() *keymap {
+	k := &keymap{keys: make(map[string]string, len(defaultBindings))}
+	for seq, name := range defaultBindings {
+		k.keys[seq] = name
+	}
+
+	return k
+}
+
+// defaultKeys is the keymap of editors given none. It is never changed.
+var defaultKeys = newKeymap()
+
+// keyBindings is the keymap of the shell's line editor, which bind changes.
+// Like the history, it belongs to the process.
+var keyBindings = newKeymap()
+
+// lookup returns the editing function bound to seq.
+func (k *keymap) lookup(seq string) (editFunc, bool) {
+	k.mu.RLock()
+	name, ok := k.keys[seq]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return editFuncs[name], true
+}
+
+// bind binds seq to the editing function name.
+func (k *keymap) bind(seq, name string) error {
+	if _, ok := editFuncs[name]; !ok {
+		return fmt.Errorf("%w: bind: %v: unknown function name", builtins.ErrNotFound, name)
+	}
+	k.mu.Lock()
+	k.keys[seq] = name
+	k.mu.Unlock()
+
+	return nil
+}
+
+// unbind removes the binding of seq.
+func (k *keymap) unbind(seq string) {
+	k.mu.Lock()
+	delete(k.keys, seq)
+	k.mu.Unlock()
+}
+
+// print writes the bindings as bind arguments, sorted by function, with a
+// comment for each function that no key is bound to.
+func (k *keymap) print(w io.Writer) error {
+	k.mu.RLock()
+	bound := map[string][]string{}
+	for seq, name := range k.keys {
+		bound[name] = append(bound[name], formatKeySeq(seq))
+	}
+	k.mu.RUnlock()
+
+	for _, name := range editFuncNames() {
+		seqs := bound[name]
+		if len(seqs) == 0 {
+			if _, err := fmt.Fprintf(w, "# %v (not bound)\n", name); err != nil {
+				return err
+			}
+			continue
+		}
+		sort.Strings(seqs)
+		for _, seq := range seqs {
+			if _, err := fmt.Fprintf(w, "\"%v\": %v\n", seq, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// editFuncNames returns the names of the editing functions in sorted order.
+func editFuncNames() []string {
+	names := make([]string, 0, len(editFuncs))
+	for name := range editFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// bindCommand handles the "bind" built-in command.
+// Each argument binds a key sequence to an editing function of the line
+// editor, written as "KEYSEQ": FUNCTION. In KEYSEQ, \C-x is Ctrl with x,
+// \M-x or \ex is Escape then x, as Alt sends it, and \e, \t, \n, \r, \\
+// and \" are the usual characters. -l lists the editing functions, -p, or no
+// arguments, lists the bindings, and -r removes the binding of a key
+// sequence.
+func bindCommand(w io.Writer, k *keymap, args ...string) error {
+	if len(args) == 0 {
+		return k.print(w)
+	}
+	for len(args) > 0 {
+		switch arg := args[0]; {
+		case arg == "-l":
+			for _, name := range editFuncNames() {
+				if _, err := fmt.Fprintln(w, name); err != nil {
+					return err
+				}
+			}
+			args = args[1:]
+		case arg == "-p":
+			if err := k.print(w); err != nil {
+				return err
+			}
+			args = args[1:]
+		case arg == "-r":
+			if len(args) < 2 {
+				return fmt.Errorf("%w: bind: -r requires a key sequence", builtins.ErrInvalidArgCount)
+			}
+			seq, err := parseKeySeq(strings.Trim(args[1], `"`))
+			if err != nil {
+				return err
+			}
+			k.unbind(seq)
+			args = args[2:]
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("%w: bind: unknown flag %v", builtins.ErrInvalidArgs, arg)
+		default:
+			seq, name, err := parseBinding(arg)
+			if err != nil {
+				return err
+			}
+			if err := k.bind(seq, name); err != nil {
+				return err
+			}
+			args = args[1:]
+		}
+	}
+
+	return nil
+}
+
+// parseBinding parses a binding written "KEYSEQ": FUNCTION.
+func parseBinding(s string) (seq, name string, err error) {
+	invalid := fmt.Errorf("%w: bind: %q: expected \"KEYSEQ\": FUNCTION", builtins.ErrInvalidArgs, s)
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", invalid
+	}
+	end := 1
+	for end < len(s) && s[end] != '"' {
+		if s[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(s) {
+		return "", "", invalid
+	}
+	rest := strings.TrimSpace(s[end+1:])
+	if !strings.HasPrefix(rest, ":") {
+		return "", "", invalid
+	}
+	if seq, err = parseKeySeq(s[1:end]); err != nil {
+		return "", "", err
+	}
+
+	return seq, strings.TrimSpace(rest[1:]), nil
+}
+
+// keyEscapes are the characters written with a backslash in a key sequence.
+var keyEscapes = map[byte]string{
+	'e': "\x1b", 't': "\t", 'n': "\n", 'r': "\r", 'a': "\a",
+	'\\': `\`, '"': `"`, '\'': "'",
+}
+
+// parseKeySeq turns a key sequence as bind writes it into the bytes the
+// terminal sends.
+func parseKeySeq(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		rest := s[i+1:]
+		switch {
+		case strings.HasPrefix(rest, "C-") && len(rest) > 2:
+			if rest[2] == '?' {
+				b.WriteByte(keyBackspace)
+			} else {
+				b.WriteByte(rest[2] & 0x1f)
+			}
+			i += 3
+		case strings.HasPrefix(rest, "M-") && len(rest) > 2:
+			b.WriteByte(keyEscape)
+			b.WriteByte(rest[2])
+			i += 3
+		case rest != "" && keyEscapes[rest[0]] != "":
+			b.WriteString(keyEscapes[rest[0]])
+			i++
+		default:
+			return "", fmt.Errorf("%w: bind: %q: invalid key sequence", builtins.ErrInvalidArgs, s)
+		}
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("%w: bind: empty key sequence", builtins.ErrInvalidArgs)
+	}
+
+	return b.String(), nil
+}
+
+// formatKeySeq writes a key sequence as bind reads it.
+func formatKeySeq(seq string) string {
+	var b strings.Builder
+	for i := 0; i < len(seq); i++ {
+		switch c := seq[i]; {
+		case c == keyEscape:
+			b.WriteString(`\e`)
+		case c == keyBackspace:
+			b.WriteString(`\C-?`)
+		case c < 0x20:
+			b.WriteString(`\C-` + strings.ToLower(string(rune(c+'@'))))
+		case c == '\\' || c == '"':
+			b.WriteString(`\` + string(rune(c)))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
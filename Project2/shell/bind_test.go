@@ -0,0 +1,79 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseKeySeq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		seq     string
+		want    string
+		wantErr bool
+	}{
+		{seq: `\C-g`, want: "\x07"},
+		{seq: `\C-G`, want: "\x07"},
+		{seq: `\C-?`, want: "\x7f"},
+		{seq: `\M-b`, want: "\x1bb"},
+		{seq: `\e[A`, want: "\x1b[A"},
+		{seq: `\t`, want: "\t"},
+		{seq: `x\\\"`, want: `x\"`},
+		{seq: `\q`, wantErr: true},
+		{seq: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.seq, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseKeySeq(tt.seq)
+			if tt.wantErr {
+				require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			again, err := parseKeySeq(formatKeySeq(got))
+			require.NoError(t, err)
+			require.Equal(t, got, again, "formatKeySeq(%q) = %q", got, formatKeySeq(got))
+		})
+	}
+}
+
+func Test_bindCommand(t *testing.T) {
+	t.Parallel()
+	k := newKeymap()
+	var out bytes.Buffer
+	require.NoError(t, bindCommand(&out, k, `"\C-g": beginning-of-line`, `"\M-k": kill-line`, "-r", `\C-a`))
+	require.NoError(t, bindCommand(&out, k, "-p"))
+	require.Contains(t, out.String(), "\"\\C-g\": beginning-of-line\n")
+	require.Contains(t, out.String(), "\"\\ek\": kill-line\n")
+	require.NotContains(t, out.String(), `"\C-a"`)
+
+	e := &editor{in: strings.NewReader("bc\x07a\x1bk\x01\r"), out: &bytes.Buffer{}, keys: k}
+	got, err := e.readLine()
+	require.NoError(t, err)
+	require.Equal(t, "a\n", got, "Ctrl-G goes home, Alt-K kills the line and Ctrl-A does nothing")
+
+	out.Reset()
+	require.NoError(t, bindCommand(&out, k, "-l"))
+	require.Equal(t, strings.Join(editFuncNames(), "\n")+"\n", out.String())
+
+	tests := []struct {
+		args    []string
+		wantErr error
+	}{
+		{args: []string{`"\C-g": no-such-function`}, wantErr: builtins.ErrNotFound},
+		{args: []string{`\C-g: kill-line`}, wantErr: builtins.ErrInvalidArgs},
+		{args: []string{`"\C-g" kill-line`}, wantErr: builtins.ErrInvalidArgs},
+		{args: []string{"-r"}, wantErr: builtins.ErrInvalidArgCount},
+		{args: []string{"-x"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		require.ErrorIs(t, bindCommand(&out, k, tt.args...), tt.wantErr, tt.args)
+	}
+}
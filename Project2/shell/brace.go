@@ -0,0 +1,223 @@
+package shell
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxBraceWords bounds the words one brace expansion may produce, so that a
+// typo such as {1..100000000} can't exhaust memory.
+const maxBraceWords = 1 << 16
+
+// braceExpand performs csh-style brace expansion on w, which happens before
+// any other expansion: a{b,c}d becomes abd acd, {1..5..2} becomes 1 3 5 and
+// {a..c} becomes a b c. Only unquoted braces and commas count, and ${ is left
+// for parameter expansion. A word without a valid brace expression is
+// returned unchanged.
+func braceExpand(w word) []word {
+	chars := splitUnquoted(w)
+	words := braceExpandChars(chars)
+	out := make([]word, len(words))
+	for i, cs := range words {
+		out[i] = joinParts(cs)
+	}
+
+	return out
+}
+
+// splitUnquoted returns w with every unquoted character as its own part.
+func splitUnquoted(w word) []wordPart {
+	var chars []wordPart
+	for _, p := range w {
+		if p.quote != unquoted {
+			chars = append(chars, p)
+			continue
+		}
+		for i := 0; i < len(p.text); i++ {
+			chars = append(chars, wordPart{text: p.text[i : i+1]})
+		}
+	}
+
+	return chars
+}
+
+// joinParts merges neighbouring parts with the same quoting, undoing splitUnquoted.
+func joinParts(chars []wordPart) word {
+	var w word
+	for _, c := range chars {
+		if n := len(w); n > 0 && w[n-1].quote == c.quote && !separate(c.quote) {
+			w[n-1].text += c.text
+			continue
+		}
+		w = append(w, c)
+	}
+
+	return w
+}
+
+// isChar reports whether part is the unquoted character c.
+func isChar(part wordPart, c string) bool {
+	return part.quote == unquoted && part.text == c
+}
+
+func braceExpandChars(chars []wordPart) [][]wordPart {
+	for open := 0; open < len(chars); open++ {
+		if !isChar(chars[open], "{") || (open > 0 && isChar(chars[open-1], "$")) {
+			continue
+		}
+		close, commas := matchBrace(chars, open)
+		if close < 0 {
+			break
+		}
+
+		var alternatives [][]wordPart
+		if len(commas) > 0 {
+			start := open + 1
+			for _, comma := range append(commas, close) {
+				alternatives = append(alternatives, chars[start:comma])
+				start = comma + 1
+			}
+		} else if alternatives = braceSequence(chars[open+1 : close]); alternatives == nil {
+			continue
+		}
+
+		var out [][]wordPart
+		prefix, suffix := chars[:open], chars[close+1:]
+		for _, alt := range alternatives {
+			expanded := make([]wordPart, 0, len(prefix)+len(alt)+len(suffix))
+			expanded = append(append(append(expanded, prefix...), alt...), suffix...)
+			out = append(out, braceExpandChars(expanded)...)
+			if len(out) > maxBraceWords {
+				return [][]wordPart{chars}
+			}
+		}
+		return out
+	}
+
+	return [][]wordPart{chars}
+}
+
+// matchBrace returns the index of the "}" closing the "{" at open, or -1,
+// and the indexes of the commas directly inside it.
+func matchBrace(chars []wordPart, open int) (int, []int) {
+	depth := 0
+	var commas []int
+	for i := open + 1; i < len(chars); i++ {
+		switch {
+		case isChar(chars[i], "{"):
+			depth++
+		case isChar(chars[i], "}"):
+			if depth == 0 {
+				return i, commas
+			}
+			depth--
+		case isChar(chars[i], ",") && depth == 0:
+			commas = append(commas, i)
+		}
+	}
+
+	return -1, nil
+}
+
+// braceSequence expands the inside of {X..Y} or {X..Y..STEP}, where X and Y
+// are both integers or both single letters, returning nil if it is neither.
+// Integers keep the zero padding of the wider end, as in {01..10}.
+func braceSequence(inner []wordPart) [][]wordPart {
+	var b strings.Builder
+	for _, c := range inner {
+		if c.quote != unquoted {
+			return nil
+		}
+		b.WriteString(c.text)
+	}
+	fields := strings.Split(b.String(), "..")
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil
+	}
+	step := int64(1)
+	if len(fields) == 3 {
+		n, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil
+		}
+		if n < 0 {
+			n = -n
+		}
+		if n != 0 {
+			step = n
+		}
+	}
+
+	from, to := fields[0], fields[1]
+	var seq []string
+	if x, err := strconv.ParseInt(from, 10, 64); err == nil {
+		y, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return nil
+		}
+		width := 0
+		if zeroPadded(from) || zeroPadded(to) {
+			width = len(from)
+			if len(to) > width {
+				width = len(to)
+			}
+		}
+		for _, n := range steps(x, y, step) {
+			seq = append(seq, padInt(n, width))
+		}
+	} else if isLetter(from) && isLetter(to) {
+		for _, n := range steps(int64(from[0]), int64(to[0]), step) {
+			seq = append(seq, string(rune(n)))
+		}
+	} else {
+		return nil
+	}
+	if len(seq) > maxBraceWords {
+		return nil
+	}
+
+	out := make([][]wordPart, len(seq))
+	for i, s := range seq {
+		// The generated text is literal, so it is not expanded again.
+		out[i] = []wordPart{{text: s, quote: singleQuoted}}
+	}
+
+	return out
+}
+
+// steps counts from x to y by step, downwards if y < x, stopping early once
+// the count passes maxBraceWords.
+func steps(x, y, step int64) []int64 {
+	if y < x {
+		step = -step
+	}
+	var out []int64
+	for n := x; (step > 0 && n <= y) || (step < 0 && n >= y); n += step {
+		if out = append(out, n); len(out) > maxBraceWords {
+			break
+		}
+	}
+
+	return out
+}
+
+func zeroPadded(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return len(s) > 1 && s[0] == '0'
+}
+
+// padInt formats n with zeros up to width characters, counting its sign.
+func padInt(n int64, width int) string {
+	s := strconv.FormatInt(n, 10)
+	digits := strings.TrimPrefix(s, "-")
+	sign := s[:len(s)-len(digits)]
+	for len(sign)+len(digits) < width {
+		digits = "0" + digits
+	}
+
+	return sign + digits
+}
+
+func isLetter(s string) bool {
+	return len(s) == 1 && ((s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z'))
+}
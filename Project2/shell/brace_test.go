@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_braceExpand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{word: "plain", want: []string{"plain"}},
+		{word: "a{b,c}d", want: []string{"abd", "acd"}},
+		{word: "src/{cmd,pkg,internal}", want: []string{"src/cmd", "src/pkg", "src/internal"}},
+		{word: "{a,b}{1,2}", want: []string{"a1", "a2", "b1", "b2"}},
+		{word: "{a,{b,c}}", want: []string{"a", "b", "c"}},
+		{word: "x{,y}", want: []string{"x", "xy"}},
+		{word: "{1..4}", want: []string{"1", "2", "3", "4"}},
+		{word: "{3..-1}", want: []string{"3", "2", "1", "0", "-1"}},
+		{word: "{1..10..3}", want: []string{"1", "4", "7", "10"}},
+		{word: "{10..1..-4}", want: []string{"10", "6", "2"}},
+		{word: "{08..10}", want: []string{"08", "09", "10"}},
+		{word: "{a..e..2}", want: []string{"a", "c", "e"}},
+		{word: "{C..A}", want: []string{"C", "B", "A"}},
+		{word: `"{a,b}"`, want: []string{"{a,b}"}},
+		{word: `{a\,b}`, want: []string{"{a,b}"}},
+		{word: `pre{x,'y z'}`, want: []string{"prex", "prey z"}},
+		{word: "{a}", want: []string{"{a}"}},
+		{word: "{}", want: []string{"{}"}},
+		{word: "{a..1}", want: []string{"{a..1}"}},
+		{word: "{1..2..x}", want: []string{"{1..2..x}"}},
+		{word: "{a,b", want: []string{"{a,b"}},
+		{word: "${x,y}", want: []string{"${x,y}"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.word, func(t *testing.T) {
+			t.Parallel()
+			nodes, err := parse(tt.word)
+			require.NoError(t, err)
+			var got []string
+			for _, w := range braceExpand(nodes[0].(*command).args[0]) {
+				got = append(got, w.literal())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShell_RunLine_braceExpansion(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("let n=2; echo {$n,x}{1..2}")
+	require.NoError(t, err)
+	require.Equal(t, "21 22 x1 x2\n", w.String(), "brace expansion comes before parameter expansion")
+}
@@ -0,0 +1,372 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.RegisterCompleter("jobs", completeJobs)
+	builtins.RegisterCompleter("disown", completeJobs)
+	builtins.RegisterCompleter("fg", completeJobs)
+	builtins.RegisterCompleter("bg", completeJobs)
+	builtins.RegisterCompleter("kill", completeKill)
+	builtins.Register("complete", func(ctx *builtins.Context, args ...string) error {
+		return completeCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "complete [-p] [-r] [-W WORDS] [-F FUNC] [NAME...]",
+		Summary:  "set how the arguments of commands are completed",
+		Flags: []string{
+			"-W WORDS\tcomplete to the blank-separated WORDS",
+			"-F FUNC\tcomplete to what the function FUNC puts in COMPREPLY",
+			"-r\tremove the completions set for each NAME, or for all",
+			"-p\tlist the completions set, as complete commands",
+		},
+	})
+}
+
+// compSpec is how the arguments of a command complete, as set with the
+// "complete" builtin.
+type compSpec struct {
+	words    []string           // with -W
+	function string             // with -F
+	replaced builtins.Completer // the completer it took the place of, if any
+}
+
+// compSpecs holds the completions set with "complete". Like the completers
+// they are registered as, they belong to the process.
+type compSpecs struct {
+	mu    sync.Mutex
+	specs map[string]compSpec
+}
+
+var userCompletions = &compSpecs{specs: map[string]compSpec{}}
+
+// set registers complete for the command name, keeping spec to list.
+func (c *compSpecs) set(name string, spec compSpec, complete builtins.Completer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.specs[name]; ok {
+		spec.replaced = old.replaced
+	} else {
+		spec.replaced, _ = builtins.LookupCompleter(name)
+	}
+	c.specs[name] = spec
+	builtins.RegisterCompleter(name, complete)
+}
+
+// remove removes the completions set for names, or for all commands, putting
+// back the completers they replaced.
+func (c *compSpecs) remove(names ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) == 0 {
+		for name := range c.specs {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		spec, ok := c.specs[name]
+		if !ok {
+			return fmt.Errorf("%w: complete: %v: no completion specification", builtins.ErrNotFound, name)
+		}
+		delete(c.specs, name)
+		builtins.RegisterCompleter(name, spec.replaced)
+	}
+
+	return nil
+}
+
+// print writes the completions set for names, or for all commands in sorted
+// order, as the complete commands that would set them.
+func (c *compSpecs) print(w io.Writer, names ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) == 0 {
+		for name := range c.specs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		spec, ok := c.specs[name]
+		if !ok {
+			return fmt.Errorf("%w: complete: %v: no completion specification", builtins.ErrNotFound, name)
+		}
+		option := "-F " + quoteWord(spec.function)
+		if spec.function == "" {
+			option = "-W " + quoteWord(strings.Join(spec.words, " "))
+		}
+		if _, err := fmt.Fprintf(w, "complete %v %v\n", option, quoteWord(name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeCommand handles the "complete" built-in command.
+// With -W, the arguments of each NAME complete to the blank-separated WORDS
+// that start with what is typed. With -F, the shell function FUNC is called
+// with the command, the word being typed and the word before it as $1, $2
+// and $3, and the words of the command in the array COMP_WORDS, the index of
+// the one typed in COMP_CWORD; the elements it puts in the array COMPREPLY
+// that start with what is typed are the completions. -r removes the
+// completions set, and -p, or no arguments, lists them.
+func completeCommand(ctx *builtins.Context, args ...string) error {
+	var (
+		spec         compSpec
+		set          bool
+		remove, list bool
+	)
+options:
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "--":
+			args = args[1:]
+			break options
+		case "-p":
+			list = true
+			args = args[1:]
+		case "-r":
+			remove = true
+			args = args[1:]
+		case "-W", "-F":
+			if len(args) < 2 {
+				return fmt.Errorf("%w: complete: %v requires an argument", builtins.ErrInvalidArgCount, args[0])
+			}
+			if args[0] == "-W" {
+				spec.words = strings.Fields(args[1])
+			} else {
+				spec.function = args[1]
+			}
+			set = true
+			args = args[2:]
+		default:
+			return fmt.Errorf("%w: complete: unknown flag %v", builtins.ErrInvalidArgs, args[0])
+		}
+	}
+
+	switch {
+	case remove:
+		return userCompletions.remove(args...)
+	case list || !set && len(args) == 0:
+		return userCompletions.print(ctx.Stdout, args...)
+	case len(args) == 0:
+		return fmt.Errorf("%w: complete: expected a command name", builtins.ErrInvalidArgCount)
+	case spec.words != nil && spec.function != "":
+		return fmt.Errorf("%w: complete: -W and -F cannot be used together", builtins.ErrInvalidArgs)
+	}
+
+	for _, name := range args {
+		complete := builtins.CompleteWords(spec.words...)
+		if spec.function != "" {
+			var err error
+			if complete, err = functionCompleter(ctx, spec.function, name); err != nil {
+				return err
+			}
+		}
+		userCompletions.set(name, spec, complete)
+	}
+
+	return nil
+}
+
+// functionCompleter returns a completer for the command name that calls the
+// shell function fn, as complete -F does. The function runs with no input
+// and its output thrown away, so that it doesn't disturb the line being
+// edited.
+func functionCompleter(ctx *builtins.Context, fn, name string) (builtins.Completer, error) {
+	v, err := shellVars(ctx, "complete")
+	if err != nil {
+		return nil, err
+	}
+	c := ctx.WithContext(context.Background())
+	c.Stdin, c.Stdout, c.Stderr = strings.NewReader(""), io.Discard, io.Discard
+
+	return func(args []string, word string) []string {
+		words := append(append([]string{name}, args...), word)
+		prev := words[len(words)-2]
+		_ = v.SetArray("COMP_WORDS", words)
+		_ = v.Set("COMP_CWORD", strconv.Itoa(len(words)-1))
+		_ = v.SetArray("COMPREPLY", nil)
+		_ = c.Run(fn, name, word, prev)
+		reply, _ := v.Array("COMPREPLY")
+
+		return builtins.CompleteWords(reply...)(args, word)
+	}, nil
+}
+
+// completeJobs completes job specs such as %1.
+func completeJobs(_ []string, word string) []string {
+	var specs []string
+	for _, j := range jobTable.list() {
+		if spec := "%" + strconv.Itoa(j.id); strings.HasPrefix(spec, word) {
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs
+}
+
+// completeKill completes job specs and the IDs of the user's processes, or
+// only job specs once the word starts with %.
+func completeKill(args []string, word string) []string {
+	specs := completeJobs(args, word)
+	if strings.HasPrefix(word, "%") {
+		return specs
+	}
+
+	return append(specs, builtins.CompletePIDs(args, word)...)
+}
+
+// completion is the word before the cursor and the command it belongs to.
+type completion struct {
+	start    int      // where the word starts in the line
+	word     string   // the word with quotes and escapes removed
+	args     []string // the words before it in the same command
+	redirect bool     // the word is the target of a redirection
+}
+
+// completeLine returns where the last word of line, the text before the
+// cursor, starts and what that word may be completed to, escaped for the
+// shell. The first word of a command completes to command names, a
+// word starting with $ to variable names, and the arguments of a command as
+// its registered completer says, or else to file names.
+func (s *Shell) completeLine(line string) (int, []string) {
+	c := splitCompletion(line)
+	var matches []string
+	switch {
+	case strings.HasPrefix(c.word, "$") && !strings.ContainsAny(line[c.start:], `'"\`):
+		for _, name := range s.variableNames() {
+			if v := "$" + name; strings.HasPrefix(v, c.word) {
+				matches = append(matches, v)
+			}
+		}
+		return c.start, matches
+	case c.redirect:
+		matches = builtins.CompleteFiles(c.args, c.word)
+	case len(c.args) == 0 && !strings.ContainsRune(c.word, '/'):
+		matches = builtins.CompleteWords(append(s.functionNames(), commandNames()...)...)(nil, c.word)
+	case len(c.args) == 0:
+		matches = builtins.CompleteFiles(nil, c.word)
+	default:
+		complete, ok := builtins.LookupCompleter(c.args[0])
+		if !ok {
+			complete = builtins.CompleteFiles
+		}
+		matches = complete(c.args[1:], c.word)
+	}
+
+	for i, m := range matches {
+		matches[i] = escapeWord(m)
+	}
+
+	return c.start, matches
+}
+
+// splitCompletion finds the last word of line and the words before it in the
+// same command. Quotes and backslashes are removed as the shell would.
+func splitCompletion(line string) completion {
+	var (
+		c       completion
+		word    strings.Builder
+		inWord  bool
+		quote   byte
+		pending bool // the next word is a redirection target
+	)
+	endWord := func() {
+		if !inWord {
+			return
+		}
+		if pending {
+			pending = false
+		} else {
+			c.args = append(c.args, word.String())
+		}
+		word.Reset()
+		inWord = false
+	}
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				word.WriteByte(ch)
+			}
+			continue
+		case ch == '\\' && i+1 < len(line):
+			if !inWord {
+				c.start, inWord = i, true
+			}
+			i++
+			word.WriteByte(line[i])
+			continue
+		case ch == '\'' || ch == '"':
+			if !inWord {
+				c.start, inWord = i, true
+			}
+			quote = ch
+			continue
+		case ch == ' ' || ch == '\t':
+			endWord()
+			continue
+		case strings.IndexByte(";&|()", ch) >= 0:
+			endWord()
+			c.args, pending = nil, false
+			continue
+		case ch == '<' || ch == '>':
+			endWord()
+			pending = true
+			continue
+		}
+		if !inWord {
+			c.start, inWord = i, true
+		}
+		word.WriteByte(ch)
+	}
+	if !inWord {
+		c.start = len(line)
+	}
+	c.word = word.String()
+	c.redirect = pending
+
+	return c
+}
+
+// variableNames returns the names of the shell and environment variables.
+func (s *Shell) variableNames() []string {
+	names := s.vars.Names()
+	for _, kv := range os.Environ() {
+		if name, _, ok := strings.Cut(kv, "="); ok {
+			names = append(names, name)
+		}
+	}
+
+	return builtins.CompleteWords(names...)(nil, "")
+}
+
+// escapeWord puts a backslash before each character the shell would
+// otherwise split or expand, leaving a leading ~ to expand to the home
+// directory.
+func escapeWord(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(" \t\n'\"\\$`;&|<>(){}*?[]!", s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
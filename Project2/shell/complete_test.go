@@ -0,0 +1,143 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitCompletion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line string
+		want completion
+	}{
+		{line: "", want: completion{}},
+		{line: "ec", want: completion{word: "ec"}},
+		{line: "cd ", want: completion{start: 3, args: []string{"cd"}}},
+		{line: "cat my\\ fi", want: completion{start: 4, word: "my fi", args: []string{"cat"}}},
+		{line: "cat 'my fi", want: completion{start: 4, word: "my fi", args: []string{"cat"}}},
+		{line: "ls; git ch", want: completion{start: 8, word: "ch", args: []string{"git"}}},
+		{line: "sort < in", want: completion{start: 7, word: "in", args: []string{"sort"}, redirect: true}},
+		{line: "echo $HO", want: completion{start: 5, word: "$HO", args: []string{"echo"}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, splitCompletion(tt.line))
+		})
+	}
+}
+
+func TestShell_completeLine(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644))
+
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, sh.vars.Set("GOSH_COMPLETE_TEST", "1"))
+
+	tests := []struct {
+		name      string
+		line      string
+		wantStart int
+		want      []string
+	}{
+		{name: "files", line: "cat " + dir + "/", wantStart: 4, want: []string{escapeWord(dir) + "/notes.txt", escapeWord(dir) + `/sub\ dir/`}},
+		{name: "only directories for cd", line: "cd " + dir + "/", wantStart: 3, want: []string{escapeWord(dir) + `/sub\ dir/`}},
+		{name: "variable", line: "echo $GOSH_COMPLETE_", wantStart: 5, want: []string{"$GOSH_COMPLETE_TEST"}},
+		{name: "no match", line: "help nosuch", wantStart: 5, want: nil},
+		{name: "help builtin", line: "help pw", wantStart: 5, want: []string{"pwd"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start, got := sh.completeLine(tt.line)
+			require.Equal(t, tt.wantStart, start)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	start, got := sh.completeLine("pw")
+	require.Equal(t, 0, start)
+	require.Contains(t, got, "pwd", "the first word completes to commands")
+}
+
+func TestShell_completeCommand(t *testing.T) {
+	// Not parallel: completions belong to the whole process.
+	var out bytes.Buffer
+	sh := New(strings.NewReader(""), &out, &bytes.Buffer{})
+	for _, line := range []string{
+		`complete -W "start stop status" gosh-svc`,
+		`_gosh_tool() { COMPREPLY=("$1" "$3" build bench); }`,
+		`complete -F _gosh_tool gosh-tool`,
+		`complete -W "a b" cd`,
+		`complete`,
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	defer func() { _ = userCompletions.remove() }()
+	require.Equal(t, "complete -W 'a b' cd\ncomplete -W 'start stop status' gosh-svc\ncomplete -F _gosh_tool gosh-tool\n", out.String())
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "gosh-svc st", want: []string{"start", "status", "stop"}},
+		{line: "gosh-svc sta", want: []string{"start", "status"}},
+		{line: "gosh-tool x b", want: []string{"bench", "build"}},
+		{line: "gosh-tool x ", want: []string{"bench", "build", "gosh-tool", "x"}},
+		{line: "cd ", want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		_, got := sh.completeLine(tt.line)
+		require.Equal(t, tt.want, got, tt.line)
+	}
+	prev, _ := sh.vars.Array("COMP_WORDS")
+	require.Equal(t, []string{"gosh-tool", "x", ""}, prev)
+
+	_, err := sh.RunLine("complete -r cd gosh-svc")
+	require.NoError(t, err)
+	_, got := sh.completeLine("gosh-svc st")
+	require.Empty(t, got, "files, not the removed words")
+	c, ok := builtins.LookupCompleter("cd")
+	require.True(t, ok, "cd completes to directories again")
+	require.NotNil(t, c)
+
+	_, err = sh.RunLine("complete -p cd")
+	require.ErrorIs(t, err, builtins.ErrNotFound)
+}
+
+func TestShell_completeLine_jobs(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	_, err := sh.RunLine("sh -c 'exec sleep 10' & sh -c 'exec sleep 10' &")
+	require.NoError(t, err)
+	t.Cleanup(jobTable.hangup)
+	pid := strconv.Itoa(jobTable.list()[0].pids()[0])
+
+	for _, name := range []string{"jobs", "disown", "fg", "bg", "kill"} {
+		_, got := sh.completeLine(name + " %")
+		require.Equal(t, []string{"%1", "%2"}, got, name)
+	}
+	_, got := sh.completeLine("fg %2")
+	require.Equal(t, []string{"%2"}, got)
+
+	_, got = sh.completeLine("kill ")
+	require.Contains(t, got, "%1", "kill completes job specs")
+	require.Contains(t, got, pid, "and process IDs")
+	_, got = sh.completeLine("kill -9 " + pid)
+	require.Contains(t, got, pid)
+	require.NotContains(t, got, "%1")
+}
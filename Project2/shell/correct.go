@@ -0,0 +1,121 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// maxSuggestions is how many command names a "did you mean" lists at most.
+const maxSuggestions = 3
+
+// correct handles a command that wasn't found. It adds the closest command
+// names to err, or with set -o correct, when the shell's own input is being
+// read, it offers to run the closest one instead.
+func (s *Shell) correct(ctx *builtins.Context, args []string, err error) error {
+	matches := suggestCommands(args[0], s.functionNames())
+	if len(matches) == 0 {
+		return err
+	}
+
+	if s.opts.enabled(optCorrect) && ctx.Stdin == io.Reader(s.in) {
+		_, _ = fmt.Fprintf(ctx.Stderr, "correct '%v' to '%v' [y/N]? ", args[0], matches[0])
+		answer, _ := s.in.ReadString('\n')
+		if a := strings.TrimSpace(answer); a == "y" || a == "Y" || a == "yes" {
+			return ctx.Run(matches[0], args[1:]...)
+		}
+	}
+
+	return fmt.Errorf("%w; did you mean %v?", err, strings.Join(matches, " or "))
+}
+
+// notFound reports whether err says that the command name wasn't found.
+func notFound(err error, name string) bool {
+	var execErr *exec.Error
+	return errors.As(err, &execErr) && execErr.Name == name && errors.Is(execErr.Err, exec.ErrNotFound)
+}
+
+// suggestCommands returns the functions, builtins and programs in PATH whose
+// names are closest to name, allowing one edit for short names and two for
+// longer ones. An edit is inserting, deleting or changing a letter, or
+// swapping two adjacent ones.
+func suggestCommands(name string, functions []string) []string {
+	limit := len(name) / 3
+	if limit < 1 {
+		limit = 1
+	} else if limit > 2 {
+		limit = 2
+	}
+
+	best := limit + 1
+	var matches []string
+	seen := map[string]bool{}
+	for _, candidate := range append(functions, commandNames()...) {
+		if seen[candidate] || candidate == name {
+			continue
+		}
+		seen[candidate] = true
+		d := editDistance(name, candidate)
+		switch {
+		case d < best:
+			best, matches = d, []string{candidate}
+		case d == best:
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+
+	return matches
+}
+
+// commandNames returns the builtins and the executables in PATH.
+func commandNames() []string {
+	return append(builtins.Names(), commandIndex.list()...)
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the Levenshtein distance, where swapping two adjacent letters also
+// counts as one edit.
+func editDistance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+func minInt(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+
+	return first
+}
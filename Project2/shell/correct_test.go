@@ -0,0 +1,68 @@
+package shell
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_editDistance(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "grep", b: "grep", want: 0},
+		{a: "grpe", b: "grep", want: 1},
+		{a: "gre", b: "grep", want: 1},
+		{a: "grepp", b: "grep", want: 1},
+		{a: "grap", b: "grep", want: 1},
+		{a: "pyhton", b: "python", want: 1},
+		{a: "", b: "ls", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, editDistance(tt.a, tt.b), "editDistance(%q, %q)", tt.a, tt.b)
+	}
+}
+
+func Test_suggestCommands(t *testing.T) {
+	// Not parallel: sets PATH.
+	t.Setenv("PATH", t.TempDir())
+	require.Equal(t, []string{"echo"}, suggestCommands("ehco", nil))
+	require.Equal(t, []string{"pwd"}, suggestCommands("pdw", nil))
+	require.Empty(t, suggestCommands("zzzzzz", nil))
+	require.Equal(t, []string{"greet"}, suggestCommands("greta", []string{"greet"}))
+}
+
+func TestShell_RunLine_correct(t *testing.T) {
+	// Not parallel: sets PATH.
+	t.Setenv("PATH", t.TempDir())
+
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader("y\nn\n"), w, errs)
+	status, err := sh.RunLine("ehco hi")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+	require.Contains(t, err.Error(), "did you mean echo?")
+	require.Equal(t, builtins.StatusNotFound, status)
+	require.Empty(t, w.String())
+
+	_, err = sh.RunLine("set -o correct; ehco hi")
+	require.NoError(t, err)
+	require.Equal(t, "hi\n", w.String())
+	require.Equal(t, "correct 'ehco' to 'echo' [y/N]? ", errs.String())
+
+	w.Reset()
+	status, err = sh.RunLine("ehco hi")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+	require.Equal(t, builtins.StatusNotFound, status)
+	require.Empty(t, w.String())
+
+	_, err = sh.RunLine("greeting() { echo hello; }; greetign")
+	require.ErrorIs(t, err, exec.ErrNotFound)
+	require.Contains(t, err.Error(), "did you mean greeting?")
+}
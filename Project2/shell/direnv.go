@@ -0,0 +1,317 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("goshenv", func(ctx *builtins.Context, args ...string) error {
+		return goshenvCommand(ctx.Stdout, args...)
+	}, builtins.Meta{
+		Synopsis: "goshenv allow [DIR] | goshenv deny [DIR] | goshenv status",
+		Summary:  "allow or deny loading the .goshenv file of a directory",
+	})
+}
+
+const (
+	// dirEnvFile is the file whose variables are exported in its directory
+	// and the directories under it, when set -o direnv is on.
+	dirEnvFile = ".goshenv"
+	// dirEnvAllowList is where allowed files are listed, under the home
+	// directory, each with the SHA-256 of its contents.
+	dirEnvAllowList = ".gosh/allowed-env"
+)
+
+// loadedEnv is the .goshenv file loaded for the working directory. Like the
+// environment it changes, it belongs to the process.
+var loadedEnv = &dirEnv{}
+
+// dirEnv loads the nearest .goshenv file above the working directory when
+// that changes, and unloads it, restoring the variables it changed, when the
+// directory is left. A file is only loaded once it is allowed, and must be
+// allowed again after it is edited.
+type dirEnv struct {
+	mu      sync.Mutex
+	checked string             // the file and checksum last looked at
+	file    string             // the file loaded, if any
+	saved   map[string]*string // the values it replaced; nil if unset
+}
+
+// updateDirEnv loads or unloads .goshenv files for the working directory, as
+// set -o direnv asks. A restricted shell doesn't load them.
+func (s *Shell) updateDirEnv() {
+	if !s.opts.enabled(optDirenv) || s.opts.enabled(optRestricted) {
+		loadedEnv.update(s.Stderr, "")
+		return
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	loadedEnv.update(s.Stderr, wd)
+}
+
+// update loads the .goshenv file nearest to wd, if it is allowed, unloading
+// the one loaded before. It reports what it does, and files that aren't
+// allowed, on w. An empty wd unloads the file.
+func (d *dirEnv) update(w io.Writer, wd string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path, data, sum := "", []byte(nil), ""
+	if wd != "" {
+		path = findDirEnv(wd)
+	}
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			path = ""
+		} else {
+			sum = checksum(data)
+		}
+	}
+	key := path + "\x00" + sum
+	if key == d.checked {
+		return
+	}
+	d.checked = key
+
+	if d.file != "" {
+		_, _ = fmt.Fprintf(w, "goshenv: unloading %v\n", d.file)
+		d.unload()
+	}
+	if path == "" {
+		return
+	}
+	if !allowedEnv(path, sum) {
+		_, _ = fmt.Fprintf(w, "goshenv: %v is not allowed; run \"goshenv allow\" to load it\n", path)
+		return
+	}
+	vars, err := parseDirEnv(data)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "goshenv: %v: %v\n", path, err)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "goshenv: loading %v\n", path)
+	d.load(path, vars)
+}
+
+// recheck makes the next update look at the file again, as after it is
+// allowed or denied.
+func (d *dirEnv) recheck() {
+	d.mu.Lock()
+	d.checked = ""
+	d.mu.Unlock()
+}
+
+// load exports vars, in order, remembering the values they replace.
+// The caller must hold d.mu.
+func (d *dirEnv) load(path string, vars [][2]string) {
+	d.file = path
+	d.saved = map[string]*string{}
+	for _, kv := range vars {
+		name, value := kv[0], kv[1]
+		if _, ok := d.saved[name]; !ok {
+			if old, set := os.LookupEnv(name); set {
+				d.saved[name] = &old
+			} else {
+				d.saved[name] = nil
+			}
+		}
+		_ = os.Setenv(name, value)
+	}
+}
+
+// unload restores the variables the loaded file changed.
+// The caller must hold d.mu.
+func (d *dirEnv) unload() {
+	for name, old := range d.saved {
+		if old == nil {
+			_ = os.Unsetenv(name)
+		} else {
+			_ = os.Setenv(name, *old)
+		}
+	}
+	d.file, d.saved = "", nil
+}
+
+// findDirEnv returns the .goshenv file in dir or the nearest directory above
+// it, or "" if there is none.
+func findDirEnv(dir string) string {
+	for {
+		path := filepath.Join(dir, dirEnvFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// parseDirEnv reads the NAME=value lines of a .goshenv file, which may start
+// with "export". Blank lines and lines starting with # are skipped. A value
+// in single quotes is taken as it is; otherwise $NAME and ${NAME} are
+// expanded, as set by the lines before, and double quotes are removed.
+func parseDirEnv(data []byte) ([][2]string, error) {
+	var (
+		vars  [][2]string
+		set   = map[string]string{}
+		lines = bufio.NewScanner(bytes.NewReader(data))
+	)
+	lookup := func(name string) string {
+		if v, ok := set[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	}
+	for n := 1; lines.Scan(); n++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || !validName(name) {
+			return nil, fmt.Errorf("line %d: expected NAME=value", n)
+		}
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = os.Expand(value[1:len(value)-1], lookup)
+		default:
+			value = os.Expand(value, lookup)
+		}
+		set[name] = value
+		vars = append(vars, [2]string{name, value})
+	}
+
+	return vars, lines.Err()
+}
+
+// goshenvCommand handles the "goshenv" built-in command.
+// allow adds the .goshenv file of DIR, the working directory by default, to
+// the allow list as it is now, and deny removes it. status prints the file
+// found for the working directory and whether it is allowed and loaded.
+func goshenvCommand(w io.Writer, args ...string) error {
+	if len(args) == 0 || len(args) > 2 || args[0] == "status" && len(args) != 1 {
+		return fmt.Errorf("%w: goshenv: expected allow, deny or status", builtins.ErrInvalidArgCount)
+	}
+	dir := "."
+	if len(args) == 2 {
+		dir = args[1]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "allow", "deny":
+		path := filepath.Join(dir, dirEnvFile)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("goshenv: %w", err)
+		}
+		sum := ""
+		if args[0] == "allow" {
+			sum = checksum(data)
+		}
+		if err := setAllowedEnv(path, sum); err != nil {
+			return fmt.Errorf("goshenv: %w", err)
+		}
+		loadedEnv.recheck()
+		return nil
+	case "status":
+		path := findDirEnv(dir)
+		if path == "" {
+			_, err := fmt.Fprintf(w, "no %v file\n", dirEnvFile)
+			return err
+		}
+		state := "not allowed"
+		if data, err := os.ReadFile(path); err == nil && allowedEnv(path, checksum(data)) {
+			state = "allowed"
+		}
+		loadedEnv.mu.Lock()
+		if loadedEnv.file == path {
+			state += ", loaded"
+		}
+		loadedEnv.mu.Unlock()
+		_, err := fmt.Fprintf(w, "%v: %v\n", path, state)
+		return err
+	default:
+		return fmt.Errorf("%w: goshenv: unknown command %v", builtins.ErrInvalidArgs, args[0])
+	}
+}
+
+// checksum returns the hex SHA-256 of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// allowListPath returns the file listing the allowed .goshenv files.
+func allowListPath() (string, error) {
+	if builtins.HomeDir == "" {
+		return "", fmt.Errorf("%w: no home directory", builtins.ErrInvalidArgs)
+	}
+
+	return filepath.Join(builtins.HomeDir, dirEnvAllowList), nil
+}
+
+// allowedEnv reports whether the file at path is allowed with contents
+// whose checksum is sum.
+func allowedEnv(path, sum string) bool {
+	list, err := allowListPath()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(list)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Contains(append([]byte("\n"), data...), []byte("\n"+sum+" "+path+"\n"))
+}
+
+// setAllowedEnv records sum as the allowed checksum of the file at path, or
+// removes the file from the allow list if sum is empty.
+func setAllowedEnv(path, sum string) error {
+	list, err := allowListPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(list)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if _, p, ok := strings.Cut(line, " "); ok && p != path {
+			b.WriteString(line + "\n")
+		}
+	}
+	if sum != "" {
+		b.WriteString(sum + " " + path + "\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(list), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(list, []byte(b.String()), 0o600)
+}
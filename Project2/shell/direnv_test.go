@@ -0,0 +1,98 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDirEnv(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    [][2]string
+		wantErr bool
+	}{
+		{name: "plain", data: "A=1\nexport B=two\n", want: [][2]string{{"A", "1"}, {"B", "two"}}},
+		{name: "comments and blanks", data: "# env\n\nA=1\n", want: [][2]string{{"A", "1"}}},
+		{name: "expands earlier lines", data: "A=x\nB=\"$A/y\"\nC='$A'\n", want: [][2]string{{"A", "x"}, {"B", "x/y"}, {"C", "$A"}}},
+		{name: "no equals", data: "A\n", wantErr: true},
+		{name: "bad name", data: "1A=x\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseDirEnv([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dirEnv_update(t *testing.T) {
+	// Not parallel: changes the environment, the home directory and the
+	// working directory.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	home := builtins.HomeDir
+	t.Cleanup(func() {
+		builtins.HomeDir = home
+		_ = os.Chdir(wd)
+	})
+	t.Setenv("GOSHENV_TEST", "outer")
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	builtins.HomeDir = filepath.Join(root, "home")
+	project, sub := filepath.Join(root, "project"), filepath.Join(root, "project", "sub")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	file := filepath.Join(project, dirEnvFile)
+	require.NoError(t, os.WriteFile(file, []byte("GOSHENV_TEST=inner\nGOSHENV_NEW=new\n"), 0o644))
+
+	d := &dirEnv{}
+	w := &bytes.Buffer{}
+	d.update(w, sub)
+	require.Equal(t, "goshenv: "+file+" is not allowed; run \"goshenv allow\" to load it\n", w.String())
+	require.Equal(t, "outer", os.Getenv("GOSHENV_TEST"))
+
+	require.NoError(t, os.Chdir(sub))
+	require.NoError(t, goshenvCommand(w, "allow", project))
+	w.Reset()
+	d.update(w, sub)
+	require.Equal(t, "", w.String(), "the file is checked once until it changes")
+	d.recheck()
+	d.update(w, sub)
+	require.Equal(t, "goshenv: loading "+file+"\n", w.String())
+	require.Equal(t, "inner", os.Getenv("GOSHENV_TEST"))
+	require.Equal(t, "new", os.Getenv("GOSHENV_NEW"))
+
+	w.Reset()
+	d.update(w, root)
+	require.Equal(t, "goshenv: unloading "+file+"\n", w.String())
+	require.Equal(t, "outer", os.Getenv("GOSHENV_TEST"))
+	_, set := os.LookupEnv("GOSHENV_NEW")
+	require.False(t, set)
+
+	// Editing the file needs it to be allowed again.
+	require.NoError(t, os.WriteFile(file, []byte("GOSHENV_TEST=edited\n"), 0o644))
+	w.Reset()
+	d.update(w, project)
+	require.Contains(t, w.String(), "is not allowed")
+	require.Equal(t, "outer", os.Getenv("GOSHENV_TEST"))
+
+	require.NoError(t, goshenvCommand(w, "allow", project))
+	require.NoError(t, goshenvCommand(w, "deny", project))
+	w.Reset()
+	require.NoError(t, goshenvCommand(w, "status"))
+	require.Equal(t, file+": not allowed\n", w.String())
+}
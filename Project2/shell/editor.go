@@ -0,0 +1,367 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/mattn/go-runewidth"
+)
+
+// Keys the editing functions and key sequences treat specially. The others
+// are in defaultBindings.
+const (
+	keyCtrlD     = 0x04
+	keyEscape    = 0x1b
+	keyBackspace = 0x7f
+)
+
+// errLineCancelled is returned by the line editor when Ctrl-C abandons a line.
+var errLineCancelled = errors.New("line cancelled")
+
+// editor reads a line from a terminal in raw mode, a key at a time, so that
+// the cursor can be moved and Tab can complete words. It redraws the line
+// from the cursor's position after each change, so it doesn't need to know
+// the prompt, except to reprint it after listing completions. If highlight
+// is set, it renders the line with colors, and the whole line is redrawn. If
+// suggest is set, what it returns to follow the line is shown greyed out
+// after it and the right arrow at the end of the line accepts it.
+//
+// The cursor moves over characters, not runes: a wide character, such as a
+// CJK ideograph, takes two columns, and a combining accent goes with the
+// character before it.
+type editor struct {
+	in        io.RuneReader
+	out       io.Writer
+	prompt    func()
+	complete  func(line string) (start int, matches []string)
+	highlight func(line string) string
+	suggest   func(line string) string
+	keys      *keymap // the key bindings; the defaults if nil
+
+	buf []rune
+	pos int // the cursor, as an index into buf
+}
+
+// readLine returns the line typed, ending in a newline, running the editing
+// function bound to each key. Ctrl-D on an empty line returns io.EOF, and
+// Ctrl-C returns errLineCancelled.
+func (e *editor) readLine() (string, error) {
+	keys := e.keys
+	if keys == nil {
+		keys = defaultKeys
+	}
+	e.buf, e.pos = e.buf[:0], 0
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return string(e.buf), err
+		}
+		key := string(r)
+		if r == keyEscape {
+			seq, err := e.readSequence()
+			if err != nil {
+				return "", err
+			}
+			key += seq
+		}
+
+		f, ok := keys.lookup(key)
+		if !ok {
+			f = editFuncs["self-insert"]
+		}
+		if text, done, err := f(e, key); done || err != nil {
+			return text, err
+		}
+	}
+}
+
+// paste reads a bracketed paste, after its start marker. A paste of more
+// than one line ends the line, which is returned with the paste markers for
+// readPaste to confirm.
+func (e *editor) paste(string) (text string, done bool, err error) {
+	paste, err := e.readPasted()
+	if err != nil {
+		return "", false, err
+	}
+	if !strings.Contains(paste, "\n") {
+		e.insert(paste)
+		return "", false, nil
+	}
+	e.endLine("\n")
+
+	return pasteStart + string(e.buf) + paste + pasteEnd + "\n", true, nil
+}
+
+// readSequence reads the rest of an escape sequence after the escape: "[" or
+// "O" and then parameters up to a final letter or ~.
+func (e *editor) readSequence() (string, error) {
+	var seq strings.Builder
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		seq.WriteRune(r)
+		if seq.Len() > 1 && r >= 0x40 && r <= 0x7e || seq.Len() == 1 && r != '[' && r != 'O' {
+			return seq.String(), nil
+		}
+	}
+}
+
+// readPasted reads pasted text up to the end marker.
+func (e *editor) readPasted() (string, error) {
+	var text strings.Builder
+	for !strings.HasSuffix(text.String(), pasteEnd) {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		if r == '\r' {
+			r = '\n'
+		}
+		text.WriteRune(r)
+	}
+
+	return strings.TrimSuffix(text.String(), pasteEnd), nil
+}
+
+// completeWord completes the word before the cursor. A single match replaces
+// it, followed by a space unless it is a directory. Several matches extend it
+// to their common prefix, or are listed if that adds nothing.
+func (e *editor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	before := string(e.buf[:e.pos])
+	start, matches := e.complete(before)
+	start = len([]rune(before[:start]))
+	word := string(e.buf[start:e.pos])
+	switch len(matches) {
+	case 0:
+		e.write("\a")
+	case 1:
+		text := matches[0]
+		if !strings.HasSuffix(text, "/") {
+			text += " "
+		}
+		e.replace(start, text)
+	default:
+		if prefix := commonPrefix(matches); len(prefix) > len(word) {
+			e.replace(start, prefix)
+			return
+		}
+		end := e.pos
+		e.endLine("\n")
+		e.listCompletions(matches)
+		e.reprint()
+		e.moveTo(end)
+	}
+}
+
+// listCompletions prints matches in columns across the terminal.
+func (e *editor) listCompletions(matches []string) {
+	width := 0
+	for _, m := range matches {
+		if n := runewidth.StringWidth(m); n > width {
+			width = n
+		}
+	}
+	width += 2
+	cols, _ := builtins.TerminalSize(e.out)
+	perLine := cols / width
+	if perLine < 1 {
+		perLine = 1
+	}
+	var b strings.Builder
+	for i, m := range matches {
+		if i%perLine == perLine-1 || i == len(matches)-1 {
+			b.WriteString(m + "\n")
+		} else {
+			b.WriteString(m + strings.Repeat(" ", width-runewidth.StringWidth(m)))
+		}
+	}
+	e.write(b.String())
+}
+
+// reprint prints the prompt and the line again, leaving the cursor at the
+// end of it.
+func (e *editor) reprint() {
+	if e.prompt != nil {
+		e.prompt()
+	}
+	e.pos = len(e.buf)
+	e.redraw(0, 0)
+}
+
+// endLine moves the cursor to the end of the line, clears any suggestion
+// after it and writes text.
+func (e *editor) endLine(text string) {
+	e.moveTo(len(e.buf))
+	e.write("\x1b[K" + text)
+}
+
+// forward moves the cursor right, or accepts the suggestion at the end of
+// the line.
+func (e *editor) forward() {
+	if e.pos < len(e.buf) {
+		e.moveTo(e.next(e.pos))
+	} else if s := e.suggestion(); s != "" {
+		e.insert(s)
+	}
+}
+
+// suggestion returns the text suggested to follow the line, if any.
+func (e *editor) suggestion() string {
+	if e.suggest == nil || len(e.buf) == 0 {
+		return ""
+	}
+
+	return e.suggest(string(e.buf))
+}
+
+// replace replaces the text from start to the cursor.
+func (e *editor) replace(start int, text string) {
+	from := e.cols(0, e.pos)
+	rest := append([]rune(text), e.buf[e.pos:]...)
+	e.buf = append(e.buf[:start], rest...)
+	e.pos = start + len([]rune(text))
+	e.redraw(from, start)
+}
+
+// insert inserts text at the cursor.
+func (e *editor) insert(text string) {
+	e.replace(e.pos, text)
+}
+
+// delete removes the text from start to end.
+func (e *editor) delete(start, end int) {
+	if start < 0 || end > len(e.buf) || start >= end {
+		return
+	}
+	from := e.cols(0, e.pos)
+	e.buf = append(e.buf[:start], e.buf[end:]...)
+	if e.pos > end {
+		e.pos -= end - start
+	} else if e.pos > start {
+		e.pos = start
+	}
+	e.redraw(from, start)
+}
+
+// wordStart returns where the word before the cursor starts, for Ctrl-W.
+func (e *editor) wordStart() int {
+	i := e.pos
+	for i > 0 && e.buf[i-1] == ' ' {
+		i--
+	}
+	for i > 0 && e.buf[i-1] != ' ' {
+		i--
+	}
+
+	return i
+}
+
+// prev returns where the character before pos starts, taking the runes of
+// zero width after it, such as combining accents, with it.
+func (e *editor) prev(pos int) int {
+	for pos > 0 {
+		pos--
+		if runewidth.RuneWidth(e.buf[pos]) > 0 {
+			break
+		}
+	}
+
+	return pos
+}
+
+// next returns where the character after the one at pos starts.
+func (e *editor) next(pos int) int {
+	if pos >= len(e.buf) {
+		return pos
+	}
+	pos++
+	for pos < len(e.buf) && runewidth.RuneWidth(e.buf[pos]) == 0 {
+		pos++
+	}
+
+	return pos
+}
+
+// cols returns how many terminal columns the line from start to end takes.
+func (e *editor) cols(start, end int) int {
+	n := 0
+	for _, r := range e.buf[start:end] {
+		n += runewidth.RuneWidth(r)
+	}
+
+	return n
+}
+
+// moveTo moves the cursor to pos, within the line.
+func (e *editor) moveTo(pos int) {
+	if pos < 0 || pos > len(e.buf) || pos == e.pos {
+		return
+	}
+	// A count of 0 would move the cursor one column.
+	if pos < e.pos {
+		if n := e.cols(pos, e.pos); n > 0 {
+			e.write(fmt.Sprintf("\x1b[%dD", n))
+		}
+	} else if n := e.cols(e.pos, pos); n > 0 {
+		e.write(fmt.Sprintf("\x1b[%dC", n))
+	}
+	e.pos = pos
+}
+
+// redraw rewrites the line from start, where it changed, and the suggestion
+// after it, given that the cursor is from columns into the line on the
+// screen, and puts the cursor at e.pos.
+func (e *editor) redraw(from, start int) {
+	if e.highlight != nil {
+		// A change can recolor the words before it.
+		start = 0
+	}
+	var b strings.Builder
+	if back := from - e.cols(0, start); back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	b.WriteString(e.render(start))
+	suggestion := e.suggestion()
+	if suggestion != "" {
+		b.WriteString(colorGrey + suggestion + colorReset)
+	}
+	b.WriteString("\x1b[K")
+	if back := e.cols(e.pos, len(e.buf)) + runewidth.StringWidth(suggestion); back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	e.write(b.String())
+}
+
+// render returns the line from start as it is shown, highlighted if the
+// editor highlights; start must be 0 then.
+func (e *editor) render(start int) string {
+	if e.highlight != nil {
+		return e.highlight(string(e.buf))
+	}
+
+	return string(e.buf[start:])
+}
+
+func (e *editor) write(s string) {
+	_, _ = io.WriteString(e.out, s)
+}
+
+// commonPrefix returns the longest prefix the words share.
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return string(prefix)
+}
@@ -0,0 +1,93 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_editor(t *testing.T) {
+	t.Parallel()
+	complete := func(line string) (int, []string) {
+		start := strings.LastIndexByte(line, ' ') + 1
+		var matches []string
+		for _, w := range []string{"echo", "exit", "export", "dir/"} {
+			if strings.HasPrefix(w, line[start:]) {
+				matches = append(matches, w)
+			}
+		}
+		return start, matches
+	}
+	tests := []struct {
+		name    string
+		keys    string
+		want    string
+		wantErr error
+	}{
+		{name: "typed", keys: "ls -l\r", want: "ls -l\n"},
+		{name: "backspace", keys: "lx\x7fs\r", want: "ls\n"},
+		{name: "arrows", keys: "cho\x1b[D\x1b[D\x1b[De\x1b[C\x1b[C\x1b[C!\r", want: "echo!\n"},
+		{name: "home and end", keys: "b\x01a\x05c\r", want: "abc\n"},
+		{name: "kill to end", keys: "abcdef\x1b[D\x1b[D\x0b\r", want: "abcd\n"},
+		{name: "kill to start", keys: "abc def\x15x\r", want: "x\n"},
+		{name: "delete word", keys: "echo hello world\x17there\r", want: "echo hello there\n"},
+		{name: "delete key", keys: "abc\x01\x1b[3~\r", want: "bc\n"},
+		{name: "complete one", keys: "ec\t\r", want: "echo \n"},
+		{name: "complete directory", keys: "cd d\t\r", want: "cd dir/\n"},
+		{name: "complete prefix", keys: "exp\t\r", want: "export \n"},
+		{name: "complete common prefix", keys: "e\tx\r", want: "ex\n"},
+		{name: "one-line paste", keys: "echo \x1b[200~hi\x1b[201~\r", want: "echo hi\n"},
+		{name: "multi-line paste", keys: "\x1b[200~a\rb\x1b[201~", want: pasteStart + "a\nb" + pasteEnd + "\n"},
+		{name: "ctrl-c", keys: "abc\x03", wantErr: errLineCancelled},
+		{name: "ctrl-d", keys: "\x04", wantErr: io.EOF},
+		{name: "ctrl-d deletes", keys: "ab\x01\x04\r", want: "b\n"},
+		{name: "backspace wide character", keys: "日本\x7f語\r", want: "日語\n"},
+		{name: "backspace combining accent", keys: "cafe\u0301\x7f\x7fx\r", want: "cax\n"},
+		{name: "arrows over combining accent", keys: "e\u0301\x1b[Dx\x1b[C\x1b[3~\r", want: "xe\u0301\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &editor{in: strings.NewReader(tt.keys), out: &bytes.Buffer{}, complete: complete}
+			got, err := e.readLine()
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "readLine() error = %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_editorListsCompletions(t *testing.T) {
+	t.Parallel()
+	out := &bytes.Buffer{}
+	e := &editor{
+		in:       strings.NewReader("ex\t\r"),
+		out:      out,
+		prompt:   func() { out.WriteString("$ ") },
+		complete: func(string) (int, []string) { return 0, []string{"exit", "export"} },
+	}
+	got, err := e.readLine()
+	require.NoError(t, err)
+	require.Equal(t, "ex\n", got)
+	require.Contains(t, out.String(), "\nexit    export\n$ ex")
+}
+
+func Test_editorWideCharacters(t *testing.T) {
+	t.Parallel()
+	out := &bytes.Buffer{}
+	e := &editor{in: strings.NewReader("日本\x1b[D\x1b[De\u0301\x1b[D\r"), out: out}
+	got, err := e.readLine()
+	require.NoError(t, err)
+	require.Equal(t, "e\u0301日本\n", got)
+	// Each ideograph takes two columns and the accent none.
+	require.Contains(t, out.String(), "\x1b[2D\x1b[2D")
+	require.True(t, strings.HasSuffix(out.String(), "\x1b[1D\x1b[5C\x1b[K\n"), "output %q", out.String())
+}
@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// exitLastStatus asks the shell to exit with the status of the last command.
+const exitLastStatus = -1
+
+func init() {
+	builtins.Register("exit", func(ctx *builtins.Context, args ...string) error {
+		return exitCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "exit [N]",
+		Summary:  "exit the shell with status N (default: the last command's)",
+	})
+}
+
+// exitCommand handles the "exit" built-in command.
+// It asks the shell to exit with status N (modulo 256), or with the last
+// command's status when N is omitted.
+func exitCommand(ctx *builtins.Context, args ...string) error {
+	switch len(args) {
+	case 0:
+		ctx.Exit(exitLastStatus)
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("%w: exit takes at most one argument", builtins.ErrInvalidArgCount)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		// Like other shells, still exit, but report the misuse.
+		ctx.Exit(builtins.StatusUsage)
+		return fmt.Errorf("%w: exit: numeric argument required: %q", builtins.ErrInvalidArgs, args[0])
+	}
+	ctx.Exit(n & 0xff)
+
+	return nil
+}
+
+// shutdown is the one way Run ends, whether by exit, the end of the input,
+// SIGTERM or Shutdown: it runs the EXIT trap, saves the history to $HISTFILE
+// and sends SIGHUP to the jobs left running, then returns status.
+func (s *Shell) shutdown(status int) int {
+	s.runMu.Lock()
+	s.runExitTrap()
+	s.runMu.Unlock()
+	if err := s.saveHistory(); err != nil {
+		_, _ = fmt.Fprintln(s.Stderr, err)
+	}
+	jobTable.hangup()
+	_, _ = fmt.Fprintln(s.Stdout, "exiting gracefully...")
+
+	return status
+}
+
+// shutdownOnTerm makes SIGTERM shut the shell down with status 143, as if it
+// had been killed, unless a trap handles the signal. It returns a function
+// that stops this.
+func (s *Shell) shutdownOnTerm() func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigs:
+				if _, trapped := traps.Action("TERM"); trapped {
+					continue
+				}
+				s.requestExit(builtins.StatusSignal + int(syscall.SIGTERM))
+				s.cancel()
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
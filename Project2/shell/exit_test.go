@@ -0,0 +1,48 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Run_exitStatus(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "explicit status", input: "exit 3\n", want: 3},
+		{name: "status wraps", input: "exit 258\n", want: 2},
+		{name: "last status", input: "false\nexit\n", want: 1},
+		{name: "success resets status", input: "false\ntrue\nexit\n", want: 0},
+		{name: "non-numeric", input: "exit nope\n", want: 2},
+		{name: "eof", input: "", want: 0},
+		{name: "eof after failure", input: "false\n", want: 1},
+		{name: "eof runs last partial line", input: "exit 4", want: 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			got := New(strings.NewReader(tt.input), w, &bytes.Buffer{}).Run()
+			require.Equal(t, tt.want, got)
+			require.Contains(t, w.String(), "exiting gracefully...")
+		})
+	}
+}
+
+func Test_exitCommand(t *testing.T) {
+	t.Parallel()
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	err := exitCommand(sh.context(sh.in, sh.Stdout), "1", "2")
+	require.True(t, errors.Is(err, builtins.ErrInvalidArgCount))
+	_, exiting := sh.exitRequested()
+	require.False(t, exiting)
+}
@@ -0,0 +1,327 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnbound is returned for expanding an unset variable while set -u is on.
+var ErrUnbound = errors.New("unbound variable")
+
+// ErrBadSubstitution is returned for a ${...} expansion the shell can't make
+// sense of.
+var ErrBadSubstitution = errors.New("bad substitution")
+
+// piece is the kind of text expandEach passes on.
+type piece int
+
+const (
+	wordText   piece = iota // the word's own text
+	paramValue              // the value of a parameter
+	fieldBreak              // the break between two elements of ${NAME[@]}
+)
+
+// expandWord expands the parameters in the unquoted and double-quoted parts
+// of w, evaluates its arithmetic expansions and runs its command
+// substitutions.
+func (s *Shell) expandWord(w word) (string, error) {
+	var b strings.Builder
+	for _, part := range w {
+		switch part.quote {
+		case singleQuoted:
+			b.WriteString(part.text)
+		case cmdSubst, quotedCmdSubst:
+			out, err := s.commandOutput(part.text)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(out)
+		case arithmetic:
+			expr, err := s.expand(part.text)
+			if err != nil {
+				return "", err
+			}
+			n, err := evalArith(expr, s.vars)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(strconv.FormatInt(n, 10))
+		default:
+			text, err := s.expand(part.text)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(text)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// heredocWord splits a here-document body into parts like a double-quoted
+// string, so that \$, \` and \\ escape the character after them and
+// command substitutions are run.
+func heredocWord(body string) word {
+	var w word
+	start := 0
+	for i := 0; i+1 < len(body); i++ {
+		if body[i] == '\\' && strings.IndexByte("$`\\", body[i+1]) >= 0 {
+			w = append(w, wordPart{text: body[start:i], quote: doubleQuoted}, wordPart{text: body[i+1 : i+2], quote: singleQuoted})
+			i++
+			start = i + 1
+			continue
+		}
+		p := &parser{src: body, pos: i}
+		var (
+			text string
+			err  error
+		)
+		switch {
+		case body[i] == '`':
+			text, err = p.backquoted(i)
+		case strings.HasPrefix(body[i:], "$(") && !strings.HasPrefix(body[i:], "$(("):
+			text, err = p.substitution()
+		default:
+			continue
+		}
+		if err != nil {
+			// An unclosed substitution is left as text.
+			continue
+		}
+		w = append(w, wordPart{text: body[start:i], quote: doubleQuoted}, wordPart{text: text, quote: quotedCmdSubst})
+		i = p.pos - 1
+		start = p.pos
+	}
+
+	return append(w, wordPart{text: body[start:], quote: doubleQuoted})
+}
+
+// expand replaces the parameters in a word: $? (the last exit status), $$ (the
+// shell's process ID), $! (the last background process ID), $NAME or ${NAME}
+// (variables, empty if unset, or an error with set -u), the array expansions
+// ${NAME[INDEX]}, ${NAME[@]}, ${NAME[*]} and ${#NAME[@]}, and the string
+// operations of expandParam. The elements of ${NAME[@]} are joined with
+// spaces.
+func (s *Shell) expand(word string) (string, error) {
+	if !strings.Contains(word, "$") {
+		return word, nil
+	}
+
+	var b strings.Builder
+	err := s.expandEach(word, func(text string, kind piece) {
+		if kind == fieldBreak {
+			text = " "
+		}
+		b.WriteString(text)
+	})
+
+	return b.String(), err
+}
+
+// expandEach expands the parameters in word as expand does, passing emit
+// each run of the word's own text and each parameter's value in turn, with a
+// fieldBreak between the elements of ${NAME[@]}.
+func (s *Shell) expandEach(word string, emit func(text string, kind piece)) error {
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			emit(lit.String(), wordText)
+			lit.Reset()
+		}
+	}
+	value := func(v string) {
+		flush()
+		emit(v, paramValue)
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] != '$' || i+1 == len(word) {
+			lit.WriteByte(word[i])
+			continue
+		}
+		var name string
+		switch next := word[i+1]; {
+		case next == '?':
+			value(strconv.Itoa(s.Status()))
+			i++
+			continue
+		case next == '$':
+			value(strconv.Itoa(os.Getpid()))
+			i++
+			continue
+		case next == '!':
+			pid, ok := jobTable.lastBackground()
+			if ok {
+				value(strconv.Itoa(pid))
+			}
+			i++
+			continue
+		case next == '#' || next == '@' || next == '*' || next >= '1' && next <= '9':
+			flush()
+			if err := s.positional(word[i+1:i+2], emit); err != nil {
+				return err
+			}
+			i++
+			continue
+		case next == '{':
+			end := paramEnd(word, i)
+			if end < 0 {
+				lit.WriteString(word[i:])
+				i = len(word)
+				continue
+			}
+			param := word[i+2 : end]
+			i = end
+			flush()
+			if err := s.expandParam(param, emit); err != nil {
+				return err
+			}
+			continue
+		case isNameStart(next):
+			j := i + 1
+			for j < len(word) && isNameChar(word[j]) {
+				j++
+			}
+			name = word[i+1 : j]
+			i = j - 1
+		default:
+			lit.WriteByte('$')
+			continue
+		}
+		v, err := s.variable(name)
+		if err != nil {
+			return err
+		}
+		value(v)
+	}
+	flush()
+
+	return nil
+}
+
+// expandArray expands the ${...} array parameter param: NAME[@] or NAME[*]
+// (the elements), NAME[INDEX] (one element, counting back from the end if
+// INDEX is negative), #NAME[@] (the number of elements) or #NAME[INDEX] (the
+// length of an element). NAME[*] joins the elements with the first character
+// of $IFS.
+func (s *Shell) expandArray(param string, emit func(string, piece)) error {
+	count := strings.HasPrefix(param, "#")
+	param = strings.TrimPrefix(param, "#")
+	open := strings.IndexByte(param, '[')
+	if open < 0 || !strings.HasSuffix(param, "]") || !validName(param[:open]) {
+		return fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+	}
+	name, index := param[:open], param[open+1:len(param)-1]
+
+	if index == "@" || index == "*" {
+		values, ok := s.vars.Array(name)
+		if !ok && s.opts.enabled(optNounset) {
+			return fmt.Errorf("%s: %w", name, ErrUnbound)
+		}
+		if count {
+			emit(strconv.Itoa(len(values)), paramValue)
+		} else {
+			s.emitValues(values, index == "*", emit)
+		}
+		return nil
+	}
+
+	i, err := s.subscript(index)
+	if err != nil {
+		return err
+	}
+	v, ok := s.vars.Element(name, i)
+	if !ok && s.opts.enabled(optNounset) {
+		return fmt.Errorf("%s[%d]: %w", name, i, ErrUnbound)
+	}
+	if count {
+		v = strconv.Itoa(utf8.RuneCountInString(v))
+	}
+	emit(v, paramValue)
+
+	return nil
+}
+
+// emitValues passes emit the elements of an array or the positional
+// parameters, with a fieldBreak between each, or joined with the first
+// character of $IFS for ${NAME[*]} and $*.
+func (s *Shell) emitValues(values []string, join bool, emit func(string, piece)) {
+	if join {
+		sep, ok := s.vars.Get("IFS")
+		if !ok {
+			sep = defaultIFS
+		}
+		if sep != "" {
+			sep = sep[:1]
+		}
+		emit(strings.Join(values, sep), paramValue)
+		return
+	}
+	for i, v := range values {
+		if i > 0 {
+			emit("", fieldBreak)
+		}
+		emit(v, paramValue)
+	}
+}
+
+// positional expands the positional parameter $N, or $# (their number), $@
+// or $*.
+func (s *Shell) positional(param string, emit func(string, piece)) error {
+	args := s.vars.Args()
+	switch param {
+	case "#":
+		emit(strconv.Itoa(len(args)), paramValue)
+		return nil
+	case "@", "*":
+		s.emitValues(args, param == "*", emit)
+		return nil
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 1 {
+		return fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+	}
+	if n > len(args) {
+		if s.opts.enabled(optNounset) {
+			return fmt.Errorf("%s: %w", param, ErrUnbound)
+		}
+		emit("", paramValue)
+		return nil
+	}
+	emit(args[n-1], paramValue)
+
+	return nil
+}
+
+// subscript evaluates an array index, an arithmetic expression.
+func (s *Shell) subscript(index string) (int, error) {
+	expr, err := s.expand(index)
+	if err != nil {
+		return 0, err
+	}
+	i, err := evalArith(expr, s.vars)
+
+	return int(i), err
+}
+
+// variable returns the value of a shell or environment variable. An unset
+// variable is empty, or an error if nounset (set -u) is on.
+func (s *Shell) variable(name string) (string, error) {
+	v, ok := s.vars.Get(name)
+	if !ok && s.opts.enabled(optNounset) {
+		return "", fmt.Errorf("%s: %w", name, ErrUnbound)
+	}
+
+	return v, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
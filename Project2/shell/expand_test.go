@@ -0,0 +1,61 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_expand(t *testing.T) {
+	t.Setenv("GREETING", "hello")
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	sh.status = 3
+
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "plain", want: "plain"},
+		{word: "$?", want: "3"},
+		{word: "status=$?.", want: "status=3."},
+		{word: "$$", want: strconv.Itoa(os.Getpid())},
+		{word: "$GREETING-world", want: "hello-world"},
+		{word: "${GREETING}s", want: "hellos"},
+		{word: "$UNSET_FOR_TEST", want: ""},
+		{word: "cost: $5", want: "cost: "},
+		{word: "trailing$", want: "trailing$"},
+		{word: "${open", want: "${open"},
+		{word: "${list[1]}-${list[-1]}", want: "b-c"},
+		{word: "${list[i+1]}", want: "c"},
+		{word: "${list[7]}", want: ""},
+		{word: "${list[@]}", want: "a b c"},
+		{word: "${#list[@]} ${#list[2]}", want: "3 1"},
+		{word: "${GREETING[0]}", want: "hello"},
+	}
+	require.NoError(t, sh.vars.SetArray("list", []string{"a", "b", "c"}))
+	require.NoError(t, sh.vars.Set("i", "1"))
+	for _, tt := range tests {
+		got, err := sh.expand(tt.word)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, tt.word)
+	}
+}
+
+func TestShell_RunLine_status(t *testing.T) {
+	t.Parallel()
+	script := filepath.Join(t.TempDir(), "exit7.sh")
+	require.NoError(t, os.WriteFile(script, []byte("exit 7\n"), 0o644))
+
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	for _, line := range []string{"false", "echo $?", "echo $?", "sh " + script} {
+		_, _ = sh.RunLine(line)
+	}
+	require.Equal(t, "1\n0\n", w.String())
+	require.Equal(t, 7, sh.Status(), "external commands report their real exit code")
+}
@@ -0,0 +1,110 @@
+package shell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// Explain turns on explain mode (set -o explain), as for "gosh -dry-run".
+func (s *Shell) Explain() {
+	_ = s.opts.SetOption(optExplain, true)
+}
+
+// explain prints a simple command as explain mode (set -o explain) shows it,
+// expanded but not run: its assignments, words and redirections after
+// brace, tilde, parameter and arithmetic expansion, field splitting and
+// pathname expansion. Process and command substitutions are shown but not
+// run and no files are opened. There are no aliases to expand.
+// Assignments alone still set their variables, and set still runs, so that
+// explain mode can be turned off again.
+func (s *Shell) explain(ctx *builtins.Context, cmd *command) error {
+	words := make([]word, len(cmd.args))
+	for i, w := range cmd.args {
+		words[i] = showSubstitutions(w)
+	}
+	args, err := s.expandWords(words)
+	if err != nil {
+		return err
+	}
+	assigns := make([]*assignment, len(cmd.assigns))
+	for i, a := range cmd.assigns {
+		shown := *a
+		shown.value = showSubstitutions(a.value)
+		shown.array = make([]word, len(a.array))
+		for j, w := range a.array {
+			shown.array[j] = showSubstitutions(w)
+		}
+		assigns[i] = &shown
+	}
+	restore, fields, err := s.assign(assigns, len(args) > 0)
+	restore()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		fields = append(fields, quoteWords(args))
+	}
+	for _, r := range cmd.redirs {
+		text, err := s.explainRedirect(r)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, text)
+	}
+	if _, err := fmt.Fprintln(ctx.Stdout, strings.Join(fields, " ")); err != nil {
+		return err
+	}
+	if len(args) > 0 && args[0] == "set" {
+		return ctx.Run(args[0], args[1:]...)
+	}
+
+	return nil
+}
+
+// explainRedirect returns a redirection with its target expanded, leaving
+// out the file descriptor where it is the default one. A here-document is
+// shown by its delimiter.
+func (s *Shell) explainRedirect(r *redirect) (string, error) {
+	defaultFD, fd := 1, ""
+	if r.op[0] == '<' {
+		defaultFD = 0
+	}
+	if r.fd != defaultFD {
+		fd = strconv.Itoa(r.fd)
+	}
+	if r.op == redirHeredoc || r.op == redirHeredocT {
+		var delim strings.Builder
+		for _, part := range r.target {
+			delim.WriteString(part.text)
+		}
+		return fd + r.op + quoteWord(delim.String()), nil
+	}
+	target, err := s.expandWord(s.tildeExpand(showSubstitutions(r.target)))
+	if err != nil {
+		return "", err
+	}
+
+	return fd + r.op + quoteWord(target), nil
+}
+
+// showSubstitutions returns w with its process and command substitutions as
+// literal text, so that explain shows them without running them.
+func showSubstitutions(w word) word {
+	out := make(word, len(w))
+	for i, part := range w {
+		switch part.quote {
+		case procIn:
+			part = wordPart{text: "<(" + part.text + ")", quote: singleQuoted}
+		case procOut:
+			part = wordPart{text: ">(" + part.text + ")", quote: singleQuoted}
+		case cmdSubst, quotedCmdSubst:
+			part = wordPart{text: "$(" + part.text + ")", quote: singleQuoted}
+		}
+		out[i] = part
+	}
+
+	return out
+}
@@ -0,0 +1,50 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Explain(t *testing.T) {
+	t.Parallel()
+	out := filepath.Join(t.TempDir(), "out")
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	tests := []struct {
+		name    string
+		line    string
+		wantOut string
+	}{
+		{name: "quoting", line: `v="a  b"; echo $v "$v" '$v'`, wantOut: "v='a  b'\necho a b 'a  b' '$v'\n"},
+		{name: "braces", line: "echo {x,y}.go", wantOut: "echo x.go y.go\n"},
+		{name: "arrays", line: `a=(1 "2 3"); printf '%s\n' "${a[@]}" $((1+1))`, wantOut: "a=(1 '2 3')\nprintf '%s\\n' 1 '2 3' 2\n"},
+		{name: "assignments before a command", line: "x=1 env; echo [$x]", wantOut: "x=1 env\necho '[]'\n"},
+		{name: "redirections", line: "sort <in >" + out + " 2>&1 <<<\"a b\"", wantOut: "sort <in >" + out + " 2>&1 <<<'a b'\n"},
+		{name: "process substitution", line: "diff <(sort a) b", wantOut: "diff '<(sort a)' b\n"},
+		{name: "pathname expansion", line: "ls " + dir + "/*.go '*.go'", wantOut: "ls " + dir + "/a.go " + dir + "/b.go '*.go'\n"},
+		{name: "command substitution", line: "x=$(date) rm `ls`", wantOut: "x='$(date)' rm '$(ls)'\n"},
+		{name: "loops and groups", line: "for f in 1 2; do { echo $f; } >" + out + "; done", wantOut: "echo 1\necho 2\n"},
+		{name: "turned off", line: "set +o explain; echo ran", wantOut: "set +o explain\nran\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout bytes.Buffer
+			sh := New(strings.NewReader(""), &stdout, &stdout)
+			sh.Explain()
+			_, err := sh.RunLine(tt.line)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOut, stdout.String())
+			_, err = os.Stat(out)
+			require.True(t, os.IsNotExist(err), "no files are opened")
+		})
+	}
+}
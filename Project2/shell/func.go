@@ -0,0 +1,252 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("local", func(ctx *builtins.Context, args ...string) error {
+		return localCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "local [NAME[=VALUE]...]",
+		Summary:  "declare variables local to the running function",
+	})
+	builtins.Register("shift", func(ctx *builtins.Context, args ...string) error {
+		return shiftCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "shift [N]",
+		Summary:  "drop the first N (by default 1) positional parameters",
+	})
+	builtins.Register("return", func(ctx *builtins.Context, args ...string) error {
+		return returnCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "return [N]",
+		Summary:  "leave the running function with status N (default: the last command's)",
+	})
+}
+
+// maxCallDepth is how deeply function calls may nest, which stops a function
+// that calls itself without end.
+const maxCallDepth = 1000
+
+// defineFunc defines the function f, replacing any function of that name.
+func (s *Shell) defineFunc(f *funcDef) {
+	s.mu.Lock()
+	if s.funcs == nil {
+		s.funcs = map[string]*funcDef{}
+	}
+	s.funcs[f.name] = f
+	s.mu.Unlock()
+}
+
+// function returns the body of the function name.
+func (s *Shell) function(name string) (node, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.funcs[name]
+	if !ok {
+		return nil, false
+	}
+
+	return f.body, true
+}
+
+// definitions returns the source of each function's definition, by name.
+func (s *Shell) definitions() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defs := make(map[string]string, len(s.funcs))
+	for name, f := range s.funcs {
+		defs[name] = f.src
+	}
+
+	return defs
+}
+
+// isFunction reports whether name is a function.
+func (s *Shell) isFunction(name string) bool {
+	_, ok := s.function(name)
+	return ok
+}
+
+// functionNames returns the names of the functions in sorted order.
+func (s *Shell) functionNames() []string {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.funcs))
+	for name := range s.funcs {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+
+	return names
+}
+
+// snapshotFuncs returns a function restoring the functions defined now.
+func (s *Shell) snapshotFuncs() func() {
+	s.mu.Lock()
+	saved := make(map[string]*funcDef, len(s.funcs))
+	for name, f := range s.funcs {
+		saved[name] = f
+	}
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		s.funcs = saved
+		s.mu.Unlock()
+	}
+}
+
+// callFunction runs the body of a function with args as its positional
+// parameters. Variables it declares local are restored when it returns.
+func (s *Shell) callFunction(ctx *builtins.Context, name string, body node, args []string) error {
+	if s.vars.depth() >= maxCallDepth {
+		return fmt.Errorf("%w: %v: maximum function nesting level exceeded (%d)", builtins.ErrInvalidArgs, name, maxCallDepth)
+	}
+	s.vars.pushFrame(args)
+	defer s.vars.popFrame()
+
+	err := s.runNode(ctx, body)
+	var r *funcReturn
+	if !errors.As(err, &r) {
+		return err
+	}
+	status := r.status
+	if status == exitLastStatus {
+		status = s.Status()
+	}
+	if status == builtins.StatusSuccess {
+		return nil
+	}
+
+	return &builtins.ExitError{Status: status}
+}
+
+// runCommand runs the function name if there is one, or else the builtin or
+// program as runCommand does, if the shell allows it.
+func (s *Shell) runCommand(ctx *builtins.Context, name string, args ...string) error {
+	if body, ok := s.function(name); ok {
+		return s.callFunction(ctx, name, body, args)
+	}
+
+	return runCommand(ctx, name, args...)
+}
+
+// shellVars returns the shell's variables from ctx, for the builtins that
+// need more of them than builtins.Variables offers.
+func shellVars(ctx *builtins.Context, cmd string) (*variables, error) {
+	v, ok := ctx.Vars.(*variables)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v: no shell variables", builtins.ErrInvalidArgs, cmd)
+	}
+
+	return v, nil
+}
+
+// localCommand handles the "local" built-in command.
+// Each NAME is made local to the running function, set to VALUE or else
+// empty, and put back as it was when the function returns. Without
+// arguments, it lists the function's local variables.
+func localCommand(ctx *builtins.Context, args ...string) error {
+	v, err := shellVars(ctx, "local")
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return printLocals(ctx.Stdout, v)
+	}
+
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if err := v.Local(name); err != nil {
+			return err
+		}
+		if hasValue {
+			if err := v.Set(name, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printLocals writes the local variables of the running function as
+// NAME=VALUE lines.
+func printLocals(w io.Writer, v *variables) error {
+	if v.depth() == 0 {
+		return fmt.Errorf("%w: local: can only be used in a function", builtins.ErrInvalidArgs)
+	}
+	for _, name := range v.Locals() {
+		value, _ := v.Get(name)
+		if _, err := fmt.Fprintf(w, "%v=%v\n", name, quoteWord(value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shiftCommand handles the "shift" built-in command.
+// It drops the first N positional parameters, so that $N+1 becomes $1. It
+// fails if there are fewer than N.
+func shiftCommand(ctx *builtins.Context, args ...string) error {
+	v, err := shellVars(ctx, "shift")
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: shift: expected at most one argument", builtins.ErrInvalidArgCount)
+	}
+	n := 1
+	if len(args) == 1 {
+		if n, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("%w: shift: %v: numeric argument required", builtins.ErrInvalidArgs, args[0])
+		}
+	}
+
+	return v.Shift(n)
+}
+
+// funcReturn is what return gives back. It is passed up through the
+// commands of a function's body, and any loops in it, to the call.
+type funcReturn struct {
+	status int
+}
+
+func (r *funcReturn) Error() string {
+	return "return: not in a function"
+}
+
+// returnCommand handles the "return" built-in command.
+// It leaves the running function, which then has status N (modulo 256), or
+// without N, the status of the last command run.
+func returnCommand(ctx *builtins.Context, args ...string) error {
+	v, err := shellVars(ctx, "return")
+	if err != nil {
+		return err
+	}
+	if v.depth() == 0 {
+		return fmt.Errorf("%w: return: can only be used in a function", builtins.ErrInvalidArgs)
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: return takes at most one argument", builtins.ErrInvalidArgCount)
+	}
+	if len(args) == 0 {
+		return &funcReturn{status: exitLastStatus}
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: return: %v: numeric argument required", builtins.ErrInvalidArgs, args[0])
+	}
+
+	return &funcReturn{status: n & 0xff}
+}
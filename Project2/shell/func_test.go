@@ -0,0 +1,155 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_RunLine_functions(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	for _, line := range []string{
+		`args() { echo "$# [$1] [$2] ${3}"; for a in "$@"; do echo "<$a>"; done; }`,
+		`args one "two words"`,
+		"rest() { shift; echo $*; shift 2; echo $#; }",
+		"rest a b c d",
+		"x=global; inner() { echo $x; x=changed; }",
+		"outer() { local x=local; inner; echo $x; }",
+		"outer; echo $x",
+		"list() { local a=1 b; local; }",
+		"list",
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "2 [one] [two words] \n<one>\n<two words>\nb c d\n1\nlocal\nchanged\nglobal\na=1\nb=''\n", w.String())
+	require.Empty(t, sh.vars.Args(), "the positional parameters end with the call")
+}
+
+func TestShell_RunLine_typeFunction(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("f() { echo; }; type f echo; pwd() { echo mine; }; type pwd")
+	require.NoError(t, err)
+	require.Equal(t, "f is a function\necho is a shell builtin\npwd is a function\n", w.String())
+}
+
+func TestShell_RunLine_return(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("f() { false; return; echo no; }; f; echo $?; g() { for i in 1 2; do while true; do return 300; done; done; }; g; echo $?")
+	require.NoError(t, err)
+	require.Equal(t, "1\n44\n", w.String())
+
+	_, err = sh.RunLine("return")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+}
+
+func TestShell_RunLine_functionErrors(t *testing.T) {
+	t.Parallel()
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	tests := []struct {
+		line    string
+		wantErr error
+	}{
+		{line: "local x", wantErr: builtins.ErrInvalidArgs},
+		{line: "shift", wantErr: builtins.ErrInvalidArgs},
+		{line: "f() { shift 2; }; f a", wantErr: builtins.ErrInvalidArgs},
+		{line: "f() { shift x; }; f a", wantErr: builtins.ErrInvalidArgs},
+		{line: "f() { local 1x; }; f", wantErr: builtins.ErrInvalidArgs},
+		{line: "f() { f; }; f", wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		_, err := sh.RunLine(tt.line)
+		require.ErrorIs(t, err, tt.wantErr, tt.line)
+	}
+}
+
+// Not parallel: a local variable hides one in the environment.
+func TestShell_RunLine_localHidesEnvironment(t *testing.T) {
+	t.Setenv("GOSH_TEST_VAR", "exported")
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("f() { local GOSH_TEST_VAR=mine; echo $GOSH_TEST_VAR; printenv GOSH_TEST_VAR; }; f")
+	require.Error(t, err, "printenv finds no variable")
+	require.Equal(t, "mine\n", w.String())
+	require.Equal(t, "exported", os.Getenv("GOSH_TEST_VAR"))
+}
+
+func TestShell_RunLine_subshellFunctions(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("(f() { echo inside; }; f); f")
+	require.Error(t, err)
+	require.Equal(t, "inside\n", w.String())
+}
+
+func TestShell_RunLine_getopts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		line    string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "flags and arguments",
+			line:    `f() { while getopts ab:c o; do case $o in a|c) echo "-$o";; b) echo "-b $OPTARG";; esac; done; echo "$? $o"; shift $((OPTIND-1)); echo "$@"; }; f -a -b x -cbfile rest -a`,
+			wantOut: "-a\n-b x\n-c\n-b file\n0 ?\nrest -a\n",
+		},
+		{
+			name:    "end of options",
+			line:    `f() { getopts a o; echo $o; getopts a o; echo $? $OPTIND $o; }; f -a -- -a`,
+			wantOut: "a\n1 3 ?\n",
+		},
+		{
+			name:    "errors reported",
+			line:    `f() { while getopts a: o; do echo "$o [$OPTARG]"; done; }; f -x -a`,
+			wantOut: "? []\n? []\n",
+			wantErr: "getopts: illegal option -- x\ngetopts: option requires an argument -- a\n",
+		},
+		{
+			name:    "errors silent",
+			line:    `f() { while getopts :a: o; do case $o in \?) echo "unknown -$OPTARG";; :) echo "-$OPTARG needs a value";; esac; done; }; f -x -a`,
+			wantOut: "unknown -x\n-a needs a value\n",
+		},
+		{
+			name:    "usage error returns",
+			line:    `f() { local OPTIND o; while getopts v o; do case $o in v) echo verbose;; *) echo usage; return 2;; esac; done; echo ran; }; f -x; echo $?; f -v; echo $?`,
+			wantOut: "usage\n2\nverbose\nran\n0\n",
+			wantErr: "getopts: illegal option -- x\n",
+		},
+		{
+			name:    "arguments given",
+			line:    `OPTIND=1; getopts xy o -y; echo $o; getopts xy o -y; echo $?`,
+			wantOut: "y\n1\n",
+		},
+		{
+			name:    "starting over",
+			line:    `f() { getopts ab o; echo $o; OPTIND=1; getopts ab o; echo $o; }; f -ab`,
+			wantOut: "a\na\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out, errOut bytes.Buffer
+			sh := New(strings.NewReader(""), &out, &errOut)
+			_, err := sh.RunLine(tt.line)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+			require.Equal(t, tt.wantErr, errOut.String())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package shell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("getopts", func(ctx *builtins.Context, args ...string) error {
+		return getoptsCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "getopts OPTSTRING NAME [ARG...]",
+		Summary:  "parse the options of a script or function one at a time",
+	})
+}
+
+// getoptsCommand handles the "getopts" built-in command.
+// Each call sets NAME to the next option in the positional parameters, or
+// in the ARGs if given, and OPTIND to the index of the argument to look at
+// next. A letter followed by ':' in OPTSTRING takes an argument, which is put
+// in OPTARG. An unknown option or a missing argument sets NAME to '?' and
+// prints an error, unless OPTSTRING starts with ':': then NAME is '?' or ':'
+// and OPTARG the option. At "--" or the first argument that is not an
+// option, it sets NAME to '?' and fails with status 1.
+// Setting OPTIND to 1 starts over.
+func getoptsCommand(ctx *builtins.Context, args ...string) error {
+	v, err := shellVars(ctx, "getopts")
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: getopts: expected OPTSTRING and NAME", builtins.ErrInvalidArgCount)
+	}
+	optstring, name := args[0], args[1]
+	if !validName(name) {
+		return fmt.Errorf("%w: getopts: `%v': not a valid identifier", builtins.ErrInvalidArgs, name)
+	}
+	params := args[2:]
+	if len(params) == 0 {
+		params = v.Args()
+	}
+	silent := strings.HasPrefix(optstring, ":")
+	if silent {
+		optstring = optstring[1:]
+	}
+
+	ind := 1
+	if value, ok := v.Get("OPTIND"); ok {
+		if ind, err = strconv.Atoi(value); err != nil || ind < 1 {
+			ind = 1
+		}
+	}
+	v.mu.RLock()
+	pos := v.optPos
+	v.mu.RUnlock()
+
+	opt, optarg, ind, pos, status := nextOption(ctx, optstring, silent, params, ind, pos)
+	if err := v.Set("OPTIND", strconv.Itoa(ind)); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	v.optPos = pos
+	v.mu.Unlock()
+	if err := v.Set("OPTARG", optarg); err != nil {
+		return err
+	}
+	if err := v.Set(name, opt); err != nil {
+		return err
+	}
+
+	return status
+}
+
+// nextOption finds the option at pos in params[ind-1] and returns it with its
+// argument and where the next one is. At the end of the options it returns
+// "?" and a status 1 error.
+func nextOption(ctx *builtins.Context, optstring string, silent bool, params []string, ind, pos int) (opt, optarg string, nextInd, nextPos int, err error) {
+	if pos > 0 && (ind > len(params) || pos >= len(params[ind-1])) {
+		pos = 0 // the parameters changed under it
+	}
+	if pos == 0 {
+		if ind > len(params) {
+			return "?", "", ind, 0, &builtins.ExitError{Status: builtins.StatusFailure}
+		}
+		arg := params[ind-1]
+		if arg == "--" {
+			return "?", "", ind + 1, 0, &builtins.ExitError{Status: builtins.StatusFailure}
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			return "?", "", ind, 0, &builtins.ExitError{Status: builtins.StatusFailure}
+		}
+		pos = 1
+	}
+	arg := params[ind-1]
+	c := arg[pos]
+	pos++
+	if pos == len(arg) {
+		ind, pos = ind+1, 0
+	}
+
+	i := strings.IndexByte(optstring, c)
+	if c == ':' || i < 0 {
+		if silent {
+			return "?", string(c), ind, pos, nil
+		}
+		_, _ = fmt.Fprintf(ctx.Stderr, "getopts: illegal option -- %c\n", c)
+		return "?", "", ind, pos, nil
+	}
+	if i+1 == len(optstring) || optstring[i+1] != ':' {
+		return string(c), "", ind, pos, nil
+	}
+
+	switch {
+	case pos > 0: // the rest of the group, as in -ofile
+		optarg = arg[pos:]
+		ind, pos = ind+1, 0
+	case ind <= len(params):
+		optarg = params[ind-1]
+		ind++
+	case silent:
+		return ":", string(c), ind, pos, nil
+	default:
+		_, _ = fmt.Fprintf(ctx.Stderr, "getopts: option requires an argument -- %c\n", c)
+		return "?", "", ind, pos, nil
+	}
+
+	return string(c), optarg, ind, pos, nil
+}
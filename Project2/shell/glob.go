@@ -0,0 +1,130 @@
+package shell
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// hasGlob reports whether pattern has a *, ? or [ not escaped by a
+// backslash, which makes a field a pathname pattern.
+func hasGlob(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '*', '?', '[':
+			return true
+		}
+	}
+
+	return false
+}
+
+// unescapePattern removes the backslashes escaping characters in pattern.
+func unescapePattern(pattern string) string {
+	if !strings.Contains(pattern, `\`) {
+		return pattern
+	}
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+		}
+		b.WriteByte(pattern[i])
+	}
+
+	return b.String()
+}
+
+// glob returns the paths matching pattern in sorted order, matching each
+// slash-separated part against the names in one directory. A * or ? doesn't
+// match a leading dot in a name, which only a pattern part starting with a
+// dot matches.
+func glob(pattern string) []string {
+	var dirs []string
+	parts := strings.Split(pattern, "/")
+	if parts[0] == "" {
+		// An absolute pattern starts from the root.
+		dirs, parts = []string{"/"}, parts[1:]
+	} else {
+		dirs = []string{""}
+	}
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if part == "" {
+			// A doubled or trailing slash only matches directories.
+			if !last {
+				continue
+			}
+			var matched []string
+			for _, dir := range dirs {
+				if info, err := os.Stat(dir); err == nil && info.IsDir() {
+					matched = append(matched, dir+"/")
+				}
+			}
+			return matched
+		}
+
+		var next []string
+		for _, dir := range dirs {
+			next = append(next, globDir(dir, part, last)...)
+		}
+		if dirs = next; len(dirs) == 0 {
+			return nil
+		}
+	}
+	sort.Strings(dirs)
+
+	return dirs
+}
+
+// globDir returns the paths in dir matching the pattern part, which must be
+// directories unless the part is the last.
+func globDir(dir, part string, last bool) []string {
+	if !hasGlob(part) {
+		path := joinPath(dir, unescapePattern(part))
+		if info, err := os.Stat(path); err != nil || !last && !info.IsDir() {
+			return nil
+		}
+		return []string{path}
+	}
+
+	read := dir
+	if read == "" {
+		read = "."
+	}
+	entries, err := os.ReadDir(read)
+	if err != nil {
+		return nil
+	}
+	var matched []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(part, ".") && !strings.HasPrefix(part, `\.`) {
+			continue
+		}
+		if !matchPattern(part, name) {
+			continue
+		}
+		path := joinPath(dir, name)
+		if !last {
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				continue
+			}
+		}
+		matched = append(matched, path)
+	}
+
+	return matched
+}
+
+// joinPath adds name to dir as the pattern wrote it, keeping a leading "./"
+// that filepath.Join would drop.
+func joinPath(dir, name string) string {
+	if dir == "" || strings.HasSuffix(dir, "/") {
+		return dir + name
+	}
+
+	return dir + "/" + name
+}
@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", ".hidden.go", "x y.txt", "sub/c.go", "sub/deep/d.go", "[a].go"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{name: "star", pattern: "*.go", want: []string{"[a].go", "a.go", "b.go"}},
+		{name: "question mark", pattern: "?.go", want: []string{"a.go", "b.go"}},
+		{name: "bracket", pattern: "[ab].go", want: []string{"a.go", "b.go"}},
+		{name: "escaped bracket", pattern: `\[a\].go`, want: []string{"[a].go"}},
+		{name: "hidden names need a dot", pattern: ".*.go", want: []string{".hidden.go"}},
+		{name: "spaces kept", pattern: "*.txt", want: []string{"x y.txt"}},
+		{name: "directories", pattern: "*/*.go", want: []string{"sub/c.go"}},
+		{name: "trailing slash", pattern: "*/", want: []string{"sub/"}},
+		{name: "literal parts", pattern: "sub/deep/*", want: []string{"sub/deep/d.go"}},
+		{name: "no match", pattern: "*.c", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var want []string
+			for _, w := range tt.want {
+				want = append(want, dir+"/"+w)
+			}
+			require.Equal(t, want, glob(escapePattern(dir)+"/"+tt.pattern))
+		})
+	}
+}
+
+func TestShell_RunLine_pathnameExpansion(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	tests := []struct {
+		name    string
+		line    string
+		wantOut string
+	}{
+		{name: "unquoted", line: "echo DIR/*.go", wantOut: "DIR/a.go DIR/b.go\n"},
+		{name: "quoted", line: `echo "DIR/*.go" 'DIR/*.go' DIR/\*.go`, wantOut: "DIR/*.go DIR/*.go DIR/*.go\n"},
+		{name: "variable", line: `p="DIR/*.txt"; echo $p "$p"`, wantOut: "DIR/c.txt DIR/*.txt\n"},
+		{name: "quoted part of a pattern", line: `d=DIR; echo "$d"/*.txt`, wantOut: "DIR/c.txt\n"},
+		{name: "no match kept", line: "echo DIR/*.c", wantOut: "DIR/*.c\n"},
+		{name: "for loop", line: "for f in DIR/*.go; do echo [$f]; done", wantOut: "[DIR/a.go]\n[DIR/b.go]\n"},
+		{name: "noglob", line: "set -f; echo DIR/*.go; set +f; echo DIR/*.txt", wantOut: "DIR/*.go\nDIR/c.txt\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var stdout bytes.Buffer
+			sh := New(strings.NewReader(""), &stdout, &stdout)
+			_, err := sh.RunLine(strings.ReplaceAll(tt.line, "DIR", dir))
+			require.NoError(t, err)
+			require.Equal(t, strings.ReplaceAll(tt.wantOut, "DIR", dir), stdout.String())
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("hash", func(ctx *builtins.Context, args ...string) error {
+		return hashCommand(ctx.Stdout, args...)
+	}, builtins.Meta{
+		Synopsis: "hash [-r] [-d NAME...] [-t NAME...] [NAME...]",
+		Summary:  "remember or list the full paths of commands",
+		Flags: []string{
+			"-d\tforget the named commands",
+			"-r\tforget all commands",
+			"-t\tprint the remembered path of each command",
+		},
+	})
+}
+
+// commandHash remembers where commands were found in PATH, so that running
+// one again doesn't search every directory. Like the working directory and
+// environment, PATH belongs to the process, so there is one table.
+var commandHash = &hashTable{}
+
+// hashEntry is a remembered command.
+type hashEntry struct {
+	path string
+	hits int
+}
+
+// hashTable maps command names to paths. It forgets everything when PATH
+// changes.
+type hashTable struct {
+	mu      sync.Mutex
+	pathEnv string // PATH when the entries were found
+	entries map[string]*hashEntry
+}
+
+// sync clears the table if PATH has changed since it was filled.
+// The caller must hold h.mu.
+func (h *hashTable) sync() {
+	if env := os.Getenv("PATH"); h.entries == nil || env != h.pathEnv {
+		h.pathEnv = env
+		h.entries = map[string]*hashEntry{}
+	}
+}
+
+// lookPath returns the full path of a command, searching PATH only if it is
+// not remembered or the remembered file is gone. Paths are not searched for
+// and are returned as they are.
+func (h *hashTable) lookPath(name string) (string, error) {
+	if builtins.IsPath(name) {
+		return name, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sync()
+	if e, ok := h.entries[name]; ok {
+		if _, err := os.Stat(e.path); err == nil {
+			e.hits++
+			return e.path, nil
+		}
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		delete(h.entries, name)
+		return "", err
+	}
+	h.entries[name] = &hashEntry{path: path, hits: 1}
+
+	return path, nil
+}
+
+// remember looks a command up and adds it to the table without counting a hit.
+func (h *hashTable) remember(name string) error {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sync()
+	h.entries[name] = &hashEntry{path: path}
+
+	return nil
+}
+
+// forget removes the named commands, or all of them if none are named.
+func (h *hashTable) forget(names ...string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sync()
+	if len(names) == 0 {
+		h.entries = map[string]*hashEntry{}
+		return nil
+	}
+	for _, name := range names {
+		if _, ok := h.entries[name]; !ok {
+			return fmt.Errorf("%w: hash: %v: not found", builtins.ErrInvalidArgs, name)
+		}
+		delete(h.entries, name)
+	}
+
+	return nil
+}
+
+// path returns the remembered path of a command.
+func (h *hashTable) path(name string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sync()
+	e, ok := h.entries[name]
+	if !ok {
+		return "", false
+	}
+
+	return e.path, true
+}
+
+// print lists the remembered commands with their hit counts, by name.
+func (h *hashTable) print(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sync()
+	if len(h.entries) == 0 {
+		_, err := fmt.Fprintln(w, "hash: hash table empty")
+		return err
+	}
+	names := make([]string, 0, len(h.entries))
+	for name := range h.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if _, err := fmt.Fprintln(w, "hits\tcommand"); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%4d\t%v\n", h.entries[name].hits, h.entries[name].path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashCommand handles the "hash" built-in command.
+// With no arguments it lists the remembered commands and how often each was
+// run; with names it looks them up and remembers them. -r forgets every
+// command, -d forgets the named ones and -t prints their remembered paths.
+func hashCommand(w io.Writer, args ...string) error {
+	if len(args) == 0 {
+		return commandHash.print(w)
+	}
+
+	switch args[0] {
+	case "-r":
+		if len(args) > 1 {
+			return fmt.Errorf("%w: hash -r takes no names", builtins.ErrInvalidArgCount)
+		}
+		return commandHash.forget()
+	case "-d":
+		if len(args) == 1 {
+			return fmt.Errorf("%w: hash -d needs a name", builtins.ErrInvalidArgCount)
+		}
+		return commandHash.forget(args[1:]...)
+	case "-t":
+		if len(args) == 1 {
+			return fmt.Errorf("%w: hash -t needs a name", builtins.ErrInvalidArgCount)
+		}
+		for _, name := range args[1:] {
+			path, ok := commandHash.path(name)
+			if !ok {
+				return fmt.Errorf("%w: hash: %v: not found", builtins.ErrInvalidArgs, name)
+			}
+			if len(args) > 2 {
+				path = name + "\t" + path
+			}
+			if _, err := fmt.Fprintln(w, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range args {
+		if builtins.IsBuiltin(name) {
+			continue
+		}
+		if err := commandHash.remember(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hashCommand(t *testing.T) {
+	// Not parallel: changes PATH and the process-wide command hash.
+	dir := t.TempDir()
+	prog := filepath.Join(dir, "hashprog")
+	require.NoError(t, os.WriteFile(prog, []byte("#!/bin/sh\n"), 0o755))
+	t.Setenv("PATH", dir)
+	commandHash = &hashTable{}
+
+	w := &bytes.Buffer{}
+	require.NoError(t, hashCommand(w))
+	require.Equal(t, "hash: hash table empty\n", w.String())
+
+	for i := 0; i < 2; i++ {
+		got, err := commandHash.lookPath("hashprog")
+		require.NoError(t, err)
+		require.Equal(t, prog, got)
+	}
+	w.Reset()
+	require.NoError(t, hashCommand(w))
+	require.Equal(t, "hits\tcommand\n   2\t"+prog+"\n", w.String())
+
+	w.Reset()
+	require.NoError(t, hashCommand(w, "-t", "hashprog"))
+	require.Equal(t, prog+"\n", w.String())
+
+	require.NoError(t, hashCommand(w, "-d", "hashprog"))
+	require.True(t, errors.Is(hashCommand(w, "-t", "hashprog"), builtins.ErrInvalidArgs))
+	require.True(t, errors.Is(hashCommand(w, "nosuchprog"), exec.ErrNotFound))
+
+	require.NoError(t, hashCommand(w, "hashprog", "echo"))
+	_, ok := commandHash.path("echo")
+	require.False(t, ok, "builtins are not hashed")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+dir)
+	_, ok = commandHash.path("hashprog")
+	require.False(t, ok, "changing PATH forgets the table")
+
+	require.NoError(t, hashCommand(w, "hashprog"))
+	require.NoError(t, hashCommand(w, "-r"))
+	_, ok = commandHash.path("hashprog")
+	require.False(t, ok)
+}
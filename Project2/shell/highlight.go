@@ -0,0 +1,205 @@
+package shell
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// highlight returns line with ANSI colors for the line editor to show: the
+// command word of each command green if it can be run and red if not,
+// quoted strings yellow, variables cyan, operators and reserved words magenta
+// and comments grey. A command word is resolved with isFunction first, as
+// the shell would run it. Removing the colors gives back line.
+func highlight(line string, isFunction func(name string) bool) string {
+	var (
+		b       strings.Builder
+		command = true // the next word is a command name
+		target  bool   // the next word is a redirection target
+	)
+	paint := func(color, text string) {
+		b.WriteString(color + text + colorReset)
+	}
+	for i := 0; i < len(line); {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			b.WriteByte(ch)
+			i++
+		case ch == '#':
+			paint(colorGrey, line[i:])
+			i = len(line)
+		case strings.IndexByte(";&|()", ch) >= 0 || (ch == '{' || ch == '}') && wordEnd(line, i) == i+1:
+			end := i + 1
+			for end < len(line) && end-i < 2 && line[end] == ch && ch != '(' && ch != ')' {
+				end++
+			}
+			paint(colorMagenta, line[i:end])
+			i = end
+			command, target = true, false
+		case ch == '<' || ch == '>':
+			end := i + 1
+			for end < len(line) && strings.IndexByte("<>&-", line[end]) >= 0 {
+				end++
+			}
+			paint(colorMagenta, line[i:end])
+			i = end
+			target = true
+		default:
+			end := wordEnd(line, i)
+			word := line[i:end]
+			switch {
+			case command && !target && assignmentWord(word):
+				// The command name comes after the assignments.
+				highlightWord(&b, word)
+			case command && !target:
+				switch word {
+				case "case", "for":
+					paint(colorMagenta, word)
+					command = false
+				case "if", "then", "elif", "else", "fi", "while", "until", "do", "done", "esac":
+					paint(colorMagenta, word)
+				default:
+					color := colorRed
+					if resolvable(unquote(word), isFunction) {
+						color = colorGreen
+					}
+					paint(color, word)
+					command = false
+				}
+			default:
+				highlightWord(&b, word)
+				target = false
+			}
+			i = end
+		}
+	}
+
+	return b.String()
+}
+
+// wordEnd returns where the word starting at i ends, skipping over quotes,
+// escaped characters and ${...}. An unclosed quote runs to the end of the
+// line. Braces are part of words unless they stand alone, as around a group.
+func wordEnd(line string, i int) int {
+	for i < len(line) {
+		switch ch := line[i]; {
+		case ch == '\\':
+			i += 2
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(line[i+1:], ch)
+			if end < 0 {
+				return len(line)
+			}
+			i += end + 2
+		case strings.HasPrefix(line[i:], "${"):
+			end := paramEnd(line, i)
+			if end < 0 {
+				return len(line)
+			}
+			i = end + 1
+		case strings.IndexByte(" \t\n;&|()<>", ch) >= 0:
+			return i
+		default:
+			i++
+		}
+	}
+
+	return len(line)
+}
+
+// assignmentWord reports whether word starts with NAME= or NAME[INDEX]=.
+func assignmentWord(word string) bool {
+	eq := strings.IndexByte(word, '=')
+	if eq <= 0 {
+		return false
+	}
+	name := word[:eq]
+	if open := strings.IndexByte(name, '['); open > 0 && strings.HasSuffix(name, "]") {
+		name = name[:open]
+	}
+
+	return validName(name)
+}
+
+// highlightWord writes an argument with its quoted strings and variables
+// colored.
+func highlightWord(b *strings.Builder, word string) {
+	for i := 0; i < len(word); {
+		switch ch := word[i]; {
+		case ch == '\\':
+			end := i + 2
+			if end > len(word) {
+				end = len(word)
+			}
+			b.WriteString(word[i:end])
+			i = end
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(word[i+1:], ch)
+			if end < 0 {
+				end = len(word)
+			} else {
+				end += i + 2
+			}
+			b.WriteString(colorYellow + word[i:end] + colorReset)
+			i = end
+		case ch == '$':
+			end := i + 1
+			switch {
+			case end < len(word) && word[end] == '{':
+				if close := paramEnd(word, i); close >= 0 {
+					end = close + 1
+				} else {
+					end = len(word)
+				}
+			case end < len(word) && strings.IndexByte("?$!#@*0123456789", word[end]) >= 0:
+				end++
+			default:
+				for end < len(word) && isNameChar(word[end]) {
+					end++
+				}
+			}
+			b.WriteString(colorCyan + word[i:end] + colorReset)
+			i = end
+		default:
+			b.WriteByte(ch)
+			i++
+		}
+	}
+}
+
+// unquote removes the quotes and backslashes from a word, without expanding it.
+func unquote(word string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(word); i++ {
+		switch ch := word[i]; {
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && (ch == '\'' || ch == '"'):
+			quote = ch
+		case quote != '\'' && ch == '\\' && i+1 < len(word):
+			i++
+			b.WriteByte(word[i])
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String()
+}
+
+// resolvable reports whether name is a function, a builtin, a program in
+// PATH, or a path to an executable file.
+func resolvable(name string, isFunction func(name string) bool) bool {
+	if isFunction(name) || builtins.IsBuiltin(name) {
+		return true
+	}
+	if !builtins.IsPath(name) {
+		return commandIndex.has(name)
+	}
+	info, err := os.Stat(name)
+
+	return err == nil && builtins.IsExecutable(name, info)
+}
@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_highlight(t *testing.T) {
+	t.Parallel()
+	g := func(s string) string { return colorGreen + s + colorReset }
+	r := func(s string) string { return colorRed + s + colorReset }
+	y := func(s string) string { return colorYellow + s + colorReset }
+	c := func(s string) string { return colorCyan + s + colorReset }
+	m := func(s string) string { return colorMagenta + s + colorReset }
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "echo hi", want: g("echo") + " hi"},
+		{line: "nosuchcommand-xyz hi", want: r("nosuchcommand-xyz") + " hi"},
+		{line: `echo 'a b' "$HOME"x`, want: g("echo") + " " + y("'a b'") + " " + y(`"$HOME"`) + "x"},
+		{line: "echo $USER${HOME}$?", want: g("echo") + " " + c("$USER") + c("${HOME}") + c("$?")},
+		{line: "pwd; nosuch && echo", want: g("pwd") + m(";") + " " + r("nosuch") + " " + m("&&") + " " + g("echo")},
+		{line: "sort < in > out", want: g("sort") + " " + m("<") + " in " + m(">") + " out"},
+		{line: "{ pwd; }", want: m("{") + " " + g("pwd") + m(";") + " " + m("}")},
+		{line: "echo a{b,c}", want: g("echo") + " a{b,c}"},
+		{line: "cat 2>&1", want: g("cat") + " 2" + m(">&") + "1"},
+		{line: "echo x # note", want: g("echo") + " x " + colorGrey + "# note" + colorReset},
+		{line: "case $x in a) pwd;; esac", want: m("case") + " " + c("$x") + " in a" + m(")") + " " + g("pwd") + m(";;") + " " + m("esac")},
+		{line: "for f in a; do pwd; done", want: m("for") + " f in a" + m(";") + " " + m("do") + " " + g("pwd") + m(";") + " " + m("done")},
+		{line: "if pwd; then nosuch; fi || echo", want: m("if") + " " + g("pwd") + m(";") + " " + m("then") + " " + r("nosuch") + m(";") + " " + m("fi") + " " + m("||") + " " + g("echo")},
+		{line: "while pwd; do break; done", want: m("while") + " " + g("pwd") + m(";") + " " + m("do") + " " + g("break") + m(";") + " " + m("done")},
+		{line: "echo ${v/ /_}", want: g("echo") + " " + c("${v/ /_}")},
+		{line: "X=$y pwd", want: "X=" + c("$y") + " " + g("pwd")},
+		{line: `ec"ho" 'unclosed`, want: g(`ec"ho"`) + " " + y("'unclosed")},
+		{line: "greet world; greet-not", want: g("greet") + " world" + m(";") + " " + r("greet-not")},
+	}
+	isFunction := func(name string) bool { return name == "greet" }
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+			got := highlight(tt.line, isFunction)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.line, regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(got, ""))
+		})
+	}
+}
+
+func Test_editorHighlights(t *testing.T) {
+	t.Parallel()
+	out := &bytes.Buffer{}
+	e := &editor{in: strings.NewReader("pwd\r"), out: out, highlight: func(line string) string { return highlight(line, func(string) bool { return false }) }}
+	got, err := e.readLine()
+	require.NoError(t, err)
+	require.Equal(t, "pwd\n", got)
+	require.Contains(t, out.String(), colorGreen+"pwd"+colorReset)
+}
@@ -0,0 +1,303 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("history", func(ctx *builtins.Context, args ...string) error {
+		return historyCommand(ctx.Stdout, commandHistory, args...)
+	}, builtins.Meta{
+		Synopsis: "history [-c] [-t] [N]",
+		Summary:  "list the commands entered, or the last N",
+		Paged:    true,
+		Flags: []string{
+			"-c\tclear the history",
+			"-t\tshow when each command was entered",
+		},
+	})
+}
+
+// historySize is how many commands the history keeps.
+const historySize = 1000
+
+// historyTimeFormat is how history -t shows when a command was entered.
+const historyTimeFormat = "2006-01-02 15:04:05"
+
+// commandHistory holds the command lines the shell has read. Like the job
+// table, it belongs to the process, which runs one session.
+var commandHistory = &historyList{}
+
+// historyList is a list of command lines, oldest first.
+type historyList struct {
+	mu      sync.Mutex
+	entries []historyEntry
+}
+
+// historyEntry is a command line and when it was entered.
+type historyEntry struct {
+	line string
+	time time.Time
+}
+
+// historyFilter says which command lines are left out of the history, as set
+// by $HISTCONTROL and $HISTIGNORE.
+type historyFilter struct {
+	ignoreSpace bool     // lines starting with a blank
+	ignoreDups  bool     // repeats of the last line
+	eraseDups   bool     // earlier copies of the line are removed instead
+	ignore      []string // patterns of lines to leave out; & is the last line
+}
+
+// historyFilter reads the history filter from $HISTCONTROL, a colon-separated
+// list of ignorespace, ignoredups, ignoreboth (both of those) and erasedups,
+// and $HISTIGNORE, a colon-separated list of patterns. While HISTCONTROL is
+// unset, repeats are ignored.
+func (s *Shell) historyFilter() historyFilter {
+	var f historyFilter
+	control, ok := s.vars.Get("HISTCONTROL")
+	if !ok {
+		control = "ignoredups"
+	}
+	for _, opt := range strings.Split(control, ":") {
+		switch opt {
+		case "ignorespace":
+			f.ignoreSpace = true
+		case "ignoredups":
+			f.ignoreDups = true
+		case "ignoreboth":
+			f.ignoreSpace, f.ignoreDups = true, true
+		case "erasedups":
+			f.eraseDups = true
+		}
+	}
+	if ignore, _ := s.vars.Get("HISTIGNORE"); ignore != "" {
+		f.ignore = strings.Split(ignore, ":")
+	}
+
+	return f
+}
+
+// add appends a command line, without its final newline, unless it is blank
+// or f leaves it out.
+func (h *historyList) add(line string, f historyFilter) {
+	line = strings.TrimRight(line, "\n")
+	if strings.TrimSpace(line) == "" || f.ignoreSpace && (line[0] == ' ' || line[0] == '\t') {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	last := ""
+	if n := len(h.entries); n > 0 {
+		last = h.entries[n-1].line
+	}
+	if f.ignoreDups && line == last {
+		return
+	}
+	for _, pattern := range f.ignore {
+		if pattern == "&" && line == last || pattern != "&" && matchPattern(pattern, line) {
+			return
+		}
+	}
+	if f.eraseDups {
+		kept := h.entries[:0]
+		for _, e := range h.entries {
+			if e.line != line {
+				kept = append(kept, e)
+			}
+		}
+		h.entries = kept
+	}
+	h.entries = append(h.entries, historyEntry{line: line, time: time.Now()})
+	if len(h.entries) > historySize {
+		h.entries = h.entries[len(h.entries)-historySize:]
+	}
+}
+
+// list returns the command lines, oldest first.
+func (h *historyList) list() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lines := make([]string, len(h.entries))
+	for i, e := range h.entries {
+		lines[i] = e.line
+	}
+
+	return lines
+}
+
+// stamped returns a copy of the entries, oldest first.
+func (h *historyList) stamped() []historyEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]historyEntry(nil), h.entries...)
+}
+
+// load appends the lines read from r as entries entered at t, skipping blank
+// ones, and keeps the last historySize.
+func (h *historyList) load(r io.Reader, t time.Time) error {
+	var loaded []historyEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			loaded = append(loaded, historyEntry{line: line, time: t})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, loaded...)
+	if len(h.entries) > historySize {
+		h.entries = h.entries[len(h.entries)-historySize:]
+	}
+
+	return nil
+}
+
+// write writes the entries to w, one command line per line.
+func (h *historyList) write(w io.Writer) error {
+	for _, line := range h.list() {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadHistory adds the commands saved in $HISTFILE, if it is set and the file
+// exists, to the history. A restricted shell doesn't read it.
+func (s *Shell) loadHistory() error {
+	path, _ := s.vars.Get("HISTFILE")
+	if path == "" || s.opts.enabled(optRestricted) {
+		return nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	return commandHistory.load(f, info.ModTime())
+}
+
+// saveHistory writes the history to $HISTFILE, if it is set, for the next
+// shell to load. A restricted shell, which can't write files, doesn't.
+func (s *Shell) saveHistory() error {
+	path, _ := s.vars.Get("HISTFILE")
+	if path == "" || s.opts.enabled(optRestricted) {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if err := commandHistory.write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("history: %w", err)
+	}
+
+	return f.Close()
+}
+
+// replace makes entries the history, as session restore does.
+func (h *historyList) replace(entries []historyEntry) {
+	h.mu.Lock()
+	h.entries = append([]historyEntry(nil), entries...)
+	h.mu.Unlock()
+}
+
+// clear forgets every entry.
+func (h *historyList) clear() {
+	h.mu.Lock()
+	h.entries = nil
+	h.mu.Unlock()
+}
+
+// suggest returns the rest of the most recent one-line entry that starts with
+// prefix and is longer than it, or "" if there is none.
+func (h *historyList) suggest(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		e := h.entries[i].line
+		if len(e) > len(prefix) && strings.HasPrefix(e, prefix) && !strings.Contains(e, "\n") {
+			return e[len(prefix):]
+		}
+	}
+
+	return ""
+}
+
+// historyCommand handles the "history" built-in command.
+// It lists the entries of h, numbered from 1, or only the last N; -t adds
+// when each was entered and -c clears them. If $HISTFILE is set, the shell
+// loads the history from that file when it starts and saves it there when it
+// exits.
+func historyCommand(w io.Writer, h *historyList, args ...string) error {
+	showTime := false
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-" {
+		switch args[0] {
+		case "-c":
+			h.clear()
+			return nil
+		case "-t":
+			showTime = true
+		default:
+			return fmt.Errorf("%w: history: unknown option %v", builtins.ErrInvalidArgs, args[0])
+		}
+		args = args[1:]
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: history: expected at most one argument", builtins.ErrInvalidArgCount)
+	}
+	entries := h.stamped()
+	first := 0
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("%w: history: %v: numeric argument required", builtins.ErrInvalidArgs, args[0])
+		}
+		if n < len(entries) {
+			first = len(entries) - n
+		}
+	}
+
+	for i := first; i < len(entries); i++ {
+		var err error
+		if showTime {
+			_, err = fmt.Fprintf(w, "%5d  %s  %s\n", i+1, entries[i].time.Format(historyTimeFormat), entries[i].line)
+		} else {
+			_, err = fmt.Fprintf(w, "%5d  %s\n", i+1, entries[i].line)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
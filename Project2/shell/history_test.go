@@ -0,0 +1,154 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_historyList(t *testing.T) {
+	t.Parallel()
+	h := &historyList{}
+	for _, line := range []string{"ls -l\n", "  \n", "git status\n", "git status\n", "git commit\n", "if true\nthen echo\nfi\n"} {
+		h.add(line, historyFilter{ignoreDups: true})
+	}
+	require.Equal(t, []string{"ls -l", "git status", "git commit", "if true\nthen echo\nfi"}, h.list())
+
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "git", want: " commit"},
+		{prefix: "git s", want: "tatus"},
+		{prefix: "l", want: "s -l"},
+		{prefix: "ls -l", want: ""},
+		{prefix: "if", want: ""},
+		{prefix: "", want: ""},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, h.suggest(tt.prefix), "suggest(%q)", tt.prefix)
+	}
+
+	for i := 0; i < historySize+10; i++ {
+		h.add(strings.Repeat("x", i+1), historyFilter{})
+	}
+	require.Len(t, h.list(), historySize)
+}
+
+func Test_historyFilter(t *testing.T) {
+	t.Parallel()
+	lines := []string{"ls", "ls", " secret", "pwd", "ls", "exit", "echo a", "echo a"}
+	tests := []struct {
+		name    string
+		control *string
+		ignore  string
+		want    []string
+	}{
+		{name: "default", want: []string{"ls", " secret", "pwd", "ls", "exit", "echo a"}},
+		{name: "none", control: strPtr(""), want: lines},
+		{name: "ignorespace", control: strPtr("ignorespace"), want: []string{"ls", "ls", "pwd", "ls", "exit", "echo a", "echo a"}},
+		{name: "ignoreboth", control: strPtr("ignoreboth"), want: []string{"ls", "pwd", "ls", "exit", "echo a"}},
+		{name: "erasedups", control: strPtr("erasedups"), want: []string{" secret", "pwd", "ls", "exit", "echo a"}},
+		{name: "ignore patterns", control: strPtr(""), ignore: "exit:echo *:&", want: []string{"ls", " secret", "pwd", "ls"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+			if tt.control != nil {
+				require.NoError(t, sh.vars.Set("HISTCONTROL", *tt.control))
+			}
+			require.NoError(t, sh.vars.Set("HISTIGNORE", tt.ignore))
+			h := &historyList{}
+			for _, line := range lines {
+				h.add(line, sh.historyFilter())
+			}
+			require.Equal(t, tt.want, h.list())
+		})
+	}
+}
+
+func Test_historyCommand(t *testing.T) {
+	t.Parallel()
+	h := &historyList{}
+	h.add("pwd", historyFilter{})
+	h.add("ls", historyFilter{})
+	h.add("echo hi", historyFilter{})
+
+	w := &bytes.Buffer{}
+	require.NoError(t, historyCommand(w, h))
+	require.Equal(t, "    1  pwd\n    2  ls\n    3  echo hi\n", w.String())
+
+	w.Reset()
+	require.NoError(t, historyCommand(w, h, "2"))
+	require.Equal(t, "    2  ls\n    3  echo hi\n", w.String())
+
+	w.Reset()
+	require.NoError(t, historyCommand(w, h, "-t", "1"))
+	require.Regexp(t, `^    3  \d{4}-\d\d-\d\d \d\d:\d\d:\d\d  echo hi\n$`, w.String())
+
+	err := historyCommand(w, h, "x")
+	require.True(t, errors.Is(err, builtins.ErrInvalidArgs), "historyCommand() error = %v", err)
+	err = historyCommand(w, h, "-x")
+	require.True(t, errors.Is(err, builtins.ErrInvalidArgs), "historyCommand() error = %v", err)
+
+	require.NoError(t, historyCommand(w, h, "-c"))
+	require.Empty(t, h.list())
+}
+
+func Test_editorSuggests(t *testing.T) {
+	t.Parallel()
+	h := &historyList{}
+	h.add("git status", historyFilter{})
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{name: "accepted with right arrow", keys: "git\x1b[C\r", want: "git status\n"},
+		{name: "accepted with ctrl-f", keys: "gi\x06\r", want: "git status\n"},
+		{name: "right arrow within the line", keys: "gt\x1b[D\x1b[Ci\r", want: "gti\n"},
+		{name: "not accepted", keys: "git\r", want: "git\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			e := &editor{in: strings.NewReader(tt.keys), out: out, suggest: h.suggest}
+			got, err := e.readLine()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	out := &bytes.Buffer{}
+	e := &editor{in: strings.NewReader("git\r"), out: out, suggest: h.suggest}
+	_, err := e.readLine()
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "t"+colorGrey+" status"+colorReset+"\x1b[K\x1b[7D")
+	require.True(t, strings.HasSuffix(out.String(), "\x1b[K\n"), "the suggestion is cleared when the line ends")
+}
+
+func TestShell_Run_historyFile(t *testing.T) {
+	// Not parallel: the history is shared by the whole process.
+	path := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, os.WriteFile(path, []byte("echo before\n"), 0o600))
+	t.Setenv("HISTFILE", path)
+	commandHistory.clear()
+	defer commandHistory.clear()
+
+	w := &bytes.Buffer{}
+	New(strings.NewReader("history\necho now\n"), w, &bytes.Buffer{}).Run()
+	require.Contains(t, w.String(), "    1  echo before\n    2  history\n")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "echo before\nhistory\necho now\n", string(data))
+}
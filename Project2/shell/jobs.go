@@ -0,0 +1,630 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("jobs", func(ctx *builtins.Context, args ...string) error {
+		return jobsCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "jobs [-l | -o] [%JOB...]",
+		Summary:  "list background jobs, or show their output",
+		Flags: []string{
+			"-l\talso list each job's process IDs and process group IDs",
+			"-o\tshow the output kept for the jobs (default: the current job)",
+		},
+	})
+	builtins.Register("fg", func(ctx *builtins.Context, args ...string) error {
+		return fgCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "fg [%JOB]",
+		Summary:  "wait for a background job in the foreground",
+	})
+	builtins.Register("bg", func(ctx *builtins.Context, args ...string) error {
+		return bgCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "bg [%JOB...]",
+		Summary:  "continue stopped jobs in the background",
+	})
+	builtins.Register("disown", func(ctx *builtins.Context, args ...string) error {
+		return disownCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "disown [-a] [%JOB...]",
+		Summary:  "remove jobs from the job table so they outlive the shell",
+		Flags: []string{
+			"-a\tremove all jobs",
+		},
+	})
+}
+
+// ErrNoSuchJob is returned for a job spec that matches no job.
+var ErrNoSuchJob = fmt.Errorf("%w: no such job", builtins.ErrInvalidArgs)
+
+// jobTable holds the shell's background jobs. Like traps, the table belongs
+// to the process: the jobs are its children, and it signals them on exit.
+var jobTable = &jobList{}
+
+// defaultJobOutputSize is how much output of a background job is kept while
+// $JOB_OUTPUT_SIZE is unset.
+const defaultJobOutputSize = 64 << 10
+
+// job is a command started with &. Its processes are the external commands
+// it has started, in order; a job made only of builtins has none. If output
+// isn't nil, it keeps what the job writes to the terminal until it is shown.
+type job struct {
+	id     int
+	text   string
+	done   chan struct{}
+	output *jobOutput
+
+	mu       sync.Mutex // guards the fields below
+	procs    []jobProcess
+	status   int
+	reported bool
+}
+
+// jobProcess is a process a job has started, with the process group it was
+// in when it started.
+type jobProcess struct {
+	*os.Process
+	pgid int // 0 if unknown
+}
+
+// started records a process the job has started.
+func (j *job) started(p *os.Process) {
+	proc := jobProcess{Process: p, pgid: processGroup(p.Pid)}
+	j.mu.Lock()
+	j.procs = append(j.procs, proc)
+	j.mu.Unlock()
+}
+
+// finish records the job's exit status and marks it done.
+func (j *job) finish(status int) {
+	j.mu.Lock()
+	j.status = status
+	j.mu.Unlock()
+	close(j.done)
+}
+
+// finished reports whether the job has ended, and with which status.
+func (j *job) finished() (int, bool) {
+	select {
+	case <-j.done:
+	default:
+		return 0, false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.status, true
+}
+
+// pids returns the IDs of the job's processes.
+func (j *job) pids() []int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	pids := make([]int, len(j.procs))
+	for i, p := range j.procs {
+		pids[i] = p.Pid
+	}
+
+	return pids
+}
+
+// processes returns the job's processes.
+func (j *job) processes() []jobProcess {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]jobProcess(nil), j.procs...)
+}
+
+// report marks a finished job as reported, returning false if it already
+// was, so that its end is only announced once.
+func (j *job) report() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	first := !j.reported
+	j.reported = true
+
+	return first
+}
+
+// resume continues the job's processes, if they were stopped.
+func (j *job) resume() {
+	for _, p := range j.processes() {
+		_ = continueProcess(p.Process)
+	}
+}
+
+// describe returns the job as builtins see it.
+func (j *job) describe() builtins.Job {
+	status, done := j.finished()
+
+	return builtins.Job{ID: j.id, Command: j.text, PIDs: j.pids(), Done: done, Status: status}
+}
+
+// state describes the job for listings: Running, Done or Exit N.
+func (j *job) state() string {
+	status, done := j.finished()
+	switch {
+	case !done:
+		return "Running"
+	case status == builtins.StatusSuccess:
+		return "Done"
+	default:
+		return "Exit " + strconv.Itoa(status)
+	}
+}
+
+// jobOutput keeps the last size bytes a background job writes, so that the
+// output doesn't get mixed up with the line being typed.
+type jobOutput struct {
+	mu      sync.Mutex
+	buf     []byte
+	size    int
+	dropped int // how many bytes were dropped from the front of buf
+}
+
+func newJobOutput(size int) *jobOutput {
+	return &jobOutput{size: size}
+}
+
+// Write keeps p, dropping the oldest output beyond the size.
+func (o *jobOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.buf = append(o.buf, p...)
+	if over := len(o.buf) - o.size; over > 0 {
+		o.buf = append(o.buf[:0], o.buf[over:]...)
+		o.dropped += over
+	}
+
+	return len(p), nil
+}
+
+// show writes the output kept to w, after a note of how much was dropped,
+// and forgets it, so that each part is shown once.
+func (o *jobOutput) show(w io.Writer, id int) error {
+	o.mu.Lock()
+	buf, dropped := o.buf, o.dropped
+	o.buf, o.dropped = nil, 0
+	o.mu.Unlock()
+	if dropped > 0 {
+		if _, err := fmt.Fprintf(w, "[%d] %d bytes of output dropped\n", id, dropped); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(buf)
+
+	return err
+}
+
+// jobOutputSize returns how many bytes of output a background job keeps,
+// from $JOB_OUTPUT_SIZE; 0 lets jobs write to the terminal as they run.
+func (s *Shell) jobOutputSize() int {
+	v, ok := s.vars.Get("JOB_OUTPUT_SIZE")
+	if !ok {
+		return defaultJobOutputSize
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return defaultJobOutputSize
+	}
+
+	return n
+}
+
+// jobList is an ordered job table; the last job is the current one (%+).
+type jobList struct {
+	mu      sync.Mutex
+	jobs    []*job
+	lastPID int // $!
+}
+
+// add creates a job for the command text, numbered one more than the
+// highest job number in use, keeping up to outputSize bytes of its output if
+// that isn't 0.
+func (l *jobList) add(text string, outputSize int) *job {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := 1
+	if n := len(l.jobs); n > 0 {
+		id = l.jobs[n-1].id + 1
+	}
+	j := &job{id: id, text: text, done: make(chan struct{})}
+	if outputSize > 0 {
+		j.output = newJobOutput(outputSize)
+	}
+	l.jobs = append(l.jobs, j)
+
+	return j
+}
+
+// remove drops jobs from the table.
+func (l *jobList) remove(drop ...*job) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := l.jobs[:0]
+	for _, j := range l.jobs {
+		if !containsJob(drop, j) {
+			kept = append(kept, j)
+		}
+	}
+	l.jobs = kept
+}
+
+func containsJob(jobs []*job, j *job) bool {
+	for _, x := range jobs {
+		if x == j {
+			return true
+		}
+	}
+
+	return false
+}
+
+// list returns the jobs in order.
+func (l *jobList) list() []*job {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*job(nil), l.jobs...)
+}
+
+// Jobs returns the jobs in order, as builtins.JobTable.
+func (l *jobList) Jobs() []builtins.Job {
+	jobs := l.list()
+	described := make([]builtins.Job, len(jobs))
+	for i, j := range jobs {
+		described[i] = j.describe()
+	}
+
+	return described
+}
+
+// Lookup finds the job named by a job spec, as builtins.JobTable.
+func (l *jobList) Lookup(spec string) (builtins.Job, error) {
+	j, err := l.lookup(spec)
+	if err != nil {
+		return builtins.Job{}, err
+	}
+
+	return j.describe(), nil
+}
+
+// shellJobs returns the job table of the shell running a builtin.
+func shellJobs(ctx *builtins.Context, cmd string) (*jobList, error) {
+	l, ok := ctx.Jobs.(*jobList)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v: no job control", builtins.ErrInvalidArgs, cmd)
+	}
+
+	return l, nil
+}
+
+// setLastPID records the process ID of the last background process, for $!.
+func (l *jobList) setLastPID(pid int) {
+	l.mu.Lock()
+	l.lastPID = pid
+	l.mu.Unlock()
+}
+
+// lastBackground returns $!, or false if no background process has started.
+func (l *jobList) lastBackground() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lastPID, l.lastPID != 0
+}
+
+// lookup finds the job named by a job spec: %N, %% or %+ (the current job),
+// %- (the previous one), %PREFIX (the command starts with PREFIX) or
+// %?TEXT (the command contains TEXT). The % may be left out of %N.
+func (l *jobList) lookup(spec string) (*job, error) {
+	jobs := l.list()
+	rest := strings.TrimPrefix(spec, "%")
+	var match func(j *job) bool
+	switch {
+	case rest == "%" || rest == "+" || rest == "":
+		if len(jobs) > 0 {
+			return jobs[len(jobs)-1], nil
+		}
+	case rest == "-":
+		if len(jobs) > 1 {
+			return jobs[len(jobs)-2], nil
+		}
+	case rest[0] >= '0' && rest[0] <= '9':
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			break
+		}
+		match = func(j *job) bool { return j.id == n }
+	case strings.HasPrefix(rest, "?"):
+		match = func(j *job) bool { return strings.Contains(j.text, rest[1:]) }
+	case spec != rest:
+		match = func(j *job) bool { return strings.HasPrefix(j.text, rest) }
+	}
+
+	var found *job
+	for _, j := range jobs {
+		if match != nil && match(j) {
+			if found != nil {
+				return nil, fmt.Errorf("%w: %v: ambiguous job spec", builtins.ErrInvalidArgs, spec)
+			}
+			found = j
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoSuchJob, spec)
+	}
+
+	return found, nil
+}
+
+// print writes a job's status line, e.g. "[1]+  Running    sleep 10 &".
+func (l *jobList) print(w io.Writer, j *job) error {
+	marker := ' '
+	if jobs := l.list(); len(jobs) > 0 && jobs[len(jobs)-1] == j {
+		marker = '+'
+	} else if len(jobs) > 1 && jobs[len(jobs)-2] == j {
+		marker = '-'
+	}
+	text := j.text
+	if _, done := j.finished(); !done {
+		text += " &"
+	}
+	_, err := fmt.Fprintf(w, "[%d]%c  %-10s %s\n", j.id, marker, j.state(), text)
+
+	return err
+}
+
+// printLong writes a job's status line followed by a line for each of its
+// processes, giving its process ID and process group ID.
+func (l *jobList) printLong(w io.Writer, j *job) error {
+	if err := l.print(w, j); err != nil {
+		return err
+	}
+	for _, p := range j.processes() {
+		pgid := "?"
+		if p.pgid != 0 {
+			pgid = strconv.Itoa(p.pgid)
+		}
+		if _, err := fmt.Fprintf(w, "      %-8d pgid %s\n", p.Pid, pgid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notify reports a job that has just finished on w and removes it from the
+// table, as set -o notify asks, unless reap has already done so.
+func (l *jobList) notify(w io.Writer, j *job) {
+	if j.report() {
+		_ = l.printEnd(w, j)
+	}
+	l.remove(j)
+}
+
+// reap reports the jobs that have finished since the last call on w, with
+// the output they have left, and removes them from the table, as the shell
+// does before each prompt.
+func (l *jobList) reap(w io.Writer) {
+	var done []*job
+	for _, j := range l.list() {
+		if _, ok := j.finished(); ok {
+			if j.report() {
+				_ = l.printEnd(w, j)
+			}
+			done = append(done, j)
+		}
+	}
+	l.remove(done...)
+}
+
+// printEnd reports a finished job on w, followed by the output it has left.
+func (l *jobList) printEnd(w io.Writer, j *job) error {
+	if err := l.print(w, j); err != nil || j.output == nil {
+		return err
+	}
+
+	return j.output.show(w, j.id)
+}
+
+// hangup sends SIGHUP to the processes of the jobs still in the table, as
+// happens to a terminal's jobs when the shell leaves it. Disowned jobs and
+// commands run with nohup survive.
+func (l *jobList) hangup() {
+	for _, j := range l.list() {
+		if _, done := j.finished(); done {
+			continue
+		}
+		j.mu.Lock()
+		for _, p := range j.procs {
+			_ = p.Signal(syscall.SIGHUP)
+		}
+		j.mu.Unlock()
+	}
+}
+
+// jobsCommand handles the "jobs" built-in command.
+// It lists the given jobs, or all of them, with their state, and with -l
+// their processes; finished jobs are then removed from the table. The output
+// background jobs write to the terminal is kept, up to $JOB_OUTPUT_SIZE
+// bytes (64 KiB by default) each, and shown when they are reported done; -o
+// shows what the given jobs, or the current one, have written so far
+// instead. With JOB_OUTPUT_SIZE=0, jobs write to the terminal as they run.
+func jobsCommand(ctx *builtins.Context, args ...string) error {
+	l, err := shellJobs(ctx, "jobs")
+	if err != nil {
+		return err
+	}
+	printJob := l.print
+	if len(args) > 0 && args[0] == "-l" {
+		printJob = l.printLong
+		args = args[1:]
+	}
+	if len(args) > 0 && args[0] == "-o" {
+		return showJobOutput(ctx.Stdout, l, args[1:]...)
+	}
+	jobs := l.list()
+	if len(args) > 0 {
+		jobs = jobs[:0]
+		for _, spec := range args {
+			j, err := l.lookup(spec)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, j)
+		}
+	}
+
+	var done []*job
+	for _, j := range jobs {
+		if err := printJob(ctx.Stdout, j); err != nil {
+			return err
+		}
+		if _, ok := j.finished(); ok {
+			j.report()
+			done = append(done, j)
+		}
+	}
+	l.remove(done...)
+
+	return nil
+}
+
+// showJobOutput writes the output kept for the jobs in l named by specs, or
+// the current job.
+func showJobOutput(w io.Writer, l *jobList, specs ...string) error {
+	if len(specs) == 0 {
+		specs = []string{"%+"}
+	}
+	for _, spec := range specs {
+		j, err := l.lookup(spec)
+		if err != nil {
+			return err
+		}
+		if j.output == nil {
+			continue
+		}
+		if err := j.output.show(w, j.id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// disownCommand handles the "disown" built-in command.
+// It removes the given jobs (default: the current job; -a: all jobs) from the
+// table, so the shell neither lists them nor sends them SIGHUP when it exits.
+func disownCommand(ctx *builtins.Context, args ...string) error {
+	l, err := shellJobs(ctx, "disown")
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 && args[0] == "-a" {
+		l.remove(l.list()...)
+		return nil
+	}
+	if len(args) == 0 {
+		args = []string{"%+"}
+	}
+
+	jobs := make([]*job, 0, len(args))
+	for _, spec := range args {
+		j, err := l.lookup(spec)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, j)
+	}
+	l.remove(jobs...)
+
+	return nil
+}
+
+// fgCommand handles the "fg" built-in command.
+// It brings a job (default: the current job) to the foreground: it prints
+// the job's command, continues it if it was stopped, and waits for it to
+// finish. The output kept for the job is then shown, the job leaves the
+// table, and its status becomes fg's. Ctrl-C interrupts the job's processes
+// and stops waiting.
+func fgCommand(ctx *builtins.Context, args ...string) error {
+	l, err := shellJobs(ctx, "fg")
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: fg takes at most one job", builtins.ErrInvalidArgCount)
+	}
+	spec := "%+"
+	if len(args) == 1 {
+		spec = args[0]
+	}
+	j, err := l.lookup(spec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(ctx.Stdout, j.text); err != nil {
+		return err
+	}
+	j.resume()
+	select {
+	case <-j.done:
+	case <-ctx.Context().Done():
+		for _, p := range j.processes() {
+			_ = p.Signal(os.Interrupt)
+		}
+		return ctx.Context().Err()
+	}
+	j.report()
+	l.remove(j)
+	if j.output != nil {
+		if err := j.output.show(ctx.Stdout, j.id); err != nil {
+			return err
+		}
+	}
+	if status, _ := j.finished(); status != builtins.StatusSuccess {
+		return &builtins.ExitError{Status: status}
+	}
+
+	return nil
+}
+
+// bgCommand handles the "bg" built-in command.
+// It continues the given jobs (default: the current job), as stopped by
+// kill -STOP, in the background, listing each as jobs does.
+func bgCommand(ctx *builtins.Context, args ...string) error {
+	l, err := shellJobs(ctx, "bg")
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		args = []string{"%+"}
+	}
+	for _, spec := range args {
+		j, err := l.lookup(spec)
+		if err != nil {
+			return err
+		}
+		j.resume()
+		if err := l.print(ctx.Stdout, j); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package shell
+
+import "os"
+
+// processGroup returns 0, as there are no process groups on this platform.
+func processGroup(int) int {
+	return 0
+}
+
+// continueProcess does nothing, as processes can't be stopped on this
+// platform.
+func continueProcess(*os.Process) error {
+	return nil
+}
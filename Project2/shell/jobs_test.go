@@ -0,0 +1,230 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_jobList_lookup(t *testing.T) {
+	t.Parallel()
+	l := &jobList{}
+	first, second, third := l.add("sleep 10", 0), l.add("make all", 0), l.add("sleep 20", 0)
+
+	tests := []struct {
+		spec    string
+		want    *job
+		wantErr error
+	}{
+		{spec: "%%", want: third},
+		{spec: "%+", want: third},
+		{spec: "%-", want: second},
+		{spec: "%1", want: first},
+		{spec: "2", want: second},
+		{spec: "%make", want: second},
+		{spec: "%?20", want: third},
+		{spec: "%sleep", wantErr: builtins.ErrInvalidArgs},
+		{spec: "%9", wantErr: ErrNoSuchJob},
+		{spec: "%nothing", wantErr: ErrNoSuchJob},
+		{spec: "make", wantErr: ErrNoSuchJob},
+	}
+	for _, tt := range tests {
+		got, err := l.lookup(tt.spec)
+		require.True(t, errors.Is(err, tt.wantErr), "lookup(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		require.Equal(t, tt.want, got, tt.spec)
+	}
+
+	l.remove(second)
+	require.Equal(t, []*job{first, third}, l.list())
+	require.Equal(t, 4, l.add("new", 0).id, "numbers continue from the last job")
+}
+
+func TestShell_RunLine_background(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	status, err := sh.RunLine("sleep 10 & (exit 3) &")
+	require.NoError(t, err)
+	require.Equal(t, 0, status)
+	require.Equal(t, "[1]\n[2]\n", errs.String())
+	jobs := jobTable.list()
+	require.Len(t, jobs, 2)
+	select {
+	case <-jobs[1].done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background job did not finish")
+	}
+
+	_, err = sh.RunLine("jobs")
+	require.NoError(t, err)
+	require.Equal(t, "[1]-  Running    sleep 10 &\n[2]+  Exit 3     (exit 3)\n", w.String())
+	require.Len(t, jobTable.list(), 1, "jobs removes finished jobs")
+
+	_, err = sh.RunLine("disown %sleep")
+	require.NoError(t, err)
+	require.Empty(t, jobTable.list())
+
+	_, err = sh.RunLine("disown %1")
+	require.ErrorIs(t, err, ErrNoSuchJob)
+}
+
+func TestShell_RunLine_jobsLong(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	w := &syncBuffer{}
+	sh := New(strings.NewReader(""), w, &syncBuffer{})
+
+	_, err := sh.RunLine("sh -c 'sleep 10' &")
+	require.NoError(t, err)
+	t.Cleanup(jobTable.hangup)
+	jobs := jobTable.list()
+	require.Len(t, jobs, 1)
+	pids := jobs[0].pids()
+	require.Len(t, pids, 1)
+
+	_, err = sh.RunLine("jobs -l")
+	require.NoError(t, err)
+	pgid := processGroup(pids[0])
+	require.NotZero(t, pgid)
+	require.Equal(t, fmt.Sprintf("[1]+  Running    sh -c 'sleep 10' &\n      %-8d pgid %d\n", pids[0], pgid), w.String())
+}
+
+func TestShell_RunLine_notify(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	w := &syncBuffer{}
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, w)
+
+	_, err := sh.RunLine("set -b; (exit 3) &")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return strings.Contains(w.String(), "[1]+  Exit 3     (exit 3)\n")
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, jobTable.list(), "a reported job is removed")
+
+	jobTable.reap(w)
+	require.Equal(t, 1, strings.Count(w.String(), "Exit 3"), "the job is only reported once")
+}
+
+func TestShell_RunLine_fgBg(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	w := &syncBuffer{}
+	sh := New(strings.NewReader(""), w, &syncBuffer{})
+
+	_, err := sh.RunLine("sh -c 'echo out; exit 3' &")
+	require.NoError(t, err)
+	t.Cleanup(jobTable.hangup)
+	jobs := sh.context(strings.NewReader(""), w).Jobs.Jobs()
+	require.Len(t, jobs, 1, "builtins see the job table")
+	require.Equal(t, 1, jobs[0].ID)
+	require.Equal(t, "sh -c 'echo out; exit 3'", jobs[0].Command)
+
+	status, err := sh.RunLine("fg %1")
+	require.Equal(t, 3, status, "fg takes the job's status")
+	require.Equal(t, 3, builtins.StatusOf(err))
+	require.Equal(t, "sh -c 'echo out; exit 3'\nout\n", w.String())
+	require.Empty(t, jobTable.list(), "fg removes the job")
+
+	_, err = sh.RunLine("bg")
+	require.ErrorIs(t, err, ErrNoSuchJob)
+	_, err = sh.RunLine("fg 1 2")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgCount)
+
+	_, err = sh.RunLine("sh -c 'exec sleep 10' & kill -STOP %1; bg; kill %1")
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(w.String(), "\n[1]+  Running    sh -c 'exec sleep 10' &\n"), w.String())
+	status, err = sh.RunLine("fg")
+	require.Equal(t, 128+int(syscall.SIGTERM), status, "kill and bg reach the job's processes")
+	require.Error(t, err)
+}
+
+func Test_jobOutput(t *testing.T) {
+	t.Parallel()
+	o := newJobOutput(5)
+	_, _ = o.Write([]byte("abc"))
+	_, _ = o.Write([]byte("defg"))
+
+	var w bytes.Buffer
+	require.NoError(t, o.show(&w, 1))
+	require.Equal(t, "[1] 2 bytes of output dropped\ncdefg", w.String())
+	w.Reset()
+	require.NoError(t, o.show(&w, 1))
+	require.Empty(t, w.String(), "output is only shown once")
+}
+
+func TestShell_RunLine_jobOutput(t *testing.T) {
+	// Not parallel: uses the process-wide job table.
+	jobTable = &jobList{}
+	w, errs := &syncBuffer{}, &syncBuffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	_, err := sh.RunLine("echo kept; echo to stderr >&2 &")
+	require.NoError(t, err)
+	_, err = sh.RunLine("echo running > /dev/null &")
+	require.NoError(t, err)
+	for _, j := range jobTable.list() {
+		<-j.done
+	}
+	require.Equal(t, "kept\n", w.String(), "the jobs' output is kept")
+
+	_, err = sh.RunLine("jobs -o %1")
+	require.NoError(t, err)
+	require.Equal(t, "kept\nto stderr\n", w.String())
+
+	jobTable.reap(errs)
+	require.Equal(t, "[1]\n[2]\n[1]-  Done       echo to stderr >&2\n[2]+  Done       echo running > /dev/null\n", errs.String())
+
+	_, err = sh.RunLine("JOB_OUTPUT_SIZE=0; echo direct &")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return strings.Contains(w.String(), "direct\n")
+	}, 5*time.Second, 10*time.Millisecond)
+	jobTable.remove(jobTable.list()...)
+}
+
+func Test_parse_background(t *testing.T) {
+	t.Parallel()
+	nodes, err := parse("sleep 1 & echo a&\n{ b; } >out &")
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	for i, want := range []string{"sleep 1", "echo a", "{ b; } >out"} {
+		bg, ok := nodes[i].(*background)
+		require.True(t, ok, "node %d is not a background job", i)
+		require.Equal(t, want, bg.text)
+	}
+
+	for _, src := range []string{"& echo", "a && & b", "a & ; b"} {
+		_, err := parse(src)
+		require.ErrorIs(t, err, ErrSyntax, src)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that a background job can write to while the
+// test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package shell
+
+import (
+	"os"
+	"syscall"
+)
+
+// processGroup returns the process group ID of a process, or 0 if it can't
+// be found.
+func processGroup(pid int) int {
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil {
+		return 0
+	}
+
+	return pgid
+}
+
+// continueProcess sends SIGCONT to a process, continuing it if it was stopped.
+func continueProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGCONT)
+}
@@ -0,0 +1,39 @@
+package shell
+
+import (
+	"fmt"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("let", func(ctx *builtins.Context, args ...string) error {
+		return letCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "let EXPR...",
+		Summary:  "evaluate arithmetic expressions; fails if the last is zero",
+	})
+}
+
+// letCommand handles the "let" built-in command.
+// Each argument is an arithmetic expression, as in $(( ... )), evaluated in
+// order; assignments such as i+=1 update shell variables. Like (( ... )), it
+// succeeds unless the last expression evaluates to zero.
+func letCommand(ctx *builtins.Context, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: let needs an expression", builtins.ErrInvalidArgCount)
+	}
+	if ctx.Vars == nil {
+		return fmt.Errorf("%w: let: no shell variables", builtins.ErrInvalidArgs)
+	}
+
+	var n int64
+	for _, expr := range args {
+		var err error
+		if n, err = evalArith(expr, ctx.Vars); err != nil {
+			return err
+		}
+	}
+
+	return arithStatus(n)
+}
@@ -0,0 +1,113 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("break", func(ctx *builtins.Context, args ...string) error {
+		return loopCommand(ctx, "break", args...)
+	}, builtins.Meta{
+		Synopsis: "break [N]",
+		Summary:  "leave the innermost N (by default 1) for, while or until loops",
+	})
+	builtins.Register("continue", func(ctx *builtins.Context, args ...string) error {
+		return loopCommand(ctx, "continue", args...)
+	}, builtins.Meta{
+		Synopsis: "continue [N]",
+		Summary:  "go on to the next turn of the Nth (by default 1) enclosing loop",
+	})
+}
+
+// loopControl is what break and continue return. It is passed up through
+// the commands of a loop's body, which stop there, to the loop it is for.
+type loopControl struct {
+	cont  bool // continue rather than break
+	depth int  // how many loops out it is for, 1 for the innermost
+}
+
+func (l *loopControl) Error() string {
+	if l.cont {
+		return "continue: not in a loop"
+	}
+
+	return "break: not in a loop"
+}
+
+// loopCommand handles the "break" and "continue" built-in commands.
+// break leaves the Nth enclosing loop, and continue goes on to its next
+// turn; N is 1 by default, and if there are fewer loops, the outermost is
+// meant.
+func loopCommand(ctx *builtins.Context, name string, args ...string) error {
+	v, err := shellVars(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: %v takes at most one argument", builtins.ErrInvalidArgCount, name)
+	}
+	n := 1
+	if len(args) == 1 {
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return fmt.Errorf("%w: %v: %q: loop count out of range", builtins.ErrInvalidArgs, name, args[0])
+		}
+	}
+	v.mu.RLock()
+	loops := v.loops
+	v.mu.RUnlock()
+	if loops == 0 {
+		return fmt.Errorf("%w: %v: only meaningful in a for, while or until loop", builtins.ErrInvalidArgs, name)
+	}
+	if n > loops {
+		n = loops
+	}
+
+	return &loopControl{cont: name == "continue", depth: n}
+}
+
+// enterLoop counts a loop as running, for break and continue, until the
+// function it returns is called.
+func (v *variables) enterLoop() func() {
+	v.mu.Lock()
+	v.loops++
+	v.mu.Unlock()
+
+	return func() {
+		v.mu.Lock()
+		v.loops--
+		v.mu.Unlock()
+	}
+}
+
+// leaving reports whether err is a break or continue on its way to its
+// loop, or a return on its way to the function call, which the commands it
+// passes through stop for.
+func leaving(err error) bool {
+	var (
+		l *loopControl
+		r *funcReturn
+	)
+
+	return errors.As(err, &l) || errors.As(err, &r)
+}
+
+// endTurn looks at the result of one run of a loop's body. It reports
+// whether the loop stops, and returns the error to go on with: nil for a
+// break or continue meant for this loop, and for one meant for an outer
+// loop, the same with one loop fewer to go. A return stops the loop and
+// goes on up.
+func endTurn(err error) (bool, error) {
+	var l *loopControl
+	if !errors.As(err, &l) {
+		return leaving(err), err
+	}
+	if l.depth > 1 {
+		return true, &loopControl{cont: l.cont, depth: l.depth - 1}
+	}
+
+	return !l.cont, nil
+}
@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// defaultNice is how much nice lowers a command's priority without -n.
+const defaultNice = 10
+
+func init() {
+	builtins.Register("nice", func(ctx *builtins.Context, args ...string) error {
+		return niceCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "nice [-n N] [CMD [ARG...]]",
+		Summary:  "run a command with a changed scheduling priority",
+		Flags:    []string{"-n N\tadd N to the niceness (default 10); only a privileged user may lower it"},
+	})
+}
+
+// niceCommand handles the "nice" built-in command.
+// It runs an external command with its niceness N more than the shell's, so
+// the scheduler favours it less; a negative N needs privileges. Without a
+// command it prints the shell's niceness. The command's priority is changed
+// before it runs where the platform allows, or else as soon as it starts.
+func niceCommand(ctx *builtins.Context, args ...string) error {
+	adjust := defaultNice
+	if len(args) > 0 && strings.HasPrefix(args[0], "-n") {
+		value := strings.TrimPrefix(args[0], "-n")
+		args = args[1:]
+		if value == "" {
+			if len(args) == 0 {
+				return fmt.Errorf("%w: -n requires an argument", builtins.ErrInvalidArgCount)
+			}
+			value, args = args[0], args[1:]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid adjustment %q", builtins.ErrInvalidArgs, value)
+		}
+		adjust = n
+	}
+
+	current, err := builtins.Niceness(0)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		_, err := fmt.Fprintln(ctx.Stdout, current)
+		return err
+	}
+	if builtins.IsBuiltin(args[0]) {
+		return fmt.Errorf("%w: nice: %v is a shell builtin", builtins.ErrInvalidArgs, args[0])
+	}
+	if err := checkCommand(ctx, args[0], args[1:]); err != nil {
+		return err
+	}
+
+	cmd, err := newCommand(ctx, args[0], args[1:]...)
+	if err != nil {
+		return err
+	}
+	defer builtins.CookedTerminal()()
+	start := time.Now()
+	if err := startNiced(cmd, current+adjust); err != nil {
+		return err
+	}
+
+	return waitProcess(ctx, cmd, start)
+}
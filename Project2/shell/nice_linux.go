@@ -0,0 +1,27 @@
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// startNiced starts cmd with niceness n. Linux keeps a niceness per thread
+// and a child inherits its parent thread's, so cmd is started from a thread
+// of its own with n set. That thread is never unlocked, so it ends with its
+// goroutine instead of running the shell at n.
+func startNiced(cmd *exec.Cmd, n int) error {
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := builtins.SetNiceness(syscall.Gettid(), n); err != nil {
+			_, _ = fmt.Fprintf(cmd.Stderr, "nice: cannot set niceness: %v\n", err)
+		}
+		errc <- cmd.Start()
+	}()
+
+	return <-errc
+}
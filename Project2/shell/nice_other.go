@@ -0,0 +1,23 @@
+//go:build !linux
+
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// startNiced starts cmd and then sets its niceness to n, as the niceness
+// can't be set for a child alone before it starts.
+func startNiced(cmd *exec.Cmd, n int) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := builtins.SetNiceness(cmd.Process.Pid, n); err != nil {
+		_, _ = fmt.Fprintf(cmd.Stderr, "nice: cannot set niceness: %v\n", err)
+	}
+
+	return nil
+}
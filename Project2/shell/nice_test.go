@@ -0,0 +1,38 @@
+package shell
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_niceCommand(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("reads the niceness from /proc")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs sh")
+	}
+
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	ctx := sh.context(strings.NewReader(""), w)
+	require.NoError(t, niceCommand(ctx))
+	current, err := strconv.Atoi(strings.TrimSpace(w.String()))
+	require.NoError(t, err)
+
+	w.Reset()
+	require.NoError(t, niceCommand(ctx, "-n", "3", "sh", "-c", `cut -d" " -f19 /proc/$$/stat`))
+	require.Equal(t, strconv.Itoa(current+3)+"\n", w.String())
+
+	require.ErrorIs(t, niceCommand(ctx, "-n"), builtins.ErrInvalidArgCount)
+	require.ErrorIs(t, niceCommand(ctx, "-n", "x", "true"), builtins.ErrInvalidArgs)
+	require.ErrorIs(t, niceCommand(ctx, "echo", "x"), builtins.ErrInvalidArgs)
+}
@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("nohup", func(ctx *builtins.Context, args ...string) error {
+		return nohupCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "nohup CMD [ARG...]",
+		Summary:  "run a command immune to hangups, with output to nohup.out",
+	})
+}
+
+// nohupOut is the file nohup appends output to instead of a terminal.
+const nohupOut = "nohup.out"
+
+// nohupCommand handles the "nohup" built-in command.
+// It runs an external command with SIGHUP ignored, so that it survives the
+// shell exiting or the terminal closing; use "nohup CMD &" to run it in the
+// background. Output that would go to a terminal is appended to nohup.out in
+// the working directory, or in $HOME if that can't be written, and input
+// from a terminal is replaced with nothing.
+func nohupCommand(ctx *builtins.Context, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected a command", builtins.ErrInvalidArgCount)
+	}
+	if builtins.IsBuiltin(args[0]) {
+		return fmt.Errorf("%w: nohup: %v is a shell builtin", builtins.ErrInvalidArgs, args[0])
+	}
+	if err := checkCommand(ctx, args[0], args[1:]); err != nil {
+		return err
+	}
+
+	// The shell shutting down doesn't stop the command either.
+	cmd, err := newCommand(ctx.WithContext(context.Background()), args[0], args[1:]...)
+	if err != nil {
+		return err
+	}
+	ignoreHangup(cmd)
+	if isTerminal(cmd.Stdin) {
+		cmd.Stdin = nil
+	}
+	if isTerminal(cmd.Stdout) {
+		f, err := openNohupOut()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, _ = fmt.Fprintf(ctx.Stderr, "nohup: ignoring input and appending output to '%v'\n", f.Name())
+		cmd.Stdout = f
+	}
+	if isTerminal(cmd.Stderr) {
+		cmd.Stderr = cmd.Stdout
+	}
+
+	return runProcess(ctx, cmd)
+}
+
+// openNohupOut opens nohup.out for appending in the working directory, or
+// else in the home directory.
+func openNohupOut() (*os.File, error) {
+	f, err := os.OpenFile(nohupOut, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err == nil || builtins.HomeDir == "" {
+		return f, err
+	}
+
+	return os.OpenFile(filepath.Join(builtins.HomeDir, nohupOut), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+}
+
+// isTerminal reports whether v is a file open on a terminal, taken to be any
+// character device other than the null device.
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+
+	return err != nil || !os.SameFile(fi, null)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package shell
+
+import "os/exec"
+
+// ignoreHangup does nothing: there is no SIGHUP here.
+func ignoreHangup(*exec.Cmd) {}
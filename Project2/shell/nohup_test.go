@@ -0,0 +1,35 @@
+package shell
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nohupCommand(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGHUP on windows")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs sh")
+	}
+
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	ctx := sh.context(strings.NewReader(""), w)
+	script := "kill -HUP $$; echo survived"
+	require.NoError(t, nohupCommand(ctx, "sh", "-c", script))
+	require.Equal(t, "survived\n", w.String(), "output that is not a terminal stays put")
+
+	err := runCommand(ctx, "sh", "-c", script)
+	require.Equal(t, builtins.StatusSignal+1, builtins.StatusOf(err), "without nohup SIGHUP kills the command")
+
+	require.ErrorIs(t, nohupCommand(ctx, "echo", "x"), builtins.ErrInvalidArgs)
+	require.ErrorIs(t, nohupCommand(ctx), builtins.ErrInvalidArgCount)
+}
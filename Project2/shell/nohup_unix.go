@@ -0,0 +1,18 @@
+//go:build !windows
+
+package shell
+
+import "os/exec"
+
+// ignoreHangup makes cmd start with SIGHUP ignored, which lasts across exec.
+// Go can't set that for a child alone, and ignoring it in the shell can't be
+// undone, so a POSIX sh sets it and then becomes the command.
+func ignoreHangup(cmd *exec.Cmd) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return
+	}
+	cmd.Args = append([]string{"sh", "-c", `trap "" HUP; exec "$@"`, "nohup"}, cmd.Args...)
+	cmd.Path = sh
+	cmd.Err = nil
+}
@@ -0,0 +1,139 @@
+package shell
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// Shell option names, as given to set -o.
+const (
+	optAutopage   = "autopage"   // page long listings from builtins such as help on the terminal
+	optCmdstats   = "cmdstats"   // report each external command's resource usage
+	optCorrect    = "correct"    // offer to run the closest name for an unknown command
+	optDirenv     = "direnv"     // load allowed .goshenv files for the working directory
+	optErrexit    = "errexit"    // set -e: exit when a command fails
+	optExplain    = "explain"    // print simple commands expanded instead of running them
+	optNoglob     = "noglob"     // set -f: don't expand pathname patterns such as *.go
+	optNotify     = "notify"     // set -b: report finished jobs at once, not before the next prompt
+	optNounset    = "nounset"    // set -u: expanding an unset variable is an error
+	optRestricted = "restricted" // set -r: see Restrict; can't be turned off
+	optTermtitle  = "termtitle"  // report the working directory (OSC 7) and command to the terminal's title
+	optXtrace     = "xtrace"     // set -x: print commands before running them
+)
+
+// optionLetters maps the single-letter forms of set to option names.
+var optionLetters = map[byte]string{
+	'b': optNotify,
+	'e': optErrexit,
+	'f': optNoglob,
+	'r': optRestricted,
+	'u': optNounset,
+	'x': optXtrace,
+}
+
+// options are the shell options, all off by default.
+type options struct {
+	mu      sync.RWMutex
+	on      map[string]bool
+	allowed map[string]bool // if set, the only commands a restricted shell runs
+}
+
+func newOptions() *options {
+	return &options{on: map[string]bool{
+		optAutopage:   false,
+		optCmdstats:   false,
+		optCorrect:    false,
+		optDirenv:     false,
+		optErrexit:    false,
+		optExplain:    false,
+		optNoglob:     false,
+		optNotify:     false,
+		optNounset:    false,
+		optRestricted: false,
+		optTermtitle:  false,
+		optXtrace:     false,
+	}}
+}
+
+// Option reports whether the named option is on, and whether it exists.
+func (o *options) Option(name string) (on, ok bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	on, ok = o.on[name]
+
+	return on, ok
+}
+
+// SetOption turns the named option on or off. Restricted mode can only be
+// turned on.
+func (o *options) SetOption(name string, on bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	was, ok := o.on[name]
+	if !ok {
+		return fmt.Errorf("%w: %q is not a shell option", builtins.ErrInvalidArgs, name)
+	}
+	if name == optRestricted && was && !on {
+		return fmt.Errorf("%w: cannot be turned off", ErrRestricted)
+	}
+	o.on[name] = on
+
+	return nil
+}
+
+// OptionNames returns the option names in sorted order.
+func (o *options) OptionNames() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	names := make([]string, 0, len(o.on))
+	for name := range o.on {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// allowOnly sets the only commands the shell may run.
+func (o *options) allowOnly(names []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.allowed = map[string]bool{}
+	for _, name := range names {
+		o.allowed[name] = true
+	}
+}
+
+// allows reports whether the shell may run the command name: any, unless
+// allowOnly said otherwise.
+func (o *options) allows(name string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.allowed == nil || o.allowed[name]
+}
+
+// enabled reports whether the named option is on.
+func (o *options) enabled(name string) bool {
+	on, _ := o.Option(name)
+	return on
+}
+
+// snapshot returns a function restoring the options to their current values.
+func (o *options) snapshot() func() {
+	o.mu.RLock()
+	saved := make(map[string]bool, len(o.on))
+	for name, on := range o.on {
+		saved[name] = on
+	}
+	o.mu.RUnlock()
+
+	return func() {
+		o.mu.Lock()
+		o.on = saved
+		o.mu.Unlock()
+	}
+}
@@ -0,0 +1,333 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// errSubstring is returned for ${NAME:OFF:LEN} whose LEN ends before OFF.
+var errSubstring = errors.New("substring expression < 0")
+
+// expandParam expands the ${...} parameter whose text is param, passing its
+// value to emit. Besides ${NAME} and the array expansions of expandArray, it
+// handles the string operations:
+//
+//	${#NAME}          the length of the value in characters
+//	${NAME#PATTERN}   the value without the shortest prefix matching PATTERN
+//	${NAME##PATTERN}  ... without the longest such prefix
+//	${NAME%PATTERN}   ... without the shortest suffix matching PATTERN
+//	${NAME%%PATTERN}  ... without the longest such suffix
+//	${NAME/OLD/NEW}   the value with the longest match of OLD replaced by NEW;
+//	                  // replaces every match, /# a prefix and /% a suffix
+//	${NAME:OFF:LEN}   LEN characters of the value from OFF; an OFF or LEN
+//	                  below zero counts back from the end
+//
+// The patterns and NEW are expanded as words, so that quoting them makes
+// them literal. The operations apply to single elements, as in
+// ${NAME[1]#PATTERN}, but not to ${NAME[@]}.
+func (s *Shell) expandParam(param string, emit func(string, piece)) error {
+	if len(param) > 1 && param[0] == '#' {
+		if strings.HasSuffix(param, "]") {
+			return s.expandArray(param, emit)
+		}
+		if !validName(param[1:]) {
+			return fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+		}
+		v, err := s.variable(param[1:])
+		if err != nil {
+			return err
+		}
+		emit(strconv.Itoa(utf8.RuneCountInString(v)), paramValue)
+		return nil
+	}
+
+	if param == "#" || param == "@" || param == "*" || strings.Trim(param, "0123456789") == "" {
+		return s.positional(param, emit)
+	}
+
+	n := 0
+	for n < len(param) && isNameChar(param[n]) {
+		n++
+	}
+	name, op := param[:n], param[n:]
+	if !validName(name) {
+		return fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+	}
+	if strings.HasPrefix(op, "[") {
+		close := strings.IndexByte(op, ']')
+		if close < 0 {
+			return fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+		}
+		if index := op[1:close]; close == len(op)-1 || index == "@" || index == "*" {
+			return s.expandArray(param, emit)
+		}
+		name, op = param[:n+close+1], op[close+1:]
+	}
+
+	var (
+		v   string
+		err error
+	)
+	if strings.HasSuffix(name, "]") {
+		err = s.expandArray(name, func(text string, _ piece) { v = text })
+	} else {
+		v, err = s.variable(name)
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case op == "":
+	case op[0] == '#' || op[0] == '%':
+		v, err = s.trimPattern(v, op)
+	case op[0] == '/':
+		v, err = s.replacePattern(v, op[1:])
+	case op[0] == ':' && (len(op) == 1 || strings.IndexByte("-=?+", op[1]) < 0):
+		v, err = s.substring(v, op[1:])
+	default:
+		err = fmt.Errorf("${%v}: %w", param, ErrBadSubstitution)
+	}
+	if err != nil {
+		return err
+	}
+	emit(v, paramValue)
+
+	return nil
+}
+
+// trimPattern removes the prefix (#) or suffix (%) of v matching the pattern
+// after op's # or %, the shortest one, or the longest if the # or % is
+// doubled.
+func (s *Shell) trimPattern(v, op string) (string, error) {
+	c := op[0]
+	longest := len(op) > 1 && op[1] == c
+	if longest {
+		op = op[1:]
+	}
+	pattern, err := s.operand(op[1:], true)
+	if err != nil {
+		return "", err
+	}
+
+	// Cutting at each character boundary in turn from the start gives the
+	// shortest prefix or the longest suffix first.
+	cuts := make([]int, 0, len(v)+1)
+	for i := range v {
+		cuts = append(cuts, i)
+	}
+	cuts = append(cuts, len(v))
+	for k := range cuts {
+		i := cuts[k]
+		if c == '#' == longest {
+			i = cuts[len(cuts)-1-k]
+		}
+		if c == '#' && matchPattern(pattern, v[:i]) {
+			return v[i:], nil
+		}
+		if c == '%' && matchPattern(pattern, v[i:]) {
+			return v[:i], nil
+		}
+	}
+
+	return v, nil
+}
+
+// replacePattern replaces the longest match of a pattern in v, for
+// ${NAME/OLD/NEW}. op is what follows the first /: OLD/NEW, /OLD/NEW to
+// replace every match, #OLD/NEW for a match at the start or %OLD/NEW at the
+// end. Without /NEW, the match is removed.
+func (s *Shell) replacePattern(v, op string) (string, error) {
+	var mode byte
+	if op != "" && strings.IndexByte("/#%", op[0]) >= 0 {
+		mode, op = op[0], op[1:]
+	}
+	old, repl := op, ""
+	if i := operandSlash(op); i >= 0 {
+		old, repl = op[:i], op[i+1:]
+	}
+	pattern, err := s.operand(old, true)
+	if err != nil {
+		return "", err
+	}
+	if repl, err = s.operand(repl, false); err != nil {
+		return "", err
+	}
+	if pattern == "" {
+		return v, nil
+	}
+
+	switch mode {
+	case '#':
+		if end := longestMatch(pattern, v, 0); end >= 0 {
+			return repl + v[end:], nil
+		}
+		return v, nil
+	case '%':
+		for i := range v + " " {
+			if matchPattern(pattern, v[i:]) {
+				return v[:i] + repl, nil
+			}
+		}
+		return v, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(v); {
+		// Empty matches are skipped, as they would replace nothing.
+		if end := longestMatch(pattern, v, i); end > i {
+			b.WriteString(repl)
+			i = end
+			if mode != '/' {
+				b.WriteString(v[i:])
+				break
+			}
+			continue
+		}
+		_, n := utf8.DecodeRuneInString(v[i:])
+		b.WriteString(v[i : i+n])
+		i += n
+	}
+
+	return b.String(), nil
+}
+
+// longestMatch returns the end of the longest match of pattern in v starting
+// at start, or -1.
+func longestMatch(pattern, v string, start int) int {
+	for end := len(v); end >= start; end-- {
+		if end < len(v) && !utf8.RuneStart(v[end]) {
+			continue
+		}
+		if matchPattern(pattern, v[start:end]) {
+			return end
+		}
+	}
+
+	return -1
+}
+
+// substring returns the part of v given by op, OFF or OFF:LEN, whose
+// numbers are arithmetic expressions, for ${NAME:OFF:LEN}.
+func (s *Shell) substring(v, op string) (string, error) {
+	runes := []rune(v)
+	offExpr, lenExpr, hasLen := strings.Cut(op, ":")
+	off, err := s.subscript(offExpr)
+	if err != nil {
+		return "", err
+	}
+	if off < 0 {
+		off += len(runes)
+	}
+	if off < 0 || off > len(runes) {
+		return "", nil
+	}
+	end := len(runes)
+	if hasLen {
+		n, err := s.subscript(lenExpr)
+		if err != nil {
+			return "", err
+		}
+		if n < 0 {
+			end += n
+		} else if off+n < end {
+			end = off + n
+		}
+		if end < off {
+			return "", fmt.Errorf("%v: %w", lenExpr, errSubstring)
+		}
+	}
+
+	return string(runes[off:end]), nil
+}
+
+// operand expands the pattern or replacement text of a ${...} operation as a
+// word, with blanks kept. As a pattern, its quoted parts only match
+// themselves.
+func (s *Shell) operand(text string, pattern bool) (string, error) {
+	p := &parser{src: text}
+	var w word
+	for p.pos < len(p.src) {
+		part, err := p.word()
+		if err != nil {
+			return "", err
+		}
+		if len(part) == 0 {
+			// The characters that end words are ordinary here.
+			part = word{{text: p.src[p.pos : p.pos+1]}}
+			p.pos++
+		}
+		w = append(w, part...)
+	}
+	if pattern {
+		return s.patternWord(w)
+	}
+
+	return s.expandWord(s.tildeExpand(w))
+}
+
+// operandSlash returns the index of the / that separates OLD from NEW in
+// ${NAME/OLD/NEW}, skipping quoted and escaped ones, or -1.
+func operandSlash(op string) int {
+	for i := 0; i < len(op); i++ {
+		switch op[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			end := strings.IndexByte(op[i+1:], op[i])
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		case '$':
+			if end := paramEnd(op, i); end >= 0 {
+				i = end
+			}
+		case '/':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// paramEnd returns the index of the } that closes the ${ at s[i:], or -1.
+// Quoted text, escaped characters and ${...} inside are skipped over.
+func paramEnd(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(s) {
+				return -1
+			}
+		case '$':
+			if i+1 < len(s) && s[i+1] == '{' {
+				depth++
+				i++
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,75 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_expandParam(t *testing.T) {
+	t.Parallel()
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, sh.vars.Set("f", "/src/dir/main.test.go"))
+	require.NoError(t, sh.vars.Set("s", "héllo wörld"))
+	require.NoError(t, sh.vars.Set("p", "*.go"))
+	require.NoError(t, sh.vars.SetArray("a", []string{"one", "two"}))
+
+	tests := []struct {
+		word    string
+		want    string
+		wantErr error
+	}{
+		{word: "${#f}", want: "21"},
+		{word: "${#s}", want: "11"},
+		{word: "${f#*/}", want: "src/dir/main.test.go"},
+		{word: "${f##*/}", want: "main.test.go"},
+		{word: "${f%.*}", want: "/src/dir/main.test"},
+		{word: "${f%%.*}", want: "/src/dir/main"},
+		{word: "${f#nomatch}", want: "/src/dir/main.test.go"},
+		{word: "${f%$p}", want: "/src/dir/main.test"},
+		{word: `${f%"$p"}`, want: "/src/dir/main.test.go"},
+		{word: "${f/main/MAIN}", want: "/src/dir/MAIN.test.go"},
+		{word: "${f//[aeiou]/_}", want: "/src/d_r/m__n.t_st.g_"},
+		{word: "${f/#\\/src/SRC}", want: "SRC/dir/main.test.go"},
+		{word: "${f/#dir/x}", want: "/src/dir/main.test.go"},
+		{word: "${f/%go/rs}", want: "/src/dir/main.test.rs"},
+		{word: "${f/\\/dir}", want: "/src/main.test.go"},
+		{word: "${f/t*t/X}", want: "/src/dir/main.X.go"},
+		{word: `${s/ /"  "}`, want: "héllo  wörld"},
+		{word: "${s/ö/${a[1]}}", want: "héllo wtworld"},
+		{word: "${s:1:3}", want: "éll"},
+		{word: "${s: -5}", want: "wörld"},
+		{word: "${s:1:-1}", want: "éllo wörl"},
+		{word: "${s:2*3}", want: "wörld"},
+		{word: "${s:20}", want: ""},
+		{word: "${a[1]#t}", want: "wo"},
+		{word: "${#a[0]}", want: "3"},
+		{word: "${s:2:-9}", want: ""},
+		{word: "${s:3:-9}", wantErr: errSubstring},
+		{word: "${f:-default}", wantErr: ErrBadSubstitution},
+		{word: "${a[@]#o}", wantErr: ErrBadSubstitution},
+		{word: "${1x}", wantErr: ErrBadSubstitution},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.word, func(t *testing.T) {
+			t.Parallel()
+			got, err := sh.expand(tt.word)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestShell_RunLine_stringOperations(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine(`f="a b.txt"; echo "${f%.txt}" ${f/ /_} "${f/ /}"`)
+	require.NoError(t, err)
+	require.Equal(t, "a b a_b.txt ab.txt\n", w.String())
+}
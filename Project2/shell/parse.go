@@ -0,0 +1,1213 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSyntax is returned for command lines that cannot be parsed.
+	ErrSyntax = errors.New("syntax error")
+	// errIncomplete means the input ends inside a quote or before a
+	// here-document's delimiter, so more lines are needed.
+	errIncomplete = fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+)
+
+// quoting says how a part of a word was quoted, which decides how it is expanded.
+type quoting int
+
+const (
+	unquoted quoting = iota
+	singleQuoted
+	doubleQuoted
+	arithmetic     // the expression inside $(( ... ))
+	procIn         // the commands inside <( ... ), whose output is read from a path
+	procOut        // the commands inside >( ... ), whose input is written to a path
+	cmdSubst       // the commands inside $( ... ) or ` ... `, replaced by their output
+	quotedCmdSubst // a command substitution inside double quotes, whose output is not split
+)
+
+// separate reports whether parts quoted as q stand alone rather than being
+// merged with neighbouring parts quoted the same way.
+func separate(q quoting) bool {
+	return q == arithmetic || q == procIn || q == procOut || q == cmdSubst || q == quotedCmdSubst
+}
+
+// wordPart is a run of a word's text sharing one kind of quoting.
+type wordPart struct {
+	text  string
+	quote quoting
+}
+
+// word is a shell word, e.g. pre"$HOME"'$x' is three parts.
+type word []wordPart
+
+// quoted reports whether any part of the word was quoted.
+func (w word) quoted() bool {
+	for _, p := range w {
+		if p.quote != unquoted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// literal returns the word's text without quotes or expansion.
+func (w word) literal() string {
+	var b strings.Builder
+	for _, p := range w {
+		b.WriteString(p.text)
+	}
+
+	return b.String()
+}
+
+// redirection operators.
+const (
+	redirIn         = "<"
+	redirOut        = ">"
+	redirAppend     = ">>"
+	redirDupIn      = "<&"
+	redirDupOut     = ">&"
+	redirHeredoc    = "<<"
+	redirHeredocT   = "<<-" // strips leading tabs from the body and delimiter
+	redirHereString = "<<<"
+)
+
+// redirect is one redirection of a command, e.g. 2>>log or <<EOF.
+type redirect struct {
+	fd     int
+	op     string
+	target word // file, descriptor, here-string or here-document delimiter
+
+	body   string // here-document text
+	expand bool   // whether the here-document body is expanded
+}
+
+// node is a parsed command: a *command, a *group, an *arithCommand, a
+// *caseCommand, a *forLoop, an *ifCommand, a *whileLoop, a *funcDef, a
+// *pipeline, an *andOr or a *background.
+type node interface{}
+
+// terminators are the characters that end a command.
+const terminators = "\n;&)|"
+
+// command is a simple command: words to expand and run, plus redirections
+// and the assignments before its words.
+type command struct {
+	assigns []*assignment
+	args    []word
+	redirs  []*redirect
+}
+
+// assignment is NAME=value, NAME[INDEX]=value or NAME=(VALUE...).
+type assignment struct {
+	name     string
+	index    string // the subscript of NAME[INDEX], an arithmetic expression
+	hasIndex bool
+	value    word
+	array    []word
+	isArray  bool
+}
+
+// group is a list of commands run as one, either in a subshell ( ... ) whose
+// changes to the working directory and environment are undone afterwards, or
+// as a brace group { ...; } in the current shell. Its redirections apply to
+// every command inside.
+type group struct {
+	body     []node
+	subshell bool
+	redirs   []*redirect
+}
+
+// arithCommand is (( expr )), which succeeds if expr is non-zero.
+type arithCommand struct {
+	expr string
+}
+
+// caseCommand is case WORD in PATTERN) LIST ;; ... esac, which runs the list
+// of the first item with a pattern matching WORD.
+type caseCommand struct {
+	word   word
+	items  []caseItem
+	redirs []*redirect
+}
+
+// caseItem is one PATTERN|PATTERN) LIST ;; of a case command.
+type caseItem struct {
+	patterns []word
+	body     []node
+}
+
+// forLoop is for NAME in WORD...; do LIST; done, which runs the list with
+// NAME set to each word in turn.
+type forLoop struct {
+	name   string
+	words  []word
+	body   []node
+	redirs []*redirect
+}
+
+// ifCommand is if LIST; then LIST; [elif LIST; then LIST;]... [else LIST;]
+// fi, which runs the list after the first condition that succeeds, or the
+// else list if none does.
+type ifCommand struct {
+	clauses  []ifClause
+	elseBody []node
+	redirs   []*redirect
+}
+
+// ifClause is the condition and the list of an if or elif.
+type ifClause struct {
+	cond []node
+	body []node
+}
+
+// whileLoop is while LIST; do LIST; done, which runs the body for as long as
+// the condition succeeds, or with until, for as long as it fails.
+type whileLoop struct {
+	cond   []node
+	body   []node
+	until  bool
+	redirs []*redirect
+}
+
+// funcDef is NAME() BODY or function NAME BODY, which defines a function
+// running BODY, a compound command, with its arguments as $1, $2, ...
+type funcDef struct {
+	name string
+	body node
+	src  string // the definition as written, which parses to the same function
+}
+
+// pipeline is CMD | CMD ..., whose commands run at the same time, each
+// reading the output of the one before.
+type pipeline struct {
+	stages []node
+}
+
+// andOr is LEFT && RIGHT or LEFT || RIGHT, which runs RIGHT only if LEFT
+// succeeds, or with ||, only if it fails. A chain such as a && b || c nests
+// to the left.
+type andOr struct {
+	left  node
+	right node
+	or    bool
+}
+
+// background is a command run asynchronously as a job: cmd &.
+type background struct {
+	node node
+	text string // the command as written, for job listings
+}
+
+// parse splits src into a list of commands separated by newlines, ";" or "&".
+// Here-document bodies follow the line that introduces them. errIncomplete
+// means src needs more lines.
+func parse(src string) ([]node, error) {
+	p := &parser{src: src}
+	nodes, err := p.list("")
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos])
+	}
+
+	return nodes, nil
+}
+
+// parser reads commands from src, starting at pos.
+type parser struct {
+	src     string
+	pos     int
+	pending []*redirect // here-documents whose bodies start after the next newline
+	// continued is set when src ends with a line continuation.
+	continued bool
+}
+
+// list parses commands until the end of src or, inside a group or compound
+// command, its closing ")", "}" or reserved word, which is left for the
+// caller. closing may give several reserved words separated by spaces, as
+// "elif else fi" does after then. In the item of a case command, closing is
+// "esac", and the list also ends before ";;".
+func (p *parser) list(closing string) ([]node, error) {
+	var nodes []node
+	for {
+		p.skipBlanks()
+		switch {
+		case p.pos == len(p.src):
+			if len(p.pending) > 0 || closing != "" || p.continued {
+				return nil, errIncomplete
+			}
+			return nodes, nil
+		case p.src[p.pos] == '\n':
+			p.pos++
+			if err := p.heredocs(); err != nil {
+				return nil, err
+			}
+			continue
+		case closing == "esac" && (strings.HasPrefix(p.src[p.pos:], ";;") || p.reserved("esac")):
+			return nodes, nil
+		case p.src[p.pos] == ';' || p.src[p.pos] == '&' || p.src[p.pos] == '|':
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos:p.pos+1])
+		case p.src[p.pos] == ')':
+			if closing != ")" {
+				return nil, fmt.Errorf("%w: unexpected \")\"", ErrSyntax)
+			}
+			return nodes, nil
+		case p.closes(closing):
+			return nodes, nil
+		case p.closes("then elif else fi do done esac"):
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos:wordEnd(p.src, p.pos)])
+		}
+
+		start := p.pos
+		n, err := p.andOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlanks()
+		if p.pos < len(p.src) && p.src[p.pos] == '&' {
+			n = &background{node: n, text: strings.TrimSpace(p.src[start:p.pos])}
+			p.pos++
+		} else if p.pos < len(p.src) && p.src[p.pos] == ';' && !strings.HasPrefix(p.src[p.pos:], ";;") {
+			p.pos++
+		}
+		nodes = append(nodes, n)
+	}
+}
+
+// closes reports whether one of the reserved words in closing comes next.
+func (p *parser) closes(closing string) bool {
+	for _, w := range strings.Fields(closing) {
+		if w != ")" && p.reserved(w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reserved reports whether a reserved word such as "{" or "}" comes next,
+// standing alone rather than starting a longer word.
+func (p *parser) reserved(w string) bool {
+	if !strings.HasPrefix(p.src[p.pos:], w) {
+		return false
+	}
+	end := p.pos + len(w)
+
+	return end == len(p.src) || strings.IndexByte(" \t\r\n;&()<>|", p.src[end]) >= 0
+}
+
+// andOr parses a pipeline, or pipelines joined by "&&" and "||", which may
+// be followed by newlines.
+func (p *parser) andOr() (node, error) {
+	n, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipBlanks()
+		rest := p.src[p.pos:]
+		if !strings.HasPrefix(rest, "&&") && !strings.HasPrefix(rest, "||") {
+			return n, nil
+		}
+		p.pos += 2
+		if err := p.skipNewlines(); err != nil {
+			return nil, err
+		}
+		if strings.IndexByte(terminators, p.src[p.pos]) >= 0 {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos:p.pos+1])
+		}
+		right, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		n = &andOr{left: n, right: right, or: rest[0] == '|'}
+	}
+}
+
+// pipeline parses a command, or commands joined by "|" into a pipeline. The
+// pipeline goes on to the next line after a "|" at the end of one.
+func (p *parser) pipeline() (node, error) {
+	n, err := p.command()
+	if err != nil {
+		return nil, err
+	}
+	stages := []node{n}
+	for {
+		p.skipBlanks()
+		if p.pos == len(p.src) || p.src[p.pos] != '|' || strings.HasPrefix(p.src[p.pos:], "||") {
+			break
+		}
+		p.pos++
+		if err := p.skipNewlines(); err != nil {
+			return nil, err
+		}
+		if strings.IndexByte(terminators, p.src[p.pos]) >= 0 {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos:p.pos+1])
+		}
+		n, err := p.command()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, n)
+	}
+	if len(stages) == 1 {
+		return n, nil
+	}
+
+	return &pipeline{stages: stages}, nil
+}
+
+// command parses a group or a simple command, ending before a terminator.
+func (p *parser) command() (node, error) {
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "(("):
+		return p.arithCommand()
+	case p.src[p.pos] == '(':
+		p.pos++
+		return p.group(")", true)
+	case p.reserved("{"):
+		p.pos++
+		return p.group("}", false)
+	case p.reserved("case"):
+		return p.caseCommand()
+	case p.reserved("for"):
+		return p.forLoop()
+	case p.reserved("if"):
+		return p.ifCommand()
+	case p.reserved("while"), p.reserved("until"):
+		return p.whileLoop()
+	}
+	if f, ok, err := p.funcDef(); err != nil || ok {
+		return f, err
+	}
+
+	cmd := &command{}
+	for {
+		p.skipBlanks()
+		if p.pos == len(p.src) || strings.IndexByte(terminators, p.src[p.pos]) >= 0 {
+			return cmd, nil
+		}
+		if p.src[p.pos] == '(' {
+			return nil, fmt.Errorf("%w: unexpected \"(\"", ErrSyntax)
+		}
+		if r, ok, err := p.redirection(); err != nil {
+			return nil, err
+		} else if ok {
+			cmd.redirs = append(cmd.redirs, r)
+			continue
+		}
+		if len(cmd.args) == 0 {
+			if a, ok, err := p.assignment(); err != nil {
+				return nil, err
+			} else if ok {
+				cmd.assigns = append(cmd.assigns, a)
+				continue
+			}
+		}
+		w, err := p.word()
+		if err != nil {
+			return nil, err
+		}
+		cmd.args = append(cmd.args, w)
+	}
+}
+
+// group parses the body of a group after its opening token, then the closing
+// token and any redirections.
+func (p *parser) group(closing string, subshell bool) (node, error) {
+	body, err := p.list(closing)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("%w: empty %q group", ErrSyntax, closing)
+	}
+	p.pos += len(closing)
+
+	redirs, err := p.trailingRedirections(closing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group{body: body, subshell: subshell, redirs: redirs}, nil
+}
+
+// trailingRedirections parses the redirections after a compound command
+// ending in closing, which must be all that comes before a terminator.
+func (p *parser) trailingRedirections(closing string) ([]*redirect, error) {
+	var redirs []*redirect
+	for {
+		p.skipBlanks()
+		r, ok, err := p.redirection()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		redirs = append(redirs, r)
+	}
+	if p.pos < len(p.src) && strings.IndexByte(terminators, p.src[p.pos]) < 0 {
+		return nil, fmt.Errorf("%w: unexpected text after %q", ErrSyntax, closing)
+	}
+
+	return redirs, nil
+}
+
+// assignment parses NAME=value, NAME[INDEX]=value or NAME=(VALUE...), if
+// one comes next. The values of an array may span lines.
+func (p *parser) assignment() (*assignment, bool, error) {
+	if p.pos == len(p.src) || !isNameStart(p.src[p.pos]) {
+		return nil, false, nil
+	}
+	i := p.pos + 1
+	for i < len(p.src) && isNameChar(p.src[i]) {
+		i++
+	}
+	a := &assignment{name: p.src[p.pos:i]}
+	if i < len(p.src) && p.src[i] == '[' {
+		end := strings.IndexByte(p.src[i:], ']')
+		if end < 0 {
+			return nil, false, nil
+		}
+		a.index, a.hasIndex = p.src[i+1:i+end], true
+		i += end + 1
+	}
+	if i == len(p.src) || p.src[i] != '=' {
+		return nil, false, nil
+	}
+	p.pos = i + 1
+
+	if a.hasIndex || !strings.HasPrefix(p.src[p.pos:], "(") {
+		w, err := p.word()
+		a.value = w
+		return a, true, err
+	}
+	p.pos++
+	a.isArray = true
+	for {
+		if err := p.skipNewlines(); err != nil {
+			return nil, false, err
+		}
+		if p.src[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		w, err := p.word()
+		if err != nil {
+			return nil, false, err
+		}
+		if len(w) == 0 {
+			return nil, false, fmt.Errorf("%w: unexpected %q in array", ErrSyntax, p.src[p.pos])
+		}
+		a.array = append(a.array, w)
+	}
+	if p.pos < len(p.src) && strings.IndexByte(" \t\r\n;&)", p.src[p.pos]) < 0 {
+		return nil, false, fmt.Errorf("%w: unexpected text after array", ErrSyntax)
+	}
+
+	return a, true, nil
+}
+
+// funcDef parses a function definition, NAME() BODY or function NAME BODY,
+// if one comes next. The body may start on a later line.
+func (p *parser) funcDef() (node, bool, error) {
+	start := p.pos
+	keyword := p.reserved("function")
+	if keyword {
+		p.pos += len("function")
+		p.skipBlanks()
+	}
+	end := p.pos
+	for end < len(p.src) && (isNameChar(p.src[end]) || end > p.pos && strings.IndexByte("-.", p.src[end]) >= 0) {
+		end++
+	}
+	name := p.src[p.pos:end]
+	p.pos = end
+	p.skipBlanks()
+	parens := strings.HasPrefix(p.src[p.pos:], "(")
+	if parens {
+		p.pos++
+		p.skipBlanks()
+		if !strings.HasPrefix(p.src[p.pos:], ")") {
+			p.pos = start
+			return nil, false, nil
+		}
+		p.pos++
+	}
+	if !keyword && !parens {
+		p.pos = start
+		return nil, false, nil
+	}
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return nil, false, p.expected("a function name")
+	}
+
+	if err := p.skipNewlines(); err != nil {
+		return nil, false, err
+	}
+	if !p.reserved("{") && !strings.HasPrefix(p.src[p.pos:], "(") && !p.compound() {
+		return nil, false, p.expected("a function body")
+	}
+	body, err := p.command()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &funcDef{name: name, body: body, src: strings.TrimSpace(p.src[start:p.pos])}, true, nil
+}
+
+// compound reports whether a compound command starting with a reserved word,
+// such as if or while, comes next.
+func (p *parser) compound() bool {
+	for _, w := range []string{"case", "for", "if", "while", "until"} {
+		if p.reserved(w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifCommand parses if LIST; then LIST; ... fi, starting at "if", with any
+// elif clauses and else list.
+func (p *parser) ifCommand() (node, error) {
+	c := &ifCommand{}
+	keyword := "if"
+	for {
+		p.pos += len(keyword)
+		cond, err := p.list("then")
+		if err != nil {
+			return nil, err
+		}
+		if len(cond) == 0 {
+			return nil, fmt.Errorf("%w: empty %q condition", ErrSyntax, keyword)
+		}
+		p.pos += len("then")
+		body, err := p.list("elif else fi")
+		if err != nil {
+			return nil, err
+		}
+		if len(body) == 0 {
+			return nil, fmt.Errorf("%w: empty \"then\" list", ErrSyntax)
+		}
+		c.clauses = append(c.clauses, ifClause{cond: cond, body: body})
+		if !p.reserved("elif") {
+			break
+		}
+		keyword = "elif"
+	}
+	if p.reserved("else") {
+		p.pos += len("else")
+		body, err := p.list("fi")
+		if err != nil {
+			return nil, err
+		}
+		if len(body) == 0 {
+			return nil, fmt.Errorf("%w: empty \"else\" list", ErrSyntax)
+		}
+		c.elseBody = body
+	}
+	p.pos += len("fi")
+
+	var err error
+	if c.redirs, err = p.trailingRedirections("fi"); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// whileLoop parses while LIST; do LIST; done or until LIST; do LIST; done,
+// starting at the first word.
+func (p *parser) whileLoop() (node, error) {
+	w := &whileLoop{until: p.reserved("until")}
+	keyword := "while"
+	if w.until {
+		keyword = "until"
+	}
+	p.pos += len(keyword)
+
+	var err error
+	if w.cond, err = p.list("do"); err != nil {
+		return nil, err
+	}
+	if len(w.cond) == 0 {
+		return nil, fmt.Errorf("%w: empty %q condition", ErrSyntax, keyword)
+	}
+	p.pos += len("do")
+	if w.body, err = p.list("done"); err != nil {
+		return nil, err
+	}
+	if len(w.body) == 0 {
+		return nil, fmt.Errorf("%w: empty \"do\" list", ErrSyntax)
+	}
+	p.pos += len("done")
+	if w.redirs, err = p.trailingRedirections("done"); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// forLoop parses for NAME in WORD...; do LIST; done, starting at "for".
+func (p *parser) forLoop() (node, error) {
+	p.pos += len("for")
+	p.skipBlanks()
+	name, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	if name.quoted() || !validName(name.literal()) {
+		return nil, p.expected("a variable name after \"for\"")
+	}
+	if err := p.skipNewlines(); err != nil {
+		return nil, err
+	}
+	if !p.reserved("in") {
+		return nil, p.expected("\"in\"")
+	}
+	p.pos += len("in")
+
+	f := &forLoop{name: name.literal()}
+	for {
+		p.skipBlanks()
+		if p.pos == len(p.src) {
+			return nil, errIncomplete
+		}
+		if c := p.src[p.pos]; c == ';' || c == '\n' {
+			p.pos++
+			if c == '\n' {
+				if err := p.heredocs(); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+		w, err := p.word()
+		if err != nil {
+			return nil, err
+		}
+		if len(w) == 0 {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.src[p.pos])
+		}
+		f.words = append(f.words, w)
+	}
+	if err := p.skipNewlines(); err != nil {
+		return nil, err
+	}
+	if !p.reserved("do") {
+		return nil, p.expected("\"do\"")
+	}
+	p.pos += len("do")
+
+	if f.body, err = p.list("done"); err != nil {
+		return nil, err
+	}
+	if len(f.body) == 0 {
+		return nil, fmt.Errorf("%w: empty \"do\" list", ErrSyntax)
+	}
+	p.pos += len("done")
+	if f.redirs, err = p.trailingRedirections("done"); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// caseCommand parses case WORD in ... esac, starting at "case". Each item is
+// an optional "(", patterns separated by "|", ")" and a list ending in ";;",
+// which the last item may leave out.
+func (p *parser) caseCommand() (node, error) {
+	p.pos += len("case")
+	p.skipBlanks()
+	subject, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	if len(subject) == 0 {
+		return nil, fmt.Errorf("%w: expected a word after \"case\"", ErrSyntax)
+	}
+	if err := p.skipNewlines(); err != nil {
+		return nil, err
+	}
+	if !p.reserved("in") {
+		return nil, p.expected("\"in\"")
+	}
+	p.pos += len("in")
+
+	c := &caseCommand{word: subject}
+	for {
+		if err := p.skipNewlines(); err != nil {
+			return nil, err
+		}
+		if p.reserved("esac") {
+			p.pos += len("esac")
+			break
+		}
+		item, err := p.caseItem()
+		if err != nil {
+			return nil, err
+		}
+		c.items = append(c.items, item)
+		if strings.HasPrefix(p.src[p.pos:], ";;") {
+			p.pos += 2
+		} else if !p.reserved("esac") {
+			return nil, p.expected("\";;\" or \"esac\"")
+		}
+	}
+	if c.redirs, err = p.trailingRedirections("esac"); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// caseItem parses the patterns and list of one item of a case command.
+func (p *parser) caseItem() (caseItem, error) {
+	var item caseItem
+	if p.src[p.pos] == '(' {
+		p.pos++
+	}
+	for {
+		p.skipBlanks()
+		w, err := p.word()
+		if err != nil {
+			return item, err
+		}
+		if len(w) == 0 {
+			return item, p.expected("a pattern")
+		}
+		item.patterns = append(item.patterns, w)
+		p.skipBlanks()
+		if p.pos == len(p.src) {
+			return item, errIncomplete
+		}
+		if p.src[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		if p.src[p.pos] != '|' {
+			return item, p.expected("\")\"")
+		}
+		p.pos++
+	}
+	body, err := p.list("esac")
+	item.body = body
+
+	return item, err
+}
+
+// skipNewlines skips blanks and newlines, reading any here-documents due.
+func (p *parser) skipNewlines() error {
+	for {
+		p.skipBlanks()
+		if p.pos == len(p.src) {
+			return errIncomplete
+		}
+		if p.src[p.pos] != '\n' {
+			return nil
+		}
+		p.pos++
+		if err := p.heredocs(); err != nil {
+			return err
+		}
+	}
+}
+
+// expected returns a syntax error for what comes next, or errIncomplete at
+// the end of src.
+func (p *parser) expected(what string) error {
+	if p.pos == len(p.src) {
+		return errIncomplete
+	}
+
+	return fmt.Errorf("%w: expected %v", ErrSyntax, what)
+}
+
+// arithCommand parses (( expr )).
+func (p *parser) arithCommand() (node, error) {
+	expr, err := p.arithmetic(p.pos + 2)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlanks()
+	if p.pos < len(p.src) && strings.IndexByte(terminators, p.src[p.pos]) < 0 {
+		return nil, fmt.Errorf("%w: unexpected text after \"))\"", ErrSyntax)
+	}
+
+	return &arithCommand{expr: expr}, nil
+}
+
+// arithmetic returns the expression starting at start up to the matching "))",
+// and moves past it. Parentheses inside the expression must balance.
+func (p *parser) arithmetic(start int) (string, error) {
+	depth := 0
+	for i := start; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if i+1 == len(p.src) {
+				return "", errIncomplete
+			}
+			if p.src[i+1] != ')' {
+				return "", fmt.Errorf("%w: expected \"))\"", ErrSyntax)
+			}
+			p.pos = i + 2
+			return p.src[start:i], nil
+		}
+	}
+
+	return "", errIncomplete
+}
+
+// redirection parses a redirection and its target word, if one comes next.
+func (p *parser) redirection() (*redirect, bool, error) {
+	r, ok := p.redirect()
+	if !ok {
+		return nil, false, nil
+	}
+	p.skipBlanks()
+	target, err := p.word()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(target) == 0 {
+		return nil, false, fmt.Errorf("%w: expected a word after %v", ErrSyntax, r.op)
+	}
+	r.target = target
+	if r.op == redirHeredoc || r.op == redirHeredocT {
+		p.pending = append(p.pending, r)
+	}
+
+	return r, true, nil
+}
+
+// heredocs reads the bodies of the pending here-documents, in order.
+func (p *parser) heredocs() error {
+	for _, r := range p.pending {
+		if err := p.heredoc(r); err != nil {
+			return err
+		}
+	}
+	p.pending = nil
+
+	return nil
+}
+
+// skipBlanks skips spaces, tabs, line continuations and a comment, which runs
+// from a # where a word could start to the end of the line.
+func (p *parser) skipBlanks() {
+	for p.pos < len(p.src) {
+		switch {
+		case p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\r':
+			p.pos++
+		case p.continuation(p.pos):
+			p.pos += 2
+			p.continued = p.pos == len(p.src)
+		case p.src[p.pos] == '#':
+			if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+				p.pos += end
+			} else {
+				p.pos = len(p.src)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// continuation reports whether a backslash-newline, which joins two lines
+// into one, starts at i.
+func (p *parser) continuation(i int) bool {
+	return strings.HasPrefix(p.src[i:], "\\\n")
+}
+
+// redirect parses a redirection operator with an optional descriptor number.
+func (p *parser) redirect() (*redirect, bool) {
+	i := p.pos
+	for i < len(p.src) && p.src[i] >= '0' && p.src[i] <= '9' {
+		i++
+	}
+	rest := p.src[i:]
+	var op string
+	for _, candidate := range []string{redirHereString, redirHeredocT, redirHeredoc, redirAppend, redirDupIn, redirDupOut, redirIn, redirOut} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" || (op == redirIn || op == redirOut) && strings.HasPrefix(rest[len(op):], "(") {
+		// <( and >( start a process substitution.
+		return nil, false
+	}
+
+	r := &redirect{fd: 1, op: op}
+	if op[0] == '<' {
+		r.fd = 0
+	}
+	if i > p.pos {
+		fd := 0
+		for _, c := range p.src[p.pos:i] {
+			fd = fd*10 + int(c-'0')
+		}
+		r.fd = fd
+	}
+	p.pos = i + len(op)
+
+	return r, true
+}
+
+// word parses a word, which ends at a blank, a newline or an operator.
+func (p *parser) word() (word, error) {
+	var w word
+	add := func(text string, q quoting) {
+		if n := len(w); n > 0 && w[n-1].quote == q && !separate(q) {
+			w[n-1].text += text
+			return
+		}
+		w = append(w, wordPart{text: text, quote: q})
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '<', '>':
+			if len(w) > 0 || !strings.HasPrefix(p.src[p.pos+1:], "(") {
+				return w, nil
+			}
+			text, err := p.substitution()
+			if err != nil {
+				return nil, err
+			}
+			if c == '<' {
+				add(text, procIn)
+			} else {
+				add(text, procOut)
+			}
+		case ' ', '\t', '\r', '\n', ';', '&', '|', '(', ')':
+			return w, nil
+		case '\'':
+			end := strings.IndexByte(p.src[p.pos+1:], '\'')
+			if end < 0 {
+				return nil, errIncomplete
+			}
+			add(p.src[p.pos+1:p.pos+1+end], singleQuoted)
+			p.pos += end + 2
+		case '"':
+			if err := p.doubleQuoted(add); err != nil {
+				return nil, err
+			}
+		case '`':
+			text, err := p.backquoted(p.pos)
+			if err != nil {
+				return nil, err
+			}
+			add(text, cmdSubst)
+		case '$':
+			if strings.HasPrefix(p.src[p.pos:], "$(") && !strings.HasPrefix(p.src[p.pos:], "$((") {
+				text, err := p.substitution()
+				if err != nil {
+					return nil, err
+				}
+				add(text, cmdSubst)
+				continue
+			}
+			if strings.HasPrefix(p.src[p.pos:], "${") {
+				param, err := p.parameter(p.pos)
+				if err != nil {
+					return nil, err
+				}
+				add(param, unquoted)
+				continue
+			}
+			if !strings.HasPrefix(p.src[p.pos:], "$((") {
+				add("$", unquoted)
+				p.pos++
+				continue
+			}
+			expr, err := p.arithmetic(p.pos + 3)
+			if err != nil {
+				return nil, err
+			}
+			add(expr, arithmetic)
+		case '\\':
+			if p.continuation(p.pos) {
+				p.pos += 2
+				if p.pos == len(p.src) {
+					return nil, errIncomplete
+				}
+				continue
+			}
+			if p.pos+1 == len(p.src) {
+				add("\\", unquoted)
+				p.pos++
+				continue
+			}
+			// An escaped character is taken literally, like a quoted one.
+			add(p.src[p.pos+1:p.pos+2], singleQuoted)
+			p.pos += 2
+		default:
+			add(p.src[p.pos:p.pos+1], unquoted)
+			p.pos++
+		}
+	}
+
+	return w, nil
+}
+
+// parameter parses the ${...} expansion starting at start, returning its
+// text. Quotes and ${...} inside it, as in ${f%"$suffix"}, don't end it.
+func (p *parser) parameter(start int) (string, error) {
+	end := paramEnd(p.src, start)
+	if end < 0 {
+		return "", errIncomplete
+	}
+	p.pos = end + 1
+
+	return p.src[start:p.pos], nil
+}
+
+// substitution parses the process substitution <( ... ) or >( ... ), or the
+// command substitution $( ... ), at p.pos, returning the source of the
+// commands inside.
+func (p *parser) substitution() (string, error) {
+	start := p.pos + 2
+	p.pos = start
+	if _, err := p.list(")"); err != nil {
+		return "", err
+	}
+	text := p.src[start:p.pos]
+	p.pos++
+
+	return text, nil
+}
+
+// backquoted parses the command substitution ` ... ` starting at the
+// backquote at start, returning the source of the commands inside. A
+// backslash inside escapes $, ` and \; elsewhere it is kept.
+func (p *parser) backquoted(start int) (string, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(p.src); i++ {
+		switch c := p.src[i]; {
+		case c == '`':
+			p.pos = i + 1
+			return b.String(), nil
+		case c == '\\' && i+1 < len(p.src) && strings.IndexByte("$`\\", p.src[i+1]) >= 0:
+			i++
+			b.WriteByte(p.src[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", errIncomplete
+}
+
+// doubleQuoted parses "..." starting at the opening quote. Backslash only
+// escapes $, `, " and \ inside double quotes, or joins two lines.
+func (p *parser) doubleQuoted(add func(string, quoting)) error {
+	// "" is an empty word rather than none.
+	add("", doubleQuoted)
+	for i := p.pos + 1; i < len(p.src); i++ {
+		switch c := p.src[i]; c {
+		case '"':
+			p.pos = i + 1
+			return nil
+		case '\\':
+			if p.continuation(i) {
+				i++
+				continue
+			}
+			if i+1 < len(p.src) && strings.IndexByte("$`\"\\", p.src[i+1]) >= 0 {
+				i++
+				// An escaped $ must not be expanded, so it is kept apart.
+				add(p.src[i:i+1], singleQuoted)
+				continue
+			}
+			add("\\", doubleQuoted)
+		case '`':
+			text, err := p.backquoted(i)
+			if err != nil {
+				return err
+			}
+			add(text, quotedCmdSubst)
+			i = p.pos - 1
+		case '$':
+			if strings.HasPrefix(p.src[i:], "$(") && !strings.HasPrefix(p.src[i:], "$((") {
+				p.pos = i
+				text, err := p.substitution()
+				if err != nil {
+					return err
+				}
+				add(text, quotedCmdSubst)
+				i = p.pos - 1
+				continue
+			}
+			if strings.HasPrefix(p.src[i:], "${") {
+				param, err := p.parameter(i)
+				if err != nil {
+					return err
+				}
+				add(param, doubleQuoted)
+				i = p.pos - 1
+				continue
+			}
+			if !strings.HasPrefix(p.src[i:], "$((") {
+				add("$", doubleQuoted)
+				continue
+			}
+			expr, err := p.arithmetic(i + 3)
+			if err != nil {
+				return err
+			}
+			// The expression is a separate part; p.pos is just past its "))".
+			add(expr, arithmetic)
+			i = p.pos - 1
+		default:
+			add(p.src[i:i+1], doubleQuoted)
+		}
+	}
+
+	return errIncomplete
+}
+
+// heredoc reads the body of a here-document, which runs until a line holding
+// only the delimiter. Quoting any part of the delimiter turns off expansion.
+func (p *parser) heredoc(r *redirect) error {
+	delim := r.target.literal()
+	r.expand = !r.target.quoted()
+	var body strings.Builder
+	for p.pos < len(p.src) {
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		line := p.src[p.pos:]
+		if end >= 0 {
+			line = p.src[p.pos : p.pos+end]
+			p.pos += end + 1
+		} else {
+			p.pos = len(p.src)
+		}
+		if r.op == redirHeredocT {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if strings.TrimSuffix(line, "\r") == delim {
+			r.body = body.String()
+			return nil
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+
+	return errIncomplete
+}
@@ -0,0 +1,309 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		src     string
+		want    []node
+		wantErr error
+	}{
+		{
+			name: "words",
+			src:  "echo  a\tb\n",
+			want: []node{&command{args: []word{{{text: "echo"}}, {{text: "a"}}, {{text: "b"}}}}},
+		},
+		{
+			name: "quotes",
+			src:  `echo 'a b'"$x \$y"\ c`,
+			want: []node{&command{args: []word{
+				{{text: "echo"}},
+				{{text: "a b", quote: singleQuoted}, {text: "$x ", quote: doubleQuoted}, {text: "$", quote: singleQuoted}, {text: "y", quote: doubleQuoted}, {text: " ", quote: singleQuoted}, {text: "c"}},
+			}}},
+		},
+		{
+			name: "redirections",
+			src:  "sort <in >>out 2>&1",
+			want: []node{&command{
+				args: []word{{{text: "sort"}}},
+				redirs: []*redirect{
+					{fd: 0, op: redirIn, target: word{{text: "in"}}},
+					{fd: 1, op: redirAppend, target: word{{text: "out"}}},
+					{fd: 2, op: redirDupOut, target: word{{text: "1"}}},
+				},
+			}},
+		},
+		{
+			name: "here-string",
+			src:  `wc -w <<<"a b"`,
+			want: []node{&command{
+				args:   []word{{{text: "wc"}}, {{text: "-w"}}},
+				redirs: []*redirect{{fd: 0, op: redirHereString, target: word{{text: "a b", quote: doubleQuoted}}}},
+			}},
+		},
+		{
+			name: "here-documents",
+			src:  "cat <<EOF\n$x\nEOF\ncat <<-'END'\n\tliteral $x\n\tEND\n",
+			want: []node{
+				&command{
+					args:   []word{{{text: "cat"}}},
+					redirs: []*redirect{{fd: 0, op: redirHeredoc, target: word{{text: "EOF"}}, body: "$x\n", expand: true}},
+				},
+				&command{
+					args:   []word{{{text: "cat"}}},
+					redirs: []*redirect{{fd: 0, op: redirHeredocT, target: word{{text: "END", quote: singleQuoted}}, body: "literal $x\n"}},
+				},
+			},
+		},
+		{
+			name: "arithmetic",
+			src:  `echo $((1 + (2))) "n=$((x*2))"; (( i < 3 ))`,
+			want: []node{
+				&command{args: []word{
+					{{text: "echo"}},
+					{{text: "1 + (2)", quote: arithmetic}},
+					{{text: "n=", quote: doubleQuoted}, {text: "x*2", quote: arithmetic}},
+				}},
+				&arithCommand{expr: " i < 3 "},
+			},
+		},
+		{
+			name: "line continuation",
+			src:  "echo a \\\n  b\\\nc \"d\\\ne\"\n",
+			want: []node{&command{args: []word{{{text: "echo"}}, {{text: "a"}}, {{text: "bc"}}, {{text: "de", quote: doubleQuoted}}}}},
+		},
+		{
+			name: "comments",
+			src:  "# it's a comment\necho a#b '#c' \\#d # e 'f\n  # indented\n",
+			want: []node{&command{args: []word{
+				{{text: "echo"}},
+				{{text: "a#b"}},
+				{{text: "#c", quote: singleQuoted}},
+				{{text: "#", quote: singleQuoted}, {text: "d"}},
+			}}},
+		},
+		{
+			name: "list",
+			src:  "cd /tmp; pwd;\n",
+			want: []node{
+				&command{args: []word{{{text: "cd"}}, {{text: "/tmp"}}}},
+				&command{args: []word{{{text: "pwd"}}}},
+			},
+		},
+		{
+			name: "groups",
+			src:  "(cd /; pwd) >out; { echo a\n}",
+			want: []node{
+				&group{
+					subshell: true,
+					body: []node{
+						&command{args: []word{{{text: "cd"}}, {{text: "/"}}}},
+						&command{args: []word{{{text: "pwd"}}}},
+					},
+					redirs: []*redirect{{fd: 1, op: redirOut, target: word{{text: "out"}}}},
+				},
+				&group{body: []node{&command{args: []word{{{text: "echo"}}, {{text: "a"}}}}}},
+			},
+		},
+		{
+			name: "here-document in a group",
+			src:  "{ cat <<EOF; }\nbody\nEOF\n",
+			want: []node{&group{body: []node{&command{
+				args:   []word{{{text: "cat"}}},
+				redirs: []*redirect{{fd: 0, op: redirHeredoc, target: word{{text: "EOF"}}, body: "body\n", expand: true}},
+			}}}},
+		},
+		{
+			name: "closing brace as an argument",
+			src:  "echo }",
+			want: []node{&command{args: []word{{{text: "echo"}}, {{text: "}"}}}}},
+		},
+		{
+			name: "case",
+			src:  "case $f in\n  (*.go|'*') echo go;;\n  *) ;;\nesac >out",
+			want: []node{&caseCommand{
+				word: word{{text: "$f"}},
+				items: []caseItem{
+					{
+						patterns: []word{{{text: "*.go"}}, {{text: "*", quote: singleQuoted}}},
+						body:     []node{&command{args: []word{{{text: "echo"}}, {{text: "go"}}}}},
+					},
+					{patterns: []word{{{text: "*"}}}},
+				},
+				redirs: []*redirect{{fd: 1, op: redirOut, target: word{{text: "out"}}}},
+			}},
+		},
+		{
+			name: "case without a final ;;",
+			src:  "case x in x) echo a; echo b; esac; echo c",
+			want: []node{
+				&caseCommand{word: word{{text: "x"}}, items: []caseItem{{
+					patterns: []word{{{text: "x"}}},
+					body:     []node{&command{args: []word{{{text: "echo"}}, {{text: "a"}}}}, &command{args: []word{{{text: "echo"}}, {{text: "b"}}}}},
+				}}},
+				&command{args: []word{{{text: "echo"}}, {{text: "c"}}}},
+			},
+		},
+		{name: "unfinished case", src: "case x in\nx) echo a;;\n", wantErr: errIncomplete},
+		{name: "case without in", src: "case x on x) ;; esac", wantErr: ErrSyntax},
+		{name: "case item without ;;", src: "case x in a) echo a\nb) echo b;; esac", wantErr: ErrSyntax},
+		{name: "stray ;;", src: "echo a;;", wantErr: ErrSyntax},
+		{
+			name: "assignments",
+			src:  "a=1 b[i+1]= c=(x \"y z\"\n w) env d=2",
+			want: []node{&command{
+				assigns: []*assignment{
+					{name: "a", value: word{{text: "1"}}},
+					{name: "b", index: "i+1", hasIndex: true},
+					{name: "c", array: []word{{{text: "x"}}, {{text: "y z", quote: doubleQuoted}}, {{text: "w"}}}, isArray: true},
+				},
+				args: []word{{{text: "env"}}, {{text: "d=2"}}},
+			}},
+		},
+		{
+			name: "parameter operations",
+			src:  `echo ${v/ /_} "${f%"x y"}" é`,
+			want: []node{&command{args: []word{
+				{{text: "echo"}},
+				{{text: "${v/ /_}"}},
+				{{text: `${f%"x y"}`, quote: doubleQuoted}},
+				{{text: "é"}},
+			}}},
+		},
+		{name: "unclosed parameter", src: "echo ${v", wantErr: errIncomplete},
+		{name: "empty string", src: `echo ""`, want: []node{&command{args: []word{{{text: "echo"}}, {{text: "", quote: doubleQuoted}}}}}},
+		{
+			name: "for loop",
+			src:  "for x in a \"$b\"\ndo\n  echo $x\ndone >out",
+			want: []node{&forLoop{
+				name:   "x",
+				words:  []word{{{text: "a"}}, {{text: "$b", quote: doubleQuoted}}},
+				body:   []node{&command{args: []word{{{text: "echo"}}, {{text: "$x"}}}}},
+				redirs: []*redirect{{fd: 1, op: redirOut, target: word{{text: "out"}}}},
+			}},
+		},
+		{name: "unfinished for loop", src: "for x in a; do echo $x\n", wantErr: errIncomplete},
+		{name: "for loop without in", src: "for x; do echo; done", wantErr: ErrSyntax},
+		{name: "empty for loop", src: "for x in a; do done", wantErr: ErrSyntax},
+		{
+			name: "functions",
+			src:  "f() { echo $1; }\nfunction g\n(pwd)",
+			want: []node{
+				&funcDef{name: "f", body: &group{body: []node{&command{args: []word{{{text: "echo"}}, {{text: "$1"}}}}}}, src: "f() { echo $1; }"},
+				&funcDef{name: "g", body: &group{body: []node{&command{args: []word{{{text: "pwd"}}}}}, subshell: true}, src: "function g\n(pwd)"},
+			},
+		},
+		{name: "function without a body", src: "f() echo", wantErr: ErrSyntax},
+		{name: "unfinished function", src: "f()\n", wantErr: errIncomplete},
+		{name: "unclosed array", src: "a=(x y", wantErr: errIncomplete},
+		{
+			name: "process substitution",
+			src:  "diff <(sort a) >(tee 'x)') < <(echo)",
+			want: []node{&command{
+				args:   []word{{{text: "diff"}}, {{text: "sort a", quote: procIn}}, {{text: "tee 'x)'", quote: procOut}}},
+				redirs: []*redirect{{fd: 0, op: redirIn, target: word{{text: "echo", quote: procIn}}}},
+			}},
+		},
+		{name: "unclosed process substitution", src: "cat <(echo a", wantErr: errIncomplete},
+		{
+			name: "command substitution",
+			src:  "echo x$(echo ')') \"$(pwd)\"`echo \\`a\\``",
+			want: []node{&command{args: []word{
+				{{text: "echo"}},
+				{{text: "x"}, {text: "echo ')'", quote: cmdSubst}},
+				{{text: "", quote: doubleQuoted}, {text: "pwd", quote: quotedCmdSubst}, {text: "echo `a`", quote: cmdSubst}},
+			}}},
+		},
+		{name: "unclosed command substitution", src: "echo $(echo a", wantErr: errIncomplete},
+		{name: "unclosed backquote", src: "echo `echo a", wantErr: errIncomplete},
+		{
+			name: "pipeline",
+			src:  "{ echo a; }|sort -r |\n wc -l &",
+			want: []node{&background{
+				node: &pipeline{stages: []node{
+					&group{body: []node{&command{args: []word{{{text: "echo"}}, {{text: "a"}}}}}},
+					&command{args: []word{{{text: "sort"}}, {{text: "-r"}}}},
+					&command{args: []word{{{text: "wc"}}, {{text: "-l"}}}},
+				}},
+				text: "{ echo a; }|sort -r |\n wc -l",
+			}},
+		},
+		{name: "unfinished pipeline", src: "echo a |\n", wantErr: errIncomplete},
+		{name: "empty pipeline stage", src: "echo a | | wc", wantErr: ErrSyntax},
+		{name: "pipeline without a command", src: "| wc", wantErr: ErrSyntax},
+		{
+			name: "and-or list",
+			src:  "a && b |\n c ||\n d &",
+			want: []node{&background{
+				node: &andOr{
+					left: &andOr{
+						left:  &command{args: []word{{{text: "a"}}}},
+						right: &pipeline{stages: []node{&command{args: []word{{{text: "b"}}}}, &command{args: []word{{{text: "c"}}}}}},
+					},
+					right: &command{args: []word{{{text: "d"}}}},
+					or:    true,
+				},
+				text: "a && b |\n c ||\n d",
+			}},
+		},
+		{name: "unfinished and-or list", src: "a &&\n", wantErr: errIncomplete},
+		{name: "and-or list without a command", src: "a || ; b", wantErr: ErrSyntax},
+		{
+			name: "if",
+			src:  "if a; then b; elif c\nthen d; else e; fi >out",
+			want: []node{&ifCommand{
+				clauses: []ifClause{
+					{cond: []node{&command{args: []word{{{text: "a"}}}}}, body: []node{&command{args: []word{{{text: "b"}}}}}},
+					{cond: []node{&command{args: []word{{{text: "c"}}}}}, body: []node{&command{args: []word{{{text: "d"}}}}}},
+				},
+				elseBody: []node{&command{args: []word{{{text: "e"}}}}},
+				redirs:   []*redirect{{fd: 1, op: redirOut, target: word{{text: "out"}}}},
+			}},
+		},
+		{name: "unfinished if", src: "if a; then b\n", wantErr: errIncomplete},
+		{name: "empty then list", src: "if a; then fi", wantErr: ErrSyntax},
+		{name: "if without a condition", src: "if then b; fi", wantErr: ErrSyntax},
+		{
+			name: "while and until",
+			src:  "while a; do b; done; until c; do d; done",
+			want: []node{
+				&whileLoop{cond: []node{&command{args: []word{{{text: "a"}}}}}, body: []node{&command{args: []word{{{text: "b"}}}}}},
+				&whileLoop{cond: []node{&command{args: []word{{{text: "c"}}}}}, body: []node{&command{args: []word{{{text: "d"}}}}}, until: true},
+			},
+		},
+		{name: "empty while loop", src: "while a; do done", wantErr: ErrSyntax},
+		{name: "stray reserved word", src: "echo a; fi", wantErr: ErrSyntax},
+		{name: "unclosed subshell", src: "(echo a\n", wantErr: errIncomplete},
+		{name: "unclosed brace group", src: "{ echo a; ", wantErr: errIncomplete},
+		{name: "unexpected paren", src: "echo a)", wantErr: ErrSyntax},
+		{name: "empty group", src: "( )", wantErr: ErrSyntax},
+		{name: "empty command", src: "; echo", wantErr: ErrSyntax},
+		{name: "unterminated quote", src: "echo 'abc\n", wantErr: errIncomplete},
+		{name: "unterminated here-document", src: "cat <<EOF\nline\n", wantErr: errIncomplete},
+		{name: "missing target", src: "echo >\n", wantErr: ErrSyntax},
+		{name: "unclosed arithmetic", src: "echo $((1 +", wantErr: errIncomplete},
+		{name: "continued line", src: "echo a \\\n", wantErr: errIncomplete},
+		{name: "continued word", src: "echo a\\\n", wantErr: errIncomplete},
+		{name: "comment hides closing brace", src: "{ echo a # }\n", wantErr: errIncomplete},
+		{name: "comment instead of target", src: "echo > # out\n", wantErr: ErrSyntax},
+		{name: "blank lines", src: "\n  \n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parse(tt.src)
+			require.True(t, errors.Is(err, tt.wantErr), "parse() error = %v, wantErr %v", err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
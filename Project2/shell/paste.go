@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Bracketed paste: while the mode is on, the terminal marks pasted text with
+// pasteStart and pasteEnd, so that it can be told apart from typing.
+const (
+	pasteOn    = "\x1b[?2004h"
+	pasteOff   = "\x1b[?2004l"
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// setBracketedPaste turns bracketed paste on or off, when the shell reads
+// from and writes to a terminal. It is on only while a command is being read,
+// so that programs the shell runs get pastes unmarked.
+func (s *Shell) setBracketedPaste(on bool) {
+	if s.in.file == nil || !isTerminal(s.in.file) || !isTerminal(s.Stdout) {
+		return
+	}
+	if on {
+		_, _ = io.WriteString(s.Stdout, pasteOn)
+	} else {
+		_, _ = io.WriteString(s.Stdout, pasteOff)
+	}
+}
+
+// readPaste reads the rest of a paste that text starts and returns it
+// without the markers. A paste of more than one line is shown, and only kept
+// if the user confirms it; otherwise nothing is returned.
+func (s *Shell) readPaste(text string) (string, error) {
+	var err error
+	for err == nil && !strings.Contains(text, pasteEnd) {
+		var more string
+		more, err = s.in.ReadString('\n')
+		text += more
+	}
+	text = strings.NewReplacer(pasteStart, "", pasteEnd, "").Replace(text)
+	if err != nil {
+		return text, err
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) < 2 {
+		return text, nil
+	}
+	_, _ = fmt.Fprintf(s.Stderr, "pasted %d lines:\n", len(lines))
+	for _, line := range lines {
+		_, _ = fmt.Fprintf(s.Stderr, "  %v\n", line)
+	}
+	_, _ = fmt.Fprint(s.Stderr, "run them? [y/N] ")
+	answer, err := s.in.ReadString('\n')
+	if a := strings.TrimSpace(answer); a == "y" || a == "Y" || a == "yes" {
+		return text, nil
+	}
+
+	return "", err
+}
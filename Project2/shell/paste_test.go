@@ -0,0 +1,54 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_readCommand_paste(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		wantErrW string
+	}{
+		{
+			name:  "one line",
+			input: pasteStart + "echo hi" + pasteEnd + "\n",
+			want:  "echo hi\n",
+		},
+		{
+			name:     "confirmed",
+			input:    pasteStart + "echo a\necho b\n" + pasteEnd + "\ny\n",
+			want:     "echo a\necho b\n\n",
+			wantErrW: "pasted 2 lines:\n  echo a\n  echo b\nrun them? [y/N] ",
+		},
+		{
+			name:     "declined",
+			input:    pasteStart + "echo a\nrm -rf x" + pasteEnd + "\n\n",
+			want:     "",
+			wantErrW: "pasted 2 lines:\n  echo a\n  rm -rf x\nrun them? [y/N] ",
+		},
+		{
+			name:  "open quote continues after the paste",
+			input: pasteStart + "echo 'a" + pasteEnd + "\nb'\n",
+			want:  "echo 'a\nb'\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			errW := &bytes.Buffer{}
+			sh := New(strings.NewReader(tt.input), &bytes.Buffer{}, errW)
+			got, err := sh.readCommand()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantErrW, errW.String())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// commandIndex lists the executables in PATH for completion, highlighting
+// and command-not-found suggestions, so that they don't read every directory
+// on each keystroke. Like the command hash, it belongs to the process.
+var commandIndex = &pathIndex{}
+
+// pathIndex is the names of the executables in the directories of PATH. It
+// is refreshed in the background, rereading only the directories modified
+// since they were last read, and read again at once when PATH changes.
+type pathIndex struct {
+	mu       sync.Mutex
+	pathEnv  string                // PATH when names was listed
+	dirs     map[string]indexedDir // the directories read, by path
+	names    map[string]bool       // not changed once listed
+	updating bool
+}
+
+// indexedDir is the executables in a directory as of its modification time.
+type indexedDir struct {
+	modTime time.Time
+	names   []string
+}
+
+// refresh starts updating the index in the background, unless an update is
+// already running.
+func (x *pathIndex) refresh() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.updating {
+		return
+	}
+	x.updating = true
+	pathEnv, dirs := os.Getenv("PATH"), x.dirs
+	go func() {
+		dirs, names := scanPath(pathEnv, dirs)
+		x.mu.Lock()
+		defer x.mu.Unlock()
+		x.updating = false
+		// PATH may have changed while the directories were read.
+		if pathEnv == os.Getenv("PATH") {
+			x.pathEnv, x.dirs, x.names = pathEnv, dirs, names
+		}
+	}()
+}
+
+// current returns the executables in PATH, reading the directories now if
+// PATH has changed since they were last read.
+func (x *pathIndex) current() map[string]bool {
+	pathEnv := os.Getenv("PATH")
+	x.mu.Lock()
+	if x.names != nil && x.pathEnv == pathEnv {
+		defer x.mu.Unlock()
+		return x.names
+	}
+	dirs := x.dirs
+	x.mu.Unlock()
+
+	dirs, names := scanPath(pathEnv, dirs)
+	x.mu.Lock()
+	x.pathEnv, x.dirs, x.names = pathEnv, dirs, names
+	x.mu.Unlock()
+
+	return names
+}
+
+// has reports whether name is an executable in PATH.
+func (x *pathIndex) has(name string) bool {
+	return x.current()[name]
+}
+
+// list returns the executables in PATH in sorted order.
+func (x *pathIndex) list() []string {
+	names := x.current()
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// scanPath lists the executables in the directories of pathEnv, reusing the
+// listings in old of directories not modified since.
+func scanPath(pathEnv string, old map[string]indexedDir) (map[string]indexedDir, map[string]bool) {
+	dirs := map[string]indexedDir{}
+	names := map[string]bool{}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = "."
+		}
+		if _, done := dirs[dir]; done {
+			continue
+		}
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		d, ok := old[dir]
+		if !ok || !d.modTime.Equal(info.ModTime()) {
+			d = indexedDir{modTime: info.ModTime(), names: executables(dir)}
+		}
+		dirs[dir] = d
+		for _, name := range d.names {
+			names[name] = true
+		}
+	}
+
+	return dirs, names
+}
+
+// executables returns the names of the executable files in dir.
+func executables(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			// The mode of a link is that of its target.
+			info, err = os.Stat(filepath.Join(dir, e.Name()))
+		}
+		if err == nil && builtins.IsExecutable(e.Name(), info) {
+			names = append(names, builtins.CommandName(e.Name()))
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,37 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel: the index reads PATH, which the test sets.
+func Test_pathIndex(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(a, "tool"), nil, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(a, "notes"), nil, 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(a, "subdir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(b, "other"), nil, 0o755))
+	require.NoError(t, os.Symlink(filepath.Join(a, "tool"), filepath.Join(b, "link")))
+	require.NoError(t, os.Symlink(filepath.Join(a, "missing"), filepath.Join(b, "broken")))
+	t.Setenv("PATH", a)
+
+	x := &pathIndex{}
+	require.Equal(t, []string{"tool"}, x.list())
+	require.True(t, x.has("tool"))
+	require.False(t, x.has("notes"))
+
+	t.Setenv("PATH", a+string(os.PathListSeparator)+b)
+	require.Equal(t, []string{"link", "other", "tool"}, x.list(), "a new PATH is read at once")
+
+	require.NoError(t, os.WriteFile(filepath.Join(a, "new"), nil, 0o755))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(a, later, later))
+	require.False(t, x.has("new"), "the index is only refreshed in the background")
+	x.refresh()
+	require.Eventually(t, func() bool { return x.has("new") }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,149 @@
+package shell
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// matchPattern reports whether s matches the shell pattern: * matches any
+// string, including one with slashes, ? any one character, [abc], [a-z] and
+// [!abc] (or [^abc]) one character of a set, and a backslash makes the next
+// character literal. A [ without a closing ] is literal.
+func matchPattern(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(s); {
+				if matchPattern(pattern, s[i:]) {
+					return true
+				}
+				if i == len(s) {
+					break
+				}
+				_, n := utf8.DecodeRuneInString(s[i:])
+				i += n
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+			_, n := utf8.DecodeRuneInString(s)
+			pattern, s = pattern[1:], s[n:]
+		case '[':
+			if s == "" {
+				return false
+			}
+			r, n := utf8.DecodeRuneInString(s)
+			matched, rest, ok := matchClass(pattern, r)
+			if !ok {
+				// An unclosed [ is an ordinary character.
+				if s[0] != '[' {
+					return false
+				}
+				pattern, s = pattern[1:], s[1:]
+				continue
+			}
+			if !matched {
+				return false
+			}
+			pattern, s = rest, s[n:]
+		default:
+			if pattern[0] == '\\' && len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			pr, pn := utf8.DecodeRuneInString(pattern)
+			r, n := utf8.DecodeRuneInString(s)
+			if s == "" || pr != r {
+				return false
+			}
+			pattern, s = pattern[pn:], s[n:]
+		}
+	}
+
+	return s == ""
+}
+
+// matchClass matches r against the bracket expression at the start of
+// pattern, returning the pattern after it. ok is false if the bracket is not
+// closed.
+func matchClass(pattern string, r rune) (matched bool, rest string, ok bool) {
+	i := 1
+	negate := i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^')
+	if negate {
+		i++
+	}
+	for first := true; i < len(pattern); first = false {
+		if pattern[i] == ']' && !first {
+			return matched != negate, pattern[i+1:], true
+		}
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+		}
+		lo, n := utf8.DecodeRuneInString(pattern[i:])
+		i += n
+		hi := lo
+		if i+1 < len(pattern) && pattern[i] == '-' && pattern[i+1] != ']' {
+			i++
+			if pattern[i] == '\\' && i+1 < len(pattern) {
+				i++
+			}
+			hi, n = utf8.DecodeRuneInString(pattern[i:])
+			i += n
+		}
+		if lo <= r && r <= hi {
+			matched = true
+		}
+	}
+
+	return false, "", false
+}
+
+// patternWord expands w into a pattern in which the quoted parts, and the
+// expanded values in them, only match themselves.
+func (s *Shell) patternWord(w word) (string, error) {
+	var b strings.Builder
+	for _, part := range s.tildeExpand(w) {
+		text := part.text
+		var err error
+		switch part.quote {
+		case unquoted:
+			text, err = s.expand(text)
+		case doubleQuoted:
+			text, err = s.expand(text)
+			text = escapePattern(text)
+		case arithmetic, cmdSubst:
+			text, err = s.expandWord(word{part})
+		case quotedCmdSubst:
+			text, err = s.expandWord(word{part})
+			text = escapePattern(text)
+		default:
+			text = escapePattern(text)
+		}
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text)
+	}
+
+	return b.String(), nil
+}
+
+// escapePattern puts a backslash before the characters special in patterns.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(`*?[\`, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package shell
+
+import (
+	"testing"
+)
+
+func Test_matchPattern(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{pattern: "abc", s: "abc", want: true},
+		{pattern: "abc", s: "abd", want: false},
+		{pattern: "*", s: "", want: true},
+		{pattern: "*.go", s: "dir/main.go", want: true},
+		{pattern: "a*b*c", s: "axxbyyc", want: true},
+		{pattern: "a*b*c", s: "axxbyy", want: false},
+		{pattern: "?", s: "é", want: true},
+		{pattern: "??", s: "a", want: false},
+		{pattern: "[abc]x", s: "bx", want: true},
+		{pattern: "[a-c]", s: "d", want: false},
+		{pattern: "[!a-c]", s: "d", want: true},
+		{pattern: "[^a]", s: "a", want: false},
+		{pattern: "[]]", s: "]", want: true},
+		{pattern: "[a-]", s: "-", want: true},
+		{pattern: "[ab", s: "[ab", want: true},
+		{pattern: `\*`, s: "*", want: true},
+		{pattern: `\*`, s: "a", want: false},
+		{pattern: `[\]]`, s: "]", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
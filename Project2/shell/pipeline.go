@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// runPipeline runs the stages of a pipeline at the same time, each writing
+// to a pipe the next reads from, and returns the error of the last stage.
+// A pipe holds little, so a stage writing faster than the next reads waits
+// for it, and once a stage is done, writing to its input fails with a broken
+// pipe: as with SIGPIPE, the stage before stops quietly, so "yes | head -1"
+// ends. The stages run inside the shell in parallel, like background jobs,
+// and exit only ends its own stage. Errors of the other stages are reported
+// once all are done. Explain mode shows the stages one after the other.
+func (s *Shell) runPipeline(ctx *builtins.Context, p *pipeline) error {
+	if s.opts.enabled(optExplain) {
+		for _, n := range p.stages {
+			if err := s.runNode(ctx, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	stages := make([]*builtins.Context, len(p.stages))
+	files := make([][]*os.File, len(p.stages)) // the pipe ends each stage closes
+	closeAll := func() {
+		for _, fs := range files {
+			for _, f := range fs {
+				_ = f.Close()
+			}
+		}
+	}
+	for i := range stages {
+		c := *ctx
+		c.Exit = func(int) {}
+		stages[i] = &c
+		if i == 0 {
+			continue
+		}
+		r, w, err := os.Pipe()
+		if err != nil {
+			closeAll()
+			return err
+		}
+		stages[i-1].Stdout = w
+		stages[i].Stdin = r
+		files[i-1] = append(files[i-1], w)
+		files[i] = append(files[i], r)
+	}
+
+	errs := make([]error, len(p.stages))
+	var wg sync.WaitGroup
+	for i, n := range p.stages {
+		wg.Add(1)
+		go func(i int, n node) {
+			defer wg.Done()
+			errs[i] = s.runNode(stages[i], n)
+			// The next stage sees the end of its input, and the one before
+			// a broken pipe.
+			for _, f := range files[i] {
+				_ = f.Close()
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	last := len(errs) - 1
+	for _, err := range errs[:last] {
+		if err != nil && !builtins.IsStatusOnly(err) {
+			_, _ = fmt.Fprintln(ctx.Stderr, err)
+		}
+	}
+
+	return errs[last]
+}
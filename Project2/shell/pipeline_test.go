@@ -0,0 +1,50 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_runPipeline(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		line       string
+		wantOut    string
+		wantStatus int
+	}{
+		{name: "builtins", line: "echo b a | tr ' ' '\\n' | sort", wantOut: "a\nb\n"},
+		{name: "external stage", line: "echo hello | sh -c 'tr a-z A-Z' | cat", wantOut: "HELLO\n"},
+		{name: "status of the last stage", line: "echo a | false", wantStatus: 1},
+		{name: "earlier failure ignored", line: "false | echo a", wantOut: "a\n"},
+		{name: "builtin stops at a broken pipe", line: "seq 1000000000 | head -n 2", wantOut: "1\n2\n"},
+		{name: "external stops at a broken pipe", line: "sh -c 'while :; do echo y; done' | head -n 1", wantOut: "y\n"},
+		{name: "reader stops before the writer", line: "yes | true"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			sh := New(strings.NewReader(""), &out, io.Discard)
+			done := make(chan int, 1)
+			go func() {
+				status, _ := sh.RunLine(tt.line)
+				done <- status
+			}()
+			select {
+			case status := <-done:
+				require.Equal(t, tt.wantStatus, status)
+				require.Equal(t, tt.wantOut, out.String())
+			case <-time.After(10 * time.Second):
+				sh.Shutdown()
+				t.Fatalf("%q did not end", tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/mattn/go-runewidth"
+)
+
+// ANSI colors for the prompt and the input line.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGrey    = "\x1b[90m"
+)
+
+const (
+	// defaultDirTrim is how many trailing directories the prompt shows when
+	// $PROMPT_DIRTRIM is unset.
+	defaultDirTrim = 4
+	// gitWait is how long the prompt waits for a fresh git status before
+	// showing the last one known.
+	gitWait = 30 * time.Millisecond
+	// gitTimeout bounds a git status run in the background.
+	gitTimeout = 5 * time.Second
+)
+
+// printPrompt writes the prompt, e.g. "/home/me/src [me] (main*) $ ": the
+// working directory, the user, and the git branch with * if the work tree has
+// changes. The directory is shortened to take at most half the terminal's
+// width. The $ is red when the last command failed. Colors are only used on
+// a terminal, and not if $NO_COLOR is set.
+func (s *Shell) printPrompt(w io.Writer) error {
+	// Don't prematurely memoize the user because it might change due to `su`?
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	cols, _ := builtins.TerminalSize(w)
+	p := promptSegments{
+		dir:    fitPath(wd, s.dirTrim(), cols/2),
+		user:   u.Username,
+		failed: s.Status() != 0,
+		color:  isTerminal(w) && os.Getenv("NO_COLOR") == "",
+	}
+	if root, branch, ok := gitBranch(wd); ok {
+		p.branch = branch
+		p.dirty = s.git.dirty(root)
+	}
+	_, err = io.WriteString(w, p.String())
+
+	return err
+}
+
+// dirTrim returns how many trailing directories the prompt shows, from
+// $PROMPT_DIRTRIM; 0 shows the whole path.
+func (s *Shell) dirTrim() int {
+	v, ok := s.vars.Get("PROMPT_DIRTRIM")
+	if !ok {
+		return defaultDirTrim
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return defaultDirTrim
+	}
+
+	return n
+}
+
+// promptSegments are the parts of the prompt.
+type promptSegments struct {
+	dir, user, branch string
+	dirty, failed     bool
+	color             bool
+}
+
+func (p promptSegments) String() string {
+	paint := func(color, text string) string {
+		if !p.color {
+			return text
+		}
+		return color + text + colorReset
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v [%v] ", paint(colorBlue, p.dir), p.user)
+	if p.branch != "" {
+		branch := p.branch
+		if p.dirty {
+			branch += "*"
+		}
+		fmt.Fprintf(&b, "(%v) ", paint(colorYellow, branch))
+	}
+	if p.failed {
+		b.WriteString(paint(colorRed, "$"))
+	} else {
+		b.WriteString("$")
+	}
+	b.WriteString(" ")
+
+	return b.String()
+}
+
+// trimPath shortens a path to its last n elements after ".../"; n <= 0 or a
+// path that is short enough is left alone.
+func trimPath(path string, n int) string {
+	if n <= 0 {
+		return path
+	}
+	elems := strings.Split(strings.Trim(filepath.ToSlash(path), "/"), "/")
+	if len(elems) <= n {
+		return path
+	}
+
+	return ".../" + strings.Join(elems[len(elems)-n:], "/")
+}
+
+// fitPath trims path like trimPath, and then to fewer elements, down to one,
+// until it takes at most width columns.
+func fitPath(path string, n, width int) string {
+	if n <= 0 {
+		n = strings.Count(strings.Trim(filepath.ToSlash(path), "/"), "/") + 1
+	}
+	dir := trimPath(path, n)
+	for n > 1 && runewidth.StringWidth(dir) > width {
+		n--
+		dir = trimPath(path, n)
+	}
+
+	return dir
+}
+
+// gitBranch finds the git repository containing dir and returns its work
+// tree root and current branch, or the short commit ID of a detached HEAD.
+// It reads .git/HEAD itself, which is quick enough to do for every prompt.
+func gitBranch(dir string) (root, branch string, ok bool) {
+	for root = dir; ; {
+		gitDir := filepath.Join(root, ".git")
+		if fi, err := os.Stat(gitDir); err == nil {
+			if !fi.IsDir() {
+				// A worktree or submodule: .git names the real directory.
+				data, err := os.ReadFile(gitDir)
+				if err != nil {
+					return "", "", false
+				}
+				gitDir = strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(root, gitDir)
+				}
+			}
+			head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+			if err != nil {
+				return "", "", false
+			}
+			ref := strings.TrimSpace(string(head))
+			if name := strings.TrimPrefix(ref, "ref: refs/heads/"); name != ref {
+				return root, name, true
+			}
+			if len(ref) > 7 {
+				ref = ref[:7]
+			}
+			return root, ref, ref != ""
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return "", "", false
+		}
+		root = parent
+	}
+}
+
+// gitStatus runs git status in the background, so that a slow repository
+// never holds up the prompt, and remembers the last answer for each one.
+type gitStatus struct {
+	mu      sync.Mutex
+	changed map[string]bool          // by work tree root
+	running map[string]chan struct{} // closed when a refresh finishes
+}
+
+func newGitStatus() *gitStatus {
+	return &gitStatus{changed: map[string]bool{}, running: map[string]chan struct{}{}}
+}
+
+// dirty reports whether the work tree at root has uncommitted changes to
+// tracked files. It starts a refresh unless one is running and waits for it
+// briefly; if that takes too long it returns the last known answer.
+func (g *gitStatus) dirty(root string) bool {
+	g.mu.Lock()
+	done, ok := g.running[root]
+	if !ok {
+		done = make(chan struct{})
+		g.running[root] = done
+		go func() {
+			changed := gitChanged(root)
+			g.mu.Lock()
+			g.changed[root] = changed
+			delete(g.running, root)
+			g.mu.Unlock()
+			close(done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(gitWait):
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.changed[root]
+}
+
+// gitChanged runs git status on the work tree at root. Errors, including a
+// missing git, count as no changes.
+func gitChanged(root string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", root, "status", "--porcelain", "--untracked-files=no").Output()
+
+	return err == nil && len(out) > 0
+}
@@ -0,0 +1,96 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_trimPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		n    int
+		want string
+	}{
+		{path: "/home/me/src", n: 4, want: "/home/me/src"},
+		{path: "/a/b/c/d/e/f", n: 4, want: ".../c/d/e/f"},
+		{path: "/a/b/c/d/e/f", n: 1, want: ".../f"},
+		{path: "/a/b/c/d/e/f", n: 0, want: "/a/b/c/d/e/f"},
+		{path: "/", n: 2, want: "/"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, trimPath(tt.path, tt.n), tt.path)
+	}
+}
+
+func Test_fitPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path  string
+		n     int
+		width int
+		want  string
+	}{
+		{path: "/home/me/src", n: 4, width: 40, want: "/home/me/src"},
+		{path: "/home/me/src", n: 4, width: 10, want: ".../me/src"},
+		{path: "/a/b/c/d/e/f", n: 0, width: 12, want: "/a/b/c/d/e/f"},
+		{path: "/a/b/c/d/e/f", n: 0, width: 11, want: ".../c/d/e/f"},
+		{path: "/a/b/c/d/e/f", n: 2, width: 1, want: ".../f"},
+		{path: "/home/文書/写真", n: 0, width: 15, want: "/home/文書/写真"},
+		{path: "/home/文書/写真", n: 0, width: 14, want: ".../文書/写真"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, fitPath(tt.path, tt.n, tt.width), "%v in %d", tt.path, tt.width)
+	}
+}
+
+func Test_promptSegments(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		p    promptSegments
+		want string
+	}{
+		{name: "plain", p: promptSegments{dir: "/tmp", user: "me"}, want: "/tmp [me] $ "},
+		{name: "git", p: promptSegments{dir: "/src", user: "me", branch: "main", dirty: true}, want: "/src [me] (main*) $ "},
+		{
+			name: "colored failure",
+			p:    promptSegments{dir: "/src", user: "me", branch: "main", failed: true, color: true},
+			want: "\x1b[34m/src\x1b[0m [me] (\x1b[33mmain\x1b[0m) \x1b[31m$\x1b[0m ",
+		},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.p.String(), tt.name)
+	}
+}
+
+func Test_gitBranch(t *testing.T) {
+	t.Parallel()
+	repo, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	sub := filepath.Join(repo, "a", "b")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o755))
+	head := filepath.Join(repo, ".git", "HEAD")
+
+	require.NoError(t, os.WriteFile(head, []byte("ref: refs/heads/feature/x\n"), 0o644))
+	root, branch, ok := gitBranch(sub)
+	require.True(t, ok)
+	require.Equal(t, repo, root)
+	require.Equal(t, "feature/x", branch)
+
+	require.NoError(t, os.WriteFile(head, []byte("0123456789abcdef\n"), 0o644))
+	_, branch, ok = gitBranch(repo)
+	require.True(t, ok)
+	require.Equal(t, "0123456", branch, "a detached HEAD shows the short commit ID")
+
+	worktree := filepath.Join(repo, "a", "wt")
+	require.NoError(t, os.Mkdir(worktree, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../../.git\n"), 0o644))
+	root, _, ok = gitBranch(worktree)
+	require.True(t, ok)
+	require.Equal(t, worktree, root)
+}
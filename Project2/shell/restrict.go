@@ -0,0 +1,125 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// ErrRestricted is returned for what a restricted shell doesn't allow.
+var ErrRestricted = errors.New("restricted")
+
+// restrictedBuiltins can't be run in a restricted shell: they change the
+// working directory, replace the shell, write, remove or link files of their
+// own, which would get around the ban on output redirection, or start a
+// program without the checks below.
+var restrictedBuiltins = map[string]bool{
+	"cd":      true,
+	"chmod":   true,
+	"cp":      true,
+	"exec":    true,
+	"fetch":   true,
+	"goshenv": true,
+	"gunzip":  true,
+	"gzip":    true,
+	"ln":      true,
+	"mkdir":   true,
+	"mv":      true,
+	"nohup":   true, // writes nohup.out
+	"popd":    true,
+	"pushd":   true,
+	"rm":      true,
+	"rmdir":   true,
+	"session": true,
+	"tar":     true,
+	"tee":     true,
+	"timeout": true,
+	"touch":   true,
+}
+
+// restrictedVars can't be assigned in a restricted shell, as they decide
+// which programs run and how, or which file the shell writes on exit.
+var restrictedVars = map[string]bool{
+	"ENV":      true,
+	"HISTFILE": true,
+	"PATH":     true,
+	"SHELL":    true,
+}
+
+// Restrict turns on restricted mode (set -r), as for "gosh -r". A restricted
+// shell can't change directory, assign PATH, ENV, SHELL or HISTFILE, run
+// commands named with a slash, source files named with a slash, redirect
+// output to files or run the builtins that write files, such as cp and tee,
+// and it neither loads nor saves its history. It can't be
+// turned off again.
+func (s *Shell) Restrict() {
+	_ = s.opts.SetOption(optRestricted, true)
+}
+
+// AllowOnly restricts the shell, as Restrict does, and lets it run only the
+// named commands, as for a shell served to a browser. Functions can still be
+// called, but each command they run is checked as well, and so is each
+// command a builtin such as time, nice or xargs runs.
+func (s *Shell) AllowOnly(names ...string) {
+	s.Restrict()
+	s.opts.allowOnly(names)
+}
+
+// restricted reports whether ctx belongs to a restricted shell.
+func restricted(ctx *builtins.Context) bool {
+	if ctx.Options == nil {
+		return false
+	}
+	on, _ := ctx.Options.Option(optRestricted)
+
+	return on
+}
+
+// checkCommand returns an error if a restricted shell may not run the command.
+// Every command goes through it, including those run by builtins such as
+// xargs and nohup.
+func checkCommand(ctx *builtins.Context, name string, args []string) error {
+	if !restricted(ctx) {
+		return nil
+	}
+	switch {
+	case !allows(ctx, name):
+		return fmt.Errorf("%v: %w: not an allowed command", name, ErrRestricted)
+	case restrictedBuiltins[name]:
+		return fmt.Errorf("%v: %w", name, ErrRestricted)
+	case builtins.IsPath(name):
+		return fmt.Errorf("%v: %w: cannot specify '/' in command names", name, ErrRestricted)
+	case (name == "source" || name == ".") && len(args) > 0 && builtins.IsPath(args[0]):
+		return fmt.Errorf("%v: %v: %w", name, args[0], ErrRestricted)
+	}
+
+	return nil
+}
+
+// allows reports whether the shell of ctx may run the command name, if it
+// only runs some commands.
+func allows(ctx *builtins.Context, name string) bool {
+	o, ok := ctx.Options.(*options)
+
+	return !ok || o.allows(name)
+}
+
+// checkAssignment returns an error if a restricted shell may not assign the
+// named variable.
+func (s *Shell) checkAssignment(name string) error {
+	if restrictedVars[name] && s.opts.enabled(optRestricted) {
+		return fmt.Errorf("%v: %w: readonly variable", name, ErrRestricted)
+	}
+
+	return nil
+}
+
+// checkRedirect returns an error if a restricted shell may not apply r.
+func (s *Shell) checkRedirect(r *redirect, target string) error {
+	if (r.op == redirOut || r.op == redirAppend) && s.opts.enabled(optRestricted) {
+		return fmt.Errorf("%v: %w: cannot redirect output", target, ErrRestricted)
+	}
+
+	return nil
+}
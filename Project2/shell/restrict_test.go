@@ -0,0 +1,168 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Restrict(t *testing.T) {
+	t.Parallel()
+	out := filepath.Join(t.TempDir(), "out")
+	tests := []struct {
+		name       string
+		line       string
+		wantOut    string
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "cd", line: "cd /", wantStatus: 1, wantErr: true},
+		{name: "pushd", line: "pushd /", wantStatus: 1, wantErr: true},
+		{name: "exec", line: "exec echo hi", wantStatus: 1, wantErr: true},
+		{name: "slash in command name", line: "/bin/echo hi", wantStatus: 1, wantErr: true},
+		{name: "slash through xargs", line: "xargs /bin/echo <<< hi", wantStatus: 1, wantErr: true},
+		{name: "slash through nohup", line: "nohup ./script", wantStatus: 1, wantErr: true},
+		{name: "time /bin/sh", line: "time /bin/sh -c 'echo ESCAPED'", wantStatus: 1, wantErr: true},
+		{name: "slash through time", line: "time ./script", wantStatus: 1, wantErr: true},
+		{name: "source with slash", line: "source ./script", wantStatus: 1, wantErr: true},
+		{name: "output redirection", line: "echo hi > " + out, wantStatus: 1, wantErr: true},
+		{name: "append redirection", line: "echo hi >> " + out, wantStatus: 1, wantErr: true},
+		{name: "tee", line: "tee " + out + " <<< x", wantStatus: 1, wantErr: true},
+		{name: "cp", line: "cp /dev/null " + out, wantStatus: 1, wantErr: true},
+		{name: "mv", line: "mv /dev/null " + out, wantStatus: 1, wantErr: true},
+		{name: "rm", line: "rm -f " + out, wantStatus: 1, wantErr: true},
+		{name: "rmdir", line: "rmdir " + out, wantStatus: 1, wantErr: true},
+		{name: "ln", line: "ln -s /etc/passwd " + out, wantStatus: 1, wantErr: true},
+		{name: "touch", line: "touch " + out, wantStatus: 1, wantErr: true},
+		{name: "mkdir", line: "mkdir " + out, wantStatus: 1, wantErr: true},
+		{name: "chmod", line: "chmod 777 " + out, wantStatus: 1, wantErr: true},
+		{name: "tar", line: "tar -cf " + out + " /dev/null", wantStatus: 1, wantErr: true},
+		{name: "gzip", line: "gzip " + out, wantStatus: 1, wantErr: true},
+		{name: "gunzip", line: "gunzip " + out + ".gz", wantStatus: 1, wantErr: true},
+		{name: "nohup", line: "nohup echo hi", wantStatus: 1, wantErr: true},
+		{name: "assign PATH", line: "let PATH=1", wantStatus: 1, wantErr: true},
+		{name: "assign HISTFILE", line: "HISTFILE=" + out, wantStatus: 1, wantErr: true},
+		{name: "unset PATH", line: "unset PATH", wantStatus: 1, wantErr: true},
+		{name: "turn off", line: "set +r", wantStatus: 1, wantErr: true},
+		{name: "builtin allowed", line: "echo hi", wantOut: "hi\n"},
+		{name: "input redirection allowed", line: "cat < /dev/null; echo ok", wantOut: "ok\n"},
+		{name: "duplicating allowed", line: "echo hi 2>&1", wantOut: "hi\n"},
+		{name: "other variables allowed", line: "let n=2; echo $n", wantOut: "2\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+			sh.Restrict()
+			status, err := sh.RunLine(tt.line)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrRestricted)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantOut, w.String())
+			require.True(t, sh.opts.enabled(optRestricted))
+			_, err = os.Stat(out)
+			require.True(t, os.IsNotExist(err), "a refused redirection creates no file")
+		})
+	}
+}
+
+func TestShell_Restrict_history(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	require.NoError(t, os.WriteFile(path, []byte("ssh-ed25519 KEY\n"), 0o600))
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, sh.vars.Set("HISTFILE", path))
+	sh.Restrict()
+
+	require.NoError(t, sh.saveHistory())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "ssh-ed25519 KEY\n", string(data), "a restricted shell doesn't write $HISTFILE")
+}
+
+func TestShell_Restrict_setR(t *testing.T) {
+	t.Parallel()
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	_, err := sh.RunLine("set +r; let ENV=1")
+	require.NoError(t, err, "an unrestricted shell may assign ENV")
+
+	_, err = sh.RunLine("set -r; cd /")
+	require.ErrorIs(t, err, ErrRestricted)
+}
+
+func TestShell_AllowOnly(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	sh.AllowOnly("echo", "xargs")
+	require.True(t, sh.opts.enabled(optRestricted))
+
+	for _, line := range []string{"pwd", "xargs pwd <<< x", "f() { pwd; }; f"} {
+		_, err := sh.RunLine(line)
+		require.ErrorIs(t, err, ErrRestricted, line)
+	}
+	_, err := sh.RunLine("g() { echo in g; }; g; xargs echo <<< x")
+	require.NoError(t, err)
+	require.Equal(t, "in g\nx\n", w.String())
+}
+
+func TestShell_AllowOnly_wrappers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "time", line: "time sh -c 'echo ESCAPED'"},
+		{name: "time with a path", line: "time /bin/echo ESCAPED"},
+		{name: "nice", line: "nice sh -c 'echo ESCAPED'"},
+		{name: "nohup", line: "nohup sh -c 'echo ESCAPED'"},
+		{name: "xargs", line: "xargs sh -c 'echo ESCAPED' <<< x"},
+		{name: "timeout", line: "timeout 1 sh -c 'echo ESCAPED'"},
+		{name: "find -exec", line: "find / -maxdepth 0 -exec sh -c 'echo ESCAPED' {} ';'"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+			sh.AllowOnly("echo", "time", "nice", "nohup", "xargs", "timeout", "find")
+			_, err := sh.RunLine(tt.line)
+			require.ErrorIs(t, err, ErrRestricted)
+			require.NotContains(t, w.String(), "ESCAPED")
+		})
+	}
+
+	t.Run("watch", func(t *testing.T) {
+		t.Parallel()
+		w := &bytes.Buffer{}
+		sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+		sh.AllowOnly("watch")
+		// watch shows each failure and keeps going until the shell stops.
+		time.AfterFunc(100*time.Millisecond, sh.Shutdown)
+		_, err := sh.RunLine("watch -n 0.01 sh -c 'echo ESCAPED'")
+		require.NoError(t, err)
+		require.Contains(t, w.String(), "not an allowed command")
+		require.NotContains(t, w.String(), "ESCAPED\n")
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		t.Parallel()
+		w := &bytes.Buffer{}
+		sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+		sh.AllowOnly("echo", "time", "xargs")
+		_, err := sh.RunLine("time echo hi; xargs echo <<< there")
+		require.NoError(t, err)
+		require.Equal(t, "hi\nthere\n", w.String())
+	})
+}
@@ -0,0 +1,805 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// runList runs commands in order, recording each one's status for $?, until
+// stop reports that exit was requested or a break, continue or return leaves
+// the list. Errors of all but the last command are reported as they happen.
+func (s *Shell) runList(ctx *builtins.Context, nodes []node, stop func() bool) error {
+	var last error
+	for i, n := range nodes {
+		if i > 0 {
+			s.report(last)
+		}
+		if stop() || ctx.Context().Err() != nil {
+			break
+		}
+		last = s.runNode(ctx, n)
+		if leaving(last) {
+			return last
+		}
+		status := builtins.StatusOf(last)
+		s.setStatus(status)
+		if status != builtins.StatusSuccess && s.errexit(last) {
+			ctx.Exit(status)
+		}
+	}
+
+	return last
+}
+
+// errexit reports whether set -o errexit makes the failure err end the
+// shell: not in a condition, or when err is a test of && or || failing.
+func (s *Shell) errexit(err error) bool {
+	var test *testFailure
+	if !s.opts.enabled(optErrexit) || errors.As(err, &test) {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conditions == 0
+}
+
+// testFailure is the failure of the command on the left of && or ||, which
+// ended the list without running the rest. It is not an error for set -e.
+type testFailure struct {
+	error
+}
+
+func (t *testFailure) Unwrap() error {
+	return t.error
+}
+
+// runCondition runs the nodes that decide an if, while or until, or the
+// left of && or ||, with set -e off while they run.
+func (s *Shell) runCondition(ctx *builtins.Context, nodes ...node) error {
+	s.mu.Lock()
+	s.conditions++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.conditions--
+		s.mu.Unlock()
+	}()
+
+	return s.runList(ctx, nodes, s.exitStop())
+}
+
+// runGroup runs a brace group or subshell with its redirections applied.
+func (s *Shell) runGroup(ctx *builtins.Context, g *group) error {
+	ctx, closeFiles, err := s.redirect(ctx, g.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+	if !g.subshell {
+		return s.runList(ctx, g.body, s.exitStop())
+	}
+
+	// A subshell can't be a forked process here, so it runs in this one and
+	// puts back what it may have changed; exit only leaves the subshell.
+	restore, err := snapshot()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	defer s.vars.snapshot()()
+	defer s.opts.snapshot()()
+	defer s.snapshotFuncs()()
+
+	var (
+		exited bool
+		code   int
+	)
+	sub := *ctx
+	sub.Exit = func(status int) {
+		if exited {
+			return
+		}
+		if status == exitLastStatus {
+			status = s.Status()
+		}
+		exited, code = true, status
+	}
+	err = s.runList(&sub, g.body, func() bool { return exited })
+	if exited {
+		if code == builtins.StatusSuccess {
+			return nil
+		}
+		return &builtins.ExitError{Status: code}
+	}
+
+	return err
+}
+
+// runNode runs one parsed command.
+func (s *Shell) runNode(ctx *builtins.Context, n node) error {
+	switch n := n.(type) {
+	case *command:
+		return s.runSimple(ctx, n)
+	case *group:
+		return s.runGroup(ctx, n)
+	case *arithCommand:
+		return s.runArith(ctx, n)
+	case *caseCommand:
+		return s.runCase(ctx, n)
+	case *forLoop:
+		return s.runFor(ctx, n)
+	case *ifCommand:
+		return s.runIf(ctx, n)
+	case *whileLoop:
+		return s.runWhile(ctx, n)
+	case *funcDef:
+		s.defineFunc(n)
+	case *pipeline:
+		return s.runPipeline(ctx, n)
+	case *andOr:
+		return s.runAndOr(ctx, n)
+	case *background:
+		return s.runBackground(ctx, n)
+	}
+
+	return nil
+}
+
+// runBackground starts a command as a job and returns without waiting for it,
+// printing the job number and, if it has started one, its first process ID.
+// With set -o notify, its end is reported as soon as it finishes. What the
+// job writes to the terminal is kept to show with that report, or by jobs -o.
+// The job reads no input, exit only ends the job and Ctrl-C doesn't stop it; the
+// shell shutting down does. It runs inside the shell
+// like a subshell does, but in parallel, so a cd or assignment in it is seen
+// by the shell.
+func (s *Shell) runBackground(ctx *builtins.Context, bg *background) error {
+	j := jobTable.add(bg.text, s.jobOutputSize())
+	ready := make(chan struct{})
+	var once sync.Once
+	markReady := func() { once.Do(func() { close(ready) }) }
+
+	c := *ctx
+	c.Stdin = strings.NewReader("")
+	if j.output != nil {
+		// Only what would go to the terminal is kept.
+		if c.Stdout == s.Stdout {
+			c.Stdout = j.output
+		}
+		if c.Stderr == s.Stderr {
+			c.Stderr = j.output
+		}
+	}
+	c.Exit = func(int) {}
+	c.Ctx = s.ctx
+	c.Started = func(p *os.Process) {
+		j.started(p)
+		jobTable.setLastPID(p.Pid)
+		markReady()
+	}
+	c.Runner = func(ctx *builtins.Context, name string, args ...string) error {
+		if _, ok := s.function(name); ok || builtins.IsBuiltin(name) {
+			// A function or builtin may run for a long time without starting
+			// a process.
+			markReady()
+		}
+		return s.runCommand(ctx, name, args...)
+	}
+	go func() {
+		j.finish(builtins.StatusOf(s.runNode(&c, bg.node)))
+		markReady()
+		if s.opts.enabled(optNotify) {
+			jobTable.notify(s.Stderr, j)
+		}
+	}()
+	<-ready
+
+	if pids := j.pids(); len(pids) > 0 {
+		_, _ = fmt.Fprintf(ctx.Stderr, "[%d] %d\n", j.id, pids[0])
+	} else {
+		_, _ = fmt.Fprintf(ctx.Stderr, "[%d]\n", j.id)
+	}
+
+	return nil
+}
+
+// runArith evaluates (( expr )), failing with status 1 if the result is zero.
+func (s *Shell) runArith(ctx *builtins.Context, cmd *arithCommand) error {
+	expr, err := s.expand(cmd.expr)
+	if err != nil {
+		return err
+	}
+	s.trace(ctx, "(("+expr+"))")
+	n, err := evalArith(expr, s.vars)
+	if err != nil {
+		return err
+	}
+
+	return arithStatus(n)
+}
+
+// runCase runs the list of the first item of a case command with a pattern
+// matching its word. It succeeds without running anything if none matches.
+func (s *Shell) runCase(ctx *builtins.Context, c *caseCommand) error {
+	ctx, closeFiles, err := s.redirect(ctx, c.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+	subject, err := s.expandWord(s.tildeExpand(c.word))
+	if err != nil {
+		return err
+	}
+	s.trace(ctx, "case "+quoteWord(subject)+" in")
+
+	for _, item := range c.items {
+		for _, p := range item.patterns {
+			pattern, err := s.patternWord(p)
+			if err != nil {
+				return err
+			}
+			if matchPattern(pattern, subject) {
+				if len(item.body) == 0 {
+					return nil
+				}
+				return s.runList(ctx, item.body, s.exitStop())
+			}
+		}
+	}
+
+	return nil
+}
+
+// runFor runs a for loop's body once for each of its expanded words, with
+// the loop's variable set to the word.
+func (s *Shell) runFor(ctx *builtins.Context, f *forLoop) error {
+	ctx, closeFiles, err := s.redirect(ctx, f.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+	words, err := s.expandWords(f.words)
+	if err != nil {
+		return err
+	}
+
+	defer s.vars.enterLoop()()
+	stop := s.exitStop()
+	for _, w := range words {
+		if stop() || ctx.Context().Err() != nil {
+			break
+		}
+		if err := s.vars.Set(f.name, w); err != nil {
+			return err
+		}
+		s.trace(ctx, "for "+f.name+" in "+quoteWord(w))
+		var done bool
+		if done, err = endTurn(s.runList(ctx, f.body, stop)); done {
+			break
+		}
+	}
+
+	return err
+}
+
+// runIf runs the list of the first clause of an if command whose condition
+// succeeds, or else its else list. It succeeds without running anything if
+// no condition does and there is no else.
+func (s *Shell) runIf(ctx *builtins.Context, c *ifCommand) error {
+	ctx, closeFiles, err := s.redirect(ctx, c.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+	for _, clause := range c.clauses {
+		err := s.runCondition(ctx, clause.cond...)
+		if leaving(err) {
+			return err
+		}
+		if err == nil {
+			return s.runList(ctx, clause.body, s.exitStop())
+		}
+		s.report(err)
+	}
+	if c.elseBody != nil {
+		return s.runList(ctx, c.elseBody, s.exitStop())
+	}
+
+	return nil
+}
+
+// runWhile runs a while loop's body for as long as its condition succeeds,
+// or an until loop's for as long as it fails. Its status is the last run of
+// the body's, or 0 if the body never ran.
+func (s *Shell) runWhile(ctx *builtins.Context, w *whileLoop) error {
+	ctx, closeFiles, err := s.redirect(ctx, w.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+
+	defer s.vars.enterLoop()()
+	stop := s.exitStop()
+	for !stop() && ctx.Context().Err() == nil {
+		cond := s.runCondition(ctx, w.cond...)
+		if leaving(cond) {
+			if done, cErr := endTurn(cond); done {
+				return cErr
+			}
+			continue
+		}
+		s.report(cond)
+		if (cond == nil) == w.until {
+			break
+		}
+		var done bool
+		if done, err = endTurn(s.runList(ctx, w.body, stop)); done {
+			break
+		}
+	}
+
+	return err
+}
+
+// runAndOr runs the left of && or ||, and then the right if the left
+// succeeded, or with ||, if it failed.
+func (s *Shell) runAndOr(ctx *builtins.Context, a *andOr) error {
+	err := s.runCondition(ctx, a.left)
+	if leaving(err) {
+		return err
+	}
+	if (err == nil) == a.or {
+		if err == nil {
+			return nil
+		}
+		return &testFailure{err}
+	}
+	s.report(err)
+
+	return s.runNode(ctx, a.right)
+}
+
+// arithStatus is the result of let and (( )): success unless n is zero.
+func arithStatus(n int64) error {
+	if n == 0 {
+		return &builtins.ExitError{Status: builtins.StatusFailure}
+	}
+
+	return nil
+}
+
+// snapshot records the working directory and environment, returning a function
+// that restores them.
+func snapshot() (func(), error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	env := os.Environ()
+
+	return func() {
+		_ = os.Chdir(wd)
+		os.Clearenv()
+		for _, kv := range env {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				_ = os.Setenv(k, v)
+			}
+		}
+	}, nil
+}
+
+// runSimple starts its process substitutions, expands a command's words,
+// applies its redirections and runs it. Assignments before the words set
+// shell variables if there are no words, and otherwise are exported to the
+// command alone. Assignments alone have the status of the last command
+// substitution in them, if any.
+func (s *Shell) runSimple(ctx *builtins.Context, cmd *command) error {
+	if s.opts.enabled(optExplain) {
+		return s.explain(ctx, cmd)
+	}
+	substituted := s.substituted()
+	ctx, cmd, wait, err := s.substitute(ctx, cmd)
+	defer wait()
+	if err != nil {
+		return err
+	}
+	args, err := s.expandWords(cmd.args)
+	if err != nil {
+		return err
+	}
+	if len(cmd.assigns) > 0 {
+		restore, traced, err := s.assign(cmd.assigns, len(args) > 0)
+		defer restore()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			s.trace(ctx, strings.Join(traced, " "))
+		} else {
+			s.trace(ctx, strings.Join(traced, " ")+" "+quoteWords(args))
+		}
+	} else if len(args) > 0 {
+		s.trace(ctx, quoteWords(args))
+	}
+	if len(args) > 0 && args[0] == "exec" && len(cmd.redirs) > 0 {
+		// exec's redirections outlive the command, so exec applies them itself.
+		redirs, err := s.redirectArgs(cmd.redirs)
+		if err != nil {
+			return err
+		}
+		return ctx.Run("exec", append(redirs, args[1:]...)...)
+	}
+
+	ctx, closeFiles, err := s.redirect(ctx, cmd.redirs)
+	defer closeFiles()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		if status := s.Status(); s.substituted() != substituted && status != builtins.StatusSuccess {
+			return &builtins.ExitError{Status: status}
+		}
+		return nil
+	}
+
+	audited := s.startAudit(ctx, args)
+	err = ctx.Run(args[0], args[1:]...)
+	if notFound(err, args[0]) {
+		err = s.correct(ctx, args, err)
+	}
+	audited(err)
+
+	return err
+}
+
+// assign carries out assignments in order, so that each sees the ones before
+// it, and returns them as xtrace prints them. With export set they go into
+// the environment of the command they come before, and restore puts back the
+// values they replaced; arrays can't be exported.
+func (s *Shell) assign(assigns []*assignment, export bool) (restore func(), traced []string, err error) {
+	var saved []func()
+	restore = func() {
+		for i := len(saved) - 1; i >= 0; i-- {
+			saved[i]()
+		}
+	}
+	for _, a := range assigns {
+		if export && (a.isArray || a.hasIndex) {
+			return restore, nil, fmt.Errorf("%w: %v: an array can't be exported to a command", builtins.ErrInvalidArgs, a.name)
+		}
+		if a.isArray {
+			values, err := s.expandWords(a.array)
+			if err != nil {
+				return restore, nil, err
+			}
+			if err := s.vars.SetArray(a.name, values); err != nil {
+				return restore, nil, err
+			}
+			traced = append(traced, a.name+"=("+quoteWords(values)+")")
+			continue
+		}
+
+		value, err := s.expandWord(s.tildeExpand(a.value))
+		if err != nil {
+			return restore, nil, err
+		}
+		switch {
+		case a.hasIndex:
+			i, err := s.subscript(a.index)
+			if err != nil {
+				return restore, nil, err
+			}
+			if err := s.vars.SetElement(a.name, i, value); err != nil {
+				return restore, nil, err
+			}
+			traced = append(traced, a.name+"["+strconv.Itoa(i)+"]="+quoteWord(value))
+			continue
+		case export:
+			if err := s.vars.checkName(a.name); err != nil {
+				return restore, nil, err
+			}
+			name := a.name
+			if old, ok := os.LookupEnv(name); ok {
+				saved = append(saved, func() { _ = os.Setenv(name, old) })
+			} else {
+				saved = append(saved, func() { _ = os.Unsetenv(name) })
+			}
+			err = os.Setenv(name, value)
+		default:
+			err = s.vars.Set(a.name, value)
+		}
+		if err != nil {
+			return restore, nil, err
+		}
+		traced = append(traced, a.name+"="+quoteWord(value))
+	}
+
+	return restore, traced, nil
+}
+
+// trace prints a command about to run to ctx.Stderr if xtrace (set -x) is on,
+// prefixed with $PS4 ("+ " by default).
+func (s *Shell) trace(ctx *builtins.Context, text string) {
+	if !s.opts.enabled(optXtrace) {
+		return
+	}
+	prefix, ok := s.vars.Get("PS4")
+	if !ok {
+		prefix = "+ "
+	}
+	_, _ = fmt.Fprintln(ctx.Stderr, prefix+text)
+}
+
+// quoteWords joins args with spaces, quoting them where needed.
+func quoteWords(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteWord(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quoteWord single-quotes s if the shell would otherwise split or expand it.
+func quoteWord(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`;&|<>(){}*?[]#~") {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// expandWords brace-expands each word and then expands tildes and parameters
+// in the results, splitting unquoted values into fields at $IFS. Unquoted
+// words that expand to nothing are dropped.
+func (s *Shell) expandWords(words []word) ([]string, error) {
+	args := make([]string, 0, len(words))
+	for _, w := range words {
+		for _, w := range braceExpand(w) {
+			fields, err := s.expandFields(s.tildeExpand(w))
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, fields...)
+		}
+	}
+
+	return args, nil
+}
+
+// redirect returns a copy of ctx with the redirections applied, and a function
+// closing the files it opened. Explain mode opens no files.
+func (s *Shell) redirect(ctx *builtins.Context, redirs []*redirect) (*builtins.Context, func(), error) {
+	var files []*os.File
+	closeFiles := func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}
+	if len(redirs) == 0 || s.opts.enabled(optExplain) {
+		return ctx, closeFiles, nil
+	}
+
+	c := *ctx
+	for _, r := range redirs {
+		target, err := s.expandWord(s.tildeExpand(r.target))
+		if err != nil {
+			return ctx, closeFiles, err
+		}
+		if err := s.checkRedirect(r, target); err != nil {
+			return ctx, closeFiles, err
+		}
+		var (
+			in  io.Reader
+			out io.Writer
+		)
+		switch r.op {
+		case redirIn, redirOut, redirAppend:
+			flag := os.O_RDONLY
+			if r.op == redirOut {
+				flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			} else if r.op == redirAppend {
+				flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+			}
+			f, err := os.OpenFile(target, flag, 0o666)
+			if err != nil {
+				return ctx, closeFiles, err
+			}
+			files = append(files, f)
+			in, out = f, f
+		case redirHeredoc, redirHeredocT:
+			body := r.body
+			if r.expand {
+				if body, err = s.expandWord(heredocWord(body)); err != nil {
+					return ctx, closeFiles, err
+				}
+			}
+			in = strings.NewReader(body)
+		case redirHereString:
+			in = strings.NewReader(target + "\n")
+		case redirDupIn, redirDupOut:
+			switch target {
+			case "0":
+				in = c.Stdin
+			case "1":
+				out = c.Stdout
+			case "2":
+				out = c.Stderr
+			default:
+				return ctx, closeFiles, fmt.Errorf("%w: %v%v: bad file descriptor", ErrSyntax, r.op, target)
+			}
+		}
+
+		switch {
+		case r.fd == 0 && in != nil:
+			c.Stdin = in
+		case r.fd == 1 && out != nil:
+			c.Stdout = out
+		case r.fd == 2 && out != nil:
+			c.Stderr = out
+		default:
+			return ctx, closeFiles, fmt.Errorf("%w: cannot redirect file descriptor %d with %v", ErrSyntax, r.fd, r.op)
+		}
+	}
+
+	return &c, closeFiles, nil
+}
+
+// redirectArgs renders file redirections as exec arguments, e.g. 2>log.
+func (s *Shell) redirectArgs(redirs []*redirect) ([]string, error) {
+	args := make([]string, 0, len(redirs))
+	for _, r := range redirs {
+		switch r.op {
+		case redirIn, redirOut, redirAppend, redirDupIn, redirDupOut:
+			target, err := s.expandWord(s.tildeExpand(r.target))
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, strconv.Itoa(r.fd)+r.op+target)
+		default:
+			return nil, fmt.Errorf("%w: exec cannot take %v", ErrSyntax, r.op)
+		}
+	}
+
+	return args, nil
+}
+
+// runCommand runs a registered builtin, or else the named program from PATH.
+// A restricted shell first checks that the command is allowed.
+func runCommand(ctx *builtins.Context, name string, args ...string) error {
+	if err := checkCommand(ctx, name, args); err != nil {
+		return err
+	}
+	if cmd, ok := builtins.Lookup(name); ok {
+		if cmd.Paged && autopage(ctx) {
+			return runPaged(ctx, cmd, args...)
+		}
+		return cmd.Fn(ctx, args...)
+	}
+
+	cmd, err := newCommand(ctx, name, args...)
+	if err != nil {
+		return err
+	}
+
+	return runProcess(ctx, cmd)
+}
+
+// autopage reports whether set -o autopage is on and ctx writes to a
+// terminal, where output is paged.
+func autopage(ctx *builtins.Context) bool {
+	if ctx.Options == nil || !isTerminal(ctx.Stdout) {
+		return false
+	}
+	on, _ := ctx.Options.Option(optAutopage)
+
+	return on
+}
+
+// runPaged runs the builtin cmd with its output held back, and then shows
+// the output through the pager, which writes it out as it is if it fits on
+// the terminal.
+func runPaged(ctx *builtins.Context, cmd builtins.Command, args ...string) error {
+	var out bytes.Buffer
+	c := *ctx
+	c.Stdout = &out
+	err := cmd.Fn(&c, args...)
+	if perr := builtins.Page(ctx.Stdout, cmd.Name, out.String()); err == nil {
+		err = perr
+	}
+
+	return err
+}
+
+// runProcess starts cmd and waits for it as waitProcess does. Any terminal in
+// raw mode is put back in the mode it was found in while cmd runs, as a
+// full-screen program such as vim or less expects.
+func runProcess(ctx *builtins.Context, cmd *exec.Cmd) error {
+	defer builtins.CookedTerminal()()
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	return waitProcess(ctx, cmd, start)
+}
+
+// waitProcess tells ctx.Started about the process cmd started at start and
+// waits for it, reporting it as interrupted if ctx was cancelled. With set -o
+// cmdstats, it then reports the process's resource usage.
+func waitProcess(ctx *builtins.Context, cmd *exec.Cmd, start time.Time) error {
+	if ctx.Started != nil {
+		ctx.Started(cmd.Process)
+	}
+	err := cmd.Wait()
+	if ctx.Options != nil && cmd.ProcessState != nil {
+		if on, _ := ctx.Options.Option(optCmdstats); on {
+			reportUsage(ctx.Stderr, filepath.Base(cmd.Args[0]), time.Since(start), cmd.ProcessState)
+		}
+	}
+	if err != nil {
+		err = builtins.Interrupted(ctx.Context(), err)
+	}
+
+	return err
+}
+
+// newCommand prepares an external command using ctx's streams and
+// environment, to be killed if ctx is cancelled. The program is found
+// through the command hash, or if ctx's environment has a PATH of its own,
+// as env PATH=DIR CMD gives it, by searching that PATH.
+func newCommand(ctx *builtins.Context, name string, arg ...string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	if pathList, ok := ctx.LookupEnv("PATH"); ok && pathList != os.Getenv("PATH") && !builtins.IsPath(name) {
+		path, found := builtins.LookPath(name, pathList)
+		if !found {
+			return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+		}
+		if !builtins.IsPath(path) {
+			// Found in "." (an empty PATH entry); keep exec from searching.
+			path = "." + string(filepath.Separator) + path
+		}
+		cmd = exec.CommandContext(ctx.Context(), path, arg...)
+		cmd.Args[0] = name
+	} else if path, err := commandHash.lookPath(name); err == nil {
+		cmd = exec.CommandContext(ctx.Context(), path, arg...)
+		cmd.Args[0] = name
+	} else {
+		// Let exec report the failed lookup when the command is run.
+		cmd = exec.CommandContext(ctx.Context(), name, arg...)
+	}
+	cmd.Stdin = ctx.Stdin
+	if in, ok := ctx.Stdin.(*input); ok {
+		// Copying the shell's own input would block on the terminal after the
+		// command exits, so hand over the file itself (or nothing).
+		cmd.Stdin = nil
+		if in.file != nil {
+			cmd.Stdin = in.file
+		}
+	}
+	cmd.Stdout = ctx.Stdout
+	cmd.Stderr = ctx.Stderr
+	cmd.Env = ctx.Env
+	for _, f := range ctx.Files {
+		// ExtraFiles[i] is descriptor 3+i in the child; nil ones are closed.
+		n := int(f.Fd()) - 3
+		for len(cmd.ExtraFiles) <= n {
+			cmd.ExtraFiles = append(cmd.ExtraFiles, nil)
+		}
+		cmd.ExtraFiles[n] = f
+	}
+
+	return cmd, nil
+}
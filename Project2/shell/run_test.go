@@ -0,0 +1,231 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_RunLine_redirections(t *testing.T) {
+	t.Setenv("NAME", "world")
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "here-document", line: "cat <<EOF\nhello $NAME\n\\$NAME\nEOF", want: "hello world\n$NAME\n"},
+		{name: "quoted delimiter", line: "cat <<'EOF'\nhello $NAME\nEOF", want: "hello $NAME\n"},
+		{name: "stripped tabs", line: "cat <<-EOF\n\thello\n\tEOF", want: "hello\n"},
+		{name: "here-document to builtin", line: "wc -l <<EOF\na\nb\nEOF", want: "      2\n"},
+		{name: "here-string", line: `tr a-z A-Z <<<"hi $NAME"`, want: "HI WORLD\n"},
+		{name: "quoted argument", line: `echo 'a  b' "$NAME"`, want: "a  b world\n"},
+		{name: "output file", line: "echo saved >" + out, want: ""},
+		{name: "append and read back", line: "echo more >>" + out + "\ncat <" + out, want: "saved\nmore\n"},
+		{name: "stderr to stdout", line: "sh -c 'echo missing >&2' 2>&1", want: "missing"},
+	}
+	for _, tt := range tests {
+		w := &bytes.Buffer{}
+		sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+		_, _ = sh.RunLine(tt.line)
+		require.Contains(t, w.String(), tt.want, tt.name)
+		if tt.want == "" {
+			require.Empty(t, w.String(), tt.name)
+		}
+	}
+}
+
+func TestShell_Run_continuation(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader("cat <<END\none\ntwo\nEND\necho 'multi\nline'\necho con\\\ntinued \\\n  line\nexit\n"), w, &bytes.Buffer{})
+	require.Equal(t, 0, sh.Run())
+	require.Contains(t, w.String(), "> > > one\ntwo\n")
+	require.Contains(t, w.String(), "> multi\nline\n")
+	require.Contains(t, w.String(), "> > continued line\n")
+}
+
+func TestShell_RunLine_syntaxError(t *testing.T) {
+	t.Parallel()
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	status, err := sh.RunLine("echo >")
+	require.ErrorIs(t, err, ErrSyntax)
+	require.Equal(t, 2, status)
+}
+
+func TestShell_RunLine_groups(t *testing.T) {
+	// Not parallel: subshells change the working directory and environment.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	out := filepath.Join(dir, "group.txt")
+
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	_, err = sh.RunLine("(cd " + dir + "; pwd; exit 3; echo unreachable); echo status $?")
+	require.NoError(t, err)
+	require.Equal(t, dir+"\nstatus 3\n", w.String())
+	got, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, wd, got, "the subshell's cd is undone")
+
+	w.Reset()
+	_, err = sh.RunLine("{ echo one; echo two; } >" + out + "; cat " + out)
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\n", w.String())
+
+	w.Reset()
+	status, err := sh.RunLine("{ exit 4; echo unreachable; }")
+	require.NoError(t, err)
+	require.Equal(t, 0, status)
+	code, exiting := sh.exitRequested()
+	require.True(t, exiting, "exit in a brace group exits the shell")
+	require.Equal(t, 4, code)
+	require.Empty(t, w.String())
+}
+
+func TestShell_RunLine_case(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	require.NoError(t, sh.vars.Set("f", "main.go"))
+	require.NoError(t, sh.vars.Set("pat", "*.go"))
+
+	for _, line := range []string{
+		"case $f in *.txt) echo text;; *.go|*.c) echo source;; *) echo other;; esac",
+		"case $f in $pat) echo unquoted;; esac",
+		`case $f in "$pat") echo quoted;; *) echo literal;; esac`,
+		"case ab in a?) echo one;; ab) echo two;; esac",
+		"case x in [!a-c]) echo class;; esac",
+		"false; case x in y) echo no;; esac; echo $?",
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "source\nunquoted\nliteral\none\nclass\n0\n", w.String())
+}
+
+func TestShell_RunLine_controlFlow(t *testing.T) {
+	t.Parallel()
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	for _, line := range []string{
+		"if false; then echo no; elif (( 1 )); then echo elif; else echo no; fi",
+		"if false; then echo no; fi; echo $?",
+		"n=0; while (( n < 3 )); do echo -n $n; let n=n+1; done; echo",
+		"until (( n == 0 )); do let n=n-1; done; echo n=$n",
+		"true && echo and; false && echo no; false || echo or; true || echo no",
+		"false && echo no || echo chain",
+		"nosuchcommand-xyz || echo recovered",
+		"for i in 1 2 3; do for j in a b; do if (( i == 2 )); then continue 2; fi; echo -n $i$j; done; done; echo",
+		"while true; do while true; do break 2; done; echo no; done; echo broken",
+		"f() { (( $1 > 1 )) && break; echo -n $1; }; for i in 1 2 3; do f $i; done; echo",
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "elif\n0\n012\nn=0\nand\nor\nchain\nrecovered\n1a1b3a3b\nbroken\n1\n", w.String())
+	require.Contains(t, errs.String(), "nosuchcommand-xyz")
+
+	for _, line := range []string{"break", "continue 0", "for i in 1; do break x; done"} {
+		_, err := sh.RunLine(line)
+		require.ErrorIs(t, err, builtins.ErrInvalidArgs, line)
+	}
+}
+
+func TestShell_RunLine_errexitConditions(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("set -e; false && true; if false; then :; fi; while false; do :; done; until true; do :; done; echo survived")
+	require.NoError(t, err)
+	require.Equal(t, "survived\n", w.String())
+
+	_, err = sh.RunLine("true && false; echo unreachable")
+	require.Error(t, err)
+	require.Equal(t, "survived\n", w.String(), "the command after the last && still counts")
+}
+
+func TestShell_RunLine_arrays(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	for _, line := range []string{
+		`files=(a.go "b c.go"
+'*')`,
+		`echo ${#files[@]} "${files[1]}"`,
+		`for f in "${files[@]}"; do echo "<$f>"; done`,
+		"files[-1]=last; files[5]=z; echo ${files[*]} ${#files[@]}",
+		"n=0; for i in 1 2 3; do (( n += i )); done; echo $n $i",
+		"for i in; do echo never; done",
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "3 b c.go\n<a.go>\n<b c.go>\n<*>\na.go b c.go last z 4\n6 3\n", w.String())
+}
+
+// Not parallel: assignments before a command change the environment.
+func TestShell_RunLine_commandAssignments(t *testing.T) {
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("GOSH_TEST_VAR=one printenv GOSH_TEST_VAR; echo \"[$GOSH_TEST_VAR]\"")
+	require.NoError(t, err)
+	require.Equal(t, "one\n[]\n", w.String())
+	_, ok := os.LookupEnv("GOSH_TEST_VAR")
+	require.False(t, ok)
+
+	_, err = sh.RunLine("a=(x) printenv a")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+}
+
+func TestShell_RunLine_envPath(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("runs a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "gosh-env-path-test")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"found $0\"\n"), 0o755))
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	_, err := sh.RunLine("env PATH=" + dir + " gosh-env-path-test")
+	require.NoError(t, err)
+	require.Equal(t, "found "+script+"\n", w.String(), "the command is found on the new PATH")
+
+	status, err := sh.RunLine("env PATH=" + t.TempDir() + " sh -c true")
+	require.Error(t, err, "sh is only on the shell's PATH")
+	require.Equal(t, 127, status)
+
+	status, err = sh.RunLine("gosh-env-path-test")
+	require.Error(t, err, "the shell's own PATH is unchanged")
+	require.Equal(t, 127, status)
+}
+
+func TestShell_RunLine_processSubstitution(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	for _, line := range []string{
+		"diff <(echo a; echo b) <(echo a; echo c)",
+		"/bin/cat <(echo external) - < <(echo redirected)",
+		"echo written > >(wc -c)",
+	} {
+		_, _ = sh.RunLine(line)
+	}
+	require.Equal(t, "2c2\n<b\n---\n>c\nexternal\nredirected\n8\n", strings.ReplaceAll(w.String(), " ", ""), "wc pads its count")
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package shell
+
+import (
+	"os"
+	"time"
+)
+
+// selfUsage is not available on this platform.
+func selfUsage() (user, sys time.Duration, maxRSS int64) {
+	return 0, 0, 0
+}
+
+// processMaxRSS is not available on this platform.
+func processMaxRSS(*os.ProcessState) int64 {
+	return 0
+}
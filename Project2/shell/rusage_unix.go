@@ -0,0 +1,39 @@
+//go:build linux || darwin
+
+package shell
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// selfUsage returns the shell's own user and system CPU time and its peak resident set size in bytes.
+func selfUsage() (user, sys time.Duration, maxRSS int64) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0, 0
+	}
+
+	return time.Duration(ru.Utime.Nano()), time.Duration(ru.Stime.Nano()), rssBytes(int64(ru.Maxrss))
+}
+
+// processMaxRSS returns the peak resident set size of a finished process in bytes.
+func processMaxRSS(state *os.ProcessState) int64 {
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+
+	return rssBytes(int64(ru.Maxrss))
+}
+
+// rssBytes converts ru_maxrss, which Linux reports in kilobytes and macOS in bytes.
+func rssBytes(maxrss int64) int64 {
+	if runtime.GOOS == "darwin" {
+		return maxrss
+	}
+
+	return maxrss * 1024
+}
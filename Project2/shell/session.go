@@ -0,0 +1,243 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("session", func(ctx *builtins.Context, args ...string) error {
+		return sessionCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "session save NAME | session restore NAME | session list | session rm NAME",
+		Summary:  "save and restore the working directory, variables, functions, options, directory stack and history",
+	})
+}
+
+// sessionDir is where sessions are saved, under the home directory.
+const sessionDir = ".gosh/sessions"
+
+// session is the state session save keeps, written as JSON.
+type session struct {
+	Dirs      []string                  `json:"dirs"` // the working directory, then the pushd stack
+	Vars      map[string]string         `json:"vars"`
+	Arrays    map[string]map[int]string `json:"arrays,omitempty"`    // elements by index
+	Functions map[string]string         `json:"functions,omitempty"` // definitions by name
+	Options   map[string]bool           `json:"options"`
+	History   []sessionHistory          `json:"history,omitempty"` // oldest first
+}
+
+// sessionHistory is a command line in a saved history.
+type sessionHistory struct {
+	Line string    `json:"line"`
+	Time time.Time `json:"time"`
+}
+
+// sessionCommand handles the "session" built-in command.
+// save records the working directory, directory stack, shell variables,
+// arrays, functions, options and command history under a name, restore
+// brings them back, list prints the saved names and rm deletes one. The
+// history restored replaces the current one, so that it goes on from where
+// the session was saved. The shell has no aliases to save. Sessions are
+// files in ~/.gosh/sessions.
+func sessionCommand(ctx *builtins.Context, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: session: expected save, restore, list or rm", builtins.ErrInvalidArgCount)
+	}
+	if args[0] == "list" {
+		if len(args) != 1 {
+			return fmt.Errorf("%w: session list takes no names", builtins.ErrInvalidArgCount)
+		}
+		return listSessions(ctx.Stdout)
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("%w: session %v: expected a name", builtins.ErrInvalidArgCount, args[0])
+	}
+
+	path, err := sessionPath(args[1])
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "save":
+		return saveSession(ctx, path)
+	case "restore":
+		return restoreSession(ctx, path)
+	case "rm":
+		return os.Remove(path)
+	default:
+		return fmt.Errorf("%w: session: unknown command %v", builtins.ErrInvalidArgs, args[0])
+	}
+}
+
+// sessionsDir returns the directory sessions are saved in.
+func sessionsDir() (string, error) {
+	if builtins.HomeDir == "" {
+		return "", fmt.Errorf("%w: session: no home directory", builtins.ErrInvalidArgs)
+	}
+
+	return filepath.Join(builtins.HomeDir, sessionDir), nil
+}
+
+// sessionPath returns the file a session of that name is saved in.
+func sessionPath(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: session: invalid name %q", builtins.ErrInvalidArgs, name)
+	}
+	dir, err := sessionsDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// saveSession writes the shell's state to path.
+func saveSession(ctx *builtins.Context, path string) error {
+	dirs, err := builtins.DirStack()
+	if err != nil {
+		return err
+	}
+	state := session{Dirs: dirs, Vars: map[string]string{}, Arrays: map[string]map[int]string{}, Options: map[string]bool{}}
+	if ctx.Vars != nil {
+		v, _ := ctx.Vars.(*variables)
+		for _, name := range ctx.Vars.Names() {
+			if v != nil {
+				if elems, ok := v.elements(name); ok {
+					state.Arrays[name] = elems
+					continue
+				}
+			}
+			state.Vars[name], _ = ctx.Vars.Get(name)
+		}
+	}
+	if ctx.Functions != nil {
+		state.Functions = ctx.Functions()
+	}
+	if ctx.Options != nil {
+		for _, name := range ctx.Options.OptionNames() {
+			if name != optRestricted {
+				state.Options[name], _ = ctx.Options.Option(name)
+			}
+		}
+	}
+
+	for _, e := range commandHistory.stamped() {
+		state.History = append(state.History, sessionHistory{Line: e.line, Time: e.time})
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// restoreSession brings back the state saved in path. Variables set since
+// are kept unless the session sets them too.
+func restoreSession(ctx *builtins.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var state session
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("session: %v: %w", path, err)
+	}
+
+	if len(state.Dirs) > 0 {
+		if err := builtins.SetDirStack(state.Dirs); err != nil {
+			return err
+		}
+	}
+	if ctx.Vars != nil {
+		for name, value := range state.Vars {
+			if err := ctx.Vars.Set(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	if v, ok := ctx.Vars.(*variables); ok {
+		for name, elems := range state.Arrays {
+			if err := v.SetArray(name, nil); err != nil {
+				return err
+			}
+			for i, value := range elems {
+				if err := v.SetElement(name, i, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(state.Functions))
+	for name := range state.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ctx.Eval(state.Functions[name]); err != nil {
+			return fmt.Errorf("session: function %v: %w", name, err)
+		}
+	}
+	if ctx.Options != nil {
+		for name, on := range state.Options {
+			if name == optRestricted {
+				continue
+			}
+			if err := ctx.Options.SetOption(name, on); err != nil {
+				return err
+			}
+		}
+	}
+
+	if state.History != nil {
+		entries := make([]historyEntry, len(state.History))
+		for i, h := range state.History {
+			entries[i] = historyEntry{line: h.Line, time: h.Time}
+		}
+		commandHistory.replace(entries)
+	}
+
+	return nil
+}
+
+// listSessions prints the names of the saved sessions.
+func listSessions(w io.Writer) error {
+	dir, err := sessionsDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if name := strings.TrimSuffix(e.Name(), ".json"); name != e.Name() && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_RunLine_session(t *testing.T) {
+	// Not parallel: changes the working directory, the home directory, the
+	// directory stack and the history.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	home := builtins.HomeDir
+	t.Setenv("PWD", wd)
+	t.Cleanup(func() {
+		builtins.HomeDir = home
+		_ = os.Chdir(wd)
+		commandHistory.clear()
+	})
+	commandHistory.clear()
+	commandHistory.add("cd a", historyFilter{})
+	commandHistory.add("pushd b", historyFilter{})
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	builtins.HomeDir = filepath.Join(root, "home")
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	require.NoError(t, os.Mkdir(a, 0o755))
+	require.NoError(t, os.Mkdir(b, 0o755))
+
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err = sh.RunLine("cd " + a + "; pushd " + b + " >/dev/null; let n=5; l=(x 'y z'); l[5]=w\n" +
+		"greet() {\n\techo hi $1\n}\n" +
+		"set -x; session save work; set +x")
+	require.NoError(t, err)
+	commandHistory.add("later", historyFilter{})
+	_, err = sh.RunLine("dirs -c; cd " + root + "; let n=1 m=2; l=(q); greet() { echo bye; }; bye() { echo; }; session restore work")
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = sh.RunLine("dirs -c") })
+
+	w.Reset()
+	_, err = sh.RunLine(`dirs -l; echo $n $m; echo ${#l[@]} ${l[@]} "${l[5]}"; greet you; type bye; session list`)
+	require.NoError(t, err)
+	require.Equal(t, b+" "+a+"\n5 2\n3 x y z w w\nhi you\nbye is a function\nwork\n", w.String())
+	require.True(t, sh.opts.enabled(optXtrace))
+	require.Equal(t, []string{"cd a", "pushd b"}, commandHistory.list())
+
+	_, err = sh.RunLine("session restore nope")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = sh.RunLine("session save ../x")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+
+	w.Reset()
+	_, err = sh.RunLine("session rm work; session list")
+	require.NoError(t, err)
+	require.Empty(t, w.String())
+}
@@ -0,0 +1,104 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("set", func(ctx *builtins.Context, args ...string) error {
+		return setCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "set [-befrux] [+befux] [-o [NAME]] [+o [NAME]]",
+		Summary:  "change or list shell options",
+		Flags: []string{
+			"-b\treport finished background jobs at once (notify)",
+			"-e\texit when a command fails (errexit)",
+			"-f\tdon't expand pathname patterns such as *.go (noglob)",
+			"-r\trestrict the shell; it can't be turned off (restricted)",
+			"-u\ttreat expanding an unset variable as an error (nounset)",
+			"-x\tprint each command to stderr before running it (xtrace)",
+			"-o NAME\tturn an option on by name; +o turns it off",
+			"-o\tlist the options; +o lists them as set commands",
+		},
+	})
+}
+
+// setCommand handles the "set" built-in command.
+// -b, -e, -f, -r, -u and -x (or -o notify, errexit, noglob, restricted, nounset and xtrace) turn
+// options on and the same flags with + turn them off, except for -r. -o alone lists the options and +o prints
+// the set commands recreating them; set with no arguments is the same as -o.
+// -o explain prints each simple command after brace, tilde, parameter,
+// command and arithmetic expansion, field splitting and pathname expansion,
+// instead of running it. The shell has no aliases, so none are expanded.
+func setCommand(ctx *builtins.Context, args ...string) error {
+	if ctx.Options == nil {
+		return fmt.Errorf("%w: set: no shell options", builtins.ErrInvalidArgs)
+	}
+	if len(args) == 0 {
+		return printOptions(ctx.Stdout, ctx.Options, false)
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" {
+			if i+1 < len(args) {
+				return fmt.Errorf("%w: set: positional parameters are not supported", builtins.ErrInvalidArgs)
+			}
+			return nil
+		}
+		if len(arg) < 2 || (arg[0] != '-' && arg[0] != '+') {
+			return fmt.Errorf("%w: set: positional parameters are not supported", builtins.ErrInvalidArgs)
+		}
+		on := arg[0] == '-'
+
+		for _, c := range []byte(arg[1:]) {
+			if c != 'o' {
+				name, ok := optionLetters[c]
+				if !ok {
+					return fmt.Errorf("%w: set: unknown option %c%c", builtins.ErrInvalidArgs, arg[0], c)
+				}
+				if err := ctx.Options.SetOption(name, on); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if i+1 == len(args) {
+				return printOptions(ctx.Stdout, ctx.Options, !on)
+			}
+			i++
+			if err := ctx.Options.SetOption(args[i], on); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printOptions lists each option as "name on|off", or as the set commands
+// that would restore them.
+func printOptions(w io.Writer, opts builtins.Options, commands bool) error {
+	for _, name := range opts.OptionNames() {
+		on, _ := opts.Option(name)
+		var err error
+		switch {
+		case commands && on:
+			_, err = fmt.Fprintf(w, "set -o %s\n", name)
+		case commands:
+			_, err = fmt.Fprintf(w, "set +o %s\n", name)
+		case on:
+			_, err = fmt.Fprintf(w, "%-15s on\n", name)
+		default:
+			_, err = fmt.Fprintf(w, "%-15s off\n", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
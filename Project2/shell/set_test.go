@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_setCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]bool
+		wantOut string
+		wantErr error
+	}{
+		{
+			name: "letters",
+			args: []string{"-eu", "-x", "+u"},
+			want: map[string]bool{optErrexit: true, optNounset: false, optXtrace: true},
+		},
+		{
+			name: "long names",
+			args: []string{"-o", "nounset", "-eo", "xtrace"},
+			want: map[string]bool{optErrexit: true, optNounset: true, optXtrace: true},
+		},
+		{
+			name:    "list",
+			args:    []string{"-e", "-o"},
+			wantOut: "autopage        off\ncmdstats        off\ncorrect         off\ndirenv          off\nerrexit         on\nexplain         off\nnoglob          off\nnotify          off\nnounset         off\nrestricted      off\ntermtitle       off\nxtrace          off\n",
+		},
+		{
+			name:    "list as commands",
+			args:    []string{"-x", "+o"},
+			wantOut: "set +o autopage\nset +o cmdstats\nset +o correct\nset +o direnv\nset +o errexit\nset +o explain\nset +o noglob\nset +o notify\nset +o nounset\nset +o restricted\nset +o termtitle\nset -o xtrace\n",
+		},
+		{name: "unknown letter", args: []string{"-q"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "unknown name", args: []string{"-o", "nope"}, wantErr: builtins.ErrInvalidArgs},
+		{name: "positional parameters", args: []string{"--", "a"}, wantErr: builtins.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+			err := setCommand(sh.context(sh.in, w), tt.args...)
+			require.True(t, errors.Is(err, tt.wantErr), "setCommand() error = %v, wantErr %v", err, tt.wantErr)
+			require.Equal(t, tt.wantOut, w.String())
+			for name, want := range tt.want {
+				require.Equal(t, want, sh.opts.enabled(name), name)
+			}
+		})
+	}
+}
+
+func TestShell_RunLine_options(t *testing.T) {
+	t.Parallel()
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	_, err := sh.RunLine(`set -x; echo "a b" $((1 + 1)); set +x`)
+	require.NoError(t, err)
+	require.Equal(t, "a b 2\n", w.String())
+	require.Equal(t, "+ echo 'a b' 2\n+ set +x\n", errs.String())
+
+	w.Reset()
+	status, err := sh.RunLine("set -u; echo $UNSET_FOR_TEST")
+	require.ErrorIs(t, err, ErrUnbound)
+	require.Equal(t, 1, status)
+	require.Empty(t, w.String())
+
+	w.Reset()
+	_, err = sh.RunLine("set +u; (set -e; false; echo unreachable); echo $?")
+	require.NoError(t, err)
+	require.Equal(t, "1\n", w.String(), "errexit in a subshell only leaves the subshell")
+	require.False(t, sh.opts.enabled(optErrexit), "a subshell's options are undone")
+
+	w.Reset()
+	status, _ = sh.RunLine("set -e; true; (( 0 )); echo unreachable")
+	require.Equal(t, 1, status)
+	require.Empty(t, w.String())
+	code, exiting := sh.exitRequested()
+	require.True(t, exiting)
+	require.Equal(t, 1, code)
+}
@@ -0,0 +1,324 @@
+// Package shell implements the interactive command interpreter: reading lines,
+// dispatching them to builtins or external programs and tracking exit status.
+package shell
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// Shell is a command interpreter reading from Stdin and writing to Stdout and Stderr.
+type Shell struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	in   *input
+	vars *variables
+	opts *options
+	git  *gitStatus
+
+	// ctx is the context of every command, which Shutdown cancels.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// runMu serializes commands and trap handlers.
+	runMu sync.Mutex
+
+	mu       sync.Mutex // guards the fields below
+	status   int
+	exiting  bool
+	exitCode int
+	funcs    map[string]*funcDef // the functions defined, by name
+	audit    *auditLog           // if set, where each command is recorded
+
+	// conditions is how many conditions of if, while and until, and
+	// commands on the left of && and ||, are running; set -e is off in them.
+	conditions int
+	// substitutions counts the command substitutions run.
+	substitutions int
+}
+
+// New returns a shell using the given streams.
+func New(stdin io.Reader, stdout, stderr io.Writer) *Shell {
+	s := &Shell{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+		in:     newInput(stdin),
+		vars:   newVariables(),
+		opts:   newOptions(),
+		git:    newGitStatus(),
+	}
+	s.vars.check = s.checkAssignment
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	return s
+}
+
+// input is the shell's buffered stdin. Builtins read the buffer, while
+// external commands are given the underlying file, if any.
+type input struct {
+	*bufio.Reader
+	file *os.File
+}
+
+func newInput(r io.Reader) *input {
+	f, _ := r.(*os.File)
+	return &input{Reader: bufio.NewReader(r), file: f}
+}
+
+// Run reads and runs commands until exit is requested, the input ends
+// (Ctrl-D) or the shell is shut down, then shuts down the shell and returns
+// its exit status. The terminal is put back in the mode it was found in when
+// Run returns or panics.
+func (s *Shell) Run() int {
+	defer builtins.RestoreTerminal()
+	done := make(chan struct{})
+	defer close(done)
+	go s.dispatchTraps(done)
+	if s.in.file != nil {
+		// Only the shell of the process itself, not a remote session.
+		defer s.shutdownOnTerm()()
+	}
+	if err := s.loadHistory(); err != nil {
+		_, _ = fmt.Fprintln(s.Stderr, err)
+	}
+
+	for {
+		if code, ok := s.exitRequested(); ok {
+			return s.shutdown(code)
+		}
+		jobTable.reap(s.Stderr)
+		commandIndex.refresh()
+		s.updateDirEnv()
+		s.reportDir(s.Stdout)
+		if err := s.printPrompt(s.Stdout); err != nil {
+			_, _ = fmt.Fprintln(s.Stderr, err)
+			continue
+		}
+		s.setBracketedPaste(true)
+		input, err := s.readInput()
+		s.setBracketedPaste(false)
+		if s.ctx.Err() != nil {
+			// Shut down while waiting for the command.
+			continue
+		}
+		commandHistory.add(input, s.historyFilter())
+		if err != nil && !(err == io.EOF && input != "") {
+			if err == io.EOF {
+				// Ctrl-D on an empty line ends the shell like "exit".
+				_, _ = fmt.Fprintln(s.Stdout)
+				s.requestExit(exitLastStatus)
+				continue
+			}
+			_, _ = fmt.Fprintln(s.Stderr, err)
+			continue
+		}
+		s.reportCommand(s.Stdout, input)
+		_, err = s.RunLine(input)
+		s.report(err)
+	}
+}
+
+// RunLine runs one command line and returns its exit status along with any
+// error it reported. Blank lines leave the status unchanged.
+func (s *Shell) RunLine(line string) (int, error) {
+	s.runMu.Lock()
+	ctx, stop := s.interruptible()
+	err := s.eval(s.context(s.in, s.Stdout).WithContext(ctx), line)
+	stop()
+	s.runMu.Unlock()
+
+	return s.Status(), err
+}
+
+// interruptible returns the context a command line runs in, which Ctrl-C
+// cancels if the shell reads from a terminal, and the function releasing it
+// once the line has run.
+func (s *Shell) interruptible() (context.Context, context.CancelFunc) {
+	if s.in.file == nil || !isTerminal(s.in.file) {
+		return context.WithCancel(s.ctx)
+	}
+
+	return signal.NotifyContext(s.ctx, os.Interrupt)
+}
+
+// readInput reads a command as readCommand does, but gives up once the shell
+// is shut down, which Run then does even while the input stays open.
+func (s *Shell) readInput() (string, error) {
+	type result struct {
+		text string
+		err  error
+	}
+	read := make(chan result, 1)
+	go func() {
+		text, err := s.readCommand()
+		read <- result{text, err}
+	}()
+	select {
+	case r := <-read:
+		return r.text, r.err
+	case <-s.ctx.Done():
+		return "", s.ctx.Err()
+	}
+}
+
+// readCommand reads a line, and further lines while a quote, group or
+// here-document is left open or the line ends with a backslash, prompting for
+// each with $PS2 ("> " by default). A multi-line paste is read whole and
+// only run once confirmed.
+func (s *Shell) readCommand() (string, error) {
+	text, err := s.readLine(func() { _ = s.printPrompt(s.Stdout) })
+	if err == nil && strings.Contains(text, pasteStart) {
+		text, err = s.readPaste(text)
+	}
+	for err == nil && incomplete(text) {
+		prompt, ok := s.vars.Get("PS2")
+		if !ok {
+			prompt = "> "
+		}
+		_, _ = fmt.Fprint(s.Stdout, prompt)
+		var more string
+		more, err = s.readLine(func() { _, _ = fmt.Fprint(s.Stdout, prompt) })
+		text += more
+	}
+	if errors.Is(err, errLineCancelled) {
+		// Ctrl-C abandons the whole command.
+		return "\n", nil
+	}
+
+	return text, err
+}
+
+// readLine reads one line, with the line editor if the shell reads from and
+// writes to a terminal. prompt prints the line's prompt again.
+func (s *Shell) readLine(prompt func()) (string, error) {
+	if s.in.file == nil || !isTerminal(s.in.file) || !isTerminal(s.Stdout) {
+		return s.in.ReadString('\n')
+	}
+	restore, err := builtins.MakeRaw(s.in.file)
+	if err != nil {
+		return s.in.ReadString('\n')
+	}
+	defer restore()
+	e := &editor{in: s.in, out: s.Stdout, prompt: prompt, complete: s.completeLine, keys: keyBindings}
+	if os.Getenv("NO_COLOR") == "" {
+		e.highlight = func(line string) string { return highlight(line, s.isFunction) }
+		e.suggest = commandHistory.suggest
+	}
+
+	return e.readLine()
+}
+
+// incomplete reports whether src needs more lines to be parsed.
+func incomplete(src string) bool {
+	_, err := parse(src)
+	return errors.Is(err, errIncomplete)
+}
+
+// eval parses and runs a command line with ctx, recording each command's
+// status for $?. Errors of all but the last command are reported as they happen.
+func (s *Shell) eval(ctx *builtins.Context, src string) error {
+	nodes, err := parse(src)
+	if err != nil {
+		s.setStatus(builtins.StatusUsage)
+		return err
+	}
+
+	return s.runList(ctx, nodes, s.exitStop())
+}
+
+// exitStop returns a stop function for runList that reports whether exit has
+// been requested since, so a list stops at "exit" but the EXIT trap still runs.
+func (s *Shell) exitStop() func() bool {
+	_, before := s.exitRequested()
+	return func() bool {
+		_, exiting := s.exitRequested()
+		return exiting && !before
+	}
+}
+
+func (s *Shell) setStatus(status int) {
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+// report prints a command's error, unless it only carries an exit status.
+func (s *Shell) report(err error) {
+	if err != nil && !builtins.IsStatusOnly(err) {
+		_, _ = fmt.Fprintln(s.Stderr, err)
+	}
+}
+
+// Status returns the exit status of the last command.
+func (s *Shell) Status() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// Exit asks the shell to exit with status once the current command finishes.
+func (s *Shell) Exit(status int) {
+	s.requestExit(status)
+}
+
+// Shutdown stops the shell from outside, as when the client of a remote
+// session goes away: the commands running, background jobs included, are
+// cancelled, and Run shuts down as for exit with the last command's status
+// instead of reading another command.
+func (s *Shell) Shutdown() {
+	s.requestExit(exitLastStatus)
+	s.cancel()
+}
+
+// requestExit records an exit request; exitLastStatus means the status of the
+// last command. Only the first request counts.
+func (s *Shell) requestExit(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exiting {
+		return
+	}
+	if status == exitLastStatus {
+		status = s.status
+	}
+	s.exiting, s.exitCode = true, status
+}
+
+// exitRequested reports whether the shell should exit, and with which status.
+func (s *Shell) exitRequested() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.exitCode, s.exiting
+}
+
+// context returns the context builtins run with, wired to this shell.
+func (s *Shell) context(r io.Reader, w io.Writer) *builtins.Context {
+	return &builtins.Context{
+		Stdin:          r,
+		Stdout:         w,
+		Stderr:         s.Stderr,
+		Runner:         s.runCommand,
+		FunctionLookup: s.isFunction,
+		Functions:      s.definitions,
+		Evaluator:      s.eval,
+		Exit:           s.requestExit,
+		Vars:           s.vars,
+		Options:        s.opts,
+		Jobs:           jobTable,
+		Ctx:            s.ctx,
+	}
+}
@@ -0,0 +1,128 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestShell_Run(t *testing.T) {
+	t.Parallel()
+	exitCmd := strings.NewReader("exit\n")
+	type args struct {
+		r io.Reader
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantW    string
+		wantErrW string
+	}{
+		{
+			name: "no error",
+			args: args{
+				r: exitCmd,
+			},
+		},
+		{
+			name: "read error should have no effect",
+			args: args{
+				r: iotest.ErrReader(errors.New("read failed")),
+			},
+			wantErrW: "read failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := &bytes.Buffer{}
+			errW := &bytes.Buffer{}
+
+			sh := New(tt.args.r, w, errW)
+			// run the loop for 10ms
+			done := make(chan struct{})
+			go func() {
+				sh.Run()
+				close(done)
+			}()
+			time.Sleep(10 * time.Millisecond)
+			sh.Exit(0)
+			<-done
+
+			require.NotEmpty(t, w.String())
+			if tt.wantErrW != "" {
+				require.Contains(t, errW.String(), tt.wantErrW)
+			} else {
+				require.Empty(t, errW.String())
+			}
+		})
+	}
+}
+func TestShell_RunLine(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	status, err := sh.RunLine("echo hello world\n")
+	require.NoError(t, err)
+	require.Equal(t, 0, status)
+	require.Equal(t, "hello world\n", w.String())
+
+	status, err = sh.RunLine("false")
+	require.Error(t, err)
+	require.Equal(t, 1, status)
+
+	status, err = sh.RunLine("   ")
+	require.NoError(t, err)
+	require.Equal(t, 1, status, "blank lines keep the last status")
+
+	status, err = sh.RunLine("no-such-command-here")
+	require.Error(t, err)
+	require.Equal(t, 127, status)
+}
+
+func TestShell_Shutdown(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "builtin", line: "sleep 10; echo after"},
+		{name: "walk", line: "find / > /dev/null; echo after"},
+		{name: "external", line: "sh -c 'exec sleep 10'; echo after"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			sh := New(strings.NewReader(""), &out, io.Discard)
+			time.AfterFunc(50*time.Millisecond, sh.Shutdown)
+			start := time.Now()
+			status, _ := sh.RunLine(tt.line)
+			require.Less(t, time.Since(start), 5*time.Second)
+			require.Equal(t, builtins.StatusInterrupted, status)
+			require.Empty(t, out.String())
+		})
+	}
+}
+
+// Test_builtinManuals checks that go generate has made a manual page for
+// every builtin, including the shell's own.
+func Test_builtinManuals(t *testing.T) {
+	t.Parallel()
+	var missing []string
+	for _, name := range builtins.Names() {
+		if cmd, _ := builtins.Lookup(name); cmd.Manual == "" {
+			missing = append(missing, name)
+		}
+	}
+	require.Empty(t, missing, "builtins without a manual page; run go generate ./builtins")
+}
@@ -0,0 +1,78 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("source", func(ctx *builtins.Context, args ...string) error {
+		return sourceFile(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "source FILE",
+		Summary:  "run a script in the current shell",
+	})
+	builtins.Register(".", func(ctx *builtins.Context, args ...string) error {
+		return sourceFile(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: ". FILE",
+		Summary:  "run a script in the current shell",
+	})
+}
+
+// sourceFile handles the "source" and "." built-in commands.
+// It runs each line of a script in the current shell rather than a subprocess, so
+// commands like cd keep their effect afterwards. A failing line is reported on
+// stderr with its line number and the rest of the script still runs. The status
+// is that of the last command.
+func sourceFile(ctx *builtins.Context, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected a file name", builtins.ErrInvalidArgCount)
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		last    error
+		pending string // lines of a command still open, e.g. inside a here-document
+		start   int    // line number the pending command starts on
+	)
+	eval := func() {
+		if strings.TrimSpace(pending) != "" {
+			last = ctx.Eval(pending)
+			if last != nil && !builtins.IsStatusOnly(last) {
+				_, _ = fmt.Fprintf(ctx.Stderr, "%v:%d: %v\n", args[0], start, last)
+			}
+		}
+		pending = ""
+	}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if pending == "" {
+			start = lineNo
+		}
+		pending += scanner.Text() + "\n"
+		if !incomplete(pending) {
+			eval()
+		}
+	}
+	// Whatever is left is unterminated; evaluating it reports the syntax error.
+	eval()
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if last != nil {
+		// Already reported above; only the status is left to pass on.
+		return &builtins.ExitError{Status: builtins.StatusOf(last)}
+	}
+
+	return nil
+}
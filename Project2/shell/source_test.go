@@ -0,0 +1,47 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sourceFile(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n# it's documented\necho one # first\n\n  # indented\necho two\n"), 0o644))
+
+	w := &bytes.Buffer{}
+	ctx := New(strings.NewReader(""), w, &bytes.Buffer{}).context(strings.NewReader(""), w)
+	require.NoError(t, sourceFile(ctx, script))
+	require.Equal(t, "one\ntwo\n", w.String())
+
+	err := sourceFile(ctx)
+	require.True(t, errors.Is(err, builtins.ErrInvalidArgCount))
+
+	err = sourceFile(ctx, filepath.Join(dir, "missing"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func Test_sourceFile_changesDirectory(t *testing.T) {
+	// Not parallel: changes the process working directory.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	script := filepath.Join(dir, "cd.sh")
+	require.NoError(t, os.WriteFile(script, []byte("cd "+dir+"\n"), 0o644))
+
+	require.NoError(t, sourceFile(New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{}).context(strings.NewReader(""), &bytes.Buffer{}), script))
+	got, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, dir, got)
+}
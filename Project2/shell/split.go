@@ -0,0 +1,163 @@
+package shell
+
+import (
+	"strconv"
+	"strings"
+)
+
+// defaultIFS is the field separators used while IFS is unset.
+const defaultIFS = " \t\n"
+
+// expandFields expands w as expandWord does, then splits the values of its
+// unquoted parameters and the output of its unquoted command substitutions
+// into fields at the characters of $IFS. Quoted text and the word's own text
+// are never split. An unquoted word that expands to nothing gives no fields.
+// Last, unless set -f (noglob) is on, a field with an unquoted *, ? or [ is a
+// pathname pattern, replaced by the paths it matches if there are any.
+func (s *Shell) expandFields(w word) ([]string, error) {
+	ifs, ok := s.vars.Get("IFS")
+	if !ok {
+		ifs = defaultIFS
+	}
+	f := &fieldSplitter{ifs: ifs, glob: !s.opts.enabled(optNoglob)}
+	for _, part := range w {
+		switch part.quote {
+		case singleQuoted:
+			f.literal(part.text)
+		case cmdSubst, quotedCmdSubst:
+			out, err := s.commandOutput(part.text)
+			if err != nil {
+				return nil, err
+			}
+			if part.quote == cmdSubst {
+				f.split(out)
+			} else {
+				f.literal(out)
+			}
+		case arithmetic:
+			expr, err := s.expand(part.text)
+			if err != nil {
+				return nil, err
+			}
+			n, err := evalArith(expr, s.vars)
+			if err != nil {
+				return nil, err
+			}
+			f.literal(strconv.FormatInt(n, 10))
+		case doubleQuoted:
+			// "${NAME[@]}" gives a field for each element, and none if
+			// there are no elements, so "" is the only empty word.
+			emitted := false
+			if err := s.expandEach(part.text, func(text string, kind piece) {
+				emitted = true
+				if kind == fieldBreak {
+					f.end()
+				} else {
+					f.literal(text)
+				}
+			}); err != nil {
+				return nil, err
+			}
+			if !emitted && part.text == "" {
+				f.literal("")
+			}
+		default:
+			if err := s.expandEach(part.text, func(text string, kind piece) {
+				switch kind {
+				case paramValue:
+					f.split(text)
+				case fieldBreak:
+					if f.inWord {
+						f.end()
+					}
+				default:
+					f.unquoted(text)
+				}
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return f.finish(), nil
+}
+
+// fieldSplitter builds the fields of a word. Runs of the blanks in IFS
+// separate fields and are ignored at either end; each other IFS character
+// separates two fields, with any blanks around it, so "a::b" with IFS=":"
+// gives an empty field between a and b. With glob set, each field is also
+// built as a pattern, in which quoted text only matches itself, and is
+// replaced by the paths that pattern matches.
+type fieldSplitter struct {
+	ifs     string
+	glob    bool
+	fields  []string
+	field   strings.Builder
+	pattern strings.Builder
+	inWord  bool // the current field has been started, perhaps by ""
+	merge   bool // the last field ended at blanks, which a following : joins
+}
+
+// literal adds text that is not split, even if empty, to the current field.
+func (f *fieldSplitter) literal(text string) {
+	f.field.WriteString(text)
+	f.pattern.WriteString(escapePattern(text))
+	f.inWord, f.merge = true, false
+}
+
+// unquoted adds the word's own unquoted text, which is not split but may
+// be a pattern, to the current field.
+func (f *fieldSplitter) unquoted(text string) {
+	f.field.WriteString(text)
+	f.pattern.WriteString(text)
+	f.inWord, f.merge = true, false
+}
+
+// split adds an expanded value, splitting it at the IFS characters.
+func (f *fieldSplitter) split(text string) {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte(f.ifs, c) < 0:
+			f.field.WriteByte(c)
+			f.pattern.WriteByte(c)
+			f.inWord, f.merge = true, false
+		case c == ' ' || c == '\t' || c == '\n':
+			if f.inWord {
+				f.end()
+				f.merge = true
+			}
+		case f.inWord:
+			f.end()
+		case f.merge:
+			f.merge = false
+		default:
+			f.end()
+		}
+	}
+}
+
+// end ends the current field, which becomes the paths it matches if it is a
+// pattern that matches any.
+func (f *fieldSplitter) end() {
+	field, pattern := f.field.String(), f.pattern.String()
+	f.field.Reset()
+	f.pattern.Reset()
+	f.inWord, f.merge = false, false
+	if f.glob && hasGlob(pattern) {
+		if paths := glob(pattern); len(paths) > 0 {
+			f.fields = append(f.fields, paths...)
+			return
+		}
+	}
+	f.fields = append(f.fields, field)
+}
+
+// finish returns the fields, ending the last one if it was started.
+func (f *fieldSplitter) finish() []string {
+	if f.inWord {
+		f.end()
+	}
+
+	return f.fields
+}
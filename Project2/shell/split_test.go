@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_expandFields(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		ifs   *string
+		value string
+		word  string
+		want  []string
+	}{
+		{name: "default", value: "  a b\t\nc  ", word: "$v", want: []string{"a", "b", "c"}},
+		{name: "quoted", value: "a  b", word: `"$v"`, want: []string{"a  b"}},
+		{name: "joined to text", value: "a b", word: "x${v}y", want: []string{"xa", "by"}},
+		{name: "literal text is not split", value: "", word: `a\ b$v`, want: []string{"a b"}},
+		{name: "empty value", value: "", word: "$v", want: nil},
+		{name: "empty quoted value", value: "", word: `"$v"`, want: []string{""}},
+		{name: "blanks only", value: "   ", word: "$v", want: nil},
+		{name: "colon", ifs: strPtr(":"), value: "a::b:", word: "$v", want: []string{"a", "", "b"}},
+		{name: "leading colon", ifs: strPtr(":"), value: ":a", word: "$v", want: []string{"", "a"}},
+		{name: "colon and blanks", ifs: strPtr(" :"), value: " a : b  c ", word: "$v", want: []string{"a", "b", "c"}},
+		{name: "blank then colon", ifs: strPtr(" :"), value: " :a", word: "$v", want: []string{"", "a"}},
+		{name: "blanks kept without them in IFS", ifs: strPtr(":"), value: "a b:c", word: "$v", want: []string{"a b", "c"}},
+		{name: "empty IFS", ifs: strPtr(""), value: "a b", word: "$v", want: []string{"a b"}},
+		{name: "arithmetic", value: "", word: "$((1+2))", want: []string{"3"}},
+		{name: "quoted elements", value: "", word: `x"${a[@]}"y`, want: []string{"x1", "2 3y"}},
+		{name: "unquoted elements", value: "", word: "${a[@]}", want: []string{"1", "2", "3"}},
+		{name: "elements joined", value: "", word: `"${a[*]}"`, want: []string{"1 2 3"}},
+		{name: "no elements", value: "", word: `"${none[@]}"`, want: nil},
+		{name: "empty string", value: "", word: `""`, want: []string{""}},
+		{name: "command substitution", value: "", word: "x$(echo 'a  b'; echo; echo)", want: []string{"xa", "b"}},
+		{name: "quoted command substitution", value: "", word: `"$(echo 'a  b')"`, want: []string{"a  b"}},
+		{name: "command substitution at colons", ifs: strPtr(":"), value: "", word: "`echo a::b`", want: []string{"a", "", "b"}},
+		{name: "empty command substitution", value: "", word: "$(true)", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+			if tt.ifs != nil {
+				require.NoError(t, sh.vars.Set("IFS", *tt.ifs))
+			}
+			require.NoError(t, sh.vars.Set("v", tt.value))
+			require.NoError(t, sh.vars.SetArray("a", []string{"1", "2 3"}))
+			require.NoError(t, sh.vars.SetArray("none", nil))
+			w, err := (&parser{src: tt.word}).word()
+			require.NoError(t, err)
+			got, err := sh.expandFields(w)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShell_RunLine_fieldSplitting(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	require.NoError(t, sh.vars.Set("v", "a  b"))
+	_, err := sh.RunLine(`echo $v; echo "$v"; echo x${v}y`)
+	require.NoError(t, err)
+	require.Equal(t, "a b\na  b\nxa by\n", w.String())
+
+	w.Reset()
+	require.NoError(t, sh.vars.Set("IFS", ":"))
+	require.NoError(t, sh.vars.Set("v", "a:b c"))
+	_, err = sh.RunLine(`let n=2; printf "[%s]" $v-$n`)
+	require.NoError(t, err)
+	require.Equal(t, "[a][b c-2]", w.String())
+}
+
+func TestShell_RunLine_commandSubstitution(t *testing.T) {
+	t.Parallel()
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	for _, line := range []string{
+		"for f in $(echo one two); do echo \"<$f>\"; done",
+		"echo `echo back \\`echo nested\\``",
+		`echo "$(echo "a)b")"`,
+		"x=$(false); echo $? $x; y=$(echo ok); echo $? $y",
+		"echo $(cd /; v=inner; pwd) [$v]",
+		"case $(echo abc) in a*) echo matched;; esac",
+		"cat <<EOF\nbody $(echo sub) `echo tick` \\$(kept)\nEOF",
+		"echo [$(nosuchcommand-xyz)]",
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "<one>\n<two>\nback nested\na)b\n1\n0 ok\n/ []\nmatched\nbody sub tick $(kept)\n[]\n", w.String())
+	require.Contains(t, errs.String(), "nosuchcommand-xyz")
+
+	_, err := sh.RunLine("echo $(echo")
+	require.ErrorIs(t, err, ErrSyntax)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
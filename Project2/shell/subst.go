@@ -0,0 +1,148 @@
+package shell
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// substitute starts the process substitutions in the words and redirection
+// targets of cmd, returning the command with each replaced by a path to its
+// pipe, and a copy of ctx passing the pipes on to external commands. The
+// commands of <( ... ) write to the pipe and those of >( ... ) read from it.
+// wait closes the shell's ends of the pipes, once the command is done, and
+// waits for the substitutions to finish.
+func (s *Shell) substitute(ctx *builtins.Context, cmd *command) (_ *builtins.Context, _ *command, wait func(), err error) {
+	var (
+		files []*os.File
+		done  []chan struct{}
+	)
+	wait = func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+		for _, d := range done {
+			<-d
+		}
+	}
+	replace := func(w word) (word, error) {
+		var out word
+		for i, part := range w {
+			if part.quote != procIn && part.quote != procOut {
+				if out != nil {
+					out = append(out, part)
+				}
+				continue
+			}
+			if out == nil {
+				out = append(word{}, w[:i]...)
+			}
+			f, d, err := s.startSubstitution(ctx, part)
+			if err != nil {
+				return nil, err
+			}
+			files, done = append(files, f), append(done, d)
+			path, err := fdPath(f)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, wordPart{text: path, quote: singleQuoted})
+		}
+		if out == nil {
+			return w, nil
+		}
+		return out, nil
+	}
+
+	c := &command{args: make([]word, len(cmd.args)), redirs: make([]*redirect, len(cmd.redirs))}
+	for i, w := range cmd.args {
+		if c.args[i], err = replace(w); err != nil {
+			return ctx, cmd, wait, err
+		}
+	}
+	for i, r := range cmd.redirs {
+		r2 := *r
+		if r2.target, err = replace(r.target); err != nil {
+			return ctx, cmd, wait, err
+		}
+		c.redirs[i] = &r2
+	}
+	if len(files) == 0 {
+		return ctx, cmd, wait, nil
+	}
+
+	sub := *ctx
+	sub.Files = append(append([]*os.File(nil), ctx.Files...), files...)
+
+	return &sub, c, wait, nil
+}
+
+// commandOutput runs the commands of a command substitution in a subshell,
+// reading no input, and returns what they write to standard output without
+// its trailing newlines. Their status becomes $?, and their errors are
+// reported as they would be on the command line.
+func (s *Shell) commandOutput(src string) (string, error) {
+	nodes, err := parse(src)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	err = s.runGroup(s.context(strings.NewReader(""), &out), &group{body: nodes, subshell: true})
+	s.report(err)
+	s.mu.Lock()
+	s.status = builtins.StatusOf(err)
+	s.substitutions++
+	s.mu.Unlock()
+
+	return strings.TrimRight(out.String(), "\n"), nil
+}
+
+// substituted returns how many command substitutions have run, so that a
+// command of assignments alone can take the status of the last one.
+func (s *Shell) substituted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.substitutions
+}
+
+// startSubstitution starts the commands of a process substitution with a pipe
+// for their output or input, returning the shell's end of the pipe and a
+// channel closed when they finish. They run in parallel with the command,
+// like a background job, and their exit status is ignored.
+func (s *Shell) startSubstitution(ctx *builtins.Context, part wordPart) (*os.File, chan struct{}, error) {
+	nodes, err := parse(part.text)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := *ctx
+	c.Stdin = strings.NewReader("")
+	c.Exit = func(int) {}
+	keep, theirs := r, w
+	if part.quote == procIn {
+		c.Stdout = w
+	} else {
+		c.Stdin = r
+		keep, theirs = w, r
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer theirs.Close()
+		for _, n := range nodes {
+			if err := s.runNode(&c, n); err != nil && !builtins.IsStatusOnly(err) {
+				_, _ = fmt.Fprintln(c.Stderr, err)
+			}
+		}
+	}()
+
+	return keep, done, nil
+}
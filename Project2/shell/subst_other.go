@@ -0,0 +1,13 @@
+//go:build windows
+
+package shell
+
+import (
+	"errors"
+	"os"
+)
+
+// fdPath returns an error, as there is no /dev/fd on this platform.
+func fdPath(*os.File) (string, error) {
+	return "", errors.New("process substitution is not supported on this platform")
+}
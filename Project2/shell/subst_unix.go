@@ -0,0 +1,15 @@
+//go:build !windows
+
+package shell
+
+import (
+	"os"
+	"strconv"
+)
+
+// fdPath returns the path through which a command opens f: builtins open
+// the shell's own descriptor, and external commands inherit it at the same
+// number.
+func fdPath(f *os.File) (string, error) {
+	return "/dev/fd/" + strconv.Itoa(int(f.Fd())), nil
+}
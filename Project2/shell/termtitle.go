@@ -0,0 +1,68 @@
+package shell
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// maxTitle is how many characters of a command line the title shows.
+const maxTitle = 60
+
+// reportDir tells the terminal the working directory, if the termtitle
+// option is on and w is a terminal: with OSC 7, which terminal emulators and
+// tmux use to open new windows in the same directory, and as the title of
+// the window, shortened with ~ for the home directory.
+func (s *Shell) reportDir(w io.Writer) {
+	if !s.opts.enabled(optTermtitle) || !isTerminal(w) {
+		return
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	host, _ := os.Hostname()
+	_, _ = io.WriteString(w, dirReport(host, wd)+titleSeq(builtins.AbbreviateHome(wd)))
+}
+
+// reportCommand sets the title of the terminal's window to the command line
+// about to run, if the termtitle option is on and w is a terminal.
+func (s *Shell) reportCommand(w io.Writer, line string) {
+	if !s.opts.enabled(optTermtitle) || !isTerminal(w) {
+		return
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		_, _ = io.WriteString(w, titleSeq(line))
+	}
+}
+
+// dirReport returns the OSC 7 sequence reporting dir on host as a file URL.
+func dirReport(host, dir string) string {
+	u := url.URL{Scheme: "file", Host: host, Path: filepath.ToSlash(dir)}
+	return "\x1b]7;" + u.String() + "\x1b\\"
+}
+
+// titleSeq returns the OSC 2 sequence setting the window title to the first
+// line of title, without control characters and cut to maxTitle characters.
+func titleSeq(title string) string {
+	title, _, cut := strings.Cut(title, "\n")
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, title)
+	if runes := []rune(title); len(runes) > maxTitle {
+		title, cut = string(runes[:maxTitle]), true
+	}
+	if cut {
+		title += "…"
+	}
+
+	return "\x1b]2;" + title + "\a"
+}
@@ -0,0 +1,40 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dirReport(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "\x1b]7;file://box/home/me/my%20dir\x1b\\", dirReport("box", "/home/me/my dir"))
+}
+
+func Test_titleSeq(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "make test", want: "make test"},
+		{title: "echo \x1b]0;evil\a", want: "echo ]0;evil"},
+		{title: "for f in *\ndo echo $f\ndone", want: "for f in *…"},
+		{title: strings.Repeat("x", 70), want: strings.Repeat("x", maxTitle) + "…"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, "\x1b]2;"+tt.want+"\a", titleSeq(tt.title), tt.title)
+	}
+}
+
+func TestShell_reportDir(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	sh := New(strings.NewReader(""), &out, &bytes.Buffer{})
+	require.NoError(t, sh.opts.SetOption(optTermtitle, true))
+	sh.reportDir(&out)
+	sh.reportCommand(&out, "ls\n")
+	require.Empty(t, out.String(), "nothing is sent to what isn't a terminal")
+}
@@ -0,0 +1,77 @@
+package shell
+
+import (
+	"os/user"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// tildeExpand replaces an unquoted ~ or ~user at the start of w, up to the
+// first slash, with $HOME or that user's home directory. It also expands the
+// value of an assignment-style word such as dir=~/src. A ~user naming no
+// known user is left as it is.
+func (s *Shell) tildeExpand(w word) word {
+	if len(w) == 0 || w[0].quote != unquoted {
+		return w
+	}
+	text := w[0].text
+	start := 0
+	if !strings.HasPrefix(text, "~") {
+		eq := strings.IndexByte(text, '=')
+		if eq <= 0 || !validName(text[:eq]) || !strings.HasPrefix(text[eq+1:], "~") {
+			return w
+		}
+		start = eq + 1
+	}
+
+	end := strings.IndexByte(text[start:], '/')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += start
+	}
+	if end == len(text) && len(w) > 1 {
+		// Quoting any of the prefix, as in ~"user" or ~"/x", turns it off.
+		return w
+	}
+	home, ok := s.homeDir(text[start+1 : end])
+	if !ok {
+		return w
+	}
+
+	// The home directory is literal text, so it is not expanded again.
+	out := word{{text: text[:start]}, {text: home, quote: singleQuoted}, {text: text[end:]}}
+	out = append(out, w[1:]...)
+
+	return joinParts(dropEmpty(out))
+}
+
+// homeDir returns the home directory of the named user, or the current
+// user's ($HOME) if name is empty.
+func (s *Shell) homeDir(name string) (string, bool) {
+	if name == "" {
+		if home, ok := s.vars.Get("HOME"); ok {
+			return home, true
+		}
+		return builtins.HomeDir, builtins.HomeDir != ""
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", false
+	}
+
+	return u.HomeDir, true
+}
+
+// dropEmpty removes parts without text.
+func dropEmpty(w word) word {
+	out := w[:0]
+	for _, p := range w {
+		if p.text != "" || separate(p.quote) {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
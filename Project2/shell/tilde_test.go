@@ -0,0 +1,51 @@
+package shell
+
+import (
+	"bytes"
+	"os/user"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_tildeExpand(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "~", want: "/home/me"},
+		{word: "~/src", want: "/home/me/src"},
+		{word: "dir=~/src", want: "dir=/home/me/src"},
+		{word: "--dir=~/src", want: "--dir=~/src"},
+		{word: "a~", want: "a~"},
+		{word: `"~"`, want: "~"},
+		{word: `~"/x"`, want: "~/x"},
+		{word: `\~`, want: "~"},
+		{word: "~no-such-user-for-test/x", want: "~no-such-user-for-test/x"},
+	}
+	if u, err := user.Current(); err == nil {
+		tests = append(tests, struct {
+			word string
+			want string
+		}{word: "~" + u.Username + "/x", want: u.HomeDir + "/x"})
+	}
+	for _, tt := range tests {
+		nodes, err := parse("echo " + tt.word)
+		require.NoError(t, err)
+		got, err := sh.expandWord(sh.tildeExpand(nodes[0].(*command).args[1]))
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, tt.word)
+	}
+}
+
+func TestShell_RunLine_tilde(t *testing.T) {
+	t.Setenv("HOME", "/home/$USER")
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine("echo {~,x}/a")
+	require.NoError(t, err)
+	require.Equal(t, "/home/$USER/a x/a\n", w.String(), "the home directory is not expanded again")
+}
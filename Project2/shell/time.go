@@ -0,0 +1,73 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("time", func(ctx *builtins.Context, args ...string) error {
+		return timeCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "time CMD [ARG...]",
+		Summary:  "run a command and report its resource usage",
+	})
+}
+
+// timeCommand handles the "time" built-in command.
+// It runs a function, builtin or external command and then reports its
+// elapsed real time, user and system CPU time, and maximum resident set size
+// on stderr. External commands are measured from their process state;
+// functions and builtins run inside the shell, so the shell's own resource
+// usage is measured around the whole call instead.
+func timeCommand(ctx *builtins.Context, args ...string) error {
+	var (
+		start     = time.Now()
+		user, sys time.Duration
+		maxRSS    int64
+		err       error
+	)
+	switch {
+	case len(args) == 0:
+	case ctx.IsFunction(args[0]) || builtins.IsBuiltin(args[0]):
+		beforeUser, beforeSys, _ := selfUsage()
+		err = ctx.Run(args[0], args[1:]...)
+		afterUser, afterSys, rss := selfUsage()
+		user, sys, maxRSS = afterUser-beforeUser, afterSys-beforeSys, rss
+	default:
+		if err := checkCommand(ctx, args[0], args[1:]); err != nil {
+			return err
+		}
+		var cmd *exec.Cmd
+		if cmd, err = newCommand(ctx, args[0], args[1:]...); err == nil {
+			err = runProcess(ctx, cmd)
+		}
+		if cmd != nil && cmd.ProcessState != nil {
+			user, sys = cmd.ProcessState.UserTime(), cmd.ProcessState.SystemTime()
+			maxRSS = processMaxRSS(cmd.ProcessState)
+		}
+	}
+	elapsed := time.Since(start)
+
+	_, _ = fmt.Fprintf(ctx.Stderr, "\nreal\t%v\nuser\t%v\nsys\t%v\nmaxrss\t%dKB\n",
+		formatTime(elapsed), formatTime(user), formatTime(sys), maxRSS/1024)
+
+	return err
+}
+
+// reportUsage prints one line with the elapsed time, CPU time and peak memory
+// of a finished process, for set -o cmdstats.
+func reportUsage(w io.Writer, name string, elapsed time.Duration, state *os.ProcessState) {
+	_, _ = fmt.Fprintf(w, "%v: real %v user %v sys %v maxrss %dKB\n", name,
+		formatTime(elapsed), formatTime(state.UserTime()), formatTime(state.SystemTime()), processMaxRSS(state)/1024)
+}
+
+// formatTime renders a duration like the shell time keyword, e.g. 0m1.250s.
+func formatTime(d time.Duration) string {
+	return fmt.Sprintf("%dm%.3fs", int(d.Minutes()), (d % time.Minute).Seconds())
+}
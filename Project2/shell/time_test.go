@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "0m0.000s"},
+		{d: 1250 * time.Millisecond, want: "0m1.250s"},
+		{d: 2*time.Minute + 3*time.Second, want: "2m3.000s"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, formatTime(tt.d))
+	}
+}
+
+func Test_timeCommand(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	ctx := New(strings.NewReader(""), w, &bytes.Buffer{}).context(strings.NewReader(""), w)
+
+	require.NoError(t, timeCommand(ctx, "echo", "timed"))
+	require.Equal(t, "timed\n", w.String())
+	require.Error(t, timeCommand(ctx, "false"))
+}
+
+func TestShell_RunLine_timeFunction(t *testing.T) {
+	t.Parallel()
+	w, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, errs)
+
+	status, err := sh.RunLine("f() { echo in $1; sleep 0.05; return 3; }; time f x")
+	require.Equal(t, 3, status)
+	require.Equal(t, 3, builtins.StatusOf(err))
+	require.Equal(t, "in x\n", w.String())
+	require.Regexp(t, `\nreal\t0m0\.(0[5-9]|[1-9])\d*s\n`, errs.String(), "the whole call is timed")
+}
+
+func TestShell_RunLine_cmdstats(t *testing.T) {
+	t.Parallel()
+	errs := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, errs)
+
+	_, _ = sh.RunLine("sh -c 'exit 0'")
+	require.Empty(t, errs.String())
+
+	_, _ = sh.RunLine("set -o cmdstats; sh -c 'exit 0'; echo builtins are not reported")
+	require.Regexp(t, `^sh: real 0m\d+\.\d{3}s user 0m\d+\.\d{3}s sys 0m\d+\.\d{3}s maxrss \d+KB\n$`, errs.String())
+}
@@ -0,0 +1,198 @@
+package shell
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the expected output of the transcripts with what the shell
+// gives, to be reviewed before committing:
+//
+//	go test ./shell -run TestTranscripts -update
+var update = flag.Bool("update", false, "rewrite the output sections of testdata/transcripts")
+
+// transcriptDir holds the golden transcripts. Each is a comment followed by
+// sections, each starting with a line "-- NAME --":
+//
+//	-- file PATH --   a file to create before the shell starts (any number)
+//	-- stdin --       the lines typed into the shell
+//	-- stdout --      what the shell must write to stdout
+//	-- stderr --      what it must write to stderr
+//	-- status --      the status it must exit with
+//
+// Missing output sections expect nothing, and a missing status expects 0. In
+// the output each prompt is replaced by "$ " and the directory the
+// transcript runs in by $TMP. Each transcript starts in a directory of its
+// own, and the environment is put back after it.
+const transcriptDir = "testdata/transcripts"
+
+// transcript is a golden transcript.
+type transcript struct {
+	comment string
+	files   []transcriptFile
+	stdin   string
+	stdout  string
+	stderr  string
+	status  int
+}
+
+type transcriptFile struct {
+	path string
+	data string
+}
+
+var sectionHeader = regexp.MustCompile(`(?m)^-- ([a-z]+)(?: (\S+))? --\n`)
+
+func parseTranscript(text string) (*transcript, error) {
+	var tr transcript
+	headers := sectionHeader.FindAllStringSubmatchIndex(text, -1)
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("no sections")
+	}
+	tr.comment = text[:headers[0][0]]
+	for i, h := range headers {
+		end := len(text)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		name, body := text[h[2]:h[3]], text[h[1]:end]
+		switch {
+		case name == "file" && h[4] >= 0:
+			tr.files = append(tr.files, transcriptFile{path: text[h[4]:h[5]], data: body})
+		case name == "stdin":
+			tr.stdin = body
+		case name == "stdout":
+			tr.stdout = body
+		case name == "stderr":
+			tr.stderr = body
+		case name == "status":
+			status, err := strconv.Atoi(strings.TrimSpace(body))
+			if err != nil {
+				return nil, fmt.Errorf("status: %w", err)
+			}
+			tr.status = status
+		default:
+			return nil, fmt.Errorf("unknown section %q", text[h[0]:h[1]-1])
+		}
+	}
+
+	return &tr, nil
+}
+
+func (tr *transcript) String() string {
+	var b strings.Builder
+	b.WriteString(tr.comment)
+	for _, f := range tr.files {
+		fmt.Fprintf(&b, "-- file %v --\n%v", f.path, f.data)
+	}
+	fmt.Fprintf(&b, "-- stdin --\n%v", tr.stdin)
+	if tr.stdout != "" {
+		fmt.Fprintf(&b, "-- stdout --\n%v", tr.stdout)
+	}
+	if tr.stderr != "" {
+		fmt.Fprintf(&b, "-- stderr --\n%v", tr.stderr)
+	}
+	if tr.status != 0 {
+		fmt.Fprintf(&b, "-- status --\n%d\n", tr.status)
+	}
+
+	return b.String()
+}
+
+func TestTranscripts(t *testing.T) {
+	// Not parallel: changes the process working directory.
+	paths, err := filepath.Glob(filepath.Join(transcriptDir, "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	u, err := user.Current()
+	require.NoError(t, err)
+	prompt := regexp.MustCompile(`\S+ \[` + regexp.QuoteMeta(u.Username) + `\] (\([^)]*\) )?\$ `)
+
+	for _, path := range paths {
+		path := path
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".txt"), func(t *testing.T) {
+			text, err := os.ReadFile(path)
+			require.NoError(t, err)
+			want, err := parseTranscript(string(text))
+			require.NoError(t, err)
+
+			dir, err := filepath.EvalSymlinks(t.TempDir())
+			require.NoError(t, err)
+			for _, f := range want.files {
+				name := filepath.Join(dir, filepath.FromSlash(f.path))
+				require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+				require.NoError(t, os.WriteFile(name, []byte(f.data), 0o644))
+			}
+			require.NoError(t, os.Chdir(dir))
+			defer func() { require.NoError(t, os.Chdir(wd)) }()
+			defer restoreEnv(t, os.Environ())
+
+			var stdout, stderr bytes.Buffer
+			status := New(strings.NewReader(want.stdin), &stdout, &stderr).Run()
+			normalize := func(out string) string {
+				return strings.ReplaceAll(prompt.ReplaceAllString(out, "$$ "), dir, "$TMP")
+			}
+			got := *want
+			got.stdout, got.stderr, got.status = normalize(stdout.String()), normalize(stderr.String()), status
+
+			if *update {
+				require.NoError(t, os.WriteFile(filepath.Join(wd, path), []byte(got.String()), 0o644))
+				return
+			}
+			require.Equal(t, want.stdout, got.stdout, "stdout")
+			require.Equal(t, want.stderr, got.stderr, "stderr")
+			require.Equal(t, want.status, got.status, "status")
+		})
+	}
+}
+
+// restoreEnv puts back the environment env, undoing what a transcript
+// exported.
+func restoreEnv(t *testing.T, env []string) {
+	t.Helper()
+	os.Clearenv()
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		require.NoError(t, os.Setenv(name, value))
+	}
+}
+
+// TestTranscripts_coverBuiltins checks that each builtin is run by some
+// transcript, so that a new builtin comes with one.
+func TestTranscripts_coverBuiltins(t *testing.T) {
+	t.Parallel()
+	paths, err := filepath.Glob(filepath.Join(transcriptDir, "*.txt"))
+	require.NoError(t, err)
+	used := map[string]bool{}
+	for _, path := range paths {
+		text, err := os.ReadFile(path)
+		require.NoError(t, err)
+		tr, err := parseTranscript(string(text))
+		require.NoError(t, err)
+		for _, field := range strings.FieldsFunc(tr.stdin, func(r rune) bool { return strings.ContainsRune(" \t\n;&|(){}", r) }) {
+			used[field] = true
+		}
+	}
+	var missing []string
+	for _, name := range builtins.Names() {
+		if !used[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(t, missing, "builtins no transcript in %v runs", transcriptDir)
+}
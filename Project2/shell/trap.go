@@ -0,0 +1,70 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("trap", func(ctx *builtins.Context, args ...string) error {
+		return traps.Trap(ctx.Stdout, args...)
+	}, builtins.Meta{
+		Synopsis: "trap [-lp] [ACTION SIGNAL...]",
+		Summary:  "run commands when signals arrive or the shell exits",
+		Flags: []string{
+			"-l\tlist signal names",
+			"-p\tlist the traps",
+			"- SIGNAL\trestore the default",
+			"'' SIGNAL\tignore the signal",
+		},
+	})
+}
+
+// traps holds the shell's "trap" handlers. Signal dispositions belong to the
+// whole process, so there is a single table rather than one per loop.
+var traps = builtins.NewTraps()
+
+// runTrap runs a trap's command in base, reporting any failure on Stderr.
+// Trap commands get an empty stdin so they don't compete with the prompt for
+// input.
+func (s *Shell) runTrap(base context.Context, action string) {
+	if action == "" {
+		return
+	}
+	s.report(s.eval(s.context(strings.NewReader(""), s.Stdout).WithContext(base), action))
+}
+
+// dispatchTraps runs the handlers of trapped signals until done is closed.
+// Holding runMu means a handler waits for the running command to finish, and
+// the next command waits for the handler.
+func (s *Shell) dispatchTraps(done <-chan struct{}) {
+	defer builtins.RestoreTerminalOnPanic()
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-traps.Signals():
+			action, ok := traps.SignalAction(sig)
+			if !ok {
+				continue
+			}
+			s.runMu.Lock()
+			s.runTrap(s.ctx, action)
+			s.runMu.Unlock()
+		}
+	}
+}
+
+// runExitTrap runs and clears the EXIT trap so it only ever runs once.
+func (s *Shell) runExitTrap() {
+	action, ok := traps.Action(builtins.TrapExit)
+	if !ok {
+		return
+	}
+	_ = traps.Trap(io.Discard, "-", builtins.TrapExit)
+	// It runs even once Shutdown has cancelled the shell's commands.
+	s.runTrap(context.Background(), action)
+}
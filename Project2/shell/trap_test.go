@@ -0,0 +1,59 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_Run_exitTrap(t *testing.T) {
+	// Not parallel: the trap table is shared by the whole process.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	w := &bytes.Buffer{}
+	status := New(strings.NewReader("trap pwd EXIT\nexit 5\n"), w, &bytes.Buffer{}).Run()
+	require.Equal(t, 5, status)
+	require.Contains(t, w.String(), wd+"\nexiting gracefully...")
+
+	_, ok := traps.Action("EXIT")
+	require.False(t, ok, "EXIT trap should only run once")
+}
+
+func TestShell_Run_exitTrapOnShutdown(t *testing.T) {
+	// Not parallel: the trap table is shared by the whole process.
+	in, _ := io.Pipe() // input that never ends
+	w := &bytes.Buffer{}
+	sh := New(in, w, &bytes.Buffer{})
+	_, _ = sh.RunLine("trap 'echo trapped' EXIT; false")
+
+	time.AfterFunc(50*time.Millisecond, sh.Shutdown)
+	status := sh.Run()
+	require.Equal(t, 1, status)
+	require.Contains(t, w.String(), "trapped\nexiting gracefully...")
+}
+
+func TestShell_shutdownOnTerm(t *testing.T) {
+	// Not parallel: changes the process's handling of SIGTERM.
+	sh := New(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	stop := sh.shutdownOnTerm()
+	defer stop()
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGTERM))
+	select {
+	case <-sh.ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGTERM did not shut the shell down")
+	}
+	status, exiting := sh.exitRequested()
+	require.True(t, exiting)
+	require.Equal(t, 143, status)
+}
@@ -0,0 +1,53 @@
+package shell
+
+import (
+	"fmt"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+func init() {
+	builtins.Register("unset", func(ctx *builtins.Context, args ...string) error {
+		return unsetCommand(ctx, args...)
+	}, builtins.Meta{
+		Synopsis: "unset [-v] NAME...",
+		Summary:  "remove shell and environment variables",
+		Flags: []string{
+			"-v\tremove variables (the default)",
+		},
+	})
+}
+
+// unsetCommand handles the "unset" built-in command.
+// Each NAME is removed from the shell's variables, arrays and the
+// environment; a name that is not set is not an error. Unsetting IFS puts
+// back the default field splitting at blanks, tabs and newlines, and
+// unsetting OPTIND restarts getopts. A restricted shell can't unset the
+// variables it can't assign.
+func unsetCommand(ctx *builtins.Context, args ...string) error {
+	v, err := shellVars(ctx, "unset")
+	if err != nil {
+		return err
+	}
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		if arg != "-v" {
+			return fmt.Errorf("%w: unset: unknown option %v", builtins.ErrInvalidArgs, arg)
+		}
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: unset needs a variable name", builtins.ErrInvalidArgCount)
+	}
+
+	for _, name := range args {
+		if err := v.Unset(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell_RunLine_unset(t *testing.T) {
+	t.Parallel()
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+
+	for _, line := range []string{
+		`v="a:b c"; IFS=:; printf "[%s]" $v; echo`,
+		`unset IFS; printf "[%s]" $v; echo`,
+		`unset v; echo "[$v]"`,
+		`a=(x y); unset -v a; echo "[${a[1]}] ${#a[@]}"`,
+		`unset nosuch; echo $?`,
+		`x=global; f() { local x=local; unset x; echo "[$x]"; }; f; echo $x`,
+	} {
+		_, err := sh.RunLine(line)
+		require.NoError(t, err, line)
+	}
+	require.Equal(t, "[a][b c]\n[a:b][c]\n[]\n[] 0\n0\n[]\nglobal\n", w.String())
+	for _, name := range []string{"IFS", "v", "a"} {
+		_, ok := sh.vars.Get(name)
+		require.False(t, ok, name)
+	}
+	_, err := sh.RunLine(`set -u; echo $v`)
+	require.Error(t, err, "v is unset, not empty")
+	_, err = sh.RunLine(`set +u`)
+	require.NoError(t, err)
+
+	_, err = sh.RunLine("unset")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgCount)
+	_, err = sh.RunLine("unset -f f")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+	_, err = sh.RunLine("unset 1x")
+	require.ErrorIs(t, err, builtins.ErrInvalidArgs)
+}
+
+func TestShell_RunLine_unsetEnvironment(t *testing.T) {
+	t.Setenv("GOSH_UNSET_TEST", "exported")
+	w := &bytes.Buffer{}
+	sh := New(strings.NewReader(""), w, &bytes.Buffer{})
+	_, err := sh.RunLine(`unset GOSH_UNSET_TEST; echo "[$GOSH_UNSET_TEST]" $(env | grep -c GOSH_UNSET_TEST)`)
+	require.NoError(t, err)
+	require.Equal(t, "[] 0\n", w.String())
+	_, ok := os.LookupEnv("GOSH_UNSET_TEST")
+	require.False(t, ok)
+}
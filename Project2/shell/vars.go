@@ -0,0 +1,430 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+)
+
+// variables are the shell's variables layered over the environment. Setting
+// a variable that is in the environment updates it there, so that commands
+// see the change; others are kept in the shell only. Arrays are kept in the
+// shell only, and as a plain variable an array is its element 0.
+type variables struct {
+	mu     sync.RWMutex
+	local  map[string]string
+	arrays map[string]array
+	frames []*frame // the function calls running, innermost last
+
+	// optPos is how far getopts has got into the argument at OPTIND, a group
+	// of options such as -ab; 0 if it is at the start of one. Setting OPTIND
+	// resets it.
+	optPos int
+	// loops is how many for, while and until loops are running, which break
+	// and continue may leave.
+	loops int
+
+	// check, if set, may refuse an assignment, as a restricted shell does.
+	check func(name string) error
+}
+
+// array is an indexed array variable. Its elements need not be contiguous.
+type array map[int]string
+
+// values returns the elements in the order of their indexes.
+func (a array) values() []string {
+	indexes := make([]int, 0, len(a))
+	for i := range a {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	values := make([]string, len(indexes))
+	for k, i := range indexes {
+		values[k] = a[i]
+	}
+
+	return values
+}
+
+// resolve turns a negative index, counted back from the end, into an index.
+func (a array) resolve(i int) (int, bool) {
+	if i >= 0 {
+		return i, true
+	}
+	end := 0
+	for j := range a {
+		if j >= end {
+			end = j + 1
+		}
+	}
+
+	return end + i, end+i >= 0
+}
+
+// frame is a function call: its positional parameters and the variables it
+// declared local, with the values they hid.
+type frame struct {
+	args  []string
+	saved map[string]savedVar
+}
+
+// savedVar is a variable as it was before a local declaration hid it; nil
+// fields were unset.
+type savedVar struct {
+	local *string
+	array array
+	env   *string
+}
+
+func newVariables() *variables {
+	return &variables{local: map[string]string{}, arrays: map[string]array{}}
+}
+
+// Get returns a shell variable, or else the environment variable of that name.
+func (v *variables) Get(name string) (string, bool) {
+	v.mu.RLock()
+	value, ok := v.local[name]
+	if a, isArray := v.arrays[name]; isArray {
+		value, ok = a[0], true
+	}
+	v.mu.RUnlock()
+	if ok {
+		return value, true
+	}
+
+	return os.LookupEnv(name)
+}
+
+// Set assigns a variable.
+func (v *variables) Set(name, value string) error {
+	if err := v.checkName(name); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	if name == "OPTIND" {
+		v.optPos = 0
+	}
+	if a, ok := v.arrays[name]; ok {
+		a[0] = value
+		v.mu.Unlock()
+		return nil
+	}
+	v.mu.Unlock()
+	if _, exported := os.LookupEnv(name); exported {
+		return os.Setenv(name, value)
+	}
+	v.mu.Lock()
+	v.local[name] = value
+	v.mu.Unlock()
+
+	return nil
+}
+
+// SetArray makes name an array of values, indexed from 0. An environment
+// variable of that name is hidden from the shell but still exported.
+func (v *variables) SetArray(name string, values []string) error {
+	if err := v.checkName(name); err != nil {
+		return err
+	}
+	a := make(array, len(values))
+	for i, value := range values {
+		a[i] = value
+	}
+	v.mu.Lock()
+	delete(v.local, name)
+	v.arrays[name] = a
+	v.mu.Unlock()
+
+	return nil
+}
+
+// SetElement assigns element i of the array name, which a plain variable
+// of that name becomes element 0 of. A negative i counts back from the end.
+func (v *variables) SetElement(name string, i int, value string) error {
+	if err := v.checkName(name); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	a, ok := v.arrays[name]
+	if !ok {
+		a = array{}
+		if old, set := v.local[name]; set {
+			a[0] = old
+		} else if old, set := os.LookupEnv(name); set {
+			a[0] = old
+		}
+	}
+	index, ok := a.resolve(i)
+	if !ok {
+		return fmt.Errorf("%w: %v[%d]: bad array subscript", builtins.ErrInvalidArgs, name, i)
+	}
+	a[index] = value
+	delete(v.local, name)
+	v.arrays[name] = a
+
+	return nil
+}
+
+// Array returns the elements of the array name in order. A plain variable
+// is an array of one element.
+func (v *variables) Array(name string) ([]string, bool) {
+	v.mu.RLock()
+	a, ok := v.arrays[name]
+	var values []string
+	if ok {
+		values = a.values()
+	}
+	v.mu.RUnlock()
+	if ok {
+		return values, true
+	}
+	if value, ok := v.Get(name); ok {
+		return []string{value}, true
+	}
+
+	return nil, false
+}
+
+// elements returns a copy of the elements of the array name by index, and
+// false if name is not an array.
+func (v *variables) elements(name string) (map[int]string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	a, ok := v.arrays[name]
+	if !ok {
+		return nil, false
+	}
+	elems := make(map[int]string, len(a))
+	for i, value := range a {
+		elems[i] = value
+	}
+
+	return elems, true
+}
+
+// Element returns element i of the array name, where a negative i counts
+// back from the end. Element 0 of a plain variable is its value.
+func (v *variables) Element(name string, i int) (string, bool) {
+	v.mu.RLock()
+	a, isArray := v.arrays[name]
+	if isArray {
+		index, ok := a.resolve(i)
+		value, set := a[index]
+		v.mu.RUnlock()
+		return value, ok && set
+	}
+	v.mu.RUnlock()
+	if i != 0 && i != -1 {
+		return "", false
+	}
+
+	return v.Get(name)
+}
+
+// Unset removes the variable name, whether it is a shell variable, an array
+// or in the environment. Unsetting a variable a function declared local
+// leaves it unset until the function returns, which puts it back.
+func (v *variables) Unset(name string) error {
+	if err := v.checkName(name); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	if name == "OPTIND" {
+		v.optPos = 0
+	}
+	delete(v.local, name)
+	delete(v.arrays, name)
+	v.mu.Unlock()
+
+	return os.Unsetenv(name)
+}
+
+// checkName returns an error if name can't be assigned.
+func (v *variables) checkName(name string) error {
+	if !validName(name) {
+		return fmt.Errorf("%w: %q is not a valid variable name", builtins.ErrInvalidArgs, name)
+	}
+	if v.check != nil {
+		return v.check(name)
+	}
+
+	return nil
+}
+
+// Names returns the names of the shell's own variables in sorted order.
+func (v *variables) Names() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	names := make([]string, 0, len(v.local)+len(v.arrays))
+	for name := range v.local {
+		names = append(names, name)
+	}
+	for name := range v.arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// snapshot returns a function restoring the shell variables to their current values.
+func (v *variables) snapshot() func() {
+	v.mu.RLock()
+	saved := make(map[string]string, len(v.local))
+	for name, value := range v.local {
+		saved[name] = value
+	}
+	savedArrays := make(map[string]array, len(v.arrays))
+	for name, a := range v.arrays {
+		copied := make(array, len(a))
+		for i, value := range a {
+			copied[i] = value
+		}
+		savedArrays[name] = copied
+	}
+	v.mu.RUnlock()
+
+	return func() {
+		v.mu.Lock()
+		v.local, v.arrays = saved, savedArrays
+		v.mu.Unlock()
+	}
+}
+
+// pushFrame starts a function call with args as its positional parameters.
+func (v *variables) pushFrame(args []string) {
+	v.mu.Lock()
+	v.frames = append(v.frames, &frame{args: args, saved: map[string]savedVar{}})
+	v.mu.Unlock()
+}
+
+// popFrame ends the innermost function call, restoring the variables it
+// declared local.
+func (v *variables) popFrame() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	f := v.frames[len(v.frames)-1]
+	v.frames = v.frames[:len(v.frames)-1]
+	for name, old := range f.saved {
+		delete(v.local, name)
+		delete(v.arrays, name)
+		if old.local != nil {
+			v.local[name] = *old.local
+		}
+		if old.array != nil {
+			v.arrays[name] = old.array
+		}
+		if old.env != nil {
+			_ = os.Setenv(name, *old.env)
+		} else {
+			_ = os.Unsetenv(name)
+		}
+	}
+}
+
+// depth returns how many function calls are running.
+func (v *variables) depth() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return len(v.frames)
+}
+
+// Local declares name local to the innermost function call, which puts back
+// its value when it returns, and sets it to the empty string. A local
+// variable is not exported, hiding an environment variable of that name from
+// the commands the function runs. Functions the call makes see its local
+// variables.
+func (v *variables) Local(name string) error {
+	if err := v.checkName(name); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.frames) == 0 {
+		return fmt.Errorf("%w: local: can only be used in a function", builtins.ErrInvalidArgs)
+	}
+	f := v.frames[len(v.frames)-1]
+	if _, ok := f.saved[name]; !ok {
+		var old savedVar
+		if value, ok := v.local[name]; ok {
+			old.local = &value
+		}
+		old.array = v.arrays[name]
+		if value, ok := os.LookupEnv(name); ok {
+			old.env = &value
+		}
+		f.saved[name] = old
+	}
+	delete(v.arrays, name)
+	v.local[name] = ""
+
+	return os.Unsetenv(name)
+}
+
+// Locals returns the names declared local in the innermost function call, in
+// sorted order.
+func (v *variables) Locals() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if len(v.frames) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(v.frames[len(v.frames)-1].saved))
+	for name := range v.frames[len(v.frames)-1].saved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Args returns the positional parameters $1, $2, ... of the innermost
+// function call. The shell itself has none.
+func (v *variables) Args() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if len(v.frames) == 0 {
+		return nil
+	}
+
+	return v.frames[len(v.frames)-1].args
+}
+
+// Shift drops the first n positional parameters.
+func (v *variables) Shift(n int) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	count := 0
+	if len(v.frames) > 0 {
+		count = len(v.frames[len(v.frames)-1].args)
+	}
+	if n < 0 || n > count {
+		return fmt.Errorf("%w: shift: %d: shift count out of range", builtins.ErrInvalidArgs, n)
+	}
+	if n > 0 {
+		f := v.frames[len(v.frames)-1]
+		f.args = f.args[n:]
+	}
+
+	return nil
+}
+
+// validName reports whether name can be a variable: a letter or underscore
+// followed by letters, digits and underscores.
+func validName(name string) bool {
+	if name == "" || !isNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isNameChar(name[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+package sshd
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Control characters the line discipline handles.
+const (
+	keyInterrupt = 0x03 // Ctrl-C
+	keyEOF       = 0x04 // Ctrl-D
+	keyBackspace = 0x08 // Ctrl-H
+	keyKill      = 0x15 // Ctrl-U
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// lineReader does what a terminal in cooked mode would for the shell, which
+// reads whole lines: it echoes what is typed, handles backspace, Ctrl-U and
+// Ctrl-C, and passes on the line when Enter is pressed. Ctrl-D on an empty
+// line ends the input. Escape sequences, such as the arrow keys send, are
+// dropped.
+type lineReader struct {
+	r       *bufio.Reader
+	echo    io.Writer
+	pending []byte // a finished line not yet read
+}
+
+func newLineReader(r io.Reader, echo io.Writer) *lineReader {
+	return &lineReader{r: bufio.NewReader(r), echo: echo}
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	if len(l.pending) == 0 {
+		line, err := l.readLine()
+		if err != nil {
+			return 0, err
+		}
+		l.pending = line
+	}
+	n := copy(p, l.pending)
+	l.pending = l.pending[n:]
+
+	return n, nil
+}
+
+// readLine edits a line until it is finished, returning it with a newline.
+func (l *lineReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		c, err := l.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return line, nil
+			}
+			return nil, err
+		}
+		switch {
+		case c == '\r' || c == '\n':
+			if c == '\r' {
+				l.skipByte('\n')
+			}
+			_, _ = io.WriteString(l.echo, "\n")
+			return append(line, '\n'), nil
+		case c == keyDelete || c == keyBackspace:
+			if len(line) > 0 {
+				_, size := utf8.DecodeLastRune(line)
+				line = line[:len(line)-size]
+				_, _ = io.WriteString(l.echo, "\b \b")
+			}
+		case c == keyKill:
+			_, _ = io.WriteString(l.echo, strings.Repeat("\b \b", utf8.RuneCount(line)))
+			line = line[:0]
+		case c == keyInterrupt:
+			// Abandon the line, leaving the shell an empty one.
+			_, _ = io.WriteString(l.echo, "^C\n")
+			return []byte("\n"), nil
+		case c == keyEOF:
+			if len(line) == 0 {
+				return nil, io.EOF
+			}
+		case c == keyEscape:
+			l.skipEscape()
+		case c >= ' ':
+			line = append(line, c)
+			_, _ = l.echo.Write([]byte{c})
+		}
+	}
+}
+
+// skipByte drops the next byte if it is c and has already arrived.
+func (l *lineReader) skipByte(c byte) {
+	if l.r.Buffered() > 0 {
+		if next, _ := l.r.Peek(1); next[0] == c {
+			_, _ = l.r.ReadByte()
+		}
+	}
+}
+
+// skipEscape drops the rest of an escape sequence: ESC [ or ESC O followed
+// by parameters and a final byte from @ to ~.
+func (l *lineReader) skipEscape() {
+	c, err := l.r.ReadByte()
+	if err != nil || c != '[' && c != 'O' {
+		return
+	}
+	for {
+		c, err := l.r.ReadByte()
+		if err != nil || c >= '@' && c <= '~' {
+			return
+		}
+	}
+}
+
+// crlfWriter writes newlines as CRLF, as a terminal in raw mode needs.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,48 @@
+package sshd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lineReader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		typed    string
+		want     string
+		wantEcho string
+	}{
+		{name: "lines", typed: "echo a\recho b\r\n", want: "echo a\necho b\n", wantEcho: "echo a\necho b\n"},
+		{name: "backspace", typed: "ecx\x7fho é\x7f!\r", want: "echo !\n", wantEcho: "ecx\b \bho é\b \b!\n"},
+		{name: "kill", typed: "ls\x15pwd\r", want: "pwd\n", wantEcho: "ls\b \b\b \bpwd\n"},
+		{name: "interrupt", typed: "sleep\x03pwd\r", want: "\npwd\n", wantEcho: "sleep^C\npwd\n"},
+		{name: "arrow keys", typed: "a\x1b[Ab\x1bOBc\r", want: "abc\n", wantEcho: "abc\n"},
+		{name: "Ctrl-D ends an empty line", typed: "pwd\r\x04echo", want: "pwd\n", wantEcho: "pwd\n"},
+		{name: "Ctrl-D is ignored mid-line", typed: "pw\x04d\r", want: "pwd\n", wantEcho: "pwd\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var echo bytes.Buffer
+			got, err := io.ReadAll(newLineReader(strings.NewReader(tt.typed), &echo))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+			require.Equal(t, tt.wantEcho, echo.String())
+		})
+	}
+}
+
+func Test_crlfWriter(t *testing.T) {
+	t.Parallel()
+	var b bytes.Buffer
+	n, err := crlfWriter{&b}.Write([]byte("a\nb\n"))
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, "a\r\nb\r\n", b.String())
+}
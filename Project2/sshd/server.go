@@ -0,0 +1,327 @@
+// Package sshd serves the shell over SSH to clients holding an authorized
+// key, so that it can be used as a login shell without being installed
+// system-wide.
+package sshd
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project2/builtins"
+	"github.com/jar0582/CSCE4600/Project2/shell"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// defaultHostKey is where the host key is kept, under the home
+	// directory. It is made on first use.
+	defaultHostKey = ".gosh/ssh_host_ed25519_key"
+	// defaultAuthorizedKeys is the keys allowed in, under the home directory.
+	defaultAuthorizedKeys = ".ssh/authorized_keys"
+)
+
+// extFingerprint is the permissions extension the fingerprint of the key a
+// client authenticated with is kept in.
+const extFingerprint = "gosh-key-fingerprint"
+
+var errUnauthorized = errors.New("key not authorized")
+
+// Options configure the server and the shell that is served.
+type Options struct {
+	// HostKey is the file of the server's private key, by default
+	// ~/.gosh/ssh_host_ed25519_key. An ed25519 key is made if it is missing.
+	HostKey string
+	// AuthorizedKeys is the file of the public keys allowed in, one per
+	// line as in OpenSSH, by default ~/.ssh/authorized_keys. Keys with
+	// options, such as command=, from=, restrict or no-pty, are skipped:
+	// the server can't honour them, and must not give such a key a shell.
+	AuthorizedKeys string
+	// Restricted restricts the shell, as gosh -r does.
+	Restricted bool
+	// Allow, if not empty, is the only commands the shell may run. The
+	// shell is restricted as well.
+	Allow []string
+	// Audit, if set, gets a JSON line for each command the shell runs. The
+	// user recorded is the SSH user name, the fingerprint of the key it
+	// authenticated with and the client's address.
+	Audit io.Writer
+}
+
+// Server runs a shell for each SSH session, interactively or for the command
+// given to ssh. It runs one session at a time, as the shell's working
+// directory, environment and jobs belong to the process.
+type Server struct {
+	// HostKey is the public key clients see the server by.
+	HostKey ssh.PublicKey
+
+	config *ssh.ServerConfig
+	opts   Options
+	busy   chan struct{}
+}
+
+// NewServer returns a server with the keys opts names.
+func NewServer(opts Options) (*Server, error) {
+	home, err := os.UserHomeDir()
+	if err != nil && (opts.HostKey == "" || opts.AuthorizedKeys == "") {
+		return nil, err
+	}
+	if opts.HostKey == "" {
+		opts.HostKey = filepath.Join(home, defaultHostKey)
+	}
+	if opts.AuthorizedKeys == "" {
+		opts.AuthorizedKeys = filepath.Join(home, defaultAuthorizedKeys)
+	}
+	signer, err := hostKey(opts.HostKey)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := authorizedKeys(opts.AuthorizedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if keys[string(key.Marshal())] {
+				return &ssh.Permissions{Extensions: map[string]string{extFingerprint: ssh.FingerprintSHA256(key)}}, nil
+			}
+			return nil, errUnauthorized
+		},
+	}
+	config.AddHostKey(signer)
+
+	return &Server{HostKey: signer.PublicKey(), config: config, opts: opts, busy: make(chan struct{}, 1)}, nil
+}
+
+// ListenAndServe serves the shell at addr, such as ":2222", until the
+// listener fails. It prints the address and the host key's fingerprint on w.
+func ListenAndServe(addr string, opts Options, w io.Writer) error {
+	srv, err := NewServer(opts)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	_, _ = fmt.Fprintf(w, "serving the shell over SSH at %v (host key %v)\n", ln.Addr(), ssh.FingerprintSHA256(srv.HostKey))
+
+	return srv.Serve(ln)
+}
+
+// Serve accepts connections on ln until it fails.
+func (srv *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.serveConn(conn)
+	}
+}
+
+// serveConn runs the sessions of one connection.
+func (srv *Server) serveConn(conn net.Conn) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, srv.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+	user := fmt.Sprintf("%v (key %v) from %v", sconn.User(), sconn.Permissions.Extensions[extFingerprint], sconn.RemoteAddr())
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, chReqs, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go srv.serveSession(ch, chReqs, user)
+	}
+}
+
+// serveSession answers the requests of a session channel, running the shell
+// for user when asked for one or for a command and sending its exit status.
+// A shell still running when the session ends is shut down.
+func (srv *Server) serveSession(ch ssh.Channel, reqs <-chan *ssh.Request, user string) {
+	defer ch.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var pty, started bool
+	done := make(chan int, 1)
+	for {
+		select {
+		case req, ok := <-reqs:
+			if !ok {
+				return
+			}
+			switch {
+			case req.Type == "pty-req" && !started:
+				pty = true
+				_ = req.Reply(true, nil)
+			case (req.Type == "shell" || req.Type == "exec") && !started:
+				var command *string
+				if req.Type == "exec" {
+					var payload struct{ Command string }
+					if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+						_ = req.Reply(false, nil)
+						continue
+					}
+					command = &payload.Command
+				}
+				select {
+				case srv.busy <- struct{}{}:
+				default:
+					_ = req.Reply(false, nil)
+					_, _ = fmt.Fprintln(ch.Stderr(), "a session is already running")
+					return
+				}
+				started = true
+				_ = req.Reply(true, nil)
+				go func() {
+					defer func() { <-srv.busy }()
+					done <- srv.run(ctx, ch, pty, command, user)
+				}()
+			case req.WantReply:
+				// env, window-change and the like don't apply.
+				_ = req.Reply(false, nil)
+			}
+		case status := <-done:
+			_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+			return
+		}
+	}
+}
+
+// run runs a shell on ch for user, who the audit log names, for command if
+// it isn't nil, and returns its exit status, shutting the shell down if ctx is cancelled first. With a pty the
+// client's terminal is raw, so the line is edited here and newlines written
+// as CRLF.
+func (srv *Server) run(ctx context.Context, ch ssh.Channel, pty bool, command *string, user string) int {
+	var (
+		in     io.Reader = ch
+		stdout io.Writer = ch
+		stderr io.Writer = ch.Stderr()
+	)
+	if pty {
+		stdout = crlfWriter{ch}
+		stderr = stdout
+		in = newLineReader(ch, stdout)
+	}
+
+	sh := shell.New(in, stdout, stderr)
+	if srv.opts.Restricted {
+		sh.Restrict()
+	}
+	if len(srv.opts.Allow) > 0 {
+		sh.AllowOnly(srv.opts.Allow...)
+	}
+	if srv.opts.Audit != nil {
+		sh.AuditAs(srv.opts.Audit, user)
+	}
+	go func() {
+		<-ctx.Done()
+		sh.Shutdown()
+	}()
+	if command == nil {
+		return sh.Run()
+	}
+	if _, err := sh.RunLine(*command); err != nil && !builtins.IsStatusOnly(err) {
+		_, _ = fmt.Fprintln(stderr, err)
+	}
+
+	return sh.Status()
+}
+
+// hostKey reads the private key at path, first making an ed25519 key there
+// if there is none.
+func hostKey(path string) (ssh.Signer, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b, err = newHostKey(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+
+	return signer, nil
+}
+
+// newHostKey writes a new ed25519 private key to path and returns it.
+func newHostKey(path string) ([]byte, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(key, "gosh host key")
+	if err != nil {
+		return nil, err
+	}
+	b := pem.EncodeToMemory(block)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	return b, os.WriteFile(path, b, 0o600)
+}
+
+// authorizedKeys reads the public keys in an authorized_keys file, skipping
+// blank lines and comments, and keys with options, which OpenSSH uses to
+// limit what a key may do. The file must hold at least one key without
+// options.
+func authorizedKeys(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := map[string]bool{}
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%v:%d: %w", path, n, err)
+		}
+		if len(options) > 0 {
+			skipped++
+			continue
+		}
+		keys[string(key.Marshal())] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 && skipped > 0 {
+		return nil, fmt.Errorf("%v: no keys without options, which are not supported", path)
+	} else if len(keys) == 0 {
+		return nil, fmt.Errorf("%v: no keys", path)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,152 @@
+package sshd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestServer(t *testing.T) {
+	// Not parallel: the sessions run shells, whose state belongs to the process.
+	dir := t.TempDir()
+	client := newSigner(t)
+	authorized := filepath.Join(dir, "authorized_keys")
+	require.NoError(t, os.WriteFile(authorized, append([]byte("# lab users\n\n"), ssh.MarshalAuthorizedKey(client.PublicKey())...), 0o600))
+	hostKeyFile := filepath.Join(dir, "keys", "host")
+
+	var audit safeBuffer
+	srv, err := NewServer(Options{HostKey: hostKeyFile, AuthorizedKeys: authorized, Allow: []string{"echo", "exit"}, Audit: &audit})
+	require.NoError(t, err)
+	again, err := NewServer(Options{HostKey: hostKeyFile, AuthorizedKeys: authorized})
+	require.NoError(t, err)
+	require.Equal(t, srv.HostKey.Marshal(), again.HostKey.Marshal(), "the host key is kept")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() { _ = srv.Serve(ln) }()
+
+	_, err = ssh.Dial("tcp", ln.Addr().String(), clientConfig(newSigner(t), srv.HostKey))
+	require.Error(t, err, "an unknown key is refused")
+
+	conn, err := ssh.Dial("tcp", ln.Addr().String(), clientConfig(client, srv.HostKey))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Run("exec", func(t *testing.T) {
+		session, err := conn.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		out, err := session.CombinedOutput("echo hello over ssh")
+		require.NoError(t, err)
+		require.Equal(t, "hello over ssh\n", string(out))
+
+		session, err = conn.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		out, err = session.CombinedOutput("pwd")
+		var exitErr *ssh.ExitError
+		require.True(t, errors.As(err, &exitErr), "err = %v", err)
+		require.NotZero(t, exitErr.ExitStatus())
+		require.Contains(t, string(out), "not an allowed command")
+		require.Contains(t, audit.String(), `"user":"student (key `+ssh.FingerprintSHA256(client.PublicKey())+`) from 127.0.0.1:`,
+			"the audit log names the client, not the server's user")
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		session, err := conn.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		require.NoError(t, session.RequestPty("xterm", 24, 80, ssh.TerminalModes{}))
+		stdin, err := session.StdinPipe()
+		require.NoError(t, err)
+		var out safeBuffer
+		session.Stdout = &out
+		require.NoError(t, session.Shell())
+
+		_, err = stdin.Write([]byte("echo hi\r"))
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return strings.Contains(out.String(), "echo hi\r\nhi\r\n") },
+			5*time.Second, 10*time.Millisecond, "output: %q", out.String())
+		_, err = stdin.Write([]byte{keyEOF})
+		require.NoError(t, err)
+		require.NoError(t, session.Wait())
+		require.Contains(t, out.String(), "exiting gracefully...\r\n")
+	})
+}
+
+func Test_authorizedKeys(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	require.NoError(t, os.WriteFile(path, []byte("# nobody yet\n"), 0o600))
+	_, err := authorizedKeys(path)
+	require.ErrorContains(t, err, "no keys")
+
+	require.NoError(t, os.WriteFile(path, []byte("ssh-ed25519 not-base64\n"), 0o600))
+	_, err = authorizedKeys(path)
+	require.ErrorContains(t, err, path+":1:")
+
+	// A key limited with options gets no shell at all.
+	restricted, plain := newSigner(t).PublicKey(), newSigner(t).PublicKey()
+	line := func(options string, key ssh.PublicKey) string {
+		return strings.TrimSpace(options + " " + string(ssh.MarshalAuthorizedKey(key)))
+	}
+	require.NoError(t, os.WriteFile(path, []byte(line("restrict", restricted)+"\n"), 0o600))
+	_, err = authorizedKeys(path)
+	require.ErrorContains(t, err, "no keys without options")
+
+	data := line("restrict", restricted) + "\n" +
+		line(`command="date",from="10.0.0.1",no-pty`, restricted) + "\n" +
+		line("", plain) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	keys, err := authorizedKeys(path)
+	require.NoError(t, err)
+	require.False(t, keys[string(restricted.Marshal())])
+	require.True(t, keys[string(plain.Marshal())])
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	return signer
+}
+
+func clientConfig(signer ssh.Signer, hostKey ssh.PublicKey) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            "student",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	}
+}
+
+// safeBuffer is a strings.Builder that can be written and read concurrently.
+type safeBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *safeBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *safeBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
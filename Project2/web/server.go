@@ -0,0 +1,152 @@
+// Package web serves the shell to a browser: a page running xterm.js that
+// talks to a shell.Shell over a WebSocket, so that it can be shown without a
+// terminal.
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/jar0582/CSCE4600/Project2/shell"
+)
+
+//go:embed terminal.html
+var terminalPage []byte
+
+// Options configure the shell that is served.
+type Options struct {
+	// Restricted restricts the shell, as gosh -r does.
+	Restricted bool
+	// Allow, if not empty, is the only commands the shell may run. The
+	// shell is restricted as well.
+	Allow []string
+	// Audit, if set, gets a JSON line for each command the shell runs. As
+	// the token is all a client authenticates with, the user recorded is
+	// the client's address.
+	Audit io.Writer
+}
+
+// Server serves the terminal page at / and a shell session over the
+// WebSocket at /ws to clients presenting its token. It runs one session at a
+// time, as the shell's working directory, environment and jobs belong to
+// the process.
+type Server struct {
+	// Token must be given as the token query parameter of both URLs.
+	Token string
+
+	opts Options
+	busy chan struct{}
+}
+
+// NewServer returns a server with a random token.
+func NewServer(opts Options) (*Server, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return &Server{Token: hex.EncodeToString(b), opts: opts, busy: make(chan struct{}, 1)}, nil
+}
+
+// ListenAndServe serves the shell at addr, such as ":9000", until the
+// listener fails. It prints the URL to open, with the token, on w.
+func ListenAndServe(addr string, opts Options, w io.Writer) error {
+	srv, err := NewServer(opts)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	_, _ = fmt.Fprintf(w, "serving the shell at http://%v/?token=%v\n", net.JoinHostPort(host, port), srv.Token)
+
+	return http.Serve(ln, srv)
+}
+
+// ServeHTTP serves the terminal page and the shell's WebSocket.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(srv.Token)) != 1 {
+		http.Error(w, "missing or wrong token", http.StatusForbidden)
+		return
+	}
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(terminalPage)
+	case "/ws":
+		srv.serveSession(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSession runs a shell over the WebSocket of r until the shell exits or
+// the client goes away.
+func (srv *Server) serveSession(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return
+	}
+	select {
+	case srv.busy <- struct{}{}:
+		defer func() { <-srv.busy }()
+	default:
+		http.Error(w, "a session is already running", http.StatusServiceUnavailable)
+		return
+	}
+	sock, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer sock.close()
+
+	in, input := io.Pipe()
+	defer in.Close()
+	sh := shell.New(in, sock, sock)
+	if srv.opts.Restricted {
+		sh.Restrict()
+	}
+	if len(srv.opts.Allow) > 0 {
+		sh.AllowOnly(srv.opts.Allow...)
+	}
+	if srv.opts.Audit != nil {
+		sh.AuditAs(srv.opts.Audit, "web client "+r.RemoteAddr)
+	}
+	go func() {
+		_, err := io.Copy(input, sock)
+		input.CloseWithError(err)
+		// The browser has gone, so stop whatever it left running.
+		sh.Shutdown()
+	}()
+	sh.Run()
+}
+
+// sameOrigin reports whether a WebSocket request comes from a page of the
+// server itself, so that other sites can't open sessions from a visitor's
+// browser. Clients that aren't browsers send no Origin.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == r.Host
+}
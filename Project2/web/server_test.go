@@ -0,0 +1,133 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	var audit safeBuffer
+	srv, err := NewServer(Options{Allow: []string{"echo", "exit"}, Audit: &audit})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/?token=" + srv.Token)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = http.Get(ts.URL + "/?token=wrong")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, r := dialSession(t, ts.URL, srv.Token, "")
+	defer conn.Close()
+	_, err = conn.Write(clientFrame(opText, []byte("echo hello from the web\npwd\n")))
+	require.NoError(t, err)
+	out := readUntil(t, r, "not an allowed command")
+	require.Contains(t, out, "hello from the web\n")
+
+	status := handshake(t, ts.URL, srv.Token, "")
+	require.Contains(t, status, "503", "one session runs at a time")
+
+	_, err = conn.Write(clientFrame(opText, []byte("exit\n")))
+	require.NoError(t, err)
+	readUntil(t, r, "exiting gracefully...")
+	require.Contains(t, audit.String(), `"user":"web client 127.0.0.1:`, "the audit log names the client, not the server's user")
+}
+
+func TestServer_crossOrigin(t *testing.T) {
+	srv, err := NewServer(Options{})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	status := handshake(t, ts.URL, srv.Token, "http://evil.example")
+	require.Contains(t, status, "403")
+}
+
+// dialSession opens a WebSocket to the server at url.
+func dialSession(t *testing.T, url, token, origin string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(conn, "GET /ws?token=%v HTTP/1.1\r\nHost: %v\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n", token, strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	if origin != "" {
+		_, err = fmt.Fprintf(conn, "Origin: %v\r\n", origin)
+		require.NoError(t, err)
+	}
+	_, err = fmt.Fprint(conn, "\r\n")
+	require.NoError(t, err)
+
+	r := bufio.NewReader(conn)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\r\n" {
+			return conn, r
+		}
+	}
+}
+
+// handshake tries to open a WebSocket and returns the status line.
+func handshake(t *testing.T, url, token, origin string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url+"/ws?token="+token, nil)
+	require.NoError(t, err)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return resp.Status
+}
+
+// readUntil reads frames until their text contains want, returning the text.
+func readUntil(t *testing.T, r *bufio.Reader, want string) string {
+	t.Helper()
+	var b strings.Builder
+	for !strings.Contains(b.String(), want) {
+		_, payload, err := readServerFrame(r)
+		require.NoError(t, err, "read so far: %q", b.String())
+		b.Write(payload)
+	}
+
+	return b.String()
+}
+
+// safeBuffer is a strings.Builder that can be written and read concurrently.
+type safeBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *safeBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *safeBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
@@ -0,0 +1,219 @@
+package web
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is appended to the client's key to make the accept key.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxFrame is the largest frame read, which is far more than a line of
+// typing needs.
+const maxFrame = 1 << 20
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// errFrameTooLarge is returned for a frame over maxFrame bytes.
+var errFrameTooLarge = errors.New("websocket: frame too large")
+
+// socket is a server's WebSocket connection. Reads return the payloads of
+// the data frames received, answering pings on the way, and writes send
+// binary frames, so that output split inside a UTF-8 character still goes
+// through.
+type socket struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	mu     sync.Mutex // serializes writes
+	closed bool
+
+	payload []byte // what is left of the last data frame
+}
+
+// upgrade answers a WebSocket handshake and takes over its connection.
+func upgrade(w http.ResponseWriter, r *http.Request) (*socket, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" ||
+		!headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection can't be upgraded", http.StatusInternalServerError)
+		return nil, errors.New("websocket: connection can't be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %v\r\n\r\n", acceptKey(key))
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &socket{conn: conn, r: rw.Reader}, nil
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for a client's key. The
+// protocol fixes SHA-1 here; it proves nothing secret.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHas reports whether a comma-separated header lists token, ignoring
+// case.
+func headerHas(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Read reads the payloads of data frames. It returns io.EOF once the client
+// closes the connection.
+func (s *socket) Read(p []byte) (int, error) {
+	for len(s.payload) == 0 {
+		op, payload, err := s.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case opText, opBinary, opContinuation:
+			s.payload = payload
+		case opPing:
+			if err := s.writeFrame(opPong, payload); err != nil {
+				return 0, err
+			}
+		case opClose:
+			_ = s.close()
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, s.payload)
+	s.payload = s.payload[n:]
+
+	return n, nil
+}
+
+// readFrame reads one frame, unmasking its payload.
+func (s *socket) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(s.r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	op := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(s.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(s.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > maxFrame {
+		return 0, nil, errFrameTooLarge
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(s.r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(s.r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return op, payload, nil
+}
+
+// Write sends p as a binary frame.
+func (s *socket) Write(p []byte) (int, error) {
+	if err := s.writeFrame(opBinary, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeFrame sends one unmasked frame, as a server does.
+func (s *socket) writeFrame(op byte, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return net.ErrClosed
+	}
+
+	head := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, byte(n))
+	case n <= 0xFFFF:
+		head = append(head, 126, byte(n>>8), byte(n))
+	default:
+		head = append(head, 127)
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	if _, err := s.conn.Write(head); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(payload)
+
+	return err
+}
+
+// close sends a close frame, if it hasn't been sent, and closes the
+// connection.
+func (s *socket) close() error {
+	_ = s.writeFrame(opClose, []byte{0x03, 0xE8}) // 1000, a normal closure
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	return s.conn.Close()
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_acceptKey(t *testing.T) {
+	t.Parallel()
+	// The example from RFC 6455, section 1.3.
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func Test_socket(t *testing.T) {
+	t.Parallel()
+	server, client := net.Pipe()
+	s := &socket{conn: server, r: bufio.NewReader(server)}
+	go func() {
+		_, _ = client.Write(clientFrame(opText, []byte("hello ")))
+		_, _ = client.Write(clientFrame(opPing, []byte("p")))
+		_, _ = client.Write(clientFrame(opBinary, bytes.Repeat([]byte("x"), 300)))
+		_, _ = client.Write(clientFrame(opClose, nil))
+	}()
+	go func() {
+		// The pong and the close frame.
+		for i := 0; i < 2; i++ {
+			if _, _, err := readServerFrame(client); err != nil {
+				return
+			}
+		}
+	}()
+
+	got, err := io.ReadAll(s)
+	require.NoError(t, err)
+	require.Equal(t, "hello "+string(bytes.Repeat([]byte("x"), 300)), string(got))
+}
+
+func Test_socketFrameTooLarge(t *testing.T) {
+	t.Parallel()
+	head := []byte{0x80 | opBinary, 0x80 | 127}
+	head = binary.BigEndian.AppendUint64(head, maxFrame+1)
+	s := &socket{r: bufio.NewReader(bytes.NewReader(head))}
+	_, err := s.Read(make([]byte, 1))
+	require.ErrorIs(t, err, errFrameTooLarge)
+}
+
+// clientFrame returns a masked frame, as a client sends.
+func clientFrame(op byte, payload []byte) []byte {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	default:
+		frame = append(frame, 0x80|126, byte(n>>8), byte(n))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	return frame
+}
+
+// readServerFrame reads an unmasked frame.
+func readServerFrame(r io.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	size := int(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, size)
+	_, err := io.ReadFull(r, payload)
+
+	return head[0] & 0x0F, payload, err
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project3/memory"
+	"github.com/olekukonko/tablewriter"
+)
+
+// allocConfig is how the allocators of the alloc simulation are set up.
+type allocConfig struct {
+	heapSize int
+	minBlock int   // the smallest block of the buddy allocator
+	slabSize int   // the size of the slab allocator's slabs
+	classes  []int // the object sizes of the slab allocator's caches
+}
+
+// allocCommand runs a trace of allocation requests against each allocator
+// chosen with -policy, printing the heap after every step, then compares the
+// allocators.
+func allocCommand(w io.Writer, args ...string) error {
+	var cfg allocConfig
+	fs := flag.NewFlagSet("alloc", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.IntVar(&cfg.heapSize, "heap", 1024, "the size of the heap in `UNITS`")
+	policy := fs.String("policy", "all", "the `ALLOCATOR`: first, best, worst or next fit, buddy, slab, or all")
+	fs.IntVar(&cfg.minBlock, "min", 16, "the smallest block of the buddy allocator in `UNITS`")
+	fs.IntVar(&cfg.slabSize, "slab", 128, "the size of the slab allocator's slabs in `UNITS`")
+	classes := fs.String("classes", "16,32,64,128", "the object sizes of the slab allocator's caches, as comma-separated `UNITS`")
+	width := fs.Int("width", 64, "the width of the memory map in `COLUMNS`")
+	quiet := fs.Bool("q", false, "only print the comparison, not each step")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if cfg.heapSize <= 0 || *width <= 0 {
+		return fmt.Errorf("%w: -heap and -width must be above 0", ErrInvalidArgs)
+	}
+	for _, class := range strings.Split(*classes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(class))
+		if err != nil {
+			return fmt.Errorf("%w: -classes: %q is not a number", ErrInvalidArgs, class)
+		}
+		cfg.classes = append(cfg.classes, n)
+	}
+	allocators, err := newAllocators(*policy, cfg)
+	if err != nil {
+		return err
+	}
+
+	f, err := openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	trace, err := memory.LoadTrace(f)
+	if err != nil {
+		return err
+	}
+
+	sym := memory.NewSymbols(trace)
+	results := make([]memory.Result, len(allocators))
+	for i, a := range allocators {
+		results[i] = memory.Simulate(a, trace)
+		if !*quiet {
+			outputTitle(w, strings.ToUpper(results[i].Name[:1])+results[i].Name[1:])
+			outputSteps(w, results[i], *width, sym)
+		}
+	}
+	outputComparison(w, results)
+	for _, a := range allocators {
+		if s, ok := a.(*memory.Slab); ok {
+			outputCaches(w, s.Caches())
+		}
+	}
+
+	return nil
+}
+
+// newAllocators returns the allocators name picks: a heap with a placement
+// policy, the buddy or the slab allocator, or all of them, leaving out the
+// buddy allocator unless the heap is a power of two and the slab allocator
+// unless its slabs divide the heap.
+func newAllocators(name string, cfg allocConfig) ([]memory.Allocator, error) {
+	var allocators []memory.Allocator
+	buddy := name == "buddy" || name == "all" && cfg.heapSize&(cfg.heapSize-1) == 0
+	slab := name == "slab" || name == "all" && cfg.slabSize > 0 && cfg.heapSize%cfg.slabSize == 0
+	switch name {
+	case "all":
+		for _, p := range memory.Policies {
+			allocators = append(allocators, memory.NewHeap(cfg.heapSize, p))
+		}
+	case "buddy", "slab":
+	default:
+		p, err := memory.ParsePolicy(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		return []memory.Allocator{memory.NewHeap(cfg.heapSize, p)}, nil
+	}
+	if buddy {
+		b, err := memory.NewBuddy(cfg.heapSize, cfg.minBlock)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		allocators = append(allocators, b)
+	}
+	if slab {
+		s, err := memory.NewSlab(cfg.heapSize, cfg.slabSize, cfg.classes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		allocators = append(allocators, s)
+	}
+
+	return allocators, nil
+}
+
+// outputSteps prints each step of a run with a map of the heap after it.
+func outputSteps(w io.Writer, res memory.Result, width int, sym memory.Symbols) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Step", "Request", "Result", "Memory map", "Free", "Largest", "Ext frag", "Int frag"})
+	table.SetAutoWrapText(false)
+	for i, step := range res.Steps {
+		result := "ok"
+		switch {
+		case step.Err != nil:
+			result = "FAILED"
+		case step.Request.Op == memory.OpAlloc:
+			result = fmt.Sprintf("@%d", step.Addr)
+		}
+		table.Append([]string{
+			fmt.Sprint(i + 1),
+			step.Request.String(),
+			result,
+			"|" + memory.MemoryMap(step.Blocks, res.Size, width, sym) + "|",
+			fmt.Sprint(step.Usage.Free),
+			fmt.Sprint(step.Usage.LargestFree),
+			fmt.Sprintf("%.2f", step.Usage.ExternalFragmentation()),
+			fmt.Sprintf("%.2f", step.Usage.InternalFragmentation()),
+		})
+	}
+	table.Render()
+}
+
+// outputComparison prints how each allocator did over the trace.
+func outputComparison(w io.Writer, results []memory.Result) {
+	_, _ = fmt.Fprintln(w, "Comparison")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Allocator", "Failed", "Free blocks", "Peak ext frag", "Mean ext frag",
+		"Peak int frag", "Mean int frag", "Splits", "Merges"})
+	for _, res := range results {
+		final := res.Final()
+		table.Append([]string{
+			res.Name,
+			fmt.Sprint(res.Failed),
+			fmt.Sprint(final.FreeBlocks),
+			fmt.Sprintf("%.2f", res.PeakExternal),
+			fmt.Sprintf("%.2f", res.MeanExternal),
+			fmt.Sprintf("%.2f", res.PeakInternal),
+			fmt.Sprintf("%.2f", res.MeanInternal),
+			fmt.Sprint(res.Stats.Splits),
+			fmt.Sprint(res.Stats.Merges),
+		})
+	}
+	table.Render()
+}
+
+// outputCaches prints how each cache of a slab allocator was used: how often
+// allocations reused a slab the cache had, against how often slabs were taken
+// from and given back to the heap.
+func outputCaches(w io.Writer, caches []memory.CacheStats) {
+	_, _ = fmt.Fprintln(w, "Slab caches")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Object size", "Per slab", "Allocs", "Reused", "Slab allocs", "Slab frees", "Peak slabs"})
+	for _, c := range caches {
+		size, perSlab := fmt.Sprint(c.Size), fmt.Sprint(c.PerSlab)
+		if c.Size == 0 {
+			size, perSlab = "large", "-"
+		}
+		reused := "-"
+		if c.Allocs > 0 {
+			reused = fmt.Sprintf("%d (%.0f%%)", c.Reuses, 100*float64(c.Reuses)/float64(c.Allocs))
+		}
+		table.Append([]string{
+			size,
+			perSlab,
+			fmt.Sprint(c.Allocs),
+			reused,
+			fmt.Sprint(c.SlabAllocs),
+			fmt.Sprint(c.SlabFrees),
+			fmt.Sprint(c.PeakSlabs),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidArgs is returned for command lines that can't be run.
+var ErrInvalidArgs = errors.New("invalid args")
+
+// command is a simulation run by its name, as the first argument.
+type command struct {
+	summary string
+	run     func(w io.Writer, args ...string) error
+}
+
+var commands = map[string]command{
+	"alloc":      {summary: "run a trace of malloc/free requests against heap allocators", run: allocCommand},
+	"paging":     {summary: "run a page reference string through page replacement algorithms", run: pagingCommand},
+	"tlb":        {summary: "translate virtual addresses through a TLB and a two-level page table", run: tlbCommand},
+	"workingset": {summary: "share frames among processes with the working set and PFF policies", run: workingSetCommand},
+}
+
+func main() {
+	if err := run(os.Stdout, os.Args[1:]...); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			// -h printed the usage, as asked.
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run runs the simulation args name, with the rest of args, writing to w.
+func run(w io.Writer, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: must give a simulation to run: %v", ErrInvalidArgs, strings.Join(commandNames(), ", "))
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+			usage(w)
+			return nil
+		}
+		return fmt.Errorf("%w: unknown simulation %q", ErrInvalidArgs, args[0])
+	}
+
+	return cmd.run(w, args[1:]...)
+}
+
+// flagError returns the error from parsing a simulation's flags: flag.ErrHelp
+// as it is for -h, after the flags' usage, and otherwise an ErrInvalidArgs.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+}
+
+// usage lists the simulations.
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "usage: Project3 SIMULATION [FLAGS] [FILE]")
+	for _, name := range commandNames() {
+		_, _ = fmt.Fprintf(w, "  %-12v %v\n", name, commands[name].summary)
+	}
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// openInput opens the file named by args, or returns stdin for none or "-".
+func openInput(args []string) (io.ReadCloser, error) {
+	switch {
+	case len(args) > 1:
+		return nil, fmt.Errorf("%w: too many files: %v", ErrInvalidArgs, strings.Join(args, " "))
+	case len(args) == 0 || args[0] == "-":
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening input file", err)
+	}
+
+	return f, nil
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
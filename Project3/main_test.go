@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the fixtures in testdata")
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "alloc",
+			args:    []string{"alloc", "-width", "32", "example_trace.csv"},
+			wantOut: loadFixture(t, "testdata", "alloc.txt"),
+		},
+		{
+			name:    "slab",
+			args:    []string{"alloc", "-policy", "slab", "-width", "32", "example_objects.csv"},
+			wantOut: loadFixture(t, "testdata", "slab.txt"),
+		},
+		{
+			name:    "paging",
+			args:    []string{"paging", "example_refs.txt"},
+			wantOut: loadFixture(t, "testdata", "paging.txt"),
+		},
+		{
+			name:    "tlb",
+			args:    []string{"tlb", "-tlb", "2", "example_addresses.txt"},
+			wantOut: loadFixture(t, "testdata", "tlb.txt"),
+		},
+		{
+			name:    "workingset",
+			args:    []string{"workingset", "example_processes.txt"},
+			wantOut: loadFixture(t, "testdata", "workingset.txt"),
+		},
+		{name: "no simulation", wantErr: ErrInvalidArgs},
+		{name: "unknown simulation", args: []string{"defrag"}, wantErr: ErrInvalidArgs},
+		{name: "unknown policy", args: []string{"alloc", "-policy", "random", "example_trace.csv"}, wantErr: ErrInvalidArgs},
+		{name: "bad heap", args: []string{"alloc", "-heap", "0", "example_trace.csv"}, wantErr: ErrInvalidArgs},
+		{name: "buddy heap", args: []string{"alloc", "-policy", "buddy", "-heap", "1000", "example_trace.csv"}, wantErr: ErrInvalidArgs},
+		{name: "buddy min", args: []string{"alloc", "-min", "24", "example_trace.csv"}, wantErr: ErrInvalidArgs},
+		{name: "slab size", args: []string{"alloc", "-policy", "slab", "-slab", "100", "example_objects.csv"}, wantErr: ErrInvalidArgs},
+		{name: "slab classes", args: []string{"alloc", "-classes", "16,x", "example_objects.csv"}, wantErr: ErrInvalidArgs},
+		{name: "paging frames", args: []string{"paging", "-frames", "0", "example_refs.txt"}, wantErr: ErrInvalidArgs},
+		{name: "paging policy", args: []string{"paging", "-policy", "random", "example_refs.txt"}, wantErr: ErrInvalidArgs},
+		{name: "tlb page", args: []string{"tlb", "-page", "1000", "example_addresses.txt"}, wantErr: ErrInvalidArgs},
+		{name: "tlb address", args: []string{"tlb", "-bits", "16", "example_addresses.txt"}, wantErr: ErrInvalidArgs},
+		{name: "workingset policy", args: []string{"workingset", "-policy", "lru", "example_processes.txt"}, wantErr: ErrInvalidArgs},
+		{name: "workingset tau", args: []string{"workingset", "-tau", "0", "example_processes.txt"}, wantErr: ErrInvalidArgs},
+		{name: "two files", args: []string{"alloc", "a.csv", "b.csv"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			err := run(&w, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if *update {
+				if err := os.WriteFile(path.Join("testdata", tt.name+".txt"), w.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("run() = \n%v\nwant\n%v", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_run_help(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	err := run(&w, "paging", "-h")
+	if !errors.Is(err, flag.ErrHelp) || errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("run() error = %v, want %v alone", err, flag.ErrHelp)
+	}
+	if !strings.Contains(w.String(), "-frames") {
+		t.Errorf("run() = %q, want the usage of paging's flags", w.String())
+	}
+}
+
+func loadFixture(t *testing.T, p ...string) string {
+	b, err := os.ReadFile(path.Join(p...))
+	if err != nil && !*update {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
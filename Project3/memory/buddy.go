@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotPowerOfTwo is returned for a buddy allocator with a size that isn't
+// a power of two.
+var ErrNotPowerOfTwo = errors.New("not a power of two")
+
+// Buddy is a binary buddy allocator. Every block is a power of two in size and
+// starts at a multiple of its size. An allocation is rounded up to a power of
+// two, no smaller than the minimum block, and takes the smallest free block
+// that fits, halving it until it is that size; the half it doesn't take is its
+// buddy. A freed block is merged with its buddy while the buddy is free,
+// undoing the splits.
+type Buddy struct {
+	size   int
+	min    int
+	blocks []Block // in address order, covering the heap
+	stats  Stats
+}
+
+// NewBuddy returns an empty buddy allocator of size units, which splits blocks
+// no smaller than minSize units. Both must be powers of two.
+func NewBuddy(size, minSize int) (*Buddy, error) {
+	switch {
+	case !isPowerOfTwo(size):
+		return nil, fmt.Errorf("buddy size %d: %w", size, ErrNotPowerOfTwo)
+	case !isPowerOfTwo(minSize):
+		return nil, fmt.Errorf("buddy minimum block %d: %w", minSize, ErrNotPowerOfTwo)
+	case minSize > size:
+		return nil, fmt.Errorf("buddy minimum block %d is bigger than the size %d", minSize, size)
+	}
+
+	return &Buddy{size: size, min: minSize, blocks: []Block{{Start: 0, Size: size}}}, nil
+}
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// Name returns "buddy".
+func (b *Buddy) Name() string {
+	return "buddy"
+}
+
+// Size returns how many units the heap has.
+func (b *Buddy) Size() int {
+	return b.size
+}
+
+// Alloc allocates the smallest block of a power of two units that holds size
+// units for id, splitting bigger blocks down to it.
+func (b *Buddy) Alloc(id string, size int) (int, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("alloc %v: size %d must be above 0", id, size)
+	}
+	if holder(b.blocks, id) >= 0 {
+		return 0, fmt.Errorf("alloc %v: %w", id, ErrInUse)
+	}
+	want := b.min
+	for want < size {
+		want *= 2
+	}
+	found := -1
+	for i, blk := range b.blocks {
+		if blk.Free() && blk.Size >= want && (found < 0 || blk.Size < b.blocks[found].Size) {
+			found = i
+		}
+	}
+	if found < 0 {
+		return 0, fmt.Errorf("alloc %v %d: %w", id, size, ErrNoSpace)
+	}
+
+	for b.blocks[found].Size > want {
+		blk := b.blocks[found]
+		half := blk.Size / 2
+		b.blocks[found].Size = half
+		buddy := Block{Start: blk.Start + half, Size: half}
+		b.blocks = append(b.blocks[:found+1], append([]Block{buddy}, b.blocks[found+1:]...)...)
+		b.stats.Splits++
+	}
+	b.blocks[found].ID, b.blocks[found].Used = id, size
+
+	return b.blocks[found].Start, nil
+}
+
+// Free frees the block id holds and merges it with its buddy while the buddy
+// is free.
+func (b *Buddy) Free(id string) error {
+	i := holder(b.blocks, id)
+	if i < 0 {
+		return fmt.Errorf("free %v: %w", id, ErrNotAllocated)
+	}
+	b.blocks[i].ID, b.blocks[i].Used = "", 0
+	for b.blocks[i].Size < b.size {
+		blk := b.blocks[i]
+		// The buddy is the other half of the block they were split from:
+		// after the block if it starts the pair, before it if not.
+		j := i + 1
+		if blk.Start&blk.Size != 0 {
+			j = i - 1
+		}
+		if j < 0 || j >= len(b.blocks) || !b.blocks[j].Free() || b.blocks[j].Size != blk.Size {
+			break
+		}
+		if j < i {
+			i = j
+		}
+		b.blocks[i] = Block{Start: b.blocks[i].Start, Size: blk.Size * 2}
+		b.blocks = append(b.blocks[:i+1], b.blocks[i+2:]...)
+		b.stats.Merges++
+	}
+
+	return nil
+}
+
+// Blocks returns a copy of the allocator's blocks in address order.
+func (b *Buddy) Blocks() []Block {
+	return append([]Block(nil), b.blocks...)
+}
+
+// Stats returns how many times blocks were split and merged.
+func (b *Buddy) Stats() Stats {
+	return b.stats
+}
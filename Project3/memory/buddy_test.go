@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewBuddy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		size, min int
+		wantErr   bool
+	}{
+		{size: 1024, min: 16},
+		{size: 1000, min: 16, wantErr: true},
+		{size: 1024, min: 24, wantErr: true},
+		{size: 16, min: 32, wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := NewBuddy(tt.size, tt.min); (err != nil) != tt.wantErr {
+			t.Errorf("NewBuddy(%d, %d) error = %v, wantErr %v", tt.size, tt.min, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBuddy(t *testing.T) {
+	t.Parallel()
+	b, err := NewBuddy(128, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20 rounds up to 32: 128 splits into 64+64, then 32+32.
+	if addr, err := b.Alloc("a", 20); err != nil || addr != 0 {
+		t.Fatalf("Alloc(a) = %d, %v, want 0", addr, err)
+	}
+	// 5 rounds up to the minimum of 16, splitting the other 32.
+	if addr, err := b.Alloc("b", 5); err != nil || addr != 32 {
+		t.Fatalf("Alloc(b) = %d, %v, want 32", addr, err)
+	}
+	// 64 takes the free half of the heap without a split.
+	if addr, err := b.Alloc("c", 64); err != nil || addr != 64 {
+		t.Fatalf("Alloc(c) = %d, %v, want 64", addr, err)
+	}
+	want := []Block{
+		{Start: 0, Size: 32, ID: "a", Used: 20},
+		{Start: 32, Size: 16, ID: "b", Used: 5},
+		{Start: 48, Size: 16},
+		{Start: 64, Size: 64, ID: "c", Used: 64},
+	}
+	if got := b.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks() = %v, want %v", got, want)
+	}
+	if got, want := Measure(b.Blocks()), (Usage{Held: 112, Requested: 89, Free: 16, LargestFree: 16, FreeBlocks: 1}); got != want {
+		t.Errorf("Measure() = %+v, want %+v", got, want)
+	}
+	if _, err := b.Alloc("d", 17); !errors.Is(err, ErrNoSpace) {
+		t.Errorf("Alloc(d) error = %v, want %v", err, ErrNoSpace)
+	}
+
+	// Freeing a doesn't merge with b's half, which is split; freeing b
+	// merges 16+16 and then 32+32.
+	for _, id := range []string{"a", "b"} {
+		if err := b.Free(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want = []Block{{Start: 0, Size: 64}, {Start: 64, Size: 64, ID: "c", Used: 64}}
+	if got := b.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks() = %v, want %v", got, want)
+	}
+	if err := b.Free("c"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.Stats(), (Stats{Splits: 3, Merges: 3}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if err := b.Free("c"); !errors.Is(err, ErrNotAllocated) {
+		t.Errorf("Free(c) twice error = %v, want %v", err, ErrNotAllocated)
+	}
+}
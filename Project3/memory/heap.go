@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoSpace is returned when no free block can hold an allocation.
+	ErrNoSpace = errors.New("out of memory")
+	// ErrInUse is returned for allocating an id that already holds memory.
+	ErrInUse = errors.New("id already allocated")
+	// ErrNotAllocated is returned for freeing an id that holds no memory.
+	ErrNotAllocated = errors.New("id not allocated")
+)
+
+// Block is a run of memory, held by ID or free if ID is empty. Used is how
+// much of it was asked for; an allocator rounding sizes up leaves the rest
+// unused. A cached block is held by the allocator itself, such as the free
+// objects of a slab, so it is neither free nor used.
+type Block struct {
+	Start  int
+	Size   int
+	ID     string
+	Used   int
+	Cached bool
+}
+
+// Free reports whether no one holds the block.
+func (b Block) Free() bool {
+	return b.ID == "" && !b.Cached
+}
+
+// Allocator manages a heap of memory.
+type Allocator interface {
+	// Name describes the allocator, such as "first fit".
+	Name() string
+	// Size returns how many units the heap has.
+	Size() int
+	// Alloc allocates size units for id and returns where they start.
+	Alloc(id string, size int) (int, error)
+	// Free frees the memory id holds.
+	Free(id string) error
+	// Blocks returns the blocks of the heap in address order.
+	Blocks() []Block
+	// Stats returns how many times blocks were split and merged.
+	Stats() Stats
+}
+
+// Stats counts the work an allocator did on its blocks.
+type Stats struct {
+	Splits int // free blocks split to make an allocation
+	Merges int // freed blocks coalesced with a free neighbour
+}
+
+// Policy is how a Heap picks the free block an allocation goes in.
+type Policy int
+
+// The placement policies.
+const (
+	FirstFit Policy = iota // the first block big enough
+	BestFit                // the smallest block big enough
+	WorstFit               // the largest block
+	NextFit                // the first block big enough after the last allocation
+)
+
+// Policies are the placement policies, in the order they are compared.
+var Policies = []Policy{FirstFit, BestFit, WorstFit, NextFit}
+
+func (p Policy) String() string {
+	switch p {
+	case FirstFit:
+		return "first fit"
+	case BestFit:
+		return "best fit"
+	case WorstFit:
+		return "worst fit"
+	case NextFit:
+		return "next fit"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// ParsePolicy returns the policy named s, such as "best" or "best-fit".
+func ParsePolicy(s string) (Policy, error) {
+	name := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(s), "fit"), "-")
+	for _, p := range Policies {
+		if strings.TrimSuffix(p.String(), " fit") == name {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown policy %q", s)
+}
+
+// Heap is a heap managed by a placement policy. An allocation takes the front
+// of the free block the policy picks, and the rest stays free; a freed block
+// is coalesced with the free blocks next to it.
+type Heap struct {
+	size   int
+	policy Policy
+	blocks []Block // in address order, covering the heap
+	rover  int     // where next fit starts looking
+	stats  Stats
+}
+
+// NewHeap returns an empty heap of size units managed by policy.
+func NewHeap(size int, policy Policy) *Heap {
+	return &Heap{size: size, policy: policy, blocks: []Block{{Start: 0, Size: size}}}
+}
+
+// Name returns the name of the heap's policy.
+func (h *Heap) Name() string {
+	return h.policy.String()
+}
+
+// Size returns how many units the heap has.
+func (h *Heap) Size() int {
+	return h.size
+}
+
+// Alloc allocates size units for id from the block the policy picks.
+func (h *Heap) Alloc(id string, size int) (int, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("alloc %v: size %d must be above 0", id, size)
+	}
+	if holder(h.blocks, id) >= 0 {
+		return 0, fmt.Errorf("alloc %v: %w", id, ErrInUse)
+	}
+	i := h.find(size)
+	if i < 0 {
+		return 0, fmt.Errorf("alloc %v %d: %w", id, size, ErrNoSpace)
+	}
+
+	b := h.blocks[i]
+	h.blocks[i] = Block{Start: b.Start, Size: size, ID: id, Used: size}
+	if b.Size > size {
+		rest := Block{Start: b.Start + size, Size: b.Size - size}
+		h.blocks = append(h.blocks[:i+1], append([]Block{rest}, h.blocks[i+1:]...)...)
+		h.stats.Splits++
+	}
+	h.rover = b.Start + size
+
+	return b.Start, nil
+}
+
+// Free frees the block id holds and coalesces it with its free neighbours.
+func (h *Heap) Free(id string) error {
+	i := holder(h.blocks, id)
+	if i < 0 {
+		return fmt.Errorf("free %v: %w", id, ErrNotAllocated)
+	}
+	h.blocks[i].ID, h.blocks[i].Used = "", 0
+	if i+1 < len(h.blocks) && h.blocks[i+1].Free() {
+		h.blocks[i].Size += h.blocks[i+1].Size
+		h.blocks = append(h.blocks[:i+1], h.blocks[i+2:]...)
+		h.stats.Merges++
+	}
+	if i > 0 && h.blocks[i-1].Free() {
+		h.blocks[i-1].Size += h.blocks[i].Size
+		h.blocks = append(h.blocks[:i], h.blocks[i+1:]...)
+		h.stats.Merges++
+	}
+
+	return nil
+}
+
+// Blocks returns a copy of the heap's blocks in address order.
+func (h *Heap) Blocks() []Block {
+	return append([]Block(nil), h.blocks...)
+}
+
+// Stats returns how many times blocks were split and merged.
+func (h *Heap) Stats() Stats {
+	return h.stats
+}
+
+// holder returns the index of the block of blocks id holds, or -1.
+func holder(blocks []Block, id string) int {
+	for i, b := range blocks {
+		if b.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// find returns the index of the free block the policy picks for size units,
+// or -1 if none is big enough.
+func (h *Heap) find(size int) int {
+	found := -1
+	switch h.policy {
+	case NextFit:
+		// Start with the block the rover is in, and wrap around.
+		first := 0
+		for i, b := range h.blocks {
+			if b.Start <= h.rover && h.rover < b.Start+b.Size {
+				first = i
+				break
+			}
+		}
+		for k := range h.blocks {
+			i := (first + k) % len(h.blocks)
+			if b := h.blocks[i]; b.Free() && b.Size >= size {
+				return i
+			}
+		}
+	default:
+		for i, b := range h.blocks {
+			if !b.Free() || b.Size < size {
+				continue
+			}
+			switch {
+			case found < 0,
+				h.policy == BestFit && b.Size < h.blocks[found].Size,
+				h.policy == WorstFit && b.Size > h.blocks[found].Size:
+				found = i
+			}
+			if h.policy == FirstFit {
+				break
+			}
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHeap_Alloc(t *testing.T) {
+	t.Parallel()
+	// Free a and c to leave holes of 100 at 0 and 50 at 300, with 294 free
+	// at the end, and the last allocation ending at 730.
+	setup := []Request{
+		{Op: OpAlloc, ID: "a", Size: 100},
+		{Op: OpAlloc, ID: "b", Size: 200},
+		{Op: OpAlloc, ID: "c", Size: 50},
+		{Op: OpAlloc, ID: "d", Size: 300},
+		{Op: OpAlloc, ID: "e", Size: 80},
+		{Op: OpFree, ID: "c"},
+		{Op: OpFree, ID: "a"},
+	}
+	tests := []struct {
+		policy Policy
+		size   int
+		want   int
+	}{
+		{policy: FirstFit, size: 40, want: 0},
+		{policy: BestFit, size: 40, want: 300},
+		{policy: WorstFit, size: 40, want: 730},
+		{policy: NextFit, size: 40, want: 730},
+		{policy: FirstFit, size: 120, want: 730},
+		{policy: BestFit, size: 100, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			t.Parallel()
+			h := NewHeap(1024, tt.policy)
+			if res := Simulate(h, setup); res.Failed != 0 {
+				t.Fatalf("setup failed %d requests", res.Failed)
+			}
+			got, err := h.Alloc("x", tt.size)
+			if err != nil {
+				t.Fatalf("Alloc() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Alloc(%d) = %d, want %d", tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeap_nextFitWraps(t *testing.T) {
+	t.Parallel()
+	h := NewHeap(100, NextFit)
+	for _, req := range []Request{
+		{Op: OpAlloc, ID: "a", Size: 30},
+		{Op: OpAlloc, ID: "b", Size: 60},
+		{Op: OpFree, ID: "a"},
+	} {
+		if req.Op == OpAlloc {
+			_, _ = h.Alloc(req.ID, req.Size)
+		} else {
+			_ = h.Free(req.ID)
+		}
+	}
+	// 10 free at the end is too small, so it wraps around to 0.
+	if got, err := h.Alloc("c", 20); err != nil || got != 0 {
+		t.Errorf("Alloc() = %d, %v, want 0", got, err)
+	}
+}
+
+func TestHeap_Free(t *testing.T) {
+	t.Parallel()
+	h := NewHeap(100, FirstFit)
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := h.Alloc(id, 20); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Free("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Free("c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Free("b"); err != nil {
+		t.Fatal(err)
+	}
+	want := []Block{{Start: 0, Size: 100}}
+	if got := h.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks() = %v, want %v", got, want)
+	}
+
+	if err := h.Free("a"); !errors.Is(err, ErrNotAllocated) {
+		t.Errorf("Free() twice error = %v, want %v", err, ErrNotAllocated)
+	}
+	if _, err := h.Alloc("a", 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Alloc("a", 10); !errors.Is(err, ErrInUse) {
+		t.Errorf("Alloc() twice error = %v, want %v", err, ErrInUse)
+	}
+	if _, err := h.Alloc("b", 91); !errors.Is(err, ErrNoSpace) {
+		t.Errorf("Alloc() too big error = %v, want %v", err, ErrNoSpace)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "first", want: FirstFit},
+		{name: "best-fit", want: BestFit},
+		{name: "Worst", want: WorstFit},
+		{name: "nextfit", want: NextFit},
+		{name: "last", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
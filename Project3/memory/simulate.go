@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"strings"
+)
+
+// Usage is how the memory of a heap is used.
+type Usage struct {
+	Held        int // units held by allocations
+	Requested   int // units of Held that were asked for
+	Free        int // units free
+	LargestFree int // units in the largest free block
+	FreeBlocks  int // how many free blocks there are
+}
+
+// Measure returns the usage of blocks.
+func Measure(blocks []Block) Usage {
+	var u Usage
+	for _, b := range blocks {
+		if !b.Free() {
+			u.Held += b.Size
+			u.Requested += b.Used
+			continue
+		}
+		u.Free += b.Size
+		u.FreeBlocks++
+		if b.Size > u.LargestFree {
+			u.LargestFree = b.Size
+		}
+	}
+
+	return u
+}
+
+// ExternalFragmentation returns the share of free memory outside the largest
+// free block, which an allocation can't use: 0 when the free memory is all in
+// one block, and closer to 1 the more it is split up.
+func (u Usage) ExternalFragmentation() float64 {
+	if u.Free == 0 {
+		return 0
+	}
+
+	return 1 - float64(u.LargestFree)/float64(u.Free)
+}
+
+// InternalFragmentation returns the share of held memory that wasn't asked
+// for, wasted inside blocks an allocator rounded up.
+func (u Usage) InternalFragmentation() float64 {
+	if u.Held == 0 {
+		return 0
+	}
+
+	return 1 - float64(u.Requested)/float64(u.Held)
+}
+
+// Step is a request of a trace and what came of it.
+type Step struct {
+	Request Request
+	Addr    int   // where an allocation was placed
+	Err     error // why the request failed, if it did
+	Blocks  []Block
+	Usage   Usage
+}
+
+// Result is how an allocator ran a trace.
+type Result struct {
+	Name   string
+	Size   int
+	Steps  []Step
+	Failed int // requests that failed
+	Stats  Stats
+
+	PeakExternal float64 // the peak external fragmentation
+	MeanExternal float64 // the mean external fragmentation over the steps
+	PeakInternal float64 // the peak internal fragmentation
+	MeanInternal float64 // the mean internal fragmentation over the steps
+}
+
+// Simulate runs trace against a, recording the heap after each request. A
+// failed request is recorded and the trace goes on.
+func Simulate(a Allocator, trace []Request) Result {
+	res := Result{Name: a.Name(), Size: a.Size(), Steps: make([]Step, 0, len(trace))}
+	var external, internal float64
+	for _, req := range trace {
+		step := Step{Request: req}
+		if req.Op == OpAlloc {
+			step.Addr, step.Err = a.Alloc(req.ID, req.Size)
+		} else {
+			step.Err = a.Free(req.ID)
+		}
+		if step.Err != nil {
+			res.Failed++
+		}
+		step.Blocks = a.Blocks()
+		step.Usage = Measure(step.Blocks)
+		ext, in := step.Usage.ExternalFragmentation(), step.Usage.InternalFragmentation()
+		external += ext
+		internal += in
+		if ext > res.PeakExternal {
+			res.PeakExternal = ext
+		}
+		if in > res.PeakInternal {
+			res.PeakInternal = in
+		}
+		res.Steps = append(res.Steps, step)
+	}
+	if len(trace) > 0 {
+		res.MeanExternal = external / float64(len(trace))
+		res.MeanInternal = internal / float64(len(trace))
+	}
+	res.Stats = a.Stats()
+
+	return res
+}
+
+// Final returns the usage of the heap at the end of the run.
+func (r Result) Final() Usage {
+	if len(r.Steps) == 0 {
+		return Usage{Free: r.Size, LargestFree: r.Size, FreeBlocks: 1}
+	}
+
+	return r.Steps[len(r.Steps)-1].Usage
+}
+
+// Symbols picks the characters a memory map draws allocations with.
+type Symbols map[string]byte
+
+// symbolSet are the characters given to allocations, in order.
+const symbolSet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// NewSymbols returns symbols for the ids of trace, each its own character
+// while they last: the first character of the id if it is free, or the next
+// character of the set.
+func NewSymbols(trace []Request) Symbols {
+	sym := Symbols{}
+	taken := map[byte]bool{'.': true}
+	next := 0
+	for _, req := range trace {
+		if _, ok := sym[req.ID]; ok || req.Op != OpAlloc {
+			continue
+		}
+		c := req.ID[0]
+		if taken[c] || strings.IndexByte(symbolSet, c) < 0 {
+			c = '#'
+			for next < len(symbolSet) {
+				if s := symbolSet[next]; !taken[s] {
+					c = s
+					break
+				}
+				next++
+			}
+		}
+		sym[req.ID] = c
+		taken[c] = true
+	}
+
+	return sym
+}
+
+// symbol returns the character id is drawn with.
+func (s Symbols) symbol(id string) byte {
+	if c, ok := s[id]; ok {
+		return c
+	}
+
+	return '#'
+}
+
+// MemoryMap draws blocks of a heap of size units as a line of width
+// characters, each standing for size/width units and showing what most of
+// them are: '.' for free memory, '~' for memory held but not asked for, or the
+// symbol of the allocation.
+func MemoryMap(blocks []Block, size, width int, sym Symbols) string {
+	if size <= 0 || width <= 0 {
+		return ""
+	}
+	line := make([]byte, width)
+	b := 0
+	for i := range line {
+		// The units [lo, hi) are cell i.
+		lo, hi := i*size/width, (i+1)*size/width
+		if hi == lo {
+			hi = lo + 1
+		}
+		best, most := byte('.'), 0
+		held, slack := 0, 0
+		for b < len(blocks) && blocks[b].Start+blocks[b].Size <= lo {
+			b++
+		}
+		for j := b; j < len(blocks) && blocks[j].Start < hi; j++ {
+			start, end := blocks[j].Start, blocks[j].Start+blocks[j].Size
+			if start < lo {
+				start = lo
+			}
+			if end > hi {
+				end = hi
+			}
+			if blocks[j].Free() {
+				continue
+			}
+			held += end - start
+			// The units past Used are slack.
+			if used := blocks[j].Start + blocks[j].Used; used < end {
+				if used > start {
+					slack += end - used
+					end = used
+				} else {
+					slack += end - start
+					end = start
+				}
+			}
+			if end-start > most {
+				best, most = sym.symbol(blocks[j].ID), end-start
+			}
+		}
+		if slack > most {
+			best = '~'
+		}
+		if held*2 < hi-lo {
+			best = '.'
+		}
+		line[i] = best
+	}
+
+	return string(line)
+}
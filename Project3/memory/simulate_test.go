@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	trace := []Request{
+		{Op: OpAlloc, ID: "a", Size: 25},
+		{Op: OpAlloc, ID: "b", Size: 25},
+		{Op: OpAlloc, ID: "c", Size: 25},
+		{Op: OpFree, ID: "b"},
+		{Op: OpAlloc, ID: "d", Size: 40},
+		{Op: OpFree, ID: "x"},
+	}
+	res := Simulate(NewHeap(100, FirstFit), trace)
+	if res.Failed != 2 {
+		t.Errorf("Failed = %d, want 2", res.Failed)
+	}
+	// After freeing b, 25 of the 50 free units are outside the largest block.
+	if got := res.Steps[3].Usage.ExternalFragmentation(); got != 0.5 {
+		t.Errorf("fragmentation after free = %v, want 0.5", got)
+	}
+	if res.PeakExternal != 0.5 {
+		t.Errorf("PeakExternal = %v, want 0.5", res.PeakExternal)
+	}
+	if want := 1.5 / 6; math.Abs(res.MeanExternal-want) > 1e-9 {
+		t.Errorf("MeanExternal = %v, want %v", res.MeanExternal, want)
+	}
+	if got, want := res.Final(), (Usage{Held: 50, Requested: 50, Free: 50, LargestFree: 25, FreeBlocks: 2}); got != want {
+		t.Errorf("Final() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryMap(t *testing.T) {
+	t.Parallel()
+	trace := []Request{
+		{Op: OpAlloc, ID: "a", Size: 30},
+		{Op: OpAlloc, ID: "apple", Size: 10},
+		{Op: OpAlloc, ID: "b", Size: 2},
+		{Op: OpFree, ID: "a"},
+	}
+	h := NewHeap(100, FirstFit)
+	res := Simulate(h, trace)
+	sym := NewSymbols(trace)
+	if sym["a"] != 'a' || sym["apple"] != 'A' {
+		t.Errorf("NewSymbols() = %q", sym)
+	}
+	tests := []struct {
+		step  int
+		width int
+		want  string
+	}{
+		{step: 0, width: 10, want: "aaa......."},
+		{step: 2, width: 10, want: "aaaA......"},
+		{step: 2, width: 50, want: "aaaaaaaaaaaaaaaAAAAAb............................."},
+		{step: 3, width: 10, want: "...A......"},
+	}
+	for _, tt := range tests {
+		got := MemoryMap(res.Steps[tt.step].Blocks, 100, tt.width, sym)
+		if got != tt.want {
+			t.Errorf("MemoryMap(step %d, width %d) = %q, want %q", tt.step+1, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryMap_slack(t *testing.T) {
+	t.Parallel()
+	b, err := NewBuddy(64, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Alloc("a", 4); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := MemoryMap(b.Blocks(), 64, 8, Symbols{"a": 'a'}), "a~......"; got != want {
+		t.Errorf("MemoryMap() = %q, want %q", got, want)
+	}
+}
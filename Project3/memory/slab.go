@@ -0,0 +1,281 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidClasses is returned for object size classes a slab allocator
+// can't use.
+var ErrInvalidClasses = errors.New("invalid size classes")
+
+// Slab is a slab allocator. The heap is cut into slabs of the same size, and
+// each size class of objects has a cache of slabs cut into objects of that
+// size. An allocation is rounded up to the smallest class that holds it and
+// takes a free object of a slab already in the cache if it can, and the cache
+// grows by a free slab if not. A slab whose objects are all freed goes back to
+// the heap, unless it is the only empty slab of its cache, which is kept for
+// the next allocation. An allocation bigger than every class takes a run of
+// whole slabs.
+type Slab struct {
+	size     int
+	slabSize int
+	frames   []frame
+	caches   []CacheStats
+	objects  map[string]slot // where the objects are, by id
+	large    map[string]slot // the first frame of large allocations, by id
+}
+
+// frame is a slab of the heap.
+type frame struct {
+	cache int      // the index of the cache it belongs to, or freeFrame or largeFrame
+	ids   []string // who holds its objects
+	used  []int    // how much of each object was asked for
+	inUse int      // how many objects are held
+	run   int      // for the first frame of a large allocation, how many frames
+}
+
+const (
+	freeFrame  = -1 // the frame is free
+	largeFrame = -2 // the frame is part of a large allocation
+)
+
+// slot is where an object is.
+type slot struct {
+	frame, object int
+}
+
+// CacheStats is how a cache of a slab allocator was used.
+type CacheStats struct {
+	Size       int // the size of its objects, or 0 for large allocations
+	PerSlab    int // how many objects a slab holds
+	Allocs     int // allocations made from the cache
+	Reuses     int // allocations made from a slab the cache already had
+	SlabAllocs int // slabs the cache took from the heap
+	SlabFrees  int // slabs the cache gave back
+	Slabs      int // slabs the cache holds
+	PeakSlabs  int // the most slabs the cache held
+}
+
+// NewSlab returns an empty slab allocator of size units, cut into slabs of
+// slabSize units, with a cache for each object size of classes.
+func NewSlab(size, slabSize int, classes []int) (*Slab, error) {
+	switch {
+	case slabSize <= 0 || size%slabSize != 0:
+		return nil, fmt.Errorf("slab size %d must divide the heap size %d", slabSize, size)
+	case len(classes) == 0:
+		return nil, fmt.Errorf("%w: none given", ErrInvalidClasses)
+	}
+	classes = append([]int(nil), classes...)
+	sort.Ints(classes)
+	s := &Slab{
+		size:     size,
+		slabSize: slabSize,
+		frames:   make([]frame, size/slabSize),
+		caches:   make([]CacheStats, len(classes)+1),
+		objects:  map[string]slot{},
+		large:    map[string]slot{},
+	}
+	for i, class := range classes {
+		if class <= 0 || class > slabSize || (i > 0 && class == classes[i-1]) {
+			return nil, fmt.Errorf("%w: %v: each must be above 0, at most the slab size %d, and different", ErrInvalidClasses, classes, slabSize)
+		}
+		s.caches[i] = CacheStats{Size: class, PerSlab: slabSize / class}
+	}
+	for i := range s.frames {
+		s.frames[i].cache = freeFrame
+	}
+
+	return s, nil
+}
+
+// Name returns "slab".
+func (s *Slab) Name() string {
+	return "slab"
+}
+
+// Size returns how many units the heap has.
+func (s *Slab) Size() int {
+	return s.size
+}
+
+// Alloc allocates an object of the smallest size class that holds size units
+// for id, or a run of slabs if none does.
+func (s *Slab) Alloc(id string, size int) (int, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("alloc %v: size %d must be above 0", id, size)
+	}
+	if _, ok := s.objects[id]; ok {
+		return 0, fmt.Errorf("alloc %v: %w", id, ErrInUse)
+	}
+	if _, ok := s.large[id]; ok {
+		return 0, fmt.Errorf("alloc %v: %w", id, ErrInUse)
+	}
+	c := 0
+	for c < len(s.caches)-1 && s.caches[c].Size < size {
+		c++
+	}
+	if c == len(s.caches)-1 {
+		return s.allocLarge(id, size)
+	}
+
+	cache := &s.caches[c]
+	// Fill partly used slabs before the empty one kept.
+	f := -1
+	for i, fr := range s.frames {
+		if fr.cache == c && fr.inUse < len(fr.ids) && (f < 0 || fr.inUse > s.frames[f].inUse) {
+			f = i
+		}
+	}
+	if f >= 0 {
+		cache.Reuses++
+	} else {
+		if f = s.freeRun(1); f < 0 {
+			return 0, fmt.Errorf("alloc %v %d: %w", id, size, ErrNoSpace)
+		}
+		s.frames[f] = frame{cache: c, ids: make([]string, cache.PerSlab), used: make([]int, cache.PerSlab)}
+		cache.SlabAllocs++
+		cache.Slabs++
+		if cache.Slabs > cache.PeakSlabs {
+			cache.PeakSlabs = cache.Slabs
+		}
+	}
+	cache.Allocs++
+
+	fr := &s.frames[f]
+	o := 0
+	for fr.ids[o] != "" {
+		o++
+	}
+	fr.ids[o], fr.used[o] = id, size
+	fr.inUse++
+	s.objects[id] = slot{frame: f, object: o}
+
+	return f*s.slabSize + o*cache.Size, nil
+}
+
+// allocLarge allocates the first run of free slabs that holds size units.
+func (s *Slab) allocLarge(id string, size int) (int, error) {
+	n := (size + s.slabSize - 1) / s.slabSize
+	f := s.freeRun(n)
+	if f < 0 {
+		return 0, fmt.Errorf("alloc %v %d: %w", id, size, ErrNoSpace)
+	}
+	for i := f; i < f+n; i++ {
+		s.frames[i] = frame{cache: largeFrame}
+	}
+	s.frames[f].run = n
+	s.frames[f].ids, s.frames[f].used = []string{id}, []int{size}
+	s.large[id] = slot{frame: f}
+	cache := &s.caches[len(s.caches)-1]
+	cache.Allocs++
+	cache.SlabAllocs += n
+	cache.Slabs += n
+	if cache.Slabs > cache.PeakSlabs {
+		cache.PeakSlabs = cache.Slabs
+	}
+
+	return f * s.slabSize, nil
+}
+
+// freeRun returns the first of n free frames in a row, or -1.
+func (s *Slab) freeRun(n int) int {
+	run := 0
+	for i, fr := range s.frames {
+		if fr.cache != freeFrame {
+			run = 0
+			continue
+		}
+		if run++; run == n {
+			return i - n + 1
+		}
+	}
+
+	return -1
+}
+
+// Free frees the object or the run of slabs id holds.
+func (s *Slab) Free(id string) error {
+	if at, ok := s.large[id]; ok {
+		delete(s.large, id)
+		n := s.frames[at.frame].run
+		for i := at.frame; i < at.frame+n; i++ {
+			s.frames[i] = frame{cache: freeFrame}
+		}
+		cache := &s.caches[len(s.caches)-1]
+		cache.SlabFrees += n
+		cache.Slabs -= n
+		return nil
+	}
+	at, ok := s.objects[id]
+	if !ok {
+		return fmt.Errorf("free %v: %w", id, ErrNotAllocated)
+	}
+	delete(s.objects, id)
+	fr := &s.frames[at.frame]
+	fr.ids[at.object], fr.used[at.object] = "", 0
+	if fr.inUse--; fr.inUse > 0 {
+		return nil
+	}
+
+	// Keep the slab if it is the cache's only empty one.
+	c := fr.cache
+	for i, other := range s.frames {
+		if i != at.frame && other.cache == c && other.inUse == 0 {
+			s.frames[at.frame] = frame{cache: freeFrame}
+			s.caches[c].SlabFrees++
+			s.caches[c].Slabs--
+			break
+		}
+	}
+
+	return nil
+}
+
+// Blocks returns the heap in address order: each object of a cache's slabs,
+// with the free objects and the unused end of a slab cached, each large
+// allocation, and the free slabs.
+func (s *Slab) Blocks() []Block {
+	var blocks []Block
+	add := func(b Block) {
+		// Join free or cached blocks to the one before of the same kind.
+		if n := len(blocks); n > 0 && b.ID == "" && blocks[n-1].ID == "" && blocks[n-1].Cached == b.Cached {
+			blocks[n-1].Size += b.Size
+			return
+		}
+		blocks = append(blocks, b)
+	}
+	for i, fr := range s.frames {
+		start := i * s.slabSize
+		switch fr.cache {
+		case freeFrame:
+			add(Block{Start: start, Size: s.slabSize})
+		case largeFrame:
+			if fr.run > 0 {
+				add(Block{Start: start, Size: fr.run * s.slabSize, ID: fr.ids[0], Used: fr.used[0]})
+			}
+		default:
+			size := s.caches[fr.cache].Size
+			for o, id := range fr.ids {
+				add(Block{Start: start + o*size, Size: size, ID: id, Used: fr.used[o], Cached: id == ""})
+			}
+			if end := len(fr.ids) * size; end < s.slabSize {
+				add(Block{Start: start + end, Size: s.slabSize - end, Cached: true})
+			}
+		}
+	}
+
+	return blocks
+}
+
+// Stats returns no splits or merges: slabs are never split or merged.
+func (s *Slab) Stats() Stats {
+	return Stats{}
+}
+
+// Caches returns how each cache was used, by object size, then how the large
+// allocations were, with a Size of 0.
+func (s *Slab) Caches() []CacheStats {
+	return append([]CacheStats(nil), s.caches...)
+}
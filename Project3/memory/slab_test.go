@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewSlab(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		size     int
+		slabSize int
+		classes  []int
+		wantErr  bool
+	}{
+		{name: "ok", size: 1024, slabSize: 128, classes: []int{64, 16, 32}},
+		{name: "uneven slabs", size: 1000, slabSize: 128, classes: []int{16}, wantErr: true},
+		{name: "no classes", size: 1024, slabSize: 128, wantErr: true},
+		{name: "class too big", size: 1024, slabSize: 128, classes: []int{16, 256}, wantErr: true},
+		{name: "same class", size: 1024, slabSize: 128, classes: []int{16, 16}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := NewSlab(tt.size, tt.slabSize, tt.classes); (err != nil) != tt.wantErr {
+			t.Errorf("%v: NewSlab() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSlab(t *testing.T) {
+	t.Parallel()
+	s, err := NewSlab(256, 64, []int{16, 48})
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := []struct {
+		id   string
+		size int
+		want int
+	}{
+		{id: "a", size: 10, want: 0},    // a new slab of 16s
+		{id: "b", size: 16, want: 16},   // reuses it
+		{id: "c", size: 40, want: 64},   // a new slab of 48s
+		{id: "d", size: 100, want: 128}, // two slabs of its own
+	}
+	for _, a := range allocs {
+		if got, err := s.Alloc(a.id, a.size); err != nil || got != a.want {
+			t.Fatalf("Alloc(%v, %d) = %d, %v, want %d", a.id, a.size, got, err, a.want)
+		}
+	}
+	want := []Block{
+		{Start: 0, Size: 16, ID: "a", Used: 10},
+		{Start: 16, Size: 16, ID: "b", Used: 16},
+		{Start: 32, Size: 32, Cached: true},
+		{Start: 64, Size: 48, ID: "c", Used: 40},
+		{Start: 112, Size: 16, Cached: true},
+		{Start: 128, Size: 128, ID: "d", Used: 100},
+	}
+	if got := s.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Blocks() = %v, want %v", got, want)
+	}
+	if _, err := s.Alloc("e", 40); !errors.Is(err, ErrNoSpace) {
+		t.Errorf("Alloc(e) error = %v, want %v", err, ErrNoSpace)
+	}
+	if _, err := s.Alloc("a", 1); !errors.Is(err, ErrInUse) {
+		t.Errorf("Alloc(a) twice error = %v, want %v", err, ErrInUse)
+	}
+
+	// The emptied slab of 48s is kept, as the only empty one; the large
+	// allocation's slabs go back to the heap.
+	for _, id := range []string{"c", "d"} {
+		if err := s.Free(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := s.Alloc("f", 30); err != nil || got != 64 {
+		t.Errorf("Alloc(f) = %d, %v, want 64", got, err)
+	}
+	if got, want := Measure(s.Blocks()), (Usage{Held: 128, Requested: 56, Free: 128, LargestFree: 128, FreeBlocks: 1}); got != want {
+		t.Errorf("Measure() = %+v, want %+v", got, want)
+	}
+	wantCaches := []CacheStats{
+		{Size: 16, PerSlab: 4, Allocs: 2, Reuses: 1, SlabAllocs: 1, Slabs: 1, PeakSlabs: 1},
+		{Size: 48, PerSlab: 1, Allocs: 2, Reuses: 1, SlabAllocs: 1, Slabs: 1, PeakSlabs: 1},
+		{Allocs: 1, SlabAllocs: 2, SlabFrees: 2, PeakSlabs: 2},
+	}
+	if got := s.Caches(); !reflect.DeepEqual(got, wantCaches) {
+		t.Errorf("Caches() = %+v, want %+v", got, wantCaches)
+	}
+	if err := s.Free("d"); !errors.Is(err, ErrNotAllocated) {
+		t.Errorf("Free(d) twice error = %v, want %v", err, ErrNotAllocated)
+	}
+}
+
+func TestSlab_releasesSecondEmptySlab(t *testing.T) {
+	t.Parallel()
+	s, err := NewSlab(256, 64, []int{64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := s.Alloc(id, 64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := s.Free(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := s.Caches()[0]
+	if got.SlabAllocs != 3 || got.SlabFrees != 2 || got.Slabs != 1 {
+		t.Errorf("Caches()[0] = %+v, want 3 slabs taken, 2 given back and 1 kept", got)
+	}
+}
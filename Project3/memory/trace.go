@@ -0,0 +1,130 @@
+// Package memory simulates memory allocators, such as placement policies
+// managing a heap and the buddy system, run against traces of allocation and
+// free requests.
+package memory
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTrace is returned for a trace that can't be read.
+var ErrInvalidTrace = errors.New("invalid trace")
+
+// Op is what a request asks of an allocator.
+type Op string
+
+// The requests of a trace.
+const (
+	OpAlloc Op = "alloc" // allocate Size units for ID
+	OpFree  Op = "free"  // free what ID holds
+)
+
+// Request is one step of a trace.
+type Request struct {
+	Op   Op     `json:"op"`
+	ID   string `json:"id"`
+	Size int    `json:"size,omitempty"`
+}
+
+func (r Request) String() string {
+	if r.Op == OpAlloc {
+		return fmt.Sprintf("%v %v %d", r.Op, r.ID, r.Size)
+	}
+
+	return fmt.Sprintf("%v %v", r.Op, r.ID)
+}
+
+// LoadTrace reads a trace, either as a JSON array of requests such as
+// {"op": "alloc", "id": "a", "size": 100}, or as CSV lines of op,id,size
+// such as "alloc,a,100" and "free,a". malloc is accepted for alloc. Blank
+// lines and lines starting with # are skipped.
+func LoadTrace(r io.Reader) ([]Request, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		_, _ = br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '[' {
+		return loadJSONTrace(br)
+	}
+
+	return loadCSVTrace(br)
+}
+
+func loadJSONTrace(r io.Reader) ([]Request, error) {
+	var trace []Request
+	if err := json.NewDecoder(r).Decode(&trace); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrace, err)
+	}
+	for i := range trace {
+		if err := trace[i].check(); err != nil {
+			return nil, fmt.Errorf("request %d: %w", i+1, err)
+		}
+	}
+
+	return trace, nil
+}
+
+func loadCSVTrace(r io.Reader) ([]Request, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	var trace []Request
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return trace, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTrace, err)
+		}
+		line, _ := cr.FieldPos(0)
+		req := Request{Op: Op(strings.TrimSpace(row[0]))}
+		if len(row) > 1 {
+			req.ID = strings.TrimSpace(row[1])
+		}
+		if len(row) > 2 {
+			if req.Size, err = strconv.Atoi(strings.TrimSpace(row[2])); err != nil {
+				return nil, fmt.Errorf("line %d: %w: size %q is not a number", line, ErrInvalidTrace, row[2])
+			}
+		}
+		if err := req.check(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		trace = append(trace, req)
+	}
+}
+
+// check validates the request, turning malloc into alloc.
+func (r *Request) check() error {
+	if r.Op == "malloc" {
+		r.Op = OpAlloc
+	}
+	switch {
+	case r.Op != OpAlloc && r.Op != OpFree:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidTrace, r.Op)
+	case r.ID == "":
+		return fmt.Errorf("%w: %v without an id", ErrInvalidTrace, r.Op)
+	case r.Op == OpAlloc && r.Size <= 0:
+		return fmt.Errorf("%w: alloc %v needs a size above 0", ErrInvalidTrace, r.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadTrace(t *testing.T) {
+	t.Parallel()
+	want := []Request{
+		{Op: OpAlloc, ID: "a", Size: 100},
+		{Op: OpAlloc, ID: "b", Size: 20},
+		{Op: OpFree, ID: "a"},
+	}
+	tests := []struct {
+		name    string
+		input   string
+		want    []Request
+		wantErr error
+	}{
+		{
+			name:  "csv",
+			input: "# op,id,size\nalloc,a,100\n\nmalloc, b, 20\nfree,a\n",
+			want:  want,
+		},
+		{
+			name:  "json",
+			input: "\n [{\"op\": \"alloc\", \"id\": \"a\", \"size\": 100}, {\"op\": \"malloc\", \"id\": \"b\", \"size\": 20}, {\"op\": \"free\", \"id\": \"a\"}]",
+			want:  want,
+		},
+		{name: "empty", input: "  \n"},
+		{name: "unknown op", input: "realloc,a,10\n", wantErr: ErrInvalidTrace},
+		{name: "no size", input: "alloc,a\n", wantErr: ErrInvalidTrace},
+		{name: "bad size", input: "alloc,a,lots\n", wantErr: ErrInvalidTrace},
+		{name: "no id", input: `[{"op": "free"}]`, wantErr: ErrInvalidTrace},
+		{name: "bad json", input: `[{"op": "free"`, wantErr: ErrInvalidTrace},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadTrace(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadTrace() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadTrace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
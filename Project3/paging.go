@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project3/paging"
+	"github.com/olekukonko/tablewriter"
+)
+
+// pagingCommand runs a reference string through frames with each replacement
+// algorithm chosen with -policy, printing the frames after every reference,
+// then compares the algorithms over a range of frame counts.
+func pagingCommand(w io.Writer, args ...string) error {
+	fs := flag.NewFlagSet("paging", flag.ContinueOnError)
+	fs.SetOutput(w)
+	frames := fs.Int("frames", 3, "the number of `FRAMES`")
+	maxFrames := fs.Int("max", 0, "compare the algorithms with 1 to `N` frames (default the number of pages referenced)")
+	policy := fs.String("policy", "all", "the replacement `ALGORITHM`: fifo, lru, clock, optimal or all")
+	quiet := fs.Bool("q", false, "only print the comparison, not each reference")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if *frames <= 0 || *maxFrames < 0 {
+		return fmt.Errorf("%w: -frames must be above 0 and -max can't be below 0", ErrInvalidArgs)
+	}
+	replacers := paging.Replacers()
+	if *policy != "all" {
+		r, err := paging.ParseReplacer(*policy)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		replacers = []paging.Replacer{r}
+	}
+
+	f, err := openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	refs, err := paging.ReadReferences(f)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("%w: no pages referenced", ErrInvalidArgs)
+	}
+	if *maxFrames == 0 {
+		*maxFrames = distinct(refs)
+	}
+
+	if !*quiet {
+		for _, r := range replacers {
+			res := paging.Simulate(r, refs, *frames)
+			outputTitle(w, res.Name)
+			outputFrames(w, res)
+		}
+	}
+	outputFaultChart(w, replacers, refs, *maxFrames)
+
+	return nil
+}
+
+// distinct returns how many different pages refs has.
+func distinct(refs []int) int {
+	seen := map[int]bool{}
+	for _, page := range refs {
+		seen[page] = true
+	}
+
+	return len(seen)
+}
+
+// outputFrames prints the frames after each reference, a column each, with
+// the references that faulted marked.
+func outputFrames(w io.Writer, res paging.Result) {
+	header := []string{"Reference"}
+	faults := []string{"Fault"}
+	rows := make([][]string, res.Frames)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprintf("Frame %d", i+1)}
+	}
+	for _, step := range res.Steps {
+		header = append(header, fmt.Sprint(step.Page))
+		mark := ""
+		if step.Fault {
+			mark = "F"
+		}
+		faults = append(faults, mark)
+		for i, page := range step.Frames {
+			cell := ""
+			if page != paging.Empty {
+				cell = fmt.Sprint(page)
+			}
+			rows[i] = append(rows[i], cell)
+		}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.AppendBulk(rows)
+	table.Append(faults)
+	table.Render()
+	_, _ = fmt.Fprintf(w, "%d faults in %d references (%.0f%%)\n", res.Faults, len(res.Steps), 100*res.FaultRate())
+}
+
+// outputFaultChart prints the faults of each algorithm with 1 to maxFrames
+// frames as bars, then any frame counts where an algorithm showed Belady's
+// anomaly: more faults with more frames.
+func outputFaultChart(w io.Writer, replacers []paging.Replacer, refs []int, maxFrames int) {
+	_, _ = fmt.Fprintln(w, "Faults by frames")
+	header := []string{"Frames"}
+	sweeps := make([][]int, len(replacers))
+	for i, r := range replacers {
+		header = append(header, r.Name())
+		sweeps[i] = paging.Sweep(r, refs, maxFrames)
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for n := 1; n <= maxFrames; n++ {
+		row := []string{fmt.Sprint(n)}
+		for _, faults := range sweeps {
+			row = append(row, fmt.Sprintf("%3d %v", faults[n-1], strings.Repeat("#", faults[n-1]*20/len(refs))))
+		}
+		table.Append(row)
+	}
+	table.Render()
+
+	for i, faults := range sweeps {
+		for _, n := range paging.BeladyAnomalies(faults) {
+			_, _ = fmt.Fprintf(w, "Belady's anomaly: %v has %d faults with %d frames, more than %d with %d\n",
+				replacers[i].Name(), faults[n-1], n, faults[n-2], n-1)
+		}
+	}
+}
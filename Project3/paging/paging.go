@@ -0,0 +1,148 @@
+// Package paging simulates virtual memory: which page a full set of frames
+// gives up when a reference string faults on a page that isn't loaded, and how
+// an MMU translates addresses through a TLB and a two-level page table.
+package paging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidReferences is returned for a reference string that can't be read.
+var ErrInvalidReferences = errors.New("invalid reference string")
+
+// ReadReferences reads a reference string: page numbers separated by commas
+// or white space, skipping anything after a # on a line.
+func ReadReferences(r io.Reader) ([]int, error) {
+	var refs []int
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}) {
+			page, err := strconv.Atoi(field)
+			if err != nil || page < 0 {
+				return nil, fmt.Errorf("line %d: %w: %q is not a page number", line, ErrInvalidReferences, field)
+			}
+			refs = append(refs, page)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// Replacer is a page replacement algorithm. A run tells it of each reference
+// and asks it for a victim when a page faults with every frame loaded.
+type Replacer interface {
+	// Name names the algorithm, such as "LRU".
+	Name() string
+	// Reset starts a run of refs with n frames.
+	Reset(n int, refs []int)
+	// Touch tells of the reference at time t to the page in frame, which was
+	// just loaded if it faulted.
+	Touch(t, frame int, loaded bool)
+	// Victim returns the frame to replace for the fault at time t, given the
+	// page in each frame.
+	Victim(t int, frames []int) int
+}
+
+// Empty marks a frame with no page in it.
+const Empty = -1
+
+// Step is a reference of a run and what came of it.
+type Step struct {
+	Page   int
+	Fault  bool
+	Victim int   // the page replaced, or Empty
+	Frames []int // the page in each frame after the reference
+}
+
+// Result is how a replacement algorithm ran a reference string.
+type Result struct {
+	Name   string
+	Frames int
+	Steps  []Step
+	Faults int
+}
+
+// FaultRate returns the share of references that faulted.
+func (r Result) FaultRate() float64 {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+
+	return float64(r.Faults) / float64(len(r.Steps))
+}
+
+// Simulate runs refs through n frames, replacing pages with alg. The frames
+// start empty and are filled in order before anything is replaced.
+func Simulate(alg Replacer, refs []int, n int) Result {
+	res := Result{Name: alg.Name(), Frames: n, Steps: make([]Step, 0, len(refs))}
+	frames := make([]int, n)
+	for i := range frames {
+		frames[i] = Empty
+	}
+	alg.Reset(n, refs)
+	for t, page := range refs {
+		step := Step{Page: page, Victim: Empty}
+		f := indexOf(frames, page)
+		if f < 0 {
+			step.Fault = true
+			res.Faults++
+			if f = indexOf(frames, Empty); f < 0 {
+				f = alg.Victim(t, frames)
+				step.Victim = frames[f]
+			}
+			frames[f] = page
+		}
+		alg.Touch(t, f, step.Fault)
+		step.Frames = append([]int(nil), frames...)
+		res.Steps = append(res.Steps, step)
+	}
+
+	return res
+}
+
+func indexOf(frames []int, page int) int {
+	for i, p := range frames {
+		if p == page {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Sweep returns the faults alg has on refs with each number of frames from
+// 1 to maxFrames; faults[n-1] is for n frames.
+func Sweep(alg Replacer, refs []int, maxFrames int) []int {
+	faults := make([]int, maxFrames)
+	for n := 1; n <= maxFrames; n++ {
+		faults[n-1] = Simulate(alg, refs, n).Faults
+	}
+
+	return faults
+}
+
+// BeladyAnomalies returns the numbers of frames with more faults than one
+// frame fewer had, from faults as Sweep returns them. FIFO and Clock can have
+// them; stack algorithms such as LRU and Optimal can't.
+func BeladyAnomalies(faults []int) []int {
+	var frames []int
+	for i := 1; i < len(faults); i++ {
+		if faults[i] > faults[i-1] {
+			frames = append(frames, i+1)
+		}
+	}
+
+	return frames
+}
@@ -0,0 +1,104 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// textbook is the reference string of Silberschatz's Operating System
+// Concepts.
+var textbook = []int{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alg        Replacer
+		refs       []int
+		frames     int
+		wantFaults int
+		wantFinal  []int
+	}{
+		{alg: &FIFO{}, refs: textbook, frames: 3, wantFaults: 15, wantFinal: []int{7, 0, 1}},
+		{alg: &LRU{}, refs: textbook, frames: 3, wantFaults: 12, wantFinal: []int{1, 0, 7}},
+		{alg: &Optimal{}, refs: textbook, frames: 3, wantFaults: 9, wantFinal: []int{7, 0, 1}},
+		// The second chance for page 1 runs out as the hand clears every bit.
+		{alg: &Clock{}, refs: []int{1, 2, 3, 1, 4, 5, 1}, frames: 3, wantFaults: 6, wantFinal: []int{4, 5, 1}},
+		{alg: &LRU{}, refs: []int{1, 2, 3, 1, 4, 5, 1}, frames: 3, wantFaults: 5, wantFinal: []int{1, 4, 5}},
+		{alg: &FIFO{}, refs: []int{1, 2}, frames: 4, wantFaults: 2, wantFinal: []int{1, 2, Empty, Empty}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.alg.Name(), func(t *testing.T) {
+			t.Parallel()
+			res := Simulate(tt.alg, tt.refs, tt.frames)
+			if res.Faults != tt.wantFaults {
+				t.Errorf("Faults = %d, want %d", res.Faults, tt.wantFaults)
+			}
+			if got := res.Steps[len(res.Steps)-1].Frames; !reflect.DeepEqual(got, tt.wantFinal) {
+				t.Errorf("final frames = %v, want %v", got, tt.wantFinal)
+			}
+		})
+	}
+}
+
+func TestSimulate_victims(t *testing.T) {
+	t.Parallel()
+	res := Simulate(&FIFO{}, []int{1, 2, 1, 3}, 2)
+	var victims []int
+	for _, step := range res.Steps {
+		victims = append(victims, step.Victim)
+	}
+	if want := []int{Empty, Empty, Empty, 1}; !reflect.DeepEqual(victims, want) {
+		t.Errorf("victims = %v, want %v", victims, want)
+	}
+	if got := res.FaultRate(); got != 0.75 {
+		t.Errorf("FaultRate() = %v, want 0.75", got)
+	}
+}
+
+func TestBeladyAnomalies(t *testing.T) {
+	t.Parallel()
+	refs := []int{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	faults := Sweep(&FIFO{}, refs, 5)
+	if want := []int{12, 12, 9, 10, 5}; !reflect.DeepEqual(faults, want) {
+		t.Fatalf("Sweep(FIFO) = %v, want %v", faults, want)
+	}
+	if got := BeladyAnomalies(faults); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("BeladyAnomalies() = %v, want [4]", got)
+	}
+	for _, alg := range []Replacer{&LRU{}, &Optimal{}} {
+		if got := BeladyAnomalies(Sweep(alg, refs, 5)); got != nil {
+			t.Errorf("BeladyAnomalies(%v) = %v, want none", alg.Name(), got)
+		}
+	}
+}
+
+func TestReadReferences(t *testing.T) {
+	t.Parallel()
+	got, err := ReadReferences(strings.NewReader("# the pages\n1, 2,3\n 4 5 # and more\n\n6\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadReferences() = %v, want %v", got, want)
+	}
+	for _, input := range []string{"1,x", "1 -2"} {
+		if _, err := ReadReferences(strings.NewReader(input)); !errors.Is(err, ErrInvalidReferences) {
+			t.Errorf("ReadReferences(%q) error = %v, want %v", input, err, ErrInvalidReferences)
+		}
+	}
+}
+
+func TestParseReplacer(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"fifo", "LRU", "clock", "optimal"} {
+		if _, err := ParseReplacer(name); err != nil {
+			t.Errorf("ParseReplacer(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseReplacer("random"); err == nil {
+		t.Error("ParseReplacer(random) error = nil")
+	}
+}
@@ -0,0 +1,156 @@
+package paging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Replacers returns each replacement algorithm, in the order they are
+// compared.
+func Replacers() []Replacer {
+	return []Replacer{&FIFO{}, &LRU{}, &Clock{}, &Optimal{}}
+}
+
+// ParseReplacer returns the replacement algorithm named s, such as "lru".
+func ParseReplacer(s string) (Replacer, error) {
+	for _, r := range Replacers() {
+		if strings.EqualFold(r.Name(), s) {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown replacement algorithm %q", s)
+}
+
+// FIFO replaces the page loaded longest ago.
+type FIFO struct {
+	loaded []int // when each frame was loaded
+}
+
+// Name returns "FIFO".
+func (f *FIFO) Name() string { return "FIFO" }
+
+// Reset starts a run with n frames.
+func (f *FIFO) Reset(n int, _ []int) {
+	f.loaded = make([]int, n)
+}
+
+// Touch notes when a frame is loaded.
+func (f *FIFO) Touch(t, frame int, loaded bool) {
+	if loaded {
+		f.loaded[frame] = t
+	}
+}
+
+// Victim returns the frame loaded first.
+func (f *FIFO) Victim(int, []int) int {
+	return oldest(f.loaded)
+}
+
+// LRU replaces the page used longest ago.
+type LRU struct {
+	used []int // when each frame was last used
+}
+
+// Name returns "LRU".
+func (l *LRU) Name() string { return "LRU" }
+
+// Reset starts a run with n frames.
+func (l *LRU) Reset(n int, _ []int) {
+	l.used = make([]int, n)
+}
+
+// Touch notes when a frame is used.
+func (l *LRU) Touch(t, frame int, _ bool) {
+	l.used[frame] = t
+}
+
+// Victim returns the frame used longest ago.
+func (l *LRU) Victim(int, []int) int {
+	return oldest(l.used)
+}
+
+// oldest returns the index of the earliest time of times.
+func oldest(times []int) int {
+	v := 0
+	for i, t := range times {
+		if t < times[v] {
+			v = i
+		}
+	}
+
+	return v
+}
+
+// Clock is second chance FIFO: a hand sweeps the frames in a circle,
+// replacing the first page whose reference bit is clear and clearing the
+// bits it passes. A reference, loads included, sets the page's bit.
+type Clock struct {
+	referenced []bool
+	hand       int
+}
+
+// Name returns "Clock".
+func (c *Clock) Name() string { return "Clock" }
+
+// Reset starts a run with n frames, with the hand at the first.
+func (c *Clock) Reset(n int, _ []int) {
+	c.referenced = make([]bool, n)
+	c.hand = 0
+}
+
+// Touch sets the reference bit of frame.
+func (c *Clock) Touch(_, frame int, _ bool) {
+	c.referenced[frame] = true
+}
+
+// Victim moves the hand to the first frame without its reference bit set,
+// giving each frame it passes a second chance, and returns that frame.
+func (c *Clock) Victim(int, []int) int {
+	for c.referenced[c.hand] {
+		c.referenced[c.hand] = false
+		c.hand = (c.hand + 1) % len(c.referenced)
+	}
+	v := c.hand
+	c.hand = (c.hand + 1) % len(c.referenced)
+
+	return v
+}
+
+// Optimal replaces the page used again furthest in the future, or never.
+// It can't be built, since it knows the future, but no algorithm faults
+// less, so it is the bound the others are measured against.
+type Optimal struct {
+	refs []int
+}
+
+// Name returns "Optimal".
+func (o *Optimal) Name() string { return "Optimal" }
+
+// Reset starts a run of refs.
+func (o *Optimal) Reset(_ int, refs []int) {
+	o.refs = refs
+}
+
+// Touch does nothing: Optimal looks only ahead.
+func (o *Optimal) Touch(int, int, bool) {}
+
+// Victim returns the frame whose page is next used furthest from t, the
+// first of them if more than one is never used again.
+func (o *Optimal) Victim(t int, frames []int) int {
+	v, furthest := 0, -1
+	for i, page := range frames {
+		next := len(o.refs)
+		for u := t + 1; u < len(o.refs); u++ {
+			if o.refs[u] == page {
+				next = u
+				break
+			}
+		}
+		if next > furthest {
+			v, furthest = i, next
+		}
+	}
+
+	return v
+}
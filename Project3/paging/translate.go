@@ -0,0 +1,198 @@
+package paging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ReadAddresses reads virtual addresses, in hex with a 0x prefix or in
+// decimal, separated by commas or white space, skipping anything after a #
+// on a line.
+func ReadAddresses(r io.Reader) ([]uint64, error) {
+	var addrs []uint64
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}) {
+			addr, err := strconv.ParseUint(field, 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w: %q is not an address", line, ErrInvalidReferences, field)
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+// MMU is how addresses are translated: the size of pages and addresses, how
+// the page number is split between the two levels of the page table, and the
+// size of the TLB, with the time each takes.
+type MMU struct {
+	PageSize    uint64 // bytes in a page, a power of two
+	AddressBits int    // bits in a virtual address
+	OuterBits   int    // bits of the page number indexing the outer page table
+	TLBSize     int    // entries in the TLB, or 0 for none
+	MemoryTime  int    // time to access memory, in ns
+	TLBTime     int    // time to look in the TLB, in ns, if there is one
+}
+
+// offsetBits returns the bits of an address within its page.
+func (m MMU) offsetBits() int {
+	bits := 0
+	for uint64(1)<<bits < m.PageSize {
+		bits++
+	}
+
+	return bits
+}
+
+// check returns an error if the MMU can't be built.
+func (m MMU) check() error {
+	switch {
+	case m.PageSize == 0 || m.PageSize&(m.PageSize-1) != 0:
+		return fmt.Errorf("page size %d is not a power of two", m.PageSize)
+	case m.AddressBits <= m.offsetBits() || m.AddressBits > 64:
+		return fmt.Errorf("%d bit addresses don't fit pages of %d bytes", m.AddressBits, m.PageSize)
+	case m.OuterBits <= 0 || m.OuterBits >= m.AddressBits-m.offsetBits():
+		return fmt.Errorf("the outer page table needs from 1 to %d of the %d page number bits",
+			m.AddressBits-m.offsetBits()-1, m.AddressBits-m.offsetBits())
+	case m.TLBSize < 0 || m.MemoryTime < 0 || m.TLBTime < 0:
+		return fmt.Errorf("the TLB size and access times can't be below 0")
+	}
+
+	return nil
+}
+
+// Translation is how a virtual address was translated.
+type Translation struct {
+	Virtual  uint64
+	Page     uint64 // the page number
+	Outer    uint64 // the index into the outer page table
+	Inner    uint64 // the index into the inner page table
+	Offset   uint64
+	Frame    uint64
+	Physical uint64
+	TLBHit   bool
+	Fault    bool // the page had no frame yet
+	Time     int  // ns to reach the byte, not counting a fault
+}
+
+// Translator translates virtual addresses through a TLB and a two-level page
+// table. A page gets the next free frame the first time it is used, as does
+// an inner page table, so nothing is ever replaced.
+type Translator struct {
+	mmu   MMU
+	outer map[uint64]map[uint64]uint64 // the frames of the pages, by outer and inner index
+	tlb   []tlbEntry                   // most recently used first
+	next  uint64                       // the next free frame
+}
+
+// tlbEntry maps a page to its frame.
+type tlbEntry struct {
+	page, frame uint64
+}
+
+// NewTranslator returns a translator for mmu, with an empty page table and
+// TLB.
+func NewTranslator(mmu MMU) (*Translator, error) {
+	if err := mmu.check(); err != nil {
+		return nil, err
+	}
+
+	return &Translator{mmu: mmu, outer: map[uint64]map[uint64]uint64{}}, nil
+}
+
+// Translate translates addr, looking in the TLB, then walking the page
+// table, which takes one memory access a level, and then reaching the byte.
+func (t *Translator) Translate(addr uint64) (Translation, error) {
+	if t.mmu.AddressBits < 64 && addr>>t.mmu.AddressBits != 0 {
+		return Translation{}, fmt.Errorf("address %#x doesn't fit in %d bits", addr, t.mmu.AddressBits)
+	}
+	offsetBits := t.mmu.offsetBits()
+	innerBits := t.mmu.AddressBits - offsetBits - t.mmu.OuterBits
+	tr := Translation{Virtual: addr, Page: addr >> offsetBits, Offset: addr & (t.mmu.PageSize - 1)}
+	tr.Outer, tr.Inner = tr.Page>>innerBits, tr.Page&(1<<innerBits-1)
+	tr.Time = t.mmu.tlbTime()
+
+	if i := t.lookup(tr.Page); i >= 0 {
+		tr.TLBHit, tr.Frame = true, t.tlb[i].frame
+	} else {
+		tr.Time += 2 * t.mmu.MemoryTime
+		inner, ok := t.outer[tr.Outer]
+		if !ok {
+			inner = map[uint64]uint64{}
+			t.outer[tr.Outer] = inner
+			t.next++ // for the inner page table itself
+		}
+		if tr.Frame, ok = inner[tr.Inner]; !ok {
+			tr.Fault, tr.Frame = true, t.next
+			inner[tr.Inner] = t.next
+			t.next++
+		}
+	}
+	t.remember(tr.Page, tr.Frame)
+	tr.Physical = tr.Frame<<offsetBits | tr.Offset
+	tr.Time += t.mmu.MemoryTime
+
+	return tr, nil
+}
+
+// lookup returns the index of page in the TLB, or -1.
+func (t *Translator) lookup(page uint64) int {
+	for i, e := range t.tlb {
+		if e.page == page {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// remember puts page first in the TLB, dropping the least recently used
+// entry if it is full.
+func (t *Translator) remember(page, frame uint64) {
+	if t.mmu.TLBSize == 0 {
+		return
+	}
+	if i := t.lookup(page); i >= 0 {
+		t.tlb = append(t.tlb[:i], t.tlb[i+1:]...)
+	} else if len(t.tlb) == t.mmu.TLBSize {
+		t.tlb = t.tlb[:len(t.tlb)-1]
+	}
+	t.tlb = append([]tlbEntry{{page: page, frame: frame}}, t.tlb...)
+}
+
+// tlbTime returns the time to look in the TLB, 0 if there is none.
+func (m MMU) tlbTime() int {
+	if m.TLBSize == 0 {
+		return 0
+	}
+
+	return m.TLBTime
+}
+
+// InnerTables returns how many inner page tables have been made.
+func (t *Translator) InnerTables() int {
+	return len(t.outer)
+}
+
+// EffectiveAccessTime returns the mean time of a memory access with a TLB
+// hit rate of hitRate: a hit costs the TLB lookup and the access, and a miss
+// the TLB lookup, one access for each level of the page table, and the
+// access.
+func (m MMU) EffectiveAccessTime(hitRate float64) float64 {
+	hit := float64(m.tlbTime() + m.MemoryTime)
+	miss := float64(m.tlbTime() + 3*m.MemoryTime)
+
+	return hitRate*hit + (1-hitRate)*miss
+}
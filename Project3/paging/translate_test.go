@@ -0,0 +1,83 @@
+package paging
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTranslator(t *testing.T) {
+	t.Parallel()
+	// 16 bit addresses with 256 byte pages: 8 bits of page number, 4 for each
+	// level.
+	mmu := MMU{PageSize: 256, AddressBits: 16, OuterBits: 4, TLBSize: 2, MemoryTime: 100, TLBTime: 20}
+	tr, err := NewTranslator(mmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr uint64
+		want Translation
+	}{
+		// The inner table for outer index 1 takes frame 0, the page frame 1.
+		{addr: 0x1234, want: Translation{Page: 0x12, Outer: 1, Inner: 2, Offset: 0x34, Frame: 1, Physical: 0x134, Fault: true, Time: 320}},
+		{addr: 0x12ff, want: Translation{Page: 0x12, Outer: 1, Inner: 2, Offset: 0xff, Frame: 1, Physical: 0x1ff, TLBHit: true, Time: 120}},
+		// Outer index 1 has its inner table already.
+		{addr: 0x1300, want: Translation{Page: 0x13, Outer: 1, Inner: 3, Frame: 2, Physical: 0x200, Fault: true, Time: 320}},
+		{addr: 0xab00, want: Translation{Page: 0xab, Outer: 0xa, Inner: 0xb, Frame: 4, Physical: 0x400, Fault: true, Time: 320}},
+		// The TLB holds two pages, so 0x12 was dropped, but it has a frame.
+		{addr: 0x1200, want: Translation{Page: 0x12, Outer: 1, Inner: 2, Frame: 1, Physical: 0x100, Time: 320}},
+	}
+	for _, tt := range tests {
+		got, err := tr.Translate(tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.want.Virtual = tt.addr
+		if got != tt.want {
+			t.Errorf("Translate(%#x) = %+v, want %+v", tt.addr, got, tt.want)
+		}
+	}
+	if got := tr.InnerTables(); got != 2 {
+		t.Errorf("InnerTables() = %d, want 2", got)
+	}
+	if _, err := tr.Translate(0x10000); err == nil {
+		t.Error("Translate(0x10000) error = nil, want it doesn't fit")
+	}
+	if got := mmu.EffectiveAccessTime(0.8); got != 160 {
+		t.Errorf("EffectiveAccessTime(0.8) = %v, want 160", got)
+	}
+	mmu.TLBSize = 0
+	if got := mmu.EffectiveAccessTime(0); got != 300 {
+		t.Errorf("EffectiveAccessTime() without a TLB = %v, want 300", got)
+	}
+}
+
+func TestNewTranslator(t *testing.T) {
+	t.Parallel()
+	tests := []MMU{
+		{PageSize: 1000, AddressBits: 32, OuterBits: 10},
+		{PageSize: 4096, AddressBits: 12, OuterBits: 1},
+		{PageSize: 4096, AddressBits: 32, OuterBits: 20},
+		{PageSize: 4096, AddressBits: 32, OuterBits: 10, TLBSize: -1},
+	}
+	for _, mmu := range tests {
+		if _, err := NewTranslator(mmu); err == nil {
+			t.Errorf("NewTranslator(%+v) error = nil", mmu)
+		}
+	}
+}
+
+func TestReadAddresses(t *testing.T) {
+	t.Parallel()
+	got, err := ReadAddresses(strings.NewReader("0x1234, 4096 # a page\n0X10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0x1234, 4096, 0x10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAddresses() = %v, want %v", got, want)
+	}
+	if _, err := ReadAddresses(strings.NewReader("0xzz")); err == nil {
+		t.Error("ReadAddresses(0xzz) error = nil")
+	}
+}
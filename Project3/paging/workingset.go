@@ -0,0 +1,224 @@
+package paging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ProcessRef is a reference by a process to one of its pages.
+type ProcessRef struct {
+	Process string
+	Page    int
+}
+
+func (r ProcessRef) String() string {
+	return fmt.Sprintf("%v:%d", r.Process, r.Page)
+}
+
+// ReadProcessReferences reads the references of several processes, each
+// written PROCESS:PAGE, such as A:3, separated by commas or white space,
+// skipping anything after a # on a line.
+func ReadProcessReferences(r io.Reader) ([]ProcessRef, error) {
+	var refs []ProcessRef
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}) {
+			proc, p, ok := strings.Cut(field, ":")
+			page, err := strconv.Atoi(p)
+			if !ok || proc == "" || err != nil || page < 0 {
+				return nil, fmt.Errorf("line %d: %w: %q is not PROCESS:PAGE", line, ErrInvalidReferences, field)
+			}
+			refs = append(refs, ProcessRef{Process: proc, Page: page})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// FramePolicy is how frames are allocated among processes: how big each
+// process's resident set is let to grow, and how it shrinks.
+type FramePolicy interface {
+	// Name names the policy, such as "Working set".
+	Name() string
+	// reference adds the page just referenced to the resident set of p,
+	// after trimming it as the policy says; fault reports that it wasn't
+	// resident.
+	reference(p *process, page int, fault bool)
+}
+
+// WorkingSet keeps the working set of each process resident: the pages it
+// referenced in its last Window references.
+type WorkingSet struct {
+	Window int
+}
+
+// Name returns the name of the policy with its window.
+func (w WorkingSet) Name() string {
+	return fmt.Sprintf("Working set (τ=%d)", w.Window)
+}
+
+func (w WorkingSet) reference(p *process, page int, _ bool) {
+	p.resident[page] = p.time
+	for pg, used := range p.resident {
+		if used <= p.time-w.Window {
+			delete(p.resident, pg)
+		}
+	}
+}
+
+// PFF is page fault frequency allocation, as Chu and Opderbeck put it: when a
+// process faults less than Threshold references after its last fault, it is
+// faulting often, so it gets another frame; when it faults later than that,
+// the pages it hasn't referenced since its last fault are taken away.
+type PFF struct {
+	Threshold int
+}
+
+// Name returns the name of the policy with its threshold.
+func (f PFF) Name() string {
+	return fmt.Sprintf("PFF (T=%d)", f.Threshold)
+}
+
+func (f PFF) reference(p *process, page int, fault bool) {
+	if fault {
+		if p.lastFault > 0 && p.time-p.lastFault > f.Threshold {
+			for pg, used := range p.resident {
+				if used <= p.lastFault {
+					delete(p.resident, pg)
+				}
+			}
+		}
+		p.lastFault = p.time
+	}
+	p.resident[page] = p.time
+}
+
+// process is the state of a process in a run.
+type process struct {
+	ProcessStats
+	resident  map[int]int // the time each resident page was last used
+	time      int         // the references the process has made
+	lastFault int         // the time of its last fault, or 0
+}
+
+// ProcessStats is how a process fared in a run.
+type ProcessStats struct {
+	Name         string
+	References   int
+	Faults       int
+	PeakResident int
+	Suspensions  int // times it was swapped out to stop thrashing
+}
+
+// MultiStep is a reference of a multiprogrammed run and what came of it.
+type MultiStep struct {
+	Ref       ProcessRef
+	Fault     bool
+	Thrashing bool   // the resident sets needed more frames than there are
+	Suspended string // the process swapped out to make room, if any
+	Resident  []int  // the size of each process's resident set after it
+}
+
+// MultiResult is how a frame policy ran the references of several processes.
+type MultiResult struct {
+	Name      string
+	Frames    int
+	Processes []ProcessStats // in the order they first referenced a page
+	Steps     []MultiStep
+	Thrashing int // thrashing events
+}
+
+// SimulateProcesses runs refs through frames shared by the processes making
+// them, allocated by policy. When the resident sets need more frames than
+// there are, the system is thrashing: the process with the biggest resident
+// set, other than the one referencing, is suspended and swapped out, freeing
+// its frames until its next reference. If no other process holds a frame,
+// the referencing process loses its least recently used pages instead.
+func SimulateProcesses(policy FramePolicy, refs []ProcessRef, frames int) MultiResult {
+	res := MultiResult{Name: policy.Name(), Frames: frames}
+	var procs []*process
+	index := map[string]int{}
+	for _, ref := range refs {
+		if _, ok := index[ref.Process]; !ok {
+			index[ref.Process] = len(procs)
+			procs = append(procs, &process{ProcessStats: ProcessStats{Name: ref.Process}, resident: map[int]int{}})
+		}
+	}
+
+	for _, ref := range refs {
+		p := procs[index[ref.Process]]
+		p.time++
+		p.References++
+		_, resident := p.resident[ref.Page]
+		step := MultiStep{Ref: ref, Fault: !resident}
+		if step.Fault {
+			p.Faults++
+		}
+		policy.reference(p, ref.Page, step.Fault)
+
+		if used(procs) > frames {
+			step.Thrashing = true
+			res.Thrashing++
+			var victim *process
+			for _, other := range procs {
+				if other != p && len(other.resident) > 0 && (victim == nil || len(other.resident) > len(victim.resident)) {
+					victim = other
+				}
+			}
+			if victim != nil {
+				victim.resident = map[int]int{}
+				victim.Suspensions++
+				step.Suspended = victim.Name
+			}
+			for used(procs) > frames {
+				delete(p.resident, leastRecent(p.resident))
+			}
+		}
+
+		step.Resident = make([]int, len(procs))
+		for i, proc := range procs {
+			step.Resident[i] = len(proc.resident)
+			if len(proc.resident) > proc.PeakResident {
+				proc.PeakResident = len(proc.resident)
+			}
+		}
+		res.Steps = append(res.Steps, step)
+	}
+	for _, p := range procs {
+		res.Processes = append(res.Processes, p.ProcessStats)
+	}
+
+	return res
+}
+
+// used returns the frames the resident sets of procs take.
+func used(procs []*process) int {
+	n := 0
+	for _, p := range procs {
+		n += len(p.resident)
+	}
+
+	return n
+}
+
+// leastRecent returns the page of resident used longest ago.
+func leastRecent(resident map[int]int) int {
+	page, oldest := 0, -1
+	for pg, used := range resident {
+		if oldest < 0 || used < oldest || used == oldest && pg < page {
+			page, oldest = pg, used
+		}
+	}
+
+	return page
+}
@@ -0,0 +1,122 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// refsOf reads the references of s, which must be valid.
+func refsOf(s string) []ProcessRef {
+	refs, err := ReadProcessReferences(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+
+	return refs
+}
+
+func TestSimulateProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		policy        FramePolicy
+		refs          string
+		frames        int
+		wantFaults    []int
+		wantResident  []int // of the first process, after each step
+		wantSuspended []string
+	}{
+		{
+			name:         "working set drops pages out of the window",
+			policy:       WorkingSet{Window: 2},
+			refs:         "A:1 A:2 A:3 A:1",
+			frames:       10,
+			wantFaults:   []int{4},
+			wantResident: []int{1, 2, 2, 2},
+		},
+		{
+			name:         "PFF drops pages unused since a late fault",
+			policy:       PFF{Threshold: 2},
+			refs:         "A:1 A:2 A:1 A:1 A:1 A:3",
+			frames:       10,
+			wantFaults:   []int{3},
+			wantResident: []int{1, 2, 2, 2, 2, 2},
+		},
+		{
+			name:          "thrashing suspends another process",
+			policy:        WorkingSet{Window: 10},
+			refs:          "A:1 A:2 B:1 B:2 A:1",
+			frames:        3,
+			wantFaults:    []int{3, 2},
+			wantResident:  []int{1, 2, 2, 0, 1},
+			wantSuspended: []string{"", "", "", "A", ""},
+		},
+		{
+			name:         "thrashing alone trims the process",
+			policy:       PFF{Threshold: 5},
+			refs:         "A:1 A:2 A:3 A:2",
+			frames:       2,
+			wantFaults:   []int{3},
+			wantResident: []int{1, 2, 2, 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := SimulateProcesses(tt.policy, refsOf(tt.refs), tt.frames)
+			var faults, resident []int
+			var suspended []string
+			for _, p := range res.Processes {
+				faults = append(faults, p.Faults)
+			}
+			for _, step := range res.Steps {
+				resident = append(resident, step.Resident[0])
+				suspended = append(suspended, step.Suspended)
+			}
+			if !reflect.DeepEqual(faults, tt.wantFaults) {
+				t.Errorf("faults = %v, want %v", faults, tt.wantFaults)
+			}
+			if !reflect.DeepEqual(resident, tt.wantResident) {
+				t.Errorf("resident = %v, want %v", resident, tt.wantResident)
+			}
+			if tt.wantSuspended != nil && !reflect.DeepEqual(suspended, tt.wantSuspended) {
+				t.Errorf("suspended = %q, want %q", suspended, tt.wantSuspended)
+			}
+		})
+	}
+}
+
+func TestSimulateProcesses_stats(t *testing.T) {
+	t.Parallel()
+	res := SimulateProcesses(WorkingSet{Window: 10}, refsOf("A:1 A:2 B:1 B:2 A:1"), 3)
+	want := []ProcessStats{
+		{Name: "A", References: 3, Faults: 3, PeakResident: 2, Suspensions: 1},
+		{Name: "B", References: 2, Faults: 2, PeakResident: 2},
+	}
+	if !reflect.DeepEqual(res.Processes, want) {
+		t.Errorf("Processes = %+v, want %+v", res.Processes, want)
+	}
+	if res.Thrashing != 1 {
+		t.Errorf("Thrashing = %d, want 1", res.Thrashing)
+	}
+}
+
+func TestReadProcessReferences(t *testing.T) {
+	t.Parallel()
+	got, err := ReadProcessReferences(strings.NewReader("A:1, B:20 # B starts\nA:3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ProcessRef{{Process: "A", Page: 1}, {Process: "B", Page: 20}, {Process: "A", Page: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadProcessReferences() = %v, want %v", got, want)
+	}
+	for _, input := range []string{"A1", ":1", "A:x", "A:-1"} {
+		if _, err := ReadProcessReferences(strings.NewReader(input)); !errors.Is(err, ErrInvalidReferences) {
+			t.Errorf("ReadProcessReferences(%q) error = %v, want %v", input, err, ErrInvalidReferences)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jar0582/CSCE4600/Project3/paging"
+	"github.com/olekukonko/tablewriter"
+)
+
+// tlbCommand translates virtual addresses through a TLB and a two-level page
+// table, printing each translation and then the TLB hit rate and the
+// effective access time.
+func tlbCommand(w io.Writer, args ...string) error {
+	var mmu paging.MMU
+	fs := flag.NewFlagSet("tlb", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Uint64Var(&mmu.PageSize, "page", 4096, "the page size in `BYTES`, a power of two")
+	fs.IntVar(&mmu.AddressBits, "bits", 32, "the `BITS` of a virtual address")
+	fs.IntVar(&mmu.OuterBits, "outer", 0, "the `BITS` of the page number indexing the outer page table (default half)")
+	fs.IntVar(&mmu.TLBSize, "tlb", 4, "the `ENTRIES` of the TLB, or 0 for none")
+	fs.IntVar(&mmu.MemoryTime, "mem", 100, "the time of a memory access in `NS`")
+	fs.IntVar(&mmu.TLBTime, "tlbtime", 20, "the time of a TLB lookup in `NS`")
+	quiet := fs.Bool("q", false, "only print the summary, not each translation")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if mmu.OuterBits == 0 {
+		pageBits := mmu.AddressBits
+		for size := mmu.PageSize; size > 1; size >>= 1 {
+			pageBits--
+		}
+		mmu.OuterBits = (pageBits + 1) / 2
+	}
+	tr, err := paging.NewTranslator(mmu)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+
+	f, err := openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	addrs, err := paging.ReadAddresses(f)
+	if err != nil {
+		return err
+	}
+
+	translations := make([]paging.Translation, len(addrs))
+	for i, addr := range addrs {
+		if translations[i], err = tr.Translate(addr); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+	}
+	if !*quiet {
+		outputTitle(w, "Address translation")
+		outputTranslations(w, translations)
+	}
+	outputAccessTime(w, mmu, translations, tr.InnerTables())
+
+	return nil
+}
+
+// outputTranslations prints how each address was translated.
+func outputTranslations(w io.Writer, translations []paging.Translation) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Virtual", "Page", "Outer", "Inner", "Offset", "TLB", "Frame", "Physical", "Time"})
+	for _, tr := range translations {
+		tlb := "miss"
+		switch {
+		case tr.TLBHit:
+			tlb = "hit"
+		case tr.Fault:
+			tlb = "miss, fault"
+		}
+		table.Append([]string{
+			fmt.Sprintf("%#x", tr.Virtual),
+			fmt.Sprintf("%#x", tr.Page),
+			fmt.Sprintf("%#x", tr.Outer),
+			fmt.Sprintf("%#x", tr.Inner),
+			fmt.Sprintf("%#x", tr.Offset),
+			tlb,
+			fmt.Sprint(tr.Frame),
+			fmt.Sprintf("%#x", tr.Physical),
+			fmt.Sprintf("%dns", tr.Time),
+		})
+	}
+	table.Render()
+}
+
+// outputAccessTime prints the TLB hit rate, the faults and the effective
+// access time, as measured and as the formula gives it for the hit rate.
+func outputAccessTime(w io.Writer, mmu paging.MMU, translations []paging.Translation, innerTables int) {
+	var hits, faults, total int
+	for _, tr := range translations {
+		if tr.TLBHit {
+			hits++
+		}
+		if tr.Fault {
+			faults++
+		}
+		total += tr.Time
+	}
+	var hitRate, measured float64
+	if n := len(translations); n > 0 {
+		hitRate, measured = float64(hits)/float64(n), float64(total)/float64(n)
+	}
+
+	_, _ = fmt.Fprintln(w, "Summary")
+	table := tablewriter.NewWriter(w)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk([][]string{
+		{"Addresses", fmt.Sprint(len(translations))},
+		{"TLB hits", fmt.Sprintf("%d (%.0f%%)", hits, 100*hitRate)},
+		{"Page faults", fmt.Sprint(faults)},
+		{"Inner page tables", fmt.Sprint(innerTables)},
+		{"Effective access time", fmt.Sprintf("%.1fns", measured)},
+		{"EAT = h(TLB+mem) + (1-h)(TLB+3mem)", fmt.Sprintf("%.1fns", mmu.EffectiveAccessTime(hitRate))},
+		{"Without a TLB", fmt.Sprintf("%dns", 3*mmu.MemoryTime)},
+	})
+	table.Render()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jar0582/CSCE4600/Project3/paging"
+	"github.com/olekukonko/tablewriter"
+)
+
+// workingSetCommand runs the references of several processes through shared
+// frames with each frame allocation policy chosen with -policy, printing the
+// resident set of each process after every reference, and then how each
+// process fared.
+func workingSetCommand(w io.Writer, args ...string) error {
+	fs := flag.NewFlagSet("workingset", flag.ContinueOnError)
+	fs.SetOutput(w)
+	frames := fs.Int("frames", 8, "the number of `FRAMES` the processes share")
+	window := fs.Int("tau", 4, "the working set window, in `REFERENCES` of a process")
+	threshold := fs.Int("pff", 3, "the PFF threshold, in `REFERENCES` of a process between faults")
+	policy := fs.String("policy", "all", "the frame allocation `POLICY`: ws, pff or all")
+	quiet := fs.Bool("q", false, "only print how each process fared, not each reference")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if *frames <= 0 || *window <= 0 || *threshold <= 0 {
+		return fmt.Errorf("%w: -frames, -tau and -pff must be above 0", ErrInvalidArgs)
+	}
+	var policies []paging.FramePolicy
+	switch *policy {
+	case "ws":
+		policies = []paging.FramePolicy{paging.WorkingSet{Window: *window}}
+	case "pff":
+		policies = []paging.FramePolicy{paging.PFF{Threshold: *threshold}}
+	case "all":
+		policies = []paging.FramePolicy{paging.WorkingSet{Window: *window}, paging.PFF{Threshold: *threshold}}
+	default:
+		return fmt.Errorf("%w: unknown frame allocation policy %q", ErrInvalidArgs, *policy)
+	}
+
+	f, err := openInput(fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	refs, err := paging.ReadProcessReferences(f)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		res := paging.SimulateProcesses(p, refs, *frames)
+		outputTitle(w, res.Name)
+		if !*quiet {
+			outputResidentSets(w, res)
+		}
+		outputProcesses(w, res)
+	}
+
+	return nil
+}
+
+// outputResidentSets prints the size of each process's resident set after
+// every reference, with the thrashing events.
+func outputResidentSets(w io.Writer, res paging.MultiResult) {
+	header := []string{"Time", "Reference", "Fault"}
+	for _, p := range res.Processes {
+		header = append(header, p.Name)
+	}
+	header = append(header, "Frames", "Event")
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for t, step := range res.Steps {
+		fault := ""
+		if step.Fault {
+			fault = "F"
+		}
+		row := []string{fmt.Sprint(t + 1), step.Ref.String(), fault}
+		total := 0
+		for _, n := range step.Resident {
+			row = append(row, fmt.Sprint(n))
+			total += n
+		}
+		event := ""
+		switch {
+		case step.Suspended != "":
+			event = "thrashing: suspended " + step.Suspended
+		case step.Thrashing:
+			event = "thrashing: trimmed " + step.Ref.Process
+		}
+		row = append(row, fmt.Sprintf("%d/%d", total, res.Frames), event)
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// outputProcesses prints how each process fared over the run.
+func outputProcesses(w io.Writer, res paging.MultiResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Process", "References", "Faults", "Fault rate", "Peak resident", "Suspended"})
+	for _, p := range res.Processes {
+		rate := 0.0
+		if p.References > 0 {
+			rate = float64(p.Faults) / float64(p.References)
+		}
+		table.Append([]string{
+			p.Name,
+			fmt.Sprint(p.References),
+			fmt.Sprint(p.Faults),
+			fmt.Sprintf("%.0f%%", 100*rate),
+			fmt.Sprint(p.PeakResident),
+			fmt.Sprint(p.Suspensions),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "%d thrashing %v\n", res.Thrashing, plural(res.Thrashing, "event", "events"))
+}
+
+// plural returns one if n is 1, and many if not.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+
+	return many
+}
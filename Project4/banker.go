@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project4/deadlock"
+	"github.com/olekukonko/tablewriter"
+)
+
+// bankerCommand reads a state of the Banker's algorithm, prints it with its
+// safe sequence, and answers each request the file has. With -i, it then
+// reads more requests and releases from in, one a line.
+func bankerCommand(in io.Reader, w io.Writer, args ...string) error {
+	fs := flag.NewFlagSet("banker", flag.ContinueOnError)
+	fs.SetOutput(w)
+	interactive := fs.Bool("i", false, "step through requests and releases read from stdin")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if *interactive && (fs.NArg() == 0 || fs.Arg(0) == "-") {
+		return fmt.Errorf("%w: -i reads stdin, so the state must be in a file", ErrInvalidArgs)
+	}
+
+	f, err := openInput(in, fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, queries, err := deadlock.LoadState(f)
+	if err != nil {
+		return err
+	}
+
+	outputTitle(w, "Banker's algorithm")
+	outputState(w, s)
+	steps, ok := s.Safe()
+	outputSafety(w, s, steps, ok)
+	for _, q := range queries {
+		s = outputRequest(w, s, q)
+	}
+	if *interactive {
+		return bankerSession(in, w, s)
+	}
+
+	return nil
+}
+
+// bankerSession reads commands from in, one a line, until it ends or reads
+// quit.
+func bankerSession(in io.Reader, w io.Writer, s deadlock.State) error {
+	const help = `request PROCESS N...   ask for N of each resource
+release PROCESS N...   give back N of each resource
+state                  print the state
+safe                   run the safety algorithm
+quit                   stop`
+	sc := bufio.NewScanner(in)
+	for {
+		_, _ = fmt.Fprint(w, "banker> ")
+		if !sc.Scan() {
+			_, _ = fmt.Fprintln(w)
+			return sc.Err()
+		}
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "request", "release":
+			if len(fields) < 2 {
+				_, _ = fmt.Fprintf(w, "usage: %v PROCESS N...\n", fields[0])
+				continue
+			}
+			v, err := deadlock.ParseVector(fields[2:])
+			if err != nil {
+				_, _ = fmt.Fprintln(w, err)
+				continue
+			}
+			if fields[0] == "request" {
+				s = outputRequest(w, s, deadlock.Query{Process: fields[1], Request: v})
+				continue
+			}
+			next, err := s.Release(s.Process(fields[1]), v)
+			if err != nil {
+				_, _ = fmt.Fprintf(w, "Release %v (%v): %v\n", fields[1], v, err)
+				continue
+			}
+			s = next
+			_, _ = fmt.Fprintf(w, "Release %v (%v): available is now %v\n", fields[1], v, s.Available)
+		case "state":
+			outputState(w, s)
+		case "safe":
+			steps, ok := s.Safe()
+			outputSafety(w, s, steps, ok)
+		case "help":
+			_, _ = fmt.Fprintln(w, help)
+		case "quit", "exit":
+			return nil
+		default:
+			_, _ = fmt.Fprintf(w, "unknown command %q; try help\n", fields[0])
+		}
+	}
+}
+
+// outputState prints what each process holds, may hold, and still needs, and
+// what is available.
+func outputState(w io.Writer, s deadlock.State) {
+	res := strings.Join(s.Resources, " ")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Process", "Allocation", "Max", "Need"})
+	table.Append([]string{"", res, res, res})
+	for i, p := range s.Processes {
+		table.Append([]string{p, s.Allocation[i].String(), s.Max[i].String(), s.Need(i).String()})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Available: %v\n", s.Available)
+}
+
+// outputSafety prints the steps of the safety algorithm and whether the state
+// is safe.
+func outputSafety(w io.Writer, s deadlock.State, steps []deadlock.SafetyStep, safe bool) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Step", "Process", "Work", "Need", "Work after"})
+	for i, step := range steps {
+		table.Append([]string{
+			fmt.Sprint(i + 1),
+			s.Processes[step.Process],
+			step.Work.String(),
+			step.Need.String(),
+			step.After.String(),
+		})
+	}
+	table.Render()
+	if safe {
+		_, _ = fmt.Fprintf(w, "Safe: the safe sequence is <%v>\n", strings.Join(s.SafeSequence(steps), ", "))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "UNSAFE: no process can finish after <%v>\n", strings.Join(s.SafeSequence(steps), ", "))
+}
+
+// outputRequest prints whether q is granted, and returns the state after it.
+func outputRequest(w io.Writer, s deadlock.State, q deadlock.Query) deadlock.State {
+	next, steps, err := s.Request(s.Process(q.Process), q.Request)
+	switch {
+	case errors.Is(err, deadlock.ErrUnavailable), errors.Is(err, deadlock.ErrUnsafe):
+		_, _ = fmt.Fprintf(w, "Request %v (%v): must wait: %v\n", q.Process, q.Request, err)
+		return s
+	case err != nil:
+		_, _ = fmt.Fprintf(w, "Request %v (%v): refused: %v\n", q.Process, q.Request, err)
+		return s
+	}
+	_, _ = fmt.Fprintf(w, "Request %v (%v): granted, with the safe sequence <%v>; available is now %v\n",
+		q.Process, q.Request, strings.Join(next.SafeSequence(steps), ", "), next.Available)
+
+	return next
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project4/buffer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// bufferCommand runs producers and consumers against a bounded buffer with
+// each backend chosen with -backend, printing how each worker did and how
+// full the buffer was over time, then compares the backends.
+func bufferCommand(_ io.Reader, w io.Writer, args ...string) error {
+	var cfg buffer.Config
+	fs := flag.NewFlagSet("buffer", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.IntVar(&cfg.Producers, "producers", 2, "the number of `PRODUCERS`")
+	fs.IntVar(&cfg.Consumers, "consumers", 2, "the number of `CONSUMERS`")
+	fs.IntVar(&cfg.Size, "size", 8, "how many `ITEMS` the buffer holds")
+	backend := fs.String("backend", "all", "the `BACKEND` of the buffer: channel, semaphore, monitor, or all")
+	produce := fs.String("produce", "10ms", "the mean `TIMES` to produce an item, comma-separated by producer")
+	consume := fs.String("consume", "10ms", "the mean `TIMES` to consume an item, comma-separated by consumer")
+	fs.DurationVar(&cfg.Duration, "time", 2*time.Second, "how long each simulation lasts, as a `TIME`")
+	fs.DurationVar(&cfg.Sample, "sample", 100*time.Millisecond, "how often to sample the buffer, as a `TIME`")
+	fs.Int64Var(&cfg.Seed, "seed", 1, "the `SEED` of how long items take")
+	quiet := fs.Bool("q", false, "only print the comparison, not each simulation")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: buffer reads no file: %v", ErrInvalidArgs, strings.Join(fs.Args(), " "))
+	}
+	var err error
+	if cfg.Produce, err = parseDurations(*produce); err != nil {
+		return fmt.Errorf("%w: -produce: %v", ErrInvalidArgs, err)
+	}
+	if cfg.Consume, err = parseDurations(*consume); err != nil {
+		return fmt.Errorf("%w: -consume: %v", ErrInvalidArgs, err)
+	}
+	backends := buffer.Backends
+	if *backend != "all" {
+		b, err := buffer.ParseBackend(*backend)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		backends = []buffer.Backend{b}
+	}
+
+	var results []buffer.Result
+	for _, b := range backends {
+		cfg.Backend = b
+		res, err := buffer.Run(cfg)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		if !*quiet {
+			outputTitle(w, "Bounded buffer: "+b.String())
+			outputWorkers(w, res)
+			outputOccupancy(w, res)
+		}
+		results = append(results, res)
+	}
+	outputBuffers(w, results)
+
+	return nil
+}
+
+// parseDurations reads comma-separated durations.
+func parseDurations(s string) ([]time.Duration, error) {
+	var ds []time.Duration
+	for _, f := range strings.Split(s, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// outputWorkers prints how many items each producer and consumer handled,
+// and how long each waited on the buffer.
+func outputWorkers(w io.Writer, res buffer.Result) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Worker", "Mean time", "Items", "Items/s", "Blocked", "Blocked %"})
+	table.SetAutoFormatHeaders(false)
+	add := func(name string, i int, wk buffer.Worker) {
+		table.Append([]string{
+			fmt.Sprintf("%v %d", name, i),
+			wk.Mean.String(),
+			fmt.Sprint(wk.Items),
+			fmt.Sprintf("%.1f", float64(wk.Items)/res.Elapsed.Seconds()),
+			wk.Blocked.Round(time.Millisecond).String(),
+			fmt.Sprintf("%.1f%%", 100*float64(wk.Blocked)/float64(res.Elapsed)),
+		})
+	}
+	for i, p := range res.Producers {
+		add("producer", i, p)
+	}
+	for i, c := range res.Consumers {
+		add("consumer", i, c)
+	}
+	table.Render()
+}
+
+// outputOccupancy prints how many items the buffer held at each sample, with
+// a bar of its slots.
+func outputOccupancy(w io.Writer, res buffer.Result) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Time", "Items", "Buffer"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	for _, s := range res.Samples {
+		table.Append([]string{
+			fmt.Sprintf("%.2fs", s.At.Seconds()),
+			fmt.Sprint(s.Len),
+			"|" + occupancyBar(s.Len, res.Size, 40) + "|",
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Produced %d, consumed %d, %d left in the buffer; %.1f items/s, with %v from producing an item to taking it.\n",
+		res.Produced(), res.Consumed(), res.Left, res.Throughput(), res.Latency.Round(100*time.Microsecond))
+}
+
+// occupancyBar draws n of size slots full, in at most width characters.
+func occupancyBar(n, size, width int) string {
+	if size < width {
+		width = size
+	}
+	full := n * width / size
+
+	return strings.Repeat("#", full) + strings.Repeat(".", width-full)
+}
+
+// outputBuffers prints how the producers and consumers did with each
+// backend.
+func outputBuffers(w io.Writer, results []buffer.Result) {
+	_, _ = fmt.Fprintln(w, "Comparison")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Backend", "Produced", "Consumed", "Items/s", "Producers blocked", "Consumers blocked",
+		"Mean occupancy", "Full", "Empty", "Latency"})
+	table.SetAutoFormatHeaders(false)
+	for _, res := range results {
+		producers, consumers := res.Blocked()
+		mean, full, empty := res.Occupancy()
+		table.Append([]string{
+			res.Backend.String(),
+			fmt.Sprint(res.Produced()),
+			fmt.Sprint(res.Consumed()),
+			fmt.Sprintf("%.1f", res.Throughput()),
+			fmt.Sprintf("%.1f%%", 100*producers),
+			fmt.Sprintf("%.1f%%", 100*consumers),
+			fmt.Sprintf("%.1f%%", 100*mean),
+			fmt.Sprintf("%.0f%%", 100*full),
+			fmt.Sprintf("%.0f%%", 100*empty),
+			res.Latency.Round(100 * time.Microsecond).String(),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,273 @@
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend is how a bounded buffer makes producers and consumers wait.
+type Backend int
+
+// The backends.
+const (
+	Channel   Backend = iota // a buffered channel
+	Semaphore                // counting semaphores of empty and full slots, and a mutex
+	Monitor                  // a mutex with condition variables for not full and not empty
+)
+
+// Backends are the backends, in the order they are compared.
+var Backends = []Backend{Channel, Semaphore, Monitor}
+
+func (b Backend) String() string {
+	switch b {
+	case Channel:
+		return "channel"
+	case Semaphore:
+		return "semaphore"
+	case Monitor:
+		return "monitor"
+	default:
+		return fmt.Sprintf("Backend(%d)", int(b))
+	}
+}
+
+// ParseBackend returns the backend named s, such as "channel".
+func ParseBackend(s string) (Backend, error) {
+	for _, b := range Backends {
+		if strings.EqualFold(b.String(), s) {
+			return b, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown backend %q", s)
+}
+
+// item is what producers put in a buffer: when it was produced.
+type item time.Time
+
+// buffer is a bounded buffer of items, which stops once the context it was
+// made with is done.
+type buffer interface {
+	// put blocks until there is room for it, or the buffer stops.
+	put(it item) error
+	// get blocks until there is an item to take, or the buffer stops.
+	get() (item, error)
+	// len returns how many items the buffer holds.
+	len() int
+}
+
+func newBuffer(ctx context.Context, b Backend, size int) buffer {
+	switch b {
+	case Semaphore:
+		return newSemBuffer(ctx, size)
+	case Monitor:
+		return newMonitorBuffer(ctx, size)
+	default:
+		return &chanBuffer{ctx: ctx, ch: make(chan item, size)}
+	}
+}
+
+type chanBuffer struct {
+	ctx context.Context
+	ch  chan item
+}
+
+func (b *chanBuffer) put(it item) error {
+	select {
+	case b.ch <- it:
+		return nil
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
+
+func (b *chanBuffer) get() (item, error) {
+	select {
+	case it := <-b.ch:
+		return it, nil
+	case <-b.ctx.Done():
+		return item{}, b.ctx.Err()
+	}
+}
+
+func (b *chanBuffer) len() int {
+	return len(b.ch)
+}
+
+// ring is a queue of items in a fixed array.
+type ring struct {
+	items []item
+	head  int // the oldest item
+	n     int
+}
+
+func (r *ring) push(it item) {
+	r.items[(r.head+r.n)%len(r.items)] = it
+	r.n++
+}
+
+func (r *ring) pop() item {
+	it := r.items[r.head]
+	r.head = (r.head + 1) % len(r.items)
+	r.n--
+
+	return it
+}
+
+// stopOnDone sets stopped to ctx's error and wakes the waiters of conds once
+// ctx is done.
+func stopOnDone(ctx context.Context, mu *sync.Mutex, stopped *error, conds ...*sync.Cond) {
+	go func() {
+		<-ctx.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		*stopped = ctx.Err()
+		for _, c := range conds {
+			c.Broadcast()
+		}
+	}()
+}
+
+// semaphore is a counting semaphore.
+type semaphore struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	n       int
+	stopped error
+}
+
+func newSemaphore(ctx context.Context, n int) *semaphore {
+	s := &semaphore{n: n}
+	s.cond = sync.NewCond(&s.mu)
+	stopOnDone(ctx, &s.mu, &s.stopped, s.cond)
+
+	return s
+}
+
+// wait takes one from the semaphore, blocking while it is 0: Dijkstra's P.
+func (s *semaphore) wait() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.n == 0 {
+		if s.stopped != nil {
+			return s.stopped
+		}
+		s.cond.Wait()
+	}
+	s.n--
+
+	return nil
+}
+
+// signal adds one to the semaphore: Dijkstra's V.
+func (s *semaphore) signal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.n++
+	s.cond.Signal()
+}
+
+// semBuffer is the textbook bounded buffer: a producer waits for an empty
+// slot and signals a full one, and a consumer the other way around, with a
+// mutex around the slots.
+type semBuffer struct {
+	empty, full *semaphore
+	mu          sync.Mutex
+	ring        ring
+}
+
+func newSemBuffer(ctx context.Context, size int) *semBuffer {
+	return &semBuffer{
+		empty: newSemaphore(ctx, size),
+		full:  newSemaphore(ctx, 0),
+		ring:  ring{items: make([]item, size)},
+	}
+}
+
+func (b *semBuffer) put(it item) error {
+	if err := b.empty.wait(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.ring.push(it)
+	b.mu.Unlock()
+	b.full.signal()
+
+	return nil
+}
+
+func (b *semBuffer) get() (item, error) {
+	if err := b.full.wait(); err != nil {
+		return item{}, err
+	}
+	b.mu.Lock()
+	it := b.ring.pop()
+	b.mu.Unlock()
+	b.empty.signal()
+
+	return it, nil
+}
+
+func (b *semBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ring.n
+}
+
+// monitorBuffer is a bounded buffer as a monitor: producers wait while it is
+// full, and consumers while it is empty.
+type monitorBuffer struct {
+	mu                sync.Mutex
+	notFull, notEmpty *sync.Cond
+	ring              ring
+	stopped           error
+}
+
+func newMonitorBuffer(ctx context.Context, size int) *monitorBuffer {
+	b := &monitorBuffer{ring: ring{items: make([]item, size)}}
+	b.notFull, b.notEmpty = sync.NewCond(&b.mu), sync.NewCond(&b.mu)
+	stopOnDone(ctx, &b.mu, &b.stopped, b.notFull, b.notEmpty)
+
+	return b
+}
+
+func (b *monitorBuffer) put(it item) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.ring.n == len(b.ring.items) {
+		if b.stopped != nil {
+			return b.stopped
+		}
+		b.notFull.Wait()
+	}
+	b.ring.push(it)
+	b.notEmpty.Signal()
+
+	return nil
+}
+
+func (b *monitorBuffer) get() (item, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.ring.n == 0 {
+		if b.stopped != nil {
+			return item{}, b.stopped
+		}
+		b.notEmpty.Wait()
+	}
+	it := b.ring.pop()
+	b.notFull.Signal()
+
+	return it, nil
+}
+
+func (b *monitorBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ring.n
+}
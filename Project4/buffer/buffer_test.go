@@ -0,0 +1,106 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseBackend(t *testing.T) {
+	t.Parallel()
+	for _, b := range Backends {
+		if got, err := ParseBackend(b.String()); err != nil || got != b {
+			t.Errorf("ParseBackend(%q) = %v, %v, want %v", b.String(), got, err, b)
+		}
+	}
+	if _, err := ParseBackend("pipe"); err == nil {
+		t.Error("ParseBackend(\"pipe\") error = nil, want one")
+	}
+}
+
+func Test_buffer(t *testing.T) {
+	t.Parallel()
+	for _, b := range Backends {
+		b := b
+		t.Run(b.String(), func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			buf := newBuffer(ctx, b, 2)
+			first, second := item(time.Unix(1, 0)), item(time.Unix(2, 0))
+			for _, it := range []item{first, second} {
+				if err := buf.put(it); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := buf.len(); got != 2 {
+				t.Errorf("len() = %d, want 2", got)
+			}
+			if it, err := buf.get(); err != nil || it != first {
+				t.Errorf("get() = %v, %v, want the first item", it, err)
+			}
+			if err := buf.put(first); err != nil {
+				t.Fatal(err)
+			}
+			// A full buffer blocks until it is stopped.
+			time.AfterFunc(10*time.Millisecond, cancel)
+			if err := buf.put(first); !errors.Is(err, context.Canceled) {
+				t.Errorf("put() on a full buffer = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	for _, b := range Backends {
+		b := b
+		t.Run(b.String(), func(t *testing.T) {
+			t.Parallel()
+			// Fast producers fill the buffer, so they block on it and the
+			// consumers rarely do.
+			cfg := Config{Producers: 3, Consumers: 2, Size: 4, Backend: b,
+				Produce: []time.Duration{time.Millisecond}, Consume: []time.Duration{5 * time.Millisecond},
+				Duration: 300 * time.Millisecond, Sample: 10 * time.Millisecond}
+			res, err := Run(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Produced() != res.Consumed()+res.Left {
+				t.Errorf("Run() produced %d, want the %d consumed and %d left", res.Produced(), res.Consumed(), res.Left)
+			}
+			if res.Consumed() == 0 || res.Throughput() <= 0 {
+				t.Errorf("Run() consumed %d, want some", res.Consumed())
+			}
+			producers, consumers := res.Blocked()
+			if producers <= consumers {
+				t.Errorf("Run() blocked producers %.2f, consumers %.2f, want producers more", producers, consumers)
+			}
+			if mean, full, _ := res.Occupancy(); mean < 0.5 || full == 0 {
+				t.Errorf("Run() occupancy = %.2f, full %.2f, want the buffer mostly full", mean, full)
+			}
+			if len(res.Samples) == 0 {
+				t.Error("Run() took no samples")
+			}
+		})
+	}
+}
+
+func TestRun_invalid(t *testing.T) {
+	t.Parallel()
+	valid := Config{Producers: 1, Consumers: 1, Size: 1, Produce: []time.Duration{0}, Consume: []time.Duration{0},
+		Duration: time.Millisecond, Sample: time.Millisecond}
+	for _, change := range []func(*Config){
+		func(c *Config) { c.Producers = 0 },
+		func(c *Config) { c.Size = 0 },
+		func(c *Config) { c.Consume = nil },
+		func(c *Config) { c.Produce = []time.Duration{-time.Second} },
+		func(c *Config) { c.Sample = 0 },
+	} {
+		cfg := valid
+		change(&cfg)
+		if _, err := Run(cfg); err == nil {
+			t.Errorf("Run(%+v) error = nil, want one", cfg)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Package buffer simulates producers and consumers sharing a bounded buffer,
+// with goroutines for each, and a choice of how the buffer makes them wait:
+// a channel, semaphores, or a monitor.
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Config is how a simulation is run.
+type Config struct {
+	Producers int
+	Consumers int
+	Size      int // how many items the buffer holds
+	Backend   Backend
+	// Produce are the mean times producers take to produce an item, by
+	// producer, wrapping around: a single time is every producer's.
+	Produce []time.Duration
+	// Consume are the mean times consumers take to consume an item, by
+	// consumer, wrapping around.
+	Consume  []time.Duration
+	Duration time.Duration // how long the simulation lasts
+	Sample   time.Duration // how often the buffer's occupancy is sampled
+	Seed     int64         // seeds how long each item takes
+}
+
+// check validates the config.
+func (c Config) check() error {
+	switch {
+	case c.Producers < 1 || c.Consumers < 1:
+		return fmt.Errorf("must have a producer and a consumer")
+	case c.Size < 1:
+		return fmt.Errorf("buffer size %d must be above 0", c.Size)
+	case len(c.Produce) == 0 || len(c.Consume) == 0:
+		return fmt.Errorf("must have a time to produce and to consume")
+	case c.Duration <= 0 || c.Sample <= 0:
+		return fmt.Errorf("the duration and sample time must be above 0")
+	}
+	for _, d := range append(append([]time.Duration(nil), c.Produce...), c.Consume...) {
+		if d < 0 {
+			return fmt.Errorf("time %v must not be negative", d)
+		}
+	}
+
+	return nil
+}
+
+// Worker is how a producer or consumer did.
+type Worker struct {
+	Mean    time.Duration // the mean time it takes for an item
+	Items   int           // items put in or taken from the buffer
+	Blocked time.Duration // time spent waiting on the buffer
+}
+
+// Sample is how many items the buffer held at a time.
+type Sample struct {
+	At  time.Duration
+	Len int
+}
+
+// Result is how a simulation went.
+type Result struct {
+	Backend   Backend
+	Size      int
+	Elapsed   time.Duration
+	Producers []Worker
+	Consumers []Worker
+	Samples   []Sample
+	Left      int           // items left in the buffer at the end
+	Latency   time.Duration // the mean time from producing an item to taking it
+}
+
+// Produced returns how many items were put in the buffer.
+func (r Result) Produced() int {
+	return total(r.Producers)
+}
+
+// Consumed returns how many items were taken from the buffer.
+func (r Result) Consumed() int {
+	return total(r.Consumers)
+}
+
+func total(workers []Worker) int {
+	n := 0
+	for _, w := range workers {
+		n += w.Items
+	}
+
+	return n
+}
+
+// Throughput returns how many items were consumed a second.
+func (r Result) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Consumed()) / r.Elapsed.Seconds()
+}
+
+// Blocked returns the share of their time producers and consumers spent
+// waiting on the buffer.
+func (r Result) Blocked() (producers, consumers float64) {
+	return blocked(r.Producers, r.Elapsed), blocked(r.Consumers, r.Elapsed)
+}
+
+func blocked(workers []Worker, elapsed time.Duration) float64 {
+	if len(workers) == 0 || elapsed <= 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, w := range workers {
+		sum += w.Blocked
+	}
+
+	return float64(sum) / float64(elapsed) / float64(len(workers))
+}
+
+// Occupancy returns the mean share of the buffer that was full over the
+// samples, and the shares of samples where it was full and empty.
+func (r Result) Occupancy() (mean, full, empty float64) {
+	if len(r.Samples) == 0 || r.Size == 0 {
+		return 0, 0, 0
+	}
+	for _, s := range r.Samples {
+		mean += float64(s.Len) / float64(r.Size)
+		if s.Len == r.Size {
+			full++
+		}
+		if s.Len == 0 {
+			empty++
+		}
+	}
+	n := float64(len(r.Samples))
+
+	return mean / n, full / n, empty / n
+}
+
+// Run runs producers and consumers, each a goroutine, against a bounded
+// buffer for cfg.Duration, sampling how full the buffer is every cfg.Sample.
+// Each producer produces an item and puts it in the buffer, and each
+// consumer takes an item from the buffer and consumes it, over and over.
+func Run(cfg Config) (Result, error) {
+	if err := cfg.check(); err != nil {
+		return Result{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
+	defer cancel()
+	buf := newBuffer(ctx, cfg.Backend, cfg.Size)
+	res := Result{
+		Backend:   cfg.Backend,
+		Size:      cfg.Size,
+		Producers: make([]Worker, cfg.Producers),
+		Consumers: make([]Worker, cfg.Consumers),
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex // guards latency and taken
+		latency time.Duration
+		taken   int
+	)
+	start := time.Now()
+	for i := range res.Producers {
+		p := &res.Producers[i]
+		p.Mean = cfg.Produce[i%len(cfg.Produce)]
+		r := rand.New(rand.NewSource(cfg.Seed + int64(i)))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sleep(ctx, random(r, p.Mean)) {
+				t := time.Now()
+				err := buf.put(item(t))
+				p.Blocked += time.Since(t)
+				if err != nil {
+					return
+				}
+				p.Items++
+			}
+		}()
+	}
+	for i := range res.Consumers {
+		c := &res.Consumers[i]
+		c.Mean = cfg.Consume[i%len(cfg.Consume)]
+		r := rand.New(rand.NewSource(cfg.Seed + int64(cfg.Producers+i)))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				t := time.Now()
+				it, err := buf.get()
+				c.Blocked += time.Since(t)
+				if err != nil {
+					return
+				}
+				c.Items++
+				mu.Lock()
+				latency += time.Since(time.Time(it))
+				taken++
+				mu.Unlock()
+				if !sleep(ctx, random(r, c.Mean)) {
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(cfg.Sample)
+	defer ticker.Stop()
+sample:
+	for {
+		select {
+		case <-ctx.Done():
+			break sample
+		case <-ticker.C:
+			res.Samples = append(res.Samples, Sample{At: time.Since(start), Len: buf.len()})
+		}
+	}
+	wg.Wait()
+	res.Elapsed = time.Since(start)
+	res.Left = buf.len()
+	if taken > 0 {
+		res.Latency = latency / time.Duration(taken)
+	}
+
+	return res, nil
+}
+
+// random returns a time between 0 and twice mean.
+func random(r *rand.Rand, mean time.Duration) time.Duration {
+	if mean <= 0 {
+		return 0
+	}
+
+	return time.Duration(r.Int63n(2 * int64(mean)))
+}
+
+// sleep sleeps for d, and reports whether ctx was still going after it.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
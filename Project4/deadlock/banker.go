@@ -0,0 +1,241 @@
+// Package deadlock simulates how an operating system deals with deadlock:
+// avoiding it with the Banker's algorithm, and detecting it with
+// resource-allocation graphs.
+package deadlock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrExceedsNeed is returned for a request beyond what a process declared
+	// it needs.
+	ErrExceedsNeed = errors.New("request exceeds need")
+	// ErrUnavailable is returned for a request the system can't grant yet:
+	// the process must wait.
+	ErrUnavailable = errors.New("resources unavailable")
+	// ErrUnsafe is returned for a request whose grant would leave the system
+	// in an unsafe state: the process must wait.
+	ErrUnsafe = errors.New("unsafe state")
+	// ErrUnknownProcess is returned for a request of a process that isn't in
+	// the state.
+	ErrUnknownProcess = errors.New("unknown process")
+)
+
+// Vector is an amount of each resource.
+type Vector []int
+
+// fits reports whether v is at most w in every resource.
+func (v Vector) fits(w Vector) bool {
+	for i := range v {
+		if v[i] > w[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// negative reports whether v is below 0 in any resource.
+func (v Vector) negative() bool {
+	for _, n := range v {
+		if n < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add returns v + w.
+func (v Vector) add(w Vector) Vector {
+	sum := make(Vector, len(v))
+	for i := range v {
+		sum[i] = v[i] + w[i]
+	}
+
+	return sum
+}
+
+// sub returns v - w.
+func (v Vector) sub(w Vector) Vector {
+	diff := make(Vector, len(v))
+	for i := range v {
+		diff[i] = v[i] - w[i]
+	}
+
+	return diff
+}
+
+func (v Vector) String() string {
+	s := make([]string, len(v))
+	for i, n := range v {
+		s[i] = fmt.Sprint(n)
+	}
+
+	return strings.Join(s, " ")
+}
+
+// State is what the Banker's algorithm knows: the resources available, and
+// what each process holds and may at most claim.
+type State struct {
+	Resources  []string
+	Available  Vector
+	Processes  []string
+	Allocation []Vector // what each process holds
+	Max        []Vector // what each process may hold at most
+}
+
+// Need returns what process i may still ask for.
+func (s State) Need(i int) Vector {
+	return s.Max[i].sub(s.Allocation[i])
+}
+
+// Process returns the index of the process named name, or -1.
+func (s State) Process(name string) int {
+	for i, p := range s.Processes {
+		if p == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// check returns an error if the state doesn't add up.
+func (s State) check() error {
+	n := len(s.Resources)
+	switch {
+	case n == 0:
+		return errors.New("no resources")
+	case len(s.Available) != n:
+		return fmt.Errorf("%d resources available for %d resources", len(s.Available), n)
+	case len(s.Allocation) != len(s.Processes) || len(s.Max) != len(s.Processes):
+		return fmt.Errorf("%d allocations and %d maximums for %d processes", len(s.Allocation), len(s.Max), len(s.Processes))
+	}
+	if s.Available.negative() {
+		return fmt.Errorf("available %v can't be below 0", s.Available)
+	}
+	for i, p := range s.Processes {
+		if len(s.Allocation[i]) != n || len(s.Max[i]) != n {
+			return fmt.Errorf("%v: needs an allocation and a maximum for each of %d resources", p, n)
+		}
+		if s.Allocation[i].negative() {
+			return fmt.Errorf("%v: allocation %v can't be below 0", p, s.Allocation[i])
+		}
+		if !s.Allocation[i].fits(s.Max[i]) {
+			return fmt.Errorf("%v: allocation %v is over its maximum %v", p, s.Allocation[i], s.Max[i])
+		}
+	}
+
+	return nil
+}
+
+// SafetyStep is a process the safety algorithm let finish.
+type SafetyStep struct {
+	Process int
+	Work    Vector // what was available before it ran
+	Need    Vector
+	After   Vector // what was available after it finished and gave back all it held
+}
+
+// Safe runs the safety algorithm: it looks for a process whose need fits in
+// what is available, lets it finish and take back what it held, and repeats,
+// always trying the processes in order. The state is safe if every process
+// can finish; the steps give the safe sequence, or how far it got if not.
+func (s State) Safe() ([]SafetyStep, bool) {
+	work := append(Vector(nil), s.Available...)
+	finished := make([]bool, len(s.Processes))
+	var steps []SafetyStep
+	for len(steps) < len(s.Processes) {
+		found := false
+		for i := range s.Processes {
+			if finished[i] || !s.Need(i).fits(work) {
+				continue
+			}
+			after := work.add(s.Allocation[i])
+			steps = append(steps, SafetyStep{Process: i, Work: work, Need: s.Need(i), After: after})
+			work, finished[i], found = after, true, true
+			break
+		}
+		if !found {
+			return steps, false
+		}
+	}
+
+	return steps, true
+}
+
+// Request runs the resource-request algorithm for process i asking for req:
+// it returns the state with req granted, and its safe sequence, if the
+// request is within the process's need, available, and leaves the state safe.
+// Otherwise it returns an error and the state is unchanged.
+func (s State) Request(i int, req Vector) (State, []SafetyStep, error) {
+	if i < 0 || i >= len(s.Processes) {
+		return s, nil, ErrUnknownProcess
+	}
+	p := s.Processes[i]
+	switch {
+	case len(req) != len(s.Resources):
+		return s, nil, fmt.Errorf("%v: a request needs an amount of each of %d resources", p, len(s.Resources))
+	case req.negative():
+		return s, nil, fmt.Errorf("%v: request %v can't be below 0", p, req)
+	case !req.fits(s.Need(i)):
+		return s, nil, fmt.Errorf("%v: %w: asked for %v with a need of %v", p, ErrExceedsNeed, req, s.Need(i))
+	case !req.fits(s.Available):
+		return s, nil, fmt.Errorf("%v: %w: asked for %v with %v available", p, ErrUnavailable, req, s.Available)
+	}
+
+	granted := s.clone()
+	granted.Available = s.Available.sub(req)
+	granted.Allocation[i] = s.Allocation[i].add(req)
+	steps, ok := granted.Safe()
+	if !ok {
+		return s, steps, fmt.Errorf("%v: %w: granting %v would leave %v available, and no safe sequence", p, ErrUnsafe, req, granted.Available)
+	}
+
+	return granted, steps, nil
+}
+
+// Release returns the state with process i giving back rel.
+func (s State) Release(i int, rel Vector) (State, error) {
+	if i < 0 || i >= len(s.Processes) {
+		return s, ErrUnknownProcess
+	}
+	p := s.Processes[i]
+	switch {
+	case len(rel) != len(s.Resources):
+		return s, fmt.Errorf("%v: a release needs an amount of each of %d resources", p, len(s.Resources))
+	case rel.negative() || !rel.fits(s.Allocation[i]):
+		return s, fmt.Errorf("%v: can't release %v holding %v", p, rel, s.Allocation[i])
+	}
+	released := s.clone()
+	released.Available = s.Available.add(rel)
+	released.Allocation[i] = s.Allocation[i].sub(rel)
+
+	return released, nil
+}
+
+// clone returns a copy of s that can be changed without changing s.
+func (s State) clone() State {
+	c := s
+	c.Available = append(Vector(nil), s.Available...)
+	c.Allocation = make([]Vector, len(s.Allocation))
+	for i, v := range s.Allocation {
+		c.Allocation[i] = append(Vector(nil), v...)
+	}
+
+	return c
+}
+
+// SafeSequence returns the names of the processes of steps, in order.
+func (s State) SafeSequence(steps []SafetyStep) []string {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = s.Processes[step.Process]
+	}
+
+	return names
+}
@@ -0,0 +1,119 @@
+package deadlock
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadExample(t *testing.T) (State, []Query) {
+	t.Helper()
+	f, err := os.Open("../example_banker.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, queries, err := LoadState(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, queries
+}
+
+func TestState_Safe(t *testing.T) {
+	t.Parallel()
+	s, _ := loadExample(t)
+	steps, ok := s.Safe()
+	if !ok {
+		t.Fatalf("Safe() = %v, false, want safe", steps)
+	}
+	if got, want := s.SafeSequence(steps), []string{"P1", "P3", "P0", "P2", "P4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SafeSequence() = %v, want %v", got, want)
+	}
+	if got, want := steps[len(steps)-1].After, (Vector{10, 5, 7}); !reflect.DeepEqual(got, want) {
+		t.Errorf("work after all finish = %v, want %v", got, want)
+	}
+
+	s.Available = Vector{0, 0, 0}
+	if steps, ok := s.Safe(); ok || len(steps) != 0 {
+		t.Errorf("Safe() with nothing available = %v, %v, want unsafe", steps, ok)
+	}
+}
+
+func TestState_Request(t *testing.T) {
+	t.Parallel()
+	s, queries := loadExample(t)
+	tests := []struct {
+		query   Query
+		wantErr error
+	}{
+		{query: queries[0]},
+		{query: queries[1], wantErr: ErrUnavailable},
+		{query: queries[2], wantErr: ErrUnsafe},
+		{query: Query{Process: "P3", Request: Vector{1, 1, 1}}, wantErr: ErrExceedsNeed},
+		{query: Query{Process: "P9", Request: Vector{0, 0, 0}}, wantErr: ErrUnknownProcess},
+	}
+	// Each request is made after the ones before it are granted or not, as
+	// in the textbook.
+	for _, tt := range tests {
+		next, _, err := s.Request(s.Process(tt.query.Process), tt.query.Request)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("Request(%v, %v) error = %v, want %v", tt.query.Process, tt.query.Request, err, tt.wantErr)
+		}
+		if err != nil && !reflect.DeepEqual(next, s) {
+			t.Errorf("Request(%v, %v) changed the state after failing", tt.query.Process, tt.query.Request)
+		}
+		s = next
+	}
+	if want := (Vector{2, 3, 0}); !reflect.DeepEqual(s.Available, want) {
+		t.Errorf("Available = %v, want %v", s.Available, want)
+	}
+
+	s, err := s.Release(1, Vector{3, 0, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Vector{5, 3, 2}); !reflect.DeepEqual(s.Available, want) {
+		t.Errorf("Available after release = %v, want %v", s.Available, want)
+	}
+	if _, err := s.Release(1, Vector{1, 0, 0}); err == nil {
+		t.Error("Release() of more than held error = nil")
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	t.Parallel()
+	csvState, csvQueries := loadExample(t)
+	const example = `{"resources": ["A", "B", "C"], "available": [3, 3, 2],
+ "processes": [
+  {"name": "P0", "allocation": [0, 1, 0], "max": [7, 5, 3]},
+  {"name": "P1", "allocation": [2, 0, 0], "max": [3, 2, 2]},
+  {"name": "P2", "allocation": [3, 0, 2], "max": [9, 0, 2]},
+  {"name": "P3", "allocation": [2, 1, 1], "max": [2, 2, 2]},
+  {"name": "P4", "allocation": [0, 0, 2], "max": [4, 3, 3]}],
+ "requests": [{"process": "P1", "request": [1, 0, 2]}, {"process": "P4", "request": [3, 3, 0]},
+  {"process": "P0", "request": [0, 2, 0]}]}`
+	s, queries, err := LoadState(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, csvState) || !reflect.DeepEqual(queries, csvQueries) {
+		t.Errorf("LoadState(JSON) = %+v, %v, want %+v, %v", s, queries, csvState, csvQueries)
+	}
+
+	for _, input := range []string{
+		"",
+		"resources,A\navailable,1\nP0,1,0\n",
+		"resources,A,B\navailable,1\n",
+		"resources,A\navailable,x\n",
+		"resources,A\navailable,1\nP0,1\n",
+		`{"resources": ["A"], "available": [-1]}`,
+	} {
+		if _, _, err := LoadState(strings.NewReader(input)); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("LoadState(%q) error = %v, want %v", input, err, ErrInvalidState)
+		}
+	}
+}
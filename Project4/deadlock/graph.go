@@ -0,0 +1,441 @@
+package deadlock
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrInvalidEvent is returned for an event that can't happen, such as
+	// releasing a resource that isn't held.
+	ErrInvalidEvent = errors.New("invalid event")
+	// ErrInvalidTrace is returned for an event trace that can't be read.
+	ErrInvalidTrace = errors.New("invalid trace")
+)
+
+// EventOp is what a process does to a resource.
+type EventOp string
+
+// The events of a trace.
+const (
+	OpRequest EventOp = "request" // ask for a resource, waiting if it is held
+	OpAcquire EventOp = "acquire" // take a resource that must be free
+	OpRelease EventOp = "release" // give back a resource held
+)
+
+// Event is a process doing something to a resource.
+type Event struct {
+	Op       EventOp `json:"op"`
+	Process  string  `json:"process"`
+	Resource string  `json:"resource"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%v %v %v", e.Process, e.Op, e.Resource)
+}
+
+// LoadEvents reads an event trace, either as a JSON array of events such as
+// {"op": "request", "process": "P1", "resource": "R1"}, or as CSV lines of
+// op,process,resource such as "request,P1,R1". Blank lines and lines
+// starting with # are skipped.
+func LoadEvents(r io.Reader) ([]Event, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		_, _ = br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '[' {
+		return loadJSONEvents(br)
+	}
+
+	return loadCSVEvents(br)
+}
+
+func loadJSONEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	if err := json.NewDecoder(r).Decode(&events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrace, err)
+	}
+	for i, e := range events {
+		if err := e.check(); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+
+	return events, nil
+}
+
+func loadCSVEvents(r io.Reader) ([]Event, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	var events []Event
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTrace, err)
+		}
+		line, _ := cr.FieldPos(0)
+		if len(row) != 3 {
+			return nil, fmt.Errorf("line %d: %w: want op,process,resource", line, ErrInvalidTrace)
+		}
+		e := Event{
+			Op:       EventOp(strings.TrimSpace(row[0])),
+			Process:  strings.TrimSpace(row[1]),
+			Resource: strings.TrimSpace(row[2]),
+		}
+		if err := e.check(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, e)
+	}
+}
+
+// check validates the event.
+func (e Event) check() error {
+	switch {
+	case e.Op != OpRequest && e.Op != OpAcquire && e.Op != OpRelease:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidTrace, e.Op)
+	case e.Process == "" || e.Resource == "":
+		return fmt.Errorf("%w: %v needs a process and a resource", ErrInvalidTrace, e.Op)
+	}
+
+	return nil
+}
+
+// DetectStep is an event of a trace and what came of it.
+type DetectStep struct {
+	Event   Event
+	Outcome string     // what the event did to the graph
+	Err     error      // why the event couldn't happen, if it couldn't
+	Cycles  [][]string // the cycles of the graph after the event
+}
+
+// Detect applies events to an empty graph, looking for cycles after each. An
+// event that can't happen is recorded and the trace goes on. It returns the
+// graph as the trace left it.
+func Detect(events []Event) ([]DetectStep, *Graph) {
+	g := NewGraph()
+	steps := make([]DetectStep, 0, len(events))
+	for _, e := range events {
+		step := DetectStep{Event: e}
+		step.Outcome, step.Err = g.Apply(e)
+		step.Cycles = g.Cycles()
+		steps = append(steps, step)
+	}
+
+	return steps, g
+}
+
+// Graph is a resource-allocation graph of resources with one instance each:
+// an assignment edge from a resource to the process holding it, and a request
+// edge from a process to the resource it waits for. A waiting process is
+// blocked, so it waits for one resource at most.
+type Graph struct {
+	Processes []string // in the order they appeared
+	Resources []string // in the order they appeared
+
+	holder map[string]string   // the process holding each resource
+	waits  map[string]string   // the resource each process waits for
+	queue  map[string][]string // the processes waiting for each resource, first come first
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{holder: map[string]string{}, waits: map[string]string{}, queue: map[string][]string{}}
+}
+
+// Apply applies e to the graph and describes what came of it. A request for
+// a held resource adds a request edge; releasing a resource gives it to the
+// process that has waited for it longest, if any.
+func (g *Graph) Apply(e Event) (string, error) {
+	outcome, err := g.apply(e)
+	if err != nil {
+		return "", err
+	}
+	if !contains(g.Processes, e.Process) {
+		g.Processes = append(g.Processes, e.Process)
+	}
+	if !contains(g.Resources, e.Resource) {
+		g.Resources = append(g.Resources, e.Resource)
+	}
+
+	return outcome, nil
+}
+
+func (g *Graph) apply(e Event) (string, error) {
+	if r, ok := g.waits[e.Process]; ok {
+		return "", fmt.Errorf("%w: %v is blocked waiting for %v", ErrInvalidEvent, e.Process, r)
+	}
+	holder, held := g.holder[e.Resource]
+	switch e.Op {
+	case OpRequest, OpAcquire:
+		switch {
+		case held && holder == e.Process:
+			return "", fmt.Errorf("%w: %v already holds %v", ErrInvalidEvent, e.Process, e.Resource)
+		case !held:
+			g.holder[e.Resource] = e.Process
+			return "granted", nil
+		case e.Op == OpAcquire:
+			return "", fmt.Errorf("%w: %v can't acquire %v, held by %v", ErrInvalidEvent, e.Process, e.Resource, holder)
+		}
+		g.waits[e.Process] = e.Resource
+		g.queue[e.Resource] = append(g.queue[e.Resource], e.Process)
+		return fmt.Sprintf("waits for %v, held by %v", e.Resource, holder), nil
+	default:
+		if !held || holder != e.Process {
+			return "", fmt.Errorf("%w: %v doesn't hold %v", ErrInvalidEvent, e.Process, e.Resource)
+		}
+		delete(g.holder, e.Resource)
+		q := g.queue[e.Resource]
+		if len(q) == 0 {
+			return "released", nil
+		}
+		next := q[0]
+		g.queue[e.Resource] = q[1:]
+		delete(g.waits, next)
+		g.holder[e.Resource] = next
+		return fmt.Sprintf("released to %v", next), nil
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cycles returns the cycles of the graph, each as the processes and
+// resources around it starting with the first process of the cycle to
+// appear, such as P1 R1 P2 R2: P1 waits for R1, held by P2, which waits for
+// R2, held by P1. With one instance of each resource, the processes of a
+// cycle are deadlocked.
+func (g *Graph) Cycles() [][]string {
+	var cycles [][]string
+	inCycle := map[string]bool{}
+	for _, start := range g.Processes {
+		// Follow the wait-for edges from start: each process waits for one
+		// process at most, so the path either ends or loops.
+		seen := map[string]int{}
+		var path []string
+		for p, ok := start, true; ok; p, ok = g.waitsFor(p) {
+			if i, loop := seen[p]; loop {
+				if !inCycle[p] {
+					cycles = append(cycles, g.cycle(path[i:]))
+					for _, q := range path[i:] {
+						inCycle[q] = true
+					}
+				}
+				break
+			}
+			if inCycle[p] {
+				break
+			}
+			seen[p] = len(path)
+			path = append(path, p)
+		}
+	}
+
+	return cycles
+}
+
+// cycle returns the processes of a cycle with the resources between them,
+// starting with the process that appeared first.
+func (g *Graph) cycle(procs []string) []string {
+	first := 0
+	for i, p := range procs {
+		if g.order(p) < g.order(procs[first]) {
+			first = i
+		}
+	}
+	var cycle []string
+	for i := range procs {
+		p := procs[(first+i)%len(procs)]
+		cycle = append(cycle, p, g.waits[p])
+	}
+
+	return cycle
+}
+
+// order returns when process p appeared.
+func (g *Graph) order(p string) int {
+	for i, q := range g.Processes {
+		if q == p {
+			return i
+		}
+	}
+
+	return len(g.Processes)
+}
+
+// Blocked reports whether p waits for a resource.
+func (g *Graph) Blocked(p string) bool {
+	_, ok := g.waits[p]
+
+	return ok
+}
+
+// waitsFor returns the process holding what p waits for.
+func (g *Graph) waitsFor(p string) (string, bool) {
+	r, ok := g.waits[p]
+	if !ok {
+		return "", false
+	}
+	holder, ok := g.holder[r]
+
+	return holder, ok
+}
+
+// Deadlocked returns the processes of the graph's cycles, and the processes
+// that wait on them, which can't go on either, in the order they appeared.
+func (g *Graph) Deadlocked() (deadlocked, stuck []string) {
+	inCycle := map[string]bool{}
+	for _, c := range g.Cycles() {
+		for i := 0; i < len(c); i += 2 {
+			inCycle[c[i]] = true
+		}
+	}
+	for _, p := range g.Processes {
+		if inCycle[p] {
+			deadlocked = append(deadlocked, p)
+			continue
+		}
+		seen := map[string]bool{}
+		for q, ok := g.waitsFor(p); ok && !seen[q]; q, ok = g.waitsFor(q) {
+			seen[q] = true
+			if inCycle[q] {
+				stuck = append(stuck, p)
+				break
+			}
+		}
+	}
+
+	return deadlocked, stuck
+}
+
+// Holding returns what each process holds and waits for, as text such as
+// "holds R1, R2; waits for R3", by process.
+func (g *Graph) Holding() map[string]string {
+	holds := map[string][]string{}
+	for _, r := range g.Resources {
+		if p, ok := g.holder[r]; ok {
+			holds[p] = append(holds[p], r)
+		}
+	}
+	text := map[string]string{}
+	for _, p := range g.Processes {
+		var parts []string
+		if len(holds[p]) > 0 {
+			parts = append(parts, "holds "+strings.Join(holds[p], ", "))
+		}
+		if r, ok := g.waits[p]; ok {
+			parts = append(parts, "waits for "+r)
+		}
+		text[p] = strings.Join(parts, "; ")
+	}
+
+	return text
+}
+
+// WriteDOT writes the graph in Graphviz DOT, with the processes as circles,
+// the resources as boxes, request edges dashed, and the deadlocked processes
+// and the edges of their cycles in red. With waitFor, it writes the wait-for
+// graph instead: only the processes, with an edge from each to the process
+// holding what it waits for.
+func (g *Graph) WriteDOT(w io.Writer, waitFor bool) error {
+	deadlocked, _ := g.Deadlocked()
+	red := map[string]bool{}
+	for _, p := range deadlocked {
+		red[p] = true
+	}
+	var b strings.Builder
+	name := "resource_allocation"
+	if waitFor {
+		name = "wait_for"
+	}
+	fmt.Fprintf(&b, "digraph %v {\n\trankdir=LR;\n", name)
+	for _, p := range g.Processes {
+		fmt.Fprintf(&b, "\t%q%v;\n", p, attrs("shape=circle", red[p]))
+	}
+	if !waitFor {
+		for _, r := range g.Resources {
+			fmt.Fprintf(&b, "\t%q%v;\n", r, attrs("shape=box", false))
+		}
+	}
+
+	for _, p := range g.Processes {
+		r, waiting := g.waits[p]
+		if !waiting {
+			continue
+		}
+		holder := g.holder[r]
+		// Both ends of an edge in a cycle are deadlocked, but a process
+		// waiting on a cycle isn't in it.
+		cyc := red[p] && red[holder]
+		if waitFor {
+			fmt.Fprintf(&b, "\t%q -> %q%v;\n", p, holder, attrs(fmt.Sprintf("label=%q", r), cyc))
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q -> %q%v;\n", p, r, attrs("style=dashed", cyc))
+	}
+	if !waitFor {
+		for _, r := range g.Resources {
+			if p, ok := g.holder[r]; ok {
+				fmt.Fprintf(&b, "\t%q -> %q%v;\n", r, p, attrs("", red[p] && g.waitedOn(r, red)))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// waitedOn reports whether a deadlocked process waits for r.
+func (g *Graph) waitedOn(r string, deadlocked map[string]bool) bool {
+	for _, p := range g.queue[r] {
+		if deadlocked[p] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attrs returns the DOT attribute list of attr, colored red if red.
+func attrs(attr string, red bool) string {
+	var list []string
+	if attr != "" {
+		list = append(list, attr)
+	}
+	if red {
+		list = append(list, "color=red")
+	}
+	if len(list) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(list, ", ") + "]"
+}
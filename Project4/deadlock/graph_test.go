@@ -0,0 +1,161 @@
+package deadlock
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadEvents(t *testing.T) {
+	t.Parallel()
+	want := []Event{
+		{Op: OpAcquire, Process: "P1", Resource: "R1"},
+		{Op: OpRelease, Process: "P1", Resource: "R1"},
+	}
+	tests := []struct {
+		name    string
+		in      string
+		want    []Event
+		wantErr error
+	}{
+		{name: "csv", in: "# op,process,resource\nacquire, P1, R1\n\nrelease,P1,R1\n", want: want},
+		{name: "json", in: ` [{"op": "acquire", "process": "P1", "resource": "R1"},
+			{"op": "release", "process": "P1", "resource": "R1"}]`, want: want},
+		{name: "empty", in: "\n"},
+		{name: "unknown op", in: "grab,P1,R1\n", wantErr: ErrInvalidTrace},
+		{name: "no resource", in: "request,P1\n", wantErr: ErrInvalidTrace},
+		{name: "json no process", in: `[{"op": "request", "resource": "R1"}]`, wantErr: ErrInvalidTrace},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadEvents(strings.NewReader(tt.in))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadEvents() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_Apply(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		event   Event
+		want    string
+		wantErr error
+	}{
+		{event: Event{OpAcquire, "P1", "R1"}, want: "granted"},
+		{event: Event{OpRequest, "P2", "R1"}, want: "waits for R1, held by P1"},
+		{event: Event{OpRequest, "P3", "R1"}, want: "waits for R1, held by P1"},
+		{event: Event{OpRequest, "P2", "R2"}, wantErr: ErrInvalidEvent},
+		{event: Event{OpRequest, "P1", "R1"}, wantErr: ErrInvalidEvent},
+		{event: Event{OpAcquire, "P4", "R1"}, wantErr: ErrInvalidEvent},
+		{event: Event{OpRelease, "P4", "R1"}, wantErr: ErrInvalidEvent},
+		// The resource goes to whoever waited longest.
+		{event: Event{OpRelease, "P1", "R1"}, want: "released to P2"},
+		{event: Event{OpRelease, "P2", "R1"}, want: "released to P3"},
+		{event: Event{OpRelease, "P3", "R1"}, want: "released"},
+	}
+	g := NewGraph()
+	for _, tt := range tests {
+		got, err := g.Apply(tt.event)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("Apply(%v) error = %v, want %v", tt.event, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("Apply(%v) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+	if want := []string{"P1", "P2", "P3"}; !reflect.DeepEqual(g.Processes, want) {
+		t.Errorf("Processes = %v, want %v: failed events must not add nodes", g.Processes, want)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("../example_events.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events, err := LoadEvents(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, g := Detect(events)
+	for i, step := range steps[:len(steps)-2] {
+		if len(step.Cycles) > 0 {
+			t.Errorf("step %d: Cycles = %v before the deadlock", i+1, step.Cycles)
+		}
+	}
+	want := [][]string{{"P1", "R2", "P2", "R3", "P3", "R1"}}
+	if got := steps[len(steps)-2].Cycles; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles = %v, want %v", got, want)
+	}
+	if err := steps[len(steps)-1].Err; !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("release by a blocked process: error = %v, want %v", err, ErrInvalidEvent)
+	}
+	deadlocked, stuck := g.Deadlocked()
+	if want := []string{"P1", "P2", "P3"}; !reflect.DeepEqual(deadlocked, want) {
+		t.Errorf("Deadlocked() = %v, want %v", deadlocked, want)
+	}
+	if want := []string{"P4"}; !reflect.DeepEqual(stuck, want) {
+		t.Errorf("Deadlocked() stuck = %v, want %v", stuck, want)
+	}
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	t.Parallel()
+	// Two separate cycles, found once each and started at the process that
+	// appeared first.
+	g := NewGraph()
+	for _, e := range []Event{
+		{OpAcquire, "P1", "A"}, {OpAcquire, "P2", "B"}, {OpAcquire, "P3", "C"}, {OpAcquire, "P4", "D"},
+		{OpRequest, "P2", "A"}, {OpRequest, "P1", "B"}, {OpRequest, "P4", "C"}, {OpRequest, "P3", "D"},
+	} {
+		if _, err := g.Apply(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := [][]string{{"P1", "B", "P2", "A"}, {"P3", "D", "P4", "C"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	t.Parallel()
+	g := NewGraph()
+	for _, e := range []Event{{OpAcquire, "P1", "A"}, {OpRequest, "P2", "A"}} {
+		if _, err := g.Apply(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		waitFor bool
+		want    string
+	}{
+		{want: "digraph resource_allocation {\n\trankdir=LR;\n" +
+			"\t\"P1\" [shape=circle];\n\t\"P2\" [shape=circle];\n\t\"A\" [shape=box];\n" +
+			"\t\"P2\" -> \"A\" [style=dashed];\n\t\"A\" -> \"P1\";\n}\n"},
+		{waitFor: true, want: "digraph wait_for {\n\trankdir=LR;\n" +
+			"\t\"P1\" [shape=circle];\n\t\"P2\" [shape=circle];\n" +
+			"\t\"P2\" -> \"P1\" [label=\"A\"];\n}\n"},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		if err := g.WriteDOT(&b, tt.waitFor); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("WriteDOT(%v) = \n%v\nwant\n%v", tt.waitFor, got, tt.want)
+		}
+	}
+}
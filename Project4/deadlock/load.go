@@ -0,0 +1,167 @@
+package deadlock
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidState is returned for a state that can't be read.
+var ErrInvalidState = errors.New("invalid state")
+
+// Query is a request to check against a state.
+type Query struct {
+	Process string `json:"process"`
+	Request Vector `json:"request"`
+}
+
+// stateFile is a state and its queries as JSON has them.
+type stateFile struct {
+	Resources []string `json:"resources"`
+	Available Vector   `json:"available"`
+	Processes []struct {
+		Name       string `json:"name"`
+		Allocation Vector `json:"allocation"`
+		Max        Vector `json:"max"`
+	} `json:"processes"`
+	Requests []Query `json:"requests"`
+}
+
+// LoadState reads a state and the requests to check against it, either as
+// JSON:
+//
+//	{"resources": ["A", "B"], "available": [3, 2],
+//	 "processes": [{"name": "P0", "allocation": [0, 1], "max": [7, 5]}],
+//	 "requests": [{"process": "P0", "request": [1, 0]}]}
+//
+// or as CSV, with rows for the resources and what is available, a row for
+// each process with its allocation and then its maximum, and a row for each
+// request:
+//
+//	resources,A,B
+//	available,3,2
+//	P0,0,1,7,5
+//	request,P0,1,0
+//
+// Blank lines and lines starting with # are skipped.
+func LoadState(r io.Reader) (State, []Query, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return State{}, nil, fmt.Errorf("%w: empty", ErrInvalidState)
+			}
+			return State{}, nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		_, _ = br.ReadByte()
+	}
+	var (
+		s       State
+		queries []Query
+		err     error
+	)
+	if b, _ := br.Peek(1); b[0] == '{' {
+		s, queries, err = loadJSONState(br)
+	} else {
+		s, queries, err = loadCSVState(br)
+	}
+	if err != nil {
+		return State{}, nil, err
+	}
+	if err := s.check(); err != nil {
+		return State{}, nil, fmt.Errorf("%w: %v", ErrInvalidState, err)
+	}
+
+	return s, queries, nil
+}
+
+func loadJSONState(r io.Reader) (State, []Query, error) {
+	var f stateFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return State{}, nil, fmt.Errorf("%w: %v", ErrInvalidState, err)
+	}
+	s := State{Resources: f.Resources, Available: f.Available}
+	for _, p := range f.Processes {
+		s.Processes = append(s.Processes, p.Name)
+		s.Allocation = append(s.Allocation, p.Allocation)
+		s.Max = append(s.Max, p.Max)
+	}
+
+	return s, f.Requests, nil
+}
+
+func loadCSVState(r io.Reader) (State, []Query, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	var (
+		s       State
+		queries []Query
+	)
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return s, queries, nil
+		}
+		if err != nil {
+			return State{}, nil, fmt.Errorf("%w: %v", ErrInvalidState, err)
+		}
+		line, _ := cr.FieldPos(0)
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		switch row[0] {
+		case "resources":
+			s.Resources = row[1:]
+			continue
+		case "request":
+			if len(row) < 2 {
+				return State{}, nil, fmt.Errorf("line %d: %w: a request needs a process", line, ErrInvalidState)
+			}
+			req, err := ParseVector(row[2:])
+			if err != nil {
+				return State{}, nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			queries = append(queries, Query{Process: row[1], Request: req})
+			continue
+		}
+		v, err := ParseVector(row[1:])
+		if err != nil {
+			return State{}, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if row[0] == "available" {
+			s.Available = v
+			continue
+		}
+		if len(v)%2 != 0 {
+			return State{}, nil, fmt.Errorf("line %d: %w: %v needs an allocation and a maximum of each resource", line, ErrInvalidState, row[0])
+		}
+		s.Processes = append(s.Processes, row[0])
+		s.Allocation = append(s.Allocation, v[:len(v)/2])
+		s.Max = append(s.Max, v[len(v)/2:])
+	}
+}
+
+// ParseVector reads an amount of each resource from fields.
+func ParseVector(fields []string) (Vector, error) {
+	v := make(Vector, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidState, f)
+		}
+		v[i] = n
+	}
+
+	return v, nil
+}
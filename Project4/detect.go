@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jar0582/CSCE4600/Project4/deadlock"
+	"github.com/olekukonko/tablewriter"
+)
+
+// detectCommand runs a trace of events through a resource-allocation graph,
+// printing what each event did and the cycles after it, then who is
+// deadlocked at the end. With -dot, it writes the final graph in Graphviz DOT.
+func detectCommand(in io.Reader, w io.Writer, args ...string) error {
+	fs := flag.NewFlagSet("detect", flag.ContinueOnError)
+	fs.SetOutput(w)
+	dot := fs.String("dot", "", "write the final graph in Graphviz DOT to `FILE`, or only it to stdout for -")
+	waitFor := fs.Bool("waitfor", false, "write the wait-for graph with -dot, not the resource-allocation graph")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+
+	f, err := openInput(in, fs.Args())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	events, err := deadlock.LoadEvents(f)
+	if err != nil {
+		return err
+	}
+
+	steps, g := deadlock.Detect(events)
+	if *dot == "-" {
+		return g.WriteDOT(w, *waitFor)
+	}
+	outputTitle(w, "Deadlock detection")
+	outputEvents(w, steps)
+	outputDeadlock(w, g, steps)
+	if *dot == "" {
+		return nil
+	}
+	out, err := os.Create(*dot)
+	if err != nil {
+		return fmt.Errorf("%v: error creating DOT file", err)
+	}
+	if err := g.WriteDOT(out, *waitFor); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// outputEvents prints each event with what came of it and the cycles of the
+// graph after it.
+func outputEvents(w io.Writer, steps []deadlock.DetectStep) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Step", "Event", "Outcome", "Cycles"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	for i, step := range steps {
+		outcome := step.Outcome
+		if step.Err != nil {
+			outcome = "FAILED: " + strings.TrimPrefix(step.Err.Error(), deadlock.ErrInvalidEvent.Error()+": ")
+		}
+		table.Append([]string{fmt.Sprint(i + 1), step.Event.String(), outcome, formatCycles(step.Cycles)})
+	}
+	table.Render()
+}
+
+// formatCycles returns cycles as text such as "P1 -> R1 -> P2 -> R2 -> P1",
+// or "-" for none.
+func formatCycles(cycles [][]string) string {
+	if len(cycles) == 0 {
+		return "-"
+	}
+	text := make([]string, len(cycles))
+	for i, c := range cycles {
+		text[i] = strings.Join(c, " -> ") + " -> " + c[0]
+	}
+
+	return strings.Join(text, "; ")
+}
+
+// outputDeadlock prints what each process holds and waits for at the end of
+// the trace, and who is deadlocked.
+func outputDeadlock(w io.Writer, g *deadlock.Graph, steps []deadlock.DetectStep) {
+	deadlocked, stuck := g.Deadlocked()
+	status := map[string]string{}
+	for _, p := range deadlocked {
+		status[p] = "deadlocked"
+	}
+	for _, p := range stuck {
+		status[p] = "waits on the deadlock"
+	}
+	holding := g.Holding()
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Process", "Resources", "Status"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	for _, p := range g.Processes {
+		st, ok := status[p]
+		switch {
+		case ok:
+		case g.Blocked(p):
+			st = "waiting"
+		default:
+			st = "running"
+		}
+		table.Append([]string{p, holding[p], st})
+	}
+	table.Render()
+
+	if len(deadlocked) == 0 {
+		_, _ = fmt.Fprintln(w, "No deadlock.")
+		return
+	}
+	// The deadlock began at the last step with no cycle before it.
+	first := len(steps)
+	for first > 0 && len(steps[first-1].Cycles) > 0 {
+		first--
+	}
+	_, _ = fmt.Fprintf(w, "Deadlock since step %d: %v deadlocked", first+1, strings.Join(deadlocked, ", "))
+	if len(stuck) > 0 {
+		_, _ = fmt.Fprintf(w, ", and %v waiting on them", strings.Join(stuck, ", "))
+	}
+	_, _ = fmt.Fprintln(w, ".")
+}
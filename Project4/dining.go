@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project4/dining"
+	"github.com/olekukonko/tablewriter"
+)
+
+// diningCommand runs a dinner of philosophers with each strategy chosen with
+// -strategy, printing how they are doing as they go and then how each fared,
+// then compares the strategies.
+func diningCommand(_ io.Reader, w io.Writer, args ...string) error {
+	var cfg dining.Config
+	fs := flag.NewFlagSet("dining", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.IntVar(&cfg.Philosophers, "n", 5, "the number of `PHILOSOPHERS`")
+	strategy := fs.String("strategy", "all", "the `STRATEGY` for picking up forks: naive, ordering, arbitrator, chandy-misra, or all")
+	fs.DurationVar(&cfg.Think, "think", 10*time.Millisecond, "the mean `TIME` a philosopher thinks")
+	fs.DurationVar(&cfg.Eat, "eat", 10*time.Millisecond, "the mean `TIME` a philosopher eats")
+	fs.DurationVar(&cfg.Reach, "reach", 10*time.Millisecond, "the `TIME` between picking up one fork and the other")
+	fs.DurationVar(&cfg.Duration, "time", 2*time.Second, "how long each dinner lasts, as a `TIME`")
+	fs.Int64Var(&cfg.Seed, "seed", 1, "the `SEED` of how long philosophers think and eat")
+	every := fs.Duration("report", 250*time.Millisecond, "report how the philosophers are doing every `TIME`, or never for 0")
+	starve := fs.Duration("starve", 100*time.Millisecond, "a philosopher hungry for this `TIME` or longer is starving")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: dining reads no file: %v", ErrInvalidArgs, strings.Join(fs.Args(), " "))
+	}
+	strategies := dining.Strategies
+	if *strategy != "all" {
+		s, err := dining.ParseStrategy(*strategy)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		strategies = []dining.Strategy{s}
+	}
+
+	var results []dining.Result
+	for _, s := range strategies {
+		cfg.Strategy = s
+		outputTitle(w, "Dining philosophers: "+s.String())
+		if *every > 0 {
+			_, _ = fmt.Fprintln(w, "Each philosopher thinking (T), hungry (H) or eating (E), with its meals; ! marks one starving.")
+		}
+		res, err := dining.Run(cfg, *every, func(st dining.Status) {
+			outputStatus(w, st, *starve)
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		outputPhilosophers(w, res, *starve)
+		results = append(results, res)
+	}
+	if len(results) > 1 {
+		outputDinners(w, results, *starve)
+	}
+
+	return nil
+}
+
+// outputStatus prints a line of how each philosopher is doing.
+func outputStatus(w io.Writer, st dining.Status, starve time.Duration) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%6.2fs ", st.Elapsed.Seconds())
+	for i, p := range st.Philosophers {
+		mark := " "
+		if p.Hungry >= starve {
+			mark = "!"
+		}
+		fmt.Fprintf(&b, " P%d %c%4d%v", i, strings.ToUpper(p.State.String())[0], p.Meals, mark)
+	}
+	if st.Deadlock != nil {
+		fmt.Fprintf(&b, " deadlock: %v -> %v", strings.Join(st.Deadlock, " -> "), st.Deadlock[0])
+	}
+	_, _ = fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+}
+
+// outputPhilosophers prints how each philosopher fared, and whether they
+// deadlocked.
+func outputPhilosophers(w io.Writer, res dining.Result, starve time.Duration) {
+	starving := map[int]bool{}
+	for _, i := range res.Starving(starve) {
+		starving[i] = true
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Philosopher", "Meals", "Waits", "Mean wait", "Longest wait", "Starving"})
+	table.SetAutoFormatHeaders(false)
+	for i, p := range res.Philosophers {
+		mean := time.Duration(0)
+		if p.Waits > 0 {
+			mean = p.Waited / time.Duration(p.Waits)
+		}
+		table.Append([]string{
+			fmt.Sprintf("P%d", i),
+			fmt.Sprint(p.Meals),
+			fmt.Sprint(p.Waits),
+			formatDuration(mean),
+			formatDuration(p.LongestWait),
+			yesNo(starving[i]),
+		})
+	}
+	table.Render()
+	if res.Deadlock != nil {
+		_, _ = fmt.Fprintf(w, "Deadlock at %.2fs: %v -> %v\n", res.DeadlockAt.Seconds(), strings.Join(res.Deadlock, " -> "), res.Deadlock[0])
+	}
+}
+
+// outputDinners prints how the philosophers fared with each strategy.
+func outputDinners(w io.Writer, results []dining.Result, starve time.Duration) {
+	_, _ = fmt.Fprintln(w, "Comparison")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Strategy", "Meals", "Fewest", "Most", "Mean wait", "Longest wait", "Starving", "Deadlock"})
+	table.SetAutoFormatHeaders(false)
+	for _, res := range results {
+		total, fewest, most := res.Meals()
+		deadlock := "-"
+		if res.Deadlock != nil {
+			deadlock = fmt.Sprintf("at %.2fs", res.DeadlockAt.Seconds())
+		}
+		table.Append([]string{
+			res.Strategy.String(),
+			fmt.Sprint(total),
+			fmt.Sprint(fewest),
+			fmt.Sprint(most),
+			formatDuration(res.MeanWait()),
+			formatDuration(res.LongestWait()),
+			fmt.Sprint(len(res.Starving(starve))),
+			deadlock,
+		})
+	}
+	table.Render()
+}
+
+// formatDuration returns d to the tenth of a millisecond.
+func formatDuration(d time.Duration) string {
+	return d.Round(100 * time.Microsecond).String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
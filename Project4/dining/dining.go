@@ -0,0 +1,417 @@
+// Package dining simulates the dining philosophers with goroutines: each
+// philosopher thinks, gets hungry, picks up the forks on either side and eats,
+// picking up forks with a strategy that may or may not keep them from
+// deadlock and starvation.
+package dining
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jar0582/CSCE4600/Project4/deadlock"
+)
+
+// Strategy is how philosophers pick up their forks.
+type Strategy int
+
+// The strategies.
+const (
+	Naive       Strategy = iota // the left fork, then the right, which can deadlock
+	Ordering                    // the lower-numbered fork, then the other
+	Arbitrator                  // both forks at once, when a waiter sees both are free
+	ChandyMisra                 // ask the neighbours for forks, which give up the ones they ate with
+)
+
+// Strategies are the strategies, in the order they are compared.
+var Strategies = []Strategy{Naive, Ordering, Arbitrator, ChandyMisra}
+
+func (s Strategy) String() string {
+	switch s {
+	case Naive:
+		return "naive"
+	case Ordering:
+		return "resource ordering"
+	case Arbitrator:
+		return "arbitrator"
+	case ChandyMisra:
+		return "Chandy-Misra"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// ParseStrategy returns the strategy named s, such as "ordering" or
+// "chandy-misra".
+func ParseStrategy(s string) (Strategy, error) {
+	name := strings.ReplaceAll(strings.ToLower(s), " ", "-")
+	for _, st := range Strategies {
+		full := strings.ReplaceAll(strings.ToLower(st.String()), " ", "-")
+		if name == full || strings.HasSuffix(full, "-"+name) {
+			return st, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown strategy %q", s)
+}
+
+// State is what a philosopher is doing.
+type State int
+
+// The states of a philosopher.
+const (
+	Thinking State = iota
+	Hungry
+	Eating
+)
+
+func (s State) String() string {
+	switch s {
+	case Thinking:
+		return "thinking"
+	case Hungry:
+		return "hungry"
+	case Eating:
+		return "eating"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Config is how a dinner is run.
+type Config struct {
+	Philosophers int
+	Strategy     Strategy
+	Think        time.Duration // the mean time a philosopher thinks
+	Eat          time.Duration // the mean time a philosopher eats
+	Reach        time.Duration // the time between picking up one fork and the other
+	Duration     time.Duration // how long the dinner lasts
+	Seed         int64         // seeds how long each philosopher thinks and eats
+}
+
+// Philosopher is how a philosopher is doing.
+type Philosopher struct {
+	State       State
+	Meals       int
+	Waits       int           // how many times it got hungry and then ate
+	Waited      time.Duration // the time it spent hungry before those meals
+	LongestWait time.Duration
+	Hungry      time.Duration // how long it has been hungry, if it is
+}
+
+// Status is how the dinner is doing.
+type Status struct {
+	Elapsed      time.Duration
+	Philosophers []Philosopher
+	Deadlock     []string // the cycle the philosophers just deadlocked in, if they did
+}
+
+// Result is how a dinner went.
+type Result struct {
+	Strategy     Strategy
+	Elapsed      time.Duration
+	Philosophers []Philosopher // a philosopher still hungry at the end counts that wait as its longest, if it is
+	Deadlock     []string      // the cycle of philosophers and forks they deadlocked in, if they did
+	DeadlockAt   time.Duration
+}
+
+// Meals returns how many meals were eaten, and the fewest and most any
+// philosopher ate.
+func (r Result) Meals() (total, fewest, most int) {
+	for i, p := range r.Philosophers {
+		total += p.Meals
+		if i == 0 || p.Meals < fewest {
+			fewest = p.Meals
+		}
+		if p.Meals > most {
+			most = p.Meals
+		}
+	}
+
+	return total, fewest, most
+}
+
+// MeanWait returns the mean time a philosopher was hungry before eating.
+func (r Result) MeanWait() time.Duration {
+	var waited time.Duration
+	waits := 0
+	for _, p := range r.Philosophers {
+		waited += p.Waited
+		waits += p.Waits
+	}
+	if waits == 0 {
+		return 0
+	}
+
+	return waited / time.Duration(waits)
+}
+
+// LongestWait returns the longest time any philosopher was hungry.
+func (r Result) LongestWait() time.Duration {
+	var longest time.Duration
+	for _, p := range r.Philosophers {
+		if p.LongestWait > longest {
+			longest = p.LongestWait
+		}
+	}
+
+	return longest
+}
+
+// Starving returns the philosophers that were hungry for limit or longer.
+func (r Result) Starving(limit time.Duration) []int {
+	var starving []int
+	for i, p := range r.Philosophers {
+		if p.LongestWait >= limit {
+			starving = append(starving, i)
+		}
+	}
+
+	return starving
+}
+
+// checkEvery is how often a dinner is checked for deadlock.
+const checkEvery = 5 * time.Millisecond
+
+// Run runs a dinner with a goroutine for each philosopher, calling report
+// with how it is doing every so often, and once more if the philosophers
+// deadlock, or never for 0. The dinner ends after cfg.Duration, or once the
+// philosophers are deadlocked.
+func Run(cfg Config, every time.Duration, report func(Status)) (Result, error) {
+	if cfg.Philosophers < 2 {
+		return Result{}, fmt.Errorf("%d philosophers: must have 2 or more", cfg.Philosophers)
+	}
+	if cfg.Think < 0 || cfg.Eat < 0 || cfg.Reach < 0 || cfg.Duration <= 0 {
+		return Result{}, fmt.Errorf("times must not be negative, and the dinner must last")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
+	defer cancel()
+	s := newSim(cfg.Philosophers)
+	t := newTable(ctx, cfg, s)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Philosophers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.dine(ctx, t, i, cfg)
+		}(i)
+	}
+
+	res := Result{Strategy: cfg.Strategy}
+	ticker := time.NewTicker(checkEvery)
+	defer ticker.Stop()
+	next := every
+watch:
+	for {
+		select {
+		case <-ctx.Done():
+			break watch
+		case <-ticker.C:
+		}
+		if cycle := s.cycle(); cycle != nil {
+			res.Deadlock, res.DeadlockAt = cycle, time.Since(s.start)
+			if every > 0 {
+				st := s.status()
+				st.Deadlock = cycle
+				report(st)
+			}
+			break
+		}
+		if every > 0 && time.Since(s.start) >= next {
+			report(s.status())
+			next += every
+		}
+	}
+	cancel()
+	wg.Wait()
+
+	final := s.status()
+	res.Elapsed = final.Elapsed
+	res.Philosophers = final.Philosophers
+	for i, p := range res.Philosophers {
+		if p.Hungry > p.LongestWait {
+			res.Philosophers[i].LongestWait = p.Hungry
+		}
+	}
+
+	return res, nil
+}
+
+// sim keeps track of the philosophers of a dinner: what each is doing, and
+// which forks each holds and waits for.
+type sim struct {
+	mu    sync.Mutex
+	start time.Time
+	seats []seat
+}
+
+type seat struct {
+	Philosopher
+	hungrySince time.Time
+	holds       []bool // by fork
+	waitsFor    int    // the fork it waits for, or -1
+}
+
+func newSim(n int) *sim {
+	s := &sim{start: time.Now(), seats: make([]seat, n)}
+	for i := range s.seats {
+		s.seats[i].holds = make([]bool, n)
+		s.seats[i].waitsFor = -1
+	}
+
+	return s
+}
+
+// dine is philosopher i: thinking, getting hungry and eating until ctx is
+// done.
+func (s *sim) dine(ctx context.Context, t table, i int, cfg Config) {
+	r := rand.New(rand.NewSource(cfg.Seed + int64(i)))
+	for {
+		if !sleep(ctx, random(r, cfg.Think)) {
+			return
+		}
+		s.hungry(i)
+		if err := t.pickUp(ctx, i); err != nil {
+			return
+		}
+		s.eating(i)
+		ate := sleep(ctx, random(r, cfg.Eat))
+		t.putDown(i)
+		s.thinking(i, ate)
+		if !ate {
+			return
+		}
+	}
+}
+
+// random returns a time between 0 and twice mean.
+func random(r *rand.Rand, mean time.Duration) time.Duration {
+	if mean <= 0 {
+		return 0
+	}
+
+	return time.Duration(r.Int63n(2 * int64(mean)))
+}
+
+// sleep sleeps for d, and reports whether ctx was still going after it.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (s *sim) hungry(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seats[i].State = Hungry
+	s.seats[i].hungrySince = time.Now()
+}
+
+func (s *sim) eating(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := &s.seats[i]
+	waited := time.Since(p.hungrySince)
+	p.State = Eating
+	p.Waits++
+	p.Waited += waited
+	if waited > p.LongestWait {
+		p.LongestWait = waited
+	}
+}
+
+// thinking records that philosopher i stopped eating, having finished its
+// meal if ate.
+func (s *sim) thinking(i int, ate bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seats[i].State = Thinking
+	if ate {
+		s.seats[i].Meals++
+	}
+}
+
+// wait records that philosopher i waits for fork f, or for none with -1.
+func (s *sim) wait(i, f int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seats[i].waitsFor = f
+}
+
+// hold records that philosopher i picked up fork f.
+func (s *sim) hold(i, f int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seats[i].holds[f] = true
+	s.seats[i].waitsFor = -1
+}
+
+// release records that philosopher i is putting down fork f. It must be
+// recorded before the fork is free, so a fork recorded as held is held.
+func (s *sim) release(i, f int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seats[i].holds[f] = false
+}
+
+func (s *sim) status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Status{Elapsed: time.Since(s.start), Philosophers: make([]Philosopher, len(s.seats))}
+	for i, p := range s.seats {
+		st.Philosophers[i] = p.Philosopher
+		if p.State == Hungry {
+			st.Philosophers[i].Hungry = time.Since(p.hungrySince)
+		}
+	}
+
+	return st
+}
+
+// cycle returns a cycle of the resource-allocation graph of the philosophers
+// and their forks, or nil if there is none. Forks are recorded as held after
+// they are picked up and as free before they are put down, so a cycle is a
+// deadlock.
+func (s *sim) cycle() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := deadlock.NewGraph()
+	held := make([]bool, len(s.seats))
+	for i, p := range s.seats {
+		for f, h := range p.holds {
+			if h {
+				_, _ = g.Apply(deadlock.Event{Op: deadlock.OpAcquire, Process: philosopher(i), Resource: fork(f)})
+				held[f] = true
+			}
+		}
+	}
+	for i, p := range s.seats {
+		if p.waitsFor >= 0 && held[p.waitsFor] {
+			_, _ = g.Apply(deadlock.Event{Op: deadlock.OpRequest, Process: philosopher(i), Resource: fork(p.waitsFor)})
+		}
+	}
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return cycles[0]
+	}
+
+	return nil
+}
+
+func philosopher(i int) string {
+	return fmt.Sprintf("P%d", i)
+}
+
+func fork(f int) string {
+	return fmt.Sprintf("F%d", f)
+}
@@ -0,0 +1,95 @@
+package dining
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseStrategy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    Strategy
+		wantErr bool
+	}{
+		{in: "naive", want: Naive},
+		{in: "ordering", want: Ordering},
+		{in: "Resource-Ordering", want: Ordering},
+		{in: "arbitrator", want: Arbitrator},
+		{in: "chandy-misra", want: ChandyMisra},
+		{in: "waiter", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStrategy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseStrategy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseStrategy(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	for _, s := range []Strategy{Ordering, Arbitrator, ChandyMisra} {
+		s := s
+		t.Run(s.String(), func(t *testing.T) {
+			t.Parallel()
+			cfg := Config{Philosophers: 5, Strategy: s, Think: time.Millisecond, Eat: time.Millisecond,
+				Reach: time.Millisecond, Duration: 300 * time.Millisecond}
+			reports := 0
+			res, err := Run(cfg, 100*time.Millisecond, func(Status) { reports++ })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Deadlock != nil {
+				t.Fatalf("Run() deadlocked in %v", res.Deadlock)
+			}
+			if _, fewest, _ := res.Meals(); fewest == 0 {
+				t.Errorf("Run() meals = %+v, want every philosopher to eat", res.Philosophers)
+			}
+			if reports == 0 {
+				t.Errorf("Run() reported %d times, want some", reports)
+			}
+		})
+	}
+}
+
+func TestRun_deadlock(t *testing.T) {
+	t.Parallel()
+	// With no thinking, every philosopher picks up its left fork at once, and
+	// none can pick up its right.
+	cfg := Config{Philosophers: 5, Strategy: Naive, Reach: 20 * time.Millisecond, Duration: 5 * time.Second}
+	var reported []string
+	res, err := Run(cfg, time.Hour, func(st Status) { reported = st.Deadlock })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reported, res.Deadlock) {
+		t.Errorf("Run() reported deadlock %v, want %v", reported, res.Deadlock)
+	}
+	if len(res.Deadlock) != 10 || res.Deadlock[0] != "P0" || res.Deadlock[1] != "F1" {
+		t.Fatalf("Run() Deadlock = %v, want the cycle of all five from P0", res.Deadlock)
+	}
+	if res.Elapsed >= cfg.Duration {
+		t.Errorf("Run() lasted %v, want it to stop at the deadlock", res.Elapsed)
+	}
+	if got := res.Starving(res.DeadlockAt / 2); len(got) != 5 {
+		t.Errorf("Starving() = %v, want all five", got)
+	}
+}
+
+func TestRun_invalid(t *testing.T) {
+	t.Parallel()
+	for _, cfg := range []Config{
+		{Philosophers: 1, Duration: time.Second},
+		{Philosophers: 5},
+		{Philosophers: 5, Eat: -time.Second, Duration: time.Second},
+	} {
+		if _, err := Run(cfg, 0, nil); err == nil {
+			t.Errorf("Run(%+v) error = nil, want one", cfg)
+		}
+	}
+}
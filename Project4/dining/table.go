@@ -0,0 +1,221 @@
+package dining
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// table is how philosophers pick up and put down forks. Philosopher i eats
+// with forks i and i+1, wrapping around.
+type table interface {
+	// pickUp blocks until philosopher i holds both forks, or ctx is done.
+	pickUp(ctx context.Context, i int) error
+	// putDown puts down the forks of philosopher i.
+	putDown(i int)
+}
+
+func newTable(ctx context.Context, cfg Config, s *sim) table {
+	switch cfg.Strategy {
+	case Arbitrator:
+		return newWaiter(ctx, cfg.Philosophers, s)
+	case ChandyMisra:
+		return newChandyMisra(ctx, cfg.Philosophers, s)
+	default:
+		return newForks(cfg, s)
+	}
+}
+
+// forks are forks picked up one at a time, each a channel holding the fork
+// while it is free.
+type forks struct {
+	s       *sim
+	ch      []chan struct{}
+	reach   time.Duration
+	ordered bool // pick up the lower-numbered fork first
+}
+
+func newForks(cfg Config, s *sim) *forks {
+	t := &forks{s: s, ch: make([]chan struct{}, cfg.Philosophers), reach: cfg.Reach, ordered: cfg.Strategy == Ordering}
+	for f := range t.ch {
+		t.ch[f] = make(chan struct{}, 1)
+		t.ch[f] <- struct{}{}
+	}
+
+	return t
+}
+
+func (t *forks) pickUp(ctx context.Context, i int) error {
+	first, second := i, (i+1)%len(t.ch)
+	if t.ordered && second < first {
+		first, second = second, first
+	}
+	if err := t.take(ctx, i, first); err != nil {
+		return err
+	}
+	if !sleep(ctx, t.reach) {
+		t.put(i, first)
+		return ctx.Err()
+	}
+	if err := t.take(ctx, i, second); err != nil {
+		t.put(i, first)
+		return err
+	}
+
+	return nil
+}
+
+func (t *forks) putDown(i int) {
+	t.put(i, i)
+	t.put(i, (i+1)%len(t.ch))
+}
+
+func (t *forks) take(ctx context.Context, i, f int) error {
+	t.s.wait(i, f)
+	select {
+	case <-t.ch[f]:
+		t.s.hold(i, f)
+		return nil
+	case <-ctx.Done():
+		t.s.wait(i, -1)
+		return ctx.Err()
+	}
+}
+
+func (t *forks) put(i, f int) {
+	t.s.release(i, f)
+	t.ch[f] <- struct{}{}
+}
+
+// condTable is a table guarded by a mutex, whose philosophers wait on a
+// condition for their forks.
+type condTable struct {
+	s       *sim
+	n       int
+	mu      sync.Mutex
+	cond    *sync.Cond
+	stopped bool
+}
+
+func (t *condTable) init(ctx context.Context, n int, s *sim) {
+	t.s, t.n = s, n
+	t.cond = sync.NewCond(&t.mu)
+	go func() {
+		<-ctx.Done()
+		t.mu.Lock()
+		t.stopped = true
+		t.cond.Broadcast()
+		t.mu.Unlock()
+	}()
+}
+
+// forksOf returns the forks of philosopher i.
+func (t *condTable) forksOf(i int) [2]int {
+	return [2]int{i, (i + 1) % t.n}
+}
+
+// waiter is an arbitrator that lets a philosopher pick up its forks only when
+// both are free, so it never holds one while waiting for the other.
+type waiter struct {
+	condTable
+	busy []bool // by fork
+}
+
+func newWaiter(ctx context.Context, n int, s *sim) *waiter {
+	t := &waiter{busy: make([]bool, n)}
+	t.init(ctx, n, s)
+
+	return t
+}
+
+func (t *waiter) pickUp(ctx context.Context, i int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	forks := t.forksOf(i)
+	for t.busy[forks[0]] || t.busy[forks[1]] {
+		if t.stopped {
+			return ctx.Err()
+		}
+		t.cond.Wait()
+	}
+	for _, f := range forks {
+		t.busy[f] = true
+		t.s.hold(i, f)
+	}
+
+	return nil
+}
+
+func (t *waiter) putDown(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, f := range t.forksOf(i) {
+		t.s.release(i, f)
+		t.busy[f] = false
+	}
+	t.cond.Broadcast()
+}
+
+// chandyMisra hands each fork to one of the two philosophers sharing it. A
+// hungry philosopher takes a fork its neighbour isn't eating with only if
+// the fork is dirty, having been eaten with; the fork is cleaned as it is
+// handed over, and the neighbour can't take it back until it is eaten with.
+// The forks start dirty, each with the lower-numbered of its philosophers.
+type chandyMisra struct {
+	condTable
+	owner  []int  // by fork
+	dirty  []bool // by fork
+	eating []bool // by philosopher
+}
+
+func newChandyMisra(ctx context.Context, n int, s *sim) *chandyMisra {
+	t := &chandyMisra{owner: make([]int, n), dirty: make([]bool, n), eating: make([]bool, n)}
+	t.init(ctx, n, s)
+	for f := range t.owner {
+		// Fork f is shared by philosophers f-1 and f.
+		t.owner[f] = f - 1
+		if f == 0 {
+			t.owner[f] = 0
+		}
+		t.dirty[f] = true
+	}
+
+	return t
+}
+
+func (t *chandyMisra) pickUp(ctx context.Context, i int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	forks := t.forksOf(i)
+	for {
+		for _, f := range forks {
+			if o := t.owner[f]; o != i && t.dirty[f] && !t.eating[o] {
+				t.owner[f], t.dirty[f] = i, false
+			}
+		}
+		if t.owner[forks[0]] == i && t.owner[forks[1]] == i {
+			break
+		}
+		if t.stopped {
+			return ctx.Err()
+		}
+		t.cond.Wait()
+	}
+	t.eating[i] = true
+	for _, f := range forks {
+		t.s.hold(i, f)
+	}
+
+	return nil
+}
+
+func (t *chandyMisra) putDown(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.eating[i] = false
+	for _, f := range t.forksOf(i) {
+		t.s.release(i, f)
+		t.dirty[f] = true
+	}
+	t.cond.Broadcast()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the fixtures in testdata")
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantOut string
+		wantErr error
+	}{
+		{
+			name:    "banker",
+			args:    []string{"banker", "example_banker.csv"},
+			wantOut: loadFixture(t, "testdata", "banker.txt"),
+		},
+		{
+			name:    "banker_session",
+			args:    []string{"banker", "-i", "example_banker.csv"},
+			stdin:   "help\nrequest P1 0 2 0\nrelease P1 3 0 2\nstate\nrequest P0\nrelease P9 1 1 1\nsafe\nfly\nquit\n",
+			wantOut: loadFixture(t, "testdata", "banker_session.txt"),
+		},
+		{
+			name:    "detect",
+			args:    []string{"detect", "example_events.csv"},
+			wantOut: loadFixture(t, "testdata", "detect.txt"),
+		},
+		{
+			name:    "detect_dot",
+			args:    []string{"detect", "-dot", "-", "example_events.csv"},
+			wantOut: loadFixture(t, "testdata", "detect_dot.txt"),
+		},
+		{name: "no simulation", wantErr: ErrInvalidArgs},
+		{name: "unknown simulation", args: []string{"ostrich"}, wantErr: ErrInvalidArgs},
+		{name: "banker interactive stdin", args: []string{"banker", "-i"}, wantErr: ErrInvalidArgs},
+		{name: "dining unknown strategy", args: []string{"dining", "-strategy", "hope"}, wantErr: ErrInvalidArgs},
+		{name: "dining one philosopher", args: []string{"dining", "-n", "1"}, wantErr: ErrInvalidArgs},
+		{name: "dining file", args: []string{"dining", "example_events.csv"}, wantErr: ErrInvalidArgs},
+		{name: "buffer unknown backend", args: []string{"buffer", "-backend", "pipe"}, wantErr: ErrInvalidArgs},
+		{name: "buffer bad rate", args: []string{"buffer", "-produce", "5ms,fast"}, wantErr: ErrInvalidArgs},
+		{name: "buffer no size", args: []string{"buffer", "-size", "0"}, wantErr: ErrInvalidArgs},
+		{name: "two files", args: []string{"banker", "a.csv", "b.csv"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			err := run(strings.NewReader(tt.stdin), &w, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if *update {
+				if err := os.WriteFile(path.Join("testdata", tt.name+".txt"), w.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("run() = \n%v\nwant\n%v", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_run_help(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	err := run(strings.NewReader(""), &w, "dining", "-h")
+	if !errors.Is(err, flag.ErrHelp) || errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("run() error = %v, want %v alone", err, flag.ErrHelp)
+	}
+	if !strings.Contains(w.String(), "-strategy") {
+		t.Errorf("run() = %q, want the usage of dining's flags", w.String())
+	}
+}
+
+func loadFixture(t *testing.T, p ...string) string {
+	b, err := os.ReadFile(path.Join(p...))
+	if err != nil && !*update {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}